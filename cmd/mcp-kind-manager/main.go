@@ -1,11 +1,23 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/auth"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/logging"
 	"github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/settings"
 	"github.com/kubevoidcraft/mcp-kind-manager/internal/tools"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/tracing"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -13,9 +25,17 @@ import (
 var Version = "dev"
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
-		Level: logLevel(),
-	}))
+	logCfg, err := logging.ConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid logging configuration: %v\n", err)
+		os.Exit(1)
+	}
+	logger, logOut, err := logging.New(logCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "setting up logging: %v\n", err)
+		os.Exit(1)
+	}
+	defer logOut.Close()
 
 	slog.SetDefault(logger)
 
@@ -25,32 +45,145 @@ func main() {
 		"arch", runtime.DetectOS().Arch,
 	)
 
+	settingsPath := settings.DefaultPath()
+	cfg, err := settings.Load(settingsPath)
+	if err != nil {
+		logger.Error("invalid settings file", "error", err)
+		os.Exit(1)
+	}
+	logger.Debug("settings loaded", "path", settingsPath)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), Version)
+	if err != nil {
+		logger.Error("tracing setup failed", "error", err)
+		os.Exit(1)
+	}
+	if tracing.Enabled() {
+		logger.Info("exporting traces over OTLP")
+	}
+
+	reg := tools.NewRegistry(logger, cfg, Version)
 	s := server.NewMCPServer(
 		"mcp-kind-manager",
 		Version,
 		server.WithToolCapabilities(false),
 		server.WithRecovery(),
+		server.WithLogging(),
+		server.WithToolHandlerMiddleware(reg.ToolMiddleware),
+		server.WithHooks(reg.Hooks()),
 	)
-
-	reg := tools.NewRegistry(logger)
 	reg.RegisterAll(s)
 
-	logger.Info("serving over stdio")
-	if err := server.ServeStdio(s); err != nil {
-		logger.Error("server exited with error", "error", err)
+	grace, err := shutdownGrace(cfg.Timeouts.ShutdownGrace)
+	if err != nil {
+		logger.Error("invalid shutdown configuration", "error", err)
+		os.Exit(1)
+	}
+
+	var httpServer *http.Server
+	if addr := os.Getenv("MCP_KIND_HTTP_ADDR"); addr != "" {
+		authCfg, err := auth.ConfigFromEnv()
+		if err != nil {
+			logger.Error("invalid HTTP client configuration", "error", err)
+			os.Exit(1)
+		}
+		if len(authCfg.Tokens) == 0 {
+			logger.Warn("MCP_KIND_HTTP_TOKENS is not set; HTTP clients are not authenticated")
+		}
+		httpServer = newHTTPServer(s, addr, authCfg)
+	}
+
+	ctx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	served := make(chan error, 1)
+	if httpServer != nil {
+		logger.Info("serving over HTTP", "addr", httpServer.Addr, "endpoint", httpEndpoint)
+		go func() {
+			served <- httpServer.ListenAndServe()
+		}()
+	} else {
+		logger.Info("serving over stdio")
+		go func() {
+			served <- server.NewStdioServer(s).Listen(ctx, os.Stdin, os.Stdout)
+		}()
+	}
+
+	var serveErr error
+	listening := true
+	select {
+	case serveErr = <-served:
+		listening = false
+	case <-ctx.Done():
+		logger.Info("received shutdown signal", "grace_period", grace.String())
+	}
+	// A second signal terminates the process immediately.
+	stopSignals()
+
+	graceCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if err := reg.Shutdown(graceCtx); err != nil {
+		logger.Warn("shutdown incomplete", "error", err)
+	}
+	if listening && httpServer != nil {
+		// Shutdown waits for the cancelled calls' responses to be written.
+		if err := httpServer.Shutdown(graceCtx); err != nil {
+			logger.Warn("HTTP shutdown incomplete", "error", err)
+		}
+	}
+	if listening {
+		// Let the cancelled calls' responses reach the client.
+		select {
+		case serveErr = <-served:
+		case <-graceCtx.Done():
+		}
+	}
+	if errors.Is(serveErr, context.Canceled) || errors.Is(serveErr, http.ErrServerClosed) {
+		serveErr = nil
+	}
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Warn("flushing traces failed", "error", err)
+	}
+	if serveErr != nil {
+		logger.Error("server exited with error", "error", serveErr)
+		logOut.Close()
 		os.Exit(1)
 	}
+	logger.Info("server stopped")
 }
 
-func logLevel() slog.Level {
-	switch os.Getenv("LOG_LEVEL") {
-	case "debug", "DEBUG":
-		return slog.LevelDebug
-	case "warn", "WARN":
-		return slog.LevelWarn
-	case "error", "ERROR":
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
+// Defaults for shutting down: how long in-flight tool calls get to return once they
+// are cancelled (MCP_KIND_SHUTDOWN_GRACE), and how long exporting pending traces may take.
+const (
+	defaultShutdownGrace = 10 * time.Second
+	flushTimeout         = 5 * time.Second
+)
+
+// shutdownGrace returns the grace period configured with MCP_KIND_SHUTDOWN_GRACE, else
+// the one from the settings file.
+func shutdownGrace(configured time.Duration) (time.Duration, error) {
+	v := os.Getenv("MCP_KIND_SHUTDOWN_GRACE")
+	if v == "" {
+		return cmp.Or(configured, defaultShutdownGrace), nil
 	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("MCP_KIND_SHUTDOWN_GRACE=%q: must be a non-negative duration such as '30s'", v)
+	}
+	return d, nil
+}
+
+// httpEndpoint is the path the streamable HTTP transport serves MCP on.
+const httpEndpoint = "/mcp"
+
+// newHTTPServer serves s over the streamable HTTP transport on addr
+// (MCP_KIND_HTTP_ADDR). Each request is identified by authCfg, so tool calls know the
+// client they come from; see auth.Config.
+func newHTTPServer(s *server.MCPServer, addr string, authCfg auth.Config) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(httpEndpoint, authCfg.Middleware(server.NewStreamableHTTPServer(s, server.WithEndpointPath(httpEndpoint))))
+	return &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
 }
@@ -0,0 +1,101 @@
+// Package addons installs common add-ons (cert-manager, Gateway API, ...) into Kind clusters.
+package addons
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// DefaultCertManagerVersion is the cert-manager release installed when none is specified.
+const DefaultCertManagerVersion = "v1.16.2"
+
+// retryInterval is the delay between readiness probes; overridden in tests.
+var retryInterval = 5 * time.Second
+
+// CertManagerOptions holds the parameters for installing cert-manager.
+type CertManagerOptions struct {
+	Version                string
+	CreateSelfSignedIssuer bool
+	IssuerName             string
+	Timeout                time.Duration
+}
+
+// InstallCertManager installs cert-manager into a Kind cluster, waits for its
+// deployments and admission webhook to become ready, and optionally creates a
+// self-signed ClusterIssuer. It returns a per-step result log.
+func InstallCertManager(ctx context.Context, mgr *kind.Manager, clusterName string, opts CertManagerOptions) ([]string, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if opts.Version == "" {
+		opts.Version = DefaultCertManagerVersion
+	}
+	if !strings.HasPrefix(opts.Version, "v") {
+		opts.Version = "v" + opts.Version
+	}
+	if opts.IssuerName == "" {
+		opts.IssuerName = "selfsigned"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+
+	var results []string
+	manifestURL := fmt.Sprintf(
+		"https://github.com/cert-manager/cert-manager/releases/download/%s/cert-manager.yaml", opts.Version)
+
+	if _, err := mgr.Kubectl(ctx, clusterName, "apply", "-f", manifestURL); err != nil {
+		return results, fmt.Errorf("applying cert-manager %s: %w", opts.Version, err)
+	}
+	results = append(results, fmt.Sprintf("OK applied cert-manager %s", opts.Version))
+
+	timeout := fmt.Sprintf("--timeout=%ds", int(opts.Timeout.Seconds()))
+	if _, err := mgr.Kubectl(ctx, clusterName, "wait", "--for=condition=Available",
+		"deployment", "--all", "-n", "cert-manager", timeout); err != nil {
+		return results, fmt.Errorf("waiting for cert-manager deployments: %w", err)
+	}
+	results = append(results, "OK cert-manager deployments available")
+
+	// The webhook deployment can report Available before its serving certificate is
+	// injected, so probe it with a server-side dry-run until it admits a resource.
+	issuer := selfSignedIssuer(opts.IssuerName)
+	deadline := time.Now().Add(opts.Timeout)
+	for {
+		_, err := mgr.ApplyManifest(ctx, clusterName, issuer, "--dry-run=server")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return results, fmt.Errorf("cert-manager webhook not ready after %s: %w", opts.Timeout, err)
+		}
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+	results = append(results, "OK cert-manager webhook ready")
+
+	if opts.CreateSelfSignedIssuer {
+		if _, err := mgr.ApplyManifest(ctx, clusterName, issuer); err != nil {
+			return results, fmt.Errorf("creating ClusterIssuer %q: %w", opts.IssuerName, err)
+		}
+		results = append(results, fmt.Sprintf("OK created self-signed ClusterIssuer %q", opts.IssuerName))
+	}
+
+	return results, nil
+}
+
+func selfSignedIssuer(name string) string {
+	return fmt.Sprintf(`apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: %s
+spec:
+  selfSigned: {}
+`, name)
+}
@@ -0,0 +1,99 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// mockRunner records invocations and returns canned output keyed by the first
+// argument after the kubeconfig flag.
+type mockRunner struct {
+	calls   [][]string
+	outputs map[string][]error
+}
+
+func (m *mockRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	m.calls = append(m.calls, append([]string{name}, args...))
+	if name == "kind" {
+		return []byte("apiVersion: v1\n"), nil
+	}
+	// kubectl --kubeconfig <path> <verb> ...
+	key := ""
+	if len(args) > 2 {
+		key = args[2]
+	}
+	if errs := m.outputs[key]; len(errs) > 0 {
+		err := errs[0]
+		m.outputs[key] = errs[1:]
+		return nil, err
+	}
+	return []byte("ok\n"), nil
+}
+
+func (m *mockRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	out, err := m.Run(ctx, name, args...)
+	return out, nil, err
+}
+
+func (m *mockRunner) LookPath(name string) (string, error) {
+	return "/usr/bin/" + name, nil
+}
+
+func (m *mockRunner) called(substr string) bool {
+	for _, c := range m.calls {
+		if strings.Contains(strings.Join(c, " "), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func newManager(runner *mockRunner) *kind.Manager {
+	return kind.NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+}
+
+func TestInstallCertManager(t *testing.T) {
+	retryInterval = time.Millisecond
+	runner := &mockRunner{outputs: map[string][]error{
+		// webhook not ready on first dry-run
+		"apply": {nil, fmt.Errorf("webhook not ready")},
+	}}
+
+	results, err := InstallCertManager(context.Background(), newManager(runner), "test",
+		CertManagerOptions{Version: "1.16.2", CreateSelfSignedIssuer: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Errorf("expected 4 result lines, got %v", results)
+	}
+	if !runner.called("releases/download/v1.16.2/cert-manager.yaml") {
+		t.Error("expected cert-manager manifest to be applied")
+	}
+	if !runner.called("--dry-run=server") {
+		t.Error("expected webhook dry-run probe")
+	}
+}
+
+func TestInstallCertManager_WaitFails(t *testing.T) {
+	runner := &mockRunner{outputs: map[string][]error{
+		"wait": {fmt.Errorf("timed out")},
+	}}
+
+	_, err := InstallCertManager(context.Background(), newManager(runner), "test", CertManagerOptions{})
+	if err == nil {
+		t.Error("expected error when deployments never become available")
+	}
+}
+
+func TestInstallCertManager_EmptyName(t *testing.T) {
+	if _, err := InstallCertManager(context.Background(), newManager(&mockRunner{}), "", CertManagerOptions{}); err == nil {
+		t.Error("expected error for empty cluster name")
+	}
+}
@@ -0,0 +1,149 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// DefaultGatewayAPIVersion is the Gateway API release installed when none is specified.
+const DefaultGatewayAPIVersion = "v1.2.1"
+
+// Gateway API implementations that can be installed alongside the CRDs.
+const (
+	GatewayNginxFabric  = "nginx-gateway-fabric"
+	GatewayEnvoyGateway = "envoy-gateway"
+)
+
+const (
+	nginxGatewayFabricVersion = "v1.5.1"
+	envoyGatewayVersion       = "v1.2.4"
+)
+
+// GatewayAPIOptions holds the parameters for installing the Gateway API.
+type GatewayAPIOptions struct {
+	Version        string
+	Channel        string // "standard" or "experimental"
+	Implementation string // "", GatewayNginxFabric or GatewayEnvoyGateway
+	HTTPNodePort   int
+	HTTPSNodePort  int
+}
+
+// GatewayAPIResult reports what was installed and how to reach it from the host.
+type GatewayAPIResult struct {
+	Steps        []string           `json:"steps"`
+	PortMappings []kind.PortMapping `json:"port_mappings,omitempty"`
+	Notes        string             `json:"notes,omitempty"`
+}
+
+// InstallGatewayAPI applies the Gateway API CRDs for the selected release channel and,
+// optionally, a Gateway implementation exposed on fixed NodePorts so it can be
+// reached through Kind extraPortMappings.
+func InstallGatewayAPI(ctx context.Context, mgr *kind.Manager, clusterName string, opts GatewayAPIOptions) (*GatewayAPIResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if opts.Version == "" {
+		opts.Version = DefaultGatewayAPIVersion
+	}
+	if !strings.HasPrefix(opts.Version, "v") {
+		opts.Version = "v" + opts.Version
+	}
+	if opts.Channel == "" {
+		opts.Channel = "standard"
+	}
+	if opts.Channel != "standard" && opts.Channel != "experimental" {
+		return nil, fmt.Errorf("invalid channel %q; must be 'standard' or 'experimental'", opts.Channel)
+	}
+	if opts.HTTPNodePort == 0 {
+		opts.HTTPNodePort = 30080
+	}
+	if opts.HTTPSNodePort == 0 {
+		opts.HTTPSNodePort = 30443
+	}
+
+	result := &GatewayAPIResult{}
+	crdURL := fmt.Sprintf("https://github.com/kubernetes-sigs/gateway-api/releases/download/%s/%s-install.yaml",
+		opts.Version, opts.Channel)
+	// Server-side apply avoids the annotation size limit hit by the large CRDs.
+	if _, err := mgr.Kubectl(ctx, clusterName, "apply", "--server-side", "-f", crdURL); err != nil {
+		return result, fmt.Errorf("applying Gateway API %s (%s channel): %w", opts.Version, opts.Channel, err)
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK applied Gateway API %s CRDs (%s channel)", opts.Version, opts.Channel))
+
+	switch opts.Implementation {
+	case "":
+		return result, nil
+	case GatewayNginxFabric:
+		if err := installNginxGatewayFabric(ctx, mgr, clusterName, opts, result); err != nil {
+			return result, err
+		}
+	case GatewayEnvoyGateway:
+		if err := installEnvoyGateway(ctx, mgr, clusterName, result); err != nil {
+			return result, err
+		}
+	default:
+		return result, fmt.Errorf("unsupported implementation %q; must be %q or %q",
+			opts.Implementation, GatewayNginxFabric, GatewayEnvoyGateway)
+	}
+
+	return result, nil
+}
+
+func installNginxGatewayFabric(ctx context.Context, mgr *kind.Manager, clusterName string, opts GatewayAPIOptions, result *GatewayAPIResult) error {
+	base := "https://raw.githubusercontent.com/nginx/nginx-gateway-fabric/" + nginxGatewayFabricVersion + "/deploy"
+	for _, url := range []string{base + "/crds.yaml", base + "/nodeport/deploy.yaml"} {
+		if _, err := mgr.Kubectl(ctx, clusterName, "apply", "--server-side", "-f", url); err != nil {
+			return fmt.Errorf("applying %s: %w", url, err)
+		}
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK applied nginx-gateway-fabric %s", nginxGatewayFabricVersion))
+
+	patch := fmt.Sprintf(`{"spec":{"type":"NodePort","ports":[{"port":80,"nodePort":%d},{"port":443,"nodePort":%d}]}}`,
+		opts.HTTPNodePort, opts.HTTPSNodePort)
+	if _, err := mgr.Kubectl(ctx, clusterName, "patch", "service", "nginx-gateway",
+		"-n", "nginx-gateway", "-p", patch); err != nil {
+		return fmt.Errorf("pinning nginx-gateway NodePorts: %w", err)
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK pinned nginx-gateway NodePorts to %d/%d",
+		opts.HTTPNodePort, opts.HTTPSNodePort))
+
+	if _, err := mgr.Kubectl(ctx, clusterName, "wait", "--for=condition=Available",
+		"deployment", "--all", "-n", "nginx-gateway", "--timeout=300s"); err != nil {
+		return fmt.Errorf("waiting for nginx-gateway-fabric: %w", err)
+	}
+	result.Steps = append(result.Steps, "OK nginx-gateway-fabric available")
+
+	result.PortMappings = gatewayPortMappings(opts)
+	result.Notes = "Add the port mappings to the first control-plane node (generate_cluster_config) " +
+		"to reach Gateways on host ports 80/443. GatewayClass name: 'nginx'."
+	return nil
+}
+
+func installEnvoyGateway(ctx context.Context, mgr *kind.Manager, clusterName string, result *GatewayAPIResult) error {
+	url := fmt.Sprintf("https://github.com/envoyproxy/gateway/releases/download/%s/install.yaml", envoyGatewayVersion)
+	if _, err := mgr.Kubectl(ctx, clusterName, "apply", "--server-side", "-f", url); err != nil {
+		return fmt.Errorf("applying envoy-gateway %s: %w", envoyGatewayVersion, err)
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK applied envoy-gateway %s", envoyGatewayVersion))
+
+	if _, err := mgr.Kubectl(ctx, clusterName, "wait", "--for=condition=Available",
+		"deployment/envoy-gateway", "-n", "envoy-gateway-system", "--timeout=300s"); err != nil {
+		return fmt.Errorf("waiting for envoy-gateway: %w", err)
+	}
+	result.Steps = append(result.Steps, "OK envoy-gateway available")
+
+	result.Notes = "Envoy Gateway creates one Service per Gateway. Set an EnvoyProxy resource with " +
+		"type NodePort (or use 'kubectl port-forward') to reach Gateways from the host; " +
+		"Kind has no LoadBalancer support without cloud-provider-kind."
+	return nil
+}
+
+func gatewayPortMappings(opts GatewayAPIOptions) []kind.PortMapping {
+	return []kind.PortMapping{
+		{HostPort: 80, ContainerPort: opts.HTTPNodePort, ListenAddress: "127.0.0.1", Protocol: "TCP"},
+		{HostPort: 443, ContainerPort: opts.HTTPSNodePort, ListenAddress: "127.0.0.1", Protocol: "TCP"},
+	}
+}
@@ -0,0 +1,58 @@
+package addons
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInstallGatewayAPI_CRDsOnly(t *testing.T) {
+	runner := &mockRunner{outputs: map[string][]error{}}
+
+	result, err := InstallGatewayAPI(context.Background(), newManager(runner), "test",
+		GatewayAPIOptions{Channel: "experimental"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Steps) != 1 {
+		t.Errorf("steps = %v", result.Steps)
+	}
+	if !runner.called("v1.2.1/experimental-install.yaml") {
+		t.Error("expected experimental channel manifest to be applied")
+	}
+}
+
+func TestInstallGatewayAPI_NginxFabric(t *testing.T) {
+	runner := &mockRunner{outputs: map[string][]error{}}
+
+	result, err := InstallGatewayAPI(context.Background(), newManager(runner), "test",
+		GatewayAPIOptions{Implementation: GatewayNginxFabric})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.PortMappings) != 2 {
+		t.Fatalf("expected 2 port mappings, got %d", len(result.PortMappings))
+	}
+	if result.PortMappings[0].HostPort != 80 || result.PortMappings[0].ContainerPort != 30080 {
+		t.Errorf("http mapping = %+v", result.PortMappings[0])
+	}
+	if !runner.called(`"nodePort":30443`) {
+		t.Error("expected nginx-gateway service to be patched with NodePorts")
+	}
+}
+
+func TestInstallGatewayAPI_InvalidChannel(t *testing.T) {
+	_, err := InstallGatewayAPI(context.Background(), newManager(&mockRunner{}), "test",
+		GatewayAPIOptions{Channel: "beta"})
+	if err == nil {
+		t.Error("expected error for invalid channel")
+	}
+}
+
+func TestInstallGatewayAPI_UnknownImplementation(t *testing.T) {
+	runner := &mockRunner{outputs: map[string][]error{}}
+	_, err := InstallGatewayAPI(context.Background(), newManager(runner), "test",
+		GatewayAPIOptions{Implementation: "traefik"})
+	if err == nil {
+		t.Error("expected error for unsupported implementation")
+	}
+}
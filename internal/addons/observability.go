@@ -0,0 +1,314 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// Observability stacks that can be installed.
+const (
+	// ObservabilityDashboard is metrics-server plus the Kubernetes Dashboard.
+	ObservabilityDashboard = "dashboard"
+	// ObservabilityPrometheus is the kube-prometheus-stack Helm chart (Prometheus,
+	// Grafana, kube-state-metrics, and node-exporter).
+	ObservabilityPrometheus = "kube-prometheus-stack"
+)
+
+// Ways to reach an observability UI from the host.
+const (
+	AccessPortForward = "port-forward"
+	AccessNodePort    = "node-port"
+)
+
+const (
+	metricsServerVersion = "v0.7.2"
+	dashboardVersion     = "v2.7.0"
+	// DefaultKubePrometheusStackVersion is the chart version installed when none is specified.
+	DefaultKubePrometheusStackVersion = "66.3.1"
+
+	prometheusChartRepo = "https://prometheus-community.github.io/helm-charts"
+	monitoringNamespace = "monitoring"
+	dashboardNamespace  = "kubernetes-dashboard"
+)
+
+// ObservabilityOptions holds the parameters for installing an observability stack.
+type ObservabilityOptions struct {
+	Stack        string // ObservabilityDashboard (default) or ObservabilityPrometheus
+	Access       string // AccessPortForward (default) or AccessNodePort
+	NodePort     int    // NodePort of the UI service with AccessNodePort
+	HostPort     int    // host port mapped to NodePort
+	ChartVersion string // kube-prometheus-stack chart version
+	Timeout      time.Duration
+}
+
+// ObservabilityEndpoint tells how to open one UI of the stack from the host.
+type ObservabilityEndpoint struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// PortForward is the command that makes URL reachable when the cluster does not
+	// publish the UI's NodePort.
+	PortForward string `json:"port_forward,omitempty"`
+	Login       string `json:"login,omitempty"`
+}
+
+// ObservabilityResult reports what was installed and how to reach it.
+type ObservabilityResult struct {
+	Stack     string                  `json:"stack"`
+	Steps     []string                `json:"steps"`
+	Endpoints []ObservabilityEndpoint `json:"endpoints,omitempty"`
+	// PortMappings are the extraPortMappings the cluster needs to reach the UI on
+	// its NodePort; empty when it already publishes it.
+	PortMappings []kind.PortMapping `json:"port_mappings,omitempty"`
+	Notes        string             `json:"notes,omitempty"`
+}
+
+// InstallObservability installs a lightweight monitoring stack with values suited to
+// Kind and returns how to open its UIs: a kubectl port-forward command, or with
+// AccessNodePort the UI service on a fixed NodePort and the port mapping that reaches it.
+func InstallObservability(ctx context.Context, mgr *kind.Manager, clusterName string, opts ObservabilityOptions) (*ObservabilityResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if opts.Stack == "" {
+		opts.Stack = ObservabilityDashboard
+	}
+	if opts.Access == "" {
+		opts.Access = AccessPortForward
+	}
+	if opts.Access != AccessPortForward && opts.Access != AccessNodePort {
+		return nil, fmt.Errorf("invalid access %q; must be %q or %q", opts.Access, AccessPortForward, AccessNodePort)
+	}
+	if opts.NodePort != 0 && (opts.NodePort < 30000 || opts.NodePort > 32767) {
+		return nil, fmt.Errorf("node port %d is outside the default NodePort range 30000-32767", opts.NodePort)
+	}
+	if opts.HostPort < 0 || opts.HostPort > 65535 {
+		return nil, fmt.Errorf("invalid host port %d", opts.HostPort)
+	}
+	if opts.ChartVersion == "" {
+		opts.ChartVersion = DefaultKubePrometheusStackVersion
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+
+	result := &ObservabilityResult{Stack: opts.Stack}
+	switch opts.Stack {
+	case ObservabilityDashboard:
+		if opts.NodePort == 0 {
+			opts.NodePort = 31443
+		}
+		if opts.HostPort == 0 {
+			opts.HostPort = 8443
+		}
+		if err := installDashboard(ctx, mgr, clusterName, opts, result); err != nil {
+			return result, err
+		}
+	case ObservabilityPrometheus:
+		if opts.NodePort == 0 {
+			opts.NodePort = 31300
+		}
+		if opts.HostPort == 0 {
+			opts.HostPort = 3000
+		}
+		if err := installKubePrometheusStack(ctx, mgr, clusterName, opts, result); err != nil {
+			return result, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported stack %q; must be %q or %q",
+			opts.Stack, ObservabilityDashboard, ObservabilityPrometheus)
+	}
+
+	if opts.Access == AccessNodePort {
+		exposeOnNodePort(ctx, mgr, clusterName, opts, result)
+	}
+	return result, nil
+}
+
+func installDashboard(ctx context.Context, mgr *kind.Manager, clusterName string, opts ObservabilityOptions, result *ObservabilityResult) error {
+	timeout := fmt.Sprintf("--timeout=%ds", int(opts.Timeout.Seconds()))
+	if err := installMetricsServer(ctx, mgr, clusterName, timeout, result); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://raw.githubusercontent.com/kubernetes/dashboard/%s/aio/deploy/recommended.yaml", dashboardVersion)
+	if _, err := mgr.Kubectl(ctx, clusterName, "apply", "-f", url); err != nil {
+		return fmt.Errorf("applying Kubernetes Dashboard %s: %w", dashboardVersion, err)
+	}
+	if _, err := mgr.ApplyManifest(ctx, clusterName, dashboardAdminManifest); err != nil {
+		return fmt.Errorf("creating the dashboard admin-user: %w", err)
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK applied Kubernetes Dashboard %s with an admin-user ServiceAccount", dashboardVersion))
+
+	if opts.Access == AccessNodePort {
+		patch := fmt.Sprintf(`{"spec":{"type":"NodePort","ports":[{"port":443,"nodePort":%d}]}}`, opts.NodePort)
+		if _, err := mgr.Kubectl(ctx, clusterName, "patch", "service", "kubernetes-dashboard",
+			"-n", dashboardNamespace, "-p", patch); err != nil {
+			return fmt.Errorf("pinning the dashboard NodePort: %w", err)
+		}
+		result.Steps = append(result.Steps, fmt.Sprintf("OK pinned kubernetes-dashboard NodePort to %d", opts.NodePort))
+	}
+
+	if _, err := mgr.Kubectl(ctx, clusterName, "wait", "--for=condition=Available",
+		"deployment", "--all", "-n", dashboardNamespace, timeout); err != nil {
+		return fmt.Errorf("waiting for the Kubernetes Dashboard: %w", err)
+	}
+	result.Steps = append(result.Steps, "OK Kubernetes Dashboard available")
+
+	ctxFlag := "--context kind-" + clusterName
+	result.Endpoints = []ObservabilityEndpoint{{
+		Name: "Kubernetes Dashboard",
+		URL:  "https://localhost:8443",
+		PortForward: fmt.Sprintf("kubectl %s -n %s port-forward svc/kubernetes-dashboard 8443:443",
+			ctxFlag, dashboardNamespace),
+		Login: fmt.Sprintf("sign in with the token printed by 'kubectl %s -n %s create token admin-user'",
+			ctxFlag, dashboardNamespace),
+	}}
+	result.Notes = "The dashboard serves a self-signed certificate; accept it in the browser. " +
+		"'kubectl top' works once metrics-server has scraped the nodes (about a minute)."
+	return nil
+}
+
+// installMetricsServer applies metrics-server and lets it skip verifying kubelet serving
+// certificates, which Kind nodes sign themselves.
+func installMetricsServer(ctx context.Context, mgr *kind.Manager, clusterName, timeout string, result *ObservabilityResult) error {
+	url := fmt.Sprintf("https://github.com/kubernetes-sigs/metrics-server/releases/download/%s/components.yaml", metricsServerVersion)
+	if _, err := mgr.Kubectl(ctx, clusterName, "apply", "-f", url); err != nil {
+		return fmt.Errorf("applying metrics-server %s: %w", metricsServerVersion, err)
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK applied metrics-server %s", metricsServerVersion))
+
+	args, err := mgr.Kubectl(ctx, clusterName, "get", "deployment", "metrics-server", "-n", "kube-system",
+		"-o", "jsonpath={.spec.template.spec.containers[0].args}")
+	if err != nil {
+		return fmt.Errorf("reading metrics-server args: %w", err)
+	}
+	// Re-applying the manifest keeps the flag, so only add it once.
+	if !strings.Contains(args, "--kubelet-insecure-tls") {
+		patch := `[{"op":"add","path":"/spec/template/spec/containers/0/args/-","value":"--kubelet-insecure-tls"}]`
+		if _, err := mgr.Kubectl(ctx, clusterName, "patch", "deployment", "metrics-server",
+			"-n", "kube-system", "--type=json", "-p", patch); err != nil {
+			return fmt.Errorf("patching metrics-server for Kind kubelets: %w", err)
+		}
+	}
+	result.Steps = append(result.Steps, "OK configured metrics-server with --kubelet-insecure-tls")
+
+	if _, err := mgr.Kubectl(ctx, clusterName, "wait", "--for=condition=Available",
+		"deployment/metrics-server", "-n", "kube-system", timeout); err != nil {
+		return fmt.Errorf("waiting for metrics-server: %w", err)
+	}
+	result.Steps = append(result.Steps, "OK metrics-server available")
+	return nil
+}
+
+func installKubePrometheusStack(ctx context.Context, mgr *kind.Manager, clusterName string, opts ObservabilityOptions, result *ObservabilityResult) error {
+	args := []string{"upgrade", "--install", "kube-prometheus-stack", "kube-prometheus-stack",
+		"--repo", prometheusChartRepo, "--version", opts.ChartVersion,
+		"--namespace", monitoringNamespace, "--create-namespace",
+		"--wait", fmt.Sprintf("--timeout=%ds", int(opts.Timeout.Seconds()))}
+	for _, v := range kubePrometheusStackValues(opts) {
+		args = append(args, "--set", v)
+	}
+	if _, err := mgr.Helm(ctx, clusterName, args...); err != nil {
+		return fmt.Errorf("installing kube-prometheus-stack %s: %w", opts.ChartVersion, err)
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK installed kube-prometheus-stack %s in namespace %s",
+		opts.ChartVersion, monitoringNamespace))
+
+	ctxFlag := "--context kind-" + clusterName
+	result.Endpoints = []ObservabilityEndpoint{
+		{
+			Name: "Grafana",
+			URL:  "http://localhost:3000",
+			PortForward: fmt.Sprintf("kubectl %s -n %s port-forward svc/kube-prometheus-stack-grafana 3000:80",
+				ctxFlag, monitoringNamespace),
+			Login: fmt.Sprintf("user 'admin', password from 'kubectl %s -n %s get secret kube-prometheus-stack-grafana "+
+				"-o jsonpath={.data.admin-password} | base64 -d'", ctxFlag, monitoringNamespace),
+		},
+		{
+			Name: "Prometheus",
+			URL:  "http://localhost:9090",
+			PortForward: fmt.Sprintf("kubectl %s -n %s port-forward svc/kube-prometheus-stack-prometheus 9090:9090",
+				ctxFlag, monitoringNamespace),
+		},
+	}
+	result.Notes = "Alertmanager and the control-plane component monitors are disabled: Kind binds " +
+		"etcd, the scheduler, the controller manager, and kube-proxy metrics to localhost. " +
+		"Prometheus selects every ServiceMonitor and PodMonitor in the cluster."
+	return nil
+}
+
+// kubePrometheusStackValues returns the chart values that keep the stack small and
+// free of alerts that always fire on Kind.
+func kubePrometheusStackValues(opts ObservabilityOptions) []string {
+	values := []string{
+		"alertmanager.enabled=false",
+		"kubeEtcd.enabled=false",
+		"kubeScheduler.enabled=false",
+		"kubeControllerManager.enabled=false",
+		"kubeProxy.enabled=false",
+		// Kind node root filesystems are not shared mounts, which the host rootfs mount needs.
+		"prometheus-node-exporter.hostRootFsMount.enabled=false",
+		"prometheus.prometheusSpec.retention=1d",
+		"prometheus.prometheusSpec.serviceMonitorSelectorNilUsesHelmValues=false",
+		"prometheus.prometheusSpec.podMonitorSelectorNilUsesHelmValues=false",
+	}
+	if opts.Access == AccessNodePort {
+		values = append(values, "grafana.service.type=NodePort",
+			fmt.Sprintf("grafana.service.nodePort=%d", opts.NodePort))
+	}
+	return values
+}
+
+// exposeOnNodePort points the first endpoint at the host port the cluster publishes
+// its NodePort on, or adds the port mapping it needs when there is none.
+func exposeOnNodePort(ctx context.Context, mgr *kind.Manager, clusterName string, opts ObservabilityOptions, result *ObservabilityResult) {
+	if len(result.Endpoints) == 0 {
+		return
+	}
+	ui := &result.Endpoints[0]
+	scheme, _, _ := strings.Cut(ui.URL, "://")
+
+	if addr := mgr.PublishedPort(ctx, clusterName, opts.NodePort); addr != "" {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if host == "0.0.0.0" || host == "::" || host == "" {
+				host = "localhost"
+			}
+			ui.URL = fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port))
+			ui.PortForward = ""
+			result.Notes += fmt.Sprintf(" %s is published on %s through NodePort %d.", ui.Name, addr, opts.NodePort)
+			return
+		}
+	}
+
+	result.PortMappings = []kind.PortMapping{
+		{HostPort: opts.HostPort, ContainerPort: opts.NodePort, ListenAddress: "127.0.0.1", Protocol: "TCP"},
+	}
+	result.Notes += fmt.Sprintf(" %s listens on NodePort %d, which this cluster does not publish: add the port "+
+		"mapping to the first control-plane node (generate_cluster_config) and recreate the cluster to reach it "+
+		"at %s://localhost:%d. Until then use the port_forward command.", ui.Name, opts.NodePort, scheme, opts.HostPort)
+}
+
+const dashboardAdminManifest = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: admin-user
+  namespace: kubernetes-dashboard
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kubernetes-dashboard-admin-user
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: cluster-admin
+subjects:
+- kind: ServiceAccount
+  name: admin-user
+  namespace: kubernetes-dashboard
+`
@@ -0,0 +1,67 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInstallObservability_Dashboard(t *testing.T) {
+	runner := &mockRunner{outputs: map[string][]error{}}
+
+	result, err := InstallObservability(context.Background(), newManager(runner), "dev", ObservabilityOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !runner.called("metrics-server/releases/download/v0.7.2/components.yaml") ||
+		!runner.called("dashboard/v2.7.0/aio/deploy/recommended.yaml") {
+		t.Error("expected metrics-server and dashboard manifests to be applied")
+	}
+	if !runner.called("--kubelet-insecure-tls") {
+		t.Error("expected metrics-server to be patched for Kind kubelets")
+	}
+	if runner.called("nodePort") {
+		t.Error("port-forward access should not pin a NodePort")
+	}
+	if len(result.Endpoints) != 1 || !strings.Contains(result.Endpoints[0].PortForward, "--context kind-dev") {
+		t.Errorf("endpoints = %+v", result.Endpoints)
+	}
+}
+
+func TestInstallObservability_PrometheusNodePort(t *testing.T) {
+	runner := &mockRunner{outputs: map[string][]error{
+		// the cluster does not publish the NodePort
+		"31300/tcp": {fmt.Errorf("no public port")},
+	}}
+
+	result, err := InstallObservability(context.Background(), newManager(runner), "dev",
+		ObservabilityOptions{Stack: ObservabilityPrometheus, Access: AccessNodePort})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"upgrade --install kube-prometheus-stack", "--version 66.3.1",
+		"kubeEtcd.enabled=false", "grafana.service.nodePort=31300"} {
+		if !runner.called(want) {
+			t.Errorf("helm not called with %q", want)
+		}
+	}
+	if len(result.PortMappings) != 1 || result.PortMappings[0].HostPort != 3000 || result.PortMappings[0].ContainerPort != 31300 {
+		t.Errorf("port mappings = %+v", result.PortMappings)
+	}
+	if len(result.Endpoints) != 2 || result.Endpoints[0].PortForward == "" {
+		t.Errorf("endpoints = %+v", result.Endpoints)
+	}
+}
+
+func TestInstallObservability_InvalidOptions(t *testing.T) {
+	for _, opts := range []ObservabilityOptions{
+		{Stack: "loki"},
+		{Access: "ingress"},
+		{NodePort: 8080},
+	} {
+		if _, err := InstallObservability(context.Background(), newManager(&mockRunner{}), "dev", opts); err == nil {
+			t.Errorf("expected error for %+v", opts)
+		}
+	}
+}
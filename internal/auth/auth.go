@@ -0,0 +1,127 @@
+// Package auth identifies the clients of the HTTP transport, so clusters can be
+// recorded with the client that created them and, optionally, only be changed by it.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ClientIDHeader names the client when no tokens are configured. It is taken on
+// trust, so it only separates cooperating clients.
+const ClientIDHeader = "X-MCP-Client-ID"
+
+// Identity is the client a tool call comes from.
+type Identity struct {
+	// Name identifies the client; it is recorded as the owner of the clusters it
+	// creates. It is empty for anonymous clients.
+	Name string
+	// Admin clients may change every cluster, whoever owns it.
+	Admin bool
+}
+
+// Config configures how HTTP clients are identified.
+type Config struct {
+	// Tokens maps bearer tokens to client names. When it is empty, clients name
+	// themselves with ClientIDHeader and no token is required.
+	Tokens map[string]string
+	// Admins lists the client names that may change every cluster.
+	Admins []string
+}
+
+// ErrUnauthorized is returned for a request without a valid bearer token.
+var ErrUnauthorized = errors.New("a valid bearer token is required")
+
+// ConfigFromEnv reads MCP_KIND_HTTP_TOKENS, a comma-separated list of name=token
+// pairs, and MCP_KIND_ADMINS, a comma-separated list of client names.
+func ConfigFromEnv() (Config, error) {
+	var cfg Config
+	if raw := os.Getenv("MCP_KIND_HTTP_TOKENS"); raw != "" {
+		cfg.Tokens = make(map[string]string)
+		for _, pair := range strings.Split(raw, ",") {
+			name, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || name == "" || token == "" {
+				return cfg, fmt.Errorf("MCP_KIND_HTTP_TOKENS must be name=token pairs separated by commas, got %q", pair)
+			}
+			if _, dup := cfg.Tokens[token]; dup {
+				return cfg, fmt.Errorf("MCP_KIND_HTTP_TOKENS: token of %q is used by another client", name)
+			}
+			cfg.Tokens[token] = name
+		}
+	}
+	for _, name := range strings.Split(os.Getenv("MCP_KIND_ADMINS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.Admins = append(cfg.Admins, name)
+		}
+	}
+	return cfg, nil
+}
+
+// Identify returns the identity of the client sending r: the name of its bearer token
+// when tokens are configured, else the name in ClientIDHeader.
+func (c Config) Identify(r *http.Request) (Identity, error) {
+	var name string
+	if len(c.Tokens) > 0 {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return Identity{}, ErrUnauthorized
+		}
+		if name = c.lookup(strings.TrimSpace(token)); name == "" {
+			return Identity{}, ErrUnauthorized
+		}
+	} else {
+		name = strings.TrimSpace(r.Header.Get(ClientIDHeader))
+	}
+	return Identity{Name: name, Admin: name != "" && slices.Contains(c.Admins, name)}, nil
+}
+
+// lookup returns the client name of token, comparing in constant time.
+func (c Config) lookup(token string) string {
+	var name string
+	for known, n := range c.Tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			name = n
+		}
+	}
+	return name
+}
+
+// Middleware rejects requests Identify fails for with 401 Unauthorized and passes the
+// identity of the others on in the request context.
+func (c Config) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := c.Identify(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying id.
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity in ctx. ok is false for calls that did not come
+// over HTTP, such as those over stdio.
+func FromContext(ctx context.Context) (id Identity, ok bool) {
+	id, ok = ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
+
+// MayChange reports whether id may change a cluster owned by owner. Clusters without
+// an owner may be changed by every client.
+func (id Identity) MayChange(owner string) bool {
+	return owner == "" || id.Admin || id.Name == owner
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("MCP_KIND_HTTP_TOKENS", "alice=tok-a, bob=tok-b")
+	t.Setenv("MCP_KIND_ADMINS", "alice,")
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Tokens["tok-a"] != "alice" || cfg.Tokens["tok-b"] != "bob" || len(cfg.Admins) != 1 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	for _, bad := range []string{"alice", "=tok", "alice=", "alice=x,bob=x"} {
+		t.Setenv("MCP_KIND_HTTP_TOKENS", bad)
+		if _, err := ConfigFromEnv(); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestIdentify_Tokens(t *testing.T) {
+	cfg := Config{Tokens: map[string]string{"tok-a": "alice", "tok-b": "bob"}, Admins: []string{"alice"}}
+	for header, want := range map[string]Identity{
+		"Bearer tok-a": {Name: "alice", Admin: true},
+		"Bearer tok-b": {Name: "bob"},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.Header.Set("Authorization", header)
+		r.Header.Set(ClientIDHeader, "mallory")
+		got, err := cfg.Identify(r)
+		if err != nil || got != want {
+			t.Errorf("%s: got %+v, %v; want %+v", header, got, err, want)
+		}
+	}
+	for _, header := range []string{"", "Bearer", "Bearer nope", "Basic tok-a"} {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.Header.Set("Authorization", header)
+		if _, err := cfg.Identify(r); err != ErrUnauthorized {
+			t.Errorf("%q: expected ErrUnauthorized, got %v", header, err)
+		}
+	}
+}
+
+func TestIdentify_ClientID(t *testing.T) {
+	cfg := Config{Admins: []string{"ops"}}
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	if id, err := cfg.Identify(r); err != nil || id != (Identity{}) {
+		t.Errorf("expected an anonymous identity, got %+v, %v", id, err)
+	}
+	r.Header.Set(ClientIDHeader, "ops")
+	if id, _ := cfg.Identify(r); id != (Identity{Name: "ops", Admin: true}) {
+		t.Errorf("unexpected identity %+v", id)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := Config{Tokens: map[string]string{"tok": "alice"}}
+	var got Identity
+	h := cfg.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("expected 401 with a challenge, got %d", w.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	r.Header.Set("Authorization", "Bearer tok")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || got.Name != "alice" {
+		t.Errorf("got %d and %+v", w.Code, got)
+	}
+}
+
+func TestMayChange(t *testing.T) {
+	alice, admin := Identity{Name: "alice"}, Identity{Name: "ops", Admin: true}
+	if !alice.MayChange("alice") || !alice.MayChange("") || alice.MayChange("bob") {
+		t.Error("only owned-by-alice and unowned clusters should be changeable by alice")
+	}
+	if !admin.MayChange("bob") {
+		t.Error("admins should change every cluster")
+	}
+	if (Identity{}).MayChange("bob") {
+		t.Error("anonymous clients should not change owned clusters")
+	}
+}
@@ -0,0 +1,450 @@
+// Package bootstrap brings up a ready development environment from one declarative
+// spec: a Kind cluster, the images it needs, add-ons, Helm charts and manifests.
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/addons"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// Add-ons a spec can install.
+const (
+	AddonCertManager   = "cert-manager"
+	AddonGatewayAPI    = "gateway-api"
+	AddonObservability = "observability"
+)
+
+// Addons lists the add-on names a spec accepts.
+var Addons = []string{AddonCertManager, AddonGatewayAPI, AddonObservability}
+
+// DefaultTimeout bounds each wait: add-on readiness, Helm installs and WaitReady.
+const DefaultTimeout = 5 * time.Minute
+
+// Step statuses.
+const (
+	StepOK      = "ok"
+	StepFailed  = "failed"
+	StepSkipped = "skipped"
+)
+
+// Spec describes an environment. Its parts are set up in a fixed order: the cluster,
+// then images, add-ons, charts and manifests, so manifests can use the images and
+// the CRDs the add-ons and charts install.
+type Spec struct {
+	Cluster ClusterSpec `json:"cluster"`
+	// ReuseExisting sets up the rest of the spec in an existing cluster of the same
+	// name instead of failing.
+	ReuseExisting bool `json:"reuse_existing,omitempty"`
+	// Images are loaded from the local container runtime onto every node.
+	Images    []string   `json:"images,omitempty"`
+	Addons    []Addon    `json:"addons,omitempty"`
+	Charts    []Chart    `json:"charts,omitempty"`
+	Manifests []Manifest `json:"manifests,omitempty"`
+	// WaitReady waits for every Deployment in the cluster to become Available.
+	WaitReady      bool `json:"wait_ready,omitempty"`
+	TimeoutSeconds int  `json:"timeout_seconds,omitempty"`
+	// KeepOnFailure skips the rollback, leaving a failed environment for inspection.
+	KeepOnFailure bool `json:"keep_on_failure,omitempty"`
+}
+
+// ClusterSpec is the cluster to create: either a complete Kind config, or the
+// generate_cluster_config options it is generated from.
+type ClusterSpec struct {
+	Name                string                     `json:"name"`
+	ConfigYAML          string                     `json:"config_yaml,omitempty"`
+	ControlPlanes       int                        `json:"control_planes,omitempty"`
+	Workers             int                        `json:"workers,omitempty"`
+	KubernetesVersion   string                     `json:"kubernetes_version,omitempty"`
+	NodeImageRepository string                     `json:"node_image_repository,omitempty"`
+	DisableDefaultCNI   bool                       `json:"disable_default_cni,omitempty"`
+	EnableIngressPorts  bool                       `json:"enable_ingress_ports,omitempty"`
+	IngressListenAddr   string                     `json:"ingress_listen_address,omitempty"`
+	PortMappings        []kind.TargetedPortMapping `json:"port_mappings,omitempty"`
+	ExtraMounts         []kind.TargetedMount       `json:"extra_mounts,omitempty"`
+	// Overrides is raw config YAML merged onto the generated config with
+	// kind.MergeConfigOverrides.
+	Overrides string `json:"overrides,omitempty"`
+}
+
+// Addon is an add-on to install with the options of its install tool.
+type Addon struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// cert-manager
+	CreateSelfSignedIssuer bool `json:"create_self_signed_issuer,omitempty"`
+	// gateway-api
+	Channel        string `json:"channel,omitempty"`
+	Implementation string `json:"implementation,omitempty"`
+	// observability
+	Stack        string `json:"stack,omitempty"`
+	Access       string `json:"access,omitempty"`
+	ChartVersion string `json:"chart_version,omitempty"`
+}
+
+// Chart is a Helm chart to install, from a repository URL or an OCI or local chart reference.
+type Chart struct {
+	Release   string            `json:"release"`
+	Chart     string            `json:"chart"`
+	Repo      string            `json:"repo,omitempty"`
+	Version   string            `json:"version,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Values    map[string]string `json:"values,omitempty"`
+}
+
+// Manifest is applied with kubectl apply: Source is a URL, file or directory, Inline
+// is YAML text. Exactly one of them is set.
+type Manifest struct {
+	Source    string `json:"source,omitempty"`
+	Inline    string `json:"inline,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Step is the outcome of one step of a bootstrap or its rollback.
+type Step struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Result reports each step of a bootstrap and, when one failed, of its rollback.
+type Result struct {
+	Cluster   string `json:"cluster"`
+	Created   bool   `json:"created"`
+	Succeeded bool   `json:"succeeded"`
+	// ConfigYAML is the Kind config the cluster was created from.
+	ConfigYAML string   `json:"config_yaml,omitempty"`
+	Steps      []Step   `json:"steps"`
+	Rollback   []Step   `json:"rollback,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// step is a bootstrap step; undo, when set, reverts it during a rollback of an
+// existing cluster.
+type step struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+	undo func(ctx context.Context) error
+}
+
+// Validate checks a spec before anything is created.
+func (s Spec) Validate() error {
+	var errs []error
+	if err := kind.ValidateClusterName(s.Cluster.Name); err != nil {
+		errs = append(errs, fmt.Errorf("cluster.name: %w", err))
+	}
+	if s.TimeoutSeconds < 0 {
+		errs = append(errs, fmt.Errorf("timeout_seconds must not be negative"))
+	}
+	for i, image := range s.Images {
+		if strings.TrimSpace(image) == "" {
+			errs = append(errs, fmt.Errorf("images[%d] is empty", i))
+		}
+	}
+	for i, a := range s.Addons {
+		if !slices.Contains(Addons, a.Name) {
+			errs = append(errs, fmt.Errorf("addons[%d]: unknown add-on %q; must be one of %s",
+				i, a.Name, strings.Join(Addons, ", ")))
+		}
+	}
+	for i, c := range s.Charts {
+		if c.Release == "" || c.Chart == "" {
+			errs = append(errs, fmt.Errorf("charts[%d]: release and chart are required", i))
+		}
+	}
+	for i, m := range s.Manifests {
+		if (m.Source == "") == (m.Inline == "") {
+			errs = append(errs, fmt.Errorf("manifests[%d]: set exactly one of source and inline", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// KindConfig returns the Kind config of the cluster: ConfigYAML when set, else one
+// generated from the other options.
+func (c ClusterSpec) KindConfig() (string, error) {
+	if c.ConfigYAML != "" {
+		return c.ConfigYAML, nil
+	}
+	opts := kind.ConfigOptions{
+		ClusterName:          c.Name,
+		NumControlPlanes:     max(c.ControlPlanes, 1),
+		NumWorkers:           c.Workers,
+		KubernetesVersion:    c.KubernetesVersion,
+		NodeImageRepository:  c.NodeImageRepository,
+		DisableDefaultCNI:    c.DisableDefaultCNI,
+		EnableIngressPorts:   c.EnableIngressPorts,
+		IngressListenAddress: c.IngressListenAddr,
+		NodeMounts:           c.ExtraMounts,
+	}
+	for _, pm := range c.PortMappings {
+		if pm.Target == (kind.NodeTarget{}) {
+			opts.PortMappings = append(opts.PortMappings, pm.PortMapping)
+		} else {
+			opts.NodePortMappings = append(opts.NodePortMappings, pm)
+		}
+	}
+	configYAML, err := kind.GenerateConfig(opts)
+	if err != nil || c.Overrides == "" {
+		return configYAML, err
+	}
+	return kind.MergeConfigOverrides(configYAML, c.Overrides)
+}
+
+// Run sets up the environment a spec describes, one step at a time. When a step
+// fails, the remaining steps are skipped and, unless KeepOnFailure is set, the
+// environment is rolled back: a cluster this run created is deleted; in an existing
+// cluster the charts and manifests this run installed are removed. Run returns the
+// step-by-step result along with the error of the failed step.
+func Run(ctx context.Context, mgr *kind.Manager, spec Spec) (*Result, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	timeout := DefaultTimeout
+	if spec.TimeoutSeconds > 0 {
+		timeout = time.Duration(spec.TimeoutSeconds) * time.Second
+	}
+	name := spec.Cluster.Name
+	res := &Result{Cluster: name}
+
+	clusters, err := mgr.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exists := slices.Contains(clusters, name)
+	if exists && !spec.ReuseExisting {
+		return nil, fmt.Errorf("cluster %q already exists; set reuse_existing to set up the environment in it, "+
+			"or delete it first", name)
+	}
+
+	var steps []step
+	if exists {
+		res.Steps = append(res.Steps, Step{Name: "create cluster " + name, Status: StepSkipped,
+			Detail: "cluster exists; reusing it"})
+	} else {
+		configYAML, err := spec.Cluster.KindConfig()
+		if err != nil {
+			return nil, fmt.Errorf("generating cluster config: %w", err)
+		}
+		res.ConfigYAML = configYAML
+		steps = append(steps, step{name: "create cluster " + name, run: func(ctx context.Context) (string, error) {
+			return createCluster(ctx, mgr, name, configYAML, res)
+		}})
+	}
+	for _, image := range spec.Images {
+		steps = append(steps, step{name: "load image " + image, run: func(ctx context.Context) (string, error) {
+			return "", mgr.LoadImage(ctx, name, image)
+		}})
+	}
+	for _, a := range spec.Addons {
+		steps = append(steps, step{name: "install " + a.Name, run: func(ctx context.Context) (string, error) {
+			return installAddon(ctx, mgr, name, a, timeout)
+		}})
+	}
+	for _, c := range spec.Charts {
+		steps = append(steps, chartStep(mgr, name, c, timeout))
+	}
+	for i, m := range spec.Manifests {
+		steps = append(steps, manifestStep(mgr, name, i, m))
+	}
+	if spec.WaitReady {
+		steps = append(steps, step{name: "wait for deployments", run: func(ctx context.Context) (string, error) {
+			_, err := mgr.Kubectl(ctx, name, "wait", "--for=condition=Available", "deployment", "--all",
+				"--all-namespaces", fmt.Sprintf("--timeout=%ds", int(timeout.Seconds())))
+			return "", err
+		}})
+	}
+
+	for i, st := range steps {
+		detail, err := st.run(ctx)
+		if err == nil {
+			res.Steps = append(res.Steps, Step{Name: st.name, Status: StepOK, Detail: detail})
+			continue
+		}
+		res.Steps = append(res.Steps, Step{Name: st.name, Status: StepFailed, Detail: err.Error()})
+		for _, rest := range steps[i+1:] {
+			res.Steps = append(res.Steps, Step{Name: rest.name, Status: StepSkipped})
+		}
+		if spec.KeepOnFailure {
+			res.Warnings = append(res.Warnings, "keep_on_failure is set; nothing was rolled back")
+		} else {
+			rollback(ctx, mgr, res, steps[:i])
+		}
+		return res, fmt.Errorf("%s: %w", st.name, err)
+	}
+	res.Succeeded = true
+	return res, nil
+}
+
+// createCluster creates the cluster after the preflight checks create_cluster runs.
+func createCluster(ctx context.Context, mgr *kind.Manager, name, configYAML string, res *Result) (string, error) {
+	if err := mgr.CheckNodeImages(ctx, configYAML); err != nil {
+		return "", fmt.Errorf("node image preflight failed: %w", err)
+	}
+	for _, check := range []kind.PreflightCheck{mgr.CheckIPFamily(ctx, configYAML),
+		mgr.CheckKindCompatibility(ctx, configYAML), mgr.CheckNodeImageArch(ctx, configYAML)} {
+		switch check.Status {
+		case kind.CheckFail:
+			return "", fmt.Errorf("%s preflight failed: %s", check.Name, check.Message)
+		case kind.CheckWarn:
+			res.Warnings = append(res.Warnings, check.Message)
+		}
+	}
+	if _, err := mgr.CreateClusterWithOptions(ctx, name, configYAML, kind.CreateOptions{}); err != nil {
+		return "", err
+	}
+	res.Created = true
+	return "", nil
+}
+
+// installAddon installs one add-on and summarizes what its installer reported.
+func installAddon(ctx context.Context, mgr *kind.Manager, cluster string, a Addon, timeout time.Duration) (string, error) {
+	switch a.Name {
+	case AddonCertManager:
+		results, err := addons.InstallCertManager(ctx, mgr, cluster, addons.CertManagerOptions{
+			Version: a.Version, CreateSelfSignedIssuer: a.CreateSelfSignedIssuer, Timeout: timeout,
+		})
+		return strings.Join(results, "; "), err
+	case AddonGatewayAPI:
+		result, err := addons.InstallGatewayAPI(ctx, mgr, cluster, addons.GatewayAPIOptions{
+			Version: a.Version, Channel: a.Channel, Implementation: a.Implementation,
+		})
+		if result == nil {
+			return "", err
+		}
+		return strings.Join(result.Steps, "; "), err
+	default:
+		result, err := addons.InstallObservability(ctx, mgr, cluster, addons.ObservabilityOptions{
+			Stack: a.Stack, Access: a.Access, ChartVersion: a.ChartVersion, Timeout: timeout,
+		})
+		if result == nil {
+			return "", err
+		}
+		return strings.Join(result.Steps, "; "), err
+	}
+}
+
+// chartStep installs a chart with helm upgrade --install; its undo uninstalls the release.
+func chartStep(mgr *kind.Manager, cluster string, c Chart, timeout time.Duration) step {
+	namespace := c.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return step{
+		name: fmt.Sprintf("install chart %s (%s)", c.Release, c.Chart),
+		run: func(ctx context.Context) (string, error) {
+			args := []string{"upgrade", "--install", c.Release, c.Chart,
+				"--namespace", namespace, "--create-namespace",
+				"--wait", fmt.Sprintf("--timeout=%ds", int(timeout.Seconds()))}
+			if c.Repo != "" {
+				args = append(args, "--repo", c.Repo)
+			}
+			if c.Version != "" {
+				args = append(args, "--version", c.Version)
+			}
+			keys := make([]string, 0, len(c.Values))
+			for k := range c.Values {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				args = append(args, "--set", k+"="+c.Values[k])
+			}
+			if _, err := mgr.Helm(ctx, cluster, args...); err != nil {
+				return "", err
+			}
+			return "release " + c.Release + " in namespace " + namespace, nil
+		},
+		undo: func(ctx context.Context) error {
+			_, err := mgr.Helm(ctx, cluster, "uninstall", c.Release, "--namespace", namespace)
+			return err
+		},
+	}
+}
+
+// manifestStep applies a manifest; its undo deletes what it applied.
+func manifestStep(mgr *kind.Manager, cluster string, index int, m Manifest) step {
+	name := "apply " + m.Source
+	if m.Inline != "" {
+		name = fmt.Sprintf("apply inline manifest #%d", index+1)
+	}
+	kubectl := func(ctx context.Context, verb string, extra ...string) (string, error) {
+		args := []string{verb}
+		if m.Namespace != "" {
+			args = append(args, "--namespace", m.Namespace)
+		}
+		if m.Source != "" {
+			source := m.Source
+			if !strings.Contains(source, "://") {
+				source = kind.ExpandHome(source)
+			}
+			return mgr.Kubectl(ctx, cluster, append(append(args, "-f", source), extra...)...)
+		}
+		f, err := os.CreateTemp("", "kind-manifest-*.yaml")
+		if err != nil {
+			return "", fmt.Errorf("creating temp manifest file: %w", err)
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.WriteString(m.Inline); err != nil {
+			f.Close()
+			return "", fmt.Errorf("writing manifest to temp file: %w", err)
+		}
+		f.Close()
+		return mgr.Kubectl(ctx, cluster, append(append(args, "-f", f.Name()), extra...)...)
+	}
+	return step{
+		name: name,
+		run: func(ctx context.Context) (string, error) {
+			out, err := kubectl(ctx, "apply")
+			return strings.TrimSpace(out), err
+		},
+		undo: func(ctx context.Context) error {
+			_, err := kubectl(ctx, "delete", "--ignore-not-found")
+			return err
+		},
+	}
+}
+
+// rollbackTimeout bounds the rollback, which runs even when the bootstrap was cancelled.
+const rollbackTimeout = 5 * time.Minute
+
+// rollback reverts the completed steps: a cluster this run created is deleted, else
+// the completed steps with an undo are reverted newest first.
+func rollback(ctx context.Context, mgr *kind.Manager, res *Result, done []step) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+	if res.Created {
+		st := Step{Name: "delete cluster " + res.Cluster, Status: StepOK}
+		if _, err := mgr.DeleteCluster(ctx, res.Cluster); err != nil {
+			st.Status, st.Detail = StepFailed, err.Error()
+		} else {
+			res.Created = false
+		}
+		res.Rollback = append(res.Rollback, st)
+		return
+	}
+	kept := false
+	for _, st := range slices.Backward(done) {
+		if st.undo == nil {
+			kept = true
+			continue
+		}
+		undone := Step{Name: "undo " + st.name, Status: StepOK}
+		if err := st.undo(ctx); err != nil {
+			undone.Status, undone.Detail = StepFailed, err.Error()
+		}
+		res.Rollback = append(res.Rollback, undone)
+	}
+	if kept {
+		res.Warnings = append(res.Warnings, "loaded images and installed add-ons stay in the existing cluster; "+
+			"delete the cluster to remove them")
+	}
+}
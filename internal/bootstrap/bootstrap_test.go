@@ -0,0 +1,203 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// mockRunner records invocations, lists clusters, and fails the commands that
+// contain one of failOn.
+type mockRunner struct {
+	clusters string
+	failOn   []string
+	calls    []string
+}
+
+func (m *mockRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	call := strings.Join(append([]string{name}, args...), " ")
+	m.calls = append(m.calls, call)
+	for _, s := range m.failOn {
+		if strings.Contains(call, s) {
+			return []byte("boom"), fmt.Errorf("exit status 1")
+		}
+	}
+	switch {
+	case call == "kind get clusters":
+		return []byte(m.clusters), nil
+	case name == "kind":
+		return []byte("apiVersion: v1\n"), nil
+	}
+	return []byte("ok\n"), nil
+}
+
+func (m *mockRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	out, err := m.Run(ctx, name, args...)
+	return out, nil, err
+}
+
+func (m *mockRunner) LookPath(name string) (string, error) {
+	return "/usr/bin/" + name, nil
+}
+
+// called returns the index of the first call containing substr, or -1.
+func (m *mockRunner) called(substr string) int {
+	for i, c := range m.calls {
+		if strings.Contains(c, substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func newManager(runner *mockRunner) *kind.Manager {
+	return kind.NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+}
+
+func testSpec() Spec {
+	return Spec{
+		Cluster: ClusterSpec{Name: "dev", Workers: 1},
+		Charts:  []Chart{{Release: "redis", Chart: "redis", Repo: "https://charts.example.com", Values: map[string]string{"b": "2", "a": "1"}}},
+		Manifests: []Manifest{
+			{Source: "https://example.com/app.yaml"},
+			{Inline: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n", Namespace: "app"},
+		},
+	}
+}
+
+func TestRun_Succeeds(t *testing.T) {
+	runner := &mockRunner{}
+	res, err := Run(context.Background(), newManager(runner), testSpec())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Succeeded || !res.Created || res.ConfigYAML == "" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if len(res.Steps) != 4 {
+		t.Fatalf("expected 4 steps, got %+v", res.Steps)
+	}
+	for _, st := range res.Steps {
+		if st.Status != StepOK {
+			t.Errorf("step %q: status %s", st.Name, st.Status)
+		}
+	}
+	create, helm, apply := runner.called("kind create cluster --name dev"), runner.called("helm"), runner.called("apply -f https://example.com/app.yaml")
+	if create < 0 || helm < create || apply < helm {
+		t.Errorf("expected create, chart, manifest in order; calls: %v", runner.calls)
+	}
+	if runner.called("--set a=1 --set b=2") < 0 {
+		t.Errorf("expected sorted chart values; calls: %v", runner.calls)
+	}
+}
+
+func TestRun_ConfigOverrides(t *testing.T) {
+	spec := Spec{Cluster: ClusterSpec{Name: "dev", Overrides: "featureGates:\n  SidecarContainers: true\n"}}
+	res, err := Run(context.Background(), newManager(&mockRunner{}), spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(res.ConfigYAML, "SidecarContainers: true") {
+		t.Errorf("overrides not merged:\n%s", res.ConfigYAML)
+	}
+}
+
+func TestRun_RollsBackCreatedCluster(t *testing.T) {
+	runner := &mockRunner{failOn: []string{"apply -f https://example.com/app.yaml"}}
+	res, err := Run(context.Background(), newManager(runner), testSpec())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	want := []string{StepOK, StepOK, StepFailed, StepSkipped}
+	for i, st := range res.Steps {
+		if st.Status != want[i] {
+			t.Errorf("step %d %q: status %s, want %s", i, st.Name, st.Status, want[i])
+		}
+	}
+	if len(res.Rollback) != 1 || res.Rollback[0].Name != "delete cluster dev" || res.Rollback[0].Status != StepOK {
+		t.Errorf("expected the cluster to be deleted, got %+v", res.Rollback)
+	}
+	if res.Created || runner.called("kind delete cluster --name dev") < 0 {
+		t.Errorf("expected kind delete cluster; calls: %v", runner.calls)
+	}
+	if runner.called("helm uninstall") >= 0 {
+		t.Error("charts are not uninstalled when the whole cluster is deleted")
+	}
+}
+
+func TestRun_RollsBackExistingCluster(t *testing.T) {
+	runner := &mockRunner{clusters: "dev\n", failOn: []string{"apply --namespace app"}}
+	spec := testSpec()
+	spec.ReuseExisting = true
+	spec.Images = []string{"app:dev"}
+	res, err := Run(context.Background(), newManager(runner), spec)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if res.Created || res.Steps[0].Status != StepSkipped {
+		t.Errorf("expected the existing cluster to be reused, got %+v", res.Steps[0])
+	}
+	if runner.called("kind create cluster") >= 0 || runner.called("kind delete cluster") >= 0 {
+		t.Errorf("existing cluster must not be created or deleted; calls: %v", runner.calls)
+	}
+	var names []string
+	for _, st := range res.Rollback {
+		names = append(names, st.Name)
+	}
+	want := "undo apply https://example.com/app.yaml,undo install chart redis (redis)"
+	if strings.Join(names, ",") != want {
+		t.Errorf("rollback = %v, want %s", names, want)
+	}
+	if runner.called("delete -f https://example.com/app.yaml --ignore-not-found") < 0 || runner.called("uninstall redis") < 0 {
+		t.Errorf("expected manifest delete and helm uninstall; calls: %v", runner.calls)
+	}
+	if len(res.Warnings) == 0 {
+		t.Error("expected a warning about the loaded image left in the cluster")
+	}
+}
+
+func TestRun_KeepOnFailure(t *testing.T) {
+	runner := &mockRunner{failOn: []string{"helm"}}
+	spec := testSpec()
+	spec.KeepOnFailure = true
+	res, err := Run(context.Background(), newManager(runner), spec)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(res.Rollback) != 0 || !res.Created || runner.called("kind delete cluster") >= 0 {
+		t.Errorf("expected no rollback, got %+v", res)
+	}
+}
+
+func TestRun_ExistingClusterWithoutReuse(t *testing.T) {
+	runner := &mockRunner{clusters: "dev\n"}
+	if _, err := Run(context.Background(), newManager(runner), testSpec()); err == nil ||
+		!strings.Contains(err.Error(), "reuse_existing") {
+		t.Errorf("expected an already-exists error, got %v", err)
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	spec := Spec{
+		Images:    []string{" "},
+		Addons:    []Addon{{Name: "istio"}},
+		Charts:    []Chart{{Release: "x"}},
+		Manifests: []Manifest{{}, {Source: "a.yaml", Inline: "kind: List"}},
+	}
+	err := spec.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"cluster.name", "images[0]", "istio", "charts[0]", "manifests[0]", "manifests[1]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	if err := testSpec().Validate(); err != nil {
+		t.Errorf("valid spec rejected: %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+// Package bundle packs the configuration of a Kind cluster into one shareable tarball
+// (its Kind config, installed add-ons, loaded images, registry mirrors and the resources
+// of selected namespaces) and reproduces the environment from it on another machine.
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/addons"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/bootstrap"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/registry"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/workloads"
+)
+
+// bundleVersion is the layout version recorded in bundle.json.
+const bundleVersion = 1
+
+// Bundle entries. Only these names are written and extracted.
+const (
+	bundleFile    = "bundle.json"
+	configFile    = "kind-config.yaml"
+	workloadsFile = "workloads.tar.gz"
+)
+
+// Sources of Bundle.ConfigSource.
+const (
+	ConfigStored        = "stored"
+	ConfigReconstructed = "reconstructed"
+)
+
+// preloadedImagePrefixes are the repositories of the images Kind node images ship
+// with; they are not bundled.
+var preloadedImagePrefixes = []string{"registry.k8s.io/", "docker.io/kindest/"}
+
+// Mirror is a containerd registry mirror of the exported cluster.
+type Mirror struct {
+	Registry     string   `json:"registry"`
+	URL          string   `json:"url"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	SkipVerify   bool     `json:"skip_verify,omitempty"`
+	// CA is the CA bundle the mirror was trusted with on the nodes. Neither it nor the
+	// credentials of an Authenticated mirror are bundled, so Import skips such mirrors.
+	CA            string `json:"ca,omitempty"`
+	Authenticated bool   `json:"authenticated,omitempty"`
+}
+
+// Bundle describes a cluster bundle; it is stored as bundle.json.
+type Bundle struct {
+	Version    int       `json:"version"`
+	Cluster    string    `json:"cluster"`
+	ExportedAt time.Time `json:"exported_at"`
+	// ConfigSource tells whether the Kind config is the one the cluster was created
+	// from or was reconstructed from its nodes.
+	ConfigSource string            `json:"config_source"`
+	Addons       []bootstrap.Addon `json:"addons,omitempty"`
+	// Images are the tagged images cached on the nodes, minus those of the node image.
+	Images  []string `json:"images,omitempty"`
+	Mirrors []Mirror `json:"mirrors,omitempty"`
+	// Namespaces are the namespaces whose resources are in workloads.tar.gz.
+	Namespaces []string `json:"namespaces,omitempty"`
+	Resources  int      `json:"resources"`
+	// Tags, DefaultNamespace and KubeContext are the cluster's stored settings.
+	Tags             map[string]string `json:"tags,omitempty"`
+	DefaultNamespace string            `json:"default_namespace,omitempty"`
+	KubeContext      string            `json:"kube_context,omitempty"`
+}
+
+// ExportOptions controls what Export bundles.
+type ExportOptions struct {
+	// Config is the Kind config the cluster was created from. When empty, the config
+	// saved in the manager's config directory is used, or one reconstructed from the nodes.
+	Config string
+	// Namespaces are the namespaces whose resources are exported. None when empty.
+	Namespaces []string
+	// IncludeVolumeData copies the data of the namespaces' local-path volumes.
+	IncludeVolumeData bool
+	Tags              map[string]string
+	DefaultNamespace  string
+	KubeContext       string
+}
+
+// ExportResult reports what Export wrote.
+type ExportResult struct {
+	Path   string `json:"path"`
+	Bundle Bundle `json:"bundle"`
+	// Warnings lists the parts of the cluster that could not be bundled as-is.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Export writes a gzipped tarball at path holding the Kind config of a cluster, the
+// add-ons install_cert_manager, install_gateway_api and install_observability put in
+// it, the images loaded onto its nodes, its registry mirrors and, through
+// workloads.ExportWorkloads, the resources of the selected namespaces.
+func Export(ctx context.Context, mgr *kind.Manager, clusterName, path string, opts ExportOptions) (*ExportResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("output path is required")
+	}
+	path = kind.ExpandHome(path)
+	result := &ExportResult{Path: path, Bundle: Bundle{
+		Version:          bundleVersion,
+		Cluster:          clusterName,
+		ExportedAt:       time.Now().UTC(),
+		ConfigSource:     ConfigStored,
+		Tags:             opts.Tags,
+		DefaultNamespace: opts.DefaultNamespace,
+		KubeContext:      opts.KubeContext,
+	}}
+
+	config := opts.Config
+	if config == "" {
+		config, _ = mgr.StoredConfig(clusterName)
+	}
+	if config == "" {
+		var err error
+		if config, err = mgr.ReconstructConfig(ctx, clusterName); err != nil {
+			return nil, fmt.Errorf("no stored config and reconstruction failed: %w", err)
+		}
+		result.Bundle.ConfigSource = ConfigReconstructed
+		result.Warnings = append(result.Warnings, "no stored Kind config; the bundled config was reconstructed "+
+			"from the nodes and lacks port mappings, mounts and patches")
+	}
+
+	found, err := detectAddons(ctx, mgr, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("detecting add-ons: %w", err)
+	}
+	result.Bundle.Addons = found
+
+	if report, err := mgr.ListNodeImages(ctx, clusterName); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("listing node images: %v", err))
+	} else {
+		result.Bundle.Images = loadedImages(report)
+	}
+
+	if report, err := registry.GetMirrors(ctx, mgr, clusterName); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("reading registry mirrors: %v", err))
+	} else {
+		result.Bundle.Mirrors = bundledMirrors(report.Nodes[0])
+		for _, m := range result.Bundle.Mirrors {
+			if m.Authenticated || m.CA != "" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("the credentials and CA of the %s mirror "+
+					"are not bundled; configure it with configure_registry_mirrors after the import", m.Registry))
+			}
+		}
+	}
+
+	staging, err := os.MkdirTemp("", "kind-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	entries := []string{bundleFile, configFile}
+	if len(opts.Namespaces) > 0 {
+		exported, err := workloads.ExportWorkloads(ctx, mgr, clusterName, filepath.Join(staging, workloadsFile),
+			workloads.ExportOptions{Namespaces: opts.Namespaces, IncludeVolumeData: opts.IncludeVolumeData})
+		if err != nil {
+			return nil, fmt.Errorf("exporting namespaces: %w", err)
+		}
+		result.Bundle.Namespaces = exported.Manifest.Namespaces
+		result.Bundle.Resources = exported.Manifest.Resources
+		result.Warnings = append(result.Warnings, exported.Warnings...)
+		entries = append(entries, workloadsFile)
+	}
+
+	if err := os.WriteFile(filepath.Join(staging, configFile), []byte(config), 0o600); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", configFile, err)
+	}
+	data, err := json.MarshalIndent(result.Bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", bundleFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(staging, bundleFile), data, 0o600); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", bundleFile, err)
+	}
+	if err := writeArchive(staging, entries, path); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// detectAddons finds the add-ons of a cluster by the namespaces and CRDs their
+// installers create, with the versions they were installed at where the cluster
+// records them.
+func detectAddons(ctx context.Context, mgr *kind.Manager, clusterName string) ([]bootstrap.Addon, error) {
+	out, err := kubectl(ctx, mgr, clusterName, "get", "namespaces", "-o", "name")
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make(map[string]bool)
+	for _, name := range strings.Fields(out) {
+		namespaces[strings.TrimPrefix(name, "namespace/")] = true
+	}
+
+	var found []bootstrap.Addon
+	if namespaces["cert-manager"] {
+		a := bootstrap.Addon{Name: bootstrap.AddonCertManager}
+		image, _ := kubectl(ctx, mgr, clusterName, "get", "deployment", "cert-manager", "-n", "cert-manager",
+			"-o", "jsonpath={.spec.template.spec.containers[0].image}")
+		if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+			a.Version = strings.TrimSpace(image[i+1:])
+		}
+		issuer, _ := kubectl(ctx, mgr, clusterName, "get", "clusterissuer", "selfsigned", "--ignore-not-found", "-o", "name")
+		a.CreateSelfSignedIssuer = strings.TrimSpace(issuer) != ""
+		found = append(found, a)
+	}
+
+	annotations, err := kubectl(ctx, mgr, clusterName, "get", "crd", "gateways.gateway.networking.k8s.io",
+		"--ignore-not-found", "-o", "jsonpath={.metadata.annotations}")
+	if err != nil {
+		return nil, err
+	}
+	if annotations = strings.TrimSpace(annotations); annotations != "" {
+		var values map[string]string
+		if err := json.Unmarshal([]byte(annotations), &values); err != nil {
+			return nil, fmt.Errorf("parsing Gateway API CRD annotations: %w", err)
+		}
+		a := bootstrap.Addon{Name: bootstrap.AddonGatewayAPI,
+			Version: values["gateway.networking.k8s.io/bundle-version"],
+			Channel: values["gateway.networking.k8s.io/channel"]}
+		switch {
+		case namespaces["nginx-gateway"]:
+			a.Implementation = addons.GatewayNginxFabric
+		case namespaces["envoy-gateway-system"]:
+			a.Implementation = addons.GatewayEnvoyGateway
+		}
+		found = append(found, a)
+	}
+
+	if namespaces["kubernetes-dashboard"] {
+		found = append(found, bootstrap.Addon{Name: bootstrap.AddonObservability, Stack: addons.ObservabilityDashboard})
+	}
+	if namespaces["monitoring"] {
+		charts, err := kubectl(ctx, mgr, clusterName, "get", "deployments", "-n", "monitoring",
+			"-l", "release=kube-prometheus-stack", "-o", `jsonpath={range .items[*]}{.metadata.labels.helm\.sh/chart}{"\n"}{end}`)
+		if err != nil {
+			return nil, err
+		}
+		for _, chart := range strings.Fields(charts) {
+			if version, ok := strings.CutPrefix(chart, "kube-prometheus-stack-"); ok {
+				found = append(found, bootstrap.Addon{Name: bootstrap.AddonObservability,
+					Stack: addons.ObservabilityPrometheus, ChartVersion: version})
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+// loadedImages returns the first tag of every node image that did not come with the
+// node image, sorted.
+func loadedImages(report *kind.NodeImageReport) []string {
+	var images []string
+	for _, img := range report.Images {
+		if img.Pinned || len(img.Tags) == 0 || hasAnyPrefix(img.Tags[0], preloadedImagePrefixes) {
+			continue
+		}
+		images = append(images, img.Tags[0])
+	}
+	slices.Sort(images)
+	return slices.Compact(images)
+}
+
+// bundledMirrors returns the first mirror of each registry configured on a node, sorted
+// by registry. A node whose containerd ignores certs.d has none in effect.
+func bundledMirrors(nm registry.NodeMirrors) []Mirror {
+	if !nm.ConfigPathActive {
+		return nil
+	}
+	var mirrors []Mirror
+	for reg, live := range nm.Mirrors {
+		if len(live) == 0 {
+			continue
+		}
+		mirrors = append(mirrors, Mirror{Registry: reg, URL: live[0].URL, Capabilities: live[0].Capabilities,
+			SkipVerify: live[0].SkipVerify, CA: live[0].CA, Authenticated: live[0].Authenticated})
+	}
+	slices.SortFunc(mirrors, func(a, b Mirror) int { return strings.Compare(a.Registry, b.Registry) })
+	return mirrors
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// kubectl runs kubectl against the cluster and returns its stdout.
+func kubectl(ctx context.Context, mgr *kind.Manager, clusterName string, args ...string) (string, error) {
+	res, err := mgr.RunKubectl(ctx, clusterName, "", args)
+	if err != nil {
+		return "", err
+	}
+	if res.ExitCode != 0 {
+		return "", fmt.Errorf("kubectl %s exited %d: %s", args[0], res.ExitCode, strings.TrimSpace(res.Stderr))
+	}
+	return res.Stdout, nil
+}
@@ -0,0 +1,167 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/bootstrap"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+const testConfig = "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n- role: control-plane\n"
+
+// fakeCluster answers the kind, runtime and kubectl commands of an export and an
+// import. Everything it does not know succeeds with no output.
+type fakeCluster struct {
+	calls   []string
+	applied []string
+}
+
+func (f *fakeCluster) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, _, err := f.RunSeparate(ctx, name, args...)
+	return out, err
+}
+
+func (f *fakeCluster) RunSeparate(_ context.Context, name string, args ...string) ([]byte, []byte, error) {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	switch name {
+	case "kind":
+		switch {
+		case len(args) == 4 && args[1] == "nodes":
+			return []byte(args[3] + "-control-plane\n"), nil, nil
+		case len(args) == 2 && args[1] == "clusters":
+			return nil, nil, nil
+		}
+		return []byte("apiVersion: v1\n"), nil, nil
+	case "docker":
+		return f.runtime(args)
+	case "kubectl":
+		return f.kubectl(args[2:]) // --kubeconfig <path>
+	}
+	return nil, nil, nil
+}
+
+func (f *fakeCluster) runtime(args []string) ([]byte, []byte, error) {
+	cmd := strings.Join(args, " ")
+	switch {
+	case strings.Contains(cmd, "crictl images"):
+		return []byte(`{"images":[
+			{"id":"sha256:p","repoTags":["registry.k8s.io/pause:3.10"],"size":"300","pinned":true},
+			{"id":"sha256:c","repoTags":["registry.k8s.io/coredns/coredns:v1.11.3"],"size":"200"},
+			{"id":"sha256:a","repoTags":["docker.io/library/app:dev"],"size":"100"},
+			{"id":"sha256:r","repoTags":["docker.io/library/redis:7"],"size":"100"},
+			{"id":"sha256:u","repoDigests":["docker.io/library/busybox@sha256:1"],"size":"10"}]}`), nil, nil
+	case strings.Contains(cmd, "crictl ps"):
+		return []byte(`{"containers":[]}`), nil, nil
+	case strings.Contains(cmd, "containerd config dump"):
+		return []byte("config_path: config_path = \"/etc/containerd/certs.d\"\n" +
+			"### /etc/containerd/certs.d/localhost:5001/hosts.toml\n[host.\"http://kind-registry:5000\"]\n" +
+			"### /etc/containerd/certs.d/registry.corp/hosts.toml\n[host.\"https://cache.corp\"]\n" +
+			"  [host.\"https://cache.corp\".header]\n    Authorization = \"Basic eDp5\"\n"), nil, nil
+	case cmd == "image inspect docker.io/library/redis:7":
+		return nil, nil, fmt.Errorf("exit status 1")
+	}
+	return nil, nil, nil
+}
+
+func (f *fakeCluster) kubectl(args []string) ([]byte, []byte, error) {
+	cmd := strings.Join(args, " ")
+	switch {
+	case cmd == "get namespaces -o name":
+		return []byte("namespace/default\nnamespace/kube-system\nnamespace/shop\n"), nil, nil
+	case strings.HasPrefix(cmd, "get crd gateways.gateway.networking.k8s.io"):
+		return []byte(`{"gateway.networking.k8s.io/bundle-version":"v1.2.1","gateway.networking.k8s.io/channel":"standard"}`), nil, nil
+	case strings.HasPrefix(cmd, "api-resources"):
+		return []byte("configmaps\n"), nil, nil
+	case cmd == "get namespaces -o json":
+		return []byte(`{"items":[{"kind":"Namespace","metadata":{"name":"default"}},
+			{"kind":"Namespace","metadata":{"name":"shop"}}]}`), nil, nil
+	case strings.HasPrefix(cmd, "get configmaps --all-namespaces"):
+		return []byte(`{"items":[{"kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop"}}]}`), nil, nil
+	case strings.HasPrefix(cmd, "apply"):
+		f.applied = append(f.applied, args[len(args)-1])
+	}
+	return nil, nil, nil
+}
+
+func (f *fakeCluster) LookPath(name string) (string, error) {
+	return "/usr/bin/" + name, nil
+}
+
+// called reports whether a call contains substr.
+func (f *fakeCluster) called(substr string) bool {
+	return slices.ContainsFunc(f.calls, func(c string) bool { return strings.Contains(c, substr) })
+}
+
+func newManager(runner *fakeCluster) *kind.Manager {
+	return kind.NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+}
+
+func TestExportImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev.tar.gz")
+	runner := &fakeCluster{}
+	exported, err := Export(context.Background(), newManager(runner), "dev", path, ExportOptions{
+		Config: testConfig, Namespaces: []string{"shop"}, KubeContext: "team-dev",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := exported.Bundle
+	if len(b.Addons) != 1 || b.Addons[0] != (bootstrap.Addon{Name: bootstrap.AddonGatewayAPI, Version: "v1.2.1", Channel: "standard"}) {
+		t.Errorf("addons = %+v", b.Addons)
+	}
+	if strings.Join(b.Images, ",") != "docker.io/library/app:dev,docker.io/library/redis:7" {
+		t.Errorf("images = %v", b.Images)
+	}
+	if len(b.Mirrors) != 2 || b.Mirrors[0].Registry != "localhost:5001" || b.Mirrors[0].URL != "http://kind-registry:5000" ||
+		!b.Mirrors[1].Authenticated || len(exported.Warnings) != 1 {
+		t.Errorf("mirrors = %+v, warnings = %v", b.Mirrors, exported.Warnings)
+	}
+	if strings.Join(b.Namespaces, ",") != "shop" || b.Resources != 1 || b.ConfigSource != ConfigStored {
+		t.Errorf("bundle = %+v", b)
+	}
+
+	read, config, err := Read(path)
+	if err != nil || config != testConfig || read.KubeContext != "team-dev" {
+		t.Fatalf("read = %+v, %q, %v", read, config, err)
+	}
+
+	runner = &fakeCluster{}
+	imported, err := Import(context.Background(), newManager(runner), path, ImportOptions{Name: "copy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Cluster != "copy" || !imported.Bootstrap.Succeeded || len(imported.Bootstrap.Steps) != 3 {
+		t.Fatalf("import = %+v, bootstrap = %+v", imported, imported.Bootstrap)
+	}
+	if !runner.called("kind create cluster --name copy") || !runner.called("save -o") ||
+		!runner.called("gateway-api/releases/download/v1.2.1/standard-install.yaml") {
+		t.Errorf("calls = %v", runner.calls)
+	}
+	if strings.Join(imported.Mirrors, ",") != "localhost:5001" || len(imported.Warnings) != 2 {
+		t.Errorf("mirrors = %v, warnings = %v", imported.Mirrors, imported.Warnings)
+	}
+	if !slices.ContainsFunc(runner.applied, func(a string) bool { return strings.HasSuffix(a, "resources.json") }) {
+		t.Errorf("namespace resources not applied: %v", runner.applied)
+	}
+}
+
+func TestRead_RejectsOtherArchives(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, configFile), []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config-only.tar.gz")
+	if err := writeArchive(dir, []string{configFile}, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Read(path); err == nil || !strings.Contains(err.Error(), "not a cluster bundle") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package bundle
+
+import (
+	"archive/tar"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/bootstrap"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/registry"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/workloads"
+)
+
+// ImportOptions controls how Import reproduces a bundle.
+type ImportOptions struct {
+	// Name is the cluster to create. Default: the exported cluster's name.
+	Name string
+	// SkipImages does not load the bundled images, leaving the nodes to pull them.
+	SkipImages bool
+	// RestoreVolumeData copies bundled volume data into the new claims' volumes.
+	RestoreVolumeData bool
+	// Timeout bounds add-on installs and the wait for restored claims. Default:
+	// bootstrap.DefaultTimeout.
+	Timeout time.Duration
+}
+
+// ImportResult reports each step of an Import.
+type ImportResult struct {
+	Cluster string `json:"cluster"`
+	Bundle  Bundle `json:"bundle"`
+	// Bootstrap is the result of creating the cluster, loading the images and
+	// installing the add-ons.
+	Bootstrap *bootstrap.Result `json:"bootstrap,omitempty"`
+	// Steps are the mirror and namespace steps run in the created cluster.
+	Steps []string `json:"steps,omitempty"`
+	// Mirrors are the registries configured with mirrors.
+	Mirrors  []string `json:"mirrors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Read returns the description and Kind config of a bundle without extracting its
+// namespace resources.
+func Read(path string) (*Bundle, string, error) {
+	f, err := os.Open(kind.ExpandHome(path))
+	if err != nil {
+		return nil, "", fmt.Errorf("opening bundle: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading bundle: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var b *Bundle
+	var config string
+	for b == nil || config == "" {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("reading bundle: %w", err)
+		}
+		switch hdr.Name {
+		case bundleFile:
+			b = &Bundle{}
+			if err := json.NewDecoder(tr).Decode(b); err != nil {
+				return nil, "", fmt.Errorf("parsing %s: %w", bundleFile, err)
+			}
+		case configFile:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, "", fmt.Errorf("reading %s: %w", configFile, err)
+			}
+			config = string(data)
+		}
+	}
+	if b == nil || config == "" {
+		return nil, "", fmt.Errorf("not a cluster bundle: %s or %s is missing", bundleFile, configFile)
+	}
+	if b.Version != bundleVersion {
+		return nil, "", fmt.Errorf("unsupported bundle version %d (expected %d)", b.Version, bundleVersion)
+	}
+	return b, config, nil
+}
+
+// Import reproduces the environment of a bundle written by Export: it creates the
+// cluster from the bundled Kind config, loads the bundled images found in the local
+// container runtime and installs the add-ons (rolling back the new cluster if any of
+// that fails), then configures the registry mirrors and applies the bundled namespaces.
+// A failure in those last steps is returned with the result so far; the cluster is kept.
+func Import(ctx context.Context, mgr *kind.Manager, path string, opts ImportOptions) (*ImportResult, error) {
+	if path == "" {
+		return nil, fmt.Errorf("bundle path is required")
+	}
+	b, config, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = bootstrap.DefaultTimeout
+	}
+	name := cmp.Or(opts.Name, b.Cluster)
+	result := &ImportResult{Cluster: name, Bundle: *b}
+
+	spec := bootstrap.Spec{
+		Cluster:        bootstrap.ClusterSpec{Name: name, ConfigYAML: config},
+		Addons:         b.Addons,
+		TimeoutSeconds: int(opts.Timeout.Seconds()),
+	}
+	if !opts.SkipImages && len(b.Images) > 0 {
+		missing := mgr.MissingImages(ctx, b.Images)
+		spec.Images = slices.DeleteFunc(slices.Clone(b.Images), func(image string) bool {
+			return slices.Contains(missing, image)
+		})
+		if len(missing) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("not loaded, as they are not in the local "+
+				"image store (the nodes pull them on demand): %s", strings.Join(missing, ", ")))
+		}
+	}
+	result.Bootstrap, err = bootstrap.Run(ctx, mgr, spec)
+	if err != nil {
+		return result, err
+	}
+
+	if err := importMirrors(ctx, mgr, name, b.Mirrors, result); err != nil {
+		return result, err
+	}
+
+	if len(b.Namespaces) == 0 {
+		return result, nil
+	}
+	dir, err := os.MkdirTemp("", "kind-bundle-*")
+	if err != nil {
+		return result, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := extractArchive(kind.ExpandHome(path), dir); err != nil {
+		return result, err
+	}
+	imported, err := workloads.ImportWorkloads(ctx, mgr, name, filepath.Join(dir, workloadsFile),
+		workloads.ImportOptions{RestoreVolumeData: opts.RestoreVolumeData, Timeout: opts.Timeout})
+	if imported != nil {
+		result.Steps = append(result.Steps, imported.Steps...)
+		result.Warnings = append(result.Warnings, imported.Warnings...)
+	}
+	if err != nil {
+		return result, fmt.Errorf("importing namespaces: %w", err)
+	}
+	return result, nil
+}
+
+// importMirrors configures the bundled mirrors whose CA and credentials were not left
+// behind, and publishes a local registry among them.
+func importMirrors(ctx context.Context, mgr *kind.Manager, clusterName string, mirrors []Mirror, result *ImportResult) error {
+	var overrides []registry.RegistryOverride
+	for _, m := range mirrors {
+		if m.Authenticated || m.CA != "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("skipped the %s mirror %s: its credentials or CA "+
+				"are not bundled; configure it with configure_registry_mirrors", m.Registry, m.URL))
+			continue
+		}
+		overrides = append(overrides, registry.RegistryOverride{Original: m.Registry, Mirror: m.URL,
+			InsecureSkipVerify: m.SkipVerify, Capabilities: m.Capabilities})
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	mirrorCfg, err := registry.GenerateMirrorConfig(overrides, nil)
+	if err != nil {
+		return fmt.Errorf("generating mirror config: %w", err)
+	}
+	applied, err := registry.ApplyMirrorConfig(ctx, mgr, clusterName, mirrorCfg, registry.ApplyOptions{})
+	if err != nil {
+		return fmt.Errorf("configuring registry mirrors: %w", err)
+	}
+	if applied.Failed {
+		return fmt.Errorf("configuring registry mirrors failed: %s", strings.Join(applied.Results, "; "))
+	}
+	for _, o := range overrides {
+		result.Mirrors = append(result.Mirrors, o.Original)
+	}
+	result.Steps = append(result.Steps, "OK configured mirrors for "+strings.Join(result.Mirrors, ", "))
+	if local, ok := registry.LocalRegistryOverride(overrides); ok {
+		if err := registry.PublishLocalRegistry(ctx, mgr, clusterName, local); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		} else {
+			result.Steps = append(result.Steps, "OK published the local registry "+local.Original)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+// Package containerapi is a minimal client for the Docker Engine API, also served by
+// Podman's Docker-compatible API, over a local unix socket. It covers the calls needed
+// to inspect and exec into Kind node containers without the docker/podman CLI.
+package containerapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrUnavailable wraps errors reaching the API socket, as opposed to errors reported
+// by the API. Callers fall back to the CLI when errors.Is(err, ErrUnavailable).
+var ErrUnavailable = errors.New("container API unavailable")
+
+// Client talks to the Docker Engine API over a unix socket.
+type Client struct {
+	socket string
+	http   *http.Client
+}
+
+// ContainerState is the structured state of a container.
+type ContainerState struct {
+	Status       string    `json:"status"`
+	Running      bool      `json:"running"`
+	Health       string    `json:"health,omitempty"`
+	RestartCount int       `json:"restart_count"`
+	ExitCode     int       `json:"exit_code"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	// Networks are the names of the networks the container is attached to, sorted.
+	Networks []string `json:"networks,omitempty"`
+}
+
+// ExitError reports a command that ran in the container but exited non-zero.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// New returns a Client for the given socket path (with or without a unix:// prefix),
+// or nil if the path is not a local unix socket that exists.
+func New(socket string) *Client {
+	socket = strings.TrimPrefix(socket, "unix://")
+	if socket == "" || strings.Contains(socket, "://") {
+		return nil
+	}
+	if info, err := os.Stat(socket); err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	return &Client{
+		socket: socket,
+		http: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}},
+	}
+}
+
+// Inspect returns the state of a container.
+func (c *Client) Inspect(ctx context.Context, container string) (*ContainerState, error) {
+	var resp struct {
+		RestartCount int
+		State        struct {
+			Status     string
+			Running    bool
+			ExitCode   int
+			StartedAt  time.Time
+			FinishedAt time.Time
+			Health     *struct{ Status string }
+		}
+		NetworkSettings struct {
+			Networks map[string]struct{}
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/containers/"+url.PathEscape(container)+"/json", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	state := &ContainerState{
+		Status:       resp.State.Status,
+		Running:      resp.State.Running,
+		RestartCount: resp.RestartCount,
+		ExitCode:     resp.State.ExitCode,
+		StartedAt:    resp.State.StartedAt,
+		FinishedAt:   resp.State.FinishedAt,
+	}
+	if resp.State.Health != nil {
+		state.Health = resp.State.Health.Status
+	}
+	for name := range resp.NetworkSettings.Networks {
+		state.Networks = append(state.Networks, name)
+	}
+	sort.Strings(state.Networks)
+	return state, nil
+}
+
+// Exec runs cmd in a container and returns its combined stdout and stderr. A non-zero
+// exit status is returned as *ExitError along with the output.
+func (c *Client) Exec(ctx context.Context, container string, cmd []string) ([]byte, error) {
+	var created struct {
+		ID string `json:"Id"`
+	}
+	body := map[string]any{"Cmd": cmd, "AttachStdout": true, "AttachStderr": true}
+	if err := c.do(ctx, http.MethodPost, "/containers/"+url.PathEscape(container)+"/exec", body, &created); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.request(ctx, http.MethodPost, "/exec/"+created.ID+"/start", map[string]any{"Detach": false})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	out, err := demux(resp.Body)
+	if err != nil {
+		return out, fmt.Errorf("reading exec output: %w", err)
+	}
+
+	var inspect struct{ ExitCode int }
+	if err := c.do(ctx, http.MethodGet, "/exec/"+created.ID+"/json", nil, &inspect); err != nil {
+		return out, err
+	}
+	if inspect.ExitCode != 0 {
+		return out, &ExitError{Code: inspect.ExitCode}
+	}
+	return out, nil
+}
+
+// do sends a request and decodes a JSON response into out (if non-nil).
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", path, err)
+	}
+	return nil
+}
+
+// request sends a request and returns the response, converting API error statuses to errors.
+func (c *Client) request(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, "http://container-api"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, path, apiErr.Message)
+	}
+	return resp, nil
+}
+
+// demux reads a multiplexed exec stream (8-byte headers: stream type, 3 zero bytes,
+// big-endian payload size) and returns the stdout and stderr payloads in order. If the
+// stream has no headers (a TTY exec), it is returned as-is.
+func demux(r io.Reader) ([]byte, error) {
+	var out bytes.Buffer
+	header := make([]byte, 8)
+	for {
+		n, err := io.ReadFull(r, header)
+		if err == io.EOF {
+			return out.Bytes(), nil
+		}
+		if err != nil {
+			out.Write(header[:n])
+			if err == io.ErrUnexpectedEOF {
+				return out.Bytes(), nil
+			}
+			return out.Bytes(), err
+		}
+		if header[0] > 2 || header[1] != 0 || header[2] != 0 || header[3] != 0 {
+			// Raw stream.
+			out.Write(header)
+			_, err := io.Copy(&out, r)
+			return out.Bytes(), err
+		}
+		size := binary.BigEndian.Uint32(header[4:])
+		if _, err := io.CopyN(&out, r, int64(size)); err != nil {
+			return out.Bytes(), err
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package containerapi
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// serve starts an HTTP server on a unix socket and returns a Client for it.
+func serve(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "capi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "api.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: handler}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+
+	c := New("unix://" + socket)
+	if c == nil {
+		t.Fatal("New returned nil for a live socket")
+	}
+	return c
+}
+
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func TestInspect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/dev-control-plane/json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"RestartCount":2,"State":{"Status":"running","Running":true,
+			"StartedAt":"2024-05-01T10:00:00Z","Health":{"Status":"healthy"}},
+			"NetworkSettings":{"Networks":{"kind":{"IPAddress":"172.18.0.2"},"bridge":{}}}}`))
+	})
+	c := serve(t, mux)
+
+	state, err := c.Inspect(context.Background(), "dev-control-plane")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !state.Running || state.Status != "running" || state.RestartCount != 2 || state.Health != "healthy" {
+		t.Errorf("state = %+v", state)
+	}
+	if len(state.Networks) != 2 || state.Networks[0] != "bridge" || state.Networks[1] != "kind" {
+		t.Errorf("Networks = %v", state.Networks)
+	}
+	if state.StartedAt.Year() != 2024 {
+		t.Errorf("StartedAt = %v", state.StartedAt)
+	}
+}
+
+func TestInspect_NotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"No such container: missing"}`))
+	})
+	c := serve(t, mux)
+
+	_, err := c.Inspect(context.Background(), "missing")
+	if err == nil || errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected API error, got %v", err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	exitCode := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /containers/node/exec", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"e1"}`))
+	})
+	mux.HandleFunc("POST /exec/e1/start", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(frame(1, "hello\n"))
+		w.Write(frame(2, "warn\n"))
+	})
+	mux.HandleFunc("GET /exec/e1/json", func(w http.ResponseWriter, _ *http.Request) {
+		if exitCode != 0 {
+			w.Write([]byte(`{"ExitCode":3}`))
+			return
+		}
+		w.Write([]byte(`{"ExitCode":0}`))
+	})
+	c := serve(t, mux)
+
+	out, err := c.Exec(context.Background(), "node", []string{"echo", "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "hello\nwarn\n" {
+		t.Errorf("output = %q", out)
+	}
+
+	exitCode = 3
+	_, err = c.Exec(context.Background(), "node", []string{"false"})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Errorf("expected ExitError 3, got %v", err)
+	}
+}
+
+func TestNew_InvalidSocket(t *testing.T) {
+	for _, s := range []string{"", "tcp://1.2.3.4:2375", filepath.Join(t.TempDir(), "missing.sock")} {
+		if New(s) != nil {
+			t.Errorf("New(%q) should return nil", s)
+		}
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	c := serve(t, http.NewServeMux())
+	os.Remove(c.socket)
+	_, err := c.Inspect(context.Background(), "x")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package kind
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// APIEndpoint is where a cluster's API server can be reached and the CA that signs its
+// certificate, without any credentials.
+type APIEndpoint struct {
+	Cluster string `json:"cluster"`
+	// Node is the container publishing the API server: the external load balancer of
+	// an HA cluster, otherwise the control-plane node.
+	Node             string `json:"node"`
+	HighAvailability bool   `json:"high_availability"`
+	// Server is the host-reachable URL, as in the kubeconfig from get_kubeconfig.
+	Server string `json:"server"`
+	Host   string `json:"host"`
+	Port   int    `json:"port"`
+	// Published is the address the node container publishes port 6443 on, e.g.
+	// "127.0.0.1:41234", or "0.0.0.0:6443" when bound to every interface.
+	Published string `json:"published,omitempty"`
+	// InternalServer is the URL containers on the kind network use.
+	InternalServer string `json:"internal_server"`
+	// CACertificate is the cluster CA in PEM form, for TLS verification.
+	CACertificate       string     `json:"ca_certificate"`
+	CASubject           string     `json:"ca_subject,omitempty"`
+	CAFingerprintSHA256 string     `json:"ca_fingerprint_sha256,omitempty"`
+	CANotAfter          *time.Time `json:"ca_not_after,omitempty"`
+}
+
+// APIEndpoint returns the host and internal API server endpoints of a cluster and its
+// CA certificate. Only the server and CA of the kubeconfig are used; its client
+// credentials are not returned.
+func (m *Manager) APIEndpoint(ctx context.Context, clusterName string) (*APIEndpoint, error) {
+	nodes, err := m.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q %w", clusterName, ErrClusterNotFound)
+	}
+	ep := &APIEndpoint{Cluster: clusterName, Node: clusterName + "-" + RoleControlPlane}
+	for _, n := range nodes {
+		if NodeRole(n) == RoleExternalLoadBalancer {
+			ep.Node, ep.HighAvailability = n, true
+		}
+	}
+	ep.Published = m.loadBalancerPort(ctx, ep.Node)
+
+	kubeconfig, err := m.GetKubeconfig(ctx, clusterName, false)
+	if err != nil {
+		return nil, err
+	}
+	ep.Server = kubeconfigServer(kubeconfig)
+	u, err := url.Parse(ep.Server)
+	if err != nil || u.Port() == "" {
+		return nil, fmt.Errorf("unexpected kubeconfig server %q", ep.Server)
+	}
+	ep.Host = u.Hostname()
+	ep.Port, _ = strconv.Atoi(u.Port())
+
+	internal, err := m.GetKubeconfig(ctx, clusterName, true)
+	if err != nil {
+		return nil, err
+	}
+	ep.InternalServer = kubeconfigServer(internal)
+	if ep.InternalServer == "" {
+		ep.InternalServer = "https://" + net.JoinHostPort(ep.Node, "6443")
+	}
+
+	if err := ep.setCA(kubeconfig); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+// setCA fills in the CA fields from the certificate-authority-data of a kubeconfig's
+// first cluster.
+func (ep *APIEndpoint) setCA(kubeconfig string) error {
+	var cfg struct {
+		Clusters []struct {
+			Cluster struct {
+				CAData string `yaml:"certificate-authority-data"`
+			} `yaml:"cluster"`
+		} `yaml:"clusters"`
+	}
+	if err := yaml.Unmarshal([]byte(kubeconfig), &cfg); err != nil || len(cfg.Clusters) == 0 {
+		return fmt.Errorf("kubeconfig has no cluster entry")
+	}
+	data, err := base64.StdEncoding.DecodeString(cfg.Clusters[0].Cluster.CAData)
+	if err != nil || len(data) == 0 {
+		return fmt.Errorf("kubeconfig has no valid certificate-authority-data")
+	}
+	ep.CACertificate = string(data)
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	sum := sha256.Sum256(cert.Raw)
+	ep.CASubject = cert.Subject.String()
+	ep.CAFingerprintSHA256 = hex.EncodeToString(sum[:])
+	ep.CANotAfter = &cert.NotAfter
+	return nil
+}
@@ -0,0 +1,95 @@
+package kind
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCAPEM returns a self-signed CA certificate in PEM form.
+func testCAPEM(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func endpointKubeconfig(server, ca string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: %s
+    server: %s
+  name: kind-ha
+users:
+- name: kind-ha
+  user:
+    client-key-data: c2VjcmV0
+`, base64.StdEncoding.EncodeToString([]byte(ca)), server))
+}
+
+func TestAPIEndpoint_HA(t *testing.T) {
+	ca := testCAPEM(t)
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"},
+			out: []byte("ha-control-plane\nha-control-plane2\nha-external-load-balancer\nha-worker\n")},
+		{name: "docker", args: []string{"port", "ha-external-load-balancer", "6443/tcp"}, out: []byte("127.0.0.1:41234\n")},
+		{name: "kind", args: []string{"get", "kubeconfig", "--name", "ha", "--internal"},
+			out: endpointKubeconfig("https://ha-external-load-balancer:6443", ca)},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: endpointKubeconfig("https://127.0.0.1:41234", ca)},
+	}}
+
+	ep, err := newDockerManager(runner).APIEndpoint(context.Background(), "ha")
+	if err != nil {
+		t.Fatalf("APIEndpoint: %v", err)
+	}
+	if !ep.HighAvailability || ep.Node != "ha-external-load-balancer" || ep.Published != "127.0.0.1:41234" {
+		t.Errorf("endpoint = %+v", ep)
+	}
+	if ep.Server != "https://127.0.0.1:41234" || ep.Host != "127.0.0.1" || ep.Port != 41234 {
+		t.Errorf("host endpoint = %s (%s, %d)", ep.Server, ep.Host, ep.Port)
+	}
+	if ep.InternalServer != "https://ha-external-load-balancer:6443" {
+		t.Errorf("InternalServer = %q", ep.InternalServer)
+	}
+	if ep.CACertificate != ca || ep.CASubject != "CN=kubernetes" || len(ep.CAFingerprintSHA256) != 64 || ep.CANotAfter == nil {
+		t.Errorf("CA fields = %q, %q, %v", ep.CASubject, ep.CAFingerprintSHA256, ep.CANotAfter)
+	}
+	if strings.Contains(ep.CACertificate, "c2VjcmV0") {
+		t.Error("client credentials must not be returned")
+	}
+}
+
+func TestAPIEndpoint_NotFound(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("No kind nodes found for cluster \"gone\".\n")},
+	}}
+	if _, err := newDockerManager(runner).APIEndpoint(context.Background(), "gone"); err == nil {
+		t.Error("expected an error for a cluster without nodes")
+	}
+}
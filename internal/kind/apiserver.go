@@ -0,0 +1,109 @@
+package kind
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeadmAPIVersions are the kubeadm config versions Kind generates, depending on the
+// Kubernetes version. A patch with an apiVersion only applies to that version, and
+// v1beta4 turned extraArgs from a map into a list, so API server patches come in one
+// form per version.
+var kubeadmAPIVersions = []string{"kubeadm.k8s.io/v1beta3", "kubeadm.k8s.io/v1beta4"}
+
+// hostnamePattern matches DNS names, optionally with a leading wildcard label.
+var hostnamePattern = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// validateNodePortRange checks a kube-apiserver --service-node-port-range value such
+// as "30000-32767".
+func validateNodePortRange(r string) error {
+	lo, hi, ok := strings.Cut(r, "-")
+	low, err1 := strconv.Atoi(strings.TrimSpace(lo))
+	high, err2 := strconv.Atoi(strings.TrimSpace(hi))
+	if !ok || err1 != nil || err2 != nil {
+		return fmt.Errorf("invalid node port range %q; use 'first-last', e.g. '30000-32767'", r)
+	}
+	if low < 1 || high > 65535 || low > high {
+		return fmt.Errorf("invalid node port range %q; ports must satisfy 1 <= first <= last <= 65535", r)
+	}
+	return nil
+}
+
+// validateCertSAN checks that a SAN is an IP address or a DNS name.
+func validateCertSAN(san string) error {
+	if net.ParseIP(san) != nil || len(san) <= 253 && hostnamePattern.MatchString(san) {
+		return nil
+	}
+	return fmt.Errorf("invalid API server certificate SAN %q; must be a hostname or IP address", san)
+}
+
+// apiServerPatches returns the kubeadm ClusterConfiguration patches that set the
+// API server's NodePort range and extra certificate SANs and the API server and
+// scheduler flags of opts.Tuning, one per kubeadm version, or nil if none is set.
+// Kind merges patches by replacing lists, so everything goes into one patch.
+func apiServerPatches(opts ConfigOptions) ([]string, error) {
+	nodePortRange, certSANs, t := opts.NodePortRange, opts.APIServerCertSANs, opts.Tuning
+	if nodePortRange != "" {
+		if err := validateNodePortRange(nodePortRange); err != nil {
+			return nil, err
+		}
+	}
+	for _, san := range certSANs {
+		if err := validateCertSAN(san); err != nil {
+			return nil, err
+		}
+	}
+
+	var apiArgs, schedulerArgs []kubeadmArg
+	if nodePortRange != "" {
+		apiArgs = append(apiArgs, kubeadmArg{"service-node-port-range", nodePortRange})
+	}
+	apiArgs = append(apiArgs, t.apiServerArgs()...)
+	apiVolumes := t.apiServerVolumes()
+	var schedulerVolumes []map[string]any
+	if len(t.SchedulerProfiles) > 0 {
+		schedulerArgs = append(schedulerArgs, kubeadmArg{"config", tuningNodeDir + "/" + schedulerConfigFile})
+		schedulerVolumes = append(schedulerVolumes, tuningFileVolume("scheduler-config", schedulerConfigFile))
+	}
+	if len(certSANs) == 0 && len(apiArgs) == 0 && len(schedulerArgs) == 0 {
+		return nil, nil
+	}
+
+	var patches []string
+	for _, version := range kubeadmAPIVersions {
+		patch := map[string]any{
+			"apiVersion": version,
+			"kind":       "ClusterConfiguration",
+		}
+		apiServer := map[string]any{}
+		if len(certSANs) > 0 {
+			apiServer["certSANs"] = certSANs
+		}
+		if len(apiArgs) > 0 {
+			apiServer["extraArgs"] = kubeadmArgs(version, apiArgs)
+		}
+		if len(apiVolumes) > 0 {
+			apiServer["extraVolumes"] = apiVolumes
+		}
+		if len(apiServer) > 0 {
+			patch["apiServer"] = apiServer
+		}
+		if len(schedulerArgs) > 0 {
+			patch["scheduler"] = map[string]any{
+				"extraArgs":    kubeadmArgs(version, schedulerArgs),
+				"extraVolumes": schedulerVolumes,
+			}
+		}
+		data, err := yaml.Marshal(patch)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling kubeadm patch: %w", err)
+		}
+		patches = append(patches, string(data))
+	}
+	return patches, nil
+}
@@ -0,0 +1,65 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateConfig_APIServerPatches(t *testing.T) {
+	out, err := GenerateConfig(ConfigOptions{
+		ClusterName:       "exposed",
+		NodePortRange:     "30000-40000",
+		APIServerCertSANs: []string{"kind.lan", "192.168.1.20"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(cfg.KubeadmConfigPatches) != 2 {
+		t.Fatalf("expected a patch per kubeadm version, got %d:\n%s", len(cfg.KubeadmConfigPatches), out)
+	}
+
+	var v1beta3, v1beta4 struct {
+		APIVersion string `yaml:"apiVersion"`
+		APIServer  struct {
+			CertSANs  []string  `yaml:"certSANs"`
+			ExtraArgs yaml.Node `yaml:"extraArgs"`
+		} `yaml:"apiServer"`
+	}
+	yaml.Unmarshal([]byte(cfg.KubeadmConfigPatches[0]), &v1beta3)
+	yaml.Unmarshal([]byte(cfg.KubeadmConfigPatches[1]), &v1beta4)
+	if v1beta3.APIVersion != "kubeadm.k8s.io/v1beta3" || v1beta3.APIServer.ExtraArgs.Kind != yaml.MappingNode {
+		t.Errorf("v1beta3 patch should use an extraArgs map:\n%s", cfg.KubeadmConfigPatches[0])
+	}
+	if v1beta4.APIVersion != "kubeadm.k8s.io/v1beta4" || v1beta4.APIServer.ExtraArgs.Kind != yaml.SequenceNode {
+		t.Errorf("v1beta4 patch should use an extraArgs list:\n%s", cfg.KubeadmConfigPatches[1])
+	}
+	if strings.Join(v1beta4.APIServer.CertSANs, ",") != "kind.lan,192.168.1.20" {
+		t.Errorf("certSANs = %v", v1beta4.APIServer.CertSANs)
+	}
+	if !strings.Contains(cfg.KubeadmConfigPatches[1], "value: 30000-40000") {
+		t.Errorf("node port range missing:\n%s", cfg.KubeadmConfigPatches[1])
+	}
+}
+
+func TestGenerateConfig_InvalidAPIServerOptions(t *testing.T) {
+	for _, opts := range []ConfigOptions{
+		{ClusterName: "x", NodePortRange: "32767"},
+		{ClusterName: "x", NodePortRange: "40000-30000"},
+		{ClusterName: "x", NodePortRange: "0-100"},
+		{ClusterName: "x", APIServerCertSANs: []string{"not a host"}},
+	} {
+		if _, err := GenerateConfig(opts); err == nil {
+			t.Errorf("expected error for %+v", opts)
+		}
+	}
+	if _, err := GenerateConfig(ConfigOptions{ClusterName: "x", APIServerCertSANs: []string{"*.kind.lan", "::1"}}); err != nil {
+		t.Errorf("wildcard and IPv6 SANs should be valid: %v", err)
+	}
+}
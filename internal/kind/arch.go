@@ -0,0 +1,101 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// hostArch returns the architecture node containers run natively on: the container
+// engine's, falling back to this binary's.
+func (m *Manager) hostArch() string {
+	if m.runtime.Arch != "" {
+		return m.runtime.Arch
+	}
+	return m.runtime.OS.Arch
+}
+
+// archCheck reports the host and engine architectures, warning when they differ,
+// which means either this server or the engine runs under emulation.
+func (m *Manager) archCheck() PreflightCheck {
+	check := PreflightCheck{Name: "host-arch", Status: CheckPass}
+	engine, host := m.runtime.Arch, m.runtime.OS.Arch
+	switch {
+	case engine == "":
+		check.Message = fmt.Sprintf("host architecture %s; container engine architecture unknown", host)
+	case engine != host:
+		check.Status = CheckWarn
+		check.Message = fmt.Sprintf("this server runs as %s but the container engine is %s; one of them is emulated "+
+			"(e.g. Rosetta), and node images are pulled for %s", host, engine, engine)
+	default:
+		check.Message = fmt.Sprintf("host and container engine architecture %s", engine)
+	}
+	return check
+}
+
+// CheckNodeImageArch verifies that every node image pinned in the config provides the
+// engine's architecture, using the local image when present and the registry manifest
+// otherwise. An image without it would run under emulation, which makes clusters very
+// slow or unstable. Nodes without an image use Kind's default, which is multi-arch.
+func (m *Manager) CheckNodeImageArch(ctx context.Context, configYAML string) PreflightCheck {
+	check := PreflightCheck{Name: "node-image-arch", Status: CheckPass}
+	arch := m.hostArch()
+
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		check.Status, check.Message = CheckWarn, fmt.Sprintf("invalid YAML: %v", err)
+		return check
+	}
+
+	var emulated, unknown []string
+	seen := make(map[string]bool)
+	for _, node := range cfg.Nodes {
+		if node.Image == "" || seen[node.Image] {
+			continue
+		}
+		seen[node.Image] = true
+		archs := m.imageArchitectures(ctx, node.Image)
+		switch {
+		case len(archs) == 0:
+			unknown = append(unknown, node.Image)
+		case !contains(archs, arch):
+			emulated = append(emulated, fmt.Sprintf("%s (%s)", node.Image, strings.Join(archs, ", ")))
+		}
+	}
+
+	switch {
+	case len(emulated) > 0:
+		check.Status = CheckWarn
+		check.Message = fmt.Sprintf("node images that do not provide %s would run under emulation and be very slow: %s; "+
+			"use a multi-arch kindest/node image or one built for %s", arch, strings.Join(emulated, "; "), arch)
+	case len(unknown) > 0:
+		check.Message = fmt.Sprintf("could not determine the architecture of: %s", strings.Join(unknown, ", "))
+	case len(seen) == 0:
+		check.Message = "no pinned node images; Kind's default image is multi-arch"
+	default:
+		check.Message = fmt.Sprintf("all pinned node images provide %s", arch)
+	}
+	return check
+}
+
+// imageArchitectures returns the Linux architectures an image provides, or nil if
+// they cannot be determined.
+func (m *Manager) imageArchitectures(ctx context.Context, image string) []string {
+	if out, err := m.runner.Run(ctx, m.runtimeBin(), "image", "inspect", "--format", "{{.Architecture}}", image); err == nil {
+		if arch := strings.TrimSpace(string(out)); arch != "" {
+			return []string{rtdetect.NormalizeArch(arch)}
+		}
+	}
+
+	platforms, _ := m.manifestPlatforms(ctx, image)
+	var archs []string
+	for _, p := range platforms {
+		if p.OS == "linux" && !contains(archs, p.Architecture) {
+			archs = append(archs, p.Architecture)
+		}
+	}
+	return archs
+}
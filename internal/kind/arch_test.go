@@ -0,0 +1,71 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+const archConfig = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+  - role: control-plane
+    image: kindest/node:v1.31.0
+  - role: worker
+    image: registry.corp/kind/node:v1.31.0
+`
+
+func newArchManager(runner *mockRunner, engineArch string) *Manager {
+	return NewManager(runner, rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker,
+		Arch:    engineArch,
+		OS:      rtdetect.OSInfo{OS: "darwin", Arch: "arm64"},
+	}, nil)
+}
+
+func TestCheckNodeImageArch_Emulated(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		// The first image is local and native; the second only exists as an amd64 manifest list.
+		{name: "docker", args: []string{"image", "inspect", "--format", "{{.Architecture}}", "kindest/node:v1.31.0"},
+			out: []byte("arm64\n")},
+		{name: "docker", args: []string{"image", "inspect"}, err: fmt.Errorf("no such image")},
+		{name: "docker", args: []string{"manifest", "inspect", "registry.corp/kind/node:v1.31.0"},
+			out: []byte(`{"manifests":[{"platform":{"architecture":"amd64","os":"linux"}},
+				{"platform":{"architecture":"unknown","os":"unknown"}}]}`)},
+	}}
+
+	check := newArchManager(runner, "arm64").CheckNodeImageArch(context.Background(), archConfig)
+
+	if check.Status != CheckWarn {
+		t.Fatalf("status = %q, message = %s", check.Status, check.Message)
+	}
+	if !strings.Contains(check.Message, "registry.corp/kind/node:v1.31.0 (amd64)") ||
+		strings.Contains(check.Message, "kindest/node:v1.31.0") {
+		t.Errorf("message = %s", check.Message)
+	}
+}
+
+func TestCheckNodeImageArch_Native(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"image", "inspect"}, err: fmt.Errorf("no such image")},
+		{name: "docker", args: []string{"manifest", "inspect"},
+			out: []byte(`{"manifests":[{"platform":{"architecture":"amd64","os":"linux"}},
+				{"platform":{"architecture":"arm64","os":"linux"}}]}`)},
+	}}
+
+	check := newArchManager(runner, "arm64").CheckNodeImageArch(context.Background(), archConfig)
+
+	if check.Status != CheckPass {
+		t.Errorf("status = %q, message = %s", check.Status, check.Message)
+	}
+}
+
+func TestArchCheck_Mismatch(t *testing.T) {
+	m := newArchManager(&mockRunner{}, "amd64")
+	if check := m.archCheck(); check.Status != CheckWarn || !strings.Contains(check.Message, "emulated") {
+		t.Errorf("check = %+v", check)
+	}
+}
@@ -0,0 +1,194 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// buildOutputLines is how many trailing lines of build output BuildAndLoad keeps.
+const buildOutputLines = 40
+
+// BuildOptions describes an image build for BuildAndLoad.
+type BuildOptions struct {
+	// ContextDir is the build context directory.
+	ContextDir string
+	// Dockerfile defaults to <ContextDir>/Dockerfile.
+	Dockerfile string
+	Tag        string
+	BuildArgs  map[string]string
+	// Restart rolls out every Deployment whose containers use Tag once it is loaded.
+	Restart bool
+}
+
+// BuildResult is the outcome of BuildAndLoad.
+type BuildResult struct {
+	Image string `json:"image"`
+	// BuildOutput is the tail of the build log.
+	BuildOutput string   `json:"build_output"`
+	Loaded      bool     `json:"loaded"`
+	Restarted   []string `json:"restarted,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// BuildAndLoad builds an image with the container runtime, loads it onto the nodes of
+// a Kind cluster, and optionally restarts the Deployments that use it.
+func (m *Manager) BuildAndLoad(ctx context.Context, clusterName string, opts BuildOptions) (*BuildResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if opts.Tag == "" {
+		return nil, fmt.Errorf("image tag is required")
+	}
+	contextDir := ExpandHome(opts.ContextDir)
+	if info, err := os.Stat(contextDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("build context %q is not a directory", opts.ContextDir)
+	}
+
+	result := &BuildResult{Image: opts.Tag}
+	if tag := imageTag(opts.Tag); tag == "" || tag == "latest" {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("image %q uses the latest tag, so pods default to "+
+			"imagePullPolicy Always and will try to pull it from a registry; use a specific tag", opts.Tag))
+	}
+
+	args := []string{"build", "-t", opts.Tag}
+	if opts.Dockerfile != "" {
+		args = append(args, "-f", ExpandHome(opts.Dockerfile))
+	}
+	keys := make([]string, 0, len(opts.BuildArgs))
+	for k := range opts.BuildArgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--build-arg", k+"="+opts.BuildArgs[k])
+	}
+	args = append(args, contextDir)
+
+	m.logger.Info("building image", "tag", opts.Tag, "context", contextDir)
+	out, err := m.runStreaming(ctx, m.runtimeBin(), args...)
+	result.BuildOutput = tailLines(string(out), buildOutputLines)
+	if err != nil {
+		return result, fmt.Errorf("%s build failed: %w\nOutput: %s", m.runtimeBin(), err, result.BuildOutput)
+	}
+
+	if err := m.LoadImage(ctx, clusterName, opts.Tag); err != nil {
+		return result, err
+	}
+	result.Loaded = true
+
+	if opts.Restart {
+		restarted, warnings, err := m.restartDeploymentsUsing(ctx, clusterName, opts.Tag)
+		result.Restarted = restarted
+		result.Warnings = append(result.Warnings, warnings...)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// LoadImage copies an image from the local runtime onto every node of a Kind cluster.
+// It goes through an image archive so that it works with Podman and the kind library.
+func (m *Manager) LoadImage(ctx context.Context, clusterName, image string) error {
+	dir, err := os.MkdirTemp("", "kind-load-*")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := filepath.Join(dir, "image.tar")
+	if _, err := m.SaveImages(ctx, []string{image}, archive); err != nil {
+		return err
+	}
+	_, err = m.loadArchive(ctx, clusterName, archive)
+	return err
+}
+
+// restartDeploymentsUsing restarts the Deployments with a container running image and
+// returns them as namespace/name.
+func (m *Manager) restartDeploymentsUsing(ctx context.Context, clusterName, image string) ([]string, []string, error) {
+	out, err := m.Kubectl(ctx, clusterName, "get", "deployments", "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, nil, err
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				Template struct {
+					Spec struct {
+						Containers []struct {
+							Name            string `json:"name"`
+							Image           string `json:"image"`
+							ImagePullPolicy string `json:"imagePullPolicy"`
+						} `json:"containers"`
+					} `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return nil, nil, fmt.Errorf("parsing deployments: %w", err)
+	}
+
+	var restarted, warnings []string
+	want := normalizeImageRef(image)
+	for _, d := range list.Items {
+		uses := false
+		for _, c := range d.Spec.Template.Spec.Containers {
+			if normalizeImageRef(c.Image) != want {
+				continue
+			}
+			uses = true
+			if c.ImagePullPolicy == "Always" {
+				warnings = append(warnings, fmt.Sprintf("container %s in %s/%s has imagePullPolicy Always and will "+
+					"not use the loaded image; set it to IfNotPresent", c.Name, d.Metadata.Namespace, d.Metadata.Name))
+			}
+		}
+		if !uses {
+			continue
+		}
+		target := RolloutTarget{Kind: WorkloadDeployment, Name: d.Metadata.Name, Namespace: d.Metadata.Namespace}
+		if err := m.RolloutRestart(ctx, clusterName, target); err != nil {
+			return restarted, warnings, err
+		}
+		restarted = append(restarted, d.Metadata.Namespace+"/"+d.Metadata.Name)
+	}
+	if len(restarted) == 0 {
+		warnings = append(warnings, fmt.Sprintf("no Deployments use %s; nothing was restarted", image))
+	}
+	return restarted, warnings, nil
+}
+
+// normalizeImageRef expands Docker Hub short names so that "app:dev" and
+// "docker.io/library/app:dev" compare equal.
+func normalizeImageRef(image string) string {
+	name := image
+	if first, _, ok := strings.Cut(image, "/"); !ok || !strings.ContainsAny(first, ".:") && first != "localhost" {
+		if !ok {
+			name = "library/" + name
+		}
+		name = "docker.io/" + name
+	}
+	if imageTag(name) == "" && !strings.Contains(name, "@") {
+		name += ":latest"
+	}
+	return name
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,85 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestBuildAndLoad(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"build"}, out: []byte("step 1\nstep 2\nSuccessfully tagged shop/web:dev\n")},
+		{name: "docker", args: []string{"save"}},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+		{name: "kind", args: []string{"load", "image-archive"}},
+		{name: "kubectl", args: []string{"*", "*", "get", "deployments"}, out: []byte(`{"items":[
+			{"metadata":{"name":"web","namespace":"shop"},"spec":{"template":{"spec":{"containers":[
+				{"name":"web","image":"docker.io/shop/web:dev","imagePullPolicy":"IfNotPresent"}]}}}},
+			{"metadata":{"name":"db","namespace":"shop"},"spec":{"template":{"spec":{"containers":[
+				{"name":"db","image":"postgres:16"}]}}}}]}`)},
+	}}}
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	client, _ := fakeClusters(t, m, object("apps/v1", "Deployment", "shop", "web", nil))
+
+	result, err := m.BuildAndLoad(context.Background(), "dev", BuildOptions{
+		ContextDir: t.TempDir(),
+		Tag:        "shop/web:dev",
+		BuildArgs:  map[string]string{"VERSION": "1.2.3", "COMMIT": "abc"},
+		Restart:    true,
+	})
+	if err != nil {
+		t.Fatalf("BuildAndLoad: %v", err)
+	}
+	if !result.Loaded || len(result.Restarted) != 1 || result.Restarted[0] != "shop/web" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("warnings = %v", result.Warnings)
+	}
+
+	calls := strings.Join(runner.calls, "\n")
+	if !strings.Contains(calls, "--build-arg COMMIT=abc --build-arg VERSION=1.2.3") {
+		t.Errorf("build args not passed in order:\n%s", calls)
+	}
+	web, _ := client.Resource(deploymentsResource).Namespace("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	if restartedAt, _, _ := unstructured.NestedString(web.Object, "spec", "template", "metadata", "annotations",
+		restartedAtAnnotation); restartedAt == "" {
+		t.Errorf("expected web to be restarted: %v", web.Object)
+	}
+}
+
+func TestBuildAndLoad_LatestTagWarning(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"build"}},
+		{name: "docker", args: []string{"save"}},
+		{name: "kind", args: []string{"load", "image-archive"}},
+	}}
+
+	result, err := newDockerManager(runner).BuildAndLoad(context.Background(), "dev",
+		BuildOptions{ContextDir: t.TempDir(), Tag: "web"})
+	if err != nil {
+		t.Fatalf("BuildAndLoad: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "latest") {
+		t.Errorf("warnings = %v", result.Warnings)
+	}
+}
+
+func TestNormalizeImageRef(t *testing.T) {
+	tests := map[string]string{
+		"web":                        "docker.io/library/web:latest",
+		"shop/web:dev":               "docker.io/shop/web:dev",
+		"registry.corp:5000/web:dev": "registry.corp:5000/web:dev",
+		"localhost/web:dev":          "localhost/web:dev",
+	}
+	for in, want := range tests {
+		if got := normalizeImageRef(in); got != want {
+			t.Errorf("normalizeImageRef(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
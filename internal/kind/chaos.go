@@ -0,0 +1,210 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// NodeFault reports a failure induced on a single node, or its removal.
+type NodeFault struct {
+	Cluster string `json:"cluster"`
+	NodeAction
+	Disconnected bool `json:"disconnected"`
+	// Addresses are the node's addresses on the kind network when it was disconnected;
+	// RestoreNode reconnects it with them so certificates and kubelet config stay valid.
+	Addresses []string `json:"addresses,omitempty"`
+	// Steps lists the runtime commands that were run.
+	Steps   []string `json:"steps,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// KillNode sends signal (default SIGKILL) to a node container, as if the machine lost
+// power. node is the container name or its suffix after "<cluster>-" (e.g. "worker2").
+func (m *Manager) KillNode(ctx context.Context, cluster, node, signal string) (*NodeFault, error) {
+	nodes, node, err := m.clusterNode(ctx, cluster, node)
+	if err != nil {
+		return nil, err
+	}
+	if signal == "" {
+		signal = "SIGKILL"
+	}
+
+	fault := &NodeFault{Cluster: cluster, NodeAction: NodeAction{Name: node, Role: NodeRole(node)}}
+	if err := m.faultStep(ctx, fault, "kill", "--signal", signal, node); err != nil {
+		return nil, err
+	}
+	m.refreshFault(ctx, fault)
+	fault.Message = faultImpact(nodes, node, "killed")
+	return fault, nil
+}
+
+// DisconnectNode detaches a node container from the kind network, partitioning it from
+// the rest of the cluster while it keeps running. Its addresses are returned so that
+// RestoreNode can reconnect it with the same ones.
+func (m *Manager) DisconnectNode(ctx context.Context, cluster, node string) (*NodeFault, error) {
+	nodes, node, err := m.clusterNode(ctx, cluster, node)
+	if err != nil {
+		return nil, err
+	}
+
+	fault := &NodeFault{Cluster: cluster, NodeAction: NodeAction{Name: node, Role: NodeRole(node)}}
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "inspect", "--format",
+		fmt.Sprintf(`{{with index .NetworkSettings.Networks %q}}{{.IPAddress}}|{{.GlobalIPv6Address}}{{end}}`, kindNetwork), node)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting node %s: %v: %s", node, err, strings.TrimSpace(string(out)))
+	}
+	addrs := strings.TrimSpace(string(out))
+	if addrs == "" {
+		return nil, fmt.Errorf("node %s is not connected to the %s network", node, kindNetwork)
+	}
+	for _, addr := range strings.Split(addrs, "|") {
+		if addr != "" {
+			fault.Addresses = append(fault.Addresses, addr)
+		}
+	}
+
+	if err := m.faultStep(ctx, fault, "network", "disconnect", kindNetwork, node); err != nil {
+		return nil, err
+	}
+	m.refreshFault(ctx, fault)
+	fault.Message = faultImpact(nodes, node, "disconnected")
+	return fault, nil
+}
+
+// RestoreNode undoes KillNode, DisconnectNode and pausing: it reconnects the node to the
+// kind network (with addresses, when known), starts or unpauses its container and, if
+// timeout is positive, waits for the API server and reports the node's Ready condition.
+func (m *Manager) RestoreNode(ctx context.Context, cluster, node string, addresses []string, timeout time.Duration) (*NodeFault, error) {
+	nodes, node, err := m.clusterNode(ctx, cluster, node)
+	if err != nil {
+		return nil, err
+	}
+
+	fault := &NodeFault{Cluster: cluster, NodeAction: NodeAction{Name: node, Role: NodeRole(node)}}
+	state, ok := m.inspectNodesCLI(ctx, []string{node})[node]
+	if !ok {
+		return nil, fmt.Errorf("inspecting node %s failed", node)
+	}
+
+	if state.networks != nil && !slices.Contains(state.networks, kindNetwork) {
+		args := []string{"network", "connect"}
+		for _, addr := range addresses {
+			if strings.Contains(addr, ":") {
+				args = append(args, "--ip6", addr)
+			} else {
+				args = append(args, "--ip", addr)
+			}
+		}
+		if err := m.faultStep(ctx, fault, append(args, kindNetwork, node)...); err != nil {
+			return nil, err
+		}
+	}
+	switch state.status {
+	case "running":
+	case "paused":
+		if err := m.faultStep(ctx, fault, "unpause", node); err != nil {
+			return nil, err
+		}
+	default:
+		if err := m.faultStep(ctx, fault, "start", node); err != nil {
+			return nil, err
+		}
+	}
+	m.refreshFault(ctx, fault)
+	if len(fault.Steps) == 0 {
+		fault.Message = "node was already running and connected; nothing to restore"
+	}
+
+	if timeout <= 0 || fault.Role == RoleExternalLoadBalancer {
+		return fault, nil
+	}
+	controlPlane := ""
+	for _, n := range nodes {
+		if NodeRole(n) == RoleControlPlane {
+			controlPlane = n
+			break
+		}
+	}
+	if err := m.waitAPIServer(ctx, controlPlane, timeout); err != nil {
+		fault.Message = err.Error()
+		return fault, nil
+	}
+	// The node's kubelet reports NotReady for a while after it is back; wait for it.
+	deadline := time.Now().Add(timeout)
+	for {
+		fault.Ready = m.kubeNodeReadiness(ctx, controlPlane)[node]
+		if fault.Ready == "Ready" || time.Now().After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fault, nil
+		case <-time.After(readyPollInterval):
+		}
+	}
+	return fault, nil
+}
+
+// clusterNode returns a cluster's nodes and the full container name of one of them.
+func (m *Manager) clusterNode(ctx context.Context, cluster, node string) ([]string, string, error) {
+	nodes, err := m.orderedNodes(ctx, cluster)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, name := range []string{node, cluster + "-" + node} {
+		if slices.Contains(nodes, name) {
+			return nodes, name, nil
+		}
+	}
+	return nil, "", fmt.Errorf("node %q is not part of cluster %q (nodes: %s)", node, cluster, strings.Join(nodes, ", "))
+}
+
+// faultStep runs a runtime command for a fault and records it in fault.Steps.
+func (m *Manager) faultStep(ctx context.Context, fault *NodeFault, args ...string) error {
+	step := m.runtimeBin() + " " + strings.Join(args, " ")
+	m.logger.Info("node fault", "node", fault.Name, "command", step)
+	if out, err := m.runner.Run(ctx, m.runtimeBin(), args...); err != nil {
+		return fmt.Errorf("%s: %v: %s", step, err, strings.TrimSpace(string(out)))
+	}
+	fault.Steps = append(fault.Steps, step)
+	return nil
+}
+
+// refreshFault records the node's container state and network attachment after a step.
+func (m *Manager) refreshFault(ctx context.Context, fault *NodeFault) {
+	state, ok := m.inspectNodesCLI(ctx, []string{fault.Name})[fault.Name]
+	if !ok {
+		fault.Status = "unknown"
+		return
+	}
+	fault.Status = state.status
+	fault.Disconnected = state.networks != nil && !slices.Contains(state.networks, kindNetwork)
+}
+
+// faultImpact describes what losing node means for the cluster.
+func faultImpact(nodes []string, node, verb string) string {
+	switch NodeRole(node) {
+	case RoleExternalLoadBalancer:
+		return fmt.Sprintf("load balancer %s; the API server is unreachable from the host until restore_node", verb)
+	case RoleControlPlane:
+		controlPlanes := 0
+		for _, n := range nodes {
+			if NodeRole(n) == RoleControlPlane {
+				controlPlanes++
+			}
+		}
+		if controlPlanes == 1 {
+			return fmt.Sprintf("the only control-plane node was %s; the API server is unavailable until restore_node", verb)
+		}
+		quorum := "etcd keeps quorum"
+		if controlPlanes-1 <= controlPlanes/2 {
+			quorum = "etcd has lost quorum and the API server stops accepting writes"
+		}
+		return fmt.Sprintf("control-plane node %s; %d of %d control planes remain and %s", verb, controlPlanes-1, controlPlanes, quorum)
+	default:
+		return fmt.Sprintf("worker %s; Kubernetes marks it NotReady after about 40s and evicts its pods after about 5m", verb)
+	}
+}
@@ -0,0 +1,102 @@
+package kind
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestKillNode(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"kill"}},
+		{name: "docker", args: []string{"inspect"}, out: []byte("/ha-control-plane2|exited|kind,\n")},
+	}}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	fault, err := mgr.KillNode(context.Background(), "ha", "control-plane2", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(runner.calls, "docker kill --signal SIGKILL ha-control-plane2") {
+		t.Errorf("calls = %v", runner.calls)
+	}
+	if fault.Name != "ha-control-plane2" || fault.Status != "exited" || fault.Disconnected {
+		t.Errorf("fault = %+v", fault)
+	}
+	if !strings.Contains(fault.Message, "1 of 2 control planes remain") || !strings.Contains(fault.Message, "lost quorum") {
+		t.Errorf("message = %q", fault.Message)
+	}
+
+	if _, err := mgr.KillNode(context.Background(), "ha", "worker9", ""); err == nil || !strings.Contains(err.Error(), "ha-worker") {
+		t.Errorf("expected an error listing the nodes, got %v", err)
+	}
+}
+
+func TestDisconnectNode(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"inspect", "--format", `{{with index .NetworkSettings.Networks "kind"}}{{.IPAddress}}|{{.GlobalIPv6Address}}{{end}}`},
+			out: []byte("172.18.0.5|fc00:f853::5\n")},
+		{name: "docker", args: []string{"network", "disconnect", "kind", "ha-worker"}},
+		{name: "docker", args: []string{"inspect"}, out: []byte("/ha-worker|running|\n")},
+	}}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	fault, err := mgr.DisconnectNode(context.Background(), "ha", "ha-worker")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fault.Disconnected || fault.Status != "running" || !slices.Equal(fault.Addresses, []string{"172.18.0.5", "fc00:f853::5"}) {
+		t.Errorf("fault = %+v", fault)
+	}
+	if !strings.Contains(fault.Message, "NotReady") {
+		t.Errorf("message = %q", fault.Message)
+	}
+}
+
+func TestRestoreNode(t *testing.T) {
+	readyPollInterval = time.Millisecond
+	runner := &mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"inspect"}, out: []byte("/ha-worker|exited|\n")},
+		{name: "docker", args: []string{"network", "connect"}},
+		{name: "docker", args: []string{"start", "ha-worker"}},
+		{name: "docker", args: []string{"exec", "ha-control-plane2", "kubectl", "*", "get", "--raw=/readyz"}, out: []byte("ok")},
+		{name: "docker", args: []string{"exec", "ha-control-plane2", "kubectl", "*", "get", "nodes"}, out: []byte(
+			"ha-worker   Ready   <none>   1d   v1.31.0\n")},
+	}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	fault, err := mgr.RestoreNode(context.Background(), "ha", "worker", []string{"172.18.0.5", "fc00:f853::5"}, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"docker network connect --ip 172.18.0.5 --ip6 fc00:f853::5 kind ha-worker", "docker start ha-worker"}
+	if !slices.Equal(fault.Steps, want) {
+		t.Errorf("steps = %v, want %v", fault.Steps, want)
+	}
+	if fault.Ready != "Ready" {
+		t.Errorf("ready = %q", fault.Ready)
+	}
+}
+
+func TestRestoreNode_NothingToDo(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"inspect"}, out: []byte("/ha-worker|running|kind,\n")},
+	}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	fault, err := mgr.RestoreNode(context.Background(), "ha", "worker", nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fault.Steps) != 0 || !strings.Contains(fault.Message, "nothing to restore") {
+		t.Errorf("fault = %+v", fault)
+	}
+}
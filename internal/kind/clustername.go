@@ -0,0 +1,68 @@
+package kind
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxClusterNameLength is the longest cluster name kind accepts. It leaves room for
+// node suffixes such as "-control-plane3" within the 63-character hostname limit.
+const MaxClusterNameLength = 50
+
+// clusterNamePattern is an RFC 1123 label: lowercase alphanumerics and '-', starting
+// and ending with an alphanumeric. Node container names and hostnames are derived
+// from the cluster name, so it must be one.
+var clusterNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// invalidNameRun matches the characters SanitizeClusterName replaces with '-'.
+var invalidNameRun = regexp.MustCompile(`[^a-z0-9]+`)
+
+// InvalidClusterNameError reports a cluster name kind would reject, with a valid
+// name derived from it.
+type InvalidClusterNameError struct {
+	Name       string
+	Reason     string
+	Suggestion string
+}
+
+func (e *InvalidClusterNameError) Error() string {
+	msg := fmt.Sprintf("invalid cluster name %q: %s", e.Name, e.Reason)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf("; try %q", e.Suggestion)
+	}
+	return msg
+}
+
+// ValidateClusterName checks a cluster name before anything is created, so a name
+// kind rejects fails at once instead of after a slow create. It returns an
+// *InvalidClusterNameError suggesting a valid name.
+func ValidateClusterName(name string) error {
+	var reason string
+	switch {
+	case name == "":
+		return fmt.Errorf("cluster name is required")
+	case len(name) > MaxClusterNameLength:
+		reason = fmt.Sprintf("longer than %d characters", MaxClusterNameLength)
+	case !clusterNamePattern.MatchString(name):
+		reason = "must consist of lowercase letters, digits and '-', and start and end with a letter or digit"
+	default:
+		return nil
+	}
+	return &InvalidClusterNameError{Name: name, Reason: reason, Suggestion: SanitizeClusterName(name)}
+}
+
+// SanitizeClusterName turns an arbitrary string into a valid cluster name: lowercased,
+// with runs of other characters replaced by '-', trimmed of leading and trailing
+// '-', and cut to MaxClusterNameLength. It returns "kind" when nothing is left.
+func SanitizeClusterName(name string) string {
+	name = invalidNameRun.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > MaxClusterNameLength {
+		name = strings.TrimRight(name[:MaxClusterNameLength], "-")
+	}
+	if name == "" {
+		return "kind"
+	}
+	return name
+}
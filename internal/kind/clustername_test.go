@@ -0,0 +1,70 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestValidateClusterName(t *testing.T) {
+	for _, name := range []string{"kind", "dev-1", "a", "0ab", strings.Repeat("a", MaxClusterNameLength)} {
+		if err := ValidateClusterName(name); err != nil {
+			t.Errorf("%q: unexpected error: %v", name, err)
+		}
+	}
+
+	tests := map[string]string{
+		"My_Cluster":              "my-cluster",
+		"-dev-":                   "dev",
+		"team.dev":                "team-dev",
+		"___":                     "kind",
+		strings.Repeat("ab-", 20): strings.TrimRight(strings.Repeat("ab-", 20)[:MaxClusterNameLength], "-"),
+		"Feature/Login Page":      "feature-login-page",
+	}
+	for name, suggestion := range tests {
+		err := ValidateClusterName(name)
+		var invalid *InvalidClusterNameError
+		if !errors.As(err, &invalid) {
+			t.Errorf("%q: expected *InvalidClusterNameError, got %v", name, err)
+			continue
+		}
+		if invalid.Suggestion != suggestion {
+			t.Errorf("%q: suggestion %q, want %q", name, invalid.Suggestion, suggestion)
+		}
+		if ValidateClusterName(invalid.Suggestion) != nil {
+			t.Errorf("%q: suggestion %q is itself invalid", name, invalid.Suggestion)
+		}
+		if !strings.Contains(err.Error(), suggestion) {
+			t.Errorf("%q: error %q does not include the suggestion", name, err)
+		}
+	}
+
+	if err := ValidateClusterName(""); err == nil || errors.As(err, new(*InvalidClusterNameError)) {
+		t.Errorf("empty name: got %v", err)
+	}
+}
+
+func TestClusterNameCheckedBeforeCreate(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{}}
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	if _, err := m.CreateCluster(context.Background(), "Dev_Cluster", "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n"); err == nil ||
+		!strings.Contains(err.Error(), `try "dev-cluster"`) {
+		t.Errorf("expected an invalid name error, got %v", err)
+	}
+	if _, err := m.RecreateCluster(context.Background(), "Dev_Cluster", "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n"); err == nil {
+		t.Error("expected recreate to reject the name")
+	}
+	if len(runner.calls) != 0 {
+		t.Errorf("expected no commands, got %v", runner.calls)
+	}
+	if _, err := GenerateConfig(ConfigOptions{ClusterName: "Dev"}); err == nil {
+		t.Error("expected GenerateConfig to reject the name")
+	}
+	report := CheckConfig("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nname: dev.local\n", rtdetect.RuntimeInfo{})
+	if report.Valid || report.Findings[0].Field != "name" {
+		t.Errorf("expected a name finding, got %+v", report)
+	}
+}
@@ -0,0 +1,89 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kindbin"
+	"gopkg.in/yaml.v3"
+)
+
+// kindModule is the module path of the kind Go library.
+const kindModule = "sigs.k8s.io/kind"
+
+// KindVersion returns the kind release in use: the kind CLI's, or the version of the
+// kind module this server is built with for the library backend.
+func (m *Manager) KindVersion(ctx context.Context) (string, error) {
+	if m.lib != nil {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, dep := range info.Deps {
+				if dep.Path == kindModule {
+					return dep.Version, nil
+				}
+			}
+		}
+		return "", fmt.Errorf("kind module version unknown")
+	}
+	out, err := m.runner.Run(m.kindContext(ctx), "kind", "version")
+	if err != nil {
+		return "", fmt.Errorf("kind version failed: %w\nOutput: %s", err, string(out))
+	}
+	return kindbin.ParseKindVersion(string(out))
+}
+
+// CheckKindCompatibility checks that the installed kind release supports the
+// Kubernetes version of every pinned node image in a config, and fails when it is too
+// old for one. Images in other repositories are checked by their vX.Y.Z tag, as
+// node image mirrors keep Kind's tags.
+func (m *Manager) CheckKindCompatibility(ctx context.Context, configYAML string) PreflightCheck {
+	check := PreflightCheck{Name: "kind-compatibility", Status: CheckPass}
+
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		check.Status, check.Message = CheckWarn, fmt.Sprintf("invalid YAML: %v", err)
+		return check
+	}
+	var versions []string
+	for _, node := range cfg.Nodes {
+		if tag := imageTag(node.Image); strings.HasPrefix(tag, "v1.") && !contains(versions, tag) {
+			versions = append(versions, tag)
+		}
+	}
+	if len(versions) == 0 {
+		check.Message = "no pinned node images; Kind uses the default image of its release"
+		return check
+	}
+
+	kindVersion, err := m.KindVersion(ctx)
+	if err != nil {
+		check.Status, check.Message = CheckWarn, fmt.Sprintf("could not determine the kind version: %v", err)
+		return check
+	}
+	var problems []string
+	for _, v := range versions {
+		r := kindbin.Check(kindVersion, v)
+		switch r.Level {
+		case kindbin.Unsupported:
+			check.Status = CheckFail
+		case kindbin.Untested:
+			if check.Status == CheckPass {
+				check.Status = CheckWarn
+			}
+		default:
+			continue
+		}
+		msg := r.Message
+		if len(r.Suggested) > 0 {
+			msg += fmt.Sprintf(" (suggested: %s)", strings.Join(r.Suggested, ", "))
+		}
+		problems = append(problems, msg)
+	}
+	if len(problems) == 0 {
+		check.Message = fmt.Sprintf("kind %s publishes node images for %s", kindVersion, strings.Join(versions, ", "))
+		return check
+	}
+	check.Message = strings.Join(problems, "; ")
+	return check
+}
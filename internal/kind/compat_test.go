@@ -0,0 +1,36 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCheckKindCompatibility(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"version"}, out: []byte("kind v0.27.0 go1.23.6 linux/amd64\n")},
+	}}
+	m := newDockerManager(runner)
+
+	tests := []struct {
+		version string
+		status  string
+		want    string
+	}{
+		{"1.32.2", CheckPass, "publishes node images"},
+		{"1.31.0", CheckWarn, "kindest/node:v1.31.6"},
+		{"1.34.0", CheckFail, "upgrade kind to v0.30.0"},
+	}
+	for _, tt := range tests {
+		cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev", KubernetesVersion: tt.version, NumWorkers: 1})
+		check := m.CheckKindCompatibility(context.Background(), cfg)
+		if check.Status != tt.status || !strings.Contains(check.Message, tt.want) {
+			t.Errorf("%s: %s %s", tt.version, check.Status, check.Message)
+		}
+	}
+
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev"})
+	if check := m.CheckKindCompatibility(context.Background(), cfg); check.Status != CheckPass {
+		t.Errorf("default image: %s %s", check.Status, check.Message)
+	}
+}
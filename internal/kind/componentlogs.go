@@ -0,0 +1,190 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Node components whose logs ComponentLogs reads: systemd units from the node's
+// journal, and control-plane static pods from their containers.
+const (
+	ComponentKubelet           = "kubelet"
+	ComponentContainerd        = "containerd"
+	ComponentAPIServer         = "kube-apiserver"
+	ComponentControllerManager = "kube-controller-manager"
+	ComponentScheduler         = "kube-scheduler"
+	ComponentEtcd              = "etcd"
+)
+
+// NodeComponents lists the components ComponentLogs accepts.
+var NodeComponents = []string{ComponentKubelet, ComponentContainerd, ComponentAPIServer,
+	ComponentControllerManager, ComponentScheduler, ComponentEtcd}
+
+// Component log limits.
+const (
+	DefaultComponentLogLines = 200
+	MaxComponentLogLines     = 5000
+)
+
+// ComponentLogs is the tail of one component's logs on a node.
+type ComponentLogs struct {
+	Cluster   string `json:"cluster"`
+	Node      string `json:"node"`
+	Component string `json:"component"`
+	// Source is "journal" for systemd units or "container" for static pods.
+	Source string `json:"source"`
+	// ContainerID, ContainerState and Restarts describe the static pod container the
+	// logs come from: the most recently created one, which for a crash-looping
+	// component is the last attempt.
+	ContainerID    string `json:"container_id,omitempty"`
+	ContainerState string `json:"container_state,omitempty"`
+	Restarts       int    `json:"restarts,omitempty"`
+	Command        string `json:"command"`
+	Logs           string `json:"logs"`
+}
+
+// crictlContainers is the part of 'crictl ps -a -o json' ComponentLogs reads.
+type crictlContainers struct {
+	Containers []struct {
+		ID       string `json:"id"`
+		State    string `json:"state"`
+		Metadata struct {
+			Name    string `json:"name"`
+			Attempt int    `json:"attempt"`
+		} `json:"metadata"`
+		CreatedAt string `json:"createdAt"`
+	} `json:"containers"`
+}
+
+// ComponentLogs returns the last lines of a node component's logs: journalctl for
+// kubelet and containerd, crictl logs of the newest container for the control-plane
+// static pods. since limits the logs to a recent window, as a Go duration ("15m") or
+// an RFC 3339 time. node is the container name or its suffix after "<cluster>-".
+func (m *Manager) ComponentLogs(ctx context.Context, cluster, node, component string, lines int, since string) (*ComponentLogs, error) {
+	if !slices.Contains(NodeComponents, component) {
+		return nil, fmt.Errorf("invalid component %q; must be one of %s", component, strings.Join(NodeComponents, ", "))
+	}
+	if lines <= 0 {
+		lines = DefaultComponentLogLines
+	}
+	lines = min(lines, MaxComponentLogLines)
+	_, node, err := m.clusterNode(ctx, cluster, node)
+	if err != nil {
+		return nil, err
+	}
+	role := NodeRole(node)
+	if role == RoleExternalLoadBalancer {
+		return nil, fmt.Errorf("node %s is the external load balancer; it runs haproxy only, without Kubernetes components", node)
+	}
+
+	res := &ComponentLogs{Cluster: cluster, Node: node, Component: component}
+	var cmd []string
+	switch component {
+	case ComponentKubelet, ComponentContainerd:
+		res.Source = "journal"
+		cmd = []string{"journalctl", "-u", component, "-n", strconv.Itoa(lines), "--no-pager", "-o", "short-iso"}
+		if since != "" {
+			s, err := journalSince(since)
+			if err != nil {
+				return nil, err
+			}
+			cmd = append(cmd, "--since", s)
+		}
+	default:
+		if role != RoleControlPlane {
+			return nil, fmt.Errorf("%s runs on control-plane nodes only, not on %s", component, node)
+		}
+		res.Source = "container"
+		if err := m.latestContainer(ctx, node, component, res); err != nil {
+			return nil, err
+		}
+		cmd = []string{"crictl", "logs", "--tail", strconv.Itoa(lines)}
+		if since != "" {
+			s, err := crictlSince(since)
+			if err != nil {
+				return nil, err
+			}
+			cmd = append(cmd, "--since", s)
+		}
+		cmd = append(cmd, res.ContainerID)
+	}
+
+	res.Command = m.ExecCommandLine(node, cmd)
+	out, err := m.ExecOnNode(ctx, node, cmd)
+	if err != nil {
+		return nil, err
+	}
+	res.Logs = out
+	return res, nil
+}
+
+// latestContainer finds the newest container of a static pod component on a node.
+func (m *Manager) latestContainer(ctx context.Context, node, component string, res *ComponentLogs) error {
+	out, err := m.ExecOnNode(ctx, node, []string{"crictl", "ps", "-a", "--name", component, "-o", "json"})
+	if err != nil {
+		return err
+	}
+	var list crictlContainers
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return fmt.Errorf("parsing crictl ps output: %w", err)
+	}
+	found := false
+	var newest int64
+	for _, c := range list.Containers {
+		// --name is a regular expression, so kube-scheduler would also match a
+		// component named kube-scheduler-extra.
+		if c.Metadata.Name != component {
+			continue
+		}
+		created, _ := strconv.ParseInt(c.CreatedAt, 10, 64)
+		if found && created <= newest {
+			continue
+		}
+		found, newest = true, created
+		res.ContainerID, res.ContainerState, res.Restarts = c.ID, c.State, c.Metadata.Attempt
+	}
+	if !found {
+		return fmt.Errorf("no %s container on %s; check the kubelet logs for why its static pod did not start", component, node)
+	}
+	return nil
+}
+
+// parseSince parses a since value: a positive Go duration, or else an RFC 3339 time.
+func parseSince(since string) (time.Duration, time.Time, error) {
+	if d, err := time.ParseDuration(since); err == nil && d > 0 {
+		return d, time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return 0, t.UTC(), nil
+	}
+	return 0, time.Time{}, fmt.Errorf("invalid since %q; use a duration such as '15m' or an RFC 3339 time", since)
+}
+
+// journalSince converts a since value to a journalctl --since argument.
+func journalSince(since string) (string, error) {
+	d, t, err := parseSince(since)
+	switch {
+	case err != nil:
+		return "", err
+	case d > 0:
+		return fmt.Sprintf("-%ds", int(d.Seconds())), nil
+	}
+	return t.Format("2006-01-02 15:04:05") + " UTC", nil
+}
+
+// crictlSince converts a since value to a crictl logs --since argument.
+func crictlSince(since string) (string, error) {
+	d, t, err := parseSince(since)
+	switch {
+	case err != nil:
+		return "", err
+	case d > 0:
+		return fmt.Sprintf("%ds", int(d.Seconds())), nil
+	}
+	return t.Format(time.RFC3339), nil
+}
@@ -0,0 +1,73 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const apiServerContainersJSON = `{"containers":[
+	{"id":"old","state":"CONTAINER_EXITED","metadata":{"name":"kube-apiserver","attempt":3},"createdAt":"1700000000000000000"},
+	{"id":"new","state":"CONTAINER_EXITED","metadata":{"name":"kube-apiserver","attempt":4},"createdAt":"1700000100000000000"},
+	{"id":"other","state":"CONTAINER_RUNNING","metadata":{"name":"kube-apiserver-proxy","attempt":0},"createdAt":"1700000200000000000"}]}`
+
+func componentLogRunner() *loggingRunner {
+	return &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\ndev-external-load-balancer\n")},
+		{name: "docker", args: []string{"exec", "dev-control-plane", "crictl", "ps"}, out: []byte(apiServerContainersJSON)},
+		{name: "docker", args: []string{"exec", "*", "crictl", "logs"}, out: []byte("E0101 failed to listen\n")},
+		{name: "docker", args: []string{"exec", "*", "journalctl"}, out: []byte("kubelet started\n")},
+	}}}
+}
+
+func TestComponentLogs_Journal(t *testing.T) {
+	runner := componentLogRunner()
+	m := newDockerManager(runner.mockRunner)
+	m.runner = runner
+
+	logs, err := m.ComponentLogs(context.Background(), "dev", "worker", ComponentKubelet, 50, "15m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logs.Node != "dev-worker" || logs.Source != "journal" || logs.Logs != "kubelet started\n" {
+		t.Errorf("logs = %+v", logs)
+	}
+	want := "docker exec dev-worker journalctl -u kubelet -n 50 --no-pager -o short-iso --since -900s"
+	if !strings.Contains(strings.Join(runner.calls, "\n"), want) {
+		t.Errorf("calls = %v, want %q", runner.calls, want)
+	}
+}
+
+func TestComponentLogs_StaticPod(t *testing.T) {
+	runner := componentLogRunner()
+	m := newDockerManager(runner.mockRunner)
+	m.runner = runner
+
+	logs, err := m.ComponentLogs(context.Background(), "dev", "control-plane", ComponentAPIServer, 0, "2026-01-02T03:04:05+01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logs.ContainerID != "new" || logs.Restarts != 4 || logs.ContainerState != "CONTAINER_EXITED" {
+		t.Errorf("logs = %+v, want the newest kube-apiserver container", logs)
+	}
+	want := "crictl logs --tail 200 --since 2026-01-02T02:04:05Z new"
+	if !strings.Contains(logs.Command, want) {
+		t.Errorf("command = %q, want %q", logs.Command, want)
+	}
+}
+
+func TestComponentLogs_Errors(t *testing.T) {
+	m := newDockerManager(componentLogRunner().mockRunner)
+	for _, tt := range []struct{ node, component, since, want string }{
+		{"worker", "kube-proxy", "", "invalid component"},
+		{"worker", ComponentEtcd, "", "control-plane nodes only"},
+		{"external-load-balancer", ComponentKubelet, "", "external load balancer"},
+		{"worker", ComponentKubelet, "yesterday", "invalid since"},
+		{"worker9", ComponentKubelet, "", "not part of cluster"},
+	} {
+		_, err := m.ComponentLogs(context.Background(), "dev", tt.node, tt.component, 0, tt.since)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s/%s: err = %v, want %q", tt.node, tt.component, err, tt.want)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package kind
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
 	"gopkg.in/yaml.v3"
+	kinddefaults "sigs.k8s.io/kind/pkg/apis/config/defaults"
 )
 
 // ClusterConfig represents a Kind cluster configuration.
@@ -16,6 +19,7 @@ type ClusterConfig struct {
 	Networking              *NetworkConfig  `yaml:"networking,omitempty"`
 	FeatureGates            map[string]bool `yaml:"featureGates,omitempty"`
 	ContainerdConfigPatches []string        `yaml:"containerdConfigPatches,omitempty"`
+	KubeadmConfigPatches    []string        `yaml:"kubeadmConfigPatches,omitempty"`
 }
 
 // NodeConfig represents a Kind node configuration.
@@ -63,12 +67,111 @@ type ConfigOptions struct {
 	IPFamily          string
 	KubeProxyMode     string
 	APIServerPort     int
+	// NodeImageRepository replaces kindest/node for node images, e.g. registry.corp/kind/node.
+	NodeImageRepository string
+	// NodePortMappings and NodeMounts are applied only to the nodes their Target selects.
+	NodePortMappings []TargetedPortMapping
+	NodeMounts       []TargetedMount
+	// Taints are registered by the kubelets of the nodes their Target selects.
+	Taints []TargetedTaint
+	// NodePortRange sets kube-apiserver --service-node-port-range, e.g. "30000-40000".
+	NodePortRange string
+	// APIServerCertSANs are extra hostnames and IPs for the API server certificate, for
+	// reaching the cluster from other machines or through a reverse proxy.
+	APIServerCertSANs []string
+	// EnableIngressPorts maps host ports 80 and 443 to the first control plane and
+	// labels it ingress-ready=true, as the Kind ingress controller manifests expect.
+	EnableIngressPorts bool
+	// IngressListenAddress is the host address of the ingress ports; default 127.0.0.1.
+	IngressListenAddress string
+	// ReserveKubeletResources reserves CPU and memory for system daemons and the
+	// kubelet on every node, as RecommendClusterSize suggests for constrained engines.
+	ReserveKubeletResources bool
+	// ControlPlaneImage and WorkerImage pin the node image of one role, overriding
+	// KubernetesVersion, e.g. to test version skew between control planes and
+	// kubelets. A bare version such as "1.30.4" names the image of that version in
+	// NodeImageRepository or kindest/node.
+	ControlPlaneImage string
+	WorkerImage       string
+	// Tuning sets kubelet, API server and scheduler options through generated kubeadm patches.
+	Tuning Tuning
+	// TuningDir is the host directory holding the files written by WriteTuningFiles,
+	// mounted into the control-plane nodes; required when Tuning.NeedsFiles.
+	TuningDir string
+}
+
+// NodeTarget selects nodes by role and, optionally, by index within that role.
+// The zero value selects every node.
+type NodeTarget struct {
+	Role  string `json:"role,omitempty"`  // "control-plane", "worker", or empty for any role
+	Index *int   `json:"index,omitempty"` // 0-based position among nodes of Role; nil for all of them
+}
+
+// Matches reports whether the target selects the index'th node of the given role.
+func (t NodeTarget) Matches(role string, index int) bool {
+	if t.Role != "" && t.Role != role {
+		return false
+	}
+	return t.Index == nil || *t.Index == index
+}
+
+// validate checks that the target names a valid role and an index that exists.
+func (t NodeTarget) validate(opts ConfigOptions) error {
+	var count int
+	switch t.Role {
+	case RoleControlPlane:
+		count = opts.NumControlPlanes
+	case RoleWorker:
+		count = opts.NumWorkers
+	case "":
+		if t.Index != nil {
+			return fmt.Errorf("target index requires a role")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid target role %q; must be 'control-plane' or 'worker'", t.Role)
+	}
+	if count == 0 {
+		return fmt.Errorf("target role %q has no nodes", t.Role)
+	}
+	if t.Index != nil && (*t.Index < 0 || *t.Index >= count) {
+		return fmt.Errorf("target %s index %d out of range (0-%d)", t.Role, *t.Index, count-1)
+	}
+	return nil
+}
+
+// TargetedPortMapping is a port mapping applied to the nodes selected by Target.
+type TargetedPortMapping struct {
+	PortMapping
+	Target NodeTarget `json:"target"`
+}
+
+// TargetedMount is an extra mount applied to the nodes selected by Target.
+type TargetedMount struct {
+	Mount
+	Target NodeTarget `json:"target"`
 }
 
 // GenerateConfig generates a Kind cluster configuration YAML from the given options.
 func GenerateConfig(opts ConfigOptions) (string, error) {
-	if opts.ClusterName == "" {
-		return "", fmt.Errorf("cluster name is required")
+	cfg, err := BuildConfig(opts)
+	if err != nil {
+		return "", err
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("marshaling config to YAML: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// BuildConfig generates a Kind cluster configuration from the given options as a
+// ClusterConfig, for callers that inspect or adjust it before encoding. Fields the
+// struct does not model are added to the encoded YAML with MergeConfigOverrides.
+func BuildConfig(opts ConfigOptions) (*ClusterConfig, error) {
+	if err := ValidateClusterName(opts.ClusterName); err != nil {
+		return nil, err
 	}
 	if opts.NumControlPlanes <= 0 {
 		opts.NumControlPlanes = 1
@@ -80,42 +183,87 @@ func GenerateConfig(opts ConfigOptions) (string, error) {
 		Name:       opts.ClusterName,
 	}
 
-	// Build control plane nodes
-	for i := 0; i < opts.NumControlPlanes; i++ {
-		node := NodeConfig{
-			Role: "control-plane",
+	if err := opts.Tuning.validate(); err != nil {
+		return nil, err
+	}
+	if opts.Tuning.NeedsFiles() && opts.TuningDir == "" {
+		return nil, fmt.Errorf("audit logging and scheduler profiles need a directory for their files")
+	}
+
+	for _, t := range opts.NodePortMappings {
+		if err := t.Target.validate(opts); err != nil {
+			return nil, fmt.Errorf("port mapping %d->%d: %w", t.HostPort, t.ContainerPort, err)
 		}
-		if opts.KubernetesVersion != "" {
-			node.Image = kindNodeImage(opts.KubernetesVersion)
+	}
+	for _, t := range opts.NodeMounts {
+		if err := t.Target.validate(opts); err != nil {
+			return nil, fmt.Errorf("mount %s: %w", t.HostPath, err)
 		}
-		// Port mappings only on the first control plane
-		if i == 0 && len(opts.PortMappings) > 0 {
-			node.ExtraPortMappings = opts.PortMappings
+	}
+	for _, t := range opts.Taints {
+		if err := t.validate(); err != nil {
+			return nil, err
 		}
-		if len(opts.ExtraMounts) > 0 {
-			node.ExtraMounts = opts.ExtraMounts
+		if err := t.Target.validate(opts); err != nil {
+			return nil, fmt.Errorf("taint %s: %w", t.Taint, err)
 		}
-		if len(opts.Labels) > 0 {
-			node.Labels = opts.Labels
+	}
+
+	// Build control plane nodes, then workers
+	for i := 0; i < opts.NumControlPlanes; i++ {
+		node := newNode(opts, RoleControlPlane, i)
+		node.ExtraMounts = append(node.ExtraMounts, opts.Tuning.tuningMounts(opts.TuningDir)...)
+		// Untargeted port mappings only go on the first control plane
+		if i == 0 && len(opts.PortMappings) > 0 {
+			node.ExtraPortMappings = append(append([]PortMapping{}, opts.PortMappings...), node.ExtraPortMappings...)
 		}
 		cfg.Nodes = append(cfg.Nodes, node)
 	}
-
-	// Build worker nodes
 	for i := 0; i < opts.NumWorkers; i++ {
-		node := NodeConfig{
-			Role: "worker",
+		cfg.Nodes = append(cfg.Nodes, newNode(opts, RoleWorker, i))
+	}
+	if len(opts.Taints) > 0 {
+		indexes := map[string]int{}
+		for i := range cfg.Nodes {
+			node := &cfg.Nodes[i]
+			taints := nodeTaints(opts, node.Role, indexes[node.Role])
+			indexes[node.Role]++
+			if len(taints) == 0 {
+				continue
+			}
+			patches, err := taintPatches(taints)
+			if err != nil {
+				return nil, err
+			}
+			node.KubeadmConfigPatches = append(node.KubeadmConfigPatches, patches...)
 		}
-		if opts.KubernetesVersion != "" {
-			node.Image = kindNodeImage(opts.KubernetesVersion)
+	}
+
+	for _, f := range versionSkewFindings(cfg) {
+		if f.Severity == SeverityError {
+			return nil, fmt.Errorf("%s", f.Message)
 		}
-		if len(opts.ExtraMounts) > 0 {
-			node.ExtraMounts = opts.ExtraMounts
+	}
+	for _, image := range []string{opts.ControlPlaneImage, opts.WorkerImage} {
+		if image == "" {
+			continue
 		}
-		if len(opts.Labels) > 0 {
-			node.Labels = opts.Labels
+		if err := checkImageRef(roleImage(opts.NodeImageRepository, image)); err != nil {
+			return nil, err
 		}
-		cfg.Nodes = append(cfg.Nodes, node)
+	}
+
+	if opts.EnableIngressPorts {
+		mappings, err := ingressPortMappings(opts)
+		if err != nil {
+			return nil, err
+		}
+		patches, err := ingressReadyPatches()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Nodes[0].ExtraPortMappings = append(cfg.Nodes[0].ExtraPortMappings, mappings...)
+		cfg.Nodes[0].KubeadmConfigPatches = append(cfg.Nodes[0].KubeadmConfigPatches, patches...)
 	}
 
 	// Networking
@@ -136,48 +284,109 @@ func GenerateConfig(opts ConfigOptions) (string, error) {
 		cfg.ContainerdConfigPatches = opts.ContainerdPatches
 	}
 
-	data, err := yaml.Marshal(cfg)
+	patches, err := apiServerPatches(opts)
 	if err != nil {
-		return "", fmt.Errorf("marshaling config to YAML: %w", err)
+		return nil, err
 	}
+	kubelet, err := kubeletPatch(opts.ReserveKubeletResources, opts.Tuning)
+	if err != nil {
+		return nil, err
+	}
+	if kubelet != "" {
+		patches = append(patches, kubelet)
+	}
+	cfg.KubeadmConfigPatches = patches
+	return &cfg, nil
+}
 
-	return string(data), nil
+// newNode builds the index'th node of the given role, applying the shared options
+// and any targeted port mappings and mounts that select it.
+func newNode(opts ConfigOptions, role string, index int) NodeConfig {
+	node := NodeConfig{Role: role}
+	switch {
+	case opts.KubernetesVersion != "":
+		node.Image = kindNodeImage(opts.NodeImageRepository, opts.KubernetesVersion)
+	case opts.NodeImageRepository != "":
+		// Kind's default image lives in kindest/node, so pin its version in the mirror.
+		node.Image = kindNodeImage(opts.NodeImageRepository, imageTag(kinddefaults.Image))
+	}
+	switch {
+	case role == RoleControlPlane && opts.ControlPlaneImage != "":
+		node.Image = roleImage(opts.NodeImageRepository, opts.ControlPlaneImage)
+	case role == RoleWorker && opts.WorkerImage != "":
+		node.Image = roleImage(opts.NodeImageRepository, opts.WorkerImage)
+	}
+	if len(opts.ExtraMounts) > 0 {
+		node.ExtraMounts = append(node.ExtraMounts, opts.ExtraMounts...)
+	}
+	if len(opts.Labels) > 0 {
+		node.Labels = opts.Labels
+	}
+	for _, t := range opts.NodePortMappings {
+		if t.Target.Matches(role, index) {
+			node.ExtraPortMappings = append(node.ExtraPortMappings, t.PortMapping)
+		}
+	}
+	for _, t := range opts.NodeMounts {
+		if t.Target.Matches(role, index) {
+			node.ExtraMounts = append(node.ExtraMounts, t.Mount)
+		}
+	}
+	return node
 }
 
-// kindNodeImage returns the kindest/node image for a given Kubernetes version.
-func kindNodeImage(version string) string {
+// DefaultNodeImageRepository is the repository Kind publishes node images to.
+const DefaultNodeImageRepository = "kindest/node"
+
+// kindNodeImage returns the node image for a given Kubernetes version from repo, or
+// from kindest/node if repo is empty.
+func kindNodeImage(repo, version string) string {
+	if repo == "" {
+		repo = DefaultNodeImageRepository
+	}
 	if !strings.HasPrefix(version, "v") {
 		version = "v" + version
 	}
-	return fmt.Sprintf("kindest/node:%s", version)
+	return fmt.Sprintf("%s:%s", strings.TrimSuffix(repo, "/"), version)
 }
 
-// ValidateConfig performs basic validation on a Kind cluster config YAML.
-func ValidateConfig(configYAML string) error {
-	var cfg ClusterConfig
-	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
-		return fmt.Errorf("invalid YAML: %w", err)
+// imageRepository returns an image reference without its tag or digest.
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return image[:i]
 	}
+	return image
+}
 
-	if cfg.Kind != "Cluster" {
-		return fmt.Errorf("expected kind 'Cluster', got %q", cfg.Kind)
+// ConfigObject parses a Kind config YAML into a generic object that keeps Kind's own
+// field names, so it can be encoded as JSON, edited, and passed back as config YAML.
+func ConfigObject(configYAML string) (map[string]any, error) {
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(configYAML), &obj); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
 	}
-	if cfg.APIVersion != "kind.x-k8s.io/v1alpha4" {
-		return fmt.Errorf("expected apiVersion 'kind.x-k8s.io/v1alpha4', got %q", cfg.APIVersion)
+	return obj, nil
+}
+
+// ConfigNodeCount returns the number of nodes a Kind config creates; a config without
+// nodes creates a single control-plane node.
+func ConfigNodeCount(configYAML string) (int, error) {
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return 0, fmt.Errorf("invalid YAML: %w", err)
 	}
+	return max(len(cfg.Nodes), 1), nil
+}
 
-	hasControlPlane := false
-	for _, node := range cfg.Nodes {
-		if node.Role == "control-plane" {
-			hasControlPlane = true
-		}
-		if node.Role != "control-plane" && node.Role != "worker" {
-			return fmt.Errorf("invalid node role %q; must be 'control-plane' or 'worker'", node.Role)
+// ValidateConfig performs basic validation on a Kind cluster config YAML and returns
+// the first error found by CheckConfig. Warnings are ignored.
+func ValidateConfig(configYAML string) error {
+	report := CheckConfig(configYAML, rtdetect.RuntimeInfo{})
+	for _, f := range report.Findings {
+		if f.Severity == SeverityError {
+			return errors.New(f.Message)
 		}
 	}
-	if len(cfg.Nodes) > 0 && !hasControlPlane {
-		return fmt.Errorf("at least one control-plane node is required")
-	}
-
 	return nil
 }
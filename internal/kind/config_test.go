@@ -1,6 +1,7 @@
 package kind
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -177,6 +178,19 @@ func TestValidateConfig_InvalidRole(t *testing.T) {
 	}
 }
 
+func TestConfigNodeCount(t *testing.T) {
+	out, _ := GenerateConfig(ConfigOptions{ClusterName: "count", NumControlPlanes: 3, NumWorkers: 2})
+	if n, err := ConfigNodeCount(out); err != nil || n != 5 {
+		t.Errorf("ConfigNodeCount = %d, %v; want 5", n, err)
+	}
+	if n, err := ConfigNodeCount("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n"); err != nil || n != 1 {
+		t.Errorf("ConfigNodeCount without nodes = %d, %v; want 1", n, err)
+	}
+	if _, err := ConfigNodeCount("nodes: ["); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
 func TestKindNodeImage(t *testing.T) {
 	tests := []struct {
 		version string
@@ -186,9 +200,137 @@ func TestKindNodeImage(t *testing.T) {
 		{"v1.30.0", "kindest/node:v1.30.0"},
 	}
 	for _, tt := range tests {
-		got := kindNodeImage(tt.version)
+		got := kindNodeImage("", tt.version)
 		if got != tt.want {
 			t.Errorf("kindNodeImage(%q) = %q, want %q", tt.version, got, tt.want)
 		}
 	}
 }
+
+func TestGenerateConfig_TargetedMountsAndPorts(t *testing.T) {
+	second := 1
+	out, err := GenerateConfig(ConfigOptions{
+		ClusterName:      "targeted",
+		NumControlPlanes: 1,
+		NumWorkers:       2,
+		PortMappings:     []PortMapping{{HostPort: 8080, ContainerPort: 80}},
+		NodePortMappings: []TargetedPortMapping{
+			{PortMapping: PortMapping{HostPort: 9090, ContainerPort: 30090}, Target: NodeTarget{Role: RoleWorker, Index: &second}},
+		},
+		NodeMounts: []TargetedMount{
+			{Mount: Mount{HostPath: "/data", ContainerPath: "/data"}, Target: NodeTarget{Role: RoleWorker}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	cp, w0, w1 := cfg.Nodes[0], cfg.Nodes[1], cfg.Nodes[2]
+	if len(cp.ExtraPortMappings) != 1 || cp.ExtraPortMappings[0].HostPort != 8080 {
+		t.Errorf("control plane ports = %+v", cp.ExtraPortMappings)
+	}
+	if len(cp.ExtraMounts) != 0 {
+		t.Errorf("control plane should have no mounts: %+v", cp.ExtraMounts)
+	}
+	if len(w0.ExtraPortMappings) != 0 {
+		t.Errorf("first worker should have no ports: %+v", w0.ExtraPortMappings)
+	}
+	if len(w1.ExtraPortMappings) != 1 || w1.ExtraPortMappings[0].HostPort != 9090 {
+		t.Errorf("second worker ports = %+v", w1.ExtraPortMappings)
+	}
+	if len(w0.ExtraMounts) != 1 || len(w1.ExtraMounts) != 1 {
+		t.Errorf("both workers should get the mount: %+v / %+v", w0.ExtraMounts, w1.ExtraMounts)
+	}
+}
+
+func TestGenerateConfig_InvalidTarget(t *testing.T) {
+	idx := 2
+	tests := map[string]NodeTarget{
+		"bad role":     {Role: "bogus"},
+		"out of range": {Role: RoleWorker, Index: &idx},
+		"no workers":   {Role: RoleWorker},
+		"index only":   {Index: &idx},
+	}
+	for name, target := range tests {
+		workers := 2
+		if name == "no workers" {
+			workers = 0
+		}
+		_, err := GenerateConfig(ConfigOptions{
+			ClusterName: "bad",
+			NumWorkers:  workers,
+			NodeMounts:  []TargetedMount{{Mount: Mount{HostPath: "/a", ContainerPath: "/a"}, Target: target}},
+		})
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestGenerateConfig_NodeImageRepository(t *testing.T) {
+	out, err := GenerateConfig(ConfigOptions{
+		ClusterName:         "mirror",
+		KubernetesVersion:   "1.31.0",
+		NodeImageRepository: "registry.corp/kind/node",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "image: registry.corp/kind/node:v1.31.0") {
+		t.Errorf("expected mirrored image:\n%s", out)
+	}
+
+	// Without a version, Kind's default node version is pinned from the mirror.
+	out, _ = GenerateConfig(ConfigOptions{ClusterName: "mirror", NodeImageRepository: "registry.corp/kind/node"})
+	if !strings.Contains(out, "image: registry.corp/kind/node:v") {
+		t.Errorf("expected default version pinned in mirror:\n%s", out)
+	}
+}
+
+func TestImageRepository(t *testing.T) {
+	tests := map[string]string{
+		"kindest/node:v1.31.0@sha256:abc": "kindest/node",
+		"registry:5000/kind/node:v1.30.0": "registry:5000/kind/node",
+		"registry:5000/kind/node":         "registry:5000/kind/node",
+	}
+	for image, want := range tests {
+		if got := imageRepository(image); got != want {
+			t.Errorf("imageRepository(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestConfigObject_RoundTrip(t *testing.T) {
+	configYAML, err := GenerateConfig(ConfigOptions{
+		ClusterName:  "obj",
+		NumWorkers:   1,
+		PortMappings: DefaultPortMappings(""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := ConfigObject(configYAML)
+	if err != nil {
+		t.Fatalf("ConfigObject: %v", err)
+	}
+
+	nodes := obj["nodes"].([]any)
+	first := nodes[0].(map[string]any)
+	mappings := first["extraPortMappings"].([]any)
+	if got := mappings[0].(map[string]any)["hostPort"]; got != 80 {
+		t.Errorf("hostPort = %v (%T), want 80", got, got)
+	}
+
+	// The JSON form must be accepted back as config YAML.
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateConfig(string(data)); err != nil {
+		t.Errorf("ValidateConfig(json) = %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package kind
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// storedConfigName is the file a cluster's config is kept in under its config directory.
+const storedConfigName = "kind-config.yaml"
+
+// SetConfigDir makes CreateCluster keep each cluster's config, readable only by the
+// owner, in <dir>/<cluster>/kind-config.yaml instead of passing it through a
+// short-lived file or stdin. An empty dir disables it.
+func (m *Manager) SetConfigDir(dir string) {
+	m.configDir = ExpandHome(dir)
+}
+
+// StoredConfigPath returns where a cluster's config is kept in the config directory,
+// or "" if no config directory is set.
+func (m *Manager) StoredConfigPath(name string) string {
+	if m.configDir == "" {
+		return ""
+	}
+	return filepath.Join(m.configDir, name, storedConfigName)
+}
+
+// StoredConfig returns the config kept for a cluster in the config directory.
+func (m *Manager) StoredConfig(name string) (string, error) {
+	path := m.StoredConfigPath(name)
+	if path == "" {
+		return "", fmt.Errorf("no config directory is set")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading stored config: %w", err)
+	}
+	return string(data), nil
+}
+
+// configSource decides how kind create receives a cluster's config: the file in the
+// config directory when one is set, stdin ("-") when the runner can write it, or
+// otherwise a 0600 temp file removed by cleanup.
+func (m *Manager) configSource(name, configYAML string) (path string, stdin []byte, cleanup func(), err error) {
+	cleanup = func() {}
+	if stored := m.StoredConfigPath(name); stored != "" {
+		if err := writePrivateFile(stored, []byte(configYAML)); err != nil {
+			return "", nil, cleanup, fmt.Errorf("storing cluster config: %w", err)
+		}
+		return stored, nil, cleanup, nil
+	}
+	if _, ok := m.runner.(rtdetect.InputRunner); ok {
+		return "-", []byte(configYAML), cleanup, nil
+	}
+
+	// CreateTemp opens the file with mode 0600.
+	f, err := os.CreateTemp("", "kind-config-*.yaml")
+	if err != nil {
+		return "", nil, cleanup, fmt.Errorf("creating temp config file: %w", err)
+	}
+	cleanup = func() { os.Remove(f.Name()) }
+	if _, err := f.WriteString(configYAML); err != nil {
+		f.Close()
+		return "", nil, cleanup, fmt.Errorf("writing config to temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", nil, cleanup, fmt.Errorf("writing config to temp file: %w", err)
+	}
+	return f.Name(), nil, cleanup, nil
+}
+
+// plannedConfigSource is the config path configSource would use, for dry runs.
+func (m *Manager) plannedConfigSource(name string) string {
+	if stored := m.StoredConfigPath(name); stored != "" {
+		return stored
+	}
+	if _, ok := m.runner.(rtdetect.InputRunner); ok {
+		return "-"
+	}
+	return "<temp>/kind-config-XXXX.yaml"
+}
+
+// writePrivateFile writes data to path with mode 0600, creating its directory with 0700.
+func writePrivateFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file, so tighten it explicitly.
+	return os.Chmod(path, 0o600)
+}
@@ -0,0 +1,117 @@
+package kind
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// configRunner records how kind create received its config.
+type configRunner struct {
+	mockRunner
+	args  []string
+	stdin []byte
+	mode  os.FileMode // of the --config file while kind runs
+}
+
+func (c *configRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	c.record(args)
+	return c.mockRunner.RunSeparate(ctx, name, args...)
+}
+
+func (c *configRunner) record(args []string) {
+	c.args = args
+	if path := args[len(args)-1]; path != "-" {
+		if st, err := os.Stat(path); err == nil {
+			c.mode = st.Mode().Perm()
+		}
+	}
+}
+
+// stdinConfigRunner is a configRunner that can also pass standard input.
+type stdinConfigRunner struct{ configRunner }
+
+func (s *stdinConfigRunner) RunInput(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	s.stdin = input
+	return s.configRunner.RunSeparate(ctx, name, args...)
+}
+
+func createRuns() mockRunner {
+	return mockRunner{runs: []runCall{{name: "kind", args: []string{"create", "cluster"}, out: []byte("Creating cluster\n")}}}
+}
+
+func testClusterConfig(t *testing.T) string {
+	t.Helper()
+	cfg, err := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestCreateCluster_ConfigOnStdin(t *testing.T) {
+	runner := &stdinConfigRunner{configRunner{mockRunner: createRuns()}}
+	cfg := testClusterConfig(t)
+
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	if _, err := mgr.CreateCluster(context.Background(), "dev", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(runner.args, " "); got != "create cluster --name dev --config -" {
+		t.Errorf("args = %q", got)
+	}
+	if string(runner.stdin) != cfg {
+		t.Errorf("stdin = %q", runner.stdin)
+	}
+
+	plan, _ := mgr.PlanCreateCluster(context.Background(), "dev", cfg)
+	if plan.ConfigFile != "-" {
+		t.Errorf("planned config file = %q", plan.ConfigFile)
+	}
+}
+
+func TestCreateCluster_TempConfigMode(t *testing.T) {
+	runner := &configRunner{mockRunner: createRuns()}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	if _, err := mgr.CreateCluster(context.Background(), "dev", testClusterConfig(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := runner.args[len(runner.args)-1]
+	if runner.mode != 0o600 {
+		t.Errorf("temp config mode = %o, want 600", runner.mode)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("temp config %s should be removed, stat error: %v", path, err)
+	}
+}
+
+func TestCreateCluster_ConfigDir(t *testing.T) {
+	runner := &stdinConfigRunner{configRunner{mockRunner: createRuns()}}
+	cfg := testClusterConfig(t)
+	dir := t.TempDir()
+
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	mgr.SetConfigDir(dir)
+	if _, err := mgr.CreateCluster(context.Background(), "dev", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := filepath.Join(dir, "dev", "kind-config.yaml")
+	if path := runner.args[len(runner.args)-1]; path != want || runner.stdin != nil {
+		t.Errorf("config passed as %q (stdin %q), want %q", path, runner.stdin, want)
+	}
+	if runner.mode != 0o600 {
+		t.Errorf("stored config mode = %o, want 600", runner.mode)
+	}
+	stored, err := mgr.StoredConfig("dev")
+	if err != nil || stored != cfg {
+		t.Errorf("StoredConfig = %q, %v", stored, err)
+	}
+	if _, err := mgr.StoredConfig("other"); err == nil {
+		t.Error("expected error for a cluster without a stored config")
+	}
+}
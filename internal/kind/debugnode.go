@@ -0,0 +1,166 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Diagnostics DebugNode runs inside a node container.
+const (
+	DiagnosticDisk       = "disk"
+	DiagnosticInodes     = "inodes"
+	DiagnosticMemory     = "memory"
+	DiagnosticLoad       = "load"
+	DiagnosticServices   = "services"
+	DiagnosticContainers = "containers"
+	DiagnosticIptables   = "iptables"
+	DiagnosticKubelet    = "kubelet-errors"
+)
+
+// NodeDiagnostics lists the diagnostics DebugNode accepts, in the order it runs them.
+var NodeDiagnostics = []string{DiagnosticDisk, DiagnosticInodes, DiagnosticMemory, DiagnosticLoad,
+	DiagnosticServices, DiagnosticContainers, DiagnosticIptables, DiagnosticKubelet}
+
+// nodeDiagnosticCommands are the commands of the diagnostics; kube marks those that
+// need the kubelet and containerd, which the external load balancer lacks.
+var nodeDiagnosticCommands = map[string]struct {
+	cmd  []string
+	kube bool
+}{
+	DiagnosticDisk:       {cmd: []string{"df", "-h"}},
+	DiagnosticInodes:     {cmd: []string{"df", "-i"}},
+	DiagnosticMemory:     {cmd: []string{"free", "-m"}},
+	DiagnosticLoad:       {cmd: []string{"cat", "/proc/loadavg"}},
+	DiagnosticServices:   {cmd: []string{"systemctl", "is-active", "containerd", "kubelet"}, kube: true},
+	DiagnosticContainers: {cmd: []string{"crictl", "ps", "-a"}, kube: true},
+	DiagnosticIptables:   {cmd: []string{"iptables-save"}},
+	DiagnosticKubelet: {cmd: []string{"journalctl", "-u", "kubelet", "-p", "err", "-n", "20", "--no-pager",
+		"-o", "short-iso"}, kube: true},
+}
+
+// diagnosticTimeout bounds each diagnostic command.
+const diagnosticTimeout = 30 * time.Second
+
+// NodeDiagnostic is the outcome of one command DebugNode ran.
+type NodeDiagnostic struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	OK      bool   `json:"ok"`
+	Output  string `json:"output"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NodeDebugReport is the result of DebugNode.
+type NodeDebugReport struct {
+	Cluster     string           `json:"cluster"`
+	Node        string           `json:"node"`
+	Role        string           `json:"role"`
+	Diagnostics []NodeDiagnostic `json:"diagnostics"`
+	// Skipped are requested diagnostics that do not apply to the node's role.
+	Skipped []string `json:"skipped,omitempty"`
+	Failed  int      `json:"failed"`
+}
+
+// DebugNode runs a fixed set of read-only diagnostics inside a node container, as
+// 'oc debug node' would from a privileged pod: disk and inode usage, memory, load,
+// the containerd and kubelet units, the CRI containers, a per-chain summary of the
+// iptables rules, and recent kubelet errors. checks selects a subset of
+// NodeDiagnostics (all when empty). A failing command is reported, not returned as an
+// error, so one broken tool does not hide the others. node is the container name or its
+// suffix after "<cluster>-".
+func (m *Manager) DebugNode(ctx context.Context, cluster, node string, checks []string) (*NodeDebugReport, error) {
+	for _, c := range checks {
+		if !slices.Contains(NodeDiagnostics, c) {
+			return nil, fmt.Errorf("invalid diagnostic %q; must be one of %s", c, strings.Join(NodeDiagnostics, ", "))
+		}
+	}
+	_, node, err := m.clusterNode(ctx, cluster, node)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NodeDebugReport{Cluster: cluster, Node: node, Role: NodeRole(node), Diagnostics: []NodeDiagnostic{}}
+	for _, name := range NodeDiagnostics {
+		if len(checks) > 0 && !slices.Contains(checks, name) {
+			continue
+		}
+		diag := nodeDiagnosticCommands[name]
+		if diag.kube && report.Role == RoleExternalLoadBalancer {
+			report.Skipped = append(report.Skipped, name)
+			continue
+		}
+		res := NodeDiagnostic{Name: name, Command: m.ExecCommandLine(node, diag.cmd), OK: true}
+		cmdCtx, cancel := context.WithTimeout(ctx, diagnosticTimeout)
+		out, err := m.ExecOnNode(cmdCtx, node, diag.cmd)
+		cancel()
+		res.Output = out
+		if err != nil {
+			res.OK = false
+			res.Error, _, _ = strings.Cut(err.Error(), "\nOutput:")
+			report.Failed++
+		} else if name == DiagnosticIptables {
+			res.Output = summarizeIptables(out)
+		}
+		report.Diagnostics = append(report.Diagnostics, res)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return report, nil
+}
+
+// summarizeIptables condenses iptables-save output to one line per table with its rule
+// count, followed by its chains with the most rules; the full dump of a node running
+// kube-proxy is thousands of lines.
+func summarizeIptables(dump string) string {
+	type table struct {
+		name  string
+		rules int
+		// chains counts the rules per chain.
+		chains map[string]int
+	}
+	var tables []*table
+	var cur *table
+	for _, line := range strings.Split(dump, "\n") {
+		switch {
+		case strings.HasPrefix(line, "*"):
+			cur = &table{name: strings.TrimPrefix(line, "*"), chains: map[string]int{}}
+			tables = append(tables, cur)
+		case cur == nil:
+		case strings.HasPrefix(line, ":"):
+			chain, _, _ := strings.Cut(strings.TrimPrefix(line, ":"), " ")
+			if _, ok := cur.chains[chain]; !ok {
+				cur.chains[chain] = 0
+			}
+		case strings.HasPrefix(line, "-A "):
+			chain, _, _ := strings.Cut(strings.TrimPrefix(line, "-A "), " ")
+			cur.chains[chain]++
+			cur.rules++
+		}
+	}
+
+	var b strings.Builder
+	for _, t := range tables {
+		fmt.Fprintf(&b, "%s: %d rules in %d chains\n", t.name, t.rules, len(t.chains))
+		chains := make([]string, 0, len(t.chains))
+		for c := range t.chains {
+			if t.chains[c] > 0 {
+				chains = append(chains, c)
+			}
+		}
+		sort.Slice(chains, func(i, j int) bool {
+			if t.chains[chains[i]] != t.chains[chains[j]] {
+				return t.chains[chains[i]] > t.chains[chains[j]]
+			}
+			return chains[i] < chains[j]
+		})
+		for _, c := range chains[:min(len(chains), 5)] {
+			fmt.Fprintf(&b, "  %s: %d\n", c, t.chains[c])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testIptablesSave = `# Generated by iptables-save
+*nat
+:PREROUTING ACCEPT [0:0]
+:KUBE-SERVICES - [0:0]
+:KUBE-SEP-ABC - [0:0]
+-A PREROUTING -m comment --comment "kubernetes service portals" -j KUBE-SERVICES
+-A KUBE-SERVICES -d 10.96.0.1/32 -p tcp -j KUBE-SVC-NPX
+-A KUBE-SERVICES -d 10.96.0.10/32 -p udp -j KUBE-SVC-TCO
+-A KUBE-SEP-ABC -p tcp -j DNAT --to-destination 172.18.0.2:6443
+COMMIT
+*filter
+:INPUT ACCEPT [0:0]
+:FORWARD DROP [0:0]
+COMMIT
+`
+
+func TestDebugNode(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"exec", "dev-worker", "df", "-h"}, out: []byte("/dev/vda1 59G 41G 18G 70% /\n")},
+		{name: "docker", args: []string{"exec", "dev-worker", "systemctl"},
+			out: []byte("active\ninactive\n"), err: fmt.Errorf("exit status 3")},
+		{name: "docker", args: []string{"exec", "dev-worker", "iptables-save"}, out: []byte(testIptablesSave)},
+	}}
+
+	report, err := newDockerManager(runner).DebugNode(context.Background(), "dev", "worker",
+		[]string{DiagnosticIptables, DiagnosticDisk, DiagnosticServices})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Node != "dev-worker" || report.Role != RoleWorker || len(report.Diagnostics) != 3 || report.Failed != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	names := []string{report.Diagnostics[0].Name, report.Diagnostics[1].Name, report.Diagnostics[2].Name}
+	if strings.Join(names, ",") != "disk,services,iptables" {
+		t.Errorf("diagnostics ran in order %v", names)
+	}
+	services := report.Diagnostics[1]
+	if services.OK || services.Output != "active\ninactive\n" || strings.Contains(services.Error, "Output:") {
+		t.Errorf("services = %+v", services)
+	}
+	want := "nat: 4 rules in 3 chains\n  KUBE-SERVICES: 2\n  KUBE-SEP-ABC: 1\n  PREROUTING: 1\nfilter: 0 rules in 2 chains\n"
+	if got := report.Diagnostics[2].Output; got != want {
+		t.Errorf("iptables summary =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDebugNode_LoadBalancer(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("ha-control-plane\nha-external-load-balancer\n")},
+		{name: "docker", args: []string{"exec", "ha-external-load-balancer"}, out: []byte("ok\n")},
+	}}
+	report, err := newDockerManager(runner).DebugNode(context.Background(), "ha", "external-load-balancer",
+		[]string{DiagnosticMemory, DiagnosticContainers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Diagnostics) != 1 || len(report.Skipped) != 1 || report.Skipped[0] != DiagnosticContainers {
+		t.Errorf("report = %+v", report)
+	}
+
+	if _, err := newDockerManager(runner).DebugNode(context.Background(), "ha", "control-plane", []string{"strace"}); err == nil {
+		t.Error("expected an error for an unknown diagnostic")
+	}
+}
@@ -0,0 +1,342 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// Checks run by DiagnoseNetworking, in the order traffic depends on them.
+const (
+	CheckDebugPods    = "debug-pods"
+	CheckClusterDNS   = "cluster-dns"
+	CheckExternalDNS  = "external-dns"
+	CheckPodToPod     = "pod-to-pod"
+	CheckPodToService = "pod-to-service"
+	CheckEgress       = "egress"
+	CheckPortMappings = "host-port-mappings"
+)
+
+// DefaultDiagnoseImage runs the debug pods; busybox has nslookup, wget and httpd.
+const DefaultDiagnoseImage = "busybox:1.36"
+
+// diagnoseNamespace holds the debug pods; it is deleted when the diagnosis finishes.
+const diagnoseNamespace = "mcp-netdiag"
+
+// dialTimeout opens the host-side connections for port mapping checks; replaced in tests.
+var dialTimeout = net.DialTimeout
+
+// DiagnoseOptions controls DiagnoseNetworking.
+type DiagnoseOptions struct {
+	// Image for the debug pods. Default: DefaultDiagnoseImage.
+	Image string
+	// EgressURL is fetched from a pod to test egress. Default: http://example.com.
+	EgressURL string
+	// Timeout bounds the wait for the debug pods. Default: 2 minutes.
+	Timeout time.Duration
+}
+
+// NetworkCheck is the outcome of one connectivity check.
+type NetworkCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+	// LikelyCauses are backend-specific explanations for a failed check.
+	LikelyCauses []string `json:"likely_causes,omitempty"`
+}
+
+// NetworkDiagnosis is the report returned by DiagnoseNetworking.
+type NetworkDiagnosis struct {
+	Cluster string         `json:"cluster"`
+	Backend string         `json:"backend"`
+	Checks  []NetworkCheck `json:"checks"`
+	// BrokenLayer is the first failed check, where troubleshooting should start.
+	BrokenLayer string `json:"broken_layer,omitempty"`
+}
+
+func (d *NetworkDiagnosis) add(ri rtdetect.RuntimeInfo, name string, err error, okDetail string) {
+	check := NetworkCheck{Name: name, OK: err == nil, Detail: okDetail}
+	if err != nil {
+		check.Detail = err.Error()
+		check.LikelyCauses = likelyNetworkCauses(ri, name)
+		if d.BrokenLayer == "" {
+			d.BrokenLayer = name
+		}
+	}
+	d.Checks = append(d.Checks, check)
+}
+
+// DiagnoseNetworking runs a server and a client debug pod in a temporary namespace and
+// tests, from the client, cluster DNS, external DNS, pod-to-pod and pod-to-service
+// traffic, and egress. It then checks from the host that every published port of the
+// node containers accepts connections. Failed checks carry likely causes for the
+// detected runtime backend.
+func (m *Manager) DiagnoseNetworking(ctx context.Context, clusterName string, opts DiagnoseOptions) (*NetworkDiagnosis, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if opts.Image == "" {
+		opts.Image = DefaultDiagnoseImage
+	}
+	if opts.EgressURL == "" {
+		opts.EgressURL = "http://example.com"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+	nodes, err := m.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q %w", clusterName, ErrClusterNotFound)
+	}
+
+	diag := &NetworkDiagnosis{Cluster: clusterName, Backend: string(m.runtime.Backend)}
+	if err := m.diagnoseInCluster(ctx, clusterName, opts, diag); err != nil {
+		return nil, err
+	}
+	m.checkPortMappings(ctx, nodes, diag)
+	return diag, nil
+}
+
+// diagnoseInCluster runs the pod-based checks. It only returns an error if the debug
+// namespace cannot be created; failures past that point are recorded as checks.
+func (m *Manager) diagnoseInCluster(ctx context.Context, clusterName string, opts DiagnoseOptions, diag *NetworkDiagnosis) error {
+	if _, err := m.ApplyManifest(ctx, clusterName, diagnoseManifest(opts.Image)); err != nil {
+		return fmt.Errorf("creating debug pods: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so cleanup still happens after a cancellation.
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := m.Kubectl(cleanupCtx, clusterName, "delete", "namespace", diagnoseNamespace, "--wait=false"); err != nil {
+			m.logger.Warn("removing network debug namespace failed", "cluster", clusterName, "error", err)
+		}
+	}()
+
+	_, err := m.Kubectl(ctx, clusterName, "wait", "--for=condition=Ready", "pod", "--all",
+		"-n", diagnoseNamespace, fmt.Sprintf("--timeout=%ds", int(opts.Timeout.Seconds())))
+	diag.add(m.runtime, CheckDebugPods, err, "debug pods scheduled and running")
+	if err != nil {
+		return nil
+	}
+
+	exec := func(args ...string) (string, error) {
+		full := append([]string{"exec", "-n", diagnoseNamespace, "client", "--"}, args...)
+		return m.Kubectl(ctx, clusterName, full...)
+	}
+
+	_, err = exec("nslookup", "kubernetes.default.svc.cluster.local")
+	diag.add(m.runtime, CheckClusterDNS, err, "kubernetes.default.svc.cluster.local resolved via CoreDNS")
+
+	egressHost := opts.EgressURL
+	if i := strings.Index(egressHost, "://"); i >= 0 {
+		egressHost = egressHost[i+3:]
+	}
+	egressHost, _, _ = strings.Cut(egressHost, "/")
+	egressHost, _, _ = strings.Cut(egressHost, ":")
+	_, err = exec("nslookup", egressHost)
+	diag.add(m.runtime, CheckExternalDNS, err, egressHost+" resolved")
+
+	podIP, err := m.Kubectl(ctx, clusterName, "get", "pod", "server", "-n", diagnoseNamespace,
+		"-o", "jsonpath={.status.podIP}")
+	if err == nil {
+		podIP = strings.TrimSpace(podIP)
+		_, err = exec("wget", "-T", "5", "-q", "-O", "-", fmt.Sprintf("http://%s:8080/", podIP))
+	}
+	diag.add(m.runtime, CheckPodToPod, err, "client reached server pod at "+podIP)
+
+	svc := fmt.Sprintf("server.%s.svc.cluster.local", diagnoseNamespace)
+	_, err = exec("wget", "-T", "5", "-q", "-O", "-", fmt.Sprintf("http://%s:8080/", svc))
+	diag.add(m.runtime, CheckPodToService, err, "client reached server through Service "+svc)
+
+	_, err = exec("wget", "-T", "10", "-q", "-O", "/dev/null", opts.EgressURL)
+	diag.add(m.runtime, CheckEgress, err, "fetched "+opts.EgressURL)
+	return nil
+}
+
+// checkPortMappings dials every host port published by the node containers. A refused
+// or timed-out connection means the runtime is not forwarding the port to the host.
+func (m *Manager) checkPortMappings(ctx context.Context, nodes []string, diag *NetworkDiagnosis) {
+	var reached, failed []string
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		out, err := m.runner.Run(ctx, m.runtimeBin(), "port", node)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: listing ports: %v", node, err))
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			// "80/tcp -> 0.0.0.0:80"
+			containerPort, hostAddr, ok := strings.Cut(line, " -> ")
+			if !ok || !strings.HasSuffix(containerPort, "/tcp") {
+				continue
+			}
+			host, port, err := net.SplitHostPort(strings.TrimSpace(hostAddr))
+			if err != nil {
+				continue
+			}
+			if host == "0.0.0.0" || host == "::" || host == "" {
+				host = "127.0.0.1"
+			}
+			addr := net.JoinHostPort(host, port)
+			desc := fmt.Sprintf("%s %s -> %s", node, containerPort, addr)
+			// IPv4 and IPv6 wildcard bindings of the same port are one check
+			if seen[desc] {
+				continue
+			}
+			seen[desc] = true
+			conn, err := dialTimeout("tcp", addr, 3*time.Second)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", desc, err))
+				continue
+			}
+			conn.Close()
+			reached = append(reached, desc)
+		}
+	}
+
+	var err error
+	if len(failed) > 0 {
+		err = fmt.Errorf("unreachable from the host: %s", strings.Join(failed, "; "))
+	}
+	detail := "no TCP ports published by the node containers"
+	if len(reached) > 0 {
+		detail = "reachable from the host: " + strings.Join(reached, "; ")
+	}
+	diag.add(m.runtime, CheckPortMappings, err, detail)
+}
+
+// diagnoseManifest returns the debug namespace with an HTTP server pod and Service, and
+// a client pod, preferably on another node so pod-to-pod traffic crosses nodes.
+func diagnoseManifest(image string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: %[1]s
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: server
+  namespace: %[1]s
+  labels:
+    app: mcp-netdiag-server
+spec:
+  containers:
+  - name: httpd
+    image: %[2]s
+    command: ["sh", "-c", "echo ok > /tmp/index.html && httpd -f -p 8080 -h /tmp"]
+    ports:
+    - containerPort: 8080
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: server
+  namespace: %[1]s
+spec:
+  selector:
+    app: mcp-netdiag-server
+  ports:
+  - port: 8080
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: client
+  namespace: %[1]s
+spec:
+  affinity:
+    podAntiAffinity:
+      preferredDuringSchedulingIgnoredDuringExecution:
+      - weight: 100
+        podAffinityTerm:
+          topologyKey: kubernetes.io/hostname
+          labelSelector:
+            matchLabels:
+              app: mcp-netdiag-server
+  containers:
+  - name: client
+    image: %[2]s
+    command: ["sleep", "3600"]
+`, diagnoseNamespace, image)
+}
+
+// likelyNetworkCauses explains a failed check for the detected runtime backend.
+func likelyNetworkCauses(ri rtdetect.RuntimeInfo, check string) []string {
+	vm := ri.Backend != rtdetect.BackendNative
+	podman := ri.Runtime == rtdetect.RuntimePodman
+	var causes []string
+	add := func(cause string) { causes = append(causes, cause) }
+
+	switch check {
+	case CheckDebugPods:
+		add("The debug image could not be pulled: the cluster may be offline or need a registry mirror " +
+			"(pass an image available to the nodes).")
+		add("The CNI (kindnet) is not running, so pods stay ContainerCreating: check 'kubectl get pods -n kube-system'.")
+	case CheckClusterDNS:
+		add("CoreDNS pods are not running or not ready: check 'kubectl get pods -n kube-system -l k8s-app=kube-dns'.")
+		add("Pod-to-service traffic is broken (kube-proxy), so CoreDNS's Service IP is unreachable; see the pod-to-service check.")
+	case CheckExternalDNS:
+		add("CoreDNS forwards to the node's resolver, which inherits the runtime's DNS: a VPN or corporate DNS " +
+			"the runtime can't reach breaks external names.")
+		if vm {
+			add(fmt.Sprintf("On %s, name resolution goes through the VM; check DNS inside the VM.", ri.Backend))
+		} else {
+			add("On native Linux, a host resolv.conf pointing at a local stub (127.0.0.53) is not reachable from " +
+				"containers unless the runtime rewrites it; check the node's /etc/resolv.conf.")
+		}
+	case CheckPodToPod:
+		add("kindnet is not running on every node: check 'kubectl get pods -n kube-system -l app=kindnet'.")
+		if podman {
+			add("If Podman runs rootless, its networking (slirp4netns/pasta) can break cross-node routes; use a rootful " +
+				"Podman machine or a single-node cluster.")
+		}
+		if !vm {
+			add("Host firewall rules (ufw, firewalld) or a missing br_netfilter module can drop traffic between node containers.")
+		}
+	case CheckPodToService:
+		add("kube-proxy is not running or failed to program rules: check 'kubectl logs -n kube-system -l k8s-app=kube-proxy'.")
+		if !vm {
+			add("The host's iptables/nftables backend may conflict with kube-proxy's mode; check for " +
+				"'iptables-legacy' vs 'iptables-nft' mismatches.")
+		}
+	case CheckEgress:
+		add("A corporate proxy is required: create the cluster with HTTP_PROXY/HTTPS_PROXY/NO_PROXY set so " +
+			"kind passes them to the nodes.")
+		if vm {
+			add(fmt.Sprintf("On %s, egress is NATed through the VM; check that the VM itself can reach the internet.", ri.Backend))
+		} else {
+			add("IP forwarding or masquerading for the runtime bridge is disabled or blocked by the host firewall.")
+		}
+		if podman {
+			add("If Podman runs rootless, egress goes through slirp4netns/pasta; check that it is installed and working.")
+		}
+	case CheckPortMappings:
+		add("Ports published by extraPortMappings are fixed at creation; a port added later needs a recreated cluster.")
+		switch ri.Backend {
+		case rtdetect.BackendColima, rtdetect.BackendLima:
+			add("Lima-based VMs forward ports asynchronously; retry, and check the VM's port forwarding if it persists.")
+		case rtdetect.BackendPodmanMachine:
+			add("Podman machine forwards ports only in rootful mode; run 'podman machine set --rootful'.")
+		case rtdetect.BackendWSL:
+			if ri.WSL.Mirrored() {
+				add("WSL mirrored networking is enabled; inbound LAN traffic is blocked by the Hyper-V firewall unless a rule allows the port.")
+			} else {
+				add("WSL forwards localhost only; mappings on other addresses need Windows port proxy rules.")
+			}
+		case rtdetect.BackendDockerDesktop:
+			add("Docker Desktop needs its privileged helper for ports below 1024; check Docker Desktop's settings.")
+		}
+		if podman {
+			add("If Podman runs rootless, it cannot bind ports below 1024 unless net.ipv4.ip_unprivileged_port_start is lowered.")
+		}
+	}
+	return causes
+}
@@ -0,0 +1,126 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// loggingRunner wraps mockRunner and records every command line.
+type loggingRunner struct {
+	*mockRunner
+	calls []string
+}
+
+func (r *loggingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, name+" "+strings.Join(args, " "))
+	return r.mockRunner.Run(ctx, name, args...)
+}
+
+func (r *loggingRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	r.calls = append(r.calls, name+" "+strings.Join(args, " "))
+	return r.mockRunner.RunSeparate(ctx, name, args...)
+}
+
+func TestDiagnoseNetworking(t *testing.T) {
+	var dialed []string
+	dialTimeout = func(_, addr string, _ time.Duration) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		if addr == "127.0.0.1:8443" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+	defer func() { dialTimeout = net.DialTimeout }()
+
+	kubectl := func(args ...string) []string {
+		return append([]string{"--kubeconfig", "*"}, args...)
+	}
+	exec := func(args ...string) []string {
+		return kubectl(append([]string{"exec", "-n", diagnoseNamespace, "client", "--"}, args...)...)
+	}
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+		{name: "kubectl", args: kubectl("apply"), out: []byte("created\n")},
+		{name: "kubectl", args: kubectl("wait"), out: []byte("condition met\n")},
+		{name: "kubectl", args: exec("nslookup", "kubernetes.default.svc.cluster.local"), out: []byte("Address: 10.96.0.1\n")},
+		{name: "kubectl", args: exec("nslookup", "example.com"), out: []byte("Address: 93.184.215.14\n")},
+		{name: "kubectl", args: kubectl("get", "pod", "server"), out: []byte("10.244.1.5")},
+		{name: "kubectl", args: exec("wget", "-T", "5", "-q", "-O", "-", "http://10.244.1.5:8080/"), out: []byte("ok\n")},
+		{name: "kubectl", args: exec("wget", "-T", "5", "-q", "-O", "-", "http://server.mcp-netdiag.svc.cluster.local:8080/"),
+			out: []byte("wget: download timed out\n"), err: fmt.Errorf("exit status 1")},
+		{name: "kubectl", args: exec("wget", "-T", "10"), out: []byte("")},
+		{name: "kubectl", args: kubectl("delete", "namespace", diagnoseNamespace), out: []byte("deleted\n")},
+		{name: "docker", args: []string{"port", "dev-control-plane"}, out: []byte(
+			"6443/tcp -> 127.0.0.1:41234\n80/tcp -> 0.0.0.0:80\n80/tcp -> [::]:80\n443/tcp -> 0.0.0.0:8443\n")},
+		{name: "docker", args: []string{"port", "dev-worker"}, out: []byte("")},
+	}}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker, Backend: rtdetect.BackendColima}, nil)
+
+	diag, err := mgr.DiagnoseNetworking(context.Background(), "dev", DiagnoseOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make(map[string]NetworkCheck)
+	for _, c := range diag.Checks {
+		got[c.Name] = c
+	}
+	for name, ok := range map[string]bool{
+		CheckDebugPods: true, CheckClusterDNS: true, CheckExternalDNS: true, CheckPodToPod: true,
+		CheckPodToService: false, CheckEgress: true, CheckPortMappings: false,
+	} {
+		if got[name].OK != ok {
+			t.Errorf("%s ok = %v, want %v (%s)", name, got[name].OK, ok, got[name].Detail)
+		}
+	}
+	if diag.BrokenLayer != CheckPodToService {
+		t.Errorf("broken layer = %q", diag.BrokenLayer)
+	}
+	if !strings.Contains(strings.Join(got[CheckPodToService].LikelyCauses, " "), "kube-proxy") {
+		t.Errorf("pod-to-service causes = %v", got[CheckPodToService].LikelyCauses)
+	}
+	if !strings.Contains(strings.Join(got[CheckPortMappings].LikelyCauses, " "), "Lima-based") {
+		t.Errorf("port mapping causes should be backend-specific: %v", got[CheckPortMappings].LikelyCauses)
+	}
+	if len(dialed) != 3 {
+		t.Errorf("dialed %v, want each published port once", dialed)
+	}
+	if !strings.Contains(strings.Join(runner.calls, "\n"), "delete namespace "+diagnoseNamespace) {
+		t.Error("debug namespace should be cleaned up")
+	}
+}
+
+func TestDiagnoseNetworking_PodsNotReady(t *testing.T) {
+	dialTimeout = func(string, string, time.Duration) (net.Conn, error) { return nil, fmt.Errorf("unused") }
+	defer func() { dialTimeout = net.DialTimeout }()
+
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\n")},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "apply"}, out: []byte("created\n")},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "wait"}, err: fmt.Errorf("timed out")},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "delete"}, out: []byte("deleted\n")},
+		{name: "docker", args: []string{"port"}, out: []byte("")},
+	}}
+
+	diag, err := newDockerManager(runner).DiagnoseNetworking(context.Background(), "dev", DiagnoseOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diag.BrokenLayer != CheckDebugPods {
+		t.Errorf("broken layer = %q", diag.BrokenLayer)
+	}
+	// pod checks are skipped; only the host-side check still runs
+	if len(diag.Checks) != 2 || diag.Checks[1].Name != CheckPortMappings || !diag.Checks[1].OK {
+		t.Errorf("checks = %+v", diag.Checks)
+	}
+}
@@ -0,0 +1,255 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sizes above which DiskUsage suggests reclaiming space inside a node.
+const (
+	largeNodeStoreBytes = 5 << 30
+	largeNodeLogBytes   = 1 << 30
+)
+
+// HostImageUsage is a node image in the host's container runtime.
+type HostImageUsage struct {
+	Image string `json:"image"`
+	ID    string `json:"id,omitempty"`
+	Size  int64  `json:"size_bytes"`
+	// InUse is set when a node container of any cluster runs the image.
+	InUse bool `json:"in_use"`
+}
+
+// NodeDiskUsage is the space a node container takes on the host.
+type NodeDiskUsage struct {
+	Name    string `json:"name"`
+	Cluster string `json:"cluster"`
+	Image   string `json:"image"`
+	Status  string `json:"status"`
+	// WritableBytes is the container's writable layer.
+	WritableBytes int64 `json:"writable_bytes"`
+	// Volumes are the runtime volumes mounted into the node; Kind keeps /var in one.
+	Volumes []string `json:"volumes,omitempty"`
+	// VarBytes, ContainerdBytes, and LogBytes are measured inside running nodes.
+	VarBytes        int64 `json:"var_bytes,omitempty"`
+	ContainerdBytes int64 `json:"containerd_bytes,omitempty"`
+	LogBytes        int64 `json:"log_bytes,omitempty"`
+}
+
+// DiskUsageReport summarizes the disk space used by Kind on the host.
+type DiskUsageReport struct {
+	NodeImages []HostImageUsage `json:"node_images"`
+	Nodes      []NodeDiskUsage  `json:"nodes"`
+	// DanglingVolumes are volumes no container uses, such as leftovers of deleted nodes.
+	DanglingVolumes []string `json:"dangling_volumes,omitempty"`
+	TotalBytes      int64    `json:"total_bytes"`
+	Suggestions     []string `json:"suggestions,omitempty"`
+	Warnings        []string `json:"warnings,omitempty"`
+}
+
+// DiskUsage reports the space used by node images on the host, by the writable layer
+// and /var volume of every node container, and by the containerd store and logs inside
+// running nodes, with suggestions for reclaiming it.
+func (m *Manager) DiskUsage(ctx context.Context) (*DiskUsageReport, error) {
+	clusters, err := m.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DiskUsageReport{NodeImages: []HostImageUsage{}, Nodes: []NodeDiskUsage{}}
+	for _, cluster := range clusters {
+		nodes, err := m.GetClusterNodes(ctx, cluster)
+		if err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+			continue
+		}
+		usage, err := m.nodeDiskUsage(ctx, cluster, nodes)
+		if err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		}
+		report.Nodes = append(report.Nodes, usage...)
+	}
+
+	report.NodeImages = m.hostNodeImages(ctx, report.Nodes)
+	if out, err := m.runner.Run(ctx, m.runtimeBin(), "volume", "ls", "-q", "--filter", "dangling=true"); err == nil {
+		report.DanglingVolumes = strings.Fields(string(out))
+	}
+
+	for _, img := range report.NodeImages {
+		report.TotalBytes += img.Size
+	}
+	for _, n := range report.Nodes {
+		report.TotalBytes += n.WritableBytes + n.VarBytes
+	}
+	report.Suggestions = diskSuggestions(m.runtimeBin(), report)
+	return report, nil
+}
+
+// nodeDiskUsage inspects the node containers of a cluster in one runtime call and
+// measures /var inside the running ones, except the load balancer, which has none.
+func (m *Manager) nodeDiskUsage(ctx context.Context, cluster string, nodes []string) ([]NodeDiskUsage, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	format := `{{.Name}}|{{.Config.Image}}|{{.State.Status}}|{{.SizeRw}}|` +
+		`{{range .Mounts}}{{if eq .Type "volume"}}{{.Name}},{{end}}{{end}}`
+	args := append([]string{"inspect", "--size", "--format", format}, nodes...)
+	out, err := m.runner.Run(ctx, m.runtimeBin(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting nodes of %s: %w\nOutput: %s", cluster, err, string(out))
+	}
+
+	var usage []NodeDiskUsage
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "|", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		n := NodeDiskUsage{
+			Name:    strings.TrimPrefix(parts[0], "/"),
+			Cluster: cluster,
+			Image:   parts[1],
+			Status:  parts[2],
+		}
+		n.WritableBytes, _ = strconv.ParseInt(parts[3], 10, 64)
+		for _, v := range strings.Split(parts[4], ",") {
+			if v != "" {
+				n.Volumes = append(n.Volumes, v)
+			}
+		}
+		if n.Status == "running" && NodeRole(n.Name) != RoleExternalLoadBalancer {
+			m.measureNodeVar(ctx, &n)
+		}
+		usage = append(usage, n)
+	}
+	return usage, nil
+}
+
+// measureNodeVar fills in the /var usage of a running node with du. du exits non-zero
+// when files vanish while it runs, so whatever it printed is used.
+func (m *Manager) measureNodeVar(ctx context.Context, n *NodeDiskUsage) {
+	out, _ := m.ExecOnNode(ctx, n.Name, []string{"du", "-sxb", "/var", "/var/lib/containerd", "/var/log"})
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[1] {
+		case "/var":
+			n.VarBytes = size
+		case "/var/lib/containerd":
+			n.ContainerdBytes = size
+		case "/var/log":
+			n.LogBytes = size
+		}
+	}
+}
+
+// hostNodeImages returns the kindest/node images on the host together with any other
+// image a node runs, with their sizes.
+func (m *Manager) hostNodeImages(ctx context.Context, nodes []NodeDiskUsage) []HostImageUsage {
+	images := []HostImageUsage{}
+	seen := make(map[string]int)
+	add := func(ref, id string) {
+		if _, ok := seen[ref]; ok || ref == "" {
+			return
+		}
+		seen[ref] = len(images)
+		images = append(images, HostImageUsage{Image: ref, ID: id})
+	}
+
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "images", "--format", "{{.Repository}}:{{.Tag}}|{{.ID}}", "kindest/node")
+	if err == nil {
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			ref, id, _ := strings.Cut(strings.TrimSpace(line), "|")
+			if !strings.HasSuffix(ref, ":<none>") {
+				add(ref, id)
+			}
+		}
+	}
+	for _, n := range nodes {
+		if NodeRole(n.Name) != RoleExternalLoadBalancer {
+			add(n.Image, "")
+		}
+	}
+	// Node containers may name their image with or without the docker.io prefix.
+	for _, n := range nodes {
+		for i := range images {
+			if normalizeImageRef(images[i].Image) == normalizeImageRef(n.Image) {
+				images[i].InUse = true
+			}
+		}
+	}
+
+	for i := range images {
+		out, err := m.runner.Run(ctx, m.runtimeBin(), "image", "inspect", "--format", "{{.Size}}", images[i].Image)
+		if err == nil {
+			images[i].Size, _ = strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		}
+	}
+	sort.SliceStable(images, func(i, j int) bool { return images[i].Size > images[j].Size })
+	return images
+}
+
+// diskSuggestions lists ways to reclaim the space in report.
+func diskSuggestions(bin string, report *DiskUsageReport) []string {
+	var suggestions []string
+	for _, img := range report.NodeImages {
+		if !img.InUse && img.Size > 0 {
+			suggestions = append(suggestions, fmt.Sprintf("node image %s (%s) is not used by any cluster; remove it with '%s rmi %s'",
+				img.Image, formatBytes(img.Size), bin, img.Image))
+		}
+	}
+
+	stopped := make(map[string]bool)
+	for _, n := range report.Nodes {
+		if n.Status != "running" && n.Status != "paused" {
+			stopped[n.Cluster] = true
+		}
+		if n.ContainerdBytes > largeNodeStoreBytes {
+			suggestions = append(suggestions, fmt.Sprintf("node %s holds %s of images; run prune_node_images on cluster %s "+
+				"to remove the ones no container uses", n.Name, formatBytes(n.ContainerdBytes), n.Cluster))
+		}
+		if n.LogBytes > largeNodeLogBytes {
+			suggestions = append(suggestions, fmt.Sprintf("node %s has %s of logs in /var/log; delete noisy pods or "+
+				"recreate the cluster to clear them", n.Name, formatBytes(n.LogBytes)))
+		}
+	}
+	clusters := make([]string, 0, len(stopped))
+	for c := range stopped {
+		clusters = append(clusters, c)
+	}
+	sort.Strings(clusters)
+	for _, c := range clusters {
+		suggestions = append(suggestions, fmt.Sprintf("cluster %s is stopped but keeps its node volumes; delete it with "+
+			"delete_cluster if it is no longer needed", c))
+	}
+
+	if len(report.DanglingVolumes) > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("%d volume(s) are not used by any container, possibly left over from "+
+			"deleted nodes; review them with '%s volume ls --filter dangling=true' and remove them with '%s volume prune'",
+			len(report.DanglingVolumes), bin, bin))
+	}
+	return suggestions
+}
+
+// formatBytes formats a size with binary units (e.g. "1.5 GiB").
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,56 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "clusters"}, out: []byte("dev\nold\n")},
+		{name: "kind", args: []string{"get", "nodes", "--name", "dev"}, out: []byte("dev-control-plane\n")},
+		{name: "kind", args: []string{"get", "nodes", "--name", "old"}, out: []byte("old-control-plane\n")},
+		{name: "docker", args: []string{"inspect", "--size", "--format", "*", "dev-control-plane"},
+			out: []byte("/dev-control-plane|kindest/node:v1.31.0|running|2048|vol-dev,\n")},
+		{name: "docker", args: []string{"inspect", "--size", "--format", "*", "old-control-plane"},
+			out: []byte("/old-control-plane|kindest/node:v1.31.0|exited|1024|vol-old,\n")},
+		{name: "docker", args: []string{"exec", "dev-control-plane", "du"},
+			out: []byte("7000000000\t/var\n6000000000\t/var/lib/containerd\n1000\t/var/log\n")},
+		{name: "docker", args: []string{"images"}, out: []byte("kindest/node:v1.31.0|sha256:a\nkindest/node:v1.29.0|sha256:b\n")},
+		{name: "docker", args: []string{"image", "inspect", "--format", "{{.Size}}", "kindest/node:v1.31.0"}, out: []byte("1000000000\n")},
+		{name: "docker", args: []string{"image", "inspect", "--format", "{{.Size}}", "kindest/node:v1.29.0"}, out: []byte("900000000\n")},
+		{name: "docker", args: []string{"volume", "ls"}, out: []byte("abc\n")},
+	}}
+
+	report, err := newDockerManager(runner).DiskUsage(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Nodes) != 2 || report.Nodes[0].ContainerdBytes != 6000000000 || report.Nodes[0].Volumes[0] != "vol-dev" {
+		t.Errorf("nodes = %+v", report.Nodes)
+	}
+	if len(report.NodeImages) != 2 || !report.NodeImages[0].InUse || report.NodeImages[1].InUse {
+		t.Errorf("node images = %+v", report.NodeImages)
+	}
+	if want := int64(1900000000 + 2048 + 1024 + 7000000000); report.TotalBytes != want {
+		t.Errorf("total = %d, want %d", report.TotalBytes, want)
+	}
+
+	suggestions := strings.Join(report.Suggestions, "\n")
+	for _, want := range []string{"docker rmi kindest/node:v1.29.0", "prune_node_images on cluster dev",
+		"cluster old is stopped", "1 volume(s)"} {
+		if !strings.Contains(suggestions, want) {
+			t.Errorf("suggestions missing %q:\n%s", want, suggestions)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{512: "512 B", 1536: "1.5 KiB", 5 << 30: "5.0 GiB"}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fields reported by DiffConfig.
+const (
+	DriftNodeCount   = "node-count"
+	DriftImage       = "image"
+	DriftMount       = "mount"
+	DriftPortMapping = "port-mapping"
+	DriftMirror      = "registry-mirror"
+)
+
+// mirrorHostsDir is where containerd looks up per-registry mirror configuration.
+const mirrorHostsDir = "/etc/containerd/certs.d"
+
+// ConfigDrift is a difference between a cluster's desired config and its live nodes.
+type ConfigDrift struct {
+	Field   string `json:"field"`
+	Node    string `json:"node,omitempty"`
+	Desired string `json:"desired,omitempty"`
+	Live    string `json:"live,omitempty"`
+	// RequiresRecreate is set for settings Kind fixes when it creates the node containers.
+	RequiresRecreate bool   `json:"requires_recreate"`
+	Remedy           string `json:"remedy"`
+}
+
+// DriftReport is the result of DiffConfig.
+type DriftReport struct {
+	Cluster string `json:"cluster"`
+	// Source says where the desired config came from ("stored" or "provided").
+	Source           string        `json:"source,omitempty"`
+	InSync           bool          `json:"in_sync"`
+	RequiresRecreate bool          `json:"requires_recreate"`
+	Drift            []ConfigDrift `json:"drift"`
+}
+
+// liveNode is the part of a node container's inspect output DiffConfig compares.
+type liveNode struct {
+	Config struct {
+		Image string `json:"Image"`
+	} `json:"Config"`
+	HostConfig struct {
+		PortBindings map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string `json:"HostPort"`
+		} `json:"PortBindings"`
+	} `json:"HostConfig"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+		RW          bool   `json:"RW"`
+	} `json:"Mounts"`
+}
+
+// legacyMirrorPattern matches registries configured inline in containerdConfigPatches.
+var legacyMirrorPattern = regexp.MustCompile(`registry\.mirrors\."([^"]+)"`)
+
+// DiffConfig compares a desired Kind config, plus the registries that should have
+// mirrors configured, with the running cluster's node containers: node counts per
+// role, pinned node images, extra mounts, port mappings and containerd mirror
+// hosts. Mirrors can be fixed on the live nodes; everything else requires recreation.
+func (m *Manager) DiffConfig(ctx context.Context, clusterName, configYAML string, mirrors []string) (*DriftReport, error) {
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(cfg.Nodes) == 0 {
+		// Kind's default is a single control-plane node.
+		cfg.Nodes = []NodeConfig{{Role: RoleControlPlane}}
+	}
+
+	nodes, err := m.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q %w", clusterName, ErrClusterNotFound)
+	}
+
+	desired := make(map[string][]NodeConfig)
+	for _, n := range cfg.Nodes {
+		desired[n.Role] = append(desired[n.Role], n)
+	}
+	live := make(map[string][]string)
+	for _, n := range nodes {
+		if role := NodeRole(n); role != RoleExternalLoadBalancer {
+			live[role] = append(live[role], n)
+		}
+	}
+
+	report := &DriftReport{Cluster: clusterName, Drift: []ConfigDrift{}}
+	add := func(d ConfigDrift) {
+		report.Drift = append(report.Drift, d)
+		report.RequiresRecreate = report.RequiresRecreate || d.RequiresRecreate
+	}
+
+	wantMirrors := desiredMirrors(cfg, mirrors)
+	for _, role := range []string{RoleControlPlane, RoleWorker} {
+		want, have := desired[role], live[role]
+		sortNodeNames(have)
+		if len(want) != len(have) {
+			add(ConfigDrift{
+				Field: DriftNodeCount, Desired: fmt.Sprintf("%d %s", len(want), role),
+				Live: fmt.Sprintf("%d %s", len(have), role), RequiresRecreate: true,
+				Remedy: "Kind cannot add or remove nodes; recreate the cluster ('recreate_cluster')",
+			})
+		}
+		for i, node := range have {
+			info, err := m.inspectLiveNode(ctx, node)
+			if err != nil {
+				return nil, err
+			}
+			if i < len(want) {
+				for _, d := range diffNode(want[i], info, role) {
+					d.Node = node
+					add(d)
+				}
+			}
+			for _, d := range m.diffMirrors(ctx, node, wantMirrors) {
+				add(d)
+			}
+		}
+	}
+
+	report.InSync = len(report.Drift) == 0
+	return report, nil
+}
+
+// inspectLiveNode returns a node container's image, port bindings and mounts.
+func (m *Manager) inspectLiveNode(ctx context.Context, node string) (*liveNode, error) {
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "inspect", node)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting node %q: %w\nOutput: %s", node, err, string(out))
+	}
+	var info []liveNode
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("parsing inspect output for node %q: %w", node, err)
+	}
+	if len(info) == 0 {
+		return nil, fmt.Errorf("node %q not found", node)
+	}
+	return &info[0], nil
+}
+
+// diffNode compares one desired node with its live container.
+func diffNode(want NodeConfig, info *liveNode, role string) []ConfigDrift {
+	var drift []ConfigDrift
+	recreate := func(field, desired, live string) {
+		drift = append(drift, ConfigDrift{
+			Field: field, Desired: desired, Live: live, RequiresRecreate: true,
+			Remedy: "fixed when the node container is created; recreate the cluster ('recreate_cluster')",
+		})
+	}
+
+	// Nodes without a pinned image follow the Kind release's default, so only pins are compared.
+	if want.Image != "" && !sameImage(want.Image, info.Config.Image) {
+		recreate(DriftImage, want.Image, info.Config.Image)
+	}
+
+	liveMounts := make(map[string]string)
+	for _, mnt := range info.Mounts {
+		// Kind adds /lib/modules and the /var volume to every node.
+		if mnt.Type != "bind" || mnt.Destination == "/lib/modules" {
+			continue
+		}
+		liveMounts[mnt.Destination] = formatMount(mnt.Source, mnt.Destination, !mnt.RW)
+	}
+	for _, mnt := range want.ExtraMounts {
+		wantStr := formatMount(ExpandHome(mnt.HostPath), mnt.ContainerPath, mnt.ReadOnly)
+		have, ok := liveMounts[mnt.ContainerPath]
+		delete(liveMounts, mnt.ContainerPath)
+		if !ok || !sameMount(wantStr, have) {
+			recreate(DriftMount, wantStr, have)
+		}
+	}
+	for _, dest := range sortedKeys(liveMounts) {
+		recreate(DriftMount, "", liveMounts[dest])
+	}
+
+	var livePorts []string
+	for port, bindings := range info.HostConfig.PortBindings {
+		// The API server port is published by Kind itself, not by extraPortMappings.
+		if port == "6443/tcp" && role == RoleControlPlane {
+			continue
+		}
+		containerPort, proto, _ := strings.Cut(port, "/")
+		for _, b := range bindings {
+			hostPort, _ := strconv.Atoi(b.HostPort)
+			livePorts = append(livePorts, formatPort(b.HostIP, hostPort, containerPort, proto))
+		}
+	}
+	sort.Strings(livePorts)
+	for _, pm := range want.ExtraPortMappings {
+		wantStr := formatPort(pm.ListenAddress, pm.HostPort, strconv.Itoa(pm.ContainerPort), pm.Protocol)
+		idx := -1
+		for i, have := range livePorts {
+			if samePort(wantStr, have) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			recreate(DriftPortMapping, wantStr, "")
+			continue
+		}
+		livePorts = append(livePorts[:idx], livePorts[idx+1:]...)
+	}
+	for _, have := range livePorts {
+		recreate(DriftPortMapping, "", have)
+	}
+	return drift
+}
+
+// diffMirrors compares the registries with a hosts directory on a node with the desired ones.
+func (m *Manager) diffMirrors(ctx context.Context, node string, want []string) []ConfigDrift {
+	out, err := m.ExecOnNode(ctx, node, []string{"sh", "-c", "ls -1 " + mirrorHostsDir + " 2>/dev/null || true"})
+	if err != nil {
+		m.logger.Debug("listing mirror hosts", "node", node, "error", err)
+	}
+	var have []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			have = append(have, line)
+		}
+	}
+
+	var drift []ConfigDrift
+	for _, reg := range want {
+		if !contains(have, reg) {
+			drift = append(drift, ConfigDrift{
+				Field: DriftMirror, Node: node, Desired: reg,
+				Remedy: "apply it to the running nodes with 'configure_registry_mirrors'",
+			})
+		}
+	}
+	for _, reg := range have {
+		if !contains(want, reg) {
+			drift = append(drift, ConfigDrift{
+				Field: DriftMirror, Node: node, Live: reg,
+				Remedy: fmt.Sprintf("not recorded for this cluster; remove %s/%s on the node or re-apply it "+
+					"with 'configure_registry_mirrors' to record it", mirrorHostsDir, reg),
+			})
+		}
+	}
+	return drift
+}
+
+// desiredMirrors merges the recorded mirror registries with those a config sets up
+// itself, through inline containerd patches or mounts into the hosts directory.
+func desiredMirrors(cfg ClusterConfig, recorded []string) []string {
+	var mirrors []string
+	addMirror := func(reg string) {
+		if reg != "" && !contains(mirrors, reg) {
+			mirrors = append(mirrors, reg)
+		}
+	}
+	for _, reg := range recorded {
+		addMirror(reg)
+	}
+	for _, patch := range cfg.ContainerdConfigPatches {
+		for _, match := range legacyMirrorPattern.FindAllStringSubmatch(patch, -1) {
+			addMirror(match[1])
+		}
+	}
+	for _, node := range cfg.Nodes {
+		for _, mnt := range node.ExtraMounts {
+			if rest, ok := strings.CutPrefix(mnt.ContainerPath, mirrorHostsDir+"/"); ok {
+				addMirror(strings.Split(rest, "/")[0])
+			}
+		}
+	}
+	sort.Strings(mirrors)
+	return mirrors
+}
+
+// sortNodeNames orders Kind node names by their index suffix (worker, worker2, ..., worker10).
+func sortNodeNames(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+}
+
+func formatMount(source, dest string, readOnly bool) string {
+	s := filepath.Clean(source) + ":" + dest
+	if readOnly {
+		s += ":ro"
+	}
+	return s
+}
+
+// sameMount compares formatted mounts. Desktop VMs report the host path under a
+// prefix such as /host_mnt, so a live source ending in the desired one matches.
+func sameMount(want, have string) bool {
+	return want == have || strings.HasSuffix(have, want) && strings.HasPrefix(want, "/")
+}
+
+// formatPort renders a port mapping as listen:hostPort->containerPort/protocol, with
+// Kind's defaults filled in. A hostPort of 0 lets the runtime pick one.
+func formatPort(listen string, hostPort int, containerPort, proto string) string {
+	if listen == "" {
+		listen = "0.0.0.0"
+	}
+	if proto == "" {
+		proto = "tcp"
+	}
+	host := "random"
+	if hostPort != 0 {
+		host = strconv.Itoa(hostPort)
+	}
+	return fmt.Sprintf("%s:%s->%s/%s", listen, host, containerPort, strings.ToLower(proto))
+}
+
+func samePort(want, have string) bool {
+	if want == have {
+		return true
+	}
+	if !strings.Contains(want, ":random->") {
+		return false
+	}
+	wantListen, wantRest, _ := strings.Cut(want, ":random")
+	i := strings.LastIndex(have, "->")
+	return i >= 0 && strings.HasPrefix(have, wantListen+":") && have[i:] == wantRest
+}
+
+// sameImage compares image references, ignoring the digest when only one side has it.
+func sameImage(want, have string) bool {
+	want, have = normalizeImageRef(want), normalizeImageRef(have)
+	if strings.Contains(want, "@") != strings.Contains(have, "@") {
+		want, _, _ = strings.Cut(want, "@")
+		have, _, _ = strings.Cut(have, "@")
+	}
+	return want == have
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
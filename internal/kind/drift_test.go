@@ -0,0 +1,131 @@
+package kind
+
+import (
+	"context"
+	"testing"
+)
+
+const driftConfig = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+  - role: control-plane
+    image: kindest/node:v1.31.0
+    extraPortMappings:
+      - containerPort: 80
+        hostPort: 8080
+      - containerPort: 443
+        hostPort: 0
+    extraMounts:
+      - hostPath: /src
+        containerPath: /src
+        readOnly: true
+  - role: worker
+    image: kindest/node:v1.31.0
+  - role: worker
+    image: kindest/node:v1.31.0
+`
+
+const driftControlPlane = `[{
+  "Config": {"Image": "kindest/node:v1.31.0@sha256:abc"},
+  "HostConfig": {"PortBindings": {
+    "6443/tcp": [{"HostIp": "127.0.0.1", "HostPort": "41234"}],
+    "80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}],
+    "443/tcp": [{"HostIp": "0.0.0.0", "HostPort": "32768"}]
+  }},
+  "Mounts": [
+    {"Type": "bind", "Source": "/lib/modules", "Destination": "/lib/modules", "RW": false},
+    {"Type": "volume", "Source": "/var/lib/docker/volumes/x", "Destination": "/var", "RW": true},
+    {"Type": "bind", "Source": "/host_mnt/src", "Destination": "/src", "RW": false}
+  ]
+}]`
+
+func TestDiffConfig_InSync(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-worker2\ndev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"inspect", "dev-control-plane"}, out: []byte(driftControlPlane)},
+		{name: "docker", args: []string{"inspect", "*"}, out: []byte(`[{"Config": {"Image": "kindest/node:v1.31.0"}}]`)},
+		{name: "docker", args: []string{"exec"}, out: []byte("docker.io\n")},
+	}}
+
+	report, err := newDockerManager(runner).DiffConfig(context.Background(), "dev", driftConfig, []string{"docker.io"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.InSync || report.RequiresRecreate {
+		t.Errorf("expected no drift, got %+v", report.Drift)
+	}
+}
+
+func TestDiffConfig_Drift(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"inspect", "dev-control-plane"}, out: []byte(`[{
+  "Config": {"Image": "kindest/node:v1.30.0"},
+  "HostConfig": {"PortBindings": {"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "9090"}]}},
+  "Mounts": [{"Type": "bind", "Source": "/data", "Destination": "/data", "RW": true}]
+}]`)},
+		{name: "docker", args: []string{"inspect", "dev-worker"}, out: []byte(`[{"Config": {"Image": "kindest/node:v1.31.0"}}]`)},
+		{name: "docker", args: []string{"exec", "dev-control-plane"}, out: []byte("ghcr.io\n")},
+		{name: "docker", args: []string{"exec", "dev-worker"}, out: []byte("")},
+	}}
+
+	report, err := newDockerManager(runner).DiffConfig(context.Background(), "dev", driftConfig, []string{"docker.io"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.InSync || !report.RequiresRecreate {
+		t.Fatalf("expected drift requiring recreation: %+v", report)
+	}
+
+	counts := make(map[string]int)
+	for _, d := range report.Drift {
+		counts[d.Field]++
+		if d.Field == DriftMirror && d.RequiresRecreate {
+			t.Errorf("mirror drift should be patchable live: %+v", d)
+		}
+		if d.Field != DriftMirror && !d.RequiresRecreate {
+			t.Errorf("%s drift should require recreation: %+v", d.Field, d)
+		}
+	}
+	want := map[string]int{
+		DriftNodeCount: 1, // 2 workers desired, 1 live
+		DriftImage:     1,
+		// 80->8080 and 443 missing, 80->9090 extra
+		DriftPortMapping: 3,
+		// /src missing, /data extra
+		DriftMount: 2,
+		// docker.io missing on both nodes, ghcr.io extra on the control plane
+		DriftMirror: 3,
+	}
+	for field, n := range want {
+		if counts[field] != n {
+			t.Errorf("%s drift count = %d, want %d (%+v)", field, counts[field], n, report.Drift)
+		}
+	}
+}
+
+func TestDesiredMirrors(t *testing.T) {
+	cfg := ClusterConfig{
+		ContainerdConfigPatches: []string{`[plugins."io.containerd.grpc.v1.cri".registry.mirrors."quay.io"]
+  endpoint = ["http://mirror:5000"]`},
+		Nodes: []NodeConfig{{ExtraMounts: []Mount{{HostPath: "/certs", ContainerPath: "/etc/containerd/certs.d/ghcr.io"}}}},
+	}
+	got := desiredMirrors(cfg, []string{"docker.io", "quay.io"})
+	want := []string{"docker.io", "ghcr.io", "quay.io"}
+	if len(got) != len(want) {
+		t.Fatalf("mirrors = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("mirrors = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSortNodeNames(t *testing.T) {
+	names := []string{"dev-worker10", "dev-worker2", "dev-worker"}
+	sortNodeNames(names)
+	if names[0] != "dev-worker" || names[1] != "dev-worker2" || names[2] != "dev-worker10" {
+		t.Errorf("order = %v", names)
+	}
+}
@@ -0,0 +1,258 @@
+package kind
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ways ServiceEndpoints reaches a service port from the host.
+const (
+	ViaNodePort     = "nodeport"      // a node's extraPortMapping publishes the NodePort
+	ViaLoadBalancer = "load-balancer" // an address assigned by cloud-provider-kind
+	ViaPortForward  = "port-forward"  // only 'kubectl port-forward' reaches it
+)
+
+// ServicePortEndpoint is how one port of a Service can be reached from the host.
+type ServicePortEndpoint struct {
+	Name     string `json:"name,omitempty"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+	NodePort int    `json:"node_port,omitempty"`
+	Via      string `json:"via"`
+	// URLs are reachable from the host without further setup, best first.
+	URLs        []string `json:"urls,omitempty"`
+	PortForward string   `json:"port_forward"`
+	Note        string   `json:"note,omitempty"`
+}
+
+// ServiceEndpoint is a Service and the host URLs for its ports.
+type ServiceEndpoint struct {
+	Namespace string                `json:"namespace"`
+	Name      string                `json:"name"`
+	Type      string                `json:"type"`
+	Ports     []ServicePortEndpoint `json:"ports"`
+}
+
+// EndpointReport is the result of ServiceEndpoints.
+type EndpointReport struct {
+	Cluster  string            `json:"cluster"`
+	Services []ServiceEndpoint `json:"services"`
+	// Ingress lists the host URLs of node ports 80/443, where an ingress controller
+	// deployed with Kind's ingress-ready setup serves Ingress resources.
+	Ingress []string `json:"ingress,omitempty"`
+	Notes   []string `json:"notes,omitempty"`
+}
+
+// serviceList is the part of 'kubectl get services -o json' ServiceEndpoints reads.
+type serviceList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			Type  string `json:"type"`
+			Ports []struct {
+				Name     string `json:"name"`
+				Port     int    `json:"port"`
+				Protocol string `json:"protocol"`
+				NodePort int    `json:"nodePort"`
+			} `json:"ports"`
+		} `json:"spec"`
+		Status struct {
+			LoadBalancer struct {
+				Ingress []struct {
+					IP       string `json:"ip"`
+					Hostname string `json:"hostname"`
+				} `json:"ingress"`
+			} `json:"loadBalancer"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// ServiceEndpoints lists the Services of a cluster (one namespace, or all when
+// namespace is empty) and works out how each port is reachable from the host: through
+// a node's extraPortMapping of its NodePort, through a LoadBalancer address assigned
+// by cloud-provider-kind, or otherwise only through 'kubectl port-forward'.
+func (m *Manager) ServiceEndpoints(ctx context.Context, clusterName, namespace string) (*EndpointReport, error) {
+	args := []string{"get", "services", "-o", "json"}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	} else {
+		args = append(args, "-A")
+	}
+	out, err := m.Kubectl(ctx, clusterName, args...)
+	if err != nil {
+		return nil, err
+	}
+	var services serviceList
+	if err := json.Unmarshal([]byte(out), &services); err != nil {
+		return nil, fmt.Errorf("parsing services: %w", err)
+	}
+
+	published, err := m.publishedNodePorts(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &EndpointReport{Cluster: clusterName, Services: []ServiceEndpoint{}}
+	for _, port := range []int{80, 443} {
+		if addr, ok := published[portKey(port, "TCP")]; ok {
+			report.Ingress = append(report.Ingress, serviceURL(addr, port, "", "TCP"))
+		}
+	}
+
+	pendingLB, assignedLB := false, false
+	ctxFlag := "--context kind-" + clusterName
+	for _, svc := range services.Items {
+		ep := ServiceEndpoint{
+			Namespace: svc.Metadata.Namespace,
+			Name:      svc.Metadata.Name,
+			Type:      svc.Spec.Type,
+		}
+		var lbAddrs []string
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			if addr := cmp.Or(ing.IP, ing.Hostname); addr != "" {
+				lbAddrs = append(lbAddrs, addr)
+			}
+		}
+		for _, p := range svc.Spec.Ports {
+			proto := cmp.Or(p.Protocol, "TCP")
+			pe := ServicePortEndpoint{
+				Name:     p.Name,
+				Port:     p.Port,
+				Protocol: proto,
+				NodePort: p.NodePort,
+				Via:      ViaPortForward,
+				PortForward: fmt.Sprintf("kubectl %s -n %s port-forward svc/%s %d:%d",
+					ctxFlag, ep.Namespace, ep.Name, localForwardPort(p.Port), p.Port),
+			}
+			if addr, ok := published[portKey(p.NodePort, proto)]; ok && p.NodePort != 0 {
+				pe.Via = ViaNodePort
+				pe.URLs = append(pe.URLs, serviceURL(addr, p.Port, p.Name, proto))
+			}
+			for _, addr := range lbAddrs {
+				if pe.Via == ViaPortForward {
+					pe.Via = ViaLoadBalancer
+				}
+				pe.URLs = append(pe.URLs, serviceURL(addr, p.Port, p.Name, proto))
+			}
+			switch {
+			case pe.Via != ViaPortForward:
+			case proto == "UDP":
+				pe.Note = "kubectl port-forward does not carry UDP; publish the NodePort with an extraPortMapping " +
+					"(plan_network_exposure target=udp-service)."
+			case p.NodePort != 0:
+				pe.Note = fmt.Sprintf("NodePort %d is not published by any node; add an extraPortMapping for it "+
+					"(plan_network_exposure target=tcp-service port=%d) or use the port-forward command.", p.NodePort, p.NodePort)
+			}
+			ep.Ports = append(ep.Ports, pe)
+		}
+		if svc.Spec.Type == "LoadBalancer" {
+			pendingLB = pendingLB || len(lbAddrs) == 0
+			assignedLB = assignedLB || len(lbAddrs) > 0
+		}
+		report.Services = append(report.Services, ep)
+	}
+	sort.Slice(report.Services, func(i, j int) bool {
+		a, b := report.Services[i], report.Services[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	if pendingLB {
+		report.Notes = append(report.Notes, "LoadBalancer services without an address stay pending until "+
+			"cloud-provider-kind runs on the host (https://github.com/kubernetes-sigs/cloud-provider-kind); "+
+			"until then use their NodePort or the port-forward command.")
+	}
+	if assignedLB && (m.runtime.OS.OS == "darwin" || m.runtime.OS.OS == "windows") {
+		report.Notes = append(report.Notes, "LoadBalancer addresses are on the kind network inside the "+
+			"runtime's VM and are only reachable from this host when cloud-provider-kind publishes them "+
+			"(--enable-lb-port-mapping).")
+	}
+	return report, nil
+}
+
+// publishedNodePorts maps "<containerPort>/<proto>" to the host address:port the
+// cluster's nodes publish it on. The first node publishing a port wins.
+func (m *Manager) publishedNodePorts(ctx context.Context, clusterName string) (map[string]string, error) {
+	nodes, err := m.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q %w", clusterName, ErrClusterNotFound)
+	}
+	sortNodeNames(nodes)
+	published := make(map[string]string)
+	for _, node := range nodes {
+		if NodeRole(node) == RoleExternalLoadBalancer {
+			continue
+		}
+		info, err := m.inspectLiveNode(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+		for containerPort, bindings := range info.HostConfig.PortBindings {
+			if len(bindings) == 0 || bindings[0].HostPort == "" {
+				continue
+			}
+			key := strings.ToUpper(containerPort)
+			if _, ok := published[key]; !ok {
+				published[key] = net.JoinHostPort(hostForListen(bindings[0].HostIP), bindings[0].HostPort)
+			}
+		}
+	}
+	return published, nil
+}
+
+// portKey is the PortBindings key of a container port, upper-cased.
+func portKey(port int, proto string) string {
+	return strconv.Itoa(port) + "/" + strings.ToUpper(proto)
+}
+
+// hostForListen is the address a host client dials for a port bound to listen.
+func hostForListen(listen string) string {
+	switch listen {
+	case "", "0.0.0.0", "::", "127.0.0.1", "::1":
+		return "localhost"
+	}
+	return listen
+}
+
+// serviceURL formats a host address for a service port: an http(s) URL for TCP,
+// guessing https from the port name or number, or a bare address for other protocols.
+func serviceURL(addr string, port int, name, proto string) string {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, strconv.Itoa(port))
+	}
+	if !strings.EqualFold(proto, "TCP") {
+		return strings.ToLower(proto) + "://" + addr
+	}
+	scheme := "http"
+	if port == 443 || port == 8443 || strings.Contains(name, "https") || strings.Contains(name, "tls") {
+		scheme = "https"
+	}
+	host, p, _ := net.SplitHostPort(addr)
+	if (scheme == "http" && p == "80") || (scheme == "https" && p == "443") {
+		return scheme + "://" + host + "/"
+	}
+	return scheme + "://" + addr + "/"
+}
+
+// localForwardPort picks a local port for port-forwarding a service port, moving
+// privileged ports above 1024 so no root is needed.
+func localForwardPort(port int) int {
+	if port < 1024 {
+		return port + 8000
+	}
+	return port
+}
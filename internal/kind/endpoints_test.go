@@ -0,0 +1,88 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const endpointServices = `{"items": [
+  {"metadata": {"name": "web", "namespace": "default"},
+   "spec": {"type": "NodePort", "ports": [{"name": "http", "port": 80, "protocol": "TCP", "nodePort": 30080}]}},
+  {"metadata": {"name": "api", "namespace": "default"},
+   "spec": {"type": "NodePort", "ports": [{"port": 8443, "protocol": "TCP", "nodePort": 31443}]}},
+  {"metadata": {"name": "gw", "namespace": "edge"},
+   "spec": {"type": "LoadBalancer", "ports": [{"port": 80, "protocol": "TCP", "nodePort": 32000}]},
+   "status": {"loadBalancer": {"ingress": [{"ip": "172.18.0.5"}]}}},
+  {"metadata": {"name": "dns", "namespace": "kube-system"},
+   "spec": {"type": "ClusterIP", "ports": [{"name": "dns", "port": 53, "protocol": "UDP"}]}},
+  {"metadata": {"name": "pending", "namespace": "edge"},
+   "spec": {"type": "LoadBalancer", "ports": [{"port": 8080, "protocol": "TCP", "nodePort": 32100}]}}
+]}`
+
+func TestServiceEndpoints(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "get", "services", "-o", "json", "-A"}, out: []byte(endpointServices)},
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"inspect", "dev-control-plane"}, out: []byte(`[{"HostConfig": {"PortBindings": {
+			"6443/tcp": [{"HostIp": "127.0.0.1", "HostPort": "40123"}],
+			"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "80"}],
+			"30080/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}]}}}]`)},
+		{name: "docker", args: []string{"inspect", "dev-worker"}, out: []byte(`[{"HostConfig": {"PortBindings": {
+			"31443/tcp": [{"HostIp": "192.168.1.20", "HostPort": "31443"}]}}}]`)},
+	}}
+	report, err := newDockerManager(runner).ServiceEndpoints(context.Background(), "dev", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Ingress) != 1 || report.Ingress[0] != "http://localhost/" {
+		t.Errorf("ingress = %v", report.Ingress)
+	}
+
+	byName := map[string]ServicePortEndpoint{}
+	for _, svc := range report.Services {
+		byName[svc.Name] = svc.Ports[0]
+	}
+	if web := byName["web"]; web.Via != ViaNodePort || web.URLs[0] != "http://localhost:8080/" {
+		t.Errorf("web = %+v", web)
+	}
+	if api := byName["api"]; api.URLs[0] != "https://192.168.1.20:31443/" {
+		t.Errorf("api = %+v", api)
+	}
+	if gw := byName["gw"]; gw.Via != ViaLoadBalancer || gw.URLs[0] != "http://172.18.0.5/" {
+		t.Errorf("gw = %+v", gw)
+	}
+	dns := byName["dns"]
+	if dns.Via != ViaPortForward || !strings.Contains(dns.Note, "UDP") {
+		t.Errorf("dns = %+v", dns)
+	}
+	if dns.PortForward != "kubectl --context kind-dev -n kube-system port-forward svc/dns 8053:53" {
+		t.Errorf("port forward = %q", dns.PortForward)
+	}
+	if pending := byName["pending"]; pending.Via != ViaPortForward || !strings.Contains(pending.Note, "32100") {
+		t.Errorf("pending = %+v", pending)
+	}
+	if !strings.Contains(strings.Join(report.Notes, " "), "cloud-provider-kind") {
+		t.Errorf("notes = %v", report.Notes)
+	}
+	if report.Services[0].Namespace != "default" || report.Services[len(report.Services)-1].Namespace != "kube-system" {
+		t.Errorf("services not sorted: %+v", report.Services)
+	}
+}
+
+func TestServiceURL(t *testing.T) {
+	for _, tc := range []struct {
+		addr, name, proto string
+		port              int
+		want              string
+	}{
+		{"localhost:443", "", "TCP", 443, "https://localhost/"},
+		{"10.0.0.1", "web-https", "TCP", 9000, "https://10.0.0.1:9000/"},
+		{"localhost:30053", "dns", "UDP", 53, "udp://localhost:30053"},
+	} {
+		if got := serviceURL(tc.addr, tc.port, tc.name, tc.proto); got != tc.want {
+			t.Errorf("serviceURL(%q, %d) = %q, want %q", tc.addr, tc.port, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package kind
+
+import "errors"
+
+// Typed errors returned by Manager regardless of whether the kind CLI or the kind Go
+// library backend is in use. Callers match them with errors.Is.
+var (
+	// ErrClusterExists is returned when creating a cluster whose name is already taken.
+	ErrClusterExists = errors.New("already exists")
+	// ErrClusterNotFound is returned when a cluster has no node containers.
+	ErrClusterNotFound = errors.New("not found or has no nodes")
+	// ErrOutsideStorageDir is returned when removing a host storage directory that is
+	// not inside the managed storage directory.
+	ErrOutsideStorageDir = errors.New("is not inside the managed storage directory")
+)
@@ -0,0 +1,195 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// HostInterface is a host network interface and the addresses teammates could use to reach it.
+type HostInterface struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+}
+
+// hostInterfaces lists the host's up, non-loopback interfaces; overridden in tests.
+var hostInterfaces = func() ([]HostInterface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("listing host interfaces: %w", err)
+	}
+	var result []HostInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		hi := HostInterface{Name: iface.Name}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			hi.Addresses = append(hi.Addresses, ipNet.IP.String())
+		}
+		if len(hi.Addresses) > 0 {
+			result = append(result, hi)
+		}
+	}
+	return result, nil
+}
+
+// APIServerExposure describes how to reach a cluster's API server from the LAN.
+type APIServerExposure struct {
+	Interfaces []HostInterface `json:"interfaces"`
+	// Address is the selected host interface address.
+	Address string `json:"address"`
+	// BoundTo is the API server's current host binding, e.g. 127.0.0.1:41234.
+	BoundTo string `json:"bound_to,omitempty"`
+	// Reachable reports whether the current binding accepts connections on Address.
+	Reachable bool `json:"reachable"`
+	// Server is the API server URL for LAN clients.
+	Server         string `json:"server,omitempty"`
+	Kubeconfig     string `json:"kubeconfig,omitempty"`
+	KubeconfigPath string `json:"kubeconfig_path,omitempty"`
+	// ConfigPatch is the networking section that binds a new cluster's API server to Address.
+	ConfigPatch string   `json:"config_patch"`
+	Notes       []string `json:"notes"`
+}
+
+// ExposeAPIServer selects a host interface address (the first IPv4 address when address
+// is empty) and explains how LAN clients can reach the API server. For an existing
+// cluster whose API server already listens on that address, it returns a kubeconfig
+// pointing at it; otherwise the config patch must be applied by recreating the cluster.
+// port is used in the config patch when the cluster has no current binding (default 6443).
+func (m *Manager) ExposeAPIServer(ctx context.Context, clusterName, address string, port int) (*APIServerExposure, error) {
+	ifaces, err := hostInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	result := &APIServerExposure{Interfaces: ifaces, Notes: []string{}}
+	if result.Address, err = selectAddress(ifaces, address); err != nil {
+		return nil, err
+	}
+
+	if clusterName != "" {
+		node := clusterName + "-control-plane"
+		nodes, err := m.GetClusterNodes(ctx, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		if len(nodes) == 0 {
+			return nil, fmt.Errorf("cluster %q %w", clusterName, ErrClusterNotFound)
+		}
+		for _, n := range nodes {
+			if NodeRole(n) == RoleExternalLoadBalancer {
+				node = n
+			}
+		}
+		result.BoundTo = m.loadBalancerPort(ctx, node)
+	}
+
+	boundHost, boundPort, _ := net.SplitHostPort(result.BoundTo)
+	if p, err := strconv.Atoi(boundPort); err == nil && port == 0 {
+		port = p
+	}
+	if port == 0 {
+		port = 6443
+	}
+	result.ConfigPatch = fmt.Sprintf("networking:\n  apiServerAddress: %q\n  apiServerPort: %d\n", result.Address, port)
+
+	add := func(format string, args ...any) {
+		result.Notes = append(result.Notes, fmt.Sprintf(format, args...))
+	}
+	switch {
+	case clusterName == "":
+		add("Add the config patch to a new cluster's config; binding to %s also puts the address in the "+
+			"API server certificate's SANs.", result.Address)
+	case boundHost == result.Address || boundHost == "0.0.0.0" || boundHost == "::":
+		result.Reachable = true
+		result.Server = "https://" + net.JoinHostPort(result.Address, boundPort)
+		// Kind only adds apiServerAddress, localhost and 127.0.0.1 to the certificate, so a
+		// wildcard binding needs a server name the certificate covers.
+		tlsServerName := ""
+		if boundHost != result.Address {
+			tlsServerName = "localhost"
+			add("The API server listens on all interfaces, so its certificate does not include %s; the kubeconfig "+
+				"sets tls-server-name: localhost to keep TLS verification. To add the address to the SANs, recreate "+
+				"with the config patch.", result.Address)
+		}
+		kubeconfig, err := m.GetKubeconfig(ctx, clusterName, false)
+		if err != nil {
+			return nil, err
+		}
+		if result.Kubeconfig, err = patchKubeconfigServer(kubeconfig, result.Server, tlsServerName); err != nil {
+			return nil, err
+		}
+	default:
+		add("The API server is bound to %s and cannot be reached on %s. The address is fixed at creation: "+
+			"recreate the cluster (e.g. 'recreate_cluster') with the config patch merged into its config.",
+			result.BoundTo, result.Address)
+	}
+
+	add("Anyone who can reach %s:%d and holds this kubeconfig has cluster-admin access; restrict the port "+
+		"with a host firewall.", result.Address, port)
+	if m.runtime.Backend != rtdetect.BackendNative && m.runtime.Backend != "" {
+		add("On %s the API server port is forwarded from a VM; confirm the backend forwards it to LAN interfaces "+
+			"and not only to localhost (see 'get_network_advice').", m.runtime.Backend)
+	}
+	return result, nil
+}
+
+// selectAddress returns address if it belongs to a host interface, or the first IPv4
+// address when address is empty.
+func selectAddress(ifaces []HostInterface, address string) (string, error) {
+	var all []string
+	for _, iface := range ifaces {
+		for _, a := range iface.Addresses {
+			if address == "" && net.ParseIP(a).To4() != nil {
+				return a, nil
+			}
+			if a == address {
+				return a, nil
+			}
+			all = append(all, a)
+		}
+	}
+	if address == "" {
+		return "", fmt.Errorf("no host interface with an IPv4 address found")
+	}
+	return "", fmt.Errorf("address %q is not assigned to a host interface; available: %s", address, strings.Join(all, ", "))
+}
+
+// patchKubeconfigServer points every cluster in a kubeconfig at server and, if set,
+// overrides the TLS server name used to verify the certificate.
+func patchKubeconfigServer(kubeconfig, server, tlsServerName string) (string, error) {
+	var cfg map[string]any
+	if err := yaml.Unmarshal([]byte(kubeconfig), &cfg); err != nil {
+		return "", fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	clusters, _ := cfg["clusters"].([]any)
+	for _, c := range clusters {
+		entry, _ := c.(map[string]any)
+		cluster, ok := entry["cluster"].(map[string]any)
+		if !ok {
+			continue
+		}
+		cluster["server"] = server
+		if tlsServerName != "" {
+			cluster["tls-server-name"] = tlsServerName
+		}
+	}
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("encoding kubeconfig: %w", err)
+	}
+	return string(out), nil
+}
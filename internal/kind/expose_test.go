@@ -0,0 +1,84 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: Q0E=
+    server: https://127.0.0.1:6443
+  name: kind-lan
+contexts:
+- context:
+    cluster: kind-lan
+    user: kind-lan
+  name: kind-lan
+current-context: kind-lan
+`
+
+func withInterfaces(t *testing.T, ifaces []HostInterface) {
+	old := hostInterfaces
+	hostInterfaces = func() ([]HostInterface, error) { return ifaces, nil }
+	t.Cleanup(func() { hostInterfaces = old })
+}
+
+func TestExposeAPIServer_WildcardBinding(t *testing.T) {
+	withInterfaces(t, []HostInterface{
+		{Name: "en0", Addresses: []string{"fd00::5", "192.168.1.20"}},
+	})
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("lan-control-plane\n")},
+		{name: "docker", args: []string{"port", "lan-control-plane", "6443/tcp"}, out: []byte("0.0.0.0:6443\n[::]:6443\n")},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte(testKubeconfig)},
+	}}
+
+	result, err := newDockerManager(runner).ExposeAPIServer(context.Background(), "lan", "", 0)
+	if err != nil {
+		t.Fatalf("ExposeAPIServer: %v", err)
+	}
+	if result.Address != "192.168.1.20" || !result.Reachable {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Server != "https://192.168.1.20:6443" {
+		t.Errorf("Server = %q", result.Server)
+	}
+	if !strings.Contains(result.Kubeconfig, "server: https://192.168.1.20:6443") ||
+		!strings.Contains(result.Kubeconfig, "tls-server-name: localhost") {
+		t.Errorf("kubeconfig not patched:\n%s", result.Kubeconfig)
+	}
+	if !strings.Contains(result.ConfigPatch, `apiServerAddress: "192.168.1.20"`) {
+		t.Errorf("ConfigPatch = %q", result.ConfigPatch)
+	}
+}
+
+func TestExposeAPIServer_LocalhostBinding(t *testing.T) {
+	withInterfaces(t, []HostInterface{{Name: "eth0", Addresses: []string{"10.0.0.7"}}})
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("lan-control-plane\n")},
+		{name: "docker", args: []string{"port"}, out: []byte("127.0.0.1:41234\n")},
+	}}
+
+	result, err := newDockerManager(runner).ExposeAPIServer(context.Background(), "lan", "10.0.0.7", 0)
+	if err != nil {
+		t.Fatalf("ExposeAPIServer: %v", err)
+	}
+	if result.Reachable || result.Kubeconfig != "" {
+		t.Errorf("expected unreachable binding without kubeconfig: %+v", result)
+	}
+	if !strings.Contains(result.ConfigPatch, "apiServerPort: 41234") {
+		t.Errorf("ConfigPatch should keep the current port: %q", result.ConfigPatch)
+	}
+}
+
+func TestExposeAPIServer_UnknownAddress(t *testing.T) {
+	withInterfaces(t, []HostInterface{{Name: "eth0", Addresses: []string{"10.0.0.7"}}})
+	_, err := newDockerManager(&mockRunner{}).ExposeAPIServer(context.Background(), "", "10.9.9.9", 0)
+	if err == nil || !strings.Contains(err.Error(), "10.0.0.7") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,375 @@
+package kind
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// Exposure targets accepted by PlanExposure.
+const (
+	ExposeHTTPIngress = "http-ingress"
+	ExposeTCPService  = "tcp-service"
+	ExposeUDPService  = "udp-service"
+	ExposeAPIServer   = "api-server"
+)
+
+// Exposure audiences accepted by PlanExposure.
+const (
+	AudienceLocalhost  = "localhost"
+	AudienceLAN        = "lan"
+	AudienceContainers = "containers"
+)
+
+// defaultExposedNodePort is the NodePort planned for services when none is given.
+const defaultExposedNodePort = 30080
+
+// ExposureRequest describes what to expose from a Kind cluster and to whom.
+type ExposureRequest struct {
+	Target   string
+	Audience string
+	// ClusterName fills node names into the plan; a placeholder is used when empty.
+	ClusterName string
+	// Port is the NodePort of a TCP or UDP service (default 30080) or the API server
+	// host port (default 6443). Unused for ingress.
+	Port int
+	// HostPort is the host port of a service, or the HTTP host port of an ingress
+	// (default 80). Defaults to Port for services.
+	HostPort int
+	// HTTPSHostPort is the HTTPS host port of an ingress; default 443 when HostPort is
+	// 80, otherwise 8443.
+	HTTPSHostPort int
+}
+
+// ExposurePlan is the concrete configuration for one ExposureRequest on a backend.
+type ExposurePlan struct {
+	Target        string           `json:"target"`
+	Audience      string           `json:"audience"`
+	Backend       rtdetect.Backend `json:"backend"`
+	ListenAddress string           `json:"listen_address,omitempty"`
+	PortMappings  []PortMapping    `json:"port_mappings,omitempty"`
+	// GenerateParams are generate_cluster_config arguments that produce the plan's
+	// cluster configuration.
+	GenerateParams map[string]any `json:"generate_cluster_config,omitempty"`
+	// ClusterChanges are changes inside the cluster (Services, controllers, kubeconfig).
+	ClusterChanges []string `json:"cluster_changes,omitempty"`
+	// HostSteps are changes outside the cluster: firewalls, port proxies, VM settings.
+	HostSteps []string `json:"host_steps,omitempty"`
+	// ConnectTo lists the addresses the audience uses once the plan is applied.
+	ConnectTo []string `json:"connect_to"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// PlanExposure returns the port mappings, configuration and host changes needed to
+// expose req.Target to req.Audience on the detected backend.
+func PlanExposure(ri rtdetect.RuntimeInfo, req ExposureRequest) (*ExposurePlan, error) {
+	switch req.Target {
+	case ExposeHTTPIngress, ExposeTCPService, ExposeUDPService, ExposeAPIServer:
+	default:
+		return nil, fmt.Errorf("unknown target %q; must be %q, %q, %q, or %q",
+			req.Target, ExposeHTTPIngress, ExposeTCPService, ExposeUDPService, ExposeAPIServer)
+	}
+	if req.Audience == "" {
+		req.Audience = AudienceLocalhost
+	}
+	switch req.Audience {
+	case AudienceLocalhost, AudienceLAN, AudienceContainers:
+	default:
+		return nil, fmt.Errorf("unknown audience %q; must be %q, %q, or %q",
+			req.Audience, AudienceLocalhost, AudienceLAN, AudienceContainers)
+	}
+	for _, p := range []int{req.Port, req.HostPort, req.HTTPSHostPort} {
+		if p < 0 || p > 65535 {
+			return nil, fmt.Errorf("invalid port %d", p)
+		}
+	}
+	if req.ClusterName == "" {
+		req.ClusterName = "<cluster>"
+	}
+
+	plan := &ExposurePlan{Target: req.Target, Audience: req.Audience, Backend: ri.Backend}
+	if req.Audience == AudienceContainers {
+		planForContainers(ri, req, plan)
+		return plan, nil
+	}
+	plan.ListenAddress = "127.0.0.1"
+	if req.Audience == AudienceLAN {
+		plan.ListenAddress = "0.0.0.0"
+	}
+
+	switch req.Target {
+	case ExposeHTTPIngress:
+		planIngress(ri, req, plan)
+	case ExposeTCPService, ExposeUDPService:
+		planService(ri, req, plan)
+	case ExposeAPIServer:
+		planAPIServer(req, plan)
+	}
+	addHostSteps(ri, req, plan)
+	return plan, nil
+}
+
+func planIngress(ri rtdetect.RuntimeInfo, req ExposureRequest, plan *ExposurePlan) {
+	httpPort := req.HostPort
+	if httpPort == 0 {
+		httpPort = 80
+	}
+	httpsPort := req.HTTPSHostPort
+	if httpsPort == 0 {
+		httpsPort = 443
+		if httpPort != 80 {
+			httpsPort = 8443
+		}
+	}
+	plan.PortMappings = []PortMapping{
+		{HostPort: httpPort, ContainerPort: 80, ListenAddress: plan.ListenAddress, Protocol: "TCP"},
+		{HostPort: httpsPort, ContainerPort: 443, ListenAddress: plan.ListenAddress, Protocol: "TCP"},
+	}
+	controller := "Install an ingress controller that binds hostPorts 80/443 on the node labelled " +
+		"ingress-ready=true, e.g. kind's ingress-nginx manifest " +
+		"(https://kind.sigs.k8s.io/examples/ingress/deploy-ingress-nginx.yaml)."
+	if httpPort == 80 && httpsPort == 443 {
+		plan.GenerateParams = map[string]any{
+			"enable_ingress_ports":   true,
+			"ingress_listen_address": plan.ListenAddress,
+		}
+		plan.ClusterChanges = []string{controller}
+	} else {
+		plan.GenerateParams = map[string]any{"port_mappings": portMappingsParam(plan.PortMappings)}
+		plan.ClusterChanges = []string{
+			fmt.Sprintf("Label the first control plane for the controller: 'kubectl label node %s-control-plane "+
+				"ingress-ready=true' (enable_ingress_ports only maps host ports 80/443).", req.ClusterName),
+			controller,
+		}
+	}
+
+	plan.ConnectTo = []string{
+		"http://" + hostAddress(req.Audience) + portSuffix(httpPort, 80) + "/",
+		"https://" + hostAddress(req.Audience) + portSuffix(httpsPort, 443) + "/",
+	}
+	if httpPort < 1024 || httpsPort < 1024 {
+		if ri.Backend == rtdetect.BackendDockerDesktop && ri.OS.OS == "darwin" {
+			plan.Warnings = append(plan.Warnings, "Docker Desktop on macOS needs its privileged port helper (vmnetd) "+
+				"to bind ports below 1024; if creation fails, use host_port 8080.")
+		}
+		if ri.Runtime == rtdetect.RuntimePodman {
+			plan.Warnings = append(plan.Warnings, "Rootless Podman cannot bind ports below 1024 unless "+
+				"net.ipv4.ip_unprivileged_port_start is lowered; otherwise use host_port 8080.")
+		}
+	}
+}
+
+func planService(ri rtdetect.RuntimeInfo, req ExposureRequest, plan *ExposurePlan) {
+	protocol := "TCP"
+	if req.Target == ExposeUDPService {
+		protocol = "UDP"
+	}
+	nodePort := req.Port
+	if nodePort == 0 {
+		nodePort = defaultExposedNodePort
+	}
+	hostPort := req.HostPort
+	if hostPort == 0 {
+		hostPort = nodePort
+	}
+	plan.PortMappings = []PortMapping{{
+		HostPort: hostPort, ContainerPort: nodePort, ListenAddress: plan.ListenAddress, Protocol: protocol,
+	}}
+	plan.GenerateParams = map[string]any{"port_mappings": portMappingsParam(plan.PortMappings)}
+	if nodePort < 30000 || nodePort > 32767 {
+		plan.GenerateParams["node_port_range"] = fmt.Sprintf("%d-%d", min(nodePort, 30000), max(nodePort, 32767))
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("NodePort %d is outside the default range "+
+			"30000-32767; the plan widens node_port_range to include it.", nodePort))
+	}
+	plan.ClusterChanges = []string{fmt.Sprintf("Expose the workload with a Service of type NodePort with "+
+		"nodePort: %d and protocol: %s. The mapping targets the first control plane; kube-proxy forwards the "+
+		"NodePort from every node to the pods.", nodePort, protocol)}
+	plan.ConnectTo = []string{fmt.Sprintf("%s:%d (%s)", hostAddress(req.Audience), hostPort, protocol)}
+	if ri.Backend == rtdetect.BackendNative && ri.OS.OS == "linux" {
+		plan.ConnectTo = append(plan.ConnectTo, fmt.Sprintf("<node-ip>:%d from this host, without the mapping", nodePort))
+	}
+}
+
+func planAPIServer(req ExposureRequest, plan *ExposurePlan) {
+	port := req.Port
+	if req.HostPort != 0 {
+		port = req.HostPort
+	}
+	if port == 0 {
+		port = 6443
+	}
+	plan.GenerateParams = map[string]any{"api_server_port": port}
+	if req.Audience == AudienceLocalhost {
+		plan.ClusterChanges = []string{"Kind binds the API server to 127.0.0.1 by default; pinning the port " +
+			"keeps the server address in kubeconfigs stable across recreation."}
+		plan.ConnectTo = []string{fmt.Sprintf("https://127.0.0.1:%d (get_kubeconfig)", port)}
+		return
+	}
+	plan.GenerateParams["api_server_cert_sans"] = []string{"<host LAN IP>"}
+	plan.ClusterChanges = []string{
+		fmt.Sprintf("Call expose_api_server for %s: it finds the host's LAN addresses, returns the "+
+			"networking.apiServerAddress 0.0.0.0 patch to apply with recreate_cluster (the API server "+
+			"binding cannot change on a running cluster), and a kubeconfig for LAN clients.", req.ClusterName),
+	}
+	plan.ConnectTo = []string{fmt.Sprintf("https://<host LAN IP>:%d", port)}
+	plan.Warnings = append(plan.Warnings, "This exposes cluster-admin access to the network; restrict the "+
+		"port to trusted addresses with a host firewall.")
+}
+
+// planForContainers plans access from containers on the same host, which reach nodes
+// over the kind network instead of published ports.
+func planForContainers(ri rtdetect.RuntimeInfo, req ExposureRequest, plan *ExposurePlan) {
+	runtime := ri.Runtime
+	if runtime != rtdetect.RuntimePodman {
+		runtime = rtdetect.RuntimeDocker
+	}
+	node := req.ClusterName + "-control-plane"
+	plan.HostSteps = []string{fmt.Sprintf("Attach the client container to the %s network: '%s network connect %s "+
+		"<container>', or start it with --network %s.", kindNetwork, runtime, kindNetwork, kindNetwork)}
+
+	switch req.Target {
+	case ExposeHTTPIngress:
+		plan.GenerateParams = map[string]any{"enable_ingress_ports": true}
+		plan.ClusterChanges = []string{"Install an ingress controller that binds hostPorts 80/443 on the node " +
+			"labelled ingress-ready=true (enable_ingress_ports sets the label; its host port mappings are optional here)."}
+		plan.ConnectTo = []string{"http://" + node + "/", "https://" + node + "/"}
+	case ExposeTCPService, ExposeUDPService:
+		nodePort := req.Port
+		if nodePort == 0 {
+			nodePort = defaultExposedNodePort
+		}
+		plan.ClusterChanges = []string{fmt.Sprintf("Expose the workload with a Service of type NodePort with "+
+			"nodePort: %d; any node name works.", nodePort)}
+		plan.ConnectTo = []string{fmt.Sprintf("%s:%d", node, nodePort)}
+	case ExposeAPIServer:
+		plan.ClusterChanges = []string{"Give the container the kubeconfig from get_kubeconfig with internal=true, " +
+			"which addresses the API server by container name."}
+		plan.ConnectTo = []string{fmt.Sprintf("https://%s:6443 (HA clusters: https://%s-external-load-balancer:6443)",
+			node, req.ClusterName)}
+	}
+	plan.Warnings = []string{"Containers that cannot join the kind network can use a localhost plan and " +
+		"connect to host.docker.internal (Docker Desktop; elsewhere add --add-host host.docker.internal:host-gateway)."}
+}
+
+// addHostSteps adds the backend's host-side steps for published ports.
+func addHostSteps(ri rtdetect.RuntimeInfo, req ExposureRequest, plan *ExposurePlan) {
+	ports := make([]int, 0, len(plan.PortMappings)+1)
+	protocol := "TCP"
+	for _, pm := range plan.PortMappings {
+		ports = append(ports, pm.HostPort)
+		protocol = pm.Protocol
+	}
+	if req.Target == ExposeAPIServer {
+		ports = append(ports, plan.GenerateParams["api_server_port"].(int))
+	}
+	udp := protocol == "UDP"
+	step := func(format string, args ...any) {
+		plan.HostSteps = append(plan.HostSteps, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...any) { plan.Warnings = append(plan.Warnings, fmt.Sprintf(format, args...)) }
+
+	if len(plan.PortMappings) > 0 {
+		warn("extraPortMappings cannot be added to a running cluster: create the cluster with them, or " +
+			"recreate_cluster an existing one.")
+	}
+
+	switch ri.Backend {
+	case rtdetect.BackendWSL:
+		w := ri.WSL
+		switch {
+		case w != nil && w.Mirrored():
+			if req.Audience == AudienceLAN {
+				for _, p := range ports {
+					step("In an elevated PowerShell: New-NetFirewallHyperVRule -Name kind-%[1]d -DisplayName 'kind %[1]d' "+
+						"-Direction Inbound -VMCreatorId '{40E0AC32-46A5-438A-A0B2-2B479E8F2E90}' -Protocol %[2]s "+
+						"-LocalPorts %[1]d; New-NetFirewallRule -DisplayName 'kind %[1]d' -Direction Inbound "+
+						"-Protocol %[2]s -LocalPort %[1]d -Action Allow", p, protocol)
+				}
+			}
+		default:
+			if w != nil && !w.LocalhostForwarding {
+				warn("WSL localhostForwarding is off: Windows cannot reach these ports on localhost. Set " +
+					"localhostForwarding=true in .wslconfig and run 'wsl --shutdown', or use the address from 'hostname -I'.")
+			}
+			if req.Audience == AudienceLAN {
+				if udp {
+					warn("netsh portproxy only forwards TCP; UDP from the LAN needs networkingMode=mirrored in .wslconfig.")
+					break
+				}
+				for _, p := range ports {
+					step("In an elevated PowerShell: netsh interface portproxy add v4tov4 listenport=%[1]d "+
+						"listenaddress=0.0.0.0 connectport=%[1]d connectaddress=<WSL IP from 'wsl hostname -I'>; "+
+						"New-NetFirewallRule -DisplayName 'kind %[1]d' -Direction Inbound -Protocol TCP "+
+						"-LocalPort %[1]d -Action Allow (the WSL IP changes on restart)", p)
+				}
+			}
+		}
+
+	case rtdetect.BackendColima, rtdetect.BackendLima:
+		if udp {
+			warn("%s's default ssh port forwarder only forwards TCP; for UDP start it with "+
+				"'colima start --port-forwarder grpc' (Lima: portForwarder grpc).", ri.Backend)
+		}
+		if req.Audience == AudienceLAN {
+			for _, p := range ports {
+				step("%s forwards VM ports to 127.0.0.1 on the host; for LAN clients forward port %d yourself, "+
+					"e.g. 'socat %s-LISTEN:%d,fork,reuseaddr %s:127.0.0.1:%d', or add a Lima portForwards rule with "+
+					"hostIP 0.0.0.0.", ri.Backend, p, protocol, p, protocol, p)
+			}
+			if c := ri.Colima; c != nil && c.NetworkAddress() {
+				step("The Colima VM address %s also serves ports bound to 0.0.0.0, but only from this Mac.", c.Address)
+			}
+		}
+
+	case rtdetect.BackendPodmanMachine:
+		if ri.OS.OS == "darwin" || ri.OS.OS == "windows" {
+			step("Run the Podman machine rootful ('podman machine set --rootful') so gvproxy forwards the " +
+				"published ports to the host.")
+		}
+		if req.Audience == AudienceLAN {
+			step("Allow the ports through the host firewall; gvproxy listens on the mapping's listen address.")
+		}
+
+	case rtdetect.BackendDockerDesktop, rtdetect.BackendRancherDesktop:
+		if req.Audience == AudienceLAN {
+			step("Allow inbound connections for %s in the host firewall when prompted; it publishes 0.0.0.0 "+
+				"ports on all host interfaces.", ri.Backend)
+		}
+
+	case rtdetect.BackendNative:
+		if req.Audience == AudienceLAN {
+			if ri.Runtime == rtdetect.RuntimePodman {
+				for _, p := range ports {
+					step("Open the port in the host firewall, e.g. 'sudo firewall-cmd --add-port=%d/%s'.", p, strings.ToLower(protocol))
+				}
+			} else {
+				step("Docker publishes ports with its own iptables rules, which bypass ufw and firewalld INPUT " +
+					"rules; restrict LAN access in the DOCKER-USER chain if needed.")
+			}
+		}
+	}
+}
+
+// hostAddress is the address a client of the audience uses for the host.
+func hostAddress(audience string) string {
+	if audience == AudienceLAN {
+		return "<host LAN IP>"
+	}
+	return "localhost"
+}
+
+// portSuffix renders ":port" unless port is the scheme default.
+func portSuffix(port, def int) string {
+	if port == def {
+		return ""
+	}
+	return fmt.Sprintf(":%d", port)
+}
+
+// portMappingsParam renders mappings as generate_cluster_config's port_mappings JSON.
+func portMappingsParam(mappings []PortMapping) string {
+	data, _ := json.Marshal(mappings)
+	return string(data)
+}
@@ -0,0 +1,149 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestPlanExposure_IngressLocalhost(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker,
+		Backend: rtdetect.BackendDockerDesktop,
+		OS:      rtdetect.OSInfo{OS: "darwin"},
+	}
+	plan, err := PlanExposure(ri, ExposureRequest{Target: ExposeHTTPIngress, ClusterName: "dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Audience != AudienceLocalhost || plan.ListenAddress != "127.0.0.1" {
+		t.Errorf("audience = %q, listen address = %q", plan.Audience, plan.ListenAddress)
+	}
+	if plan.GenerateParams["enable_ingress_ports"] != true || plan.GenerateParams["ingress_listen_address"] != "127.0.0.1" {
+		t.Errorf("generate params = %v", plan.GenerateParams)
+	}
+	if len(plan.PortMappings) != 2 || plan.PortMappings[1].HostPort != 443 {
+		t.Errorf("port mappings = %+v", plan.PortMappings)
+	}
+	if plan.ConnectTo[0] != "http://localhost/" {
+		t.Errorf("connect to = %v", plan.ConnectTo)
+	}
+	if !strings.Contains(strings.Join(plan.Warnings, " "), "vmnetd") {
+		t.Errorf("expected vmnetd warning: %v", plan.Warnings)
+	}
+}
+
+func TestPlanExposure_IngressCustomPorts(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimePodman, Backend: rtdetect.BackendNative, OS: rtdetect.OSInfo{OS: "linux"}}
+	plan, err := PlanExposure(ri, ExposureRequest{Target: ExposeHTTPIngress, ClusterName: "dev", HostPort: 8080})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := plan.GenerateParams["port_mappings"]; got != `[{"host_port":8080,"container_port":80,"listen_address":"127.0.0.1",`+
+		`"protocol":"TCP"},{"host_port":8443,"container_port":443,"listen_address":"127.0.0.1","protocol":"TCP"}]` {
+		t.Errorf("port_mappings = %v", got)
+	}
+	if !strings.Contains(plan.ClusterChanges[0], "kubectl label node dev-control-plane ingress-ready=true") {
+		t.Errorf("expected label step: %v", plan.ClusterChanges)
+	}
+	if plan.ConnectTo[0] != "http://localhost:8080/" {
+		t.Errorf("connect to = %v", plan.ConnectTo)
+	}
+	if len(plan.Warnings) != 1 { // only the recreation note: no privileged ports
+		t.Errorf("warnings = %v", plan.Warnings)
+	}
+}
+
+func TestPlanExposure_UDPOnColimaLAN(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker, Backend: rtdetect.BackendColima, OS: rtdetect.OSInfo{OS: "darwin"}}
+	plan, err := PlanExposure(ri, ExposureRequest{Target: ExposeUDPService, Audience: AudienceLAN, Port: 30053, HostPort: 53})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm := plan.PortMappings[0]
+	if pm.HostPort != 53 || pm.ContainerPort != 30053 || pm.Protocol != "UDP" || pm.ListenAddress != "0.0.0.0" {
+		t.Errorf("port mapping = %+v", pm)
+	}
+	if !strings.Contains(strings.Join(plan.Warnings, " "), "--port-forwarder grpc") {
+		t.Errorf("expected grpc forwarder warning: %v", plan.Warnings)
+	}
+	if !strings.Contains(strings.Join(plan.HostSteps, " "), "socat UDP-LISTEN:53") {
+		t.Errorf("expected socat step: %v", plan.HostSteps)
+	}
+}
+
+func TestPlanExposure_ServiceOutsideNodePortRange(t *testing.T) {
+	plan, err := PlanExposure(rtdetect.RuntimeInfo{}, ExposureRequest{Target: ExposeTCPService, Port: 8080})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.GenerateParams["node_port_range"] != "8080-32767" {
+		t.Errorf("node_port_range = %v", plan.GenerateParams["node_port_range"])
+	}
+}
+
+func TestPlanExposure_WSLNATLAN(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Backend: rtdetect.BackendWSL,
+		WSL:     &rtdetect.WSLInfo{LocalhostForwarding: true},
+	}
+	plan, err := PlanExposure(ri, ExposureRequest{Target: ExposeTCPService, Audience: AudienceLAN})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.HostSteps) != 1 || !strings.Contains(plan.HostSteps[0], "portproxy add v4tov4 listenport=30080") {
+		t.Errorf("host steps = %v", plan.HostSteps)
+	}
+
+	plan, err = PlanExposure(ri, ExposureRequest{Target: ExposeUDPService, Audience: AudienceLAN})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.HostSteps) != 0 || !strings.Contains(strings.Join(plan.Warnings, " "), "mirrored") {
+		t.Errorf("host steps = %v, warnings = %v", plan.HostSteps, plan.Warnings)
+	}
+}
+
+func TestPlanExposure_APIServerLAN(t *testing.T) {
+	plan, err := PlanExposure(rtdetect.RuntimeInfo{Backend: rtdetect.BackendNative},
+		ExposureRequest{Target: ExposeAPIServer, Audience: AudienceLAN, ClusterName: "dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.GenerateParams["api_server_port"] != 6443 || len(plan.PortMappings) != 0 {
+		t.Errorf("generate params = %v, mappings = %v", plan.GenerateParams, plan.PortMappings)
+	}
+	if !strings.Contains(plan.ClusterChanges[0], "expose_api_server for dev") {
+		t.Errorf("cluster changes = %v", plan.ClusterChanges)
+	}
+}
+
+func TestPlanExposure_Containers(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimePodman}
+	plan, err := PlanExposure(ri, ExposureRequest{Target: ExposeAPIServer, Audience: AudienceContainers, ClusterName: "dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.PortMappings) != 0 || plan.ListenAddress != "" {
+		t.Errorf("unexpected host mappings: %+v", plan)
+	}
+	if !strings.HasPrefix(plan.ConnectTo[0], "https://dev-control-plane:6443") {
+		t.Errorf("connect to = %v", plan.ConnectTo)
+	}
+	if !strings.Contains(plan.HostSteps[0], "podman network connect kind") {
+		t.Errorf("host steps = %v", plan.HostSteps)
+	}
+}
+
+func TestPlanExposure_Invalid(t *testing.T) {
+	for _, req := range []ExposureRequest{
+		{Target: "bogus"},
+		{Target: ExposeTCPService, Audience: "internet"},
+		{Target: ExposeTCPService, Port: 70000},
+	} {
+		if _, err := PlanExposure(rtdetect.RuntimeInfo{}, req); err == nil {
+			t.Errorf("expected error for %+v", req)
+		}
+	}
+}
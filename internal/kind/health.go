@@ -0,0 +1,90 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Cluster health states reported in ClusterHealth.
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+	HealthMissing  = "missing"
+)
+
+// ClusterHealth is a point-in-time health check of a cluster.
+type ClusterHealth struct {
+	Cluster  string       `json:"cluster"`
+	State    string       `json:"state"`
+	APIReady bool         `json:"api_ready"`
+	Nodes    []NodeStatus `json:"nodes,omitempty"`
+	// Problems explains every reason the cluster is not healthy.
+	Problems  []string  `json:"problems,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// SameAs reports whether h and other describe the same condition, ignoring when they
+// were checked, so that a watcher only reports changes.
+func (h *ClusterHealth) SameAs(other *ClusterHealth) bool {
+	return other != nil && h.State == other.State && h.APIReady == other.APIReady &&
+		slices.Equal(h.Problems, other.Problems)
+}
+
+// CheckHealth reports whether a cluster's node containers are running and its API
+// server is ready. Stopped or missing nodes make it degraded, or down when no node
+// runs or the API server does not answer; a cluster Kind no longer knows is missing.
+func (m *Manager) CheckHealth(ctx context.Context, name string) (*ClusterHealth, error) {
+	health := &ClusterHealth{Cluster: name, CheckedAt: time.Now().UTC()}
+	status, err := m.GetClusterStatus(ctx, name)
+	if errors.Is(err, ErrClusterNotFound) {
+		health.State = HealthMissing
+		health.Problems = []string{"cluster not found; it was deleted or its node containers were removed"}
+		return health, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	health.Nodes = status.Nodes
+
+	controlPlane := ""
+	running := 0
+	for _, n := range status.Nodes {
+		if n.Status != "running" {
+			health.Problems = append(health.Problems, fmt.Sprintf("node %s is %s", n.Name, n.Status))
+			continue
+		}
+		running++
+		if n.Disconnected {
+			health.Problems = append(health.Problems, fmt.Sprintf("node %s is disconnected from the %s network", n.Name, kindNetwork))
+		}
+		if controlPlane == "" && n.Role == RoleControlPlane {
+			controlPlane = n.Name
+		}
+		if n.Health == "unhealthy" {
+			health.Problems = append(health.Problems, fmt.Sprintf("load balancer %s does not publish the API server port", n.Name))
+		}
+	}
+
+	if controlPlane != "" {
+		_, err := m.ExecOnNode(ctx, controlPlane, []string{
+			"kubectl", "--kubeconfig=" + adminKubeconfig, "get", "--raw=/readyz"})
+		health.APIReady = err == nil
+	}
+	if !health.APIReady {
+		health.Problems = append(health.Problems, "API server is not ready")
+	}
+
+	switch {
+	case running == 0 || !health.APIReady:
+		health.State = HealthDown
+	case len(health.Problems) > 0:
+		health.State = HealthDegraded
+	default:
+		health.State = HealthHealthy
+	}
+	return health, nil
+}
@@ -0,0 +1,59 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func healthRunner(workerState string, readyErr error) *mockRunner {
+	return &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes", "--name", "dev"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("")},
+		{name: "docker", args: []string{"inspect"},
+			out: []byte("/dev-control-plane|running\n/dev-worker|" + workerState + "\n")},
+		{name: "docker", args: []string{"exec", "dev-control-plane", "kubectl"}, out: []byte("ok"), err: readyErr},
+	}}
+}
+
+func TestCheckHealth(t *testing.T) {
+	tests := []struct {
+		name        string
+		cluster     string
+		workerState string
+		readyErr    error
+		want        string
+		problems    int
+	}{
+		{"healthy", "dev", "running", nil, HealthHealthy, 0},
+		{"stopped worker", "dev", "exited", nil, HealthDegraded, 1},
+		{"disconnected worker", "dev", "running|bridge,", nil, HealthDegraded, 1},
+		{"API not ready", "dev", "running", fmt.Errorf("exit status 1"), HealthDown, 1},
+		{"deleted", "gone", "running", nil, HealthMissing, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newDockerManager(healthRunner(tt.workerState, tt.readyErr))
+			health, err := m.CheckHealth(context.Background(), tt.cluster)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if health.State != tt.want || len(health.Problems) != tt.problems {
+				t.Errorf("health = %+v", health)
+			}
+		})
+	}
+}
+
+func TestClusterHealth_SameAs(t *testing.T) {
+	a := &ClusterHealth{State: HealthDegraded, APIReady: true, Problems: []string{"node dev-worker is exited"}}
+	b := *a
+	b.CheckedAt = a.CheckedAt.Add(1)
+	if !a.SameAs(&b) {
+		t.Error("health differing only in check time should be the same")
+	}
+	b.Problems = nil
+	if a.SameAs(&b) || a.SameAs(nil) {
+		t.Error("health with different problems should differ")
+	}
+}
@@ -0,0 +1,239 @@
+package kind
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// mountPropagations are the propagation modes Kind accepts for extraMounts.
+var mountPropagations = []string{"None", "HostToContainer", "Bidirectional"}
+
+// ValidateMounts checks user-supplied mounts before they go into a config: both paths
+// are set and absolute (the host path may start with ~), the propagation is one Kind
+// knows, and the host path exists. Kind creates a missing host path as an empty
+// directory in the node, which hides a mistyped path until a hostPath volume turns up
+// empty. Whether the path is shared with a runtime VM is a warning from CheckHostPaths.
+func ValidateMounts(mounts []Mount) error {
+	home, _ := os.UserHomeDir()
+	for _, m := range mounts {
+		hostPath := resolveTilde(m.HostPath, home)
+		switch {
+		case m.HostPath == "" || m.ContainerPath == "":
+			return fmt.Errorf("mount %q -> %q: host_path and container_path are required", m.HostPath, m.ContainerPath)
+		case !filepath.IsAbs(hostPath):
+			return fmt.Errorf("mount host_path %q must be absolute; the server's working directory is not "+
+				"the caller's", m.HostPath)
+		case !strings.HasPrefix(m.ContainerPath, "/"):
+			return fmt.Errorf("mount container_path %q must be an absolute path in the node", m.ContainerPath)
+		case m.Propagation != "" && !slices.Contains(mountPropagations, m.Propagation):
+			return fmt.Errorf("mount %s: invalid propagation %q; must be one of %s", m.HostPath, m.Propagation,
+				strings.Join(mountPropagations, ", "))
+		}
+		if _, err := os.Stat(hostPath); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("mount host_path %q does not exist on this host", m.HostPath)
+			}
+			return fmt.Errorf("mount host_path %q: %w", m.HostPath, err)
+		}
+	}
+	return nil
+}
+
+// fileSharing describes which host directories a VM-backed runtime shares into its VM.
+type fileSharing struct {
+	dirs         []string
+	source       string // where dirs came from: a config file or "defaults"
+	instructions string // how to share an additional directory
+}
+
+// CheckHostPaths returns a warning for every mount whose hostPath is not shared into
+// the runtime's VM. On VM-backed runtimes (Docker Desktop, Colima, Podman Machine,
+// Rancher Desktop, Lima) such mounts silently show up as empty directories in the node.
+// Native Linux and WSL share the whole filesystem and never produce warnings.
+func CheckHostPaths(ri rtdetect.RuntimeInfo, mounts []Mount) []string {
+	home, _ := os.UserHomeDir()
+	return checkHostPaths(ri, mounts, home)
+}
+
+func checkHostPaths(ri rtdetect.RuntimeInfo, mounts []Mount, home string) []string {
+	sharing := hostFileSharing(ri, home)
+	if sharing == nil {
+		return nil
+	}
+
+	var warnings []string
+	for _, m := range mounts {
+		hostPath := resolveHostPath(m.HostPath, home)
+		if isShared(hostPath, sharing.dirs, home) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"hostPath %q is not shared with the %s VM (shared per %s: %s). %s",
+			m.HostPath, ri.Backend, sharing.source, strings.Join(sharing.dirs, ", "),
+			strings.ReplaceAll(sharing.instructions, "<path>", hostPath)))
+	}
+	return warnings
+}
+
+// hostFileSharing returns the shared directories for the runtime's backend, or nil
+// when the backend shares the whole host filesystem.
+func hostFileSharing(ri rtdetect.RuntimeInfo, home string) *fileSharing {
+	if ri.OS.OS == "windows" {
+		// Windows hosts mount through WSL2 integration; paths are not comparable.
+		return nil
+	}
+
+	switch ri.Backend {
+	case rtdetect.BackendDockerDesktop:
+		fs := &fileSharing{
+			source: "defaults",
+			instructions: "Add <path> under Docker Desktop → Settings → Resources → File sharing, " +
+				"then apply and restart.",
+		}
+		if dirs, source := dockerDesktopSharedDirs(home); len(dirs) > 0 {
+			fs.dirs, fs.source = dirs, source
+		} else if ri.OS.OS == "darwin" {
+			fs.dirs = []string{"/Users", "/Volumes", "/private", "/tmp", "/var/folders"}
+		} else {
+			fs.dirs = []string{home}
+		}
+		return fs
+
+	case rtdetect.BackendColima:
+		fs := &fileSharing{
+			dirs:   []string{home, "/tmp/colima"},
+			source: "defaults",
+			instructions: "Add <path> to 'mounts' in ~/.colima/default/colima.yaml (or start with " +
+				"'colima start --mount <path>:w') and run 'colima restart'.",
+		}
+		if dirs, source := colimaSharedDirs(home); len(dirs) > 0 {
+			fs.dirs, fs.source = dirs, source
+		}
+		return fs
+
+	case rtdetect.BackendPodmanMachine:
+		return &fileSharing{
+			dirs:   []string{home, "/private", "/var/folders"},
+			source: "defaults",
+			instructions: "Podman Machine volumes are fixed at init time: recreate the machine with " +
+				"'podman machine init -v <path>:<path>' (or use a path under your home directory).",
+		}
+
+	case rtdetect.BackendRancherDesktop:
+		return &fileSharing{
+			dirs:   []string{home, "/Volumes", "/var/folders", "/tmp/rancher-desktop", "/Applications"},
+			source: "defaults",
+			instructions: "Add <path> to 'mounts' in ~/Library/Application Support/rancher-desktop/lima/" +
+				"_config/override.yaml and restart Rancher Desktop.",
+		}
+
+	case rtdetect.BackendLima:
+		return &fileSharing{
+			dirs:   []string{home, "/tmp/lima"},
+			source: "defaults",
+			instructions: "Add <path> to 'mounts' with 'limactl edit <instance>' and restart the instance. " +
+				"Note the default home mount is read-only.",
+		}
+	}
+	return nil
+}
+
+// dockerDesktopSharedDirs reads Docker Desktop's configured file-sharing directories.
+func dockerDesktopSharedDirs(home string) ([]string, string) {
+	base := filepath.Join(home, "Library", "Group Containers", "group.com.docker")
+	if _, err := os.Stat(base); err != nil {
+		base = filepath.Join(home, ".docker", "desktop")
+	}
+	for _, name := range []string{"settings-store.json", "settings.json"} {
+		path := filepath.Join(base, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var settings map[string]any
+		if err := json.Unmarshal(data, &settings); err != nil {
+			continue
+		}
+		for key, val := range settings {
+			if !strings.EqualFold(key, "filesharingDirectories") {
+				continue
+			}
+			list, _ := val.([]any)
+			var dirs []string
+			for _, d := range list {
+				if s, ok := d.(string); ok {
+					dirs = append(dirs, s)
+				}
+			}
+			return dirs, path
+		}
+	}
+	return nil, ""
+}
+
+// colimaSharedDirs reads the mounts of the default Colima profile.
+func colimaSharedDirs(home string) ([]string, string) {
+	dir := os.Getenv("COLIMA_HOME")
+	if dir == "" {
+		dir = filepath.Join(home, ".colima")
+	}
+	path := filepath.Join(dir, "default", "colima.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ""
+	}
+	var cfg struct {
+		Mounts []struct {
+			Location string `yaml:"location"`
+		} `yaml:"mounts"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, ""
+	}
+	var dirs []string
+	for _, m := range cfg.Mounts {
+		dirs = append(dirs, m.Location)
+	}
+	return dirs, path
+}
+
+// resolveHostPath expands ~ and resolves symlinks (e.g. macOS /tmp → /private/tmp)
+// so the path can be compared against the shared directories.
+func resolveHostPath(path, home string) string {
+	path = filepath.Clean(resolveTilde(path, home))
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// isShared reports whether path is one of dirs or lies beneath one of them.
+func isShared(path string, dirs []string, home string) bool {
+	for _, dir := range dirs {
+		for _, d := range []string{filepath.Clean(resolveTilde(dir, home)), resolveHostPath(dir, home)} {
+			if path == d || strings.HasPrefix(path, strings.TrimSuffix(d, "/")+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveTilde expands a leading ~ without resolving symlinks.
+func resolveTilde(path, home string) string {
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
@@ -0,0 +1,101 @@
+package kind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func macRuntime(backend rtdetect.Backend) rtdetect.RuntimeInfo {
+	return rtdetect.RuntimeInfo{Backend: backend, OS: rtdetect.OSInfo{OS: "darwin"}}
+}
+
+func TestCheckHostPaths_NativeNoWarnings(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{Backend: rtdetect.BackendNative, OS: rtdetect.OSInfo{OS: "linux"}}
+	if w := checkHostPaths(ri, []Mount{{HostPath: "/opt/data"}}, "/home/u"); len(w) != 0 {
+		t.Errorf("expected no warnings, got %v", w)
+	}
+}
+
+func TestCheckHostPaths_ColimaDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("COLIMA_HOME", filepath.Join(home, "missing"))
+	mounts := []Mount{
+		{HostPath: "~/src/app"},
+		{HostPath: filepath.Join(home, "data")},
+		{HostPath: "/opt/data"},
+	}
+
+	w := checkHostPaths(macRuntime(rtdetect.BackendColima), mounts, home)
+	if len(w) != 1 {
+		t.Fatalf("expected 1 warning, got %v", w)
+	}
+	if !strings.Contains(w[0], "/opt/data") || !strings.Contains(w[0], "colima") {
+		t.Errorf("warning = %q", w[0])
+	}
+}
+
+func TestCheckHostPaths_ColimaConfig(t *testing.T) {
+	home := t.TempDir()
+	colimaHome := filepath.Join(home, ".colima")
+	t.Setenv("COLIMA_HOME", colimaHome)
+	os.MkdirAll(filepath.Join(colimaHome, "default"), 0o755)
+	os.WriteFile(filepath.Join(colimaHome, "default", "colima.yaml"),
+		[]byte("mounts:\n  - location: /opt/shared\n    writable: true\n"), 0o644)
+
+	mounts := []Mount{{HostPath: "/opt/shared/x"}, {HostPath: filepath.Join(home, "src")}}
+	w := checkHostPaths(macRuntime(rtdetect.BackendColima), mounts, home)
+	if len(w) != 1 || !strings.Contains(w[0], home) {
+		t.Errorf("expected only the home path to warn with custom mounts, got %v", w)
+	}
+}
+
+func TestCheckHostPaths_DockerDesktopSettings(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, "Library", "Group Containers", "group.com.docker")
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "settings-store.json"),
+		[]byte(`{"FilesharingDirectories":["/Users","/data"]}`), 0o644)
+
+	mounts := []Mount{{HostPath: "/data/models"}, {HostPath: "/Volumes/ext"}}
+	w := checkHostPaths(macRuntime(rtdetect.BackendDockerDesktop), mounts, home)
+	if len(w) != 1 || !strings.Contains(w[0], "/Volumes/ext") {
+		t.Fatalf("expected /Volumes/ext to warn, got %v", w)
+	}
+	if !strings.Contains(w[0], "File sharing") {
+		t.Errorf("expected Docker Desktop instructions: %q", w[0])
+	}
+}
+
+func TestIsShared_PrefixBoundary(t *testing.T) {
+	if isShared("/Usersfoo/x", []string{"/Users"}, "/Users/u") {
+		t.Error("/Usersfoo should not match /Users")
+	}
+	if !isShared("/Users/u/x", []string{"/Users"}, "/Users/u") {
+		t.Error("/Users/u/x should match /Users")
+	}
+}
+
+func TestValidateMounts(t *testing.T) {
+	dir := t.TempDir()
+	if err := ValidateMounts([]Mount{{HostPath: dir, ContainerPath: "/src", Propagation: "HostToContainer"}}); err != nil {
+		t.Errorf("valid mount rejected: %v", err)
+	}
+	for _, tc := range []struct {
+		mount Mount
+		want  string
+	}{
+		{Mount{HostPath: filepath.Join(dir, "missing"), ContainerPath: "/src"}, "does not exist"},
+		{Mount{HostPath: "src", ContainerPath: "/src"}, "must be absolute"},
+		{Mount{HostPath: dir, ContainerPath: "src"}, "absolute path in the node"},
+		{Mount{HostPath: dir}, "required"},
+		{Mount{HostPath: dir, ContainerPath: "/src", Propagation: "Shared"}, "invalid propagation"},
+	} {
+		if err := ValidateMounts([]Mount{tc.mount}); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: err = %v, want %q", tc.mount, err, tc.want)
+		}
+	}
+}
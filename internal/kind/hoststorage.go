@@ -0,0 +1,317 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HostStorageNodePath is where a cluster's host storage directory is mounted in every
+// node (see HostStorageMount).
+const HostStorageNodePath = "/var/lib/kind-host-storage"
+
+// Modes of ProvisionHostStorage.
+const (
+	// HostStorageLocalPath points Kind's local-path-provisioner, and so the default
+	// "standard" StorageClass, at the host storage directory.
+	HostStorageLocalPath = "local-path"
+	// HostStorageHostPath installs a StorageClass without a provisioner and a set of
+	// static hostPath PersistentVolumes in the host storage directory.
+	HostStorageHostPath = "hostpath"
+)
+
+// Defaults of HostStorageOptions.
+const (
+	DefaultHostStorageClass   = "host-storage"
+	DefaultHostStorageVolumes = 5
+	DefaultHostStorageSize    = "10Gi"
+)
+
+// Kind's local-path-provisioner deployment and its config map.
+const (
+	localPathNamespace  = "local-path-storage"
+	localPathConfigMap  = "local-path-config"
+	localPathDeployment = "local-path-provisioner"
+	// localPathDefaultNodes is the nodePathMap entry that applies to every node.
+	localPathDefaultNodes = "DEFAULT_PATH_FOR_NON_LISTED_NODES"
+)
+
+// quantityPattern matches a Kubernetes storage quantity such as "10Gi" or "500M".
+var quantityPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?([KMGTPE]i?|k)?$`)
+
+// HostStorageOptions controls ProvisionHostStorage.
+type HostStorageOptions struct {
+	// Mode is HostStorageLocalPath (the default) or HostStorageHostPath.
+	Mode string
+	// StorageClass names the StorageClass of HostStorageHostPath. Default:
+	// DefaultHostStorageClass.
+	StorageClass string
+	// Volumes and VolumeSize are the number and capacity of the PersistentVolumes of
+	// HostStorageHostPath. Default: DefaultHostStorageVolumes of DefaultHostStorageSize.
+	Volumes    int
+	VolumeSize string
+}
+
+// HostStorageResult reports what ProvisionHostStorage configured.
+type HostStorageResult struct {
+	Cluster string `json:"cluster"`
+	Mode    string `json:"mode"`
+	// HostDir is the host directory volume data is written to.
+	HostDir      string   `json:"host_dir"`
+	NodePath     string   `json:"node_path"`
+	StorageClass string   `json:"storage_class"`
+	Volumes      []string `json:"volumes,omitempty"`
+	Steps        []string `json:"steps"`
+	Notes        []string `json:"notes,omitempty"`
+}
+
+// HostStorageMount returns the extraMount that places hostDir at HostStorageNodePath.
+// Add it to every node so volumes can be scheduled anywhere.
+func HostStorageMount(hostDir string) Mount {
+	return Mount{HostPath: hostDir, ContainerPath: HostStorageNodePath}
+}
+
+// HostStorageDir returns the host directory a Kind config mounts at
+// HostStorageNodePath, or "" if it mounts none.
+func HostStorageDir(configYAML string) string {
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return ""
+	}
+	for _, n := range cfg.Nodes {
+		for _, mnt := range n.ExtraMounts {
+			if mnt.ContainerPath == HostStorageNodePath {
+				return ExpandHome(mnt.HostPath)
+			}
+		}
+	}
+	return ""
+}
+
+// ProvisionHostStorage makes PersistentVolumes of a cluster store their data in its
+// host storage directory, which every node must mount at HostStorageNodePath. The
+// data then survives the node containers, e.g. across 'recreate_cluster'.
+func (m *Manager) ProvisionHostStorage(ctx context.Context, clusterName string, opts HostStorageOptions) (*HostStorageResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = HostStorageLocalPath
+	}
+	if opts.Mode != HostStorageLocalPath && opts.Mode != HostStorageHostPath {
+		return nil, fmt.Errorf("unknown mode %q; must be %q or %q", opts.Mode, HostStorageLocalPath, HostStorageHostPath)
+	}
+
+	if opts.StorageClass == "" {
+		opts.StorageClass = DefaultHostStorageClass
+	}
+	if opts.Volumes <= 0 {
+		opts.Volumes = DefaultHostStorageVolumes
+	}
+	if opts.VolumeSize == "" {
+		opts.VolumeSize = DefaultHostStorageSize
+	}
+	if !clusterNamePattern.MatchString(opts.StorageClass) {
+		return nil, fmt.Errorf("invalid StorageClass name %q: use lowercase letters, digits and '-'", opts.StorageClass)
+	}
+	if !quantityPattern.MatchString(opts.VolumeSize) {
+		return nil, fmt.Errorf("invalid volume size %q (e.g. '10Gi')", opts.VolumeSize)
+	}
+
+	nodes, hostDir, err := m.hostStorageNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	r := &HostStorageResult{Cluster: clusterName, Mode: opts.Mode, HostDir: hostDir, NodePath: HostStorageNodePath,
+		Steps: []string{fmt.Sprintf("OK %s mounted at %s on %d node(s)", hostDir, HostStorageNodePath, len(nodes))}}
+
+	if opts.Mode == HostStorageLocalPath {
+		if err := m.configureLocalPath(ctx, clusterName, r); err != nil {
+			return r, err
+		}
+		return r, nil
+	}
+
+	manifest, volumes := hostPathManifest(opts)
+	if out, err := m.ApplyManifest(ctx, clusterName, manifest); err != nil {
+		return r, fmt.Errorf("applying the StorageClass and volumes: %w\nOutput: %s", err, out)
+	}
+	r.StorageClass, r.Volumes = opts.StorageClass, volumes
+	r.Steps = append(r.Steps, fmt.Sprintf("OK StorageClass %s with %d %s hostPath volume(s)",
+		opts.StorageClass, len(volumes), opts.VolumeSize))
+	r.Notes = append(r.Notes, fmt.Sprintf("Claims must request storageClassName %q and at most %s. "+
+		"Released volumes are retained: delete and re-run this tool to reuse them, or remove their data under %s.",
+		opts.StorageClass, opts.VolumeSize, hostDir))
+	return r, nil
+}
+
+// hostStorageNodes returns the cluster's nodes and the host directory they mount at
+// HostStorageNodePath, failing if any node lacks the mount.
+func (m *Manager) hostStorageNodes(ctx context.Context, clusterName string) ([]string, string, error) {
+	all, err := m.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, "", err
+	}
+	var nodes []string
+	hostDir := ""
+	for _, node := range all {
+		if NodeRole(node) == RoleExternalLoadBalancer {
+			continue
+		}
+		info, err := m.inspectLiveNode(ctx, node)
+		if err != nil {
+			return nil, "", err
+		}
+		source := ""
+		for _, mnt := range info.Mounts {
+			if mnt.Destination == HostStorageNodePath && mnt.RW {
+				source = mnt.Source
+			}
+		}
+		if source == "" {
+			return nil, "", fmt.Errorf("node %s has no writable mount at %s; generate the config with host_storage=true "+
+				"and recreate the cluster", node, HostStorageNodePath)
+		}
+		if hostDir != "" && source != hostDir {
+			return nil, "", fmt.Errorf("nodes mount different host directories at %s: %s and %s",
+				HostStorageNodePath, hostDir, source)
+		}
+		hostDir = source
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return nil, "", fmt.Errorf("cluster %q %w", clusterName, ErrClusterNotFound)
+	}
+	return nodes, hostDir, nil
+}
+
+// configureLocalPath sets the path local-path-provisioner creates volumes in on every
+// node to HostStorageNodePath and restarts it.
+func (m *Manager) configureLocalPath(ctx context.Context, clusterName string, r *HostStorageResult) error {
+	out, err := m.Kubectl(ctx, clusterName, "get", "configmap", localPathConfigMap, "-n", localPathNamespace,
+		"-o", `jsonpath={.data.config\.json}`)
+	if err != nil {
+		return fmt.Errorf("reading the local-path-provisioner config (is it installed?): %w", err)
+	}
+	config := make(map[string]any)
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		return fmt.Errorf("parsing the local-path-provisioner config: %w", err)
+	}
+	r.StorageClass = "standard"
+	want := []any{map[string]any{"node": localPathDefaultNodes, "paths": []any{HostStorageNodePath}}}
+	if jsonString(config["nodePathMap"]) == jsonString(want) {
+		r.Steps = append(r.Steps, "OK local-path-provisioner already uses "+HostStorageNodePath)
+		return nil
+	}
+	config["nodePathMap"] = want
+	patch, err := json.Marshal(map[string]any{"data": map[string]string{"config.json": jsonString(config)}})
+	if err != nil {
+		return err
+	}
+	if out, err := m.Kubectl(ctx, clusterName, "patch", "configmap", localPathConfigMap, "-n", localPathNamespace,
+		"--type", "merge", "-p", string(patch)); err != nil {
+		return fmt.Errorf("updating the local-path-provisioner config: %w\nOutput: %s", err, out)
+	}
+	r.Steps = append(r.Steps, "OK local-path-provisioner creates volumes in "+HostStorageNodePath)
+	if out, err := m.Kubectl(ctx, clusterName, "rollout", "restart", "deployment/"+localPathDeployment,
+		"-n", localPathNamespace); err != nil {
+		return fmt.Errorf("restarting local-path-provisioner: %w\nOutput: %s", err, out)
+	}
+	r.Steps = append(r.Steps, "OK restarted local-path-provisioner")
+	r.Notes = append(r.Notes, "New claims of the 'standard' StorageClass store their data on the host; "+
+		"volumes provisioned before stay in the node containers.")
+	return nil
+}
+
+// hostPathManifest returns the StorageClass and PersistentVolumes of HostStorageHostPath
+// and the volume names. Each volume has its own subdirectory, created on first use.
+func hostPathManifest(opts HostStorageOptions) (string, []string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, `apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: %s
+provisioner: kubernetes.io/no-provisioner
+volumeBindingMode: WaitForFirstConsumer
+reclaimPolicy: Retain
+`, opts.StorageClass)
+	var volumes []string
+	for i := 1; i <= opts.Volumes; i++ {
+		name := fmt.Sprintf("%s-%d", opts.StorageClass, i)
+		volumes = append(volumes, name)
+		fmt.Fprintf(&b, `---
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: %[1]s
+  labels:
+    app.kubernetes.io/managed-by: mcp-kind-manager
+spec:
+  storageClassName: %[2]s
+  capacity:
+    storage: %[3]s
+  accessModes: [ReadWriteOnce]
+  persistentVolumeReclaimPolicy: Retain
+  hostPath:
+    path: %[4]s/%[2]s/%[1]s
+    type: DirectoryOrCreate
+`, name, opts.StorageClass, opts.VolumeSize, HostStorageNodePath)
+	}
+	return b.String(), volumes
+}
+
+// HostStorageSource returns the host directory a cluster's nodes mount at
+// HostStorageNodePath, and the image of its nodes, which ClearHostStorage runs.
+func (m *Manager) HostStorageSource(ctx context.Context, clusterName string) (string, string, error) {
+	nodes, hostDir, err := m.hostStorageNodes(ctx, clusterName)
+	if err != nil {
+		return "", "", err
+	}
+	info, err := m.inspectLiveNode(ctx, nodes[0])
+	if err != nil {
+		return "", "", err
+	}
+	return hostDir, info.Config.Image, nil
+}
+
+// ClearHostStorage deletes the contents of a host storage directory from a throwaway
+// container of image, for volume data owned by root that the host user cannot remove.
+// Call it after the cluster is deleted, with the directory and image HostStorageSource
+// returned before, then remove the directory itself with RemoveHostStorageDir.
+func (m *Manager) ClearHostStorage(ctx context.Context, hostDir, image string) error {
+	if hostDir == "" || image == "" {
+		return fmt.Errorf("a host directory and an image are required")
+	}
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "run", "--rm", "-v", hostDir+":/data", "--entrypoint", "find",
+		image, "/data", "-mindepth", "1", "-delete")
+	if err != nil {
+		return fmt.Errorf("clearing %s: %w\nOutput: %s", hostDir, err, string(out))
+	}
+	return nil
+}
+
+// RemoveHostStorageDir removes a cluster's host storage directory. Only directories
+// inside root, the managed storage directory, are removed, so a mount the user pointed
+// at their own data is never deleted.
+func RemoveHostStorageDir(root, dir string) error {
+	if !InStorageDir(root, dir) {
+		return fmt.Errorf("%s %w %s; remove it yourself", dir, ErrOutsideStorageDir, root)
+	}
+	return os.RemoveAll(dir)
+}
+
+// InStorageDir reports whether dir lies inside root, the managed storage directory, and
+// is not root itself.
+func InStorageDir(root, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(ExpandHome(root)), filepath.Clean(dir))
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// jsonString encodes v, which holds only JSON-decoded values, as JSON.
+func jsonString(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
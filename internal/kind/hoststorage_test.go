@@ -0,0 +1,157 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+const hostStorageNode = `[{"Config": {"Image": "kindest/node:v1.31.0"}, "Mounts": [
+  {"Type": "bind", "Source": "/data/storage/dev", "Destination": "/var/lib/kind-host-storage", "RW": true}
+]}]`
+
+func TestHostStorageDir(t *testing.T) {
+	cfg, err := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1, NumWorkers: 1,
+		ExtraMounts: []Mount{HostStorageMount("/data/storage/dev")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := HostStorageDir(cfg); got != "/data/storage/dev" {
+		t.Errorf("HostStorageDir = %q", got)
+	}
+	if got := HostStorageDir("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n"); got != "" {
+		t.Errorf("HostStorageDir without mount = %q", got)
+	}
+}
+
+func TestProvisionHostStorage_HostPath(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte(hostStorageNode)},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "apply", "-f"}, out: []byte("created\n")},
+	}}}
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	r, err := m.ProvisionHostStorage(context.Background(), "dev", HostStorageOptions{Mode: HostStorageHostPath, Volumes: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.HostDir != "/data/storage/dev" || r.StorageClass != DefaultHostStorageClass ||
+		strings.Join(r.Volumes, ",") != "host-storage-1,host-storage-2" {
+		t.Errorf("result = %+v", r)
+	}
+
+	manifest, _ := hostPathManifest(HostStorageOptions{StorageClass: "fast", Volumes: 1, VolumeSize: "1Gi"})
+	if !strings.Contains(manifest, "path: /var/lib/kind-host-storage/fast/fast-1") ||
+		!strings.Contains(manifest, "provisioner: kubernetes.io/no-provisioner") {
+		t.Errorf("manifest:\n%s", manifest)
+	}
+	if _, err := m.ProvisionHostStorage(context.Background(), "dev",
+		HostStorageOptions{Mode: HostStorageHostPath, VolumeSize: "lots"}); err == nil {
+		t.Error("expected an invalid volume size to be rejected")
+	}
+}
+
+func TestProvisionHostStorage_LocalPath(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte(hostStorageNode)},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "get", "configmap"}, out: []byte(
+			`{"nodePathMap":[{"node":"DEFAULT_PATH_FOR_NON_LISTED_NODES","paths":["/var/local-path-provisioner"]}]}`)},
+		{name: "kubectl", args: []string{"--kubeconfig", "*"}},
+	}}}
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	r, err := m.ProvisionHostStorage(context.Background(), "dev", HostStorageOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Join(runner.calls, "\n")
+	if r.StorageClass != "standard" || !strings.Contains(calls, `\"paths\":[\"/var/lib/kind-host-storage\"]`) ||
+		!strings.Contains(calls, "rollout restart deployment/local-path-provisioner") {
+		t.Errorf("result = %+v, calls:\n%s", r, calls)
+	}
+}
+
+func TestProvisionHostStorage_NotMounted(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte(`[{"Mounts": []}]`)},
+	}}
+	_, err := newDockerManager(runner).ProvisionHostStorage(context.Background(), "dev", HostStorageOptions{})
+	if err == nil || !strings.Contains(err.Error(), "host_storage=true") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRemoveHostStorageDir(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "dev")
+	if err := os.MkdirAll(filepath.Join(dir, "pvc-1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, outside := range []string{root, filepath.Dir(root), "/data/mine"} {
+		if err := RemoveHostStorageDir(root, outside); !errors.Is(err, ErrOutsideStorageDir) {
+			t.Errorf("removed %s outside %s", outside, root)
+		}
+	}
+	if err := RemoveHostStorageDir(root, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s still exists: %v", dir, err)
+	}
+}
+
+func TestInStorageDir(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "srv", "storage")
+	tests := map[string]bool{
+		filepath.Join(root, "dev"):                true,
+		filepath.Join(root, "..dev"):              true,
+		filepath.Join(root, "dev", "..", "other"): true,
+		root:                                   false,
+		filepath.Dir(root):                     false,
+		filepath.Join(root, "..", "other"):     false,
+		filepath.Join(root+"-other", "dev"):    false,
+		filepath.Join(root, "dev", "..", ".."): false,
+	}
+	for dir, want := range tests {
+		if got := InStorageDir(root, dir); got != want {
+			t.Errorf("InStorageDir(%q, %q) = %v, want %v", root, dir, got, want)
+		}
+	}
+}
+
+func TestHostStorageSource(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte(hostStorageNode)},
+	}}
+	dir, image, err := newDockerManager(runner).HostStorageSource(context.Background(), "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != "/data/storage/dev" || image != "kindest/node:v1.31.0" {
+		t.Errorf("HostStorageSource = %q, %q", dir, image)
+	}
+}
+
+func TestClearHostStorage(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{{name: "docker", args: []string{"run"}}}}}
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	if err := m.ClearHostStorage(context.Background(), "/data/storage/dev", "kindest/node:v1.31.0"); err != nil {
+		t.Fatal(err)
+	}
+	want := "docker run --rm -v /data/storage/dev:/data --entrypoint find kindest/node:v1.31.0 /data -mindepth 1 -delete"
+	if len(runner.calls) != 1 || runner.calls[0] != want {
+		t.Errorf("calls = %q", runner.calls)
+	}
+	if err := m.ClearHostStorage(context.Background(), "/data/storage/dev", ""); err == nil {
+		t.Error("expected a missing image to be rejected")
+	}
+}
@@ -0,0 +1,142 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigImages returns the distinct node images referenced by a Kind config YAML.
+// It returns an error if any node relies on Kind's default image, since the exact
+// default depends on the installed kind release and cannot be verified offline.
+func ConfigImages(configYAML string) ([]string, error) {
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(cfg.Nodes) == 0 {
+		return nil, fmt.Errorf("config has no nodes; offline mode requires nodes with a pinned image")
+	}
+
+	seen := make(map[string]bool)
+	var images []string
+	for i, node := range cfg.Nodes {
+		if node.Image == "" {
+			return nil, fmt.Errorf("node %d (%s) has no image; offline mode requires a pinned node image "+
+				"(set kubernetes_version when generating the config)", i, node.Role)
+		}
+		if !seen[node.Image] {
+			seen[node.Image] = true
+			images = append(images, node.Image)
+		}
+	}
+	return images, nil
+}
+
+// MissingImages returns the images that are not present in the local runtime image store.
+func (m *Manager) MissingImages(ctx context.Context, images []string) []string {
+	var missing []string
+	for _, image := range images {
+		if _, err := m.runner.Run(ctx, m.runtimeBin(), "image", "inspect", image); err != nil {
+			missing = append(missing, image)
+		}
+	}
+	return missing
+}
+
+// CheckOfflineImages verifies every node image in the config is available locally,
+// so that kind never needs to pull. It fails fast with the list of missing images.
+func (m *Manager) CheckOfflineImages(ctx context.Context, configYAML string) error {
+	images, err := ConfigImages(configYAML)
+	if err != nil {
+		return err
+	}
+	if missing := m.MissingImages(ctx, images); len(missing) > 0 {
+		return fmt.Errorf("offline mode: node images missing locally: %s; "+
+			"use 'load_images' to import them from a tarball", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// CheckNodeImages verifies that every node image pinned in the config is present
+// locally or resolvable in its registry (via "manifest inspect"), so a mistyped mirror
+// or missing tag fails before kind starts creating containers. Nodes without an image
+// use Kind's default and are not checked.
+func (m *Manager) CheckNodeImages(ctx context.Context, configYAML string) error {
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	var images []string
+	for _, node := range cfg.Nodes {
+		if node.Image != "" && !contains(images, node.Image) {
+			images = append(images, node.Image)
+		}
+	}
+
+	var unresolved []string
+	for _, image := range m.MissingImages(ctx, images) {
+		if out, err := m.runner.Run(ctx, m.runtimeBin(), "manifest", "inspect", image); err != nil {
+			m.logger.Debug("node image not resolvable", "image", image, "output", string(out))
+			unresolved = append(unresolved, image)
+		}
+	}
+	if len(unresolved) > 0 {
+		return fmt.Errorf("node images not found locally or in their registry: %s", strings.Join(unresolved, ", "))
+	}
+	return nil
+}
+
+// SaveImages exports the given images from the local runtime into a tarball.
+func (m *Manager) SaveImages(ctx context.Context, images []string, path string) (string, error) {
+	if len(images) == 0 {
+		return "", fmt.Errorf("at least one image is required")
+	}
+	if path == "" {
+		return "", fmt.Errorf("output path is required")
+	}
+
+	m.logger.Info("saving images", "images", images, "path", path)
+	args := append([]string{"save", "-o", ExpandHome(path)}, images...)
+	out, err := m.runner.Run(ctx, m.runtimeBin(), args...)
+	if err != nil {
+		return string(out), fmt.Errorf("%s save failed: %w\nOutput: %s", m.runtimeBin(), err, string(out))
+	}
+	return string(out), nil
+}
+
+// LoadImages imports images from a tarball into the local runtime and, if clusterName
+// is set, also loads them onto the nodes of that Kind cluster.
+func (m *Manager) LoadImages(ctx context.Context, path, clusterName string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("archive path is required")
+	}
+	path = ExpandHome(path)
+
+	m.logger.Info("loading images", "path", path, "cluster", clusterName)
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "load", "-i", path)
+	if err != nil {
+		return string(out), fmt.Errorf("%s load failed: %w\nOutput: %s", m.runtimeBin(), err, string(out))
+	}
+	result := string(out)
+
+	if clusterName != "" {
+		kindOut, err := m.loadArchive(ctx, clusterName, path)
+		return result + kindOut, err
+	}
+	return result, nil
+}
+
+// loadArchive loads the images in a tarball onto the nodes of a Kind cluster.
+func (m *Manager) loadArchive(ctx context.Context, clusterName, path string) (string, error) {
+	if m.lib != nil {
+		return m.libLoadImageArchive(clusterName, path)
+	}
+	out, err := m.runner.Run(m.kindContext(ctx), "kind", "load", "image-archive", path, "--name", clusterName)
+	if err != nil {
+		return string(out), fmt.Errorf("kind load image-archive failed: %w\nOutput: %s", err, string(out))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,105 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfigImages(t *testing.T) {
+	cfg, _ := GenerateConfig(ConfigOptions{
+		ClusterName:       "offline",
+		NumControlPlanes:  1,
+		NumWorkers:        2,
+		KubernetesVersion: "1.31.0",
+	})
+
+	images, err := ConfigImages(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(images) != 1 || images[0] != "kindest/node:v1.31.0" {
+		t.Errorf("images = %v", images)
+	}
+}
+
+func TestConfigImages_Unpinned(t *testing.T) {
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "offline", NumControlPlanes: 1})
+	if _, err := ConfigImages(cfg); err == nil {
+		t.Error("expected error for node without pinned image")
+	}
+}
+
+func TestCheckOfflineImages(t *testing.T) {
+	cfg, _ := GenerateConfig(ConfigOptions{
+		ClusterName:       "offline",
+		NumControlPlanes:  1,
+		KubernetesVersion: "1.31.0",
+	})
+
+	present := &mockRunner{
+		runs: []runCall{
+			{name: "docker", args: []string{"image", "inspect", "kindest/node:v1.31.0"}, out: []byte("[]")},
+		},
+	}
+	if err := newDockerManager(present).CheckOfflineImages(context.Background(), cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := newDockerManager(&mockRunner{}).CheckOfflineImages(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "kindest/node:v1.31.0") {
+		t.Errorf("expected missing image error, got %v", err)
+	}
+}
+
+func TestSaveImages(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "docker", args: []string{"save", "-o", "/tmp/images.tar", "nginx:1.27"}},
+		},
+	}
+	if _, err := newDockerManager(runner).SaveImages(context.Background(), []string{"nginx:1.27"}, "/tmp/images.tar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := newDockerManager(runner).SaveImages(context.Background(), nil, "/tmp/images.tar"); err == nil {
+		t.Error("expected error for empty image list")
+	}
+}
+
+func TestLoadImages_IntoCluster(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "docker", args: []string{"load", "-i", "/tmp/images.tar"}, out: []byte("Loaded image: nginx:1.27\n")},
+			{name: "kind", args: []string{"load", "image-archive", "/tmp/images.tar", "--name", "test"}},
+		},
+	}
+	out, err := newDockerManager(runner).LoadImages(context.Background(), "/tmp/images.tar", "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Loaded image") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestCheckNodeImages(t *testing.T) {
+	cfg := "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n" +
+		"  - role: control-plane\n    image: registry.corp/kind/node:v1.31.0\n" +
+		"  - role: worker\n    image: registry.corp/kind/node:v1.31.0\n" +
+		"  - role: worker\n"
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"image", "inspect"}, err: errors.New("no such image")},
+		{name: "docker", args: []string{"manifest", "inspect", "registry.corp/kind/node:v1.31.0"}, out: []byte("{}")},
+	}}
+	if err := newDockerManager(runner).CheckNodeImages(context.Background(), cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	runner.runs[1].err = errors.New("manifest unknown")
+	err := newDockerManager(runner).CheckNodeImages(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "registry.corp/kind/node:v1.31.0") {
+		t.Errorf("expected unresolved image error, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package kind
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IngressReadyLabel is the node label the Kind ingress controller manifests select.
+const IngressReadyLabel = "ingress-ready"
+
+// ingressPortMappings returns the host port 80 and 443 mappings for the first control
+// plane, leaving out ports the options already map to it. A host port mapped to
+// another node would send ingress traffic elsewhere, so it is an error.
+func ingressPortMappings(opts ConfigOptions) ([]PortMapping, error) {
+	var mappings []PortMapping
+	for _, pm := range DefaultPortMappings(opts.IngressListenAddress) {
+		mapped := false
+		for _, existing := range opts.PortMappings {
+			mapped = mapped || existing.HostPort == pm.HostPort
+		}
+		for _, t := range opts.NodePortMappings {
+			if t.HostPort != pm.HostPort {
+				continue
+			}
+			if !t.Target.Matches(RoleControlPlane, 0) {
+				return nil, fmt.Errorf("ingress ports: host port %d is already mapped to another node", pm.HostPort)
+			}
+			mapped = true
+		}
+		if !mapped {
+			mappings = append(mappings, pm)
+		}
+	}
+	return mappings, nil
+}
+
+// ingressReadyPatches returns the kubeadm InitConfiguration patches that make the
+// kubelet of the first control plane register with ingress-ready=true, one per
+// kubeadm version since v1beta4 turned kubeletExtraArgs into a list.
+func ingressReadyPatches() ([]string, error) {
+	nodeLabels := IngressReadyLabel + "=true"
+	var patches []string
+	for _, version := range kubeadmAPIVersions {
+		var args any = map[string]string{"node-labels": nodeLabels}
+		if version != "kubeadm.k8s.io/v1beta3" {
+			args = []map[string]string{{"name": "node-labels", "value": nodeLabels}}
+		}
+		data, err := yaml.Marshal(map[string]any{
+			"apiVersion":       version,
+			"kind":             "InitConfiguration",
+			"nodeRegistration": map[string]any{"kubeletExtraArgs": args},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling kubeadm patch: %w", err)
+		}
+		patches = append(patches, string(data))
+	}
+	return patches, nil
+}
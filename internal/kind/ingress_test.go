@@ -0,0 +1,56 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateConfig_IngressPorts(t *testing.T) {
+	out, err := GenerateConfig(ConfigOptions{
+		ClusterName:        "web",
+		NumControlPlanes:   1,
+		NumWorkers:         1,
+		PortMappings:       []PortMapping{{HostPort: 443, ContainerPort: 30443}},
+		EnableIngressPorts: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	cp, worker := cfg.Nodes[0], cfg.Nodes[1]
+	// host port 443 is already mapped by the caller, so only 80 is added
+	if len(cp.ExtraPortMappings) != 2 || cp.ExtraPortMappings[1].HostPort != 80 || cp.ExtraPortMappings[1].ContainerPort != 80 {
+		t.Errorf("control plane ports = %+v", cp.ExtraPortMappings)
+	}
+	if len(cp.KubeadmConfigPatches) != 2 {
+		t.Fatalf("expected a patch per kubeadm version:\n%s", out)
+	}
+	for _, patch := range cp.KubeadmConfigPatches {
+		if !strings.Contains(patch, "kind: InitConfiguration") || !strings.Contains(patch, "ingress-ready=true") {
+			t.Errorf("patch does not label the node ingress-ready:\n%s", patch)
+		}
+	}
+	if len(worker.ExtraPortMappings) != 0 || len(worker.KubeadmConfigPatches) != 0 {
+		t.Errorf("worker should be untouched: %+v", worker)
+	}
+}
+
+func TestGenerateConfig_IngressPortsConflict(t *testing.T) {
+	_, err := GenerateConfig(ConfigOptions{
+		ClusterName: "web",
+		NumWorkers:  1,
+		NodePortMappings: []TargetedPortMapping{
+			{PortMapping: PortMapping{HostPort: 80, ContainerPort: 80}, Target: NodeTarget{Role: RoleWorker}},
+		},
+		EnableIngressPorts: true,
+	})
+	if err == nil {
+		t.Error("expected error for host port 80 mapped to a worker")
+	}
+}
@@ -0,0 +1,160 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// Host files read by CheckIPFamily; overridden in tests.
+var (
+	ipv6DisabledPath       = "/proc/sys/net/ipv6/conf/all/disable_ipv6"
+	dockerDaemonConfigPath = "/etc/docker/daemon.json"
+)
+
+// kindNetwork is the container network Kind attaches nodes to.
+const kindNetwork = "kind"
+
+// CheckIPFamily verifies the host prerequisites of an ipv6 or dual-stack config before
+// creation, which otherwise fails deep inside kubeadm: IPv6 enabled in the kernel,
+// ip6tables available and enabled in the Docker daemon, an IPv6-capable 'kind' network
+// and, for Podman, the netavark network backend. Problems come with instructions for
+// the detected backend.
+func (m *Manager) CheckIPFamily(ctx context.Context, configYAML string) PreflightCheck {
+	check := PreflightCheck{Name: "ip-family", Status: CheckPass}
+
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		check.Status, check.Message = CheckWarn, fmt.Sprintf("invalid YAML: %v", err)
+		return check
+	}
+	family := "ipv4"
+	if cfg.Networking != nil && cfg.Networking.IPFamily != "" {
+		family = cfg.Networking.IPFamily
+	}
+	if family == "ipv4" {
+		check.Message = "IPv4 cluster; no IPv6 prerequisites"
+		return check
+	}
+
+	var problems, warnings []string
+	sameKernel := m.runtime.Backend == rtdetect.BackendNative || m.runtime.Backend == rtdetect.BackendWSL
+	if sameKernel {
+		if data, err := os.ReadFile(ipv6DisabledPath); err == nil && strings.TrimSpace(string(data)) == "1" {
+			problems = append(problems, "IPv6 is disabled in the kernel (net.ipv6.conf.all.disable_ipv6=1)")
+		}
+		if _, err := m.runner.LookPath("ip6tables"); err != nil {
+			warnings = append(warnings, "ip6tables was not found in PATH; IPv6 port mappings and NAT need it")
+		}
+	}
+
+	switch m.runtime.Runtime {
+	case rtdetect.RuntimePodman:
+		out, err := m.runner.Run(ctx, "podman", "info", "--format", "{{.Host.NetworkBackend}}")
+		if backend := strings.TrimSpace(string(out)); err == nil && backend == "cni" {
+			warnings = append(warnings, "Podman uses the CNI network backend, which has limited IPv6 support; netavark is recommended")
+		}
+	default:
+		if sameKernel {
+			problems = append(problems, dockerDaemonIPv6Problems(m.runtime.Version)...)
+		}
+	}
+
+	if enabled, exists := m.networkIPv6(ctx); exists && !enabled {
+		problems = append(problems, fmt.Sprintf("the existing %q network was created without IPv6; once no Kind "+
+			"clusters are left, remove it with '%s network rm %s' and Kind recreates it with IPv6",
+			kindNetwork, m.runtimeBin(), kindNetwork))
+	}
+
+	switch {
+	case len(problems) > 0:
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("%s cluster prerequisites missing: %s", family, strings.Join(append(problems, warnings...), "; "))
+	case len(warnings) > 0:
+		check.Status = CheckWarn
+		check.Message = fmt.Sprintf("%s cluster may not work: %s", family, strings.Join(warnings, "; "))
+	case !sameKernel:
+		check.Message = fmt.Sprintf("%s cluster: the 'kind' network supports IPv6 or will be created with it; IPv6 "+
+			"support inside the %s VM could not be verified", family, m.runtime.Backend)
+		return check
+	default:
+		check.Message = fmt.Sprintf("%s cluster prerequisites met", family)
+		return check
+	}
+	check.Message += ". " + ipv6Instructions(m.runtime)
+	return check
+}
+
+// dockerDaemonIPv6Problems checks the Docker daemon config for ip6tables, which IPv6
+// networks need for NAT. Docker enables it by default since 27.0.
+func dockerDaemonIPv6Problems(version string) []string {
+	var daemon struct {
+		IP6Tables *bool `json:"ip6tables"`
+	}
+	if data, err := os.ReadFile(dockerDaemonConfigPath); err == nil {
+		if err := json.Unmarshal(data, &daemon); err != nil {
+			return []string{fmt.Sprintf("%s is not valid JSON: %v", dockerDaemonConfigPath, err)}
+		}
+	}
+	switch {
+	case daemon.IP6Tables != nil && !*daemon.IP6Tables:
+		return []string{fmt.Sprintf("ip6tables is disabled in %s", dockerDaemonConfigPath)}
+	case daemon.IP6Tables == nil && majorVersion(version) > 0 && majorVersion(version) < 27:
+		return []string{fmt.Sprintf("Docker %s does not enable ip6tables by default", version)}
+	}
+	return nil
+}
+
+// networkIPv6 reports whether the 'kind' network has IPv6 enabled, and whether it exists.
+func (m *Manager) networkIPv6(ctx context.Context) (enabled, exists bool) {
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "network", "inspect", kindNetwork)
+	if err != nil {
+		return false, false
+	}
+	var networks []struct {
+		EnableIPv6  bool `json:"EnableIPv6"`   // Docker
+		IPv6Enabled bool `json:"ipv6_enabled"` // Podman
+	}
+	if err := json.Unmarshal(out, &networks); err != nil || len(networks) == 0 {
+		return false, false
+	}
+	return networks[0].EnableIPv6 || networks[0].IPv6Enabled, true
+}
+
+// ipv6Instructions explains how to enable IPv6 for the runtime and backend.
+func ipv6Instructions(ri rtdetect.RuntimeInfo) string {
+	if ri.Runtime == rtdetect.RuntimePodman {
+		s := "Set network_backend = \"netavark\" in containers.conf (switching backends needs 'podman system reset', " +
+			"which removes all containers and images)"
+		if ri.Backend == rtdetect.BackendPodmanMachine {
+			s += " inside the machine ('podman machine ssh'), and check there that net.ipv6.conf.all.disable_ipv6 is 0"
+		}
+		return s + "."
+	}
+	switch ri.Backend {
+	case rtdetect.BackendDockerDesktop:
+		return "In Docker Desktop, open Settings > Docker Engine, add \"ipv6\": true and \"ip6tables\": true, and " +
+			"apply & restart."
+	case rtdetect.BackendColima:
+		return "Run 'colima start --edit', set ipv6: true and ip6tables: true under the docker: section, and let " +
+			"Colima restart."
+	case rtdetect.BackendRancherDesktop, rtdetect.BackendLima:
+		return "Add \"ipv6\": true and \"ip6tables\": true to /etc/docker/daemon.json inside the VM and restart Docker."
+	default:
+		return "Enable IPv6 with 'sudo sysctl -w net.ipv6.conf.all.disable_ipv6=0', add \"ipv6\": true and " +
+			"\"ip6tables\": true to /etc/docker/daemon.json, and restart Docker ('sudo systemctl restart docker')."
+	}
+}
+
+// majorVersion returns the major component of a version such as "27.3.1", or 0.
+func majorVersion(version string) int {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	n, _ := strconv.Atoi(major)
+	return n
+}
@@ -0,0 +1,102 @@
+package kind
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+const ipv6Config = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+networking:
+  ipFamily: dual
+`
+
+// withHostFiles points CheckIPFamily at temp copies of the host files it reads.
+func withHostFiles(t *testing.T, disableIPv6, daemonJSON string) {
+	t.Helper()
+	dir := t.TempDir()
+	oldSysctl, oldDaemon := ipv6DisabledPath, dockerDaemonConfigPath
+	ipv6DisabledPath = filepath.Join(dir, "disable_ipv6")
+	dockerDaemonConfigPath = filepath.Join(dir, "daemon.json")
+	t.Cleanup(func() { ipv6DisabledPath, dockerDaemonConfigPath = oldSysctl, oldDaemon })
+
+	if err := os.WriteFile(ipv6DisabledPath, []byte(disableIPv6+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if daemonJSON != "" {
+		if err := os.WriteFile(dockerDaemonConfigPath, []byte(daemonJSON), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckIPFamily_IPv4(t *testing.T) {
+	check := newDockerManager(&mockRunner{}).CheckIPFamily(context.Background(), "kind: Cluster\n")
+	if check.Status != CheckPass {
+		t.Errorf("check = %+v", check)
+	}
+}
+
+func TestCheckIPFamily_NativeDocker(t *testing.T) {
+	withHostFiles(t, "1", `{"ip6tables": false}`)
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"network", "inspect", "kind"}, out: []byte(`[{"Name":"kind","EnableIPv6":false}]`)},
+	}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker, Backend: rtdetect.BackendNative, Version: "28.0.1",
+	}, nil)
+
+	check := mgr.CheckIPFamily(context.Background(), ipv6Config)
+	if check.Status != CheckFail {
+		t.Fatalf("check = %+v", check)
+	}
+	for _, want := range []string{"disable_ipv6=1", "ip6tables is disabled", "docker network rm kind", "systemctl restart docker"} {
+		if !strings.Contains(check.Message, want) {
+			t.Errorf("message missing %q: %s", want, check.Message)
+		}
+	}
+}
+
+func TestCheckIPFamily_OldDockerDefaults(t *testing.T) {
+	withHostFiles(t, "0", "")
+	mgr := NewManager(&mockRunner{}, rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker, Backend: rtdetect.BackendNative, Version: "24.0.7",
+	}, nil)
+
+	check := mgr.CheckIPFamily(context.Background(), ipv6Config)
+	if check.Status != CheckFail || !strings.Contains(check.Message, "does not enable ip6tables by default") {
+		t.Errorf("check = %+v", check)
+	}
+}
+
+func TestCheckIPFamily_Ready(t *testing.T) {
+	withHostFiles(t, "0", `{"ipv6": true, "ip6tables": true}`)
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"network", "inspect", "kind"}, out: []byte(`[{"Name":"kind","EnableIPv6":true}]`)},
+	}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker, Backend: rtdetect.BackendNative, Version: "24.0.7",
+	}, nil)
+
+	if check := mgr.CheckIPFamily(context.Background(), ipv6Config); check.Status != CheckPass {
+		t.Errorf("check = %+v", check)
+	}
+}
+
+func TestCheckIPFamily_PodmanCNI(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "podman", args: []string{"info"}, out: []byte("cni\n")},
+		{name: "podman", args: []string{"network", "inspect", "kind"}, out: []byte(`[{"name":"kind","ipv6_enabled":true}]`)},
+	}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimePodman, Backend: rtdetect.BackendPodmanMachine}, nil)
+
+	check := mgr.CheckIPFamily(context.Background(), ipv6Config)
+	if check.Status != CheckWarn || !strings.Contains(check.Message, "netavark") || !strings.Contains(check.Message, "podman machine ssh") {
+		t.Errorf("check = %+v", check)
+	}
+}
@@ -0,0 +1,139 @@
+package kind
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kind commands that accept extra arguments.
+const (
+	KindCommandCreateCluster = "create cluster"
+	KindCommandDeleteCluster = "delete cluster"
+)
+
+// kindFlag is a kind CLI flag that may be passed through as an extra argument.
+type kindFlag struct {
+	// value validates the flag's value; nil marks a boolean flag.
+	value func(string) error
+}
+
+// globalKindFlags are accepted by every kind command.
+var globalKindFlags = map[string]kindFlag{
+	"--verbosity": {value: validateVerbosity},
+	"--quiet":     {},
+}
+
+// kindFlagAliases maps the short forms of allowed flags to their long names.
+var kindFlagAliases = map[string]string{"-v": "--verbosity", "-q": "--quiet"}
+
+// replacedKindFlags are kind flags that are not allowed because the config already
+// covers them, with where to set them instead.
+var replacedKindFlags = map[string]string{
+	// The node image must be in the config, which preflight checks and the stored
+	// state read.
+	"--image": "set each node's image in config_yaml instead, e.g. with generate_cluster_config kubernetes_version",
+}
+
+// kindCommandFlags are the flags each command accepts besides the global ones. The
+// flags the manager sets itself (--name, --config, --kubeconfig) are not among them.
+var kindCommandFlags = map[string]map[string]kindFlag{
+	KindCommandCreateCluster: {
+		"--retain": {},
+		"--wait":   {value: validateWait},
+	},
+	KindCommandDeleteCluster: {},
+}
+
+// ValidateKindArgs checks extra arguments for a kind command against the flags it
+// allows and returns them normalized: long flag names, "--flag=value" for flags with a
+// value, "--flag" for booleans set to true; booleans set to false are dropped.
+func ValidateKindArgs(command string, args []string) ([]string, error) {
+	flags, ok := kindCommandFlags[command]
+	if !ok {
+		return nil, fmt.Errorf("kind %s takes no extra arguments", command)
+	}
+	lookup := func(name string) (kindFlag, bool) {
+		if f, ok := flags[name]; ok {
+			return f, true
+		}
+		f, ok := globalKindFlags[name]
+		return f, ok
+	}
+
+	var normalized []string
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		if !strings.HasPrefix(arg, "-") {
+			return nil, fmt.Errorf("unexpected argument %q: only flags are allowed", arg)
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		if long, ok := kindFlagAliases[name]; ok {
+			name = long
+		}
+		flag, ok := lookup(name)
+		if hint, replaced := replacedKindFlags[name]; !ok && replaced {
+			return nil, fmt.Errorf("flag %s is not allowed for kind %s: %s", name, command, hint)
+		}
+		if !ok {
+			return nil, fmt.Errorf("flag %s is not allowed for kind %s; allowed: %s",
+				name, command, strings.Join(allowedKindFlags(flags), ", "))
+		}
+
+		if flag.value == nil {
+			set := true
+			if hasValue {
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid boolean %q", name, value)
+				}
+				set = b
+			}
+			if set {
+				normalized = append(normalized, name)
+			}
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("%s needs a value", name)
+			}
+			i++
+			value = strings.TrimSpace(args[i])
+		}
+		if err := flag.value(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		normalized = append(normalized, name+"="+value)
+	}
+	return normalized, nil
+}
+
+// allowedKindFlags returns the flags a command accepts, sorted.
+func allowedKindFlags(flags map[string]kindFlag) []string {
+	var names []string
+	for name := range flags {
+		names = append(names, name)
+	}
+	for name := range globalKindFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateVerbosity(value string) error {
+	if v, err := strconv.Atoi(value); err != nil || v < 0 {
+		return fmt.Errorf("invalid level %q: must be a non-negative integer", value)
+	}
+	return nil
+}
+
+func validateWait(value string) error {
+	if d, err := time.ParseDuration(value); err != nil || d < 0 {
+		return fmt.Errorf("invalid duration %q (e.g. '60s' or '5m')", value)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestValidateKindArgs(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{KindCommandCreateCluster, []string{"--wait", "60s", "-v=3", "--retain", "--quiet=false"}, "--wait=60s --verbosity=3 --retain", ""},
+		{KindCommandDeleteCluster, []string{"-q"}, "--quiet", ""},
+		{KindCommandCreateCluster, []string{"--name=other"}, "", "not allowed"},
+		{KindCommandCreateCluster, []string{"--kubeconfig", "/tmp/kc"}, "", "not allowed"},
+		{KindCommandDeleteCluster, []string{"--retain"}, "", "not allowed"},
+		{KindCommandCreateCluster, []string{"--image=kindest/node:v1.31.0"}, "", "config_yaml"},
+		{KindCommandCreateCluster, []string{"--wait"}, "", "needs a value"},
+		{KindCommandCreateCluster, []string{"--wait=soon"}, "", "invalid duration"},
+		{KindCommandCreateCluster, []string{"--verbosity=-1"}, "", "invalid level"},
+		{KindCommandCreateCluster, []string{"extra"}, "", "only flags"},
+		{"load docker-image", []string{"--quiet"}, "", "takes no extra arguments"},
+	}
+	for _, tt := range tests {
+		got, err := ValidateKindArgs(tt.command, tt.args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s %v: err = %v, want %q", tt.command, tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(got, " ") != tt.want {
+			t.Errorf("%s %v = %v, %v; want %s", tt.command, tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateCluster_ExtraArgs(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"create", "cluster"}, out: []byte("Creating cluster\n")},
+	}}}
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "test", NumControlPlanes: 1})
+
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	if _, err := m.CreateClusterWithOptions(context.Background(), "test", cfg,
+		CreateOptions{ExtraArgs: []string{"--wait", "60s", "--retain"}}); err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Join(runner.calls, "\n")
+	if !strings.Contains(calls, "--retain") || !strings.Contains(calls, "--wait=60s") {
+		t.Errorf("calls:\n%s", calls)
+	}
+
+	if _, err := m.CreateClusterWithOptions(context.Background(), "test", cfg,
+		CreateOptions{ExtraArgs: []string{"--config=/etc/passwd"}}); err == nil {
+		t.Error("expected --config to be rejected")
+	}
+}
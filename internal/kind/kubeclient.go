@@ -0,0 +1,174 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// clusterClients are the client-go clients of a cluster: a dynamic client for any
+// resource and a RESTMapper resolving resource names like kubectl does.
+type clusterClients struct {
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+}
+
+// clusterClients returns the client-go clients of a cluster, built from the
+// kubeconfig GetKubeconfig returns.
+func (m *Manager) clusterClients(ctx context.Context, clusterName string) (*clusterClients, error) {
+	if m.clients != nil {
+		return m.clients(ctx, clusterName)
+	}
+	kubeconfig, err := m.GetKubeconfig(ctx, clusterName, false)
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig of cluster %q: %w", clusterName, err)
+	}
+	dyn, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating client for cluster %q: %w", clusterName, err)
+	}
+	disc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating discovery client for cluster %q: %w", clusterName, err)
+	}
+	cached := memory.NewMemCacheClient(disc)
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(cached), cached, nil)
+	return &clusterClients{dynamic: dyn, mapper: mapper}, nil
+}
+
+// resource returns the client for a resource type named as kubectl accepts it ("pod",
+// "deployments", "deploy", "jobs.batch", "crd"), scoped to namespace ("default" if
+// empty) when the resource is namespaced.
+func (c *clusterClients) resource(kind, namespace string) (dynamic.ResourceInterface, error) {
+	gvr, err := c.mapper.ResourceFor(schema.ParseGroupResource(strings.ToLower(kind)).WithVersion(""))
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource type %q: %w", kind, err)
+	}
+	gvk, err := c.mapper.KindFor(gvr)
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource type %q: %w", kind, err)
+	}
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource type %q: %w", kind, err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return c.dynamic.Resource(mapping.Resource), nil
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	return c.dynamic.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+// objectSet holds the objects watchObjects tracks, by name.
+type objectSet map[string]*unstructured.Unstructured
+
+// watchObjects lists the objects of res that have the given name, or match selector
+// when name is empty, then watches them until done reports true for the current set.
+// done is first called with the listed objects. It returns the last set with the
+// error done returned, or with ctx's error if ctx ends first. A watch that closes or
+// expires is resumed with a new list.
+func watchObjects(ctx context.Context, res dynamic.ResourceInterface, name, selector string,
+	done func(objectSet) (bool, error)) (objectSet, error) {
+	opts := metav1.ListOptions{LabelSelector: selector}
+	if name != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	}
+	objects := objectSet{}
+	track := func(obj *unstructured.Unstructured, deleted bool) {
+		if name != "" && obj.GetName() != name {
+			return
+		}
+		if deleted {
+			delete(objects, obj.GetName())
+		} else {
+			objects[obj.GetName()] = obj
+		}
+	}
+
+	for {
+		list, err := res.List(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return objects, ctx.Err()
+			}
+			return objects, err
+		}
+		objects = objectSet{}
+		for i := range list.Items {
+			track(&list.Items[i], false)
+		}
+		if ok, err := done(objects); ok || err != nil {
+			return objects, err
+		}
+
+		watchOpts := opts
+		watchOpts.ResourceVersion = list.GetResourceVersion()
+		w, err := res.Watch(ctx, watchOpts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return objects, ctx.Err()
+			}
+			return objects, err
+		}
+		ok, err := consumeWatch(ctx, w, track, func() (bool, error) { return done(objects) })
+		w.Stop()
+		if ok || err != nil {
+			return objects, err
+		}
+		if ctx.Err() != nil {
+			return objects, ctx.Err()
+		}
+	}
+}
+
+// consumeWatch feeds the events of w to track until check reports true or fails, ctx
+// ends, or the watch closes or reports an error, after which the caller lists again.
+func consumeWatch(ctx context.Context, w watch.Interface, track func(*unstructured.Unstructured, bool),
+	check func() (bool, error)) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case ev, open := <-w.ResultChan():
+			if !open {
+				return false, nil
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+				obj, ok := ev.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				track(obj, ev.Type == watch.Deleted)
+				if ok, err := check(); ok || err != nil {
+					return ok, err
+				}
+			case watch.Error:
+				// Typically 410 Gone after a long watch; list again.
+				if status := apierrors.FromObject(ev.Object); !apierrors.IsGone(status) && !apierrors.IsResourceExpired(status) {
+					return false, status
+				}
+				return false, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package kind
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	podsResource        = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	jobsResource        = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	deploymentsResource = workloadResources[WorkloadDeployment]
+	daemonSetsResource  = workloadResources[WorkloadDaemonSet]
+)
+
+// fakeClusters makes m use a fake API server holding objects. The returned function
+// applies change once the first watch has started, so it is seen as an event.
+func fakeClusters(t *testing.T, m *Manager, objects ...runtime.Object) (*dynamicfake.FakeDynamicClient, func(change func())) {
+	t.Helper()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsResource:                           "PodList",
+		jobsResource:                           "JobList",
+		deploymentsResource:                    "DeploymentList",
+		workloadResources[WorkloadStatefulSet]: "StatefulSetList",
+		daemonSetsResource:                     "DaemonSetList",
+		{Version: "v1", Resource: "nodes"}:     "NodeList",
+	}, objects...)
+	watching := make(chan struct{})
+	var once sync.Once
+	client.PrependWatchReactor("*", func(k8stesting.Action) (bool, watch.Interface, error) {
+		once.Do(func() { close(watching) })
+		return false, nil, nil
+	})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Pod"},
+		{Group: "batch", Version: "v1", Kind: "Job"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+		{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	} {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, meta.RESTScopeRoot)
+	m.clients = func(context.Context, string) (*clusterClients, error) {
+		return &clusterClients{dynamic: client, mapper: mapper}, nil
+	}
+
+	var wg sync.WaitGroup
+	t.Cleanup(wg.Wait)
+	return client, func(change func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-watching
+			change()
+		}()
+	}
+}
+
+// object returns an unstructured object of the given type.
+func object(apiVersion, kind, namespace, name string, fields map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	if obj.Object == nil {
+		obj.Object = map[string]any{}
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// update replaces an object in the fake API server.
+func update(t *testing.T, client *dynamicfake.FakeDynamicClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	t.Helper()
+	if _, err := client.Resource(gvr).Namespace(obj.GetNamespace()).Update(context.Background(), obj, metav1.UpdateOptions{}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClusterClientsResource(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	fakeClusters(t, m)
+	clients, _ := m.clusterClients(context.Background(), "dev")
+	for _, kind := range []string{"deployment", "Deployments", "jobs.batch", "node"} {
+		if _, err := clients.resource(kind, ""); err != nil {
+			t.Errorf("resource(%q): %v", kind, err)
+		}
+	}
+	if _, err := clients.resource("widget", ""); err == nil {
+		t.Error("expected an unknown resource type to be rejected")
+	}
+}
@@ -0,0 +1,147 @@
+package kind
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteKubeconfig writes a kubeconfig to disk with 0600 permissions and returns the
+// path it was written to. If path is empty, a temp file is created for the cluster.
+func WriteKubeconfig(clusterName, path, kubeconfig string) (string, error) {
+	if path == "" {
+		f, err := os.CreateTemp("", fmt.Sprintf("kind-%s-kubeconfig-*.yaml", clusterName))
+		if err != nil {
+			return "", fmt.Errorf("creating temp kubeconfig file: %w", err)
+		}
+		defer f.Close()
+		if err := f.Chmod(0o600); err != nil {
+			return "", fmt.Errorf("setting kubeconfig permissions: %w", err)
+		}
+		if _, err := f.WriteString(kubeconfig); err != nil {
+			return "", fmt.Errorf("writing kubeconfig: %w", err)
+		}
+		return f.Name(), nil
+	}
+
+	path = ExpandHome(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("creating kubeconfig directory: %w", err)
+	}
+	// Write a new 0600 file and rename it over path, so the credentials never sit in
+	// an existing file with looser permissions.
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return "", fmt.Errorf("creating temp kubeconfig file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(kubeconfig); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("writing kubeconfig: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("writing kubeconfig: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("replacing kubeconfig: %w", err)
+	}
+	return path, nil
+}
+
+// SetKubeconfigContext renames the current context of a kubeconfig to contextName and
+// sets its default namespace; empty values leave the name or namespace unchanged.
+func SetKubeconfigContext(kubeconfig, contextName, namespace string) (string, error) {
+	return RenameKubeconfig(kubeconfig, KubeconfigNames{Context: contextName, Namespace: namespace})
+}
+
+// KubeconfigNames are the names RenameKubeconfig gives the current context of a
+// kubeconfig and the cluster and user entries it refers to (all kind-<name> as Kind
+// writes them), and the context's default namespace. Empty fields are left unchanged.
+type KubeconfigNames struct {
+	Context   string
+	Cluster   string
+	User      string
+	Namespace string
+}
+
+// RenameKubeconfig applies names to a kubeconfig's current context. Renamed cluster
+// and user entries are renamed in every context that refers to them.
+func RenameKubeconfig(kubeconfig string, names KubeconfigNames) (string, error) {
+	var cfg map[string]any
+	if err := yaml.Unmarshal([]byte(kubeconfig), &cfg); err != nil {
+		return "", fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	current, _ := cfg["current-context"].(string)
+	contexts, _ := cfg["contexts"].([]any)
+	var entry, ctx map[string]any
+	for _, c := range contexts {
+		if e, _ := c.(map[string]any); e != nil && e["name"] == current {
+			entry = e
+			break
+		}
+	}
+	if entry == nil {
+		return "", fmt.Errorf("kubeconfig has no current context")
+	}
+	ctx, _ = entry["context"].(map[string]any)
+	if ctx == nil {
+		ctx = map[string]any{}
+		entry["context"] = ctx
+	}
+
+	if names.Cluster != "" {
+		if old, _ := ctx["cluster"].(string); old != "" {
+			renameKubeconfigEntry(cfg, "clusters", "cluster", old, names.Cluster)
+		}
+	}
+	if names.User != "" {
+		if old, _ := ctx["user"].(string); old != "" {
+			renameKubeconfigEntry(cfg, "users", "user", old, names.User)
+		}
+	}
+	if names.Context != "" {
+		entry["name"] = names.Context
+		cfg["current-context"] = names.Context
+	}
+	if names.Namespace != "" {
+		ctx["namespace"] = names.Namespace
+	}
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("encoding kubeconfig: %w", err)
+	}
+	return string(out), nil
+}
+
+// renameKubeconfigEntry renames the entry old of a kubeconfig list (clusters or users)
+// and the ref field of every context pointing at it.
+func renameKubeconfigEntry(cfg map[string]any, list, ref, old, name string) {
+	entries, _ := cfg[list].([]any)
+	for _, e := range entries {
+		if entry, _ := e.(map[string]any); entry != nil && entry["name"] == old {
+			entry["name"] = name
+		}
+	}
+	contexts, _ := cfg["contexts"].([]any)
+	for _, c := range contexts {
+		entry, _ := c.(map[string]any)
+		if ctx, _ := entry["context"].(map[string]any); ctx != nil && ctx[ref] == old {
+			ctx[ref] = name
+		}
+	}
+}
+
+// ExpandHome expands a leading ~/ to the user's home directory.
+func ExpandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
@@ -0,0 +1,121 @@
+package kind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteKubeconfig_TempFile(t *testing.T) {
+	path, err := WriteKubeconfig("test", "", "apiVersion: v1\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(path)
+
+	if !strings.Contains(filepath.Base(path), "kind-test-kubeconfig-") {
+		t.Errorf("unexpected temp file name: %s", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %o, want 600", info.Mode().Perm())
+	}
+}
+
+func TestWriteKubeconfig_ExplicitPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "kubeconfig")
+	// Pre-create with loose permissions to verify they get tightened.
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte("old"), 0o644)
+
+	got, err := WriteKubeconfig("test", path, "apiVersion: v1\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != path {
+		t.Errorf("path = %q, want %q", got, path)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "apiVersion: v1\n" {
+		t.Errorf("content = %q", data)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %o, want 600", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestSetKubeconfigContext(t *testing.T) {
+	in := `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-dev
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: kind-dev
+  context:
+    cluster: kind-dev
+    user: kind-dev
+current-context: kind-dev
+users:
+- name: kind-dev
+  user: {}
+`
+	out, err := SetKubeconfigContext(in, "dev", "team-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"current-context: dev", "namespace: team-a", "name: dev\n", "name: kind-dev\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	if _, err := SetKubeconfigContext("apiVersion: v1\n", "dev", ""); err == nil {
+		t.Error("expected error for kubeconfig without a current context")
+	}
+}
+
+func TestRenameKubeconfig(t *testing.T) {
+	in := `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-dev
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: kind-dev
+  context:
+    cluster: kind-dev
+    user: kind-dev
+current-context: kind-dev
+users:
+- name: kind-dev
+  user: {}
+`
+	out, err := RenameKubeconfig(in, KubeconfigNames{Context: "acme-dev", Cluster: "acme-dev-cluster", User: "acme-dev-admin"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"current-context: acme-dev", "cluster: acme-dev-cluster", "name: acme-dev-cluster\n",
+		"user: acme-dev-admin", "name: acme-dev-admin\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "kind-dev") || strings.Contains(out, "namespace:") {
+		t.Errorf("unexpected leftovers in:\n%s", out)
+	}
+}
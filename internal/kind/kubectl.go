@@ -0,0 +1,198 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// DefaultKubectlVerbs are the kubectl subcommands allowed by the guarded kubectl tool.
+var DefaultKubectlVerbs = []string{
+	"get", "describe", "logs", "top", "explain", "events",
+	"api-resources", "api-versions", "version", "cluster-info", "apply",
+}
+
+// kubectlConfirmVerbs are allowed verbs that mutate the cluster and need explicit confirmation.
+var kubectlConfirmVerbs = map[string]bool{"apply": true}
+
+// kubectlBlockedFlags would let a caller escape the selected cluster, swap its
+// credentials or TLS trust, or impersonate another identity. They are rejected alone
+// and in the --flag=value form.
+var kubectlBlockedFlags = []string{
+	"--kubeconfig", "--context", "--cluster", "--server", "--token", "--user",
+	"--as", "--as-group", "--as-uid",
+	"--certificate-authority", "--client-certificate", "--client-key", "--insecure-skip-tls-verify",
+}
+
+// kubectlBlockedShortFlags are blocked short flags, also rejected with an attached
+// value (-sVALUE, -s=VALUE).
+var kubectlBlockedShortFlags = []string{"-s"}
+
+// KubectlResult holds the structured result of a kubectl invocation.
+type KubectlResult struct {
+	Command  []string `json:"command"`
+	Stdout   string   `json:"stdout"`
+	Stderr   string   `json:"stderr"`
+	ExitCode int      `json:"exit_code"`
+}
+
+// Kubectl runs kubectl against a Kind cluster. The cluster kubeconfig is written to a
+// private temp file for the duration of the call so the user's ~/.kube/config is not needed.
+func (m *Manager) Kubectl(ctx context.Context, clusterName string, args ...string) (string, error) {
+	kubeconfigPath, cleanup, err := m.tempKubeconfig(ctx, clusterName)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	m.logger.Debug("running kubectl", "cluster", clusterName, "args", args)
+	fullArgs := append([]string{"--kubeconfig", kubeconfigPath}, args...)
+	out, err := m.runner.Run(ctx, "kubectl", fullArgs...)
+	if err != nil {
+		return string(out), fmt.Errorf("kubectl %s failed: %w\nOutput: %s", firstArg(args), err, string(out))
+	}
+	return string(out), nil
+}
+
+// Helm runs helm against a Kind cluster with the same private kubeconfig as Kubectl.
+func (m *Manager) Helm(ctx context.Context, clusterName string, args ...string) (string, error) {
+	if _, err := m.runner.LookPath("helm"); err != nil {
+		return "", fmt.Errorf("helm not found in PATH; install it from https://helm.sh/docs/intro/install/")
+	}
+	kubeconfigPath, cleanup, err := m.tempKubeconfig(ctx, clusterName)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	m.logger.Debug("running helm", "cluster", clusterName, "args", args)
+	fullArgs := append([]string{"--kubeconfig", kubeconfigPath}, args...)
+	out, err := m.runner.Run(ctx, "helm", fullArgs...)
+	if err != nil {
+		return string(out), fmt.Errorf("helm %s failed: %w\nOutput: %s", firstArg(args), err, string(out))
+	}
+	return string(out), nil
+}
+
+// RunKubectl runs kubectl against a Kind cluster and returns stdout, stderr and the exit
+// code separately. A non-zero exit is reported in the result, not as an error; the error
+// is reserved for failures to prepare or start kubectl.
+func (m *Manager) RunKubectl(ctx context.Context, clusterName, namespace string, args []string) (*KubectlResult, error) {
+	kubeconfigPath, cleanup, err := m.tempKubeconfig(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	if namespace != "" {
+		args = append([]string{"--namespace", namespace}, args...)
+	}
+	m.logger.Debug("running kubectl", "cluster", clusterName, "args", args)
+	fullArgs := append([]string{"--kubeconfig", kubeconfigPath}, args...)
+	stdout, stderr, err := m.runner.RunSeparate(ctx, "kubectl", fullArgs...)
+
+	result := &KubectlResult{
+		Command:  append([]string{"kubectl"}, args...),
+		Stdout:   string(stdout),
+		Stderr:   string(stderr),
+		ExitCode: rtdetect.ExitCode(err),
+	}
+	if result.ExitCode == -1 {
+		return result, fmt.Errorf("running kubectl: %w", err)
+	}
+	return result, nil
+}
+
+// CheckKubectlArgs enforces the verb allowlist for the guarded kubectl tool. Verbs in
+// kubectlConfirmVerbs additionally require confirmed to be true. Flags that would
+// retarget kubectl at another cluster or identity are always rejected.
+func CheckKubectlArgs(args []string, allowed []string, confirmed bool) error {
+	if len(args) == 0 {
+		return fmt.Errorf("kubectl arguments are required")
+	}
+	verb := args[0]
+	if strings.HasPrefix(verb, "-") {
+		return fmt.Errorf("the first argument must be the kubectl verb, got flag %q", verb)
+	}
+
+	isAllowed := false
+	for _, a := range allowed {
+		if a == verb {
+			isAllowed = true
+			break
+		}
+	}
+	if !isAllowed {
+		return fmt.Errorf("kubectl verb %q is not allowed; allowed verbs: %s", verb, strings.Join(allowed, ", "))
+	}
+	if kubectlConfirmVerbs[verb] && !confirmed {
+		return fmt.Errorf("kubectl %s modifies the cluster; re-run with confirm=true after review", verb)
+	}
+
+	for _, arg := range args[1:] {
+		if arg == "--" {
+			break
+		}
+		for _, blocked := range kubectlBlockedFlags {
+			if arg == blocked || strings.HasPrefix(arg, blocked+"=") {
+				return fmt.Errorf("flag %q is not allowed; the cluster is selected by the tool", blocked)
+			}
+		}
+		for _, blocked := range kubectlBlockedShortFlags {
+			if strings.HasPrefix(arg, blocked) && !strings.HasPrefix(arg, "--") {
+				return fmt.Errorf("flag %q is not allowed; the cluster is selected by the tool", blocked)
+			}
+		}
+	}
+	return nil
+}
+
+// HasNamespaceFlag reports whether kubectl args select a namespace themselves with
+// -n, --namespace, -A or --all-namespaces.
+func HasNamespaceFlag(args []string) bool {
+	for _, arg := range args {
+		switch {
+		case arg == "-A", arg == "--all-namespaces", strings.HasPrefix(arg, "--all-namespaces="),
+			arg == "--namespace", strings.HasPrefix(arg, "--namespace="),
+			strings.HasPrefix(arg, "-n") && !strings.HasPrefix(arg, "--"):
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyManifest applies a YAML manifest to a Kind cluster with kubectl apply.
+func (m *Manager) ApplyManifest(ctx context.Context, clusterName, manifest string, extraArgs ...string) (string, error) {
+	path, cleanup, err := tempManifest(manifest)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	args := append([]string{"apply", "-f", path}, extraArgs...)
+	return m.Kubectl(ctx, clusterName, args...)
+}
+
+// tempKubeconfig writes the external kubeconfig of a cluster to a 0600 temp file and
+// returns its path along with a cleanup function.
+func (m *Manager) tempKubeconfig(ctx context.Context, clusterName string) (string, func(), error) {
+	kubeconfig, err := m.GetKubeconfig(ctx, clusterName, false)
+	if err != nil {
+		return "", nil, err
+	}
+	path, err := WriteKubeconfig(clusterName, "", kubeconfig)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, func() { os.Remove(path) }, nil
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
@@ -0,0 +1,146 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestKubectl(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "kubeconfig", "--name", "test"}, out: []byte("apiVersion: v1\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "get", "nodes"}, out: []byte("test-control-plane   Ready\n")},
+		},
+	}
+
+	out, err := newDockerManager(runner).Kubectl(context.Background(), "test", "get", "nodes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Ready") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestKubectl_MissingCluster(t *testing.T) {
+	_, err := newDockerManager(&mockRunner{}).Kubectl(context.Background(), "missing", "get", "nodes")
+	if err == nil {
+		t.Error("expected error when kubeconfig cannot be fetched")
+	}
+}
+
+func TestApplyManifest(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "apply", "-f"}, out: []byte("namespace/demo created\n")},
+		},
+	}
+
+	out, err := newDockerManager(runner).ApplyManifest(context.Background(), "test", "kind: Namespace\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "created") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunKubectl_NonZeroExit(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "--namespace", "demo", "get", "pod", "missing"},
+				out: []byte("Error from server (NotFound)\n"), err: exitError(t, 1)},
+		},
+	}
+
+	result, err := newDockerManager(runner).RunKubectl(context.Background(), "test", "demo", []string{"get", "pod", "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != 1 {
+		t.Errorf("ExitCode = %d, want 1", result.ExitCode)
+	}
+	if !strings.Contains(result.Stderr, "NotFound") {
+		t.Errorf("Stderr = %q", result.Stderr)
+	}
+	if strings.Join(result.Command, " ") != "kubectl --namespace demo get pod missing" {
+		t.Errorf("Command = %v", result.Command)
+	}
+}
+
+func TestCheckKubectlArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		confirmed bool
+		wantErr   bool
+	}{
+		{"allowed read verb", []string{"get", "pods"}, false, false},
+		{"disallowed verb", []string{"delete", "pod", "x"}, false, true},
+		{"apply without confirm", []string{"apply", "-f", "x.yaml"}, false, true},
+		{"apply with confirm", []string{"apply", "-f", "x.yaml"}, true, false},
+		{"blocked flag", []string{"get", "pods", "--context=other"}, false, true},
+		{"kubeconfig", []string{"get", "pods", "--kubeconfig", "/tmp/other"}, false, true},
+		{"server", []string{"get", "pods", "--server=https://other:6443"}, false, true},
+		{"server short", []string{"get", "pods", "-s", "https://other:6443"}, false, true},
+		{"server short attached", []string{"get", "pods", "-shttps://other:6443"}, false, true},
+		{"server short equals", []string{"get", "pods", "-s=https://other:6443"}, false, true},
+		{"token", []string{"get", "pods", "--token=abc"}, false, true},
+		{"user", []string{"get", "pods", "--user", "admin"}, false, true},
+		{"as", []string{"get", "secrets", "--as", "system:admin"}, false, true},
+		{"as equals", []string{"get", "secrets", "--as=system:admin"}, false, true},
+		{"as group", []string{"get", "secrets", "--as-group=system:masters"}, false, true},
+		{"as uid", []string{"get", "secrets", "--as-uid", "0"}, false, true},
+		{"certificate authority", []string{"get", "pods", "--certificate-authority=/tmp/ca.crt"}, false, true},
+		{"client certificate", []string{"get", "pods", "--client-certificate", "/tmp/c.crt"}, false, true},
+		{"client key", []string{"get", "pods", "--client-key=/tmp/c.key"}, false, true},
+		{"insecure skip tls verify", []string{"get", "pods", "--insecure-skip-tls-verify"}, false, true},
+		{"insecure skip tls verify equals", []string{"get", "pods", "--insecure-skip-tls-verify=true"}, false, true},
+		{"similar long flag", []string{"get", "pods", "--show-labels", "--sort-by=.metadata.name"}, false, false},
+		{"flag first", []string{"-n", "default", "get", "pods"}, false, true},
+		{"empty", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKubectlArgs(tt.args, DefaultKubectlVerbs, tt.confirmed)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckKubectlArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasNamespaceFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"get", "pods"}, false},
+		{[]string{"get", "pods", "--no-headers"}, false},
+		{[]string{"get", "pods", "-n", "kube-system"}, true},
+		{[]string{"get", "pods", "-nkube-system"}, true},
+		{[]string{"get", "pods", "--namespace=kube-system"}, true},
+		{[]string{"get", "pods", "-A"}, true},
+		{[]string{"get", "pods", "--all-namespaces"}, true},
+	}
+	for _, tt := range tests {
+		if got := HasNamespaceFlag(tt.args); got != tt.want {
+			t.Errorf("HasNamespaceFlag(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+// exitError returns a real *exec.ExitError with the given code.
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	if err == nil {
+		t.Fatal("expected command to fail")
+	}
+	return err
+}
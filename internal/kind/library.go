@@ -0,0 +1,225 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	kindcluster "sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	kindlog "sigs.k8s.io/kind/pkg/log"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// kindProvider is the subset of *kindcluster.Provider used by the library backend.
+type kindProvider interface {
+	Create(name string, options ...kindcluster.CreateOption) error
+	Delete(name, explicitKubeconfigPath string) error
+	List() ([]string, error)
+	KubeConfig(name string, internal bool) (string, error)
+	ListNodes(name string) ([]nodes.Node, error)
+	CollectLogs(name, dir string) error
+}
+
+// NewLibraryManager creates a Manager that drives cluster lifecycle through the kind Go
+// library (sigs.k8s.io/kind) instead of the kind CLI, so no kind binary is needed. Node
+// inspection and exec still go through the container runtime CLI via runner.
+func NewLibraryManager(runner rtdetect.CommandRunner, ri rtdetect.RuntimeInfo, logger *slog.Logger) *Manager {
+	m := NewManager(runner, ri, logger)
+	m.output = &outputLogger{logger: m.logger}
+
+	providerOpt := kindcluster.ProviderWithDocker()
+	if ri.Runtime == rtdetect.RuntimePodman {
+		providerOpt = kindcluster.ProviderWithPodman()
+	}
+	m.lib = kindcluster.NewProvider(providerOpt, kindcluster.ProviderWithLogger(m.output))
+	return m
+}
+
+// UsesLibrary reports whether the Manager uses the kind Go library backend.
+func (m *Manager) UsesLibrary() bool {
+	return m.lib != nil
+}
+
+func (m *Manager) libCreate(ctx context.Context, name, configYAML string, opts CreateOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	existing, err := m.lib.List()
+	if err != nil {
+		return "", fmt.Errorf("listing clusters: %w", err)
+	}
+	if contains(existing, name) {
+		return "", fmt.Errorf("cluster %q %w", name, ErrClusterExists)
+	}
+
+	createOpts := []kindcluster.CreateOption{
+		kindcluster.CreateWithRawConfig([]byte(configYAML)),
+		kindcluster.CreateWithDisplayUsage(false),
+		kindcluster.CreateWithDisplaySalutation(false),
+		kindcluster.CreateWithRetain(opts.RetainOnFailure),
+	}
+	for _, arg := range opts.ExtraArgs {
+		flag, value, _ := strings.Cut(arg, "=")
+		switch flag {
+		case "--wait":
+			wait, _ := time.ParseDuration(value)
+			createOpts = append(createOpts, kindcluster.CreateWithWaitForReady(wait))
+		default:
+			return "", fmt.Errorf("extra kind argument %s needs the kind CLI backend", arg)
+		}
+	}
+
+	m.logger.Info("creating kind cluster", "name", name, "backend", "library")
+	m.output.reset()
+	err = m.lib.Create(name, createOpts...)
+	out := m.output.String()
+	if err != nil {
+		err = fmt.Errorf("kind create cluster failed: %w\nOutput: %s", err, out)
+		if opts.RetainOnFailure {
+			return out, m.retainedFailure(ctx, name, err)
+		}
+		return out, err
+	}
+	return out, nil
+}
+
+func (m *Manager) libDelete(ctx context.Context, name string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	m.logger.Info("deleting kind cluster", "name", name, "backend", "library")
+	m.output.reset()
+	if err := m.lib.Delete(name, ""); err != nil {
+		return m.output.String(), fmt.Errorf("kind delete cluster failed: %w", err)
+	}
+	return m.output.String(), nil
+}
+
+func (m *Manager) libKubeconfig(name string, internal bool) (string, error) {
+	kc, err := m.lib.KubeConfig(name, internal)
+	if err != nil {
+		if n, lerr := m.lib.ListNodes(name); lerr == nil && len(n) == 0 {
+			return "", fmt.Errorf("cluster %q %w", name, ErrClusterNotFound)
+		}
+		return "", fmt.Errorf("kind get kubeconfig failed: %w", err)
+	}
+	return kc, nil
+}
+
+func (m *Manager) libNodes(name string) ([]string, error) {
+	list, err := m.lib.ListNodes(name)
+	if err != nil {
+		return nil, fmt.Errorf("kind get nodes failed: %w", err)
+	}
+	names := make([]string, 0, len(list))
+	for _, n := range list {
+		names = append(names, n.String())
+	}
+	return names, nil
+}
+
+// libLoadImageArchive loads an image archive into every node of a cluster.
+func (m *Manager) libLoadImageArchive(name, path string) (string, error) {
+	list, err := m.lib.ListNodes(name)
+	if err != nil {
+		return "", fmt.Errorf("listing nodes: %w", err)
+	}
+	internal, err := nodeutils.InternalNodes(list)
+	if err != nil {
+		return "", err
+	}
+	if len(internal) == 0 {
+		return "", fmt.Errorf("cluster %q %w", name, ErrClusterNotFound)
+	}
+
+	var loaded []string
+	for _, n := range internal {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("opening image archive: %w", err)
+		}
+		err = nodeutils.LoadImageArchive(n, f)
+		f.Close()
+		if err != nil {
+			return strings.Join(loaded, "\n"), fmt.Errorf("loading image archive into %s: %w", n, err)
+		}
+		loaded = append(loaded, fmt.Sprintf("Loaded image archive into node %s", n))
+	}
+	return strings.Join(loaded, "\n") + "\n", nil
+}
+
+// outputLogger adapts the kind library's logger to slog and captures user-facing
+// messages so they can be returned like CLI output.
+type outputLogger struct {
+	logger *slog.Logger
+	mu     sync.Mutex
+	buf    strings.Builder
+	// progress, when set, also receives each message as it is logged.
+	progress func(line string)
+}
+
+func (l *outputLogger) write(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	msg = strings.TrimRight(msg, "\n")
+	l.buf.WriteString(msg + "\n")
+	if l.progress != nil {
+		l.progress(msg)
+	}
+}
+
+func (l *outputLogger) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf.Reset()
+}
+
+func (l *outputLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+func (l *outputLogger) Warn(message string) {
+	l.logger.Warn(message, "source", "kind")
+	l.write(message)
+}
+
+func (l *outputLogger) Warnf(format string, args ...any) { l.Warn(fmt.Sprintf(format, args...)) }
+
+func (l *outputLogger) Error(message string) {
+	l.logger.Error(message, "source", "kind")
+	l.write(message)
+}
+
+func (l *outputLogger) Errorf(format string, args ...any) { l.Error(fmt.Sprintf(format, args...)) }
+
+func (l *outputLogger) V(level kindlog.Level) kindlog.InfoLogger {
+	return infoLogger{parent: l, level: level}
+}
+
+// infoLogger handles kind's leveled messages: V(0) is user-facing output, higher levels are debug.
+type infoLogger struct {
+	parent *outputLogger
+	level  kindlog.Level
+}
+
+func (i infoLogger) Info(message string) {
+	if i.level > 0 {
+		i.parent.logger.Debug(message, "source", "kind", "v", int(i.level))
+		return
+	}
+	i.parent.logger.Info(message, "source", "kind")
+	i.parent.write(message)
+}
+
+func (i infoLogger) Infof(format string, args ...any) { i.Info(fmt.Sprintf(format, args...)) }
+
+func (i infoLogger) Enabled() bool { return true }
@@ -0,0 +1,169 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	kindcluster "sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+type fakeNode struct {
+	nodes.Node
+	name string
+}
+
+func (n fakeNode) String() string { return n.name }
+
+type fakeProvider struct {
+	clusters  map[string][]string
+	output    *outputLogger
+	created   []string
+	deleted   []string
+	collected []string
+	createErr error
+}
+
+func (p *fakeProvider) Create(name string, _ ...kindcluster.CreateOption) error {
+	p.output.V(0).Info("Creating cluster " + name)
+	p.output.V(1).Info("debug detail")
+	p.created = append(p.created, name)
+	p.clusters[name] = []string{name + "-control-plane"}
+	return p.createErr
+}
+
+func (p *fakeProvider) Delete(name, _ string) error {
+	p.deleted = append(p.deleted, name)
+	return nil
+}
+
+func (p *fakeProvider) List() ([]string, error) {
+	var names []string
+	for name := range p.clusters {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (p *fakeProvider) KubeConfig(name string, _ bool) (string, error) {
+	if _, ok := p.clusters[name]; !ok {
+		return "", errors.New("could not locate any control plane nodes")
+	}
+	return "apiVersion: v1\n", nil
+}
+
+func (p *fakeProvider) CollectLogs(name, dir string) error {
+	p.collected = append(p.collected, dir)
+	return nil
+}
+
+func (p *fakeProvider) ListNodes(name string) ([]nodes.Node, error) {
+	var list []nodes.Node
+	for _, n := range p.clusters[name] {
+		list = append(list, fakeNode{name: n})
+	}
+	return list, nil
+}
+
+func newLibraryTestManager(clusters map[string][]string) (*Manager, *fakeProvider) {
+	m := NewManager(&mockRunner{}, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	m.output = &outputLogger{logger: m.logger}
+	p := &fakeProvider{clusters: clusters, output: m.output}
+	m.lib = p
+	return m, p
+}
+
+func TestLibraryManager_Create(t *testing.T) {
+	m, p := newLibraryTestManager(map[string][]string{})
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1})
+
+	out, err := m.CreateCluster(context.Background(), "dev", cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(p.created) != 1 || p.created[0] != "dev" {
+		t.Errorf("created = %v", p.created)
+	}
+	if !strings.Contains(out, "Creating cluster dev") || strings.Contains(out, "debug detail") {
+		t.Errorf("output should hold only user-facing messages: %q", out)
+	}
+}
+
+func TestLibraryManager_CreateExists(t *testing.T) {
+	m, p := newLibraryTestManager(map[string][]string{"dev": {"dev-control-plane"}})
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1})
+
+	_, err := m.CreateCluster(context.Background(), "dev", cfg)
+	if !errors.Is(err, ErrClusterExists) {
+		t.Fatalf("expected ErrClusterExists, got %v", err)
+	}
+	if len(p.created) != 0 {
+		t.Error("provider Create should not be called")
+	}
+}
+
+func TestLibraryManager_CreateRetainOnFailure(t *testing.T) {
+	m, p := newLibraryTestManager(map[string][]string{})
+	p.createErr = errors.New("failed to init node with kubeadm")
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1})
+
+	_, err := m.CreateClusterWithOptions(context.Background(), "dev", cfg, CreateOptions{RetainOnFailure: true})
+
+	var retained *RetainedCreateError
+	if !errors.As(err, &retained) {
+		t.Fatalf("expected RetainedCreateError, got %v", err)
+	}
+	defer os.RemoveAll(retained.LogsDir)
+	if len(p.collected) != 1 || p.collected[0] != retained.LogsDir || len(p.deleted) != 0 {
+		t.Errorf("collected = %v, deleted = %v", p.collected, p.deleted)
+	}
+}
+
+func TestLibraryManager_NodesAndKubeconfig(t *testing.T) {
+	m, _ := newLibraryTestManager(map[string][]string{"dev": {"dev-control-plane", "dev-worker"}})
+
+	nodes, err := m.GetClusterNodes(context.Background(), "dev")
+	if err != nil || len(nodes) != 2 || nodes[1] != "dev-worker" {
+		t.Errorf("nodes = %v, err = %v", nodes, err)
+	}
+	if _, err := m.GetKubeconfig(context.Background(), "dev", false); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := m.GetKubeconfig(context.Background(), "missing", false); !errors.Is(err, ErrClusterNotFound) {
+		t.Errorf("expected ErrClusterNotFound, got %v", err)
+	}
+	if _, err := m.GetClusterStatus(context.Background(), "missing"); !errors.Is(err, ErrClusterNotFound) {
+		t.Errorf("expected ErrClusterNotFound from status, got %v", err)
+	}
+}
+
+func TestLibraryManager_PreflightSkipsBinary(t *testing.T) {
+	m, _ := newLibraryTestManager(map[string][]string{})
+	for _, c := range m.Preflight(context.Background(), "") {
+		if c.Name == "kind-binary" && c.Status != CheckPass {
+			t.Errorf("kind-binary check = %+v", c)
+		}
+	}
+}
+
+func TestCLIManager_CreateExists(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{{
+		name: "kind", args: []string{"create", "cluster"},
+		out: []byte("ERROR: failed to create cluster: node(s) already exist for a cluster with the name \"dev\"\n"),
+		err: errors.New("exit status 1"),
+	}}}
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1})
+
+	_, err := newDockerManager(runner).CreateCluster(context.Background(), "dev", cfg)
+	if !errors.Is(err, ErrClusterExists) {
+		t.Errorf("expected ErrClusterExists, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// readyPollInterval is the delay between API server readiness probes; overridden in tests.
+var readyPollInterval = 2 * time.Second
+
+// adminKubeconfig is the kubeconfig kubeadm writes inside every control-plane node.
+const adminKubeconfig = "/etc/kubernetes/admin.conf"
+
+// NodeAction is the outcome of stopping or starting a single node container.
+type NodeAction struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+	Ready  string `json:"ready,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// LifecycleResult reports the outcome of StopCluster or StartCluster.
+type LifecycleResult struct {
+	Cluster        string       `json:"cluster"`
+	Nodes          []NodeAction `json:"nodes"`
+	APIServerReady bool         `json:"api_server_ready"`
+	Message        string       `json:"message,omitempty"`
+}
+
+// Failed reports whether any node could not be stopped or started.
+func (r *LifecycleResult) Failed() bool {
+	for _, n := range r.Nodes {
+		if n.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// StopCluster stops a cluster's node containers: workers first, then control
+// planes, then the HA load balancer, so nothing is left routing to a stopped API server.
+func (m *Manager) StopCluster(ctx context.Context, name string) (*LifecycleResult, error) {
+	nodes, err := m.orderedNodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LifecycleResult{Cluster: name}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		result.Nodes = append(result.Nodes, m.nodeAction(ctx, "stop", nodes[i]))
+	}
+	return result, nil
+}
+
+// StartCluster starts a cluster's node containers in order (load balancer, control
+// planes, workers) and, if timeout is positive, waits for the API server to report
+// ready and records each Kubernetes node's Ready condition.
+func (m *Manager) StartCluster(ctx context.Context, name string, timeout time.Duration) (*LifecycleResult, error) {
+	nodes, err := m.orderedNodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LifecycleResult{Cluster: name}
+	var controlPlane string
+	for _, node := range nodes {
+		action := m.nodeAction(ctx, "start", node)
+		if controlPlane == "" && action.Role == RoleControlPlane && action.Error == "" {
+			controlPlane = node
+		}
+		result.Nodes = append(result.Nodes, action)
+	}
+
+	if timeout <= 0 {
+		return result, nil
+	}
+	if controlPlane == "" {
+		result.Message = "no control-plane node started; skipped API server readiness check"
+		return result, nil
+	}
+
+	if err := m.waitAPIServer(ctx, controlPlane, timeout); err != nil {
+		result.Message = err.Error()
+		return result, nil
+	}
+	result.APIServerReady = true
+
+	ready := m.kubeNodeReadiness(ctx, controlPlane)
+	for i := range result.Nodes {
+		result.Nodes[i].Ready = ready[result.Nodes[i].Name]
+	}
+	return result, nil
+}
+
+// PauseCluster freezes every node container's processes ("<runtime> pause") so the
+// cluster stops using CPU while keeping its memory and state. Nodes are paused in stop order.
+func (m *Manager) PauseCluster(ctx context.Context, name string) (*LifecycleResult, error) {
+	nodes, err := m.orderedNodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LifecycleResult{Cluster: name}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		result.Nodes = append(result.Nodes, m.nodeAction(ctx, "pause", nodes[i]))
+	}
+	return result, nil
+}
+
+// UnpauseCluster resumes a paused cluster's node containers in start order.
+func (m *Manager) UnpauseCluster(ctx context.Context, name string) (*LifecycleResult, error) {
+	nodes, err := m.orderedNodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LifecycleResult{Cluster: name}
+	for _, node := range nodes {
+		result.Nodes = append(result.Nodes, m.nodeAction(ctx, "unpause", node))
+	}
+	return result, nil
+}
+
+// orderedNodes returns a cluster's node containers in start order: load balancer,
+// control planes, then workers.
+func (m *Manager) orderedNodes(ctx context.Context, name string) ([]string, error) {
+	if name == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	nodes, err := m.GetClusterNodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q %w", name, ErrClusterNotFound)
+	}
+
+	rank := map[string]int{RoleExternalLoadBalancer: 0, RoleControlPlane: 1, RoleWorker: 2}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return rank[NodeRole(nodes[i])] < rank[NodeRole(nodes[j])]
+	})
+	return nodes, nil
+}
+
+// nodeAction runs "<runtime> <verb> <node>" (start, stop, pause, unpause) and reports the resulting container state.
+func (m *Manager) nodeAction(ctx context.Context, verb, node string) NodeAction {
+	m.logger.Info("node "+verb, "node", node)
+	action := NodeAction{Name: node, Role: NodeRole(node)}
+	if out, err := m.runner.Run(ctx, m.runtimeBin(), verb, node); err != nil {
+		action.Error = fmt.Sprintf("%s failed: %v: %s", verb, err, strings.TrimSpace(string(out)))
+	}
+
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "inspect", "--format", "{{.State.Status}}", node)
+	if err != nil {
+		action.Status = "unknown"
+	} else {
+		action.Status = strings.TrimSpace(string(out))
+	}
+	return action
+}
+
+// waitAPIServer polls the API server's /readyz endpoint from inside a control-plane node.
+func (m *Manager) waitAPIServer(ctx context.Context, controlPlane string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := m.ExecOnNode(ctx, controlPlane, []string{
+			"kubectl", "--kubeconfig=" + adminKubeconfig, "get", "--raw=/readyz"})
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("API server not ready after %s: %v", timeout, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// kubeNodeReadiness returns each Kubernetes node's STATUS column (e.g. "Ready",
+// "NotReady") keyed by node name. Kind node names match their container names.
+func (m *Manager) kubeNodeReadiness(ctx context.Context, controlPlane string) map[string]string {
+	ready := make(map[string]string)
+	out, err := m.ExecOnNode(ctx, controlPlane, []string{
+		"kubectl", "--kubeconfig=" + adminKubeconfig, "get", "nodes", "--no-headers"})
+	if err != nil {
+		m.logger.Warn("listing kubernetes nodes failed", "error", err)
+		return ready
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			ready[fields[0]] = fields[1]
+		}
+	}
+	return ready
+}
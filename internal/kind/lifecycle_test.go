@@ -0,0 +1,137 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// recordingRunner wraps mockRunner and records the runtime start/stop calls in order.
+type recordingRunner struct {
+	mockRunner
+	actions []string
+}
+
+func (r *recordingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if name == "docker" && len(args) == 2 && (args[0] == "start" || args[0] == "stop") {
+		r.actions = append(r.actions, args[0]+" "+args[1])
+	}
+	return r.mockRunner.Run(ctx, name, args...)
+}
+
+func haNodes() runCall {
+	return runCall{name: "kind", args: []string{"get", "nodes"}, out: []byte(
+		"ha-worker\nha-control-plane2\nha-external-load-balancer\nha-control-plane\n")}
+}
+
+func TestStartCluster_Order(t *testing.T) {
+	readyPollInterval = time.Millisecond
+	runner := &recordingRunner{mockRunner: mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"start"}, out: []byte("ok\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte("running\n")},
+		{name: "docker", args: []string{"exec", "ha-control-plane2", "kubectl", "*", "get", "--raw=/readyz"}, out: []byte("ok")},
+		{name: "docker", args: []string{"exec", "ha-control-plane2", "kubectl", "*", "get", "nodes"}, out: []byte(
+			"ha-control-plane    Ready      control-plane   1d   v1.31.0\n" +
+				"ha-control-plane2   Ready      control-plane   1d   v1.31.0\n" +
+				"ha-worker           NotReady   <none>          1d   v1.31.0\n")},
+	}}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	result, err := mgr.StartCluster(context.Background(), "ha", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"start ha-external-load-balancer", "start ha-control-plane2", "start ha-control-plane", "start ha-worker"}
+	if fmt.Sprint(runner.actions) != fmt.Sprint(want) {
+		t.Errorf("start order = %v, want %v", runner.actions, want)
+	}
+	if !result.APIServerReady {
+		t.Error("expected API server ready")
+	}
+	if result.Nodes[3].Ready != "NotReady" || result.Nodes[1].Ready != "Ready" {
+		t.Errorf("node readiness = %+v", result.Nodes)
+	}
+	if result.Failed() {
+		t.Error("no node should have failed")
+	}
+}
+
+func TestStartCluster_APIServerTimeout(t *testing.T) {
+	readyPollInterval = time.Millisecond
+	runner := &mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"start"}, out: []byte("ok\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte("running\n")},
+		{name: "docker", args: []string{"exec"}, err: fmt.Errorf("connection refused")},
+	}}
+
+	result, err := newDockerManager(runner).StartCluster(context.Background(), "ha", 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.APIServerReady {
+		t.Error("API server should not be ready")
+	}
+	if result.Message == "" {
+		t.Error("expected a message explaining the timeout")
+	}
+}
+
+func TestStopCluster_ReverseOrder(t *testing.T) {
+	runner := &recordingRunner{mockRunner: mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"stop", "ha-worker"}, err: fmt.Errorf("exit status 1")},
+		{name: "docker", args: []string{"stop"}, out: []byte("ok\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte("exited\n")},
+	}}}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	result, err := mgr.StopCluster(context.Background(), "ha")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"stop ha-worker", "stop ha-control-plane", "stop ha-control-plane2", "stop ha-external-load-balancer"}
+	if fmt.Sprint(runner.actions) != fmt.Sprint(want) {
+		t.Errorf("stop order = %v, want %v", runner.actions, want)
+	}
+	if !result.Failed() || result.Nodes[0].Error == "" {
+		t.Errorf("expected worker stop failure to be reported: %+v", result.Nodes)
+	}
+}
+
+func TestStopCluster_NotFound(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{{name: "kind", args: []string{"get", "nodes"}, out: []byte("")}}}
+	if _, err := newDockerManager(runner).StopCluster(context.Background(), "missing"); err == nil {
+		t.Error("expected error for cluster without nodes")
+	}
+}
+
+func TestPauseAndUnpauseCluster(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		haNodes(),
+		{name: "docker", args: []string{"pause"}, out: []byte("ok\n")},
+		{name: "docker", args: []string{"unpause"}, out: []byte("ok\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte("paused\n")},
+	}}
+	mgr := newDockerManager(runner)
+
+	result, err := mgr.PauseCluster(context.Background(), "ha")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Nodes) != 4 || result.Nodes[0].Role != RoleWorker || result.Nodes[0].Status != "paused" {
+		t.Errorf("pause result = %+v", result.Nodes)
+	}
+
+	result, err = mgr.UnpauseCluster(context.Background(), "ha")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Nodes[0].Role != RoleExternalLoadBalancer || result.Failed() {
+		t.Errorf("unpause result = %+v", result.Nodes)
+	}
+}
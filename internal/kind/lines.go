@@ -0,0 +1,174 @@
+package kind
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/state"
+)
+
+// This file renders reports one line per item, for the verbosity=summary mode of read
+// tools: a few hundred bytes instead of indented JSON.
+
+// Line renders the summary as "name state 1cp+2w+lb v1.31.0 created 2006-01-02 tags".
+func (s *ClusterSummary) Line() string {
+	fields := []string{s.Name, s.State, fmt.Sprintf("%dcp+%dw", s.ControlPlanes, s.Workers)}
+	if s.LoadBalancer {
+		fields[2] += "+lb"
+	}
+	if s.KubernetesVersion != "" {
+		fields = append(fields, s.KubernetesVersion)
+	}
+	if s.CreatedAt != nil {
+		fields = append(fields, "created "+s.CreatedAt.Format("2006-01-02"))
+	}
+	if len(s.Tags) > 0 {
+		fields = append(fields, state.FormatTags(s.Tags))
+	}
+	if s.Owner != "" {
+		fields = append(fields, "owner="+s.Owner)
+	}
+	if s.Unmanaged {
+		fields = append(fields, "[unmanaged]")
+	}
+	if s.Current {
+		fields = append(fields, "[current]")
+	}
+	return strings.Join(fields, " ")
+}
+
+// Lines renders one line per node: name, role, status, and any API server port,
+// health, network disconnection, and restarts.
+func (s *ClusterStatus) Lines() []string {
+	lines := make([]string, 0, len(s.Nodes))
+	for _, n := range s.Nodes {
+		fields := []string{n.Name, n.Role, n.Status}
+		if n.HostPort != "" {
+			fields = append(fields, "api="+n.HostPort)
+		}
+		if n.Health != "" {
+			fields = append(fields, "health="+n.Health)
+		}
+		if n.Disconnected {
+			fields = append(fields, "[disconnected]")
+		}
+		if n.Restarts > 0 {
+			fields = append(fields, fmt.Sprintf("restarts=%d", n.Restarts))
+		}
+		lines = append(lines, strings.Join(fields, " "))
+	}
+	return lines
+}
+
+// Lines renders one line per image, then the disk totals and any errors.
+func (r *NodeImageReport) Lines() []string {
+	lines := make([]string, 0, len(r.Images)+1)
+	for _, img := range r.Images {
+		name := img.ID
+		if len(img.Tags) > 0 {
+			name = strings.Join(img.Tags, ",")
+		}
+		line := fmt.Sprintf("%s %s nodes=%d", name, formatBytes(img.Size), len(img.Nodes))
+		if img.Pinned {
+			line += " [pinned]"
+		} else if img.Unused {
+			line += " [unused]"
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, fmt.Sprintf("total %s, unused %s", formatBytes(r.DiskBytes), formatBytes(r.UnusedBytes)))
+	for _, e := range r.Errors {
+		lines = append(lines, "error: "+e)
+	}
+	return lines
+}
+
+// Lines renders one line per node image, node container and dangling volume, then the
+// total, suggestions and warnings.
+func (r *DiskUsageReport) Lines() []string {
+	var lines []string
+	for _, img := range r.NodeImages {
+		use := "unused"
+		if img.InUse {
+			use = "in use"
+		}
+		lines = append(lines, fmt.Sprintf("image %s %s %s", img.Image, formatBytes(img.Size), use))
+	}
+	for _, n := range r.Nodes {
+		line := fmt.Sprintf("node %s (%s) %s writable=%s", n.Name, n.Cluster, n.Status, formatBytes(n.WritableBytes))
+		if n.VarBytes > 0 {
+			line += fmt.Sprintf(" var=%s containerd=%s logs=%s",
+				formatBytes(n.VarBytes), formatBytes(n.ContainerdBytes), formatBytes(n.LogBytes))
+		}
+		lines = append(lines, line)
+	}
+	for _, v := range r.DanglingVolumes {
+		lines = append(lines, "dangling volume "+v)
+	}
+	lines = append(lines, "total "+formatBytes(r.TotalBytes))
+	for _, s := range r.Suggestions {
+		lines = append(lines, "suggestion: "+s)
+	}
+	for _, w := range r.Warnings {
+		lines = append(lines, "warning: "+w)
+	}
+	return lines
+}
+
+// Lines renders one line per service port, "namespace/name port/proto -> url" or the
+// port-forward command when no URL reaches it, then the ingress URLs and notes.
+func (r *EndpointReport) Lines() []string {
+	var lines []string
+	for _, svc := range r.Services {
+		for _, p := range svc.Ports {
+			target := p.PortForward
+			if len(p.URLs) > 0 {
+				target = strings.Join(p.URLs, " ")
+			}
+			lines = append(lines, fmt.Sprintf("%s/%s %d/%s %s -> %s", svc.Namespace, svc.Name, p.Port,
+				strings.ToLower(p.Protocol), p.Via, target))
+		}
+	}
+	if len(r.Ingress) > 0 {
+		lines = append(lines, "ingress: "+strings.Join(r.Ingress, " "))
+	}
+	for _, note := range r.Notes {
+		lines = append(lines, "note: "+note)
+	}
+	return lines
+}
+
+// Lines renders one line per object, "action Kind namespace/name", each followed by
+// its field changes ("  ~ path: before -> after", "  + path: value", "  - path"), then
+// the totals.
+func (d *ManifestDiff) Lines() []string {
+	var lines []string
+	for _, res := range d.Resources {
+		name := res.Name
+		if res.Namespace != "" {
+			name = res.Namespace + "/" + name
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s", res.Action, res.Kind, name))
+		for _, c := range res.Changes {
+			switch c.Op {
+			case OpAdd:
+				lines = append(lines, fmt.Sprintf("  + %s: %s", c.Path, compactValue(c.After)))
+			case OpRemove:
+				lines = append(lines, "  - "+c.Path)
+			default:
+				lines = append(lines, fmt.Sprintf("  ~ %s: %s -> %s", c.Path, compactValue(c.Before), compactValue(c.After)))
+			}
+		}
+	}
+	return append(lines, fmt.Sprintf("%d to create, %d to update, %d unchanged", d.Creates, d.Updates, d.Unchanged))
+}
+
+// compactValue renders a field value as compact JSON.
+func compactValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
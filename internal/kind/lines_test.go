@@ -0,0 +1,118 @@
+package kind
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestClusterSummaryLine(t *testing.T) {
+	created := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	s := &ClusterSummary{
+		Name: "ha", State: ClusterRunning, ControlPlanes: 3, Workers: 2, LoadBalancer: true,
+		KubernetesVersion: "v1.31.0", CreatedAt: &created, Tags: map[string]string{"team": "a", "env": "ci"},
+		Owner: "alice",
+	}
+	if got, want := s.Line(), "ha running 3cp+2w+lb v1.31.0 created 2026-05-01 env=ci,team=a owner=alice"; got != want {
+		t.Errorf("Line() = %q, want %q", got, want)
+	}
+	s = &ClusterSummary{Name: "manual", State: ClusterStopped, ControlPlanes: 1, Unmanaged: true, Current: true}
+	if got, want := s.Line(), "manual stopped 1cp+0w [unmanaged] [current]"; got != want {
+		t.Errorf("Line() = %q, want %q", got, want)
+	}
+}
+
+func TestClusterStatusLines(t *testing.T) {
+	s := &ClusterStatus{Name: "dev", Nodes: []NodeStatus{
+		{Name: "dev-control-plane", Role: RoleControlPlane, Status: "running", HostPort: "127.0.0.1:41234"},
+		{Name: "dev-worker", Role: RoleWorker, Status: "running", Disconnected: true, Restarts: 2},
+	}}
+	want := []string{
+		"dev-control-plane control-plane running api=127.0.0.1:41234",
+		"dev-worker worker running [disconnected] restarts=2",
+	}
+	if got := s.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q", got)
+	}
+}
+
+func TestNodeImageReportLines(t *testing.T) {
+	r := &NodeImageReport{
+		Images: []NodeImage{
+			{ID: "sha256:a", Tags: []string{"registry.k8s.io/pause:3.10"}, Size: 512 * 1024, Pinned: true, Unused: true, Nodes: []string{"n1", "n2"}},
+			{ID: "sha256:b", Size: 3 << 20, Unused: true, Nodes: []string{"n1"}},
+		},
+		DiskBytes:   4 << 20,
+		UnusedBytes: 3 << 20,
+		Errors:      []string{"n3: not running"},
+	}
+	want := []string{
+		"registry.k8s.io/pause:3.10 512.0 KiB nodes=2 [pinned]",
+		"sha256:b 3.0 MiB nodes=1 [unused]",
+		"total 4.0 MiB, unused 3.0 MiB",
+		"error: n3: not running",
+	}
+	if got := r.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q", got)
+	}
+}
+
+func TestDiskUsageReportLines(t *testing.T) {
+	r := &DiskUsageReport{
+		NodeImages:      []HostImageUsage{{Image: "kindest/node:v1.31.0", Size: 1 << 30, InUse: true}},
+		Nodes:           []NodeDiskUsage{{Name: "dev-control-plane", Cluster: "dev", Status: "exited", WritableBytes: 2048}},
+		DanglingVolumes: []string{"f00"},
+		TotalBytes:      1<<30 + 2048,
+		Suggestions:     []string{"prune"},
+	}
+	want := []string{
+		"image kindest/node:v1.31.0 1.0 GiB in use",
+		"node dev-control-plane (dev) exited writable=2.0 KiB",
+		"dangling volume f00",
+		"total 1.0 GiB",
+		"suggestion: prune",
+	}
+	if got := r.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q", got)
+	}
+}
+
+func TestEndpointReportLines(t *testing.T) {
+	r := &EndpointReport{
+		Services: []ServiceEndpoint{{Namespace: "default", Name: "web", Ports: []ServicePortEndpoint{
+			{Port: 80, Protocol: "TCP", Via: ViaNodePort, URLs: []string{"http://localhost:8080/"}},
+			{Port: 9090, Protocol: "TCP", Via: ViaPortForward, PortForward: "kubectl port-forward svc/web 9090:9090"},
+		}}},
+		Ingress: []string{"http://localhost/"},
+	}
+	want := []string{
+		"default/web 80/tcp nodeport -> http://localhost:8080/",
+		"default/web 9090/tcp port-forward -> kubectl port-forward svc/web 9090:9090",
+		"ingress: http://localhost/",
+	}
+	if got := r.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q", got)
+	}
+}
+
+func TestManifestDiffLines(t *testing.T) {
+	d := &ManifestDiff{Creates: 1, Updates: 1, Resources: []ResourceDiff{
+		{Kind: "Deployment", Namespace: "shop", Name: "web", Action: ActionUpdate, Changes: []FieldChange{
+			{Path: "metadata.labels", Op: OpRemove, Before: map[string]any{"tier": "front"}},
+			{Path: "spec.replicas", Op: OpReplace, Before: 2.0, After: 3.0},
+			{Path: "spec.type", Op: OpAdd, After: "NodePort"},
+		}},
+		{Kind: "Namespace", Name: "shop", Action: ActionCreate},
+	}}
+	want := []string{
+		"update Deployment shop/web",
+		"  - metadata.labels",
+		"  ~ spec.replicas: 2 -> 3",
+		`  + spec.type: "NodePort"`,
+		"create Namespace shop",
+		"1 to create, 1 to update, 0 unchanged",
+	}
+	if got := d.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package kind
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// hostRoot is prepended to the /sys and /proc paths LinuxHostChecks reads; overridden in tests.
+var hostRoot = "/"
+
+// inotify limits Kind's known-issues page recommends: every node runs its own
+// kubelet and containerd watching files, so distro defaults run out quickly.
+const (
+	minInotifyWatches   = 524288
+	minInotifyInstances = 512
+)
+
+// LinuxHostChecks probes the kernel settings Kind nodes depend on when the container
+// engine runs natively on this Linux host: the cgroup version and controllers, the
+// br_netfilter module and IP forwarding, and the inotify limits. Failing checks
+// carry the exact commands that fix them. It returns nil for other hosts, whose
+// engine runs in a VM with its own kernel.
+func (m *Manager) LinuxHostChecks() []PreflightCheck {
+	if m.runtime.OS.OS != "linux" || m.runtime.Backend != rtdetect.BackendNative {
+		return nil
+	}
+	return []PreflightCheck{cgroupCheck(), netfilterCheck(), inotifyCheck()}
+}
+
+// hostFile reads a file below hostRoot, trimmed.
+func hostFile(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(hostRoot, path))
+	return strings.TrimSpace(string(data)), err
+}
+
+func cgroupCheck() PreflightCheck {
+	check := PreflightCheck{Name: "cgroups", Status: CheckPass}
+	controllers, err := hostFile("sys/fs/cgroup/cgroup.controllers")
+	if err != nil {
+		check.Status = CheckWarn
+		check.Message = "the host uses cgroup v1 (or the hybrid layout); Kubernetes has moved cgroup v1 to maintenance and recent " +
+			"kubelets refuse to start on it, so switch the host to the unified cgroup v2 hierarchy and reboot"
+		check.Remediation = []string{
+			"sudo grubby --update-kernel=ALL --args=systemd.unified_cgroup_hierarchy=1  # Fedora, RHEL",
+			"sudo sed -i 's/^GRUB_CMDLINE_LINUX=\"/&systemd.unified_cgroup_hierarchy=1 /' /etc/default/grub && sudo update-grub  # Debian, Ubuntu",
+			"sudo reboot",
+		}
+		return check
+	}
+	var missing []string
+	for _, c := range []string{"cpu", "memory", "pids"} {
+		if !contains(strings.Fields(controllers), c) {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		check.Status = CheckWarn
+		check.Message = fmt.Sprintf("cgroup v2 is missing the %s controller(s); the kubelet cannot enforce "+
+			"pod limits without them", strings.Join(missing, ", "))
+		check.Remediation = []string{
+			"cat /sys/fs/cgroup/cgroup.controllers",
+			"echo '+" + strings.Join(missing, " +") + "' | sudo tee /sys/fs/cgroup/cgroup.subtree_control",
+		}
+		return check
+	}
+	check.Message = "cgroup v2 with cpu, memory and pids controllers"
+	return check
+}
+
+func netfilterCheck() PreflightCheck {
+	check := PreflightCheck{Name: "netfilter", Status: CheckPass,
+		Message: "br_netfilter is loaded and IP forwarding is on"}
+	var problems []string
+	if _, err := os.Stat(filepath.Join(hostRoot, "proc/sys/net/bridge")); err != nil {
+		problems = append(problems, "the br_netfilter module is not loaded, so bridged pod traffic skips iptables "+
+			"and Services may not route")
+		check.Remediation = append(check.Remediation,
+			"sudo modprobe br_netfilter",
+			"echo br_netfilter | sudo tee /etc/modules-load.d/kind.conf")
+	}
+	if v, err := hostFile("proc/sys/net/ipv4/ip_forward"); err == nil && v != "1" {
+		problems = append(problems, "net.ipv4.ip_forward is off, so nodes cannot reach outside the host")
+		check.Remediation = append(check.Remediation,
+			"sudo sysctl -w net.ipv4.ip_forward=1",
+			"echo 'net.ipv4.ip_forward = 1' | sudo tee /etc/sysctl.d/99-kind-forward.conf")
+	}
+	if len(problems) > 0 {
+		check.Status = CheckWarn
+		check.Message = strings.Join(problems, "; ")
+	}
+	return check
+}
+
+func inotifyCheck() PreflightCheck {
+	check := PreflightCheck{Name: "inotify-limits", Status: CheckPass}
+	watches, err1 := hostFile("proc/sys/fs/inotify/max_user_watches")
+	instances, err2 := hostFile("proc/sys/fs/inotify/max_user_instances")
+	w, errW := strconv.Atoi(watches)
+	i, errI := strconv.Atoi(instances)
+	if err1 != nil || err2 != nil || errW != nil || errI != nil {
+		check.Status, check.Message = CheckWarn, "could not read the inotify limits from /proc/sys/fs/inotify"
+		return check
+	}
+	if w >= minInotifyWatches && i >= minInotifyInstances {
+		check.Message = fmt.Sprintf("inotify max_user_watches=%d, max_user_instances=%d", w, i)
+		return check
+	}
+	check.Status = CheckWarn
+	check.Message = fmt.Sprintf("inotify limits are low (max_user_watches=%d, max_user_instances=%d); "+
+		"multi-node clusters fail with 'too many open files' in kube-proxy or kubelet", w, i)
+	check.Remediation = []string{
+		fmt.Sprintf("sudo sysctl -w fs.inotify.max_user_watches=%d fs.inotify.max_user_instances=%d",
+			max(w, minInotifyWatches), max(i, minInotifyInstances)),
+		fmt.Sprintf("printf 'fs.inotify.max_user_watches = %d\\nfs.inotify.max_user_instances = %d\\n' | "+
+			"sudo tee /etc/sysctl.d/99-kind-inotify.conf", max(w, minInotifyWatches), max(i, minInotifyInstances)),
+	}
+	return check
+}
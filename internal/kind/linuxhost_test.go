@@ -0,0 +1,93 @@
+package kind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// withHostRoot points LinuxHostChecks at a fake root holding files.
+func withHostRoot(t *testing.T, files map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := hostRoot
+	hostRoot = root
+	t.Cleanup(func() { hostRoot = old })
+}
+
+func linuxManager() *Manager {
+	return NewManager(&mockRunner{}, rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker, Backend: rtdetect.BackendNative, Available: true,
+		OS: rtdetect.OSInfo{OS: "linux"},
+	}, nil)
+}
+
+func TestLinuxHostChecks_Healthy(t *testing.T) {
+	withHostRoot(t, map[string]string{
+		"sys/fs/cgroup/cgroup.controllers":            "cpuset cpu io memory hugetlb pids rdma misc\n",
+		"proc/sys/net/bridge/bridge-nf-call-iptables": "1\n",
+		"proc/sys/net/ipv4/ip_forward":                "1\n",
+		"proc/sys/fs/inotify/max_user_watches":        "1048576\n",
+		"proc/sys/fs/inotify/max_user_instances":      "8192\n",
+	})
+	for _, c := range linuxManager().LinuxHostChecks() {
+		if c.Status != CheckPass || len(c.Remediation) != 0 {
+			t.Errorf("%s = %s: %s %v", c.Name, c.Status, c.Message, c.Remediation)
+		}
+	}
+}
+
+func TestLinuxHostChecks_Problems(t *testing.T) {
+	withHostRoot(t, map[string]string{
+		"sys/fs/cgroup/memory/memory.limit_in_bytes": "9223372036854771712\n",
+		"proc/sys/net/ipv4/ip_forward":               "0\n",
+		"proc/sys/fs/inotify/max_user_watches":       "8192\n",
+		"proc/sys/fs/inotify/max_user_instances":     "128\n",
+	})
+	checks := map[string]PreflightCheck{}
+	for _, c := range linuxManager().LinuxHostChecks() {
+		checks[c.Name] = c
+	}
+	for name, want := range map[string]string{
+		"cgroups":        "systemd.unified_cgroup_hierarchy=1",
+		"netfilter":      "sudo modprobe br_netfilter",
+		"inotify-limits": "sudo sysctl -w fs.inotify.max_user_watches=524288 fs.inotify.max_user_instances=512",
+	} {
+		c := checks[name]
+		if c.Status != CheckWarn || !strings.Contains(strings.Join(c.Remediation, "\n"), want) {
+			t.Errorf("%s = %s %v, want a warning with %q", name, c.Status, c.Remediation, want)
+		}
+	}
+	if !strings.Contains(strings.Join(checks["netfilter"].Remediation, "\n"), "net.ipv4.ip_forward=1") {
+		t.Errorf("netfilter remediation should enable IP forwarding: %v", checks["netfilter"].Remediation)
+	}
+}
+
+func TestLinuxHostChecks_MissingControllers(t *testing.T) {
+	withHostRoot(t, map[string]string{"sys/fs/cgroup/cgroup.controllers": "cpuset io memory\n"})
+	c := cgroupCheck()
+	if c.Status != CheckWarn || !strings.Contains(c.Message, "cpu, pids") {
+		t.Errorf("cgroup check = %+v", c)
+	}
+}
+
+func TestLinuxHostChecks_OnlyNativeLinux(t *testing.T) {
+	mgr := NewManager(&mockRunner{}, rtdetect.RuntimeInfo{
+		Backend: rtdetect.BackendDockerDesktop, OS: rtdetect.OSInfo{OS: "linux"},
+	}, nil)
+	if checks := mgr.LinuxHostChecks(); checks != nil {
+		t.Errorf("Docker Desktop runs its own kernel; got %+v", checks)
+	}
+}
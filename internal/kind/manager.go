@@ -2,11 +2,16 @@ package kind
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/containerapi"
 	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
 )
 
@@ -15,6 +20,66 @@ type Manager struct {
 	runner  rtdetect.CommandRunner
 	runtime rtdetect.RuntimeInfo
 	logger  *slog.Logger
+
+	// lib is set by NewLibraryManager to use the kind Go library instead of the CLI.
+	lib    kindProvider
+	output *outputLogger
+
+	// api talks to the runtime's Engine API socket for node inspection and exec when
+	// available; calls fall back to the runtime CLI when it cannot be reached.
+	api nodeAPI
+
+	// progress receives the output of long-running commands line by line; see SetProgress.
+	progress func(line string)
+
+	// configDir keeps each cluster's create config when set; see SetConfigDir.
+	configDir string
+
+	// clients, when set, replaces building client-go clients from the cluster's
+	// kubeconfig; see clusterClients.
+	clients func(ctx context.Context, clusterName string) (*clusterClients, error)
+}
+
+// streamIdleTimeout stops a long-running command that prints nothing for this long.
+// kind create prints a line per step, but pulling a node image can take minutes.
+const streamIdleTimeout = 10 * time.Minute
+
+// SetProgress registers fn to receive the output of long-running commands (cluster
+// creation, image builds) line by line while they run.
+func (m *Manager) SetProgress(fn func(line string)) {
+	m.progress = fn
+	if m.output != nil {
+		m.output.progress = fn
+	}
+}
+
+// runStreaming runs a long-running command, forwarding its output to the progress
+// callback and stopping it after streamIdleTimeout without output. It returns stdout
+// followed by stderr, capped at rtdetect.DefaultMaxOutputBytes each.
+func (m *Manager) runStreaming(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return m.runStreamingInput(ctx, nil, name, args...)
+}
+
+// runStreamingInput is runStreaming with stdin, if not nil, written to the command.
+func (m *Manager) runStreamingInput(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	opts := rtdetect.StreamOptions{IdleTimeout: streamIdleTimeout, Stdin: stdin}
+	if m.progress != nil {
+		opts.OnLine = func(l rtdetect.OutputLine) { m.progress(l.Text) }
+	}
+	res, err := rtdetect.RunStream(ctx, m.runner, opts, name, args...)
+	if res == nil {
+		return nil, err
+	}
+	if res.Truncated {
+		m.logger.Debug("command output truncated", "command", name, "limit", rtdetect.DefaultMaxOutputBytes)
+	}
+	return append(res.Stdout, res.Stderr...), err
+}
+
+// nodeAPI is the subset of *containerapi.Client used by Manager.
+type nodeAPI interface {
+	Inspect(ctx context.Context, container string) (*containerapi.ContainerState, error)
+	Exec(ctx context.Context, container string, cmd []string) ([]byte, error)
 }
 
 // ClusterStatus holds the status of a Kind cluster and its nodes.
@@ -25,9 +90,37 @@ type ClusterStatus struct {
 
 // NodeStatus holds status information for a single node.
 type NodeStatus struct {
-	Name   string `json:"name"`
-	Role   string `json:"role"`
-	Status string `json:"status"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Status   string `json:"status"`
+	HostPort string `json:"host_port,omitempty"`
+	Health   string `json:"health,omitempty"`
+	// Disconnected is set when the node container is not attached to the kind network,
+	// e.g. after disconnect_node.
+	Disconnected bool `json:"disconnected,omitempty"`
+	// Restarts and StartedAt are only reported when the container API is available.
+	Restarts  int        `json:"restarts,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// Node roles reported in NodeStatus.
+const (
+	RoleControlPlane         = "control-plane"
+	RoleWorker               = "worker"
+	RoleExternalLoadBalancer = "external-load-balancer"
+)
+
+// NodeRole infers a node's role from its Kind container name. HA clusters get an
+// extra "<cluster>-external-load-balancer" haproxy container in front of the API servers.
+func NodeRole(nodeName string) string {
+	switch {
+	case strings.HasSuffix(nodeName, RoleExternalLoadBalancer):
+		return RoleExternalLoadBalancer
+	case strings.Contains(nodeName, RoleControlPlane):
+		return RoleControlPlane
+	default:
+		return RoleWorker
+	}
 }
 
 // NewManager creates a new Kind CLI manager.
@@ -38,63 +131,149 @@ func NewManager(runner rtdetect.CommandRunner, ri rtdetect.RuntimeInfo, logger *
 	if logger == nil {
 		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
-	return &Manager{
+	m := &Manager{
 		runner:  runner,
 		runtime: ri,
 		logger:  logger,
 	}
+	if client := containerapi.New(ri.SocketPath); client != nil {
+		m.api = client
+	}
+	return m
 }
 
-// kindArgs returns extra args for the kind CLI based on the runtime (e.g. podman provider).
-func (m *Manager) kindArgs() []string {
+// kindEnv returns the environment the kind CLI needs for the runtime. kind has no
+// provider flag; it selects a non-Docker provider from KIND_EXPERIMENTAL_PROVIDER
+// (podman, and nerdctl once it is a detected runtime).
+func (m *Manager) kindEnv() []string {
 	if m.runtime.Runtime == rtdetect.RuntimePodman {
-		return []string{"--runtime", "podman"}
+		return []string{"KIND_EXPERIMENTAL_PROVIDER=podman"}
 	}
 	return nil
 }
 
+// kindContext returns ctx carrying kindEnv, for running the kind CLI.
+func (m *Manager) kindContext(ctx context.Context) context.Context {
+	return rtdetect.WithEnv(ctx, m.kindEnv()...)
+}
+
+// runtimeBin returns the container runtime CLI binary to use for node operations.
+func (m *Manager) runtimeBin() string {
+	if m.runtime.Runtime == rtdetect.RuntimePodman {
+		return "podman"
+	}
+	return "docker"
+}
+
 // CreateCluster creates a Kind cluster from the given config YAML.
 func (m *Manager) CreateCluster(ctx context.Context, name string, configYAML string) (string, error) {
-	if name == "" {
-		return "", fmt.Errorf("cluster name is required")
+	return m.CreateClusterWithOptions(ctx, name, configYAML, CreateOptions{})
+}
+
+// CreateClusterWithOptions creates a Kind cluster from the given config YAML. With
+// RetainOnFailure a failed create returns a *RetainedCreateError; a cancelled create
+// is still deleted.
+func (m *Manager) CreateClusterWithOptions(ctx context.Context, name string, configYAML string, opts CreateOptions) (string, error) {
+	if err := ValidateClusterName(name); err != nil {
+		return "", err
 	}
 	if err := ValidateConfig(configYAML); err != nil {
 		return "", fmt.Errorf("invalid config: %w", err)
 	}
-
-	tmpFile, err := os.CreateTemp("", "kind-config-*.yaml")
+	extra, err := ValidateKindArgs(KindCommandCreateCluster, opts.ExtraArgs)
 	if err != nil {
-		return "", fmt.Errorf("creating temp config file: %w", err)
+		return "", err
 	}
-	defer os.Remove(tmpFile.Name())
-
-	if _, err := tmpFile.WriteString(configYAML); err != nil {
-		tmpFile.Close()
-		return "", fmt.Errorf("writing config to temp file: %w", err)
+	if slices.Contains(extra, "--retain") {
+		opts.RetainOnFailure = true
+		extra = slices.DeleteFunc(extra, func(arg string) bool { return arg == "--retain" })
+	}
+	opts.ExtraArgs = extra
+	if m.lib != nil {
+		if stored := m.StoredConfigPath(name); stored != "" {
+			if err := writePrivateFile(stored, []byte(configYAML)); err != nil {
+				return "", fmt.Errorf("storing cluster config: %w", err)
+			}
+		}
+		return m.libCreate(ctx, name, configYAML, opts)
 	}
-	tmpFile.Close()
 
-	args := append(m.kindArgs(), "create", "cluster", "--name", name, "--config", tmpFile.Name())
+	configPath, stdin, cleanup, err := m.configSource(name, configYAML)
+	defer cleanup()
+	if err != nil {
+		return "", err
+	}
+	args := m.createArgs(name, configPath)
+	if opts.RetainOnFailure {
+		args = append(args, "--retain")
+	}
+	args = append(args, opts.ExtraArgs...)
 
 	m.logger.Info("creating kind cluster", "name", name)
-	out, err := m.runner.Run(ctx, "kind", args...)
+	out, err := m.runStreamingInput(m.kindContext(ctx), stdin, "kind", args...)
 	if err != nil {
-		return string(out), fmt.Errorf("kind create cluster failed: %w\nOutput: %s", err, string(out))
+		if strings.Contains(string(out), "already exist for a cluster with the name") {
+			return string(out), fmt.Errorf("cluster %q %w", name, ErrClusterExists)
+		}
+		if ctx.Err() != nil {
+			return string(out), m.cleanupCancelledCreate(ctx, name)
+		}
+		err = fmt.Errorf("kind create cluster failed: %w\nOutput: %s", err, string(out))
+		if opts.RetainOnFailure {
+			return string(out), m.retainedFailure(ctx, name, err)
+		}
+		return string(out), err
 	}
 
 	return string(out), nil
 }
 
+// cancelCleanupTimeout bounds the deletion of a partially created cluster.
+const cancelCleanupTimeout = 2 * time.Minute
+
+// cleanupCancelledCreate deletes whatever a cancelled kind create left behind, so a
+// retry does not fail on half-created node containers. It returns the error to report
+// for the cancelled create.
+func (m *Manager) cleanupCancelledCreate(ctx context.Context, name string) error {
+	m.logger.Warn("cluster creation cancelled, deleting partially created cluster", "name", name)
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelCleanupTimeout)
+	defer cancel()
+	if _, err := m.DeleteCluster(cleanupCtx, name); err != nil {
+		return fmt.Errorf("kind create cluster cancelled: %w; deleting the partial cluster failed: %v", ctx.Err(), err)
+	}
+	return fmt.Errorf("kind create cluster cancelled: %w; the partial cluster was deleted", ctx.Err())
+}
+
+// createArgs returns the kind CLI arguments for creating a cluster from a config file.
+func (m *Manager) createArgs(name, configPath string) []string {
+	return []string{"create", "cluster", "--name", name, "--config", configPath}
+}
+
 // DeleteCluster deletes a Kind cluster by name.
 func (m *Manager) DeleteCluster(ctx context.Context, name string) (string, error) {
+	return m.DeleteClusterWithArgs(ctx, name, nil)
+}
+
+// DeleteClusterWithArgs deletes a Kind cluster by name, passing extra kind delete
+// cluster flags checked with ValidateKindArgs.
+func (m *Manager) DeleteClusterWithArgs(ctx context.Context, name string, extraArgs []string) (string, error) {
 	if name == "" {
 		return "", fmt.Errorf("cluster name is required")
 	}
-
-	args := append(m.kindArgs(), "delete", "cluster", "--name", name)
+	extra, err := ValidateKindArgs(KindCommandDeleteCluster, extraArgs)
+	if err != nil {
+		return "", err
+	}
+	if m.lib != nil {
+		if len(extra) > 0 {
+			return "", fmt.Errorf("extra kind arguments %s need the kind CLI backend", strings.Join(extra, " "))
+		}
+		return m.libDelete(ctx, name)
+	}
 
 	m.logger.Info("deleting kind cluster", "name", name)
-	out, err := m.runner.Run(ctx, "kind", args...)
+	args := append([]string{"delete", "cluster", "--name", name}, extra...)
+	out, err := m.runner.Run(m.kindContext(ctx), "kind", args...)
 	if err != nil {
 		return string(out), fmt.Errorf("kind delete cluster failed: %w\nOutput: %s", err, string(out))
 	}
@@ -105,9 +284,17 @@ func (m *Manager) DeleteCluster(ctx context.Context, name string) (string, error
 // ListClusters returns a list of Kind cluster names.
 func (m *Manager) ListClusters(ctx context.Context) ([]string, error) {
 	m.logger.Debug("listing kind clusters")
-	args := append(m.kindArgs(), "get", "clusters")
-
-	out, err := m.runner.Run(ctx, "kind", args...)
+	if m.lib != nil {
+		clusters, err := m.lib.List()
+		if err != nil {
+			return nil, fmt.Errorf("kind get clusters failed: %w", err)
+		}
+		if clusters == nil {
+			clusters = []string{}
+		}
+		return clusters, nil
+	}
+	out, err := m.runner.Run(m.kindContext(ctx), "kind", "get", "clusters")
 	if err != nil {
 		return nil, fmt.Errorf("kind get clusters failed: %w\nOutput: %s", err, string(out))
 	}
@@ -135,12 +322,15 @@ func (m *Manager) GetKubeconfig(ctx context.Context, name string, internal bool)
 	}
 
 	m.logger.Debug("getting kubeconfig", "cluster", name, "internal", internal)
-	args := append(m.kindArgs(), "get", "kubeconfig", "--name", name)
+	if m.lib != nil {
+		return m.libKubeconfig(name, internal)
+	}
+	args := []string{"get", "kubeconfig", "--name", name}
 	if internal {
 		args = append(args, "--internal")
 	}
 
-	out, err := m.runner.Run(ctx, "kind", args...)
+	out, err := m.runner.Run(m.kindContext(ctx), "kind", args...)
 	if err != nil {
 		return "", fmt.Errorf("kind get kubeconfig failed: %w\nOutput: %s", err, string(out))
 	}
@@ -155,63 +345,213 @@ func (m *Manager) GetClusterStatus(ctx context.Context, name string) (*ClusterSt
 	}
 
 	m.logger.Debug("getting cluster status", "cluster", name)
-	args := append(m.kindArgs(), "get", "nodes", "--name", name)
-
-	out, err := m.runner.Run(ctx, "kind", args...)
+	nodes, err := m.GetClusterNodes(ctx, name)
 	if err != nil {
-		return nil, fmt.Errorf("kind get nodes failed: %w\nOutput: %s", err, string(out))
+		return nil, err
 	}
-
-	output := strings.TrimSpace(string(out))
-	if output == "" {
-		return nil, fmt.Errorf("cluster %q not found or has no nodes", name)
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q %w", name, ErrClusterNotFound)
 	}
 
-	status := &ClusterStatus{Name: name}
+	status := &ClusterStatus{Name: name, Nodes: make([]NodeStatus, len(nodes))}
+	for i, nodeName := range nodes {
+		status.Nodes[i] = NodeStatus{Name: nodeName, Role: NodeRole(nodeName)}
+	}
 
-	runtimeBin := "docker"
-	if m.runtime.Runtime == rtdetect.RuntimePodman {
-		runtimeBin = "podman"
+	// The load balancer's published port doesn't depend on the inspection, so look it
+	// up alongside it.
+	var wg sync.WaitGroup
+	hostPorts := make([]string, len(nodes))
+	for i, ns := range status.Nodes {
+		if ns.Role != RoleExternalLoadBalancer {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hostPorts[i] = m.loadBalancerPort(ctx, ns.Name)
+		}()
 	}
+	m.inspectNodes(ctx, status.Nodes)
+	wg.Wait()
 
-	for _, nodeName := range strings.Split(output, "\n") {
-		nodeName = strings.TrimSpace(nodeName)
-		if nodeName == "" {
+	for i := range status.Nodes {
+		ns := &status.Nodes[i]
+		if ns.Role != RoleExternalLoadBalancer {
 			continue
 		}
+		ns.HostPort = hostPorts[i]
+		if ns.Status == "running" && ns.HostPort != "" {
+			ns.Health = "healthy"
+		} else {
+			ns.Health = "unhealthy"
+		}
+	}
 
-		ns := NodeStatus{Name: nodeName}
+	return status, nil
+}
 
-		if strings.Contains(nodeName, "control-plane") {
-			ns.Role = "control-plane"
-		} else {
-			ns.Role = "worker"
+// inspectNodes fills in the state of every node: concurrently through the container API
+// when it is available, otherwise with a single runtime inspect call for all of them.
+func (m *Manager) inspectNodes(ctx context.Context, nodes []NodeStatus) {
+	handled := make([]bool, len(nodes))
+	if m.api != nil {
+		var wg sync.WaitGroup
+		for i := range nodes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				handled[i] = m.inspectNodeAPI(ctx, &nodes[i])
+			}()
 		}
+		wg.Wait()
+	}
 
-		inspectOut, err := m.runner.Run(ctx, runtimeBin, "inspect",
-			"--format", "{{.State.Status}}", nodeName)
-		if err != nil {
-			ns.Status = "unknown"
+	var remaining []string
+	for i, ns := range nodes {
+		if !handled[i] {
+			remaining = append(remaining, ns.Name)
+		}
+	}
+	if len(remaining) == 0 {
+		return
+	}
+
+	states := m.inspectNodesCLI(ctx, remaining)
+	for i := range nodes {
+		if handled[i] {
+			continue
+		}
+		if state, ok := states[nodes[i].Name]; ok {
+			nodes[i].Status = state.status
+			nodes[i].Disconnected = state.networks != nil && !slices.Contains(state.networks, kindNetwork)
 		} else {
-			ns.Status = strings.TrimSpace(string(inspectOut))
+			nodes[i].Status = "unknown"
 		}
+	}
+}
 
-		status.Nodes = append(status.Nodes, ns)
+// cliNodeState is a node container's state as reported by the runtime CLI. networks is
+// nil when the runtime did not report them.
+type cliNodeState struct {
+	status   string
+	networks []string
+}
+
+// inspectNodesCLI returns the container state of each named node from one inspect call.
+// Nodes the runtime can't find are missing from the result; inspect still prints the
+// others when it exits non-zero, so its output is used either way.
+func (m *Manager) inspectNodesCLI(ctx context.Context, nodes []string) map[string]cliNodeState {
+	args := append([]string{"inspect", "--format",
+		"{{.Name}}|{{.State.Status}}|{{range $k, $v := .NetworkSettings.Networks}}{{$k}},{{end}}"}, nodes...)
+	out, stderr, err := m.runner.RunSeparate(ctx, m.runtimeBin(), args...)
+	if err != nil {
+		m.logger.Debug("inspecting nodes", "error", err, "stderr", strings.TrimSpace(string(stderr)))
 	}
 
-	return status, nil
+	states := make(map[string]cliNodeState, len(nodes))
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) < 2 {
+			continue
+		}
+		state := cliNodeState{status: fields[1]}
+		if len(fields) > 2 {
+			state.networks = []string{}
+			for _, n := range strings.Split(fields[2], ",") {
+				if n != "" {
+					state.networks = append(state.networks, n)
+				}
+			}
+		}
+		// Docker reports container names with a leading slash, Podman without.
+		states[strings.TrimPrefix(fields[0], "/")] = state
+	}
+	return states
+}
+
+// inspectNodeAPI fills in a node's state from the container API. It returns false when
+// the API is not configured or unreachable, so the caller should use the CLI instead.
+func (m *Manager) inspectNodeAPI(ctx context.Context, ns *NodeStatus) bool {
+	if m.api == nil {
+		return false
+	}
+	state, err := m.api.Inspect(ctx, ns.Name)
+	if errors.Is(err, containerapi.ErrUnavailable) {
+		m.logger.Debug("container API unavailable, using CLI", "error", err)
+		return false
+	}
+	if err != nil {
+		ns.Status = "unknown"
+		return true
+	}
+	ns.Status = state.Status
+	ns.Disconnected = !slices.Contains(state.Networks, kindNetwork)
+	ns.Restarts = state.RestartCount
+	if !state.StartedAt.IsZero() {
+		ns.StartedAt = &state.StartedAt
+	}
+	return true
+}
+
+// loadBalancerPort returns the host address the load balancer publishes the API server
+// port on, or "" if it isn't published.
+func (m *Manager) loadBalancerPort(ctx context.Context, node string) string {
+	return m.publishedPort(ctx, node, 6443)
+}
+
+// publishedPort returns the first host address node publishes a TCP port on.
+func (m *Manager) publishedPort(ctx context.Context, node string, port int) string {
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "port", node, fmt.Sprintf("%d/tcp", port))
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[0])
+}
+
+// PublishedPort returns the host address the first control-plane node of a cluster
+// publishes a TCP container port on, or "" if the cluster has no mapping for it.
+func (m *Manager) PublishedPort(ctx context.Context, clusterName string, containerPort int) string {
+	return m.publishedPort(ctx, clusterName+"-control-plane", containerPort)
+}
+
+// CopyFromNode copies a file or directory from a node container to the host.
+// As with "docker cp", a source ending in "/." copies the directory's contents.
+func (m *Manager) CopyFromNode(ctx context.Context, nodeName, src, dst string) error {
+	m.logger.Debug("copy from node", "node", nodeName, "src", src, "dst", dst)
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "cp", nodeName+":"+src, dst)
+	if err != nil {
+		return fmt.Errorf("copying %s from node %q failed: %w\nOutput: %s", src, nodeName, err, string(out))
+	}
+	return nil
+}
+
+// CopyToNode copies a file or directory from the host into a node container.
+func (m *Manager) CopyToNode(ctx context.Context, nodeName, src, dst string) error {
+	m.logger.Debug("copy to node", "node", nodeName, "src", src, "dst", dst)
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "cp", src, nodeName+":"+dst)
+	if err != nil {
+		return fmt.Errorf("copying %s to node %q failed: %w\nOutput: %s", src, nodeName, err, string(out))
+	}
+	return nil
 }
 
 // ExecOnNode runs a command on a Kind node container.
 func (m *Manager) ExecOnNode(ctx context.Context, nodeName string, cmd []string) (string, error) {
 	m.logger.Debug("exec on node", "node", nodeName, "cmd", cmd)
-	runtimeBin := "docker"
-	if m.runtime.Runtime == rtdetect.RuntimePodman {
-		runtimeBin = "podman"
+	if m.api != nil {
+		out, err := m.api.Exec(ctx, nodeName, cmd)
+		if !errors.Is(err, containerapi.ErrUnavailable) {
+			if err != nil {
+				return string(out), fmt.Errorf("exec on node %q failed: %w\nOutput: %s", nodeName, err, string(out))
+			}
+			return string(out), nil
+		}
+		m.logger.Debug("container API unavailable, using CLI", "error", err)
 	}
-
 	args := append([]string{"exec", nodeName}, cmd...)
-	out, err := m.runner.Run(ctx, runtimeBin, args...)
+	out, err := m.runner.Run(ctx, m.runtimeBin(), args...)
 	if err != nil {
 		return string(out), fmt.Errorf("exec on node %q failed: %w\nOutput: %s", nodeName, err, string(out))
 	}
@@ -221,11 +561,12 @@ func (m *Manager) ExecOnNode(ctx context.Context, nodeName string, cmd []string)
 
 // GetClusterNodes returns node names for a Kind cluster.
 func (m *Manager) GetClusterNodes(ctx context.Context, name string) ([]string, error) {
-	args := append(m.kindArgs(), "get", "nodes", "--name", name)
-
-	out, err := m.runner.Run(ctx, "kind", args...)
+	if m.lib != nil {
+		return m.libNodes(name)
+	}
+	out, err := m.runner.Run(m.kindContext(ctx), "kind", "get", "nodes", "--name", name)
 	if err != nil {
-		return nil, fmt.Errorf("kind get nodes failed: %w", err)
+		return nil, fmt.Errorf("kind get nodes failed: %w\nOutput: %s", err, string(out))
 	}
 
 	output := strings.TrimSpace(string(out))
@@ -2,10 +2,13 @@ package kind
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/containerapi"
 	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
 )
 
@@ -29,10 +32,19 @@ func (m *mockRunner) Run(_ context.Context, name string, args ...string) ([]byte
 	return nil, fmt.Errorf("no mock for %s %v", name, args)
 }
 
+func (m *mockRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	out, err := m.Run(ctx, name, args...)
+	if err != nil {
+		return nil, out, err
+	}
+	return out, nil, nil
+}
+
 func (m *mockRunner) LookPath(name string) (string, error) {
 	return "/usr/bin/" + name, nil
 }
 
+// matchArgs reports whether want is a prefix of got; "*" matches any single argument.
 func matchArgs(want, got []string) bool {
 	if len(want) == 0 {
 		return true
@@ -41,7 +53,7 @@ func matchArgs(want, got []string) bool {
 		return false
 	}
 	for i, w := range want {
-		if w != got[i] {
+		if w != "*" && w != got[i] {
 			return false
 		}
 	}
@@ -98,6 +110,38 @@ func TestCreateCluster_InvalidConfig(t *testing.T) {
 	}
 }
 
+// deleteCountingRunner counts kind delete cluster calls.
+type deleteCountingRunner struct {
+	mockRunner
+	deletes int
+}
+
+func (d *deleteCountingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if name == "kind" && matchArgs([]string{"delete", "cluster"}, args) {
+		d.deletes++
+	}
+	return d.mockRunner.Run(ctx, name, args...)
+}
+
+func TestCreateCluster_CancelledCleansUp(t *testing.T) {
+	runner := &deleteCountingRunner{mockRunner: mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"create", "cluster"}, out: []byte("Creating cluster\n"), err: context.Canceled},
+		{name: "kind", args: []string{"delete", "cluster"}, out: []byte("Deleting cluster\n")},
+	}}}
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "test", NumControlPlanes: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	_, err := mgr.CreateCluster(ctx, "test", cfg)
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "partial cluster was deleted") {
+		t.Errorf("err = %v", err)
+	}
+	if runner.deletes != 1 {
+		t.Errorf("kind delete cluster ran %d times, want 1", runner.deletes)
+	}
+}
+
 func TestDeleteCluster(t *testing.T) {
 	runner := &mockRunner{
 		runs: []runCall{
@@ -189,7 +233,8 @@ func TestGetClusterStatus(t *testing.T) {
 	runner := &mockRunner{
 		runs: []runCall{
 			{name: "kind", args: []string{"get", "nodes"}, out: []byte("test-control-plane\ntest-worker\n")},
-			{name: "docker", args: []string{"inspect"}, out: []byte("running\n")},
+			{name: "docker", args: []string{"inspect", "--format", "*", "test-control-plane", "test-worker"},
+				out: []byte("/test-control-plane|running\n/test-worker|exited\n")},
 		},
 	}
 
@@ -210,20 +255,72 @@ func TestGetClusterStatus(t *testing.T) {
 	if status.Nodes[1].Role != "worker" {
 		t.Errorf("second node role = %q, want worker", status.Nodes[1].Role)
 	}
+	if status.Nodes[0].Status != "running" || status.Nodes[1].Status != "exited" {
+		t.Errorf("statuses = %q, %q", status.Nodes[0].Status, status.Nodes[1].Status)
+	}
 }
 
-func TestPodmanManager_KindArgs(t *testing.T) {
+func TestGetClusterStatus_MissingNode(t *testing.T) {
+	// inspect exits non-zero when one container is gone but still reports the others
 	runner := &mockRunner{
 		runs: []runCall{
-			{name: "kind", args: []string{"--runtime", "podman", "get", "clusters"}, out: []byte("")},
+			{name: "kind", args: []string{"get", "nodes"}, out: []byte("test-control-plane\ntest-worker\n")},
+			{name: "docker", args: []string{"inspect"}, out: []byte("test-control-plane|running\n"),
+				err: fmt.Errorf("exit status 1")},
 		},
 	}
+	mgr := NewManager(&separateRunner{runner}, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
 
-	mgr := newPodmanManager(runner)
-	_, err := mgr.ListClusters(context.Background())
+	status, err := mgr.GetClusterStatus(context.Background(), "test")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if status.Nodes[0].Status != "running" || status.Nodes[1].Status != "unknown" {
+		t.Errorf("statuses = %q, %q", status.Nodes[0].Status, status.Nodes[1].Status)
+	}
+}
+
+// separateRunner returns stdout from RunSeparate even when the command fails.
+type separateRunner struct {
+	*mockRunner
+}
+
+func (s *separateRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	out, err := s.Run(ctx, name, args...)
+	return out, nil, err
+}
+
+// envRunner records the environment each command was given through its context.
+type envRunner struct {
+	*mockRunner
+	env map[string][]string // "name arg0" -> env
+}
+
+func (e *envRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	e.env[name+" "+args[0]] = rtdetect.Env(ctx)
+	return e.mockRunner.Run(ctx, name, args...)
+}
+
+func TestPodmanManager_KindProvider(t *testing.T) {
+	runner := &envRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "clusters"}, out: []byte("")},
+	}}, env: make(map[string][]string)}
+
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimePodman}, nil)
+	if _, err := mgr.ListClusters(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env := runner.env["kind get"]; len(env) != 1 || env[0] != "KIND_EXPERIMENTAL_PROVIDER=podman" {
+		t.Errorf("kind env = %v", env)
+	}
+
+	plan, err := mgr.PlanCreateCluster(context.Background(), "dev", "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(plan.Commands[0], "KIND_EXPERIMENTAL_PROVIDER=podman kind create cluster --name dev") {
+		t.Errorf("planned command = %q", plan.Commands[0])
+	}
 }
 
 func TestExecOnNode(t *testing.T) {
@@ -259,3 +356,124 @@ func TestGetClusterNodes(t *testing.T) {
 		t.Errorf("expected 3 nodes, got %d", len(nodes))
 	}
 }
+
+func TestGetClusterStatus_HALoadBalancer(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "nodes"}, out: []byte(
+				"ha-external-load-balancer\nha-control-plane\nha-control-plane2\nha-worker\n")},
+			{name: "docker", args: []string{"inspect"}, out: []byte(
+				"/ha-external-load-balancer|running\n/ha-control-plane|running\n/ha-control-plane2|running\n/ha-worker|running\n")},
+			{name: "docker", args: []string{"port", "ha-external-load-balancer", "6443/tcp"}, out: []byte("127.0.0.1:41234\n")},
+		},
+	}
+
+	status, err := newDockerManager(runner).GetClusterStatus(context.Background(), "ha")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status.Nodes) != 4 {
+		t.Fatalf("expected 4 nodes, got %d", len(status.Nodes))
+	}
+	lb := status.Nodes[0]
+	if lb.Role != RoleExternalLoadBalancer {
+		t.Errorf("lb role = %q, want %q", lb.Role, RoleExternalLoadBalancer)
+	}
+	if lb.HostPort != "127.0.0.1:41234" {
+		t.Errorf("lb host port = %q", lb.HostPort)
+	}
+	if lb.Health != "healthy" {
+		t.Errorf("lb health = %q, want healthy", lb.Health)
+	}
+	if status.Nodes[2].Role != RoleControlPlane {
+		t.Errorf("second control plane role = %q", status.Nodes[2].Role)
+	}
+}
+
+func TestNodeRole(t *testing.T) {
+	tests := map[string]string{
+		"dev-control-plane":          RoleControlPlane,
+		"dev-control-plane3":         RoleControlPlane,
+		"dev-worker2":                RoleWorker,
+		"dev-external-load-balancer": RoleExternalLoadBalancer,
+	}
+	for name, want := range tests {
+		if got := NodeRole(name); got != want {
+			t.Errorf("NodeRole(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+type fakeNodeAPI struct {
+	states map[string]*containerapi.ContainerState
+	err    error
+	execs  [][]string
+}
+
+func (f *fakeNodeAPI) Inspect(_ context.Context, container string) (*containerapi.ContainerState, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.states[container], nil
+}
+
+func (f *fakeNodeAPI) Exec(_ context.Context, _ string, cmd []string) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.execs = append(f.execs, cmd)
+	return []byte("api\n"), nil
+}
+
+func TestGetClusterStatus_ContainerAPI(t *testing.T) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\n")},
+	}}
+	mgr := newDockerManager(runner)
+	mgr.api = &fakeNodeAPI{states: map[string]*containerapi.ContainerState{
+		"dev-control-plane": {Status: "running", Running: true, RestartCount: 1, StartedAt: started, Networks: []string{"kind"}},
+	}}
+
+	status, err := mgr.GetClusterStatus(context.Background(), "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ns := status.Nodes[0]
+	if ns.Status != "running" || ns.Disconnected || ns.Restarts != 1 || ns.StartedAt == nil || !ns.StartedAt.Equal(started) {
+		t.Errorf("node status = %+v", ns)
+	}
+}
+
+func TestContainerAPI_FallsBackToCLI(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte("/dev-control-plane|exited\n")},
+		{name: "docker", args: []string{"exec"}, out: []byte("cli\n")},
+	}}
+	mgr := newDockerManager(runner)
+	mgr.api = &fakeNodeAPI{err: fmt.Errorf("%w: dial failed", containerapi.ErrUnavailable)}
+
+	status, err := mgr.GetClusterStatus(context.Background(), "dev")
+	if err != nil || status.Nodes[0].Status != "exited" {
+		t.Errorf("status = %+v, err = %v", status, err)
+	}
+	out, err := mgr.ExecOnNode(context.Background(), "dev-control-plane", []string{"true"})
+	if err != nil || out != "cli\n" {
+		t.Errorf("exec out = %q, err = %v", out, err)
+	}
+}
+
+func TestExecOnNode_ContainerAPI(t *testing.T) {
+	mgr := newDockerManager(&mockRunner{})
+	api := &fakeNodeAPI{}
+	mgr.api = api
+
+	out, err := mgr.ExecOnNode(context.Background(), "dev-control-plane", []string{"echo", "hi"})
+	if err != nil || out != "api\n" {
+		t.Errorf("out = %q, err = %v", out, err)
+	}
+	if len(api.execs) != 1 {
+		t.Errorf("expected exec through the API")
+	}
+}
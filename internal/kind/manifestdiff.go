@@ -0,0 +1,269 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Actions reported by DiffManifest.
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// Operations of a FieldChange.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// FieldChange is one field an apply would change, at a path such as
+// "spec.template.spec.containers[0].image".
+type FieldChange struct {
+	Path   string `json:"path"`
+	Op     string `json:"op"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// ResourceDiff is what an apply would do to one object.
+type ResourceDiff struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Action    string        `json:"action"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+// ManifestDiff is the result of DiffManifest.
+type ManifestDiff struct {
+	Cluster   string         `json:"cluster"`
+	Changed   bool           `json:"changed"`
+	Creates   int            `json:"creates"`
+	Updates   int            `json:"updates"`
+	Unchanged int            `json:"unchanged"`
+	Resources []ResourceDiff `json:"resources"`
+}
+
+// ignoredMetadata are metadata fields the server sets on every write; they would
+// make every object look changed.
+var ignoredMetadata = []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"}
+
+// lastAppliedAnnotation is rewritten by every client-side apply.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// DiffManifest reports what applying a YAML manifest to a cluster would change,
+// without changing it: the manifest is applied with a server-side dry run, so
+// defaulting, mutating admission and validation all take part, and each resulting
+// object is compared with the live one. status and server-maintained metadata are
+// ignored. namespace, if set, is used for objects that name none.
+func (m *Manager) DiffManifest(ctx context.Context, clusterName, manifest, namespace string) (*ManifestDiff, error) {
+	path, cleanup, err := tempManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	desired, err := m.kubectlObjects(ctx, clusterName, namespace, "apply", "--dry-run=server", "-f", path, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	live, err := m.kubectlObjects(ctx, clusterName, namespace, "get", "-f", path, "--ignore-not-found", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	liveByKey := make(map[string]map[string]any, len(live))
+	for _, obj := range live {
+		liveByKey[objectKey(obj)] = obj
+	}
+
+	diff := &ManifestDiff{Cluster: clusterName, Resources: []ResourceDiff{}}
+	for _, obj := range desired {
+		meta, _ := obj["metadata"].(map[string]any)
+		res := ResourceDiff{Action: ActionCreate}
+		res.Kind, _ = obj["kind"].(string)
+		res.Namespace, _ = meta["namespace"].(string)
+		res.Name, _ = meta["name"].(string)
+		if current, ok := liveByKey[objectKey(obj)]; ok {
+			res.Changes = DiffObjects(current, obj)
+			res.Action = ActionUpdate
+			if len(res.Changes) == 0 {
+				res.Action = ActionUnchanged
+			}
+		}
+		switch res.Action {
+		case ActionCreate:
+			diff.Creates++
+		case ActionUpdate:
+			diff.Updates++
+		default:
+			diff.Unchanged++
+		}
+		diff.Resources = append(diff.Resources, res)
+	}
+	diff.Changed = diff.Creates+diff.Updates > 0
+	return diff, nil
+}
+
+// kubectlObjects runs a kubectl command printing objects as JSON and returns them; a
+// List is returned as its items.
+func (m *Manager) kubectlObjects(ctx context.Context, clusterName, namespace string, args ...string) ([]map[string]any, error) {
+	res, err := m.RunKubectl(ctx, clusterName, namespace, args)
+	if err != nil {
+		return nil, err
+	}
+	if res.ExitCode != 0 {
+		return nil, fmt.Errorf("kubectl %s failed: %s", args[0], strings.TrimSpace(res.Stderr))
+	}
+	if strings.TrimSpace(res.Stdout) == "" {
+		return nil, nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(res.Stdout), &obj); err != nil {
+		return nil, fmt.Errorf("parsing kubectl %s output: %w", args[0], err)
+	}
+	if obj["kind"] != "List" {
+		return []map[string]any{obj}, nil
+	}
+	items, _ := obj["items"].([]any)
+	objects := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if o, ok := item.(map[string]any); ok {
+			objects = append(objects, o)
+		}
+	}
+	return objects, nil
+}
+
+// objectKey identifies an object by API group, kind, namespace and name.
+func objectKey(obj map[string]any) string {
+	apiVersion, _ := obj["apiVersion"].(string)
+	var group string
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		group = apiVersion[:i]
+	}
+	meta, _ := obj["metadata"].(map[string]any)
+	return fmt.Sprintf("%s/%v/%v/%v", group, obj["kind"], meta["namespace"], meta["name"])
+}
+
+// DiffObjects returns the changes from the live to the desired version of an object,
+// sorted by path. status and server-maintained metadata are ignored.
+func DiffObjects(live, desired map[string]any) []FieldChange {
+	var changes []FieldChange
+	diffValues("", diffable(live), diffable(desired), &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// diffable returns a copy of obj without the fields DiffObjects ignores.
+func diffable(obj map[string]any) map[string]any {
+	out := make(map[string]any, len(obj))
+	for k, v := range obj {
+		if k != "status" {
+			out[k] = v
+		}
+	}
+	meta, ok := obj["metadata"].(map[string]any)
+	if !ok {
+		return out
+	}
+	m := make(map[string]any, len(meta))
+	for k, v := range meta {
+		m[k] = v
+	}
+	for _, k := range ignoredMetadata {
+		delete(m, k)
+	}
+	if annotations, ok := m["annotations"].(map[string]any); ok {
+		a := make(map[string]any, len(annotations))
+		for k, v := range annotations {
+			if k != lastAppliedAnnotation {
+				a[k] = v
+			}
+		}
+		if len(a) == 0 {
+			delete(m, "annotations")
+		} else {
+			m["annotations"] = a
+		}
+	}
+	out["metadata"] = m
+	return out
+}
+
+func diffValues(path string, before, after any, changes *[]FieldChange) {
+	switch b := before.(type) {
+	case map[string]any:
+		if a, ok := after.(map[string]any); ok {
+			for k, bv := range b {
+				av, found := a[k]
+				if !found {
+					*changes = append(*changes, FieldChange{Path: joinPath(path, k), Op: OpRemove, Before: bv})
+					continue
+				}
+				diffValues(joinPath(path, k), bv, av, changes)
+			}
+			for k, av := range a {
+				if _, found := b[k]; !found {
+					*changes = append(*changes, FieldChange{Path: joinPath(path, k), Op: OpAdd, After: av})
+				}
+			}
+			return
+		}
+	case []any:
+		if a, ok := after.([]any); ok {
+			for i := 0; i < max(len(a), len(b)); i++ {
+				p := path + "[" + strconv.Itoa(i) + "]"
+				switch {
+				case i >= len(a):
+					*changes = append(*changes, FieldChange{Path: p, Op: OpRemove, Before: b[i]})
+				case i >= len(b):
+					*changes = append(*changes, FieldChange{Path: p, Op: OpAdd, After: a[i]})
+				default:
+					diffValues(p, b[i], a[i], changes)
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, FieldChange{Path: path, Op: OpReplace, Before: before, After: after})
+	}
+}
+
+// joinPath appends a map key to a path, quoting keys that contain dots such as
+// annotation names.
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// tempManifest writes a manifest to a temp file and returns its path along with a
+// cleanup function.
+func tempManifest(manifest string) (string, func(), error) {
+	f, err := os.CreateTemp("", "kind-manifest-*.yaml")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating temp manifest file: %w", err)
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	if _, err := f.WriteString(manifest); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("writing manifest to temp file: %w", err)
+	}
+	f.Close()
+	return f.Name(), cleanup, nil
+}
@@ -0,0 +1,111 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const dryRunList = `{"kind": "List", "items": [
+  {"apiVersion": "apps/v1", "kind": "Deployment",
+   "metadata": {"name": "web", "namespace": "shop", "resourceVersion": "12", "generation": 3,
+                "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"}},
+   "spec": {"replicas": 3, "template": {"spec": {"containers": [{"name": "web", "image": "web:v2"}]}}},
+   "status": {"readyReplicas": 2}},
+  {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "settings", "namespace": "shop"},
+   "data": {"mode": "dev"}},
+  {"apiVersion": "v1", "kind": "Service", "metadata": {"name": "web", "namespace": "shop"},
+   "spec": {"ports": [{"port": 80}]}}
+]}`
+
+const liveList = `{"kind": "List", "items": [
+  {"apiVersion": "apps/v1", "kind": "Deployment",
+   "metadata": {"name": "web", "namespace": "shop", "resourceVersion": "11", "generation": 2,
+                "labels": {"tier": "front"}},
+   "spec": {"replicas": 2, "template": {"spec": {"containers": [{"name": "web", "image": "web:v1"}]}}},
+   "status": {"readyReplicas": 2}},
+  {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "settings", "namespace": "shop", "uid": "x"},
+   "data": {"mode": "dev"}}
+]}`
+
+func TestDiffManifest(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "--namespace", "shop", "apply", "--dry-run=server"},
+				out: []byte(dryRunList)},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "--namespace", "shop", "get", "-f"},
+				out: []byte(liveList)},
+		},
+	}
+
+	diff, err := newDockerManager(runner).DiffManifest(context.Background(), "test", "kind: List\n", "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Changed || diff.Creates != 1 || diff.Updates != 1 || diff.Unchanged != 1 || len(diff.Resources) != 3 {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+	deploy := diff.Resources[0]
+	if deploy.Kind != "Deployment" || deploy.Action != ActionUpdate {
+		t.Fatalf("unexpected deployment diff: %+v", deploy)
+	}
+	want := []FieldChange{
+		{Path: "metadata.labels", Op: OpRemove, Before: map[string]any{"tier": "front"}},
+		{Path: "spec.replicas", Op: OpReplace, Before: float64(2), After: float64(3)},
+		{Path: "spec.template.spec.containers[0].image", Op: OpReplace, Before: "web:v1", After: "web:v2"},
+	}
+	if len(deploy.Changes) != len(want) {
+		t.Fatalf("changes = %+v", deploy.Changes)
+	}
+	for i, c := range deploy.Changes {
+		if c.Path != want[i].Path || c.Op != want[i].Op || c.After != want[i].After {
+			t.Errorf("change %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+	if diff.Resources[1].Action != ActionUnchanged || diff.Resources[2].Action != ActionCreate {
+		t.Errorf("unexpected actions: %+v", diff.Resources[1:])
+	}
+}
+
+func TestDiffManifest_DryRunRejected(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "apply", "--dry-run=server"},
+				out: []byte(`The Deployment "web" is invalid: spec.replicas: Invalid value: -1`), err: exitError(t, 1)},
+		},
+	}
+	_, err := newDockerManager(runner).DiffManifest(context.Background(), "test", "kind: Deployment\n", "")
+	if err == nil || !strings.Contains(err.Error(), "Invalid value") {
+		t.Errorf("expected the dry run rejection, got %v", err)
+	}
+}
+
+func TestDiffObjects(t *testing.T) {
+	live := map[string]any{
+		"metadata": map[string]any{"name": "a", "annotations": map[string]any{"team.example.com/owner": "x"}},
+		"spec":     map[string]any{"ports": []any{map[string]any{"port": 80.0}, map[string]any{"port": 443.0}}},
+	}
+	desired := map[string]any{
+		"metadata": map[string]any{"name": "a", "annotations": map[string]any{"team.example.com/owner": "y"}},
+		"spec":     map[string]any{"ports": []any{map[string]any{"port": 80.0}}, "type": "NodePort"},
+	}
+	changes := DiffObjects(live, desired)
+	want := []string{
+		`metadata.annotations["team.example.com/owner"] replace`,
+		"spec.ports[1] remove",
+		"spec.type add",
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v", changes)
+	}
+	for i, c := range changes {
+		if got := c.Path + " " + c.Op; got != want[i] {
+			t.Errorf("change %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if len(DiffObjects(live, live)) != 0 {
+		t.Error("an object should not differ from itself")
+	}
+}
@@ -21,6 +21,15 @@ type NetworkAdvice struct {
 	RequiresExtraConfig  bool   `json:"requires_extra_config"`
 	Notes                string `json:"notes"`
 	RecommendedPortRange string `json:"recommended_port_range"`
+	// ReachableAddress is an address other than localhost where mapped ports and,
+	// with a route, node container IPs can be reached (e.g. the Colima VM address).
+	ReachableAddress string `json:"reachable_address,omitempty"`
+	// WindowsFirewallRequired flags that exposing ports to the LAN needs Windows-side
+	// firewall (and, in WSL NAT mode, port proxy) rules.
+	WindowsFirewallRequired bool `json:"windows_firewall_required,omitempty"`
+	// PortForwarder is what forwards extraPortMappings with Podman: netavark or cni
+	// firewall rules when rootful, pasta or slirp4netns when rootless.
+	PortForwarder string `json:"port_forwarder,omitempty"`
 }
 
 // DetectNetworkConfig returns network exposure advice based on the runtime info.
@@ -42,11 +51,48 @@ func DetectNetworkConfig(ri rtdetect.RuntimeInfo) NetworkAdvice {
 		advice.Notes = "Colima forwards ports from the VM to the macOS host. " +
 			"extraPortMappings work seamlessly. Bind to 127.0.0.1 for local access. " +
 			"For LAN access, use 0.0.0.0 and ensure Colima network settings allow it."
+		if c := ri.Colima; c != nil {
+			if c.NetworkAddress() {
+				advice.ReachableAddress = c.Address
+				advice.Notes += fmt.Sprintf(" The Colima VM has a network address (%s): ports bound to 0.0.0.0 "+
+					"are reachable there, and node container IPs become reachable from the host after "+
+					"'sudo route add -net <kind subnet> %s'.", c.Address, c.Address)
+			} else {
+				advice.Notes += " Node container IPs are not reachable from the host; start Colima with " +
+					"--network-address to give the VM a routable IP."
+			}
+			if c.Runtime != "" && c.Runtime != "docker" {
+				advice.RequiresExtraConfig = true
+				advice.Notes += fmt.Sprintf(" Colima is running the %s runtime; Kind needs the docker runtime "+
+					"('colima start --runtime docker').", c.Runtime)
+			}
+		}
 
 	case rtdetect.BackendWSL:
 		advice.Notes = "WSL2 automatically forwards localhost ports from the Linux VM to Windows. " +
 			"extraPortMappings on 127.0.0.1 are reachable from Windows host. " +
 			"For LAN access, Windows firewall rules may need adjustment."
+		if w := ri.WSL; w != nil {
+			advice.WindowsFirewallRequired = true
+			switch {
+			case w.Mirrored():
+				advice.Notes = "WSL2 mirrored networking shares the Windows network interfaces: " +
+					"extraPortMappings on 127.0.0.1 are reachable from Windows, and mappings on 0.0.0.0 use the " +
+					"Windows LAN address. For LAN access, allow the ports through the Hyper-V firewall " +
+					"(New-NetFirewallHyperVRule) and Windows Defender Firewall."
+			case !w.LocalhostForwarding:
+				advice.RequiresExtraConfig = true
+				advice.Notes = "WSL2 NAT networking with localhostForwarding=false: ports bound in WSL are not " +
+					"reachable on Windows localhost. Set localhostForwarding=true in .wslconfig (then 'wsl --shutdown') " +
+					"or connect to the WSL VM address from 'hostname -I'. For LAN access, add a " +
+					"'netsh interface portproxy' rule to the WSL address and a Windows Firewall inbound rule."
+			default:
+				advice.Notes = "WSL2 NAT networking forwards localhost ports from the Linux VM to Windows. " +
+					"extraPortMappings on 127.0.0.1 are reachable from the Windows host. For LAN access, add a " +
+					"'netsh interface portproxy' rule to the WSL address and a Windows Firewall inbound rule, " +
+					"or switch to networkingMode=mirrored in .wslconfig."
+			}
+		}
 
 	case rtdetect.BackendPodmanMachine:
 		advice.Notes = "Podman Machine forwards ports from the VM to the host. " +
@@ -60,8 +106,10 @@ func DetectNetworkConfig(ri rtdetect.RuntimeInfo) NetworkAdvice {
 		if ri.OS.OS == "linux" {
 			advice.ListenAddress = "0.0.0.0"
 			advice.Notes = "Native Linux: containers are directly accessible. " +
-				"extraPortMappings bind to the host network interface. " +
-				"Container IPs are also directly reachable from the host."
+				"extraPortMappings bind to the host network interface."
+			if !rootlessPodman(ri) {
+				advice.Notes += " Container IPs are also directly reachable from the host."
+			}
 		}
 
 	case rtdetect.BackendRancherDesktop:
@@ -76,9 +124,130 @@ func DetectNetworkConfig(ri rtdetect.RuntimeInfo) NetworkAdvice {
 		advice.Notes = "Unknown backend. extraPortMappings with 127.0.0.1 is a safe default."
 	}
 
+	if ri.Runtime == rtdetect.RuntimePodman && ri.Podman != nil {
+		addPodmanAdvice(&advice, ri.Podman)
+	}
 	return advice
 }
 
+// rootlessPodman reports whether the runtime is Podman running rootless; node
+// container IPs then live in the rootless network namespace, not on the host.
+func rootlessPodman(ri rtdetect.RuntimeInfo) bool {
+	return ri.Runtime == rtdetect.RuntimePodman && ri.Podman != nil && ri.Podman.Rootless
+}
+
+// addPodmanAdvice extends advice with how Podman's network backend and, when rootless,
+// its port forwarder handle the kind network and extraPortMappings.
+func addPodmanAdvice(advice *NetworkAdvice, p *rtdetect.PodmanInfo) {
+	add := func(note string) {
+		advice.Notes += " " + note
+	}
+	switch p.NetworkBackend {
+	case rtdetect.PodmanNetworkNetavark:
+		if p.DNS == "" {
+			add("Podman uses netavark without aardvark-dns, so containers on the kind network cannot resolve " +
+				"each other by name and a mirror such as http://kind-registry:5000 fails; install aardvark-dns.")
+		} else {
+			add("Podman uses netavark with aardvark-dns, which resolves container names on the kind network " +
+				"(e.g. kind-registry, <cluster>-control-plane).")
+		}
+	case rtdetect.PodmanNetworkCNI:
+		add("Podman uses the CNI network backend, which Podman 5 removed; containers on the kind network " +
+			"resolve each other only with the dnsname plugin. Switch to netavark (network_backend in " +
+			"containers.conf, then 'podman system reset').")
+	}
+
+	if !p.Rootless {
+		advice.PortForwarder = p.NetworkBackend
+		add("Rootful Podman forwards extraPortMappings with " + p.NetworkBackend + " firewall rules, like Docker: " +
+			"listenAddress 0.0.0.0 exposes a port to the LAN and pods see the client's address.")
+		return
+	}
+	advice.PortForwarder = p.RootlessNetwork
+	switch p.RootlessNetwork {
+	case rtdetect.PodmanRootlessPasta:
+		add("Rootless Podman forwards extraPortMappings with pasta, which keeps the client's source address.")
+	case rtdetect.PodmanRootlessSlirp4netns:
+		add("Rootless Podman forwards extraPortMappings with slirp4netns and its rootlessport proxy: pods see " +
+			"the proxy's address instead of the client's and throughput is lower; Podman 5 switched to pasta " +
+			"(default_rootless_network_cmd in containers.conf).")
+	}
+	add("Rootless host ports below 1024 need 'sudo sysctl net.ipv4.ip_unprivileged_port_start=80' (or map " +
+		"8080/8443), and node container IPs are inside the rootless network namespace, not reachable from the " +
+		"host: use extraPortMappings, or 'podman unshare --rootless-netns' to reach them.")
+}
+
+// Intended uses accepted by AdviseNetworkUse.
+const (
+	NetworkUseIngress      = "ingress"
+	NetworkUseAPIServerLAN = "api-server-lan"
+	NetworkUseNodePort     = "nodeport"
+)
+
+// UseAdvice holds recommendations for one intended way of exposing a Kind cluster.
+type UseAdvice struct {
+	Use             string        `json:"use"`
+	Recommendations []string      `json:"recommendations"`
+	PortMappings    []PortMapping `json:"port_mappings,omitempty"`
+}
+
+// AdviseNetworkUse returns targeted recommendations for exposing a cluster for the
+// given use: NetworkUseIngress, NetworkUseAPIServerLAN, or NetworkUseNodePort.
+func AdviseNetworkUse(ri rtdetect.RuntimeInfo, use string) (*UseAdvice, error) {
+	base := DetectNetworkConfig(ri)
+	vm := ri.Backend != rtdetect.BackendNative
+	advice := &UseAdvice{Use: use}
+	add := func(format string, args ...any) {
+		advice.Recommendations = append(advice.Recommendations, fmt.Sprintf(format, args...))
+	}
+
+	switch use {
+	case NetworkUseIngress:
+		advice.PortMappings = DefaultPortMappings(base.ListenAddress)
+		add("Map host ports 80 and 443 to the ingress node and label it ingress-ready=true " +
+			"(generate_cluster_config enable_ingress_ports=true does both on the first control plane); " +
+			"install the ingress controller with a nodeSelector on that label.")
+		if ri.Backend == rtdetect.BackendDockerDesktop && ri.OS.OS == "darwin" {
+			add("Docker Desktop on macOS needs its privileged port helper (vmnetd) to bind 80/443; " +
+				"if creation fails, use host ports 8080/8443 instead.")
+		}
+		if ri.Runtime == rtdetect.RuntimePodman && (ri.Podman == nil || ri.Podman.Rootless) {
+			add("Rootless Podman cannot bind ports below 1024 unless net.ipv4.ip_unprivileged_port_start " +
+				"is lowered; otherwise use host ports 8080/8443.")
+		}
+
+	case NetworkUseAPIServerLAN:
+		add("Set networking.apiServerAddress to 0.0.0.0 and pin networking.apiServerPort (e.g. 6443) " +
+			"so the address in shared kubeconfigs stays stable.")
+		add("Replace the server address in the kubeconfig you hand out with this host's LAN IP.")
+		add("This exposes cluster-admin access to the network; restrict it with a host firewall.")
+		if vm {
+			add("On %s the API server is forwarded from the VM; confirm the backend forwards to LAN "+
+				"interfaces and not only to localhost.", ri.Backend)
+		}
+		if base.RequiresExtraConfig {
+			add("This backend needs extra port-forwarding configuration: %s", base.Notes)
+		}
+
+	case NetworkUseNodePort:
+		advice.PortMappings = []PortMapping{{
+			HostPort: 30080, ContainerPort: 30080, ListenAddress: base.ListenAddress, Protocol: "TCP",
+		}}
+		add("Map each NodePort (range %s) you need to a host port with extraPortMappings; "+
+			"ports cannot be added to a running cluster.", base.RecommendedPortRange)
+		add("Pin the Service's nodePort to the mapped containerPort so the mapping stays valid.")
+		if !vm && !rootlessPodman(ri) {
+			add("On native Linux the node containers' IPs are routable from the host, so " +
+				"<node-ip>:<nodePort> works without any mapping.")
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown use %q; must be %q, %q, or %q",
+			use, NetworkUseIngress, NetworkUseAPIServerLAN, NetworkUseNodePort)
+	}
+	return advice, nil
+}
+
 // DefaultPortMappings returns commonly useful port mappings for Kind clusters.
 func DefaultPortMappings(listenAddr string) []PortMapping {
 	if listenAddr == "" {
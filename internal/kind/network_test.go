@@ -1,6 +1,7 @@
 package kind
 
 import (
+	"strings"
 	"testing"
 
 	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
@@ -66,6 +67,123 @@ func TestDetectNetworkConfig_WSL(t *testing.T) {
 	}
 }
 
+func TestDetectNetworkConfig_PodmanRootless(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimePodman,
+		Backend: rtdetect.BackendNative,
+		OS:      rtdetect.OSInfo{OS: "linux"},
+		Podman: &rtdetect.PodmanInfo{NetworkBackend: rtdetect.PodmanNetworkNetavark,
+			Rootless: true, RootlessNetwork: rtdetect.PodmanRootlessSlirp4netns},
+	}
+	advice := DetectNetworkConfig(ri)
+
+	if advice.PortForwarder != rtdetect.PodmanRootlessSlirp4netns {
+		t.Errorf("PortForwarder = %q", advice.PortForwarder)
+	}
+	for _, want := range []string{"install aardvark-dns", "rootlessport", "ip_unprivileged_port_start", "rootless network namespace"} {
+		if !strings.Contains(advice.Notes, want) {
+			t.Errorf("notes lack %q: %s", want, advice.Notes)
+		}
+	}
+	if strings.Contains(advice.Notes, "directly reachable") {
+		t.Errorf("rootless node IPs are not reachable from the host: %s", advice.Notes)
+	}
+
+	use, err := AdviseNetworkUse(ri, NetworkUseNodePort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range use.Recommendations {
+		if strings.Contains(r, "routable from the host") {
+			t.Errorf("unexpected recommendation for rootless Podman: %s", r)
+		}
+	}
+}
+
+func TestDetectNetworkConfig_PodmanRootful(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimePodman,
+		Backend: rtdetect.BackendNative,
+		OS:      rtdetect.OSInfo{OS: "linux"},
+		Podman:  &rtdetect.PodmanInfo{NetworkBackend: rtdetect.PodmanNetworkNetavark, DNS: "aardvark-dns"},
+	}
+	advice := DetectNetworkConfig(ri)
+
+	if advice.PortForwarder != rtdetect.PodmanNetworkNetavark || !strings.Contains(advice.Notes, "directly reachable") ||
+		!strings.Contains(advice.Notes, "resolves container names") {
+		t.Errorf("advice = %+v", advice)
+	}
+	use, _ := AdviseNetworkUse(ri, NetworkUseIngress)
+	for _, r := range use.Recommendations {
+		if strings.Contains(r, "Rootless Podman") {
+			t.Errorf("unexpected rootless hint for rootful Podman: %s", r)
+		}
+	}
+}
+
+func TestDetectNetworkConfig_ColimaNetworkAddress(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker,
+		Backend: rtdetect.BackendColima,
+		Colima:  &rtdetect.ColimaInfo{Runtime: "docker", Address: "192.168.106.2"},
+	}
+	advice := DetectNetworkConfig(ri)
+
+	if advice.ReachableAddress != "192.168.106.2" {
+		t.Errorf("ReachableAddress = %q", advice.ReachableAddress)
+	}
+	if !strings.Contains(advice.Notes, "route add") {
+		t.Errorf("expected routing hint in notes: %s", advice.Notes)
+	}
+}
+
+func TestDetectNetworkConfig_ColimaContainerd(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Backend: rtdetect.BackendColima,
+		Colima:  &rtdetect.ColimaInfo{Runtime: "containerd"},
+	}
+	advice := DetectNetworkConfig(ri)
+
+	if !advice.RequiresExtraConfig || !strings.Contains(advice.Notes, "--runtime docker") {
+		t.Errorf("advice = %+v", advice)
+	}
+	if advice.ReachableAddress != "" {
+		t.Errorf("ReachableAddress = %q, want empty", advice.ReachableAddress)
+	}
+}
+
+func TestDetectNetworkConfig_WSLNoLocalhostForwarding(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Backend: rtdetect.BackendWSL,
+		OS:      rtdetect.OSInfo{OS: "linux"},
+		WSL:     &rtdetect.WSLInfo{NetworkingMode: rtdetect.WSLNetworkingNAT},
+	}
+	advice := DetectNetworkConfig(ri)
+
+	if !advice.RequiresExtraConfig || !advice.WindowsFirewallRequired {
+		t.Errorf("advice = %+v", advice)
+	}
+	if !strings.Contains(advice.Notes, "localhostForwarding") {
+		t.Errorf("expected localhostForwarding hint: %s", advice.Notes)
+	}
+}
+
+func TestDetectNetworkConfig_WSLMirrored(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Backend: rtdetect.BackendWSL,
+		OS:      rtdetect.OSInfo{OS: "linux"},
+		WSL:     &rtdetect.WSLInfo{NetworkingMode: rtdetect.WSLNetworkingMirrored, LocalhostForwarding: true},
+	}
+	advice := DetectNetworkConfig(ri)
+
+	if advice.RequiresExtraConfig {
+		t.Error("mirrored networking should not require extra config")
+	}
+	if !strings.Contains(advice.Notes, "Hyper-V firewall") {
+		t.Errorf("expected Hyper-V firewall hint: %s", advice.Notes)
+	}
+}
+
 func TestDefaultPortMappings(t *testing.T) {
 	mappings := DefaultPortMappings("")
 	if len(mappings) != 2 {
@@ -103,3 +221,41 @@ func TestFormatNetworkAdvice(t *testing.T) {
 		t.Error("expected non-empty output")
 	}
 }
+
+func TestAdviseNetworkUse_Ingress(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{
+		Runtime: rtdetect.RuntimeDocker,
+		Backend: rtdetect.BackendDockerDesktop,
+		OS:      rtdetect.OSInfo{OS: "darwin"},
+	}
+	advice, err := AdviseNetworkUse(ri, NetworkUseIngress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(advice.PortMappings) != 2 || advice.PortMappings[0].HostPort != 80 {
+		t.Errorf("port mappings = %+v", advice.PortMappings)
+	}
+	if !strings.Contains(strings.Join(advice.Recommendations, " "), "vmnetd") {
+		t.Errorf("expected Docker Desktop privileged port note: %v", advice.Recommendations)
+	}
+}
+
+func TestAdviseNetworkUse_NodePortNative(t *testing.T) {
+	ri := rtdetect.RuntimeInfo{Backend: rtdetect.BackendNative, OS: rtdetect.OSInfo{OS: "linux"}}
+	advice, err := AdviseNetworkUse(ri, NetworkUseNodePort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if advice.PortMappings[0].ListenAddress != "0.0.0.0" {
+		t.Errorf("listen address = %q", advice.PortMappings[0].ListenAddress)
+	}
+	if !strings.Contains(strings.Join(advice.Recommendations, " "), "routable") {
+		t.Errorf("expected native routing note: %v", advice.Recommendations)
+	}
+}
+
+func TestAdviseNetworkUse_Unknown(t *testing.T) {
+	if _, err := AdviseNetworkUse(rtdetect.RuntimeInfo{}, "bogus"); err == nil {
+		t.Error("expected error for unknown use")
+	}
+}
@@ -0,0 +1,268 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NodeImage is an image cached in the containerd store of one or more cluster nodes.
+type NodeImage struct {
+	ID      string   `json:"id"`
+	Tags    []string `json:"tags,omitempty"`
+	Digests []string `json:"digests,omitempty"`
+	Size    int64    `json:"size_bytes"`
+	// Pinned images, such as the sandbox (pause) image, are never pruned.
+	Pinned bool     `json:"pinned,omitempty"`
+	Nodes  []string `json:"nodes"`
+	// Unused is set when no container on any node uses the image.
+	Unused bool `json:"unused"`
+}
+
+// NodeImageReport lists the images cached on the nodes of a cluster.
+type NodeImageReport struct {
+	Images []NodeImage `json:"images"`
+	// DiskBytes counts every node's copy of every image.
+	DiskBytes int64 `json:"disk_bytes"`
+	// UnusedBytes is the part of DiskBytes prune_node_images would free.
+	UnusedBytes int64    `json:"unused_bytes"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// PruneNodeImagesOptions selects what PruneNodeImages removes.
+type PruneNodeImagesOptions struct {
+	// Images are references or IDs to remove. When empty, every image no container
+	// uses is removed.
+	Images []string
+	DryRun bool
+}
+
+// NodeImagePruneResult is the outcome of PruneNodeImages.
+type NodeImagePruneResult struct {
+	DryRun     bool        `json:"dry_run"`
+	Removed    []NodeImage `json:"removed"`
+	FreedBytes int64       `json:"freed_bytes"`
+	Errors     []string    `json:"errors,omitempty"`
+}
+
+// criImage is an image as printed by crictl images -o json.
+type criImage struct {
+	ID          string   `json:"id"`
+	RepoTags    []string `json:"repoTags"`
+	RepoDigests []string `json:"repoDigests"`
+	Size        string   `json:"size"`
+	Pinned      bool     `json:"pinned"`
+}
+
+// nodeImages is what one node holds.
+type nodeImages struct {
+	images []criImage
+	// used are the image IDs and references of the node's containers, running or not.
+	used map[string]bool
+}
+
+// ListNodeImages lists the images cached on the nodes of a Kind cluster, one entry per
+// image however many nodes hold it, largest first.
+func (m *Manager) ListNodeImages(ctx context.Context, clusterName string) (*NodeImageReport, error) {
+	nodes, err := m.clusterNodeList(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NodeImageReport{Images: []NodeImage{}}
+	byID := make(map[string]*NodeImage)
+	var order []string
+	for _, node := range nodes {
+		held, err := m.nodeImages(ctx, node)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		for _, img := range held.images {
+			entry, ok := byID[img.ID]
+			if !ok {
+				size, _ := strconv.ParseInt(img.Size, 10, 64)
+				entry = &NodeImage{ID: img.ID, Size: size, Pinned: img.Pinned, Unused: true}
+				byID[img.ID] = entry
+				order = append(order, img.ID)
+			}
+			entry.Tags = appendMissing(entry.Tags, img.RepoTags...)
+			entry.Digests = appendMissing(entry.Digests, img.RepoDigests...)
+			entry.Nodes = append(entry.Nodes, node)
+			unused := !img.Pinned && !held.uses(img)
+			entry.Unused = entry.Unused && unused
+			report.DiskBytes += entry.Size
+			if unused {
+				report.UnusedBytes += entry.Size
+			}
+		}
+	}
+	if len(order) == 0 && len(report.Errors) > 0 {
+		return report, fmt.Errorf("listing node images failed: %s", strings.Join(report.Errors, "; "))
+	}
+
+	for _, id := range order {
+		report.Images = append(report.Images, *byID[id])
+	}
+	sort.SliceStable(report.Images, func(i, j int) bool { return report.Images[i].Size > report.Images[j].Size })
+	return report, nil
+}
+
+// PruneNodeImages removes images from the containerd store of every node of a Kind
+// cluster: the given images, or otherwise all that no container uses, like
+// crictl rmi --prune. Pinned images are kept. Errors on a node (e.g. an image still in
+// use) are reported and the other nodes are still pruned.
+func (m *Manager) PruneNodeImages(ctx context.Context, clusterName string, opts PruneNodeImagesOptions) (*NodeImagePruneResult, error) {
+	nodes, err := m.clusterNodeList(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NodeImagePruneResult{DryRun: opts.DryRun, Removed: []NodeImage{}}
+	removed := make(map[string]*NodeImage)
+	var order []string
+	for _, node := range nodes {
+		held, err := m.nodeImages(ctx, node)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+
+		var targets []criImage
+		for _, img := range held.images {
+			if img.Pinned {
+				continue
+			}
+			if len(opts.Images) == 0 && !held.uses(img) || len(opts.Images) > 0 && matchesAnyRef(img, opts.Images) {
+				targets = append(targets, img)
+			}
+		}
+
+		for _, img := range targets {
+			if !opts.DryRun {
+				if _, err := m.ExecOnNode(ctx, node, []string{"crictl", "rmi", img.ID}); err != nil {
+					result.Errors = append(result.Errors, err.Error())
+					continue
+				}
+			}
+			entry, ok := removed[img.ID]
+			if !ok {
+				size, _ := strconv.ParseInt(img.Size, 10, 64)
+				entry = &NodeImage{ID: img.ID, Tags: img.RepoTags, Digests: img.RepoDigests, Size: size, Unused: !held.uses(img)}
+				removed[img.ID] = entry
+				order = append(order, img.ID)
+			}
+			entry.Nodes = append(entry.Nodes, node)
+			result.FreedBytes += entry.Size
+		}
+	}
+
+	for _, id := range order {
+		result.Removed = append(result.Removed, *removed[id])
+	}
+	if len(order) == 0 && len(result.Errors) > 0 {
+		return result, fmt.Errorf("pruning node images failed: %s", strings.Join(result.Errors, "; "))
+	}
+	return result, nil
+}
+
+// clusterNodeList returns the nodes of a cluster, failing if it has none.
+func (m *Manager) clusterNodeList(ctx context.Context, clusterName string) ([]string, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	nodes, err := m.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q has no nodes", clusterName)
+	}
+	return nodes, nil
+}
+
+// nodeImages reads the images and the containers' images of a node through crictl.
+func (m *Manager) nodeImages(ctx context.Context, node string) (*nodeImages, error) {
+	out, err := m.ExecOnNode(ctx, node, []string{"crictl", "images", "-o", "json"})
+	if err != nil {
+		return nil, err
+	}
+	var images struct {
+		Images []criImage `json:"images"`
+	}
+	if err := json.Unmarshal([]byte(out), &images); err != nil {
+		return nil, fmt.Errorf("parsing images of node %q: %w", node, err)
+	}
+
+	out, err = m.ExecOnNode(ctx, node, []string{"crictl", "ps", "-a", "-o", "json"})
+	if err != nil {
+		return nil, err
+	}
+	var containers struct {
+		Containers []struct {
+			ImageRef string `json:"imageRef"`
+			Image    struct {
+				Image string `json:"image"`
+			} `json:"image"`
+		} `json:"containers"`
+	}
+	if err := json.Unmarshal([]byte(out), &containers); err != nil {
+		return nil, fmt.Errorf("parsing containers of node %q: %w", node, err)
+	}
+
+	held := &nodeImages{images: images.Images, used: make(map[string]bool)}
+	for _, c := range containers.Containers {
+		held.used[c.ImageRef] = true
+		held.used[c.Image.Image] = true
+	}
+	return held, nil
+}
+
+// uses reports whether a container on the node uses img, by ID, tag, or digest.
+func (n *nodeImages) uses(img criImage) bool {
+	if n.used[img.ID] {
+		return true
+	}
+	for _, refs := range [][]string{img.RepoTags, img.RepoDigests} {
+		for _, ref := range refs {
+			if n.used[ref] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesAnyRef reports whether img is one of refs, given as an ID (with or without
+// the sha256: prefix, possibly shortened), a tag, or a digest reference. Docker Hub
+// short names match their fully qualified tags.
+func matchesAnyRef(img criImage, refs []string) bool {
+	for _, ref := range refs {
+		id := strings.TrimPrefix(ref, "sha256:")
+		if len(id) >= 12 && strings.HasPrefix(strings.TrimPrefix(img.ID, "sha256:"), id) {
+			return true
+		}
+		for _, tag := range img.RepoTags {
+			if normalizeImageRef(tag) == normalizeImageRef(ref) {
+				return true
+			}
+		}
+		if contains(img.RepoDigests, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendMissing appends the values not already in list.
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		if !contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,79 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+const (
+	nodeImagesJSON = `{"images":[
+		{"id":"sha256:pause","repoTags":["registry.k8s.io/pause:3.10"],"size":"320000","pinned":true},
+		{"id":"sha256:nginx","repoTags":["docker.io/library/nginx:1.27"],"repoDigests":["docker.io/library/nginx@sha256:abc"],"size":"70000000"},
+		{"id":"sha256:old","repoTags":["docker.io/shop/web:v1"],"size":"120000000"}]}`
+	nodeContainersJSON = `{"containers":[{"imageRef":"docker.io/library/nginx@sha256:abc","image":{"image":"docker.io/library/nginx:1.27"}}]}`
+)
+
+func nodeImageRunner() *loggingRunner {
+	return &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"exec", "*", "crictl", "images"}, out: []byte(nodeImagesJSON)},
+		{name: "docker", args: []string{"exec", "*", "crictl", "ps"}, out: []byte(nodeContainersJSON)},
+		{name: "docker", args: []string{"exec", "*", "crictl", "rmi"}},
+	}}}
+}
+
+func TestListNodeImages(t *testing.T) {
+	runner := nodeImageRunner()
+	m := newDockerManager(runner.mockRunner)
+
+	report, err := m.ListNodeImages(context.Background(), "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Images) != 3 || report.Images[0].ID != "sha256:old" || len(report.Images[0].Nodes) != 2 {
+		t.Fatalf("images = %+v", report.Images)
+	}
+	if !report.Images[0].Unused || report.Images[1].Unused || report.Images[2].Unused {
+		t.Errorf("unused flags wrong: %+v", report.Images)
+	}
+	if report.DiskBytes != 2*190320000 || report.UnusedBytes != 2*120000000 {
+		t.Errorf("disk = %d, unused = %d", report.DiskBytes, report.UnusedBytes)
+	}
+}
+
+func TestPruneNodeImages(t *testing.T) {
+	runner := nodeImageRunner()
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	result, err := m.PruneNodeImages(context.Background(), "dev", PruneNodeImagesOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Removed) != 1 || result.Removed[0].ID != "sha256:old" || result.FreedBytes != 2*120000000 {
+		t.Errorf("result = %+v", result)
+	}
+	calls := strings.Join(runner.calls, "\n")
+	if strings.Count(calls, "crictl rmi sha256:old") != 2 || strings.Contains(calls, "rmi sha256:pause") {
+		t.Errorf("calls:\n%s", calls)
+	}
+}
+
+func TestPruneNodeImages_DryRunByName(t *testing.T) {
+	runner := nodeImageRunner()
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+
+	result, err := m.PruneNodeImages(context.Background(), "dev",
+		PruneNodeImagesOptions{Images: []string{"nginx:1.27", "registry.k8s.io/pause:3.10"}, DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Removed) != 1 || result.Removed[0].ID != "sha256:nginx" {
+		t.Errorf("removed = %+v", result.Removed)
+	}
+	if strings.Contains(strings.Join(runner.calls, "\n"), "rmi") {
+		t.Errorf("dry run removed images: %v", runner.calls)
+	}
+}
@@ -0,0 +1,117 @@
+package kind
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeConfigOverrides applies raw YAML (or JSON) overrides onto a Kind config, for
+// fields GenerateConfig does not model such as featureGates, runtimeConfig or
+// networking.dnsSearch. Mappings are merged key by key and a null value removes the
+// key, as in a JSON merge patch. The nodes list is merged node by node, so an
+// override's first node adds to the generated first node and extra override nodes
+// are appended; other lists, and scalars, replace the generated value. Key order and
+// comments of the config are kept.
+func MergeConfigOverrides(configYAML, overrides string) (string, error) {
+	var base, over yaml.Node
+	if err := yaml.Unmarshal([]byte(configYAML), &base); err != nil {
+		return "", fmt.Errorf("invalid config YAML: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(overrides), &over); err != nil {
+		return "", fmt.Errorf("invalid overrides YAML: %w", err)
+	}
+	if len(over.Content) == 0 {
+		return configYAML, nil
+	}
+	if len(base.Content) == 0 || base.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("config is not a YAML mapping")
+	}
+	if over.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("overrides must be a YAML mapping of config fields")
+	}
+	resetStyle(over.Content[0])
+	if err := mergeMapping(base.Content[0], over.Content[0], ""); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&base); err != nil {
+		return "", fmt.Errorf("marshaling config to YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("marshaling config to YAML: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// mergeMapping merges the override mapping src into dst; path is the dotted path of
+// dst, for error messages and to recognise the top-level nodes list.
+func mergeMapping(dst, src *yaml.Node, path string) error {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i], src.Content[i+1]
+		keyPath := key.Value
+		if path != "" {
+			keyPath = path + "." + key.Value
+		}
+		idx := mappingIndex(dst, key.Value)
+		switch {
+		case val.Tag == "!!null":
+			if idx >= 0 {
+				dst.Content = append(dst.Content[:idx], dst.Content[idx+2:]...)
+			}
+		case idx < 0:
+			dst.Content = append(dst.Content, key, val)
+		case val.Kind == yaml.MappingNode && dst.Content[idx+1].Kind == yaml.MappingNode:
+			if err := mergeMapping(dst.Content[idx+1], val, keyPath); err != nil {
+				return err
+			}
+		case keyPath == "nodes" && val.Kind == yaml.SequenceNode && dst.Content[idx+1].Kind == yaml.SequenceNode:
+			if err := mergeNodes(dst.Content[idx+1], val); err != nil {
+				return err
+			}
+		default:
+			dst.Content[idx+1] = val
+		}
+	}
+	return nil
+}
+
+// mergeNodes merges override nodes into the config's nodes by position.
+func mergeNodes(dst, src *yaml.Node) error {
+	for i, node := range src.Content {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("nodes[%d] override must be a mapping", i)
+		}
+		if i >= len(dst.Content) {
+			dst.Content = append(dst.Content, node)
+			continue
+		}
+		if err := mergeMapping(dst.Content[i], node, fmt.Sprintf("nodes[%d]", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mappingIndex returns the index of key in a mapping node's content, or -1.
+func mappingIndex(m *yaml.Node, key string) int {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// resetStyle drops the flow and quoting styles of JSON overrides, so merged fields are
+// written in the block style of the generated config.
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
@@ -0,0 +1,117 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuildConfig(t *testing.T) {
+	cfg, err := BuildConfig(ConfigOptions{ClusterName: "ast", NumWorkers: 2, PodSubnet: "10.50.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "ast" || len(cfg.Nodes) != 3 || cfg.Nodes[1].Role != RoleWorker {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Networking == nil || cfg.Networking.PodSubnet != "10.50.0.0/16" {
+		t.Errorf("unexpected networking: %+v", cfg.Networking)
+	}
+	if _, err := BuildConfig(ConfigOptions{}); err == nil {
+		t.Error("expected an error without a cluster name")
+	}
+}
+
+func TestMergeConfigOverrides(t *testing.T) {
+	base, err := GenerateConfig(ConfigOptions{ClusterName: "merge", NumWorkers: 1, PodSubnet: "10.50.0.0/16",
+		Labels: map[string]string{"tier": "dev"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	overrides := `
+featureGates:
+  InPlacePodVerticalScaling: true
+runtimeConfig:
+  api/alpha: "false"
+networking:
+  dnsSearch: []
+  podSubnet: 10.60.0.0/16
+nodes:
+- extraPortMappings:
+  - containerPort: 30000
+    hostPort: 30000
+  labels: null
+- {}
+- role: worker
+`
+	out, err := MergeConfigOverrides(base, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := yaml.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("merged config is not YAML: %v\n%s", err, out)
+	}
+	if got["featureGates"].(map[string]any)["InPlacePodVerticalScaling"] != true {
+		t.Errorf("featureGates not added:\n%s", out)
+	}
+	if got["runtimeConfig"].(map[string]any)["api/alpha"] != "false" {
+		t.Errorf("runtimeConfig string lost its type:\n%s", out)
+	}
+	networking := got["networking"].(map[string]any)
+	if networking["podSubnet"] != "10.60.0.0/16" || networking["dnsSearch"] == nil {
+		t.Errorf("networking not merged: %v", networking)
+	}
+	nodes := got["nodes"].([]any)
+	if len(nodes) != 3 {
+		t.Fatalf("expected the override node to be appended, got %d nodes", len(nodes))
+	}
+	first := nodes[0].(map[string]any)
+	if first["role"] != RoleControlPlane || first["extraPortMappings"] == nil || first["labels"] != nil {
+		t.Errorf("first node not merged: %v", first)
+	}
+	if nodes[1].(map[string]any)["labels"] == nil {
+		t.Errorf("second node should keep its labels: %v", nodes[1])
+	}
+	if !strings.HasPrefix(out, "kind: Cluster\napiVersion:") {
+		t.Errorf("key order not kept:\n%s", out)
+	}
+	if err := ValidateConfig(out); err != nil {
+		t.Errorf("merged config invalid: %v", err)
+	}
+}
+
+func TestMergeConfigOverrides_JSON(t *testing.T) {
+	base, err := GenerateConfig(ConfigOptions{ClusterName: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := MergeConfigOverrides(base, `{"featureGates": {"SidecarContainers": true}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "featureGates:\n    SidecarContainers: true\n") {
+		t.Errorf("JSON override not written in block style:\n%s", out)
+	}
+}
+
+func TestMergeConfigOverrides_Invalid(t *testing.T) {
+	base, err := GenerateConfig(ConfigOptions{ClusterName: "bad"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, overrides := range map[string]string{
+		"not yaml":    "nodes: [",
+		"not mapping": "- a",
+		"bad node":    "nodes:\n- worker",
+	} {
+		if _, err := MergeConfigOverrides(base, overrides); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if out, err := MergeConfigOverrides(base, ""); err != nil || out != base {
+		t.Errorf("empty overrides should return the config unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// ImagePlatform is one platform of a multi-arch image.
+type ImagePlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+	Digest       string `json:"digest,omitempty"`
+}
+
+// String formats the platform as os/arch[/variant], as docker --platform takes it.
+func (p ImagePlatform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// ParsePlatform parses "os/arch[/variant]" or a bare architecture, which implies linux.
+func ParsePlatform(s string) (ImagePlatform, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) == 1 {
+		parts = []string{"linux", parts[0]}
+	}
+	if len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return ImagePlatform{}, fmt.Errorf("invalid platform %q: use os/arch[/variant], e.g. linux/arm64", s)
+	}
+	p := ImagePlatform{OS: parts[0], Architecture: rtdetect.NormalizeArch(parts[1])}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// matches reports whether an image platform can run on p. A variant is only compared
+// when p asks for one.
+func (p ImagePlatform) matches(image ImagePlatform) bool {
+	return p.OS == image.OS && p.Architecture == image.Architecture &&
+		(p.Variant == "" || p.Variant == image.Variant)
+}
+
+// LoadImageOptions describes an image to load onto a cluster with LoadImageForPlatform.
+type LoadImageOptions struct {
+	Image string
+	// Platform defaults to linux/<node architecture>.
+	Platform string
+	// Pull fetches the image from its registry even when it exists locally.
+	Pull bool
+}
+
+// LoadImageResult is the outcome of LoadImageForPlatform.
+type LoadImageResult struct {
+	Image    string `json:"image"`
+	Platform string `json:"platform"`
+	// Digest is the platform-specific manifest that was loaded, when the image is a
+	// multi-arch index.
+	Digest string `json:"digest,omitempty"`
+	Pulled bool   `json:"pulled"`
+	// Available lists the platforms of a multi-arch image.
+	Available []string `json:"available,omitempty"`
+}
+
+// LoadImageForPlatform loads an image onto the nodes of a Kind cluster for a single
+// platform. kind load uses whatever variant of a tag the local runtime holds, which
+// is the host's; here a multi-arch tag is resolved to the digest for the nodes'
+// architecture (or the requested platform), pulled, and re-tagged before loading, and
+// loading fails with the available platforms when the image does not provide it.
+func (m *Manager) LoadImageForPlatform(ctx context.Context, clusterName string, opts LoadImageOptions) (*LoadImageResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if opts.Image == "" {
+		return nil, fmt.Errorf("image is required")
+	}
+	platformSpec := opts.Platform
+	if platformSpec == "" {
+		platformSpec = "linux/" + m.hostArch()
+	}
+	want, err := ParsePlatform(platformSpec)
+	if err != nil {
+		return nil, err
+	}
+	result := &LoadImageResult{Image: opts.Image, Platform: want.String()}
+
+	platforms, listErr := m.manifestPlatforms(ctx, opts.Image)
+	for _, p := range platforms {
+		result.Available = append(result.Available, p.String())
+	}
+	if len(platforms) > 0 {
+		match, ok := selectPlatform(platforms, want)
+		if !ok {
+			return result, fmt.Errorf("image %s has no %s variant; available platforms: %s",
+				opts.Image, want, strings.Join(result.Available, ", "))
+		}
+		result.Digest = match.Digest
+		if err := m.pullDigest(ctx, opts.Image, match); err != nil {
+			return result, err
+		}
+		result.Pulled = true
+	} else {
+		local, ok := m.localImagePlatform(ctx, opts.Image)
+		if !ok && listErr != nil {
+			return result, fmt.Errorf("image %s is neither available locally nor in its registry: %w", opts.Image, listErr)
+		}
+		// Without a registry manifest the local image is all there is.
+		if listErr == nil && (!ok || opts.Pull || !want.matches(local)) {
+			m.logger.Info("pulling image", "image", opts.Image, "platform", want.String())
+			if out, err := m.runner.Run(ctx, m.runtimeBin(), "pull", "--platform", want.String(), opts.Image); err != nil {
+				return result, fmt.Errorf("%s pull failed: %w\nOutput: %s", m.runtimeBin(), err, string(out))
+			}
+			result.Pulled = true
+			local, ok = m.localImagePlatform(ctx, opts.Image)
+		}
+		// A single-platform image is only usable if it already is the right one.
+		if ok && !want.matches(local) {
+			return result, fmt.Errorf("image %s is %s only, which cannot run natively on %s nodes; "+
+				"build or pull a %s variant", opts.Image, local, want, want)
+		}
+	}
+
+	if err := m.LoadImage(ctx, clusterName, opts.Image); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// selectPlatform picks the manifest for want, preferring an exact variant match.
+func selectPlatform(platforms []ImagePlatform, want ImagePlatform) (ImagePlatform, bool) {
+	var found *ImagePlatform
+	for i, p := range platforms {
+		if !want.matches(p) {
+			continue
+		}
+		if p.Variant == want.Variant {
+			return p, true
+		}
+		if found == nil {
+			found = &platforms[i]
+		}
+	}
+	if found == nil {
+		return ImagePlatform{}, false
+	}
+	return *found, true
+}
+
+// pullDigest pulls the manifest for p by digest and tags it as image, so that the
+// tag loaded onto the nodes refers to that platform whatever the host's is.
+func (m *Manager) pullDigest(ctx context.Context, image string, p ImagePlatform) error {
+	if p.Digest == "" {
+		return fmt.Errorf("manifest for %s in %s has no digest", p, image)
+	}
+	ref := imageRepository(image) + "@" + p.Digest
+	m.logger.Info("pulling image", "image", ref, "platform", p.String())
+	if out, err := m.runner.Run(ctx, m.runtimeBin(), "pull", "--platform", p.String(), ref); err != nil {
+		return fmt.Errorf("%s pull failed: %w\nOutput: %s", m.runtimeBin(), err, string(out))
+	}
+	if out, err := m.runner.Run(ctx, m.runtimeBin(), "tag", ref, image); err != nil {
+		return fmt.Errorf("%s tag failed: %w\nOutput: %s", m.runtimeBin(), err, string(out))
+	}
+	return nil
+}
+
+// localImagePlatform returns the platform of an image in the local runtime.
+func (m *Manager) localImagePlatform(ctx context.Context, image string) (ImagePlatform, bool) {
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "image", "inspect", "--format",
+		"{{.Os}}/{{.Architecture}}/{{.Variant}}", image)
+	if err != nil {
+		return ImagePlatform{}, false
+	}
+	parts := strings.SplitN(strings.TrimSpace(string(out)), "/", 3)
+	if len(parts) < 2 || parts[1] == "" {
+		return ImagePlatform{}, false
+	}
+	p := ImagePlatform{OS: parts[0], Architecture: rtdetect.NormalizeArch(parts[1])}
+	if len(parts) == 3 && parts[2] != "<no value>" {
+		p.Variant = parts[2]
+	}
+	return p, true
+}
+
+// manifestPlatforms returns the platforms of a multi-arch image from its registry
+// manifest, skipping attestation entries. It returns no platforms for single-platform
+// images.
+func (m *Manager) manifestPlatforms(ctx context.Context, image string) ([]ImagePlatform, error) {
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "manifest", "inspect", image)
+	if err != nil {
+		m.logger.Debug("inspecting image manifest failed", "image", image, "output", string(out))
+		return nil, fmt.Errorf("%s manifest inspect failed: %w", m.runtimeBin(), err)
+	}
+	var list struct {
+		Manifests []struct {
+			Digest   string        `json:"digest"`
+			Platform ImagePlatform `json:"platform"`
+		} `json:"manifests"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parsing manifest of %s: %w", image, err)
+	}
+	var platforms []ImagePlatform
+	for _, mf := range list.Manifests {
+		p := mf.Platform
+		p.Architecture = rtdetect.NormalizeArch(p.Architecture)
+		if p.OS == "" || p.OS == "unknown" || p.Architecture == "" || p.Architecture == "unknown" {
+			continue
+		}
+		p.Digest = mf.Digest
+		platforms = append(platforms, p)
+	}
+	return platforms, nil
+}
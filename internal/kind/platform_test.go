@@ -0,0 +1,86 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+const multiArchManifest = `{"manifests":[
+	{"digest":"sha256:amd","platform":{"architecture":"amd64","os":"linux"}},
+	{"digest":"sha256:armv7","platform":{"architecture":"arm","os":"linux","variant":"v7"}},
+	{"digest":"sha256:arm","platform":{"architecture":"arm64","os":"linux","variant":"v8"}},
+	{"digest":"sha256:att","platform":{"architecture":"unknown","os":"unknown"}}]}`
+
+func TestParsePlatform(t *testing.T) {
+	tests := map[string]string{
+		"arm64":         "linux/arm64",
+		"linux/x86_64":  "linux/amd64",
+		"linux/arm/v7":  "linux/arm/v7",
+		"windows/amd64": "windows/amd64",
+	}
+	for in, want := range tests {
+		p, err := ParsePlatform(in)
+		if err != nil || p.String() != want {
+			t.Errorf("ParsePlatform(%q) = %q, %v; want %q", in, p, err, want)
+		}
+	}
+	for _, in := range []string{"", "linux/", "a/b/c/d"} {
+		if _, err := ParsePlatform(in); err == nil {
+			t.Errorf("ParsePlatform(%q): expected error", in)
+		}
+	}
+}
+
+func TestLoadImageForPlatform_ResolvesDigest(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"manifest", "inspect", "nginx:1.27"}, out: []byte(multiArchManifest)},
+		{name: "docker", args: []string{"pull"}},
+		{name: "docker", args: []string{"tag"}},
+		{name: "docker", args: []string{"save"}},
+		{name: "kind", args: []string{"load", "image-archive"}},
+	}}}
+
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker, Arch: "arm64"}, nil)
+
+	result, err := m.LoadImageForPlatform(context.Background(), "dev", LoadImageOptions{Image: "nginx:1.27"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Platform != "linux/arm64" || result.Digest != "sha256:arm" || len(result.Available) != 3 {
+		t.Errorf("result = %+v", result)
+	}
+	calls := strings.Join(runner.calls, "\n")
+	if !strings.Contains(calls, "pull --platform linux/arm64/v8 nginx@sha256:arm") ||
+		!strings.Contains(calls, "tag nginx@sha256:arm nginx:1.27") {
+		t.Errorf("calls:\n%s", calls)
+	}
+}
+
+func TestLoadImageForPlatform_NoCompatiblePlatform(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"manifest", "inspect"}, out: []byte(multiArchManifest)},
+	}}
+
+	_, err := newArchManager(runner, "arm64").LoadImageForPlatform(context.Background(), "dev",
+		LoadImageOptions{Image: "nginx:1.27", Platform: "linux/s390x"})
+	if err == nil || !strings.Contains(err.Error(), "linux/amd64, linux/arm/v7, linux/arm64/v8") {
+		t.Errorf("expected error listing platforms, got %v", err)
+	}
+}
+
+func TestLoadImageForPlatform_LocalSinglePlatform(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"manifest", "inspect"}, err: fmt.Errorf("no such manifest")},
+		{name: "docker", args: []string{"image", "inspect"}, out: []byte("linux/amd64/\n")},
+	}}
+
+	_, err := newArchManager(runner, "arm64").LoadImageForPlatform(context.Background(), "dev",
+		LoadImageOptions{Image: "shop/web:dev"})
+	if err == nil || !strings.Contains(err.Error(), "linux/amd64 only") {
+		t.Errorf("expected architecture mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// Services SuggestPortMappings maps to an ingress controller on the node.
+const (
+	PortServiceHTTP  = "http"
+	PortServiceHTTPS = "https"
+)
+
+// portSearchLimit bounds how many host ports SuggestPortMappings tries per mapping.
+const portSearchLimit = 100
+
+// PortRequest is a custom port to map from the host to a node.
+type PortRequest struct {
+	// ContainerPort is the port on the node, usually a Service's NodePort.
+	ContainerPort int `json:"container_port"`
+	// Protocol is TCP (the default) or UDP.
+	Protocol string `json:"protocol,omitempty"`
+	// HostPort is the preferred host port. Default: ContainerPort.
+	HostPort int `json:"host_port,omitempty"`
+}
+
+// PortSuggestionRequest lists the services to reach from the host.
+type PortSuggestionRequest struct {
+	// Services are PortServiceHTTP and PortServiceHTTPS, served by an ingress
+	// controller on container ports 80 and 443.
+	Services []string
+	Ports    []PortRequest
+	// Audience is AudienceLocalhost or AudienceLAN. Default: the backend's listen address.
+	Audience string
+}
+
+// PortSuggestion is a set of extraPortMappings whose host ports are free.
+type PortSuggestion struct {
+	Backend       rtdetect.Backend `json:"backend"`
+	ListenAddress string           `json:"listen_address"`
+	PortMappings  []PortMapping    `json:"port_mappings"`
+	// GenerateParams are the generate_cluster_config arguments for the mappings.
+	GenerateParams map[string]any `json:"generate_cluster_config"`
+	// Reassigned explains each mapping that did not get its preferred host port.
+	Reassigned []string `json:"reassigned,omitempty"`
+	Notes      []string `json:"notes,omitempty"`
+}
+
+// hostPortFree reports whether a host port can be bound. A permission error does not
+// count as taken: an unprivileged user cannot bind ports below 1024, which the container
+// runtime still can. Overridden in tests.
+var hostPortFree = func(listen string, port int, protocol string) bool {
+	addr := net.JoinHostPort(listen, strconv.Itoa(port))
+	var l io.Closer
+	var err error
+	if protocol == "UDP" {
+		l, err = net.ListenPacket("udp", addr)
+	} else {
+		l, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return errors.Is(err, os.ErrPermission)
+	}
+	l.Close()
+	return true
+}
+
+// publishedPortPattern matches a host port in the Ports column of 'docker ps' and
+// 'podman ps', e.g. "127.0.0.1:80->80/tcp".
+var publishedPortPattern = regexp.MustCompile(`:(\d+)->\d+(?:-\d+)?/(tcp|udp|sctp)`)
+
+// SuggestPortMappings returns extraPortMappings for the requested services whose host
+// ports are neither published by a running container (another Kind cluster, a local
+// registry) nor bound on this machine. A taken or, with rootless Podman, privileged
+// preferred port is replaced by the next free one: 8080 and 8443 for http and https,
+// the following ports for custom ones.
+func (m *Manager) SuggestPortMappings(ctx context.Context, req PortSuggestionRequest) (*PortSuggestion, error) {
+	if len(req.Services) == 0 && len(req.Ports) == 0 {
+		return nil, fmt.Errorf("at least one service or port is required")
+	}
+	base := DetectNetworkConfig(m.runtime)
+	s := &PortSuggestion{Backend: m.runtime.Backend, ListenAddress: base.ListenAddress, PortMappings: []PortMapping{}}
+	switch req.Audience {
+	case "":
+	case AudienceLocalhost:
+		s.ListenAddress = "127.0.0.1"
+	case AudienceLAN:
+		s.ListenAddress = "0.0.0.0"
+	default:
+		return nil, fmt.Errorf("unknown audience %q; must be %q or %q", req.Audience, AudienceLocalhost, AudienceLAN)
+	}
+
+	type wanted struct {
+		name     string
+		port     PortRequest
+		fallback int
+	}
+	var all []wanted
+	for _, svc := range req.Services {
+		switch svc {
+		case PortServiceHTTP:
+			all = append(all, wanted{svc, PortRequest{ContainerPort: 80, Protocol: "TCP", HostPort: 80}, 8080})
+		case PortServiceHTTPS:
+			all = append(all, wanted{svc, PortRequest{ContainerPort: 443, Protocol: "TCP", HostPort: 443}, 8443})
+		default:
+			return nil, fmt.Errorf("unknown service %q; must be %q or %q", svc, PortServiceHTTP, PortServiceHTTPS)
+		}
+	}
+	nodePortRange := [2]int{30000, 32767}
+	for _, p := range req.Ports {
+		p.Protocol = strings.ToUpper(p.Protocol)
+		if p.Protocol == "" {
+			p.Protocol = "TCP"
+		}
+		if p.Protocol != "TCP" && p.Protocol != "UDP" {
+			return nil, fmt.Errorf("port %d: protocol must be TCP or UDP, not %q", p.ContainerPort, p.Protocol)
+		}
+		if p.HostPort == 0 {
+			p.HostPort = p.ContainerPort
+		}
+		if p.ContainerPort < 1 || p.ContainerPort > 65535 || p.HostPort < 1 || p.HostPort > 65535 {
+			return nil, fmt.Errorf("invalid port mapping %d->%d", p.HostPort, p.ContainerPort)
+		}
+		nodePortRange = [2]int{min(nodePortRange[0], p.ContainerPort), max(nodePortRange[1], p.ContainerPort)}
+		all = append(all, wanted{fmt.Sprintf("%d/%s", p.ContainerPort, p.Protocol), p, p.HostPort + 1})
+	}
+
+	published, err := m.publishedHostPorts(ctx)
+	if err != nil {
+		published = make(map[string]bool)
+		s.Notes = append(s.Notes, fmt.Sprintf("could not list the ports of running containers (%v); "+
+			"only ports bound on this machine were checked", err))
+	}
+	privileged := rootlessPodman(m.runtime)
+	taken := func(port int, protocol string) string {
+		key := portKey(port, protocol)
+		switch {
+		case published[key]:
+			return "published by a running container"
+		case privileged && port < 1024:
+			return "privileged, and rootless Podman cannot bind it"
+		case !hostPortFree(s.ListenAddress, port, protocol):
+			return "in use on this machine"
+		}
+		return ""
+	}
+
+	for _, w := range all {
+		port, reason := w.port.HostPort, taken(w.port.HostPort, w.port.Protocol)
+		if reason != "" {
+			port = 0
+			for candidate := w.fallback; candidate < w.fallback+portSearchLimit && candidate <= 65535; candidate++ {
+				if taken(candidate, w.port.Protocol) == "" {
+					port = candidate
+					break
+				}
+			}
+			if port == 0 {
+				return nil, fmt.Errorf("%s: no free host port in %d-%d", w.name, w.fallback, w.fallback+portSearchLimit-1)
+			}
+			s.Reassigned = append(s.Reassigned, fmt.Sprintf("%s: host port %d is %s; using %d",
+				w.name, w.port.HostPort, reason, port))
+		}
+		// Reserve the port so later mappings do not pick it too.
+		published[portKey(port, w.port.Protocol)] = true
+		s.PortMappings = append(s.PortMappings, PortMapping{HostPort: port, ContainerPort: w.port.ContainerPort,
+			ListenAddress: s.ListenAddress, Protocol: w.port.Protocol})
+	}
+
+	s.GenerateParams = map[string]any{"port_mappings": portMappingsParam(s.PortMappings)}
+	if len(req.Services) > 0 {
+		s.Notes = append(s.Notes, "http and https map to container ports 80 and 443 of the first control plane: "+
+			"label it ingress-ready=true and install an ingress controller binding hostPorts 80/443 there, "+
+			"e.g. kind's ingress-nginx manifest.")
+	}
+	if len(req.Ports) > 0 {
+		s.Notes = append(s.Notes, "Pin each Service's nodePort to its container_port so the mapping stays valid.")
+		if nodePortRange != [2]int{30000, 32767} {
+			s.GenerateParams["node_port_range"] = fmt.Sprintf("%d-%d", nodePortRange[0], nodePortRange[1])
+			s.Notes = append(s.Notes, "Some container ports are outside the default NodePort range 30000-32767; "+
+				"node_port_range widens it to include them.")
+		}
+	}
+	if base.RequiresExtraConfig {
+		s.Notes = append(s.Notes, "This backend needs extra port-forwarding configuration: "+base.Notes)
+	}
+	return s, nil
+}
+
+// publishedHostPorts returns the host ports the running containers publish, keyed by
+// portKey.
+func (m *Manager) publishedHostPorts(ctx context.Context) (map[string]bool, error) {
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "ps", "--format", "{{.Ports}}")
+	if err != nil {
+		return nil, fmt.Errorf("%s ps failed: %w", m.runtimeBin(), err)
+	}
+	ports := make(map[string]bool)
+	for _, match := range publishedPortPattern.FindAllStringSubmatch(string(out), -1) {
+		port, _ := strconv.Atoi(match[1])
+		ports[portKey(port, match[2])] = true
+	}
+	return ports, nil
+}
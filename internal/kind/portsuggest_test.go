@@ -0,0 +1,67 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestSuggestPortMappings(t *testing.T) {
+	defer func(f func(string, int, string) bool) { hostPortFree = f }(hostPortFree)
+	hostPortFree = func(_ string, port int, _ string) bool { return port != 8080 }
+
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"ps"}, out: []byte("127.0.0.1:80->80/tcp, 127.0.0.1:443->443/tcp\n" +
+			"127.0.0.1:5001->5000/tcp\n\n0.0.0.0:30053->30053/udp\n")},
+	}}
+	s, err := newDockerManager(runner).SuggestPortMappings(context.Background(), PortSuggestionRequest{
+		Services: []string{PortServiceHTTP, PortServiceHTTPS},
+		Ports:    []PortRequest{{ContainerPort: 30053, Protocol: "udp"}, {ContainerPort: 5432, HostPort: 5001}},
+		Audience: AudienceLAN,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, pm := range s.PortMappings {
+		got = append(got, portMappingString(pm))
+	}
+	want := "0.0.0.0:8081->80/TCP 0.0.0.0:8443->443/TCP 0.0.0.0:30054->30053/UDP 0.0.0.0:5002->5432/TCP"
+	if strings.Join(got, " ") != want {
+		t.Errorf("mappings = %v, want %s", got, want)
+	}
+	if len(s.Reassigned) != 4 || !strings.Contains(s.Reassigned[0], "published by a running container") {
+		t.Errorf("reassigned = %v", s.Reassigned)
+	}
+	if s.GenerateParams["node_port_range"] != "5432-32767" ||
+		!strings.Contains(s.GenerateParams["port_mappings"].(string), `"host_port":8081`) {
+		t.Errorf("generate params = %v", s.GenerateParams)
+	}
+}
+
+func TestSuggestPortMappings_RootlessPodman(t *testing.T) {
+	defer func(f func(string, int, string) bool) { hostPortFree = f }(hostPortFree)
+	hostPortFree = func(string, int, string) bool { return true }
+
+	runner := &mockRunner{runs: []runCall{{name: "podman", args: []string{"ps"}}}}
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimePodman, Backend: rtdetect.BackendNative,
+		OS: rtdetect.OSInfo{OS: "linux"}, Podman: &rtdetect.PodmanInfo{Rootless: true}}, nil)
+	s, err := m.SuggestPortMappings(context.Background(), PortSuggestionRequest{Services: []string{PortServiceHTTP}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ListenAddress != "0.0.0.0" || s.PortMappings[0].HostPort != 8080 {
+		t.Errorf("suggestion = %+v", s)
+	}
+
+	if _, err := m.SuggestPortMappings(context.Background(), PortSuggestionRequest{Services: []string{"grpc"}}); err == nil {
+		t.Error("expected an error for an unknown service")
+	}
+}
+
+func portMappingString(pm PortMapping) string {
+	return fmt.Sprintf("%s:%d->%d/%s", pm.ListenAddress, pm.HostPort, pm.ContainerPort, pm.Protocol)
+}
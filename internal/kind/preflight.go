@@ -0,0 +1,133 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Preflight check outcomes.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// PreflightCheck is the outcome of a single preflight probe.
+type PreflightCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// Remediation lists commands that fix a failing or warning check, in order.
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+// CreatePlan describes what CreateCluster would do, without doing it.
+type CreatePlan struct {
+	Preflight  []PreflightCheck `json:"preflight"`
+	Commands   []string         `json:"commands"`
+	ConfigFile string           `json:"config_file"` // "-" when the config is passed on stdin
+	ConfigYAML string           `json:"config_yaml"`
+}
+
+// Preflight runs read-only checks that catch common cluster creation failures early.
+func (m *Manager) Preflight(ctx context.Context, clusterName string) []PreflightCheck {
+	var checks []PreflightCheck
+
+	if m.lib != nil {
+		checks = append(checks, PreflightCheck{Name: "kind-binary", Status: CheckPass,
+			Message: "using the kind Go library; the kind CLI is not required"})
+	} else if path, err := m.runner.LookPath("kind"); err != nil {
+		checks = append(checks, PreflightCheck{Name: "kind-binary", Status: CheckFail,
+			Message: "kind CLI not found in PATH; install it from https://kind.sigs.k8s.io/"})
+	} else {
+		checks = append(checks, PreflightCheck{Name: "kind-binary", Status: CheckPass,
+			Message: fmt.Sprintf("kind found at %s", path)})
+	}
+
+	if !m.runtime.Available {
+		msg := "no container runtime available"
+		if m.runtime.Error != "" {
+			msg = m.runtime.Error
+		}
+		checks = append(checks, PreflightCheck{Name: "container-runtime", Status: CheckFail, Message: msg})
+	} else {
+		checks = append(checks, PreflightCheck{Name: "container-runtime", Status: CheckPass,
+			Message: fmt.Sprintf("%s %s (%s)", m.runtime.Runtime, m.runtime.Version, m.runtime.Backend)})
+		checks = append(checks, m.archCheck())
+		checks = append(checks, m.LinuxHostChecks()...)
+	}
+
+	if clusterName != "" {
+		clusters, err := m.ListClusters(ctx)
+		switch {
+		case err != nil:
+			checks = append(checks, PreflightCheck{Name: "cluster-name", Status: CheckWarn,
+				Message: fmt.Sprintf("could not list existing clusters: %v", err)})
+		case contains(clusters, clusterName):
+			checks = append(checks, PreflightCheck{Name: "cluster-name", Status: CheckFail,
+				Message: fmt.Sprintf("a cluster named %q already exists", clusterName)})
+		default:
+			checks = append(checks, PreflightCheck{Name: "cluster-name", Status: CheckPass,
+				Message: fmt.Sprintf("cluster name %q is available", clusterName)})
+		}
+	}
+
+	return checks
+}
+
+// PreflightFailed reports whether any check failed.
+func PreflightFailed(checks []PreflightCheck) bool {
+	for _, c := range checks {
+		if c.Status == CheckFail {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanCreateCluster validates the config, runs preflight checks, and returns the
+// exact commands and config file CreateCluster would use, without creating anything.
+func (m *Manager) PlanCreateCluster(ctx context.Context, name string, configYAML string) (*CreatePlan, error) {
+	if err := ValidateClusterName(name); err != nil {
+		return nil, err
+	}
+	if err := ValidateConfig(configYAML); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	configFile := m.plannedConfigSource(name)
+	return &CreatePlan{
+		Preflight:  m.Preflight(ctx, name),
+		Commands:   []string{ShellJoin(append(append(m.kindEnv(), "kind"), m.createArgs(name, configFile)...))},
+		ConfigFile: configFile,
+		ConfigYAML: configYAML,
+	}, nil
+}
+
+// ExecCommandLine returns the runtime command line ExecOnNode would run.
+func (m *Manager) ExecCommandLine(nodeName string, cmd []string) string {
+	return ShellJoin(append([]string{m.runtimeBin(), "exec", nodeName}, cmd...))
+}
+
+// ShellJoin joins arguments into a copy-pasteable shell command line, single-quoting
+// arguments that contain shell metacharacters.
+func ShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"\\$`*?[]{}()<>|&;#~!") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
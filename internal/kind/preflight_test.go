@@ -0,0 +1,76 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestPreflight_ClusterExists(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "clusters"}, out: []byte("dev\n")},
+		},
+	}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker, Available: true}, nil)
+
+	checks := mgr.Preflight(context.Background(), "dev")
+	if !PreflightFailed(checks) {
+		t.Errorf("expected preflight failure for existing cluster: %+v", checks)
+	}
+
+	checks = mgr.Preflight(context.Background(), "fresh")
+	if PreflightFailed(checks) {
+		t.Errorf("unexpected preflight failure: %+v", checks)
+	}
+}
+
+func TestPreflight_NoRuntime(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{{name: "kind", args: []string{"get", "clusters"}}},
+	}
+	mgr := NewManager(runner, rtdetect.RuntimeInfo{Error: "no container runtime detected"}, nil)
+
+	checks := mgr.Preflight(context.Background(), "dev")
+	found := false
+	for _, c := range checks {
+		if c.Name == "container-runtime" && c.Status == CheckFail {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected container-runtime failure: %+v", checks)
+	}
+}
+
+func TestPlanCreateCluster(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{{name: "kind", args: []string{"get", "clusters"}}},
+	}
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1})
+
+	plan, err := newDockerManager(runner).PlanCreateCluster(context.Background(), "dev", cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Commands) != 1 || !strings.HasPrefix(plan.Commands[0], "kind create cluster --name dev --config") {
+		t.Errorf("commands = %v", plan.Commands)
+	}
+	if plan.ConfigYAML != cfg {
+		t.Error("plan should include the config YAML")
+	}
+
+	if _, err := newDockerManager(runner).PlanCreateCluster(context.Background(), "dev", "kind: Pod"); err == nil {
+		t.Error("expected error for invalid config")
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	got := ShellJoin([]string{"bash", "-c", "echo 'hi' > /tmp/x"})
+	want := `bash -c 'echo '\''hi'\'' > /tmp/x'`
+	if got != want {
+		t.Errorf("ShellJoin = %s, want %s", got, want)
+	}
+}
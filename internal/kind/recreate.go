@@ -0,0 +1,117 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReconstructConfig builds a Kind config for an existing cluster from its running
+// node containers (roles and node images). It is used when no stored config exists.
+func (m *Manager) ReconstructConfig(ctx context.Context, name string) (string, error) {
+	nodes, err := m.GetClusterNodes(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("cluster %q %w", name, ErrClusterNotFound)
+	}
+
+	cfg := ClusterConfig{
+		Kind:       "Cluster",
+		APIVersion: "kind.x-k8s.io/v1alpha4",
+		Name:       name,
+	}
+	var workers []NodeConfig
+	for _, node := range nodes {
+		role := NodeRole(node)
+		if role == RoleExternalLoadBalancer {
+			continue
+		}
+		out, err := m.runner.Run(ctx, m.runtimeBin(), "inspect", "--format", "{{.Config.Image}}", node)
+		if err != nil {
+			return "", fmt.Errorf("inspecting node %q: %w", node, err)
+		}
+		nc := NodeConfig{Role: role, Image: strings.TrimSpace(string(out))}
+		// Keep control planes first, as Kind expects.
+		if role == RoleControlPlane {
+			cfg.Nodes = append(cfg.Nodes, nc)
+		} else {
+			workers = append(workers, nc)
+		}
+	}
+	cfg.Nodes = append(cfg.Nodes, workers...)
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("marshaling config to YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// SetNodeImageVersion rewrites the image of every node in a Kind config YAML to the
+// given Kubernetes version, keeping each node's image repository (kindest/node if it
+// has none). Fields not modeled by ClusterConfig are preserved.
+func SetNodeImageVersion(configYAML, version string) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(configYAML), &doc); err != nil {
+		return "", fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("config must be a YAML mapping")
+	}
+
+	nodes := mappingValue(doc.Content[0], "nodes")
+	if nodes == nil || nodes.Kind != yaml.SequenceNode || len(nodes.Content) == 0 {
+		return "", fmt.Errorf("config has no nodes to update")
+	}
+
+	for _, node := range nodes.Content {
+		if node.Kind != yaml.MappingNode {
+			continue
+		}
+		if v := mappingValue(node, "image"); v != nil {
+			v.Value = kindNodeImage(imageRepository(v.Value), version)
+			continue
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "image"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: kindNodeImage("", version)})
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return "", fmt.Errorf("marshaling config to YAML: %w", err)
+	}
+	return string(out), nil
+}
+
+// RecreateCluster deletes a cluster and creates it again from configYAML. The name
+// and config are validated first, so a create that would fail does not delete the
+// cluster.
+func (m *Manager) RecreateCluster(ctx context.Context, name, configYAML string) (string, error) {
+	if err := ValidateClusterName(name); err != nil {
+		return "", err
+	}
+	if err := ValidateConfig(configYAML); err != nil {
+		return "", fmt.Errorf("invalid config: %w", err)
+	}
+	deleteOut, err := m.DeleteCluster(ctx, name)
+	if err != nil {
+		return deleteOut, err
+	}
+	createOut, err := m.CreateCluster(ctx, name, configYAML)
+	return deleteOut + createOut, err
+}
+
+// mappingValue returns the value node for key in a YAML mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestReconstructConfig(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"get", "nodes"}, out: []byte(
+				"ha-worker\nha-external-load-balancer\nha-control-plane\n")},
+			{name: "docker", args: []string{"inspect", "--format", "{{.Config.Image}}"}, out: []byte("kindest/node:v1.31.0\n")},
+		},
+	}
+
+	out, err := newDockerManager(runner).ReconstructConfig(context.Background(), "ha")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes (load balancer skipped), got %d", len(cfg.Nodes))
+	}
+	if cfg.Nodes[0].Role != RoleControlPlane || cfg.Nodes[1].Role != RoleWorker {
+		t.Errorf("node order = %+v", cfg.Nodes)
+	}
+	if cfg.Nodes[0].Image != "kindest/node:v1.31.0" {
+		t.Errorf("image = %q", cfg.Nodes[0].Image)
+	}
+	if err := ValidateConfig(out); err != nil {
+		t.Errorf("reconstructed config invalid: %v", err)
+	}
+}
+
+func TestSetNodeImageVersion(t *testing.T) {
+	in := `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+  - role: control-plane
+    image: kindest/node:v1.30.0
+  - role: worker
+kubeadmConfigPatchesJSON6902: []
+`
+	out, err := SetNodeImageVersion(in, "1.31.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(out, "kindest/node:v1.31.0") != 2 {
+		t.Errorf("expected both nodes on v1.31.0:\n%s", out)
+	}
+	if !strings.Contains(out, "kubeadmConfigPatchesJSON6902") {
+		t.Error("unmodeled fields should be preserved")
+	}
+}
+
+func TestSetNodeImageVersion_NoNodes(t *testing.T) {
+	if _, err := SetNodeImageVersion("kind: Cluster\n", "1.31.0"); err == nil {
+		t.Error("expected error for config without nodes")
+	}
+}
+
+func TestRecreateCluster(t *testing.T) {
+	runner := &mockRunner{
+		runs: []runCall{
+			{name: "kind", args: []string{"delete", "cluster"}, out: []byte("Deleting cluster\n")},
+			{name: "kind", args: []string{"create", "cluster"}, out: []byte("Creating cluster\n")},
+		},
+	}
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "dev", NumControlPlanes: 1})
+
+	out, err := newDockerManager(runner).RecreateCluster(context.Background(), "dev", cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Deleting") || !strings.Contains(out, "Creating") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestSetNodeImageVersion_KeepsRepository(t *testing.T) {
+	in := "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n  - role: control-plane\n    image: registry.corp/kind/node:v1.30.0\n"
+	out, err := SetNodeImageVersion(in, "1.31.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "registry.corp/kind/node:v1.31.0") {
+		t.Errorf("expected repository to be kept:\n%s", out)
+	}
+}
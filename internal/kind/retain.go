@@ -0,0 +1,83 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CreateOptions holds optional settings for CreateClusterWithOptions.
+type CreateOptions struct {
+	// RetainOnFailure keeps the node containers of a failed create (kind --retain)
+	// and exports their logs so the failure can be inspected.
+	RetainOnFailure bool
+	// ExtraArgs are additional kind create cluster flags, checked with ValidateKindArgs.
+	// --retain among them sets RetainOnFailure.
+	ExtraArgs []string
+}
+
+// RetainedCreateError is returned when a create with RetainOnFailure fails. The
+// cluster's node containers are left in place and their logs exported to LogsDir.
+type RetainedCreateError struct {
+	Cluster string
+	Nodes   []string
+	LogsDir string
+	// LogsErr is set when the logs could not be exported, fully or at all.
+	LogsErr error
+	Err     error
+}
+
+func (e *RetainedCreateError) Error() string {
+	msg := e.Err.Error()
+	if len(e.Nodes) > 0 {
+		msg += fmt.Sprintf("\nNode containers kept for inspection: %s", strings.Join(e.Nodes, ", "))
+	}
+	if e.LogsDir != "" {
+		msg += "\nLogs exported to " + e.LogsDir
+	}
+	if e.LogsErr != nil {
+		msg += fmt.Sprintf("\nExporting logs failed: %v", e.LogsErr)
+	}
+	return msg
+}
+
+func (e *RetainedCreateError) Unwrap() error { return e.Err }
+
+// ExportLogs writes the logs and debug files of every node of a cluster to dir, as
+// "kind export logs" does.
+func (m *Manager) ExportLogs(ctx context.Context, name, dir string) error {
+	if m.lib != nil {
+		if err := m.lib.CollectLogs(name, dir); err != nil {
+			return fmt.Errorf("kind export logs failed: %w", err)
+		}
+		return nil
+	}
+	out, err := m.runner.Run(m.kindContext(ctx), "kind", "export", "logs", dir, "--name", name)
+	if err != nil {
+		return fmt.Errorf("kind export logs failed: %w\nOutput: %s", err, string(out))
+	}
+	return nil
+}
+
+// retainedFailure exports the logs of a cluster whose create failed with err and
+// returns a RetainedCreateError pointing at them and at the kept node containers.
+func (m *Manager) retainedFailure(ctx context.Context, name string, err error) error {
+	failure := &RetainedCreateError{Cluster: name, Err: err}
+	failure.Nodes, _ = m.GetClusterNodes(ctx, name)
+	if len(failure.Nodes) == 0 {
+		failure.LogsErr = errors.New("the create failed before any node container was started")
+		return failure
+	}
+
+	m.logger.Warn("cluster creation failed, exporting logs of the retained nodes", "name", name)
+	dir, derr := os.MkdirTemp("", "kind-logs-"+name+"-")
+	if derr != nil {
+		failure.LogsErr = derr
+		return failure
+	}
+	failure.LogsDir = dir
+	failure.LogsErr = m.ExportLogs(ctx, name, dir)
+	return failure
+}
@@ -0,0 +1,39 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestCreateCluster_RetainOnFailure(t *testing.T) {
+	runner := &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"create", "cluster"}, out: []byte("ERROR: failed to init node with kubeadm\n"), err: errors.New("exit status 1")},
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("test-control-plane\n")},
+		{name: "kind", args: []string{"export", "logs"}},
+	}}}
+	cfg, _ := GenerateConfig(ConfigOptions{ClusterName: "test", NumControlPlanes: 1})
+
+	m := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+	_, err := m.CreateClusterWithOptions(context.Background(), "test", cfg, CreateOptions{RetainOnFailure: true})
+
+	var retained *RetainedCreateError
+	if !errors.As(err, &retained) {
+		t.Fatalf("expected RetainedCreateError, got %v", err)
+	}
+	defer os.RemoveAll(retained.LogsDir)
+	if retained.LogsDir == "" || retained.LogsErr != nil || len(retained.Nodes) != 1 {
+		t.Errorf("retained = %+v", retained)
+	}
+	calls := strings.Join(runner.calls, "\n")
+	if !strings.Contains(calls, "--retain") || !strings.Contains(calls, "kind export logs "+retained.LogsDir+" --name test") {
+		t.Errorf("calls:\n%s", calls)
+	}
+	if strings.Contains(calls, "delete") {
+		t.Errorf("retained cluster was deleted:\n%s", calls)
+	}
+}
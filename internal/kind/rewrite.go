@@ -0,0 +1,199 @@
+package kind
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// Kubeconfig server rewrites accepted by RewriteKubeconfig.
+const (
+	// RewriteAuto picks one of the others from DetectCallerNetwork.
+	RewriteAuto = "auto"
+	// RewriteHost keeps Kind's 127.0.0.1:<port> server, for callers on the host.
+	RewriteHost = "host"
+	// RewriteInternal uses <cluster>-control-plane:6443, for containers on the kind network.
+	RewriteInternal = "internal"
+	// RewriteDockerNetwork reaches the published port through the host gateway, for
+	// containers on another network of the same engine.
+	RewriteDockerNetwork = "docker-network"
+	// RewriteCustom uses a caller-supplied host[:port].
+	RewriteCustom = "custom"
+)
+
+// CallerNetwork describes where this server, and so the MCP client that launched it
+// over stdio, runs.
+type CallerNetwork struct {
+	InContainer bool `json:"in_container"`
+	// Evidence names what gave the container away, e.g. /.dockerenv.
+	Evidence string `json:"evidence,omitempty"`
+	// ContainerID is the container's hostname, which the runtime accepts as its ID.
+	ContainerID string `json:"container_id,omitempty"`
+	// Networks are the container's runtime networks, when the runtime can inspect it
+	// (e.g. with the host's socket mounted in a devcontainer).
+	Networks      []string `json:"networks,omitempty"`
+	OnKindNetwork bool     `json:"on_kind_network"`
+}
+
+// KubeconfigRewrite is a kubeconfig whose server was chosen for where the caller runs.
+type KubeconfigRewrite struct {
+	RewriteFor    string        `json:"rewrite_for"`
+	Server        string        `json:"server"`
+	TLSServerName string        `json:"tls_server_name,omitempty"`
+	Caller        CallerNetwork `json:"caller"`
+	Notes         []string      `json:"notes,omitempty"`
+	Kubeconfig    string        `json:"-"`
+}
+
+// containerMarkers are files the Docker and Podman runtimes create in containers;
+// overridden in tests.
+var containerMarkers = []string{"/.dockerenv", "/run/.containerenv"}
+
+// DetectCallerNetwork reports whether this process runs in a container and, if the
+// runtime can inspect that container, which networks it is attached to.
+func (m *Manager) DetectCallerNetwork(ctx context.Context) CallerNetwork {
+	var caller CallerNetwork
+	for _, marker := range containerMarkers {
+		if _, err := os.Stat(marker); err == nil {
+			caller.InContainer, caller.Evidence = true, marker
+			break
+		}
+	}
+	if !caller.InContainer {
+		return caller
+	}
+	caller.ContainerID, _ = os.Hostname()
+	if caller.ContainerID == "" {
+		return caller
+	}
+	out, err := m.runner.Run(ctx, m.runtimeBin(), "inspect", "--format",
+		"{{range $k, $v := .NetworkSettings.Networks}}{{$k}},{{end}}", caller.ContainerID)
+	if err != nil {
+		m.logger.Debug("inspecting own container failed", "container", caller.ContainerID, "error", err)
+		return caller
+	}
+	for _, network := range strings.Split(strings.TrimSpace(string(out)), ",") {
+		if network != "" {
+			caller.Networks = append(caller.Networks, network)
+		}
+	}
+	caller.OnKindNetwork = slices.Contains(caller.Networks, kindNetwork)
+	return caller
+}
+
+// RewriteKubeconfig returns a cluster's kubeconfig with the server rewritten for
+// rewriteFor (one of the Rewrite constants; empty means RewriteAuto). address is the
+// host[:port] for RewriteCustom; without a port the cluster's published port is kept.
+func (m *Manager) RewriteKubeconfig(ctx context.Context, clusterName, rewriteFor, address string) (*KubeconfigRewrite, error) {
+	if rewriteFor == "" {
+		rewriteFor = RewriteAuto
+	}
+	switch rewriteFor {
+	case RewriteAuto, RewriteHost, RewriteInternal, RewriteDockerNetwork:
+	case RewriteCustom:
+		if address == "" {
+			return nil, fmt.Errorf("rewrite_for %q needs an address", RewriteCustom)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rewrite_for %q; must be %q, %q, %q, %q, or %q", rewriteFor,
+			RewriteAuto, RewriteHost, RewriteInternal, RewriteDockerNetwork, RewriteCustom)
+	}
+
+	result := &KubeconfigRewrite{Caller: m.DetectCallerNetwork(ctx)}
+	add := func(format string, args ...any) {
+		result.Notes = append(result.Notes, fmt.Sprintf(format, args...))
+	}
+	caller := result.Caller
+	if rewriteFor == RewriteAuto {
+		switch {
+		case !caller.InContainer:
+			rewriteFor = RewriteHost
+		case caller.OnKindNetwork:
+			rewriteFor = RewriteInternal
+		default:
+			rewriteFor = RewriteDockerNetwork
+		}
+		if caller.InContainer {
+			add("This server runs in a container (%s) on networks %v, so rewrite_for=%s was chosen.",
+				caller.Evidence, caller.Networks, rewriteFor)
+		}
+	}
+	result.RewriteFor = rewriteFor
+
+	kubeconfig, err := m.GetKubeconfig(ctx, clusterName, rewriteFor == RewriteInternal)
+	if err != nil {
+		return nil, err
+	}
+	result.Server = kubeconfigServer(kubeconfig)
+	result.Kubeconfig = kubeconfig
+
+	switch rewriteFor {
+	case RewriteHost:
+		if caller.InContainer {
+			add("%s is the host's loopback address and is not reachable from this container; use "+
+				"rewrite_for=%s or %s.", result.Server, RewriteInternal, RewriteDockerNetwork)
+		}
+		return result, nil
+	case RewriteInternal:
+		if caller.InContainer && !caller.OnKindNetwork {
+			id := cmp.Or(caller.ContainerID, "<container>")
+			add("This container is not on the %s network; attach it with '%s network connect %s %s'.",
+				kindNetwork, m.runtimeBin(), kindNetwork, id)
+		}
+		return result, nil
+	}
+
+	u, err := url.Parse(result.Server)
+	if err != nil || u.Port() == "" {
+		return nil, fmt.Errorf("unexpected kubeconfig server %q", result.Server)
+	}
+	host, port := "", u.Port()
+	switch rewriteFor {
+	case RewriteDockerNetwork:
+		host = "host.docker.internal"
+		if m.runtime.Runtime == rtdetect.RuntimePodman {
+			host = "host.containers.internal"
+		}
+		if m.runtime.Backend == rtdetect.BackendNative || m.runtime.Backend == "" {
+			add("On Linux, add '--add-host %s:host-gateway' to the container. Kind binds the API server to "+
+				"127.0.0.1, which the host gateway does not reach: prefer rewrite_for=%s after connecting the "+
+				"container to the %s network, or recreate the cluster with apiServerAddress 0.0.0.0.",
+				host, RewriteInternal, kindNetwork)
+		}
+	case RewriteCustom:
+		host = address
+		if h, p, err := net.SplitHostPort(address); err == nil {
+			host, port = h, p
+		}
+	}
+	result.Server = "https://" + net.JoinHostPort(host, port)
+	// Kind's certificate covers localhost but not the gateway name or custom addresses.
+	result.TLSServerName = "localhost"
+	if result.Kubeconfig, err = patchKubeconfigServer(kubeconfig, result.Server, result.TLSServerName); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// kubeconfigServer returns the server of a kubeconfig's first cluster.
+func kubeconfigServer(kubeconfig string) string {
+	var cfg struct {
+		Clusters []struct {
+			Cluster struct {
+				Server string `yaml:"server"`
+			} `yaml:"cluster"`
+		} `yaml:"clusters"`
+	}
+	if err := yaml.Unmarshal([]byte(kubeconfig), &cfg); err != nil || len(cfg.Clusters) == 0 {
+		return ""
+	}
+	return cfg.Clusters[0].Cluster.Server
+}
@@ -0,0 +1,108 @@
+package kind
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withContainerMarker makes DetectCallerNetwork see (or not see) a container marker.
+func withContainerMarker(t *testing.T, present bool) {
+	t.Helper()
+	marker := filepath.Join(t.TempDir(), ".dockerenv")
+	if present {
+		if err := os.WriteFile(marker, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := containerMarkers
+	containerMarkers = []string{marker}
+	t.Cleanup(func() { containerMarkers = old })
+}
+
+func TestRewriteKubeconfig_AutoOnHost(t *testing.T) {
+	withContainerMarker(t, false)
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte(testKubeconfig)},
+	}}
+	got, err := newDockerManager(runner).RewriteKubeconfig(context.Background(), "lan", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RewriteFor != RewriteHost || got.Server != "https://127.0.0.1:6443" || got.Caller.InContainer {
+		t.Errorf("rewrite = %+v", got)
+	}
+	if got.Kubeconfig != testKubeconfig {
+		t.Errorf("kubeconfig changed:\n%s", got.Kubeconfig)
+	}
+}
+
+func TestRewriteKubeconfig_AutoOnKindNetwork(t *testing.T) {
+	withContainerMarker(t, true)
+	internal := strings.Replace(testKubeconfig, "https://127.0.0.1:6443", "https://lan-control-plane:6443", 1)
+	runner := &mockRunner{runs: []runCall{
+		{name: "docker", args: []string{"inspect"}, out: []byte("bridge,kind,\n")},
+		{name: "kind", args: []string{"get", "kubeconfig", "--name", "lan", "--internal"}, out: []byte(internal)},
+	}}
+	got, err := newDockerManager(runner).RewriteKubeconfig(context.Background(), "lan", RewriteAuto, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RewriteFor != RewriteInternal || got.Server != "https://lan-control-plane:6443" {
+		t.Errorf("rewrite = %+v", got)
+	}
+	if !got.Caller.OnKindNetwork || len(got.Caller.Networks) != 2 {
+		t.Errorf("caller = %+v", got.Caller)
+	}
+}
+
+func TestRewriteKubeconfig_DockerNetworkPodman(t *testing.T) {
+	withContainerMarker(t, true)
+	runner := &mockRunner{runs: []runCall{
+		{name: "podman", args: []string{"inspect"}, out: []byte("devnet,\n")},
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte(testKubeconfig)},
+	}}
+	got, err := newPodmanManager(runner).RewriteKubeconfig(context.Background(), "lan", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RewriteFor != RewriteDockerNetwork || got.Server != "https://host.containers.internal:6443" {
+		t.Errorf("rewrite = %+v", got)
+	}
+	if !strings.Contains(got.Kubeconfig, "server: https://host.containers.internal:6443") ||
+		!strings.Contains(got.Kubeconfig, "tls-server-name: localhost") {
+		t.Errorf("kubeconfig not rewritten:\n%s", got.Kubeconfig)
+	}
+	if !strings.Contains(strings.Join(got.Notes, " "), "--add-host host.containers.internal:host-gateway") {
+		t.Errorf("notes = %v", got.Notes)
+	}
+}
+
+func TestRewriteKubeconfig_Custom(t *testing.T) {
+	withContainerMarker(t, false)
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte(testKubeconfig)},
+	}}
+	m := newDockerManager(runner)
+	for address, want := range map[string]string{
+		"192.168.1.20":      "https://192.168.1.20:6443",
+		"gateway.lan:16443": "https://gateway.lan:16443",
+	} {
+		got, err := m.RewriteKubeconfig(context.Background(), "lan", RewriteCustom, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Server != want || !strings.Contains(got.Kubeconfig, "server: "+want) {
+			t.Errorf("%s: server = %q", address, got.Server)
+		}
+	}
+
+	if _, err := m.RewriteKubeconfig(context.Background(), "lan", RewriteCustom, ""); err == nil {
+		t.Error("expected error for custom without an address")
+	}
+	if _, err := m.RewriteKubeconfig(context.Background(), "lan", "bogus", ""); err == nil {
+		t.Error("expected error for unknown rewrite_for")
+	}
+}
@@ -0,0 +1,282 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Workload kinds accepted by the rollout helpers.
+const (
+	WorkloadDeployment  = "deployment"
+	WorkloadStatefulSet = "statefulset"
+	WorkloadDaemonSet   = "daemonset"
+)
+
+// RolloutTarget identifies a workload for RolloutRestart and RolloutStatus.
+type RolloutTarget struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+func (t RolloutTarget) validate() error {
+	switch t.Kind {
+	case WorkloadDeployment, WorkloadStatefulSet, WorkloadDaemonSet:
+	default:
+		return fmt.Errorf("unsupported kind %q; must be %q, %q, or %q",
+			t.Kind, WorkloadDeployment, WorkloadStatefulSet, WorkloadDaemonSet)
+	}
+	if t.Name == "" {
+		return fmt.Errorf("workload name is required")
+	}
+	return nil
+}
+
+func (t RolloutTarget) ref() string {
+	return t.Kind + "/" + t.Name
+}
+
+// RolloutState reports the progress of a workload rollout.
+type RolloutState struct {
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	// Complete is true when every replica runs the current revision and is available.
+	Complete bool `json:"complete"`
+	TimedOut bool `json:"timed_out"`
+	// Message is the last status line, as "kubectl rollout status" prints it, e.g.
+	// "deployment "web" successfully rolled out".
+	Message string `json:"message"`
+	// Revision is the Deployment revision or the StatefulSet update revision.
+	Revision  string `json:"revision,omitempty"`
+	Desired   int    `json:"desired"`
+	Updated   int    `json:"updated"`
+	Ready     int    `json:"ready"`
+	Available int    `json:"available"`
+}
+
+// restartedAtAnnotation is the pod template annotation "kubectl rollout restart" sets.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// workloadResources are the apps/v1 resources of the workload kinds.
+var workloadResources = map[string]schema.GroupVersionResource{
+	WorkloadDeployment:  appsv1.SchemeGroupVersion.WithResource("deployments"),
+	WorkloadStatefulSet: appsv1.SchemeGroupVersion.WithResource("statefulsets"),
+	WorkloadDaemonSet:   appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+}
+
+// workload returns the client for the target's resource type and namespace.
+func (c *clusterClients) workload(target RolloutTarget) dynamic.ResourceInterface {
+	namespace := target.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	return c.dynamic.Resource(workloadResources[target.Kind]).Namespace(namespace)
+}
+
+// RolloutRestart triggers a rolling restart of a workload through client-go, as
+// "kubectl rollout restart" does, by stamping its pod template with a restart
+// annotation.
+func (m *Manager) RolloutRestart(ctx context.Context, clusterName string, target RolloutTarget) error {
+	if err := target.validate(); err != nil {
+		return err
+	}
+	clients, err := m.clusterClients(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	m.logger.Info("restarting workload", "cluster", clusterName, "resource", target.ref(), "namespace", target.Namespace)
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	if _, err := clients.workload(target).Patch(ctx, target.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("restarting %s: %w", target.ref(), err)
+	}
+	return nil
+}
+
+// RolloutStatus watches a workload with client-go for up to timeout until its rollout
+// finishes, judged as "kubectl rollout status" does, and reports its replica counts
+// and revision. A rollout still in progress at the timeout is reported with TimedOut
+// set, not as an error.
+func (m *Manager) RolloutStatus(ctx context.Context, clusterName string, target RolloutTarget, timeout time.Duration) (*RolloutState, error) {
+	if err := target.validate(); err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+	clients, err := m.clusterClients(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &RolloutState{Resource: target.ref(), Namespace: target.Namespace}
+	if state.Namespace == "" {
+		state.Namespace = "default"
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	listed := false
+	objects, err := watchObjects(waitCtx, clients.workload(target), target.Name, "", func(objects objectSet) (bool, error) {
+		first := !listed
+		listed = true
+		obj := objects[target.Name]
+		if obj == nil {
+			if first {
+				return false, fmt.Errorf("not found")
+			}
+			return false, nil
+		}
+		message, done, err := rolloutProgress(target.Kind, obj)
+		state.Message = message
+		return done, err
+	})
+	switch {
+	case err == nil:
+		state.Complete = true
+	case ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded):
+		state.TimedOut = true
+	default:
+		return nil, fmt.Errorf("rollout status of %s: %w", target.ref(), err)
+	}
+	if obj := objects[target.Name]; obj != nil {
+		if err := fillRolloutCounts(state, target.Kind, obj); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// rolloutProgress reports whether a workload's rollout is done, with the status line
+// "kubectl rollout status" prints for it.
+func rolloutProgress(kind string, obj *unstructured.Unstructured) (string, bool, error) {
+	name := obj.GetName()
+	switch kind {
+	case WorkloadDeployment:
+		var d appsv1.Deployment
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &d); err != nil {
+			return "", false, fmt.Errorf("parsing deployment %q: %w", name, err)
+		}
+		if d.Generation > d.Status.ObservedGeneration {
+			return "Waiting for deployment spec update to be observed...", false, nil
+		}
+		for _, c := range d.Status.Conditions {
+			if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+				return "", false, fmt.Errorf("deployment %q exceeded its progress deadline", name)
+			}
+		}
+		st := d.Status
+		switch {
+		case d.Spec.Replicas != nil && st.UpdatedReplicas < *d.Spec.Replicas:
+			return fmt.Sprintf("Waiting for deployment %q rollout to finish: %d out of %d new replicas have been updated...",
+				name, st.UpdatedReplicas, *d.Spec.Replicas), false, nil
+		case st.Replicas > st.UpdatedReplicas:
+			return fmt.Sprintf("Waiting for deployment %q rollout to finish: %d old replicas are pending termination...",
+				name, st.Replicas-st.UpdatedReplicas), false, nil
+		case st.AvailableReplicas < st.UpdatedReplicas:
+			return fmt.Sprintf("Waiting for deployment %q rollout to finish: %d of %d updated replicas are available...",
+				name, st.AvailableReplicas, st.UpdatedReplicas), false, nil
+		}
+		return fmt.Sprintf("deployment %q successfully rolled out", name), true, nil
+
+	case WorkloadStatefulSet:
+		var ss appsv1.StatefulSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &ss); err != nil {
+			return "", false, fmt.Errorf("parsing statefulset %q: %w", name, err)
+		}
+		if ss.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			return "", false, fmt.Errorf("rollout status is only available for the RollingUpdate strategy type")
+		}
+		st := ss.Status
+		if st.ObservedGeneration == 0 || ss.Generation > st.ObservedGeneration {
+			return "Waiting for statefulset spec update to be observed...", false, nil
+		}
+		if ss.Spec.Replicas != nil && st.ReadyReplicas < *ss.Spec.Replicas {
+			return fmt.Sprintf("Waiting for %d pods to be ready...", *ss.Spec.Replicas-st.ReadyReplicas), false, nil
+		}
+		if ru := ss.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil && ss.Spec.Replicas != nil {
+			if want := *ss.Spec.Replicas - *ru.Partition; st.UpdatedReplicas < want {
+				return fmt.Sprintf("Waiting for partitioned roll out to finish: %d out of %d new pods have been updated...",
+					st.UpdatedReplicas, want), false, nil
+			}
+			return fmt.Sprintf("partitioned roll out complete: %d new pods have been updated...", st.UpdatedReplicas), true, nil
+		}
+		if st.UpdateRevision != st.CurrentRevision {
+			return fmt.Sprintf("waiting for statefulset rolling update to complete %d pods at revision %s...",
+				st.UpdatedReplicas, st.UpdateRevision), false, nil
+		}
+		return fmt.Sprintf("statefulset rolling update complete %d pods at revision %s...",
+			st.CurrentReplicas, st.CurrentRevision), true, nil
+
+	default:
+		var ds appsv1.DaemonSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &ds); err != nil {
+			return "", false, fmt.Errorf("parsing daemonset %q: %w", name, err)
+		}
+		if ds.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+			return "", false, fmt.Errorf("rollout status is only available for the RollingUpdate strategy type")
+		}
+		st := ds.Status
+		switch {
+		case ds.Generation > st.ObservedGeneration:
+			return "Waiting for daemon set spec update to be observed...", false, nil
+		case st.UpdatedNumberScheduled < st.DesiredNumberScheduled:
+			return fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d out of %d new pods have been updated...",
+				name, st.UpdatedNumberScheduled, st.DesiredNumberScheduled), false, nil
+		case st.NumberAvailable < st.DesiredNumberScheduled:
+			return fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d of %d updated pods are available...",
+				name, st.NumberAvailable, st.DesiredNumberScheduled), false, nil
+		}
+		return fmt.Sprintf("daemon set %q successfully rolled out", name), true, nil
+	}
+}
+
+// fillRolloutCounts reads the replica counts and revision from a workload object.
+func fillRolloutCounts(state *RolloutState, kind string, obj *unstructured.Unstructured) error {
+	switch kind {
+	case WorkloadDaemonSet:
+		var ds appsv1.DaemonSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &ds); err != nil {
+			return fmt.Errorf("parsing %s: %w", state.Resource, err)
+		}
+		st := ds.Status
+		state.Desired, state.Updated = int(st.DesiredNumberScheduled), int(st.UpdatedNumberScheduled)
+		state.Ready, state.Available = int(st.NumberReady), int(st.NumberAvailable)
+	case WorkloadStatefulSet:
+		var ss appsv1.StatefulSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &ss); err != nil {
+			return fmt.Errorf("parsing %s: %w", state.Resource, err)
+		}
+		st := ss.Status
+		state.Desired = replicasOrOne(ss.Spec.Replicas)
+		state.Updated, state.Ready, state.Available = int(st.UpdatedReplicas), int(st.ReadyReplicas), int(st.AvailableReplicas)
+		state.Revision = st.UpdateRevision
+	default:
+		var d appsv1.Deployment
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &d); err != nil {
+			return fmt.Errorf("parsing %s: %w", state.Resource, err)
+		}
+		st := d.Status
+		state.Desired = replicasOrOne(d.Spec.Replicas)
+		state.Updated, state.Ready, state.Available = int(st.UpdatedReplicas), int(st.ReadyReplicas), int(st.AvailableReplicas)
+		state.Revision = d.Annotations["deployment.kubernetes.io/revision"]
+	}
+	return nil
+}
+
+// replicasOrOne returns a workload's replica count, which defaults to 1 when unset.
+func replicasOrOne(replicas *int32) int {
+	if replicas == nil {
+		return 1
+	}
+	return int(*replicas)
+}
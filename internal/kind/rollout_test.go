@@ -0,0 +1,92 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func deployment(available int64) map[string]any {
+	return map[string]any{
+		"metadata": map[string]any{"generation": int64(2),
+			"annotations": map[string]any{"deployment.kubernetes.io/revision": "3"}},
+		"spec": map[string]any{"replicas": int64(2)},
+		"status": map[string]any{"observedGeneration": int64(2), "replicas": int64(2), "updatedReplicas": int64(2),
+			"readyReplicas": available, "availableReplicas": available},
+	}
+}
+
+func TestRolloutStatus_Deployment(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	client, later := fakeClusters(t, m, object("apps/v1", "Deployment", "shop", "web", deployment(1)))
+	later(func() {
+		update(t, client, deploymentsResource, object("apps/v1", "Deployment", "shop", "web", deployment(2)))
+	})
+
+	state, err := m.RolloutStatus(context.Background(), "dev",
+		RolloutTarget{Kind: WorkloadDeployment, Name: "web", Namespace: "shop"}, 30*time.Second)
+	if err != nil {
+		t.Fatalf("RolloutStatus: %v", err)
+	}
+	if !state.Complete || state.TimedOut || state.Revision != "3" || state.Desired != 2 || state.Available != 2 {
+		t.Errorf("state = %+v", state)
+	}
+	if !strings.Contains(state.Message, "successfully rolled out") {
+		t.Errorf("message = %q", state.Message)
+	}
+}
+
+func TestRolloutStatus_TimedOutDaemonSet(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	fakeClusters(t, m, object("apps/v1", "DaemonSet", "default", "agent", map[string]any{
+		"status": map[string]any{"desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(1),
+			"numberReady": int64(2), "numberAvailable": int64(2)}}))
+
+	state, err := m.RolloutStatus(context.Background(), "dev",
+		RolloutTarget{Kind: WorkloadDaemonSet, Name: "agent"}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("RolloutStatus: %v", err)
+	}
+	if state.Complete || !state.TimedOut || state.Desired != 3 || state.Updated != 1 || state.Namespace != "default" ||
+		!strings.Contains(state.Message, "1 out of 3 new pods have been updated") {
+		t.Errorf("state = %+v", state)
+	}
+}
+
+func TestRolloutStatus_ProgressDeadline(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	obj := deployment(0)
+	obj["status"].(map[string]any)["conditions"] = []any{
+		map[string]any{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"}}
+	fakeClusters(t, m, object("apps/v1", "Deployment", "default", "web", obj))
+
+	_, err := m.RolloutStatus(context.Background(), "dev", RolloutTarget{Kind: WorkloadDeployment, Name: "web"}, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "progress deadline") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRolloutRestart(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	client, _ := fakeClusters(t, m, object("apps/v1", "Deployment", "shop", "web", deployment(2)))
+	if err := m.RolloutRestart(context.Background(), "dev", RolloutTarget{Kind: WorkloadDeployment, Name: "web", Namespace: "shop"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := client.Resource(deploymentsResource).Namespace("shop").Get(context.Background(), "web", metav1.GetOptions{})
+	annotations, _, _ := unstructured.NestedStringMap(got.Object, "spec", "template", "metadata", "annotations")
+	if _, err := time.Parse(time.RFC3339, annotations[restartedAtAnnotation]); err != nil {
+		t.Errorf("annotations = %v", annotations)
+	}
+}
+
+func TestRolloutRestart_InvalidKind(t *testing.T) {
+	err := newDockerManager(&mockRunner{}).RolloutRestart(context.Background(), "dev",
+		RolloutTarget{Kind: "job", Name: "migrate"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported kind") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package kind
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// podPollInterval is the delay between pod phase checks in RunPod; overridden in tests.
+var podPollInterval = 2 * time.Second
+
+// RunPodOptions describes a one-off pod for RunPod.
+type RunPodOptions struct {
+	Image   string
+	Command []string
+	Env     map[string]string
+	// Namespace defaults to "default".
+	Namespace string
+	// Timeout bounds the whole run, including the image pull. Default: 5 minutes.
+	Timeout time.Duration
+	// Keep leaves the pod in place after it finishes, for further inspection.
+	Keep bool
+}
+
+// PodRunResult is the outcome of RunPod.
+type PodRunResult struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	// Phase is the last observed pod phase (Succeeded, Failed, Pending, Running).
+	Phase    string `json:"phase"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+	TimedOut bool   `json:"timed_out"`
+	// Reason explains a pod that never ran or was terminated, e.g. ImagePullBackOff or OOMKilled.
+	Reason string `json:"reason,omitempty"`
+	Logs   string `json:"logs"`
+	// Deleted is false when the pod was kept or its deletion failed.
+	Deleted bool `json:"deleted"`
+}
+
+// RunPod creates a pod that runs once (restartPolicy Never), waits for it to finish or
+// for the timeout, and returns its logs and exit status. The pod is deleted afterwards
+// unless opts.Keep is set.
+func (m *Manager) RunPod(ctx context.Context, clusterName string, opts RunPodOptions) (*PodRunResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if opts.Image == "" {
+		return nil, fmt.Errorf("image is required")
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	result := &PodRunResult{Pod: "mcp-run-" + hex.EncodeToString(suffix), Namespace: opts.Namespace}
+
+	manifest, err := json.Marshal(runPodManifest(result.Pod, opts))
+	if err != nil {
+		return nil, fmt.Errorf("encoding pod: %w", err)
+	}
+	if _, err := m.ApplyManifest(ctx, clusterName, string(manifest)); err != nil {
+		return nil, fmt.Errorf("creating pod: %w", err)
+	}
+	defer func() {
+		if opts.Keep {
+			return
+		}
+		// Use a fresh context so the pod is removed even after a cancellation.
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := m.Kubectl(cleanupCtx, clusterName, "delete", "pod", result.Pod,
+			"-n", opts.Namespace, "--wait=false"); err != nil {
+			m.logger.Warn("deleting pod failed", "pod", result.Pod, "error", err)
+			return
+		}
+		result.Deleted = true
+	}()
+
+	deadline := time.Now().Add(opts.Timeout)
+	for {
+		if err := m.podStatus(ctx, clusterName, result); err != nil {
+			return result, err
+		}
+		if result.Phase == "Succeeded" || result.Phase == "Failed" {
+			break
+		}
+		if time.Now().After(deadline) {
+			result.TimedOut = true
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(podPollInterval):
+		}
+	}
+
+	logs, err := m.RunKubectl(ctx, clusterName, opts.Namespace, []string{"logs", result.Pod})
+	if err != nil {
+		return result, err
+	}
+	result.Logs = logs.Stdout
+	if logs.ExitCode != 0 && result.Reason == "" {
+		result.Reason = strings.TrimSpace(logs.Stderr)
+	}
+	return result, nil
+}
+
+// podStatus refreshes the phase, exit code and reason of a RunPod pod.
+func (m *Manager) podStatus(ctx context.Context, clusterName string, result *PodRunResult) error {
+	res, err := m.RunKubectl(ctx, clusterName, result.Namespace, []string{"get", "pod", result.Pod, "-o", "json"})
+	if err != nil {
+		return err
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("getting pod %s: %s", result.Pod, strings.TrimSpace(res.Stderr))
+	}
+	var pod struct {
+		Status struct {
+			Phase             string `json:"phase"`
+			ContainerStatuses []struct {
+				State struct {
+					Waiting *struct {
+						Reason  string `json:"reason"`
+						Message string `json:"message"`
+					} `json:"waiting"`
+					Terminated *struct {
+						ExitCode int    `json:"exitCode"`
+						Reason   string `json:"reason"`
+					} `json:"terminated"`
+				} `json:"state"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &pod); err != nil {
+		return fmt.Errorf("parsing pod %s: %w", result.Pod, err)
+	}
+
+	result.Phase = pod.Status.Phase
+	for _, cs := range pod.Status.ContainerStatuses {
+		switch {
+		case cs.State.Terminated != nil:
+			code := cs.State.Terminated.ExitCode
+			result.ExitCode = &code
+			if cs.State.Terminated.Reason != "Completed" {
+				result.Reason = cs.State.Terminated.Reason
+			}
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "ContainerCreating":
+			result.Reason = strings.TrimSpace(cs.State.Waiting.Reason + ": " + cs.State.Waiting.Message)
+		}
+	}
+	return nil
+}
+
+// runPodManifest returns the pod object for RunPod. Env vars are sorted so the
+// manifest is deterministic.
+func runPodManifest(name string, opts RunPodOptions) map[string]any {
+	container := map[string]any{
+		"name":  "run",
+		"image": opts.Image,
+	}
+	if len(opts.Command) > 0 {
+		container["command"] = opts.Command
+	}
+	if len(opts.Env) > 0 {
+		keys := make([]string, 0, len(opts.Env))
+		for k := range opts.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		env := make([]map[string]string, 0, len(keys))
+		for _, k := range keys {
+			env = append(env, map[string]string{"name": k, "value": opts.Env[k]})
+		}
+		container["env"] = env
+	}
+
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": opts.Namespace,
+			"labels":    map[string]string{"app.kubernetes.io/managed-by": "mcp-kind-manager"},
+		},
+		"spec": map[string]any{
+			"restartPolicy":                 "Never",
+			"terminationGracePeriodSeconds": 1,
+			"containers":                    []any{container},
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// podRunner serves successive pod states for "kubectl get pod" and records manifests.
+type podRunner struct {
+	*loggingRunner
+	states []string
+}
+
+func (p *podRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if name == "kubectl" && matchArgs([]string{"--kubeconfig", "*", "--namespace", "*", "get", "pod"}, args) {
+		state := p.states[0]
+		if len(p.states) > 1 {
+			p.states = p.states[1:]
+		}
+		return []byte(state), nil, nil
+	}
+	return p.mockRunner.RunSeparate(ctx, name, args...)
+}
+
+func newPodRunner(states ...string) *podRunner {
+	return &podRunner{
+		loggingRunner: &loggingRunner{mockRunner: &mockRunner{runs: []runCall{
+			{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte("apiVersion: v1\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "apply"}, out: []byte("pod/x created\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "--namespace", "tools", "logs"}, out: []byte("Address: 10.96.0.10\n")},
+			{name: "kubectl", args: []string{"--kubeconfig", "*", "delete", "pod"}, out: []byte("deleted\n")},
+		}}},
+		states: states,
+	}
+}
+
+func TestRunPod(t *testing.T) {
+	podPollInterval = time.Millisecond
+	runner := newPodRunner(
+		`{"status":{"phase":"Pending","containerStatuses":[{"state":{"waiting":{"reason":"ContainerCreating"}}}]}}`,
+		`{"status":{"phase":"Succeeded","containerStatuses":[{"state":{"terminated":{"exitCode":0,"reason":"Completed"}}}]}}`,
+	)
+
+	result, err := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil).RunPod(context.Background(), "dev", RunPodOptions{
+		Image:     "busybox:1.36",
+		Command:   []string{"nslookup", "kubernetes.default"},
+		Env:       map[string]string{"B": "2", "A": "1"},
+		Namespace: "tools",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Phase != "Succeeded" || result.ExitCode == nil || *result.ExitCode != 0 || result.Reason != "" {
+		t.Errorf("result = %+v", result)
+	}
+	if !strings.Contains(result.Logs, "10.96.0.10") {
+		t.Errorf("logs = %q", result.Logs)
+	}
+	if !result.Deleted || !strings.Contains(strings.Join(runner.calls, "\n"), "delete pod "+result.Pod+" -n tools") {
+		t.Errorf("pod should be deleted, calls = %v", runner.calls)
+	}
+}
+
+func TestRunPod_TimeoutKeepsReason(t *testing.T) {
+	podPollInterval = time.Millisecond
+	runner := newPodRunner(
+		`{"status":{"phase":"Pending","containerStatuses":[{"state":{"waiting":{"reason":"ImagePullBackOff","message":"Back-off pulling image"}}}]}}`,
+	)
+
+	result, err := NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil).RunPod(context.Background(), "dev", RunPodOptions{
+		Image: "missing:latest", Namespace: "tools", Timeout: 5 * time.Millisecond, Keep: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.TimedOut || result.Phase != "Pending" || !strings.HasPrefix(result.Reason, "ImagePullBackOff") {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Deleted {
+		t.Error("kept pod should not be deleted")
+	}
+}
+
+func TestRunPodManifest(t *testing.T) {
+	m := runPodManifest("mcp-run-1", RunPodOptions{Image: "alpine", Namespace: "default",
+		Env: map[string]string{"Z": "z", "A": "a"}})
+	spec := m["spec"].(map[string]any)
+	if spec["restartPolicy"] != "Never" {
+		t.Errorf("restartPolicy = %v", spec["restartPolicy"])
+	}
+	c := spec["containers"].([]any)[0].(map[string]any)
+	env := c["env"].([]map[string]string)
+	if env[0]["name"] != "A" || env[1]["name"] != "Z" {
+		t.Errorf("env not sorted: %v", env)
+	}
+	if _, ok := c["command"]; ok {
+		t.Error("no command should use the image entrypoint")
+	}
+}
@@ -0,0 +1,194 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyRule is an RBAC rule for a Role created by CreateScopedKubeconfig.
+type PolicyRule struct {
+	APIGroups     []string `json:"apiGroups"`
+	Resources     []string `json:"resources"`
+	Verbs         []string `json:"verbs"`
+	ResourceNames []string `json:"resourceNames,omitempty"`
+}
+
+// ScopedAccessOptions describes the ServiceAccount and permissions for CreateScopedKubeconfig.
+type ScopedAccessOptions struct {
+	ServiceAccount string
+	// Namespace holds the ServiceAccount and, unless ClusterWide, scopes its permissions.
+	// Default: "default".
+	Namespace string
+	// Role is an existing ClusterRole to bind, e.g. "view" or "edit". Ignored when Rules is set.
+	// Default: "view".
+	Role string
+	// Rules, when set, creates a Role (or ClusterRole if ClusterWide) named after the
+	// ServiceAccount with these rules and binds it instead of Role.
+	Rules []PolicyRule
+	// ClusterWide binds with a ClusterRoleBinding instead of a RoleBinding in Namespace.
+	ClusterWide bool
+	// Duration is the token lifetime. Default: 24 hours.
+	Duration time.Duration
+}
+
+// ScopedKubeconfig is the result of CreateScopedKubeconfig.
+type ScopedKubeconfig struct {
+	ServiceAccount string `json:"service_account"`
+	Namespace      string `json:"namespace"`
+	// Role is the bound role as kind/name, e.g. ClusterRole/view.
+	Role    string `json:"role"`
+	Binding string `json:"binding"`
+	// ExpiresAt is when the token stops being accepted by the API server.
+	ExpiresAt      time.Time `json:"expires_at"`
+	Kubeconfig     string    `json:"kubeconfig,omitempty"`
+	KubeconfigPath string    `json:"kubeconfig_path,omitempty"`
+}
+
+// CreateScopedKubeconfig creates (or updates) a ServiceAccount bound to a role and
+// returns a kubeconfig that authenticates with a time-bound token for it, so that
+// agents or CI steps get less than the cluster-admin access of Kind's kubeconfig.
+func (m *Manager) CreateScopedKubeconfig(ctx context.Context, clusterName string, opts ScopedAccessOptions) (*ScopedKubeconfig, error) {
+	if opts.ServiceAccount == "" {
+		return nil, fmt.Errorf("service account name is required")
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+	if opts.Role == "" {
+		opts.Role = "view"
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = 24 * time.Hour
+	}
+	if opts.Duration < 10*time.Minute {
+		return nil, fmt.Errorf("token duration must be at least 10 minutes")
+	}
+	for i, rule := range opts.Rules {
+		if len(rule.Resources) == 0 || len(rule.Verbs) == 0 {
+			return nil, fmt.Errorf("rule %d needs both resources and verbs", i)
+		}
+	}
+
+	objects, result := scopedAccessObjects(opts)
+	manifest, err := json.Marshal(map[string]any{"apiVersion": "v1", "kind": "List", "items": objects})
+	if err != nil {
+		return nil, fmt.Errorf("encoding RBAC objects: %w", err)
+	}
+	m.logger.Info("creating scoped service account", "cluster", clusterName,
+		"service_account", opts.ServiceAccount, "namespace", opts.Namespace, "role", result.Role)
+	if _, err := m.ApplyManifest(ctx, clusterName, string(manifest)); err != nil {
+		return nil, fmt.Errorf("creating RBAC objects: %w", err)
+	}
+
+	res, err := m.RunKubectl(ctx, clusterName, opts.Namespace, []string{
+		"create", "token", opts.ServiceAccount, fmt.Sprintf("--duration=%s", opts.Duration),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.ExitCode != 0 {
+		return nil, fmt.Errorf("creating token: %s", strings.TrimSpace(res.Stderr))
+	}
+	result.ExpiresAt = time.Now().Add(opts.Duration).UTC().Truncate(time.Second)
+
+	admin, err := m.GetKubeconfig(ctx, clusterName, false)
+	if err != nil {
+		return nil, err
+	}
+	result.Kubeconfig, err = tokenKubeconfig(admin, opts.ServiceAccount, opts.Namespace, strings.TrimSpace(res.Stdout))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scopedAccessObjects returns the ServiceAccount, optional role and binding for opts.
+func scopedAccessObjects(opts ScopedAccessOptions) ([]any, *ScopedKubeconfig) {
+	labels := map[string]string{"app.kubernetes.io/managed-by": "mcp-kind-manager"}
+	meta := func(name string, namespaced bool) map[string]any {
+		md := map[string]any{"name": name, "labels": labels}
+		if namespaced {
+			md["namespace"] = opts.Namespace
+		}
+		return md
+	}
+	rbac := "rbac.authorization.k8s.io/v1"
+
+	objects := []any{map[string]any{
+		"apiVersion": "v1", "kind": "ServiceAccount", "metadata": meta(opts.ServiceAccount, true),
+	}}
+
+	roleKind, roleName := "ClusterRole", opts.Role
+	if len(opts.Rules) > 0 {
+		roleName = opts.ServiceAccount
+		if !opts.ClusterWide {
+			roleKind = "Role"
+		}
+		objects = append(objects, map[string]any{
+			"apiVersion": rbac, "kind": roleKind, "metadata": meta(roleName, !opts.ClusterWide), "rules": opts.Rules,
+		})
+	}
+
+	bindingKind := "RoleBinding"
+	if opts.ClusterWide {
+		bindingKind = "ClusterRoleBinding"
+	}
+	// ClusterRoleBindings are cluster-scoped, so include the namespace to keep names unique.
+	bindingName := opts.ServiceAccount
+	if opts.ClusterWide {
+		bindingName = opts.Namespace + "-" + opts.ServiceAccount
+	}
+	objects = append(objects, map[string]any{
+		"apiVersion": rbac, "kind": bindingKind, "metadata": meta(bindingName, !opts.ClusterWide),
+		"roleRef": map[string]string{"apiGroup": "rbac.authorization.k8s.io", "kind": roleKind, "name": roleName},
+		"subjects": []map[string]string{{
+			"kind": "ServiceAccount", "name": opts.ServiceAccount, "namespace": opts.Namespace,
+		}},
+	})
+
+	return objects, &ScopedKubeconfig{
+		ServiceAccount: opts.ServiceAccount,
+		Namespace:      opts.Namespace,
+		Role:           roleKind + "/" + roleName,
+		Binding:        bindingKind + "/" + bindingName,
+	}
+}
+
+// tokenKubeconfig builds a kubeconfig for the first cluster in admin that authenticates
+// with a bearer token and defaults to namespace.
+func tokenKubeconfig(admin, serviceAccount, namespace, token string) (string, error) {
+	var cfg struct {
+		Clusters []struct {
+			Name    string         `yaml:"name"`
+			Cluster map[string]any `yaml:"cluster"`
+		} `yaml:"clusters"`
+	}
+	if err := yaml.Unmarshal([]byte(admin), &cfg); err != nil {
+		return "", fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	if len(cfg.Clusters) == 0 {
+		return "", fmt.Errorf("kubeconfig has no clusters")
+	}
+	cluster := cfg.Clusters[0]
+	user := serviceAccount + "@" + cluster.Name
+
+	out, err := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Config",
+		"clusters":   []any{map[string]any{"name": cluster.Name, "cluster": cluster.Cluster}},
+		"users":      []any{map[string]any{"name": user, "user": map[string]string{"token": token}}},
+		"contexts": []any{map[string]any{"name": user, "context": map[string]string{
+			"cluster": cluster.Name, "user": user, "namespace": namespace,
+		}}},
+		"current-context": user,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding kubeconfig: %w", err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,114 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const scopedAdminKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: Q0E=
+    server: https://127.0.0.1:41234
+  name: kind-dev
+users:
+- name: kind-dev
+  user:
+    client-key-data: S0VZ
+contexts:
+- context:
+    cluster: kind-dev
+    user: kind-dev
+  name: kind-dev
+current-context: kind-dev
+`
+
+func TestCreateScopedKubeconfig(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "kubeconfig"}, out: []byte(scopedAdminKubeconfig)},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "apply"}, out: []byte("serviceaccount/ci created\n")},
+		{name: "kubectl", args: []string{"--kubeconfig", "*", "--namespace", "apps", "create", "token", "ci", "--duration=1h0m0s"},
+			out: []byte("eyJhbGciOi.token\n")},
+	}}
+
+	result, err := newDockerManager(runner).CreateScopedKubeconfig(context.Background(), "dev", ScopedAccessOptions{
+		ServiceAccount: "ci", Namespace: "apps", Role: "edit", Duration: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Role != "ClusterRole/edit" || result.Binding != "RoleBinding/ci" {
+		t.Errorf("role = %q, binding = %q", result.Role, result.Binding)
+	}
+	if strings.Contains(result.Kubeconfig, "client-key-data") {
+		t.Error("scoped kubeconfig must not carry the admin credentials")
+	}
+
+	var cfg struct {
+		Clusters []struct {
+			Cluster map[string]string `yaml:"cluster"`
+		} `yaml:"clusters"`
+		Users []struct {
+			User map[string]string `yaml:"user"`
+		} `yaml:"users"`
+		Contexts []struct {
+			Context map[string]string `yaml:"context"`
+		} `yaml:"contexts"`
+		CurrentContext string `yaml:"current-context"`
+	}
+	if err := yaml.Unmarshal([]byte(result.Kubeconfig), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Clusters[0].Cluster["server"] != "https://127.0.0.1:41234" || cfg.Clusters[0].Cluster["certificate-authority-data"] != "Q0E=" {
+		t.Errorf("cluster = %v", cfg.Clusters[0].Cluster)
+	}
+	if cfg.Users[0].User["token"] != "eyJhbGciOi.token" {
+		t.Errorf("user = %v", cfg.Users[0].User)
+	}
+	if cfg.Contexts[0].Context["namespace"] != "apps" || cfg.CurrentContext != "ci@kind-dev" {
+		t.Errorf("context = %v, current = %q", cfg.Contexts[0].Context, cfg.CurrentContext)
+	}
+}
+
+func TestScopedAccessObjects_Rules(t *testing.T) {
+	objects, result := scopedAccessObjects(ScopedAccessOptions{
+		ServiceAccount: "reader", Namespace: "ci", ClusterWide: true,
+		Rules: []PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}}},
+	})
+	if len(objects) != 3 {
+		t.Fatalf("expected ServiceAccount, ClusterRole and binding, got %d objects", len(objects))
+	}
+	if result.Role != "ClusterRole/reader" || result.Binding != "ClusterRoleBinding/ci-reader" {
+		t.Errorf("role = %q, binding = %q", result.Role, result.Binding)
+	}
+	data, _ := json.Marshal(objects[2])
+	var binding struct {
+		Metadata map[string]any      `json:"metadata"`
+		Subjects []map[string]string `json:"subjects"`
+	}
+	if err := json.Unmarshal(data, &binding); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := binding.Metadata["namespace"]; ok || binding.Subjects[0]["namespace"] != "ci" {
+		t.Errorf("cluster role binding should be cluster-scoped with a namespaced subject: %s", data)
+	}
+}
+
+func TestCreateScopedKubeconfig_Validation(t *testing.T) {
+	mgr := newDockerManager(&mockRunner{})
+	for name, opts := range map[string]ScopedAccessOptions{
+		"no service account": {},
+		"short duration":     {ServiceAccount: "ci", Duration: time.Minute},
+		"rule without verbs": {ServiceAccount: "ci", Rules: []PolicyRule{{Resources: []string{"pods"}}}},
+	} {
+		if _, err := mgr.CreateScopedKubeconfig(context.Background(), "dev", opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package kind
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// Rough resource needs of Kind nodes at idle, plus headroom left for workloads and the
+// engine itself. They are deliberately conservative: a node that runs out of memory
+// shows up as a hung create or NotReady node rather than a clear error.
+const (
+	controlPlaneMilliCPU = 1000
+	controlPlaneMemory   = 1536 << 20
+	workerMilliCPU       = 500
+	workerMemory         = 1 << 30
+	headroomMilliCPU     = 1000
+	headroomMemory       = 1 << 30
+
+	// Engines below either limit are constrained: a single node with kubelet
+	// reservations is the only layout that stays responsive.
+	constrainedCPUs   = 4
+	constrainedMemory = 6 << 30
+)
+
+// Sources of the CPU and memory figures in a SizeRecommendation.
+const (
+	SizeSourceEngine  = "engine"
+	SizeSourceHost    = "host"
+	SizeSourceUnknown = "unknown"
+)
+
+// SizeRecommendation is how many Kind nodes the container engine's CPUs and memory
+// can run, for a requested layout.
+type SizeRecommendation struct {
+	CPUs        int    `json:"cpus,omitempty"`
+	MemoryBytes int64  `json:"memory_bytes,omitempty"`
+	Source      string `json:"source"`
+	// MaxWorkers is the most workers that fit next to the requested control planes.
+	MaxWorkers             int `json:"max_workers"`
+	RequestedWorkers       int `json:"requested_workers"`
+	RecommendedWorkers     int `json:"recommended_workers"`
+	RequestedControlPlanes int `json:"requested_control_planes"`
+	// RecommendedControlPlanes is 1 on constrained engines, else the request.
+	RecommendedControlPlanes int  `json:"recommended_control_planes"`
+	Constrained              bool `json:"constrained"`
+	// ReserveResources suggests the kubelet reservations of ConfigOptions.ReserveKubeletResources.
+	ReserveResources bool     `json:"reserve_resources"`
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// memInfoPath is read for host memory when the engine does not report it; overridden in tests.
+var memInfoPath = "/proc/meminfo"
+
+// RecommendClusterSize sizes a cluster of controlPlanes control planes and workers
+// workers for the engine ri describes, falling back to this host's CPUs and memory
+// when the engine reported none.
+func RecommendClusterSize(ri rtdetect.RuntimeInfo, controlPlanes, workers int) SizeRecommendation {
+	if controlPlanes < 1 {
+		controlPlanes = 1
+	}
+	rec := SizeRecommendation{
+		CPUs:                     ri.CPUs,
+		MemoryBytes:              ri.MemoryBytes,
+		Source:                   SizeSourceEngine,
+		RequestedWorkers:         workers,
+		RecommendedWorkers:       workers,
+		RequestedControlPlanes:   controlPlanes,
+		RecommendedControlPlanes: controlPlanes,
+		MaxWorkers:               workers,
+	}
+	if rec.CPUs == 0 || rec.MemoryBytes == 0 {
+		rec.CPUs, rec.MemoryBytes, rec.Source = goruntime.NumCPU(), hostMemory(), SizeSourceHost
+		if ri.Backend != rtdetect.BackendNative && ri.Backend != "" {
+			rec.Warnings = append(rec.Warnings, fmt.Sprintf("The %s engine did not report its CPUs and memory; "+
+				"sized from this host's, but its VM may have less.", ri.Backend))
+		}
+	}
+	if rec.MemoryBytes == 0 {
+		rec.Source = SizeSourceUnknown
+		rec.Warnings = append(rec.Warnings, "Could not determine available memory; no sizing advice.")
+		return rec
+	}
+
+	milliCPU := int64(rec.CPUs) * 1000
+	fit := func(cp int) int {
+		byCPU := (milliCPU - headroomMilliCPU - int64(cp)*controlPlaneMilliCPU) / workerMilliCPU
+		byMem := (rec.MemoryBytes - headroomMemory - int64(cp)*controlPlaneMemory) / workerMemory
+		return int(max(min(byCPU, byMem), 0))
+	}
+	rec.MaxWorkers = fit(controlPlanes)
+	resources := fmt.Sprintf("%d CPUs and %s", rec.CPUs, formatBytes(rec.MemoryBytes))
+
+	if rec.CPUs < constrainedCPUs || rec.MemoryBytes < constrainedMemory {
+		rec.Constrained, rec.ReserveResources = true, true
+		rec.RecommendedControlPlanes, rec.RecommendedWorkers = 1, 0
+		if controlPlanes > 1 || workers > 0 {
+			rec.Warnings = append(rec.Warnings, fmt.Sprintf("The engine has only %s: use a single-node cluster "+
+				"(1 control plane, no workers) with kubelet resource reservations so the node stays responsive.", resources))
+		}
+		return rec
+	}
+	if workers > rec.MaxWorkers {
+		rec.RecommendedWorkers = rec.MaxWorkers
+		rec.Warnings = append(rec.Warnings, fmt.Sprintf("%d workers exceed the %d that fit in %s next to %d control "+
+			"plane(s) (about %dm CPU and %s per worker); nodes may go NotReady under load.",
+			workers, rec.MaxWorkers, resources, controlPlanes, workerMilliCPU, formatBytes(workerMemory)))
+	}
+	if controlPlanes > 1 && fit(controlPlanes) == 0 {
+		rec.Warnings = append(rec.Warnings, fmt.Sprintf("%d control planes leave no room for workloads in %s; "+
+			"consider a single control plane.", controlPlanes, resources))
+	}
+	return rec
+}
+
+// hostMemory returns this host's total memory from /proc/meminfo, or 0 elsewhere.
+func hostMemory() int64 {
+	f, err := os.Open(memInfoPath)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kb << 10
+		}
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package kind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRecommendClusterSize(t *testing.T) {
+	tests := []struct {
+		name              string
+		cpus              int
+		memory            int64
+		controlPlanes     int
+		workers           int
+		wantMax           int
+		wantWorkers       int
+		wantControlPlanes int
+		wantConstrained   bool
+		wantWarning       string
+	}{
+		{"fits", 8, 16 << 30, 1, 3, 12, 3, 1, false, ""},
+		{"capped by memory", 16, 8 << 30, 1, 10, 5, 5, 1, false, "10 workers exceed the 5"},
+		{"capped by cpu", 4, 32 << 30, 1, 5, 4, 4, 1, false, "5 workers exceed the 4"},
+		{"constrained laptop", 2, 4 << 30, 1, 2, 0, 0, 1, true, "single-node cluster"},
+		{"constrained single node", 2, 4 << 30, 1, 0, 0, 0, 1, true, ""},
+		{"ha without room", 4, 6 << 30, 3, 0, 0, 0, 3, false, "3 control planes leave no room"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ri := rtdetect.RuntimeInfo{Backend: rtdetect.BackendDockerDesktop, CPUs: tt.cpus, MemoryBytes: tt.memory}
+			rec := RecommendClusterSize(ri, tt.controlPlanes, tt.workers)
+			if rec.Source != SizeSourceEngine {
+				t.Errorf("Source = %q, want %q", rec.Source, SizeSourceEngine)
+			}
+			if rec.MaxWorkers != tt.wantMax {
+				t.Errorf("MaxWorkers = %d, want %d", rec.MaxWorkers, tt.wantMax)
+			}
+			if rec.RecommendedWorkers != tt.wantWorkers || rec.RecommendedControlPlanes != tt.wantControlPlanes {
+				t.Errorf("recommended = %d control planes, %d workers, want %d, %d",
+					rec.RecommendedControlPlanes, rec.RecommendedWorkers, tt.wantControlPlanes, tt.wantWorkers)
+			}
+			if rec.Constrained != tt.wantConstrained || rec.ReserveResources != tt.wantConstrained {
+				t.Errorf("Constrained, ReserveResources = %v, %v, want %v", rec.Constrained, rec.ReserveResources, tt.wantConstrained)
+			}
+			warnings := strings.Join(rec.Warnings, "\n")
+			if tt.wantWarning == "" && warnings != "" {
+				t.Errorf("unexpected warnings: %s", warnings)
+			}
+			if !strings.Contains(warnings, tt.wantWarning) {
+				t.Errorf("warnings = %q, want one containing %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestRecommendClusterSize_HostFallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meminfo")
+	if err := os.WriteFile(path, []byte("MemTotal:       16384000 kB\nMemFree:         1000 kB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := memInfoPath
+	memInfoPath = path
+	t.Cleanup(func() { memInfoPath = old })
+
+	rec := RecommendClusterSize(rtdetect.RuntimeInfo{Backend: rtdetect.BackendNative}, 1, 1)
+	if rec.Source != SizeSourceHost || rec.MemoryBytes != 16384000<<10 {
+		t.Errorf("Source, MemoryBytes = %q, %d, want host, %d", rec.Source, rec.MemoryBytes, int64(16384000<<10))
+	}
+	if len(rec.Warnings) != 0 && !rec.Constrained {
+		t.Errorf("unexpected warnings for a native engine: %v", rec.Warnings)
+	}
+
+	memInfoPath = filepath.Join(t.TempDir(), "missing")
+	rec = RecommendClusterSize(rtdetect.RuntimeInfo{Backend: rtdetect.BackendColima}, 1, 4)
+	if rec.Source != SizeSourceUnknown || rec.RecommendedWorkers != 4 {
+		t.Errorf("Source, RecommendedWorkers = %q, %d, want unknown, 4", rec.Source, rec.RecommendedWorkers)
+	}
+	if len(rec.Warnings) != 2 {
+		t.Errorf("warnings = %v, want the VM and unknown memory warnings", rec.Warnings)
+	}
+}
+
+func TestGenerateConfig_ReserveKubeletResources(t *testing.T) {
+	out, err := GenerateConfig(ConfigOptions{ClusterName: "small", NumControlPlanes: 1, ReserveKubeletResources: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.KubeadmConfigPatches) != 1 {
+		t.Fatalf("kubeadmConfigPatches = %v, want one", cfg.KubeadmConfigPatches)
+	}
+	var patch map[string]any
+	if err := yaml.Unmarshal([]byte(cfg.KubeadmConfigPatches[0]), &patch); err != nil {
+		t.Fatal(err)
+	}
+	if patch["kind"] != "KubeletConfiguration" {
+		t.Errorf("patch kind = %v, want KubeletConfiguration", patch["kind"])
+	}
+	for _, key := range []string{"systemReserved", "kubeReserved", "evictionHard"} {
+		if _, ok := patch[key]; !ok {
+			t.Errorf("patch is missing %s", key)
+		}
+	}
+}
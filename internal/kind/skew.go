@@ -0,0 +1,114 @@
+package kind
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	kinddefaults "sigs.k8s.io/kind/pkg/apis/config/defaults"
+)
+
+// versionPattern matches a bare Kubernetes version such as "1.30.4" or "v1.30.4",
+// capturing the minor version.
+var versionPattern = regexp.MustCompile(`^v?1\.(\d+)\.\d+$`)
+
+// maxKubeletSkew is how many minors a kubelet may trail the API server (from 1.28 on;
+// two before).
+const maxKubeletSkew = 3
+
+// roleImage returns the image of a ControlPlaneImage or WorkerImage option: a bare
+// version names the node image of that version in repo (kindest/node by default).
+func roleImage(repo, image string) string {
+	if versionPattern.MatchString(image) {
+		return kindNodeImage(repo, image)
+	}
+	return image
+}
+
+// imageMinor returns the Kubernetes minor version of a node image from its vX.Y.Z
+// tag; an empty image is Kind's default image.
+func imageMinor(image string) (int, bool) {
+	if image == "" {
+		image = kinddefaults.Image
+	}
+	m := versionPattern.FindStringSubmatch(imageTag(image))
+	if m == nil {
+		return 0, false
+	}
+	minor, err := strconv.Atoi(m[1])
+	return minor, err == nil
+}
+
+// versionSkewFindings checks a config's node images against the Kubernetes version
+// skew policy: API servers within one minor of each other, and kubelets no newer than
+// the oldest API server and at most three minors older. Nodes whose image has no
+// version tag are skipped.
+func versionSkewFindings(cfg ClusterConfig) []ConfigFinding {
+	type nodeVersion struct {
+		field string
+		minor int
+	}
+	var controlPlanes, workers []nodeVersion
+	for i, node := range cfg.Nodes {
+		minor, ok := imageMinor(node.Image)
+		if !ok {
+			continue
+		}
+		nv := nodeVersion{fmt.Sprintf("nodes[%d].image", i), minor}
+		switch node.Role {
+		case RoleControlPlane:
+			controlPlanes = append(controlPlanes, nv)
+		case RoleWorker:
+			workers = append(workers, nv)
+		}
+	}
+	if len(controlPlanes) == 0 {
+		return nil
+	}
+	oldest, newest := controlPlanes[0], controlPlanes[0]
+	for _, cp := range controlPlanes[1:] {
+		if cp.minor < oldest.minor {
+			oldest = cp
+		}
+		if cp.minor > newest.minor {
+			newest = cp
+		}
+	}
+
+	var findings []ConfigFinding
+	switch diff := newest.minor - oldest.minor; {
+	case diff > 1:
+		findings = append(findings, ConfigFinding{Severity: SeverityError, Field: newest.field, Message: fmt.Sprintf(
+			"control planes run v1.%d and v1.%d; API servers must be within one minor version of each other",
+			oldest.minor, newest.minor)})
+	case diff == 1:
+		findings = append(findings, ConfigFinding{Severity: SeverityWarning, Field: newest.field, Message: fmt.Sprintf(
+			"control planes run v1.%d and v1.%d, a skew only supported during an upgrade", oldest.minor, newest.minor)})
+	}
+
+	maxSkew := maxKubeletSkew
+	if oldest.minor < 28 {
+		maxSkew = 2
+	}
+	older := false
+	for _, w := range workers {
+		switch {
+		case w.minor > oldest.minor:
+			findings = append(findings, ConfigFinding{Severity: SeverityError, Field: w.field, Message: fmt.Sprintf(
+				"worker kubelet v1.%d is newer than the v1.%d API server; kubelets must not be newer than the API server",
+				w.minor, oldest.minor)})
+		case oldest.minor-w.minor > maxSkew:
+			findings = append(findings, ConfigFinding{Severity: SeverityError, Field: w.field, Message: fmt.Sprintf(
+				"worker kubelet v1.%d is more than %d minor versions older than the v1.%d API server",
+				w.minor, maxSkew, oldest.minor)})
+		case w.minor < oldest.minor:
+			older = true
+		}
+	}
+	if older {
+		findings = append(findings, ConfigFinding{Severity: SeverityWarning, Field: "nodes", Message: "Kind joins " +
+			"workers with the kubeadm of their own image, which is older than the control plane's; kubeadm does " +
+			"not promise to join newer control planes, so if create fails at joining workers, narrow the skew"})
+	}
+	return findings
+}
@@ -0,0 +1,80 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateConfig_RoleImages(t *testing.T) {
+	out, err := GenerateConfig(ConfigOptions{
+		ClusterName:         "skew",
+		NumControlPlanes:    1,
+		NumWorkers:          2,
+		NodeImageRepository: "registry.corp/kind/node",
+		ControlPlaneImage:   "1.31.0",
+		WorkerImage:         "kindest/node:v1.30.4",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"registry.corp/kind/node:v1.31.0", "kindest/node:v1.30.4", "kindest/node:v1.30.4"}
+	for i, node := range cfg.Nodes {
+		if node.Image != want[i] {
+			t.Errorf("nodes[%d].image = %q, want %q", i, node.Image, want[i])
+		}
+	}
+}
+
+func TestGenerateConfig_RoleImageSkewErrors(t *testing.T) {
+	for _, opts := range []ConfigOptions{
+		{ClusterName: "x", NumWorkers: 1, ControlPlaneImage: "1.30.0", WorkerImage: "1.31.0"},
+		{ClusterName: "x", NumWorkers: 1, ControlPlaneImage: "1.31.0", WorkerImage: "1.27.3"},
+		{ClusterName: "x", NumWorkers: 1, ControlPlaneImage: "1.27.3", WorkerImage: "1.24.0"},
+		{ClusterName: "x", NumWorkers: 1, WorkerImage: "Not An Image"},
+	} {
+		if _, err := GenerateConfig(opts); err == nil {
+			t.Errorf("expected error for control plane %q, workers %q", opts.ControlPlaneImage, opts.WorkerImage)
+		}
+	}
+}
+
+func TestCheckConfig_VersionSkew(t *testing.T) {
+	tests := []struct {
+		name   string
+		images []string // control plane first
+		roles  []string
+		want   []string // finding severities
+	}{
+		{"same version", []string{"kindest/node:v1.31.0", "kindest/node:v1.31.0"}, []string{RoleControlPlane, RoleWorker}, nil},
+		{"older worker", []string{"kindest/node:v1.31.0", "kindest/node:v1.29.2"}, []string{RoleControlPlane, RoleWorker},
+			[]string{SeverityWarning}},
+		{"newer worker", []string{"kindest/node:v1.30.0", "kindest/node:v1.31.0"}, []string{RoleControlPlane, RoleWorker},
+			[]string{SeverityError}},
+		{"control planes one apart", []string{"kindest/node:v1.31.0", "kindest/node:v1.30.0"}, []string{RoleControlPlane, RoleControlPlane},
+			[]string{SeverityWarning}},
+		{"control planes two apart", []string{"kindest/node:v1.31.0", "kindest/node:v1.29.0"}, []string{RoleControlPlane, RoleControlPlane},
+			[]string{SeverityError}},
+		{"untagged", []string{"kindest/node@sha256:" + strings.Repeat("a", 64), "kindest/node:v1.20.0"}, []string{RoleControlPlane, RoleWorker}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg ClusterConfig
+			for i, image := range tt.images {
+				cfg.Nodes = append(cfg.Nodes, NodeConfig{Role: tt.roles[i], Image: image})
+			}
+			var got []string
+			for _, f := range versionSkewFindings(cfg) {
+				got = append(got, f.Severity)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("severities = %v, want %v (%+v)", got, tt.want, versionSkewFindings(cfg))
+			}
+		})
+	}
+}
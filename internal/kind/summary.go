@@ -0,0 +1,122 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cluster states reported in ClusterSummary.
+const (
+	ClusterRunning  = "running"
+	ClusterStopped  = "stopped"
+	ClusterPaused   = "paused"
+	ClusterDegraded = "degraded"
+)
+
+// ClusterSummary is a one-line overview of a cluster built from its node containers.
+type ClusterSummary struct {
+	Name              string            `json:"name"`
+	State             string            `json:"state"`
+	ControlPlanes     int               `json:"control_planes"`
+	Workers           int               `json:"workers"`
+	LoadBalancer      bool              `json:"load_balancer,omitempty"`
+	KubernetesVersion string            `json:"kubernetes_version,omitempty"`
+	CreatedAt         *time.Time        `json:"created_at,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	// Owner is set by callers to the HTTP client recorded as the cluster's owner.
+	Owner string `json:"owner,omitempty"`
+	// Unmanaged is set by callers for clusters without a record in the state store.
+	Unmanaged bool `json:"unmanaged,omitempty"`
+	// Current is set by callers for the cluster selected with use_cluster.
+	Current bool `json:"current,omitempty"`
+}
+
+// SummarizeCluster inspects all of a cluster's node containers in one runtime call and
+// returns node counts, state, the Kubernetes version (from the node image tag), and the
+// creation time of the oldest node.
+func (m *Manager) SummarizeCluster(ctx context.Context, name string) (*ClusterSummary, error) {
+	nodes, err := m.GetClusterNodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q %w", name, ErrClusterNotFound)
+	}
+
+	args := append([]string{"inspect", "--format", "{{.State.Status}}|{{.Config.Image}}|{{.Created}}"}, nodes...)
+	out, err := m.runner.Run(ctx, m.runtimeBin(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting nodes: %w\nOutput: %s", err, string(out))
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+
+	summary := &ClusterSummary{Name: name}
+	running, paused := 0, 0
+	for i, node := range nodes {
+		var status, image, created string
+		if i < len(lines) {
+			parts := strings.SplitN(strings.TrimSpace(lines[i]), "|", 3)
+			for len(parts) < 3 {
+				parts = append(parts, "")
+			}
+			status, image, created = parts[0], parts[1], parts[2]
+		}
+		switch status {
+		case "running":
+			running++
+		case "paused":
+			paused++
+		}
+
+		switch NodeRole(node) {
+		case RoleExternalLoadBalancer:
+			summary.LoadBalancer = true
+			continue
+		case RoleControlPlane:
+			summary.ControlPlanes++
+		default:
+			summary.Workers++
+		}
+		if summary.KubernetesVersion == "" {
+			summary.KubernetesVersion = imageTag(image)
+		}
+		if t, ok := parseCreated(created); ok && (summary.CreatedAt == nil || t.Before(*summary.CreatedAt)) {
+			summary.CreatedAt = &t
+		}
+	}
+
+	switch {
+	case running == len(nodes):
+		summary.State = ClusterRunning
+	case paused == len(nodes):
+		summary.State = ClusterPaused
+	case running == 0 && paused == 0:
+		summary.State = ClusterStopped
+	default:
+		summary.State = ClusterDegraded
+	}
+	return summary, nil
+}
+
+// imageTag returns the tag of an image reference, ignoring any digest
+// (e.g. "kindest/node:v1.31.0@sha256:..." → "v1.31.0").
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// parseCreated parses a container creation time as printed by docker (RFC 3339) or
+// podman (Go's default time format).
+func parseCreated(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,70 @@
+package kind
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSummarizeCluster(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte(
+			"ha-external-load-balancer\nha-control-plane\nha-worker\nha-worker2\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte(
+			"running|kindest/haproxy:v20230606|2024-05-01T10:00:05Z\n" +
+				"running|kindest/node:v1.31.0@sha256:abc|2024-05-01T10:00:01.5Z\n" +
+				"running|kindest/node:v1.31.0@sha256:abc|2024-05-01T10:00:02Z\n" +
+				"exited|kindest/node:v1.31.0@sha256:abc|2024-05-01T10:00:03Z\n")},
+	}}
+
+	s, err := newDockerManager(runner).SummarizeCluster(context.Background(), "ha")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.ControlPlanes != 1 || s.Workers != 2 || !s.LoadBalancer {
+		t.Errorf("counts = %+v", s)
+	}
+	if s.KubernetesVersion != "v1.31.0" {
+		t.Errorf("version = %q", s.KubernetesVersion)
+	}
+	if s.State != ClusterDegraded {
+		t.Errorf("state = %q, want degraded", s.State)
+	}
+	if s.CreatedAt == nil || s.CreatedAt.Second() != 1 {
+		t.Errorf("created = %v, want the oldest node", s.CreatedAt)
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := map[string]string{
+		"kindest/node:v1.31.0":               "v1.31.0",
+		"kindest/node:v1.31.0@sha256:abc":    "v1.31.0",
+		"registry:5000/kindest/node":         "",
+		"registry:5000/kindest/node:v1.30.0": "v1.30.0",
+	}
+	for image, want := range tests {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestParseCreated_Podman(t *testing.T) {
+	if _, ok := parseCreated("2024-05-01 10:00:00.123456789 +0000 UTC"); !ok {
+		t.Error("expected podman time format to parse")
+	}
+}
+
+func TestSummarizeCluster_Paused(t *testing.T) {
+	runner := &mockRunner{runs: []runCall{
+		{name: "kind", args: []string{"get", "nodes"}, out: []byte("dev-control-plane\ndev-worker\n")},
+		{name: "docker", args: []string{"inspect"}, out: []byte(
+			"paused|kindest/node:v1.31.0|2024-05-01T10:00:00Z\npaused|kindest/node:v1.31.0|2024-05-01T10:00:00Z\n")},
+	}}
+	s, err := newDockerManager(runner).SummarizeCluster(context.Background(), "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.State != ClusterPaused {
+		t.Errorf("state = %q, want paused", s.State)
+	}
+}
@@ -0,0 +1,93 @@
+package kind
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Taint is a node taint registered by the kubelet when the node joins.
+type Taint struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect string `json:"effect" yaml:"effect"`
+}
+
+// TargetedTaint is a taint applied to the nodes selected by Target.
+type TargetedTaint struct {
+	Taint
+	Target NodeTarget `json:"target"`
+}
+
+// String formats a taint as kubectl does, e.g. "dedicated=gpu:NoSchedule".
+func (t Taint) String() string {
+	if t.Value == "" {
+		return t.Key + ":" + t.Effect
+	}
+	return t.Key + "=" + t.Value + ":" + t.Effect
+}
+
+var (
+	taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+	// taintKeyPattern matches a qualified name with an optional DNS prefix, as taint
+	// and label keys must be.
+	taintKeyPattern = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	// taintValuePattern matches a label value.
+	taintValuePattern = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// controlPlaneTaint is the taint kubeadm puts on control planes unless taints are
+// given. Kind removes it again on single-node clusters.
+var controlPlaneTaint = Taint{Key: "node-role.kubernetes.io/control-plane", Effect: "NoSchedule"}
+
+// validate checks a taint's key, value and effect.
+func (t Taint) validate() error {
+	if prefix, _, ok := strings.Cut(t.Key, "/"); !taintKeyPattern.MatchString(t.Key) || ok && len(prefix) > 253 {
+		return fmt.Errorf("invalid taint key %q", t.Key)
+	}
+	if !taintValuePattern.MatchString(t.Value) {
+		return fmt.Errorf("invalid taint value %q for %s", t.Value, t.Key)
+	}
+	if !slices.Contains(taintEffects, t.Effect) {
+		return fmt.Errorf("invalid taint effect %q for %s; must be one of %s", t.Effect, t.Key, strings.Join(taintEffects, ", "))
+	}
+	return nil
+}
+
+// nodeTaints returns the taints the options put on the index'th node of a role. A
+// control plane given taints keeps kubeadm's own control-plane taint, which the
+// given taints would otherwise replace.
+func nodeTaints(opts ConfigOptions, role string, index int) []Taint {
+	var taints []Taint
+	for _, t := range opts.Taints {
+		if t.Target.Matches(role, index) && !slices.Contains(taints, t.Taint) {
+			taints = append(taints, t.Taint)
+		}
+	}
+	if len(taints) > 0 && role == RoleControlPlane && !slices.Contains(taints, controlPlaneTaint) {
+		taints = append([]Taint{controlPlaneTaint}, taints...)
+	}
+	return taints
+}
+
+// taintPatches returns node-level kubeadm patches that register a node with taints.
+// Kind generates both an InitConfiguration and a JoinConfiguration for every node
+// and uses one, so both are patched; nodeRegistration.taints has the same form in
+// every kubeadm version, so the patches leave the apiVersion out.
+func taintPatches(taints []Taint) ([]string, error) {
+	var patches []string
+	for _, kind := range []string{"InitConfiguration", "JoinConfiguration"} {
+		data, err := yaml.Marshal(map[string]any{
+			"kind":             kind,
+			"nodeRegistration": map[string]any{"taints": taints},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling kubeadm patch: %w", err)
+		}
+		patches = append(patches, string(data))
+	}
+	return patches, nil
+}
@@ -0,0 +1,71 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateConfig_Taints(t *testing.T) {
+	first := 0
+	out, err := GenerateConfig(ConfigOptions{
+		ClusterName:      "tainted",
+		NumControlPlanes: 1,
+		NumWorkers:       2,
+		Taints: []TargetedTaint{
+			{Taint: Taint{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}, Target: NodeTarget{Role: RoleWorker, Index: &first}},
+			{Taint: Taint{Key: "example.com/audit", Effect: "PreferNoSchedule"}, Target: NodeTarget{Role: RoleControlPlane}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	taintsOf := func(node NodeConfig) map[string][]Taint {
+		got := map[string][]Taint{}
+		for _, p := range node.KubeadmConfigPatches {
+			var patch struct {
+				Kind             string `yaml:"kind"`
+				NodeRegistration struct {
+					Taints []Taint `yaml:"taints"`
+				} `yaml:"nodeRegistration"`
+			}
+			if err := yaml.Unmarshal([]byte(p), &patch); err != nil {
+				t.Fatal(err)
+			}
+			got[patch.Kind] = patch.NodeRegistration.Taints
+		}
+		return got
+	}
+
+	cp := taintsOf(cfg.Nodes[0])
+	if len(cp["InitConfiguration"]) != 2 || cp["InitConfiguration"][0] != controlPlaneTaint ||
+		cp["InitConfiguration"][1].Key != "example.com/audit" {
+		t.Errorf("control-plane taints = %+v, want kubeadm's control-plane taint kept", cp)
+	}
+	gpu := taintsOf(cfg.Nodes[1])
+	if len(gpu["JoinConfiguration"]) != 1 || gpu["JoinConfiguration"][0].String() != "dedicated=gpu:NoSchedule" {
+		t.Errorf("first worker taints = %+v", gpu)
+	}
+	if len(cfg.Nodes[2].KubeadmConfigPatches) != 0 {
+		t.Errorf("second worker should have no patches:\n%s", strings.Join(cfg.Nodes[2].KubeadmConfigPatches, "---\n"))
+	}
+}
+
+func TestGenerateConfig_InvalidTaints(t *testing.T) {
+	for _, taint := range []TargetedTaint{
+		{Taint: Taint{Key: "dedicated", Effect: "NeverSchedule"}},
+		{Taint: Taint{Key: "bad key", Effect: "NoSchedule"}},
+		{Taint: Taint{Key: "dedicated", Value: "has space", Effect: "NoSchedule"}},
+		{Taint: Taint{Key: "dedicated", Effect: "NoSchedule"}, Target: NodeTarget{Role: RoleWorker}},
+	} {
+		if _, err := GenerateConfig(ConfigOptions{ClusterName: "x", Taints: []TargetedTaint{taint}}); err == nil {
+			t.Errorf("expected error for %+v", taint)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package kind
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tuning holds common kubelet, API server and scheduler settings. GenerateConfig
+// validates them and turns them into kubeadm patches, so callers need not write
+// KubeletConfiguration or ClusterConfiguration patches by hand.
+type Tuning struct {
+	// MaxPods is the kubelet's pod limit per node (kubelet default 110).
+	MaxPods int `json:"max_pods,omitempty"`
+	// EvictionHard sets kubelet hard eviction thresholds by signal, e.g.
+	// {"memory.available": "200Mi", "nodefs.available": "5%"}.
+	EvictionHard map[string]string `json:"eviction_hard,omitempty"`
+	// AuditLog turns on API server audit logging to /var/log/kubernetes/audit inside
+	// the control-plane nodes.
+	AuditLog *AuditLog `json:"audit_log,omitempty"`
+	// AdmissionPlugins and DisabledAdmissionPlugins are passed to kube-apiserver
+	// --enable-admission-plugins and --disable-admission-plugins.
+	AdmissionPlugins         []string `json:"admission_plugins,omitempty"`
+	DisabledAdmissionPlugins []string `json:"disabled_admission_plugins,omitempty"`
+	// SchedulerProfiles configure kube-scheduler. A plain default-scheduler profile is
+	// added unless one is given, so pods that name no scheduler still get scheduled.
+	SchedulerProfiles []SchedulerProfile `json:"scheduler_profiles,omitempty"`
+}
+
+// AuditLog configures API server audit logging.
+type AuditLog struct {
+	// Level is the audit level of every request: None, Metadata (default), Request
+	// or RequestResponse. Health checks are never logged.
+	Level      string `json:"level,omitempty"`
+	MaxAgeDays int    `json:"max_age_days,omitempty"`
+	MaxBackups int    `json:"max_backups,omitempty"`
+	MaxSizeMB  int    `json:"max_size_mb,omitempty"`
+}
+
+// SchedulerProfile is a kube-scheduler profile, selected by pods through spec.schedulerName.
+type SchedulerProfile struct {
+	Name string `json:"name"`
+	// ScoringStrategy is NodeResourcesFit's strategy: LeastAllocated (spread, the
+	// default) or MostAllocated (bin-pack).
+	ScoringStrategy string `json:"scoring_strategy,omitempty"`
+	// DisabledPlugins are turned off at every extension point, e.g. "PodTopologySpread".
+	DisabledPlugins []string `json:"disabled_plugins,omitempty"`
+}
+
+// Paths of the tuning files inside control-plane nodes, and of the audit log.
+const (
+	tuningNodeDir          = "/etc/kubernetes/mcp-kind"
+	auditPolicyFile        = "audit-policy.yaml"
+	schedulerConfigFile    = "scheduler-config.yaml"
+	auditLogDir            = "/var/log/kubernetes/audit"
+	defaultSchedulerName   = "default-scheduler"
+	schedulerKubeconfig    = "/etc/kubernetes/scheduler.conf"
+	defaultAuditLevel      = "Metadata"
+	kubeletConfigVersion   = "kubelet.config.k8s.io/v1beta1"
+	schedulerConfigVersion = "kubescheduler.config.k8s.io/v1"
+)
+
+var (
+	auditLevels       = []string{"None", "Metadata", "Request", "RequestResponse"}
+	scoringStrategies = []string{"LeastAllocated", "MostAllocated"}
+	evictionSignals   = []string{"memory.available", "nodefs.available", "nodefs.inodesFree",
+		"imagefs.available", "imagefs.inodesFree", "pid.available"}
+
+	// pluginNamePattern matches admission and scheduler plugin names such as NodeRestriction.
+	pluginNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	// evictionValuePattern matches a quantity such as 200Mi or a percentage such as 5%.
+	evictionValuePattern = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?([KMGTPE]i?)?|[0-9]+(\.[0-9]+)?%)$`)
+	// schedulerNamePattern matches a lowercase DNS subdomain, as spec.schedulerName must be.
+	schedulerNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+)
+
+// IsZero reports whether no tuning is set.
+func (t Tuning) IsZero() bool {
+	return t.MaxPods == 0 && len(t.EvictionHard) == 0 && t.AuditLog == nil &&
+		len(t.AdmissionPlugins) == 0 && len(t.DisabledAdmissionPlugins) == 0 && len(t.SchedulerProfiles) == 0
+}
+
+// NeedsFiles reports whether the tuning needs files mounted into the control-plane
+// nodes (an audit policy or a scheduler config), written by WriteTuningFiles.
+func (t Tuning) NeedsFiles() bool {
+	return t.AuditLog != nil || len(t.SchedulerProfiles) > 0
+}
+
+// validate checks every tuning value.
+func (t Tuning) validate() error {
+	if t.MaxPods < 0 || t.MaxPods > 1000 {
+		return fmt.Errorf("invalid max_pods %d; must be between 1 and 1000", t.MaxPods)
+	}
+	for _, signal := range sortedKeys(t.EvictionHard) {
+		if !slices.Contains(evictionSignals, signal) {
+			return fmt.Errorf("invalid eviction signal %q; must be one of %s", signal, strings.Join(evictionSignals, ", "))
+		}
+		if v := t.EvictionHard[signal]; !evictionValuePattern.MatchString(v) {
+			return fmt.Errorf("invalid eviction threshold %s=%q; use a quantity such as 200Mi or a percentage such as 5%%", signal, v)
+		}
+	}
+	if a := t.AuditLog; a != nil {
+		if a.Level != "" && !slices.Contains(auditLevels, a.Level) {
+			return fmt.Errorf("invalid audit level %q; must be one of %s", a.Level, strings.Join(auditLevels, ", "))
+		}
+		if a.MaxAgeDays < 0 || a.MaxBackups < 0 || a.MaxSizeMB < 0 {
+			return fmt.Errorf("audit log retention values must not be negative")
+		}
+	}
+	for _, p := range append(slices.Clone(t.AdmissionPlugins), t.DisabledAdmissionPlugins...) {
+		if !pluginNamePattern.MatchString(p) {
+			return fmt.Errorf("invalid admission plugin name %q", p)
+		}
+	}
+	for _, p := range t.AdmissionPlugins {
+		if slices.Contains(t.DisabledAdmissionPlugins, p) {
+			return fmt.Errorf("admission plugin %s is both enabled and disabled", p)
+		}
+	}
+	seen := map[string]bool{}
+	for _, p := range t.SchedulerProfiles {
+		if !schedulerNamePattern.MatchString(p.Name) || len(p.Name) > 253 {
+			return fmt.Errorf("invalid scheduler profile name %q; must be a lowercase DNS name", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate scheduler profile %q", p.Name)
+		}
+		seen[p.Name] = true
+		if p.ScoringStrategy != "" && !slices.Contains(scoringStrategies, p.ScoringStrategy) {
+			return fmt.Errorf("invalid scoring strategy %q for profile %s; must be one of %s",
+				p.ScoringStrategy, p.Name, strings.Join(scoringStrategies, ", "))
+		}
+		for _, plugin := range p.DisabledPlugins {
+			if !pluginNamePattern.MatchString(plugin) {
+				return fmt.Errorf("invalid scheduler plugin name %q in profile %s", plugin, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// kubeadmArg is one --name=value flag of a control-plane component.
+type kubeadmArg struct{ name, value string }
+
+// kubeadmArgs renders flags as extraArgs for a kubeadm API version: a map before
+// v1beta4, a list of name/value pairs from it on.
+func kubeadmArgs(version string, args []kubeadmArg) any {
+	if version == "kubeadm.k8s.io/v1beta3" {
+		m := make(map[string]string, len(args))
+		for _, a := range args {
+			m[a.name] = a.value
+		}
+		return m
+	}
+	list := make([]map[string]string, len(args))
+	for i, a := range args {
+		list[i] = map[string]string{"name": a.name, "value": a.value}
+	}
+	return list
+}
+
+// apiServerArgs returns the kube-apiserver flags of the tuning.
+func (t Tuning) apiServerArgs() []kubeadmArg {
+	var args []kubeadmArg
+	if len(t.AdmissionPlugins) > 0 {
+		args = append(args, kubeadmArg{"enable-admission-plugins", strings.Join(t.AdmissionPlugins, ",")})
+	}
+	if len(t.DisabledAdmissionPlugins) > 0 {
+		args = append(args, kubeadmArg{"disable-admission-plugins", strings.Join(t.DisabledAdmissionPlugins, ",")})
+	}
+	if a := t.AuditLog; a != nil {
+		args = append(args,
+			kubeadmArg{"audit-policy-file", tuningNodeDir + "/" + auditPolicyFile},
+			kubeadmArg{"audit-log-path", auditLogDir + "/audit.log"})
+		for _, v := range []struct {
+			name  string
+			value int
+		}{{"audit-log-maxage", a.MaxAgeDays}, {"audit-log-maxbackup", a.MaxBackups}, {"audit-log-maxsize", a.MaxSizeMB}} {
+			if v.value > 0 {
+				args = append(args, kubeadmArg{v.name, strconv.Itoa(v.value)})
+			}
+		}
+	}
+	return args
+}
+
+// apiServerVolumes returns the kube-apiserver extraVolumes of the tuning.
+func (t Tuning) apiServerVolumes() []map[string]any {
+	if t.AuditLog == nil {
+		return nil
+	}
+	return []map[string]any{
+		tuningFileVolume("audit-policy", auditPolicyFile),
+		{"name": "audit-logs", "hostPath": auditLogDir, "mountPath": auditLogDir, "pathType": "DirectoryOrCreate"},
+	}
+}
+
+// tuningFileVolume mounts a tuning file, read-only, into a control-plane static pod.
+func tuningFileVolume(name, file string) map[string]any {
+	path := tuningNodeDir + "/" + file
+	return map[string]any{"name": name, "hostPath": path, "mountPath": path, "readOnly": true, "pathType": "File"}
+}
+
+// tuningMounts returns the extraMounts that place the tuning files from hostDir
+// on a control-plane node.
+func (t Tuning) tuningMounts(hostDir string) []Mount {
+	var mounts []Mount
+	for _, file := range t.fileNames() {
+		mounts = append(mounts, Mount{
+			HostPath:      filepath.Join(hostDir, file),
+			ContainerPath: tuningNodeDir + "/" + file,
+			ReadOnly:      true,
+		})
+	}
+	return mounts
+}
+
+func (t Tuning) fileNames() []string {
+	var names []string
+	if t.AuditLog != nil {
+		names = append(names, auditPolicyFile)
+	}
+	if len(t.SchedulerProfiles) > 0 {
+		names = append(names, schedulerConfigFile)
+	}
+	return names
+}
+
+// kubeletPatch returns the KubeletConfiguration patch for reserved resources and
+// the tuning's kubelet settings, or "" when there are none. Kind's own evictionHard
+// thresholds for disk are kept, since the patch is merged into them.
+func kubeletPatch(reserve bool, t Tuning) (string, error) {
+	patch := map[string]any{}
+	eviction := map[string]string{}
+	if reserve {
+		patch["systemReserved"] = map[string]string{"cpu": "250m", "memory": "256Mi"}
+		patch["kubeReserved"] = map[string]string{"cpu": "250m", "memory": "256Mi"}
+		eviction["memory.available"] = "200Mi"
+	}
+	for signal, v := range t.EvictionHard {
+		eviction[signal] = v
+	}
+	if len(eviction) > 0 {
+		patch["evictionHard"] = eviction
+	}
+	if t.MaxPods > 0 {
+		patch["maxPods"] = t.MaxPods
+	}
+	if len(patch) == 0 {
+		return "", nil
+	}
+	patch["apiVersion"] = kubeletConfigVersion
+	patch["kind"] = "KubeletConfiguration"
+	data, err := yaml.Marshal(patch)
+	if err != nil {
+		return "", fmt.Errorf("marshaling kubelet patch: %w", err)
+	}
+	return string(data), nil
+}
+
+// TuningFiles returns the files the tuning mounts into control-plane nodes, by file
+// name: the audit policy and the kube-scheduler configuration.
+func TuningFiles(t Tuning) (map[string]string, error) {
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	if a := t.AuditLog; a != nil {
+		data, err := yaml.Marshal(map[string]any{
+			"apiVersion": "audit.k8s.io/v1",
+			"kind":       "Policy",
+			"omitStages": []string{"RequestReceived"},
+			"rules": []map[string]any{
+				{"level": "None", "nonResourceURLs": []string{"/healthz*", "/livez*", "/readyz*"}},
+				{"level": cmp.Or(a.Level, defaultAuditLevel)},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling audit policy: %w", err)
+		}
+		files[auditPolicyFile] = string(data)
+	}
+	if len(t.SchedulerProfiles) > 0 {
+		profiles := t.SchedulerProfiles
+		if !slices.ContainsFunc(profiles, func(p SchedulerProfile) bool { return p.Name == defaultSchedulerName }) {
+			profiles = append([]SchedulerProfile{{Name: defaultSchedulerName}}, profiles...)
+		}
+		var rendered []map[string]any
+		for _, p := range profiles {
+			profile := map[string]any{"schedulerName": p.Name}
+			if len(p.DisabledPlugins) > 0 {
+				var disabled []map[string]string
+				for _, plugin := range p.DisabledPlugins {
+					disabled = append(disabled, map[string]string{"name": plugin})
+				}
+				profile["plugins"] = map[string]any{"multiPoint": map[string]any{"disabled": disabled}}
+			}
+			if p.ScoringStrategy != "" {
+				profile["pluginConfig"] = []map[string]any{{
+					"name": "NodeResourcesFit",
+					"args": map[string]any{"scoringStrategy": map[string]any{
+						"type": p.ScoringStrategy,
+						"resources": []map[string]any{
+							{"name": "cpu", "weight": 1},
+							{"name": "memory", "weight": 1},
+						},
+					}},
+				}}
+			}
+			rendered = append(rendered, profile)
+		}
+		data, err := yaml.Marshal(map[string]any{
+			"apiVersion": schedulerConfigVersion,
+			"kind":       "KubeSchedulerConfiguration",
+			// kube-scheduler ignores --kubeconfig once --config is set.
+			"clientConnection": map[string]string{"kubeconfig": schedulerKubeconfig},
+			"profiles":         rendered,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling scheduler config: %w", err)
+		}
+		files[schedulerConfigFile] = string(data)
+	}
+	return files, nil
+}
+
+// WriteTuningFiles writes the files of TuningFiles into dir, readable only by the
+// owner, for GenerateConfig to mount from ConfigOptions.TuningDir.
+func WriteTuningFiles(dir string, t Tuning) error {
+	files, err := TuningFiles(t)
+	if err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(files) {
+		if err := writePrivateFile(filepath.Join(ExpandHome(dir), name), []byte(files[name])); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
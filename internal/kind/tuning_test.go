@@ -0,0 +1,150 @@
+package kind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateConfig_Tuning(t *testing.T) {
+	dir := t.TempDir()
+	out, err := GenerateConfig(ConfigOptions{
+		ClusterName:      "tuned",
+		NumControlPlanes: 1,
+		NumWorkers:       1,
+		NodePortRange:    "30000-40000",
+		Tuning: Tuning{
+			MaxPods:                  250,
+			EvictionHard:             map[string]string{"nodefs.available": "5%"},
+			AuditLog:                 &AuditLog{Level: "Request", MaxAgeDays: 7},
+			AdmissionPlugins:         []string{"AlwaysPullImages"},
+			DisabledAdmissionPlugins: []string{"DefaultStorageClass"},
+			SchedulerProfiles:        []SchedulerProfile{{Name: "bin-pack", ScoringStrategy: "MostAllocated"}},
+		},
+		TuningDir: dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(out), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.KubeadmConfigPatches) != 3 {
+		t.Fatalf("expected two ClusterConfiguration patches and a kubelet patch, got %d:\n%s", len(cfg.KubeadmConfigPatches), out)
+	}
+
+	v1beta4 := cfg.KubeadmConfigPatches[1]
+	for _, want := range []string{
+		"value: 30000-40000",
+		"name: enable-admission-plugins",
+		"value: AlwaysPullImages",
+		"name: disable-admission-plugins",
+		"name: audit-policy-file",
+		"name: audit-log-maxage",
+		"mountPath: /var/log/kubernetes/audit",
+		"scheduler:",
+		"value: /etc/kubernetes/mcp-kind/scheduler-config.yaml",
+	} {
+		if !strings.Contains(v1beta4, want) {
+			t.Errorf("ClusterConfiguration patch is missing %q:\n%s", want, v1beta4)
+		}
+	}
+
+	var kubelet struct {
+		Kind         string            `yaml:"kind"`
+		MaxPods      int               `yaml:"maxPods"`
+		EvictionHard map[string]string `yaml:"evictionHard"`
+	}
+	if err := yaml.Unmarshal([]byte(cfg.KubeadmConfigPatches[2]), &kubelet); err != nil {
+		t.Fatal(err)
+	}
+	if kubelet.Kind != "KubeletConfiguration" || kubelet.MaxPods != 250 || kubelet.EvictionHard["nodefs.available"] != "5%" {
+		t.Errorf("kubelet patch = %+v", kubelet)
+	}
+
+	// The tuning files go on control planes only.
+	if mounts := cfg.Nodes[0].ExtraMounts; len(mounts) != 2 || mounts[0].HostPath != filepath.Join(dir, auditPolicyFile) {
+		t.Errorf("control-plane mounts = %+v", mounts)
+	}
+	if len(cfg.Nodes[1].ExtraMounts) != 0 {
+		t.Errorf("worker mounts = %+v, want none", cfg.Nodes[1].ExtraMounts)
+	}
+}
+
+func TestGenerateConfig_InvalidTuning(t *testing.T) {
+	for _, tuning := range []Tuning{
+		{MaxPods: -1},
+		{EvictionHard: map[string]string{"memory.free": "1Gi"}},
+		{EvictionHard: map[string]string{"memory.available": "lots"}},
+		{AuditLog: &AuditLog{Level: "Everything"}},
+		{AdmissionPlugins: []string{"not-a-plugin"}},
+		{AdmissionPlugins: []string{"NodeRestriction"}, DisabledAdmissionPlugins: []string{"NodeRestriction"}},
+		{SchedulerProfiles: []SchedulerProfile{{Name: "Bad Name"}}},
+		{SchedulerProfiles: []SchedulerProfile{{Name: "a"}, {Name: "a"}}},
+		{SchedulerProfiles: []SchedulerProfile{{Name: "a", ScoringStrategy: "Random"}}},
+	} {
+		if _, err := GenerateConfig(ConfigOptions{ClusterName: "x", Tuning: tuning, TuningDir: "/tmp"}); err == nil {
+			t.Errorf("expected error for %+v", tuning)
+		}
+	}
+	if _, err := GenerateConfig(ConfigOptions{ClusterName: "x", Tuning: Tuning{AuditLog: &AuditLog{}}}); err == nil {
+		t.Error("expected an error for audit logging without a tuning directory")
+	}
+}
+
+func TestWriteTuningFiles(t *testing.T) {
+	dir := t.TempDir()
+	tuning := Tuning{
+		AuditLog:          &AuditLog{},
+		SchedulerProfiles: []SchedulerProfile{{Name: "no-spread", DisabledPlugins: []string{"PodTopologySpread"}}},
+	}
+	if err := WriteTuningFiles(dir, tuning); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := os.ReadFile(filepath.Join(dir, auditPolicyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(policy), "level: Metadata") || !strings.Contains(string(policy), "/healthz*") {
+		t.Errorf("audit policy:\n%s", policy)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, schedulerConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sched struct {
+		Kind             string            `yaml:"kind"`
+		ClientConnection map[string]string `yaml:"clientConnection"`
+		Profiles         []struct {
+			SchedulerName string `yaml:"schedulerName"`
+			Plugins       struct {
+				MultiPoint struct {
+					Disabled []struct{ Name string } `yaml:"disabled"`
+				} `yaml:"multiPoint"`
+			} `yaml:"plugins"`
+		} `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &sched); err != nil {
+		t.Fatal(err)
+	}
+	if sched.Kind != "KubeSchedulerConfiguration" || sched.ClientConnection["kubeconfig"] != schedulerKubeconfig {
+		t.Errorf("scheduler config:\n%s", data)
+	}
+	if len(sched.Profiles) != 2 || sched.Profiles[0].SchedulerName != defaultSchedulerName ||
+		sched.Profiles[1].Plugins.MultiPoint.Disabled[0].Name != "PodTopologySpread" {
+		t.Errorf("profiles should be default-scheduler then no-spread:\n%s", data)
+	}
+	info, err := os.Stat(filepath.Join(dir, schedulerConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("scheduler config mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,239 @@
+package kind
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// Finding severities reported by CheckConfig.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ConfigFinding is a single problem found in a Kind config.
+type ConfigFinding struct {
+	Severity string `json:"severity"`
+	// Field locates the problem, e.g. "nodes[1].extraPortMappings[0]".
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ConfigReport lists every finding for a Kind config. Valid is false when any
+// finding is an error; warnings alone leave the config valid.
+type ConfigReport struct {
+	Valid    bool            `json:"valid"`
+	Findings []ConfigFinding `json:"findings"`
+}
+
+// CheckConfig validates a Kind cluster config YAML and returns all errors and
+// warnings, not just the first. The runtime info enables backend-specific warnings
+// and may be the zero value.
+func CheckConfig(configYAML string, ri rtdetect.RuntimeInfo) ConfigReport {
+	report := ConfigReport{Findings: []ConfigFinding{}}
+	add := func(severity, field, format string, args ...any) {
+		report.Findings = append(report.Findings, ConfigFinding{
+			Severity: severity, Field: field, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	var cfg ClusterConfig
+	if err := yaml.Unmarshal([]byte(configYAML), &cfg); err != nil {
+		add(SeverityError, "", "invalid YAML: %v", err)
+		return report
+	}
+
+	if cfg.Kind != "Cluster" {
+		add(SeverityError, "kind", "expected kind 'Cluster', got %q", cfg.Kind)
+	}
+	if cfg.APIVersion != "kind.x-k8s.io/v1alpha4" {
+		add(SeverityError, "apiVersion", "expected apiVersion 'kind.x-k8s.io/v1alpha4', got %q", cfg.APIVersion)
+	}
+
+	if cfg.Name != "" {
+		if err := ValidateClusterName(cfg.Name); err != nil {
+			add(SeverityError, "name", "%v", err)
+		}
+	}
+
+	controlPlanes := 0
+	for i, node := range cfg.Nodes {
+		field := fmt.Sprintf("nodes[%d]", i)
+		switch node.Role {
+		case RoleControlPlane:
+			controlPlanes++
+			if controlPlanes > 1 && len(node.ExtraPortMappings) > 0 {
+				add(SeverityWarning, field+".extraPortMappings",
+					"port mappings on control-plane %d, not the first; ingress-ready setups and "+
+						"generate_cluster_config assume the first control plane, so make sure the exposed "+
+						"workload is scheduled on this node", controlPlanes-1)
+			}
+		case RoleWorker:
+		default:
+			add(SeverityError, field+".role", "invalid node role %q; must be 'control-plane' or 'worker'", node.Role)
+		}
+	}
+	if len(cfg.Nodes) > 0 && controlPlanes == 0 {
+		add(SeverityError, "nodes", "at least one control-plane node is required")
+	}
+
+	hostPorts := make(map[string]string)
+	for i, node := range cfg.Nodes {
+		field := fmt.Sprintf("nodes[%d]", i)
+		if node.Image != "" {
+			if err := checkImageRef(node.Image); err != nil {
+				add(SeverityError, field+".image", "%v", err)
+			} else if tag := imageTag(node.Image); !strings.Contains(node.Image, "@") && (tag == "" || tag == "latest") {
+				add(SeverityWarning, field+".image",
+					"image %q has no version tag; kindest/node images must be pinned (e.g. :v1.31.0)", node.Image)
+			}
+		}
+		for j, pm := range node.ExtraPortMappings {
+			pmField := fmt.Sprintf("%s.extraPortMappings[%d]", field, j)
+			if pm.ContainerPort < 1 || pm.ContainerPort > 65535 {
+				add(SeverityError, pmField+".containerPort", "containerPort %d is out of range 1-65535", pm.ContainerPort)
+			}
+			// hostPort 0 asks the runtime for a random port.
+			if pm.HostPort < 0 || pm.HostPort > 65535 {
+				add(SeverityError, pmField+".hostPort", "hostPort %d is out of range 0-65535", pm.HostPort)
+			}
+			protocol := strings.ToUpper(pm.Protocol)
+			if protocol == "" {
+				protocol = "TCP"
+			}
+			if protocol != "TCP" && protocol != "UDP" && protocol != "SCTP" {
+				add(SeverityError, pmField+".protocol", "invalid protocol %q; must be TCP, UDP, or SCTP", pm.Protocol)
+			}
+			if pm.ListenAddress != "" && net.ParseIP(pm.ListenAddress) == nil {
+				add(SeverityError, pmField+".listenAddress", "listenAddress %q is not an IP address", pm.ListenAddress)
+			}
+			if pm.HostPort == 0 {
+				continue
+			}
+			key := fmt.Sprintf("%s/%d/%s", pm.ListenAddress, pm.HostPort, protocol)
+			if prev, ok := hostPorts[key]; ok {
+				add(SeverityError, pmField+".hostPort", "hostPort %d/%s is already mapped by %s", pm.HostPort, protocol, prev)
+			} else {
+				hostPorts[key] = pmField
+			}
+		}
+	}
+
+	report.Findings = append(report.Findings, versionSkewFindings(cfg)...)
+
+	if n := cfg.Networking; n != nil {
+		family := n.IPFamily
+		switch family {
+		case "", "ipv4", "ipv6", "dual":
+		default:
+			add(SeverityError, "networking.ipFamily", "invalid ipFamily %q; must be 'ipv4', 'ipv6', or 'dual'", family)
+		}
+		if (family == "ipv6" || family == "dual") && ri.Backend == rtdetect.BackendDockerDesktop {
+			add(SeverityWarning, "networking.ipFamily",
+				"Docker Desktop does not route IPv6 to the host; ipFamily %q needs IPv6 enabled in its "+
+					"daemon settings and port mappings may only work over IPv4", family)
+		}
+		for field, value := range map[string]string{"networking.podSubnet": n.PodSubnet, "networking.serviceSubnet": n.ServiceSubnet} {
+			if value == "" {
+				continue
+			}
+			if err := checkSubnets(value, family); err != nil {
+				add(SeverityError, field, "%v", err)
+			}
+		}
+		if n.APIServerAddress != "" && net.ParseIP(n.APIServerAddress) == nil {
+			add(SeverityError, "networking.apiServerAddress", "apiServerAddress %q is not an IP address", n.APIServerAddress)
+		}
+		if n.APIServerPort < 0 || n.APIServerPort > 65535 {
+			add(SeverityError, "networking.apiServerPort", "apiServerPort %d is out of range 0-65535", n.APIServerPort)
+		}
+		switch n.KubeProxyMode {
+		case "", "iptables", "ipvs", "nftables":
+		case "none":
+			if !n.DisableDefaultCNI {
+				add(SeverityWarning, "networking.kubeProxyMode",
+					"kubeProxyMode 'none' keeps the default CNI (kindnet), which relies on kube-proxy for "+
+						"Service routing; set disableDefaultCNI and install a kube-proxy replacement such as Cilium")
+			}
+		default:
+			add(SeverityError, "networking.kubeProxyMode",
+				"invalid kubeProxyMode %q; must be 'iptables', 'ipvs', 'nftables', or 'none'", n.KubeProxyMode)
+		}
+	}
+
+	report.Valid = true
+	for _, f := range report.Findings {
+		if f.Severity == SeverityError {
+			report.Valid = false
+		}
+	}
+	return report
+}
+
+// checkSubnets validates a pod or service subnet: one CIDR, or for the dual family a
+// comma-separated IPv4 and IPv6 pair, matching the cluster's IP family.
+func checkSubnets(value, family string) error {
+	var v4, v6 int
+	for _, cidr := range strings.Split(value, ",") {
+		ip, _, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return fmt.Errorf("%q is not a valid CIDR", strings.TrimSpace(cidr))
+		}
+		if ip.To4() != nil {
+			v4++
+		} else {
+			v6++
+		}
+	}
+	switch family {
+	case "", "ipv4":
+		if v6 > 0 || v4 != 1 {
+			return fmt.Errorf("%q must be a single IPv4 CIDR for the ipv4 family", value)
+		}
+	case "ipv6":
+		if v4 > 0 || v6 != 1 {
+			return fmt.Errorf("%q must be a single IPv6 CIDR for the ipv6 family", value)
+		}
+	case "dual":
+		if v4 != 1 || v6 != 1 {
+			return fmt.Errorf("%q must be an IPv4 and an IPv6 CIDR separated by a comma for the dual family", value)
+		}
+	}
+	return nil
+}
+
+var (
+	imageTagPattern    = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	imageDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	imageRepoPattern   = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+)
+
+// checkImageRef validates the format of a node image reference such as
+// registry:5000/kindest/node:v1.31.0@sha256:<digest>.
+func checkImageRef(image string) error {
+	ref, digest, hasDigest := strings.Cut(image, "@")
+	if hasDigest && !imageDigestPattern.MatchString(digest) {
+		return fmt.Errorf("image %q has an invalid digest; expected sha256:<64 hex characters>", image)
+	}
+	repo := ref
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		repo = ref[:i]
+		if !imageTagPattern.MatchString(ref[i+1:]) {
+			return fmt.Errorf("image %q has an invalid tag %q", image, ref[i+1:])
+		}
+	}
+	// The first component may be a registry host with a port and upper-case letters.
+	path := repo
+	if host, rest, ok := strings.Cut(repo, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		path = rest
+	}
+	if !imageRepoPattern.MatchString(path) {
+		return fmt.Errorf("image %q is not a valid image reference", image)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func TestCheckConfig_AllFindings(t *testing.T) {
+	cfg := `kind: Pod
+apiVersion: v1
+nodes:
+  - role: bogus
+  - role: worker
+`
+	report := CheckConfig(cfg, rtdetect.RuntimeInfo{})
+
+	if report.Valid {
+		t.Error("expected invalid config")
+	}
+	fields := map[string]bool{}
+	for _, f := range report.Findings {
+		fields[f.Field] = true
+	}
+	for _, want := range []string{"kind", "apiVersion", "nodes[0].role", "nodes"} {
+		if !fields[want] {
+			t.Errorf("missing finding for %q in %+v", want, report.Findings)
+		}
+	}
+}
+
+func TestCheckConfig_Warnings(t *testing.T) {
+	cfg := `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+networking:
+  ipFamily: ipv6
+nodes:
+  - role: control-plane
+  - role: control-plane
+    extraPortMappings:
+      - containerPort: 80
+        hostPort: 8080
+`
+	report := CheckConfig(cfg, rtdetect.RuntimeInfo{Backend: rtdetect.BackendDockerDesktop})
+
+	if !report.Valid {
+		t.Errorf("warnings alone should leave the config valid: %+v", report.Findings)
+	}
+	want := map[string]bool{"nodes[1].extraPortMappings": false, "networking.ipFamily": false}
+	for _, f := range report.Findings {
+		if f.Severity != SeverityWarning {
+			t.Errorf("unexpected %s: %s", f.Severity, f.Message)
+		}
+		want[f.Field] = true
+	}
+	for field, found := range want {
+		if !found {
+			t.Errorf("missing warning for %s", field)
+		}
+	}
+}
+
+func TestCheckConfig_Semantic(t *testing.T) {
+	cfg := `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+networking:
+  ipFamily: ipv4
+  podSubnet: 10.244.0.0/33
+  serviceSubnet: fd00:10:96::/112
+  kubeProxyMode: none
+nodes:
+  - role: control-plane
+    image: kindest/node
+    extraPortMappings:
+      - containerPort: 80
+        hostPort: 8080
+      - containerPort: 70000
+        hostPort: 8080
+        protocol: HTTP
+  - role: worker
+    image: Kindest/Node:v1.31.0
+`
+	report := CheckConfig(cfg, rtdetect.RuntimeInfo{})
+
+	got := map[string]string{}
+	for _, f := range report.Findings {
+		got[f.Field] = f.Severity
+	}
+	want := map[string]string{
+		"networking.podSubnet":                        SeverityError,
+		"networking.serviceSubnet":                    SeverityError,
+		"networking.kubeProxyMode":                    SeverityWarning,
+		"nodes[0].image":                              SeverityWarning,
+		"nodes[0].extraPortMappings[1].containerPort": SeverityError,
+		"nodes[0].extraPortMappings[1].protocol":      SeverityError,
+		"nodes[1].image":                              SeverityError,
+	}
+	for field, severity := range want {
+		if got[field] != severity {
+			t.Errorf("%s: got %q, want %q (findings: %+v)", field, got[field], severity, report.Findings)
+		}
+	}
+	if report.Valid {
+		t.Error("expected invalid config")
+	}
+}
+
+func TestCheckConfig_DuplicateHostPort(t *testing.T) {
+	cfg := `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+  - role: control-plane
+    extraPortMappings:
+      - {containerPort: 80, hostPort: 80}
+      - {containerPort: 80, hostPort: 80, protocol: UDP}
+  - role: worker
+    extraPortMappings:
+      - {containerPort: 30080, hostPort: 80, protocol: tcp}
+`
+	report := CheckConfig(cfg, rtdetect.RuntimeInfo{})
+
+	if len(report.Findings) != 1 || report.Findings[0].Field != "nodes[1].extraPortMappings[0].hostPort" {
+		t.Errorf("findings = %+v", report.Findings)
+	}
+}
+
+func TestCheckSubnets(t *testing.T) {
+	tests := []struct {
+		value, family string
+		ok            bool
+	}{
+		{"10.244.0.0/16", "", true},
+		{"10.244.0.0/16,fd00:10:244::/56", "dual", true},
+		{"10.244.0.0/16", "dual", false},
+		{"fd00:10:244::/56", "ipv6", true},
+		{"10.244.0.0", "ipv4", false},
+	}
+	for _, tt := range tests {
+		if err := checkSubnets(tt.value, tt.family); (err == nil) != tt.ok {
+			t.Errorf("checkSubnets(%q, %q) = %v, want ok=%t", tt.value, tt.family, err, tt.ok)
+		}
+	}
+}
+
+func TestCheckImageRef(t *testing.T) {
+	valid := []string{
+		"kindest/node:v1.31.0",
+		"registry.corp:5000/kind/node:v1.31.0",
+		"localhost/kind/node:dev",
+		"kindest/node:v1.31.0@sha256:" + strings.Repeat("a", 64),
+	}
+	for _, image := range valid {
+		if err := checkImageRef(image); err != nil {
+			t.Errorf("checkImageRef(%q) = %v", image, err)
+		}
+	}
+	invalid := []string{"kindest/node:v1.31.0@sha256:abc", "kindest/node:", "kindest//node", "kindest/node:bad tag"}
+	for _, image := range invalid {
+		if err := checkImageRef(image); err == nil {
+			t.Errorf("checkImageRef(%q) = nil, want error", image)
+		}
+	}
+}
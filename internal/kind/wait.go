@@ -0,0 +1,236 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// defaultWaitTimeout bounds WaitFor when the caller sets no timeout.
+const defaultWaitTimeout = 2 * time.Minute
+
+// WaitTarget names the resources WaitFor waits on and the condition to wait for.
+type WaitTarget struct {
+	// Kind is the resource type, e.g. "deployment", "pod", "job" or "crd".
+	Kind string
+	// Name selects one resource; Selector selects resources by label instead.
+	Name      string
+	Selector  string
+	Namespace string
+	// Condition is a status condition type such as "Available", "Ready" or
+	// "Complete", optionally with the expected status ("Ready=False"); "delete" or
+	// "create"; or a "jsonpath={.status.phase}=Running" expression.
+	Condition string
+}
+
+func (t WaitTarget) validate() error {
+	switch {
+	case t.Kind == "":
+		return fmt.Errorf("resource kind is required")
+	case t.Name == "" && t.Selector == "":
+		return fmt.Errorf("a resource name or a label selector is required")
+	case t.Name != "" && t.Selector != "":
+		return fmt.Errorf("set either a resource name or a label selector, not both")
+	case t.Condition == "":
+		return fmt.Errorf("a condition is required")
+	}
+	return nil
+}
+
+// ref returns the resource in kubectl's form: kind/name, or the kind for a selector.
+func (t WaitTarget) ref() string {
+	if t.Name == "" {
+		return t.Kind
+	}
+	return t.Kind + "/" + t.Name
+}
+
+// forFlag returns the condition in the form of kubectl wait --for.
+func (t WaitTarget) forFlag() string {
+	c := t.Condition
+	switch {
+	case c == "delete", c == "create", strings.HasPrefix(c, "jsonpath="), strings.HasPrefix(c, "condition="):
+		return c
+	}
+	return "condition=" + c
+}
+
+// ResourceCondition is a status condition of a resource WaitFor timed out on.
+type ResourceCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// WaitResult reports whether a wait condition was met.
+type WaitResult struct {
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	For       string `json:"for"`
+	Met       bool   `json:"met"`
+	TimedOut  bool   `json:"timed_out"`
+	// ElapsedSeconds is how long the wait took.
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	// Message summarizes the outcome, e.g. "deployment/web condition met".
+	Message string `json:"message"`
+	// Conditions are the resource's current status conditions when the wait timed out,
+	// to show what it is stuck on.
+	Conditions []ResourceCondition `json:"conditions,omitempty"`
+}
+
+// WaitFor watches resources with client-go until they meet a condition or timeout
+// passes, so a caller can block on a workload becoming ready or a job completing
+// instead of polling. With a selector every matching resource must meet it. A
+// condition not met at the timeout is reported with TimedOut set, not as an error.
+func (m *Manager) WaitFor(ctx context.Context, clusterName string, target WaitTarget, timeout time.Duration) (*WaitResult, error) {
+	if err := target.validate(); err != nil {
+		return nil, err
+	}
+	met, err := target.matcher()
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+	clients, err := m.clusterClients(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := clients.resource(target.Kind, target.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WaitResult{Resource: target.ref(), Namespace: target.Namespace, For: target.forFlag()}
+	if result.Namespace == "" {
+		result.Namespace = "default"
+	}
+	start := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	listed := false
+	objects, err := watchObjects(waitCtx, res, target.Name, target.Selector, func(objects objectSet) (bool, error) {
+		first := !listed
+		listed = true
+		switch target.Condition {
+		case "delete":
+			return len(objects) == 0, nil
+		case "create":
+			return len(objects) > 0, nil
+		}
+		if len(objects) == 0 {
+			if first {
+				return false, fmt.Errorf("not found; wait for condition 'create' first")
+			}
+			return false, nil
+		}
+		for _, obj := range objects {
+			if ok, err := met(obj); !ok || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	result.ElapsedSeconds = time.Since(start).Round(100 * time.Millisecond).Seconds()
+	switch {
+	case err == nil:
+		result.Met = true
+		result.Message = target.describe() + " condition met"
+		return result, nil
+	case ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded):
+		result.TimedOut = true
+		result.Message = "timed out waiting for the condition on " + target.describe()
+		if obj := objects[target.Name]; target.Name != "" && obj != nil {
+			result.Conditions = resourceConditions(obj)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("waiting for %s: %w", target.describe(), err)
+}
+
+// describe names the resources of the target for messages.
+func (t WaitTarget) describe() string {
+	if t.Name != "" {
+		return t.ref()
+	}
+	return fmt.Sprintf("%s matching %q", t.Kind, t.Selector)
+}
+
+// matcher returns the check for the target's condition. "delete" and "create" are
+// checked on the whole set of resources by WaitFor instead.
+func (t WaitTarget) matcher() (func(*unstructured.Unstructured) (bool, error), error) {
+	c := t.forFlag()
+	if expr, ok := strings.CutPrefix(c, "jsonpath="); ok {
+		return jsonPathMatcher(expr)
+	}
+	condType, status, _ := strings.Cut(strings.TrimPrefix(c, "condition="), "=")
+	if status == "" {
+		status = "True"
+	}
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		for _, cond := range resourceConditions(obj) {
+			if strings.EqualFold(cond.Type, condType) {
+				return strings.EqualFold(cond.Status, status), nil
+			}
+		}
+		return false, nil
+	}, nil
+}
+
+// jsonPathMatcher checks a kubectl wait JSONPath expression, "{.status.phase}=Running"
+// or just "{.status.readyReplicas}" for a field that is set.
+func jsonPathMatcher(expr string) (func(*unstructured.Unstructured) (bool, error), error) {
+	expr = strings.Trim(expr, "'")
+	end := strings.LastIndex(expr, "}")
+	if !strings.HasPrefix(expr, "{") || end < 0 {
+		return nil, fmt.Errorf("invalid JSONPath condition %q; expected e.g. 'jsonpath={.status.phase}=Running'", expr)
+	}
+	want, hasValue := strings.CutPrefix(expr[end+1:], "=")
+	if !hasValue && expr[end+1:] != "" {
+		return nil, fmt.Errorf("invalid JSONPath condition %q; expected e.g. 'jsonpath={.status.phase}=Running'", expr)
+	}
+	want = strings.Trim(want, "'\"")
+	parser := jsonpath.New("condition").AllowMissingKeys(true)
+	if err := parser.Parse(expr[:end+1]); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath condition %q: %w", expr, err)
+	}
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		results, err := parser.FindResults(obj.Object)
+		if err != nil {
+			return false, nil
+		}
+		for _, group := range results {
+			for _, v := range group {
+				got := fmt.Sprint(v.Interface())
+				if hasValue && got == want || !hasValue && got != "" {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}, nil
+}
+
+// resourceConditions returns the status conditions of a resource.
+func resourceConditions(obj *unstructured.Unstructured) []ResourceCondition {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var conditions []ResourceCondition
+	for _, item := range items {
+		c, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		str := func(key string) string { s, _ := c[key].(string); return s }
+		conditions = append(conditions, ResourceCondition{
+			Type: str("type"), Status: str("status"), Reason: str("reason"), Message: str("message"),
+		})
+	}
+	return conditions
+}
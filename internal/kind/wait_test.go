@@ -0,0 +1,112 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func conditions(conds ...map[string]any) map[string]any {
+	list := make([]any, len(conds))
+	for i, c := range conds {
+		list[i] = c
+	}
+	return map[string]any{"status": map[string]any{"conditions": list}}
+}
+
+func TestWaitFor_Met(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	web := object("apps/v1", "Deployment", "shop", "web", conditions(map[string]any{"type": "Available", "status": "False"}))
+	client, later := fakeClusters(t, m, web)
+	later(func() {
+		update(t, client, deploymentsResource,
+			object("apps/v1", "Deployment", "shop", "web", conditions(map[string]any{"type": "Available", "status": "True"})))
+	})
+
+	res, err := m.WaitFor(context.Background(), "dev",
+		WaitTarget{Kind: "deployment", Name: "web", Namespace: "shop", Condition: "Available"}, 30*time.Second)
+	if err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if !res.Met || res.TimedOut || res.Message != "deployment/web condition met" || res.For != "condition=Available" {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestWaitFor_SelectorAndJSONPath(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	pod := func(name, app, phase string) *unstructured.Unstructured {
+		p := object("v1", "Pod", "default", name, map[string]any{"status": map[string]any{"phase": phase}})
+		p.SetLabels(map[string]string{"app": app})
+		return p
+	}
+	client, later := fakeClusters(t, m, pod("web-1", "web", "Running"), pod("web-2", "web", "Pending"),
+		pod("db-1", "db", "Pending"))
+	later(func() { update(t, client, podsResource, pod("web-2", "web", "Running")) })
+
+	res, err := m.WaitFor(context.Background(), "dev",
+		WaitTarget{Kind: "pod", Selector: "app=web", Condition: "jsonpath={.status.phase}=Running"}, 0)
+	if err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if !res.Met || res.Namespace != "default" || res.Resource != "pod" {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestWaitFor_TimedOut(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	fakeClusters(t, m, object("batch/v1", "Job", "default", "migrate", conditions(map[string]any{
+		"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded",
+		"message": "Job has reached the specified backoff limit"})))
+
+	res, err := m.WaitFor(context.Background(), "dev",
+		WaitTarget{Kind: "job", Name: "migrate", Condition: "Complete"}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitFor: %v", err)
+	}
+	if res.Met || !res.TimedOut || len(res.Conditions) != 1 || res.Conditions[0].Reason != "BackoffLimitExceeded" {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestWaitFor_Delete(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	client, later := fakeClusters(t, m, object("v1", "Pod", "default", "web", nil))
+	later(func() {
+		if err := client.Resource(podsResource).Namespace("default").Delete(context.Background(), "web", metav1.DeleteOptions{}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	res, err := m.WaitFor(context.Background(), "dev", WaitTarget{Kind: "pod", Name: "web", Condition: "delete"}, 30*time.Second)
+	if err != nil || !res.Met {
+		t.Errorf("WaitFor = %+v, %v", res, err)
+	}
+}
+
+func TestWaitFor_Errors(t *testing.T) {
+	m := newDockerManager(&mockRunner{})
+	fakeClusters(t, m)
+	_, err := m.WaitFor(context.Background(), "dev", WaitTarget{Kind: "deployment", Name: "web", Condition: "Available"}, 0)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("err = %v", err)
+	}
+
+	for _, target := range []WaitTarget{
+		{Name: "web", Condition: "Ready"},
+		{Kind: "pod", Condition: "Ready"},
+		{Kind: "pod", Name: "web", Selector: "app=web", Condition: "Ready"},
+		{Kind: "pod", Name: "web"},
+		{Kind: "pod", Name: "web", Condition: "jsonpath=.status.phase"},
+		{Kind: "widget", Name: "web", Condition: "Ready"},
+	} {
+		if _, err := m.WaitFor(context.Background(), "dev", target, 0); err == nil {
+			t.Errorf("%+v: expected an error", target)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package kindbin knows which Kubernetes versions each kind release supports, so that
+// a requested node image can be checked against the installed kind binary.
+package kindbin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	kinddefaults "sigs.k8s.io/kind/pkg/apis/config/defaults"
+)
+
+// Release is a kind release and the Kubernetes versions of the kindest/node images
+// published with it, newest first, as listed in its release notes.
+type Release struct {
+	Version string
+	Images  []string
+}
+
+// Releases is the compatibility table, newest release first. Node images are built
+// for the kind release they are published with; older images usually work with newer
+// kind, while images of a newer Kubernetes minor than a release supports do not.
+var Releases = []Release{
+	{Version: "v0.30.0", Images: []string{"v1.34.0", "v1.33.4", "v1.32.8", "v1.31.12"}},
+	{Version: "v0.29.0", Images: []string{"v1.33.1", "v1.32.5", "v1.31.9", "v1.30.13"}},
+	{Version: "v0.27.0", Images: []string{"v1.32.2", "v1.31.6", "v1.30.10", "v1.29.14"}},
+	{Version: "v0.26.0", Images: []string{"v1.32.0", "v1.31.4", "v1.30.8", "v1.29.12"}},
+	{Version: "v0.24.0", Images: []string{"v1.31.0", "v1.30.4", "v1.29.8", "v1.28.13"}},
+	{Version: "v0.23.0", Images: []string{"v1.30.0", "v1.29.4", "v1.28.9", "v1.27.13"}},
+	{Version: "v0.22.0", Images: []string{"v1.29.2", "v1.28.7", "v1.27.11", "v1.26.14", "v1.25.16"}},
+}
+
+// Compatibility levels reported by Check.
+const (
+	// Supported means the image was published with the installed kind release.
+	Supported = "supported"
+	// Untested means the image's Kubernetes minor is in range, but it was not built for
+	// the installed release, or the release is not in the table.
+	Untested = "untested"
+	// Unsupported means the installed kind is too old for the Kubernetes minor.
+	Unsupported = "unsupported"
+)
+
+// Result is the outcome of checking a Kubernetes version against a kind release.
+type Result struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	// Suggested are node images to pin instead, with their digest when it is known.
+	Suggested []string `json:"suggested,omitempty"`
+}
+
+// version is a parsed vMAJOR.MINOR.PATCH version.
+type version [3]int
+
+func parseVersion(s string) (version, bool) {
+	var v version
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".", 3)
+	if len(parts) < 2 {
+		return v, false
+	}
+	for i, p := range parts {
+		// tolerate suffixes such as "-alpha.1"
+		p, _, _ = strings.Cut(p, "-")
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+func (v version) less(o version) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+func (v version) minor() string { return fmt.Sprintf("1.%d", v[1]) }
+
+// ParseKindVersion returns the release in the output of "kind version", e.g. "v0.30.0"
+// for "kind v0.30.0 go1.24.6 linux/amd64".
+func ParseKindVersion(output string) (string, error) {
+	for _, f := range strings.Fields(output) {
+		if _, ok := parseVersion(f); ok && strings.HasPrefix(f, "v") {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("no kind version in %q", strings.TrimSpace(output))
+}
+
+// Check reports whether the installed kind release supports node images of the given
+// Kubernetes version (e.g. "1.31.0" or "v1.31.0") and suggests images built for it.
+func Check(kindVersion, kubernetesVersion string) Result {
+	kindV, ok := parseVersion(kindVersion)
+	if !ok {
+		return Result{Level: Untested, Message: fmt.Sprintf("unrecognized kind version %q", kindVersion)}
+	}
+	k8s, ok := parseVersion(kubernetesVersion)
+	if !ok {
+		return Result{Level: Untested, Message: fmt.Sprintf("unrecognized Kubernetes version %q", kubernetesVersion)}
+	}
+	k8sTag := fmt.Sprintf("v%d.%d.%d", k8s[0], k8s[1], k8s[2])
+
+	rel, ok := releaseFor(kindV)
+	if !ok {
+		return Result{Level: Untested, Message: fmt.Sprintf("kind %s is older than every release in the compatibility table (%s)",
+			kindVersion, Releases[len(Releases)-1].Version)}
+	}
+	newest, _ := parseVersion(Releases[0].Version)
+	beyondTable := newest.less(kindV)
+
+	images := parsedImages(rel)
+	lowest, highest := images[len(images)-1], images[0]
+	switch {
+	case contains(rel.Images, k8sTag):
+		return Result{Level: Supported, Suggested: []string{pin(k8sTag)},
+			Message: fmt.Sprintf("kindest/node:%s was published with kind %s", k8sTag, rel.Version)}
+
+	case k8s[1] > highest[1] && !beyondTable:
+		r := Result{Level: Unsupported, Suggested: []string{pin(rel.Images[0])},
+			Message: fmt.Sprintf("kind %s supports Kubernetes up to %s, not %s", kindVersion, highest.minor(), k8s.minor())}
+		if first := firstReleaseWithMinor(k8s[1]); first != "" {
+			r.Message += fmt.Sprintf("; upgrade kind to %s or newer, or use an older Kubernetes version", first)
+		}
+		return r
+
+	case k8s[1] < lowest[1]:
+		return Result{Level: Untested, Suggested: []string{pin(rel.Images[len(rel.Images)-1])},
+			Message: fmt.Sprintf("Kubernetes %s is older than the images built for kind %s (oldest: %s) and may fail to start",
+				k8s.minor(), rel.Version, lowest.minor())}
+	}
+
+	r := Result{Level: Untested,
+		Message: fmt.Sprintf("kindest/node:%s was not published with kind %s", k8sTag, rel.Version)}
+	if beyondTable {
+		r.Message = fmt.Sprintf("kind %s is newer than the compatibility table (%s); check its release notes for node images",
+			kindVersion, Releases[0].Version)
+	}
+	for i, img := range images {
+		if img[1] == k8s[1] {
+			r.Suggested = []string{pin(rel.Images[i])}
+			r.Message += fmt.Sprintf("; the image built for it for Kubernetes %s is kindest/node:%s", k8s.minor(), rel.Images[i])
+		}
+	}
+	return r
+}
+
+// releaseFor returns the newest table release not newer than v.
+func releaseFor(v version) (Release, bool) {
+	for _, rel := range Releases {
+		if rv, _ := parseVersion(rel.Version); !v.less(rv) {
+			return rel, true
+		}
+	}
+	return Release{}, false
+}
+
+// firstReleaseWithMinor returns the oldest release with an image of Kubernetes 1.minor.
+func firstReleaseWithMinor(minor int) string {
+	first := ""
+	for _, rel := range Releases {
+		for _, img := range parsedImages(rel) {
+			if img[1] == minor {
+				first = rel.Version
+			}
+		}
+	}
+	return first
+}
+
+func parsedImages(rel Release) []version {
+	images := make([]version, len(rel.Images))
+	for i, img := range rel.Images {
+		images[i], _ = parseVersion(img)
+	}
+	return images
+}
+
+// pin returns the kindest/node image for a Kubernetes version. Only the digest of the
+// default image of the kind module this server is built with is known; resolve others
+// from the release notes before pinning them by digest.
+func pin(tag string) string {
+	image := "kindest/node:" + tag
+	if ref, digest, ok := strings.Cut(kinddefaults.Image, "@"); ok && ref == image {
+		return image + "@" + digest
+	}
+	return image
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
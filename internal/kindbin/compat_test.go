@@ -0,0 +1,49 @@
+package kindbin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseKindVersion(t *testing.T) {
+	got, err := ParseKindVersion("kind v0.30.0 go1.24.6 linux/amd64\n")
+	if err != nil || got != "v0.30.0" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := ParseKindVersion("command not found"); err == nil {
+		t.Error("expected error without a version")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		kind, k8s string
+		level     string
+		suggested string
+	}{
+		{"v0.30.0", "1.34.0", Supported, "kindest/node:v1.34.0@sha256:"},
+		{"v0.30.0", "v1.33.1", Untested, "kindest/node:v1.33.4"},
+		{"v0.27.0", "1.34.0", Unsupported, "kindest/node:v1.32.2"},
+		// releases missing from the table use the previous one
+		{"v0.28.0", "1.33.1", Unsupported, "kindest/node:v1.32.2"},
+		{"v0.30.0", "1.27.3", Untested, "kindest/node:v1.31.12"},
+		{"v0.99.0", "1.40.0", Untested, ""},
+		{"v0.10.0", "1.20.2", Untested, ""},
+	}
+	for _, tt := range tests {
+		r := Check(tt.kind, tt.k8s)
+		if r.Level != tt.level {
+			t.Errorf("Check(%s, %s) = %s (%s), want %s", tt.kind, tt.k8s, r.Level, r.Message, tt.level)
+		}
+		if tt.suggested != "" && (len(r.Suggested) == 0 || !strings.HasPrefix(r.Suggested[0], tt.suggested)) {
+			t.Errorf("Check(%s, %s) suggested %v, want %s", tt.kind, tt.k8s, r.Suggested, tt.suggested)
+		}
+	}
+}
+
+func TestCheck_UpgradeHint(t *testing.T) {
+	r := Check("v0.24.0", "1.33.1")
+	if !strings.Contains(r.Message, "upgrade kind to v0.29.0") {
+		t.Errorf("message = %s", r.Message)
+	}
+}
@@ -0,0 +1,130 @@
+// Package kustomize builds kustomizations with the kustomize API and applies the
+// result to Kind clusters with kubectl, optionally pruning or only diffing.
+package kustomize
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// Output is a built kustomization.
+type Output struct {
+	// YAML is the multi-document stream of the built resources.
+	YAML string
+	// Resources lists the built resources as kind/name or kind/namespace/name.
+	Resources []string
+}
+
+// Build builds the kustomization at target: a local directory, or a remote target
+// such as "https://github.com/org/repo//deploy/overlays/dev?ref=main" (remote targets
+// are fetched with git, which must be in PATH).
+func Build(target string) (*Output, error) {
+	if target == "" {
+		return nil, fmt.Errorf("kustomization path or URL is required")
+	}
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := k.Run(filesys.MakeFsOnDisk(), target)
+	if err != nil {
+		return nil, fmt.Errorf("building kustomization %s: %w", target, err)
+	}
+	yaml, err := resMap.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("encoding kustomization %s: %w", target, err)
+	}
+	out := &Output{YAML: string(yaml)}
+	for _, res := range resMap.Resources() {
+		id := res.GetKind() + "/" + res.GetName()
+		if ns := res.GetNamespace(); ns != "" {
+			id = res.GetKind() + "/" + ns + "/" + res.GetName()
+		}
+		out.Resources = append(out.Resources, id)
+	}
+	return out, nil
+}
+
+// Options configures Apply.
+type Options struct {
+	// Target is the kustomization directory or URL; see Build.
+	Target string
+	// Namespace is used for resources the kustomization leaves without one.
+	Namespace string
+	// Prune deletes resources matching PruneSelector that the kustomization no longer
+	// contains. kubectl then also applies only the resources matching PruneSelector.
+	Prune         bool
+	PruneSelector string
+	// Diff reports what applying would change instead of applying.
+	Diff bool
+}
+
+// Result is the outcome of Apply.
+type Result struct {
+	Target    string   `json:"target"`
+	Resources []string `json:"resources"`
+	Applied   bool     `json:"applied"`
+	// Changed reports, for a diff, whether applying would change the cluster.
+	Changed bool   `json:"changed,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+	Output  string `json:"output,omitempty"`
+}
+
+// Apply builds the kustomization opts.Target and applies it to a cluster, or with
+// opts.Diff returns the output of kubectl diff without changing the cluster.
+func Apply(ctx context.Context, mgr *kind.Manager, clusterName string, opts Options) (*Result, error) {
+	if opts.Prune && opts.PruneSelector == "" {
+		return nil, fmt.Errorf("prune requires a label selector naming the resources the kustomization manages")
+	}
+	built, err := Build(opts.Target)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Target: opts.Target, Resources: built.Resources}
+	if len(built.Resources) == 0 {
+		return res, fmt.Errorf("kustomization %s produced no resources", opts.Target)
+	}
+
+	var args []string
+	if opts.Prune {
+		args = append(args, "--prune", "-l", opts.PruneSelector)
+	}
+	if !opts.Diff {
+		if opts.Namespace != "" {
+			args = append(args, "--namespace", opts.Namespace)
+		}
+		res.Output, err = mgr.ApplyManifest(ctx, clusterName, built.YAML, args...)
+		if err != nil {
+			return res, err
+		}
+		res.Applied = true
+		return res, nil
+	}
+
+	f, err := os.CreateTemp("", "kustomize-*.yaml")
+	if err != nil {
+		return res, fmt.Errorf("creating temp manifest file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(built.YAML); err != nil {
+		f.Close()
+		return res, fmt.Errorf("writing manifest to temp file: %w", err)
+	}
+	f.Close()
+
+	diff, err := mgr.RunKubectl(ctx, clusterName, opts.Namespace, append([]string{"diff", "-f", f.Name()}, args...))
+	if err != nil {
+		return res, err
+	}
+	// kubectl diff exits 1 when there are differences and above 1 when it fails.
+	switch diff.ExitCode {
+	case 0:
+	case 1:
+		res.Changed, res.Diff = true, diff.Stdout
+	default:
+		return res, fmt.Errorf("kubectl diff failed (exit %d): %s", diff.ExitCode, diff.Stderr)
+	}
+	return res, nil
+}
@@ -0,0 +1,172 @@
+package kustomize
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// mockRunner records invocations and answers kubectl diff with diffErr.
+type mockRunner struct {
+	diffErr error
+	calls   []string
+}
+
+func (m *mockRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	m.calls = append(m.calls, strings.Join(append([]string{name}, args...), " "))
+	if name == "kind" {
+		return []byte("apiVersion: v1\n"), nil
+	}
+	return []byte("configured\n"), nil
+}
+
+func (m *mockRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	m.calls = append(m.calls, strings.Join(append([]string{name}, args...), " "))
+	if name == "kubectl" && slices.Contains(args, "diff") && m.diffErr != nil {
+		return []byte("+  replicas: 2\n"), []byte("diff failed\n"), m.diffErr
+	}
+	return nil, nil, nil
+}
+
+func (m *mockRunner) LookPath(name string) (string, error) {
+	return "/usr/bin/" + name, nil
+}
+
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	if err == nil {
+		t.Fatal("expected command to fail")
+	}
+	return err
+}
+
+// writeOverlay writes a base with a Deployment and an overlay that sets its
+// namespace and replicas, and returns the overlay directory.
+func writeOverlay(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"base/kustomization.yaml": "resources:\n- deployment.yaml\n",
+		"base/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  selector:
+    matchLabels: {app: web}
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`,
+		"overlays/dev/kustomization.yaml": `namespace: dev
+labels:
+- pairs: {env: dev}
+  includeSelectors: false
+resources:
+- ../../base
+replicas:
+- name: web
+  count: 2
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "overlays", "dev")
+}
+
+func TestBuild(t *testing.T) {
+	out, err := Build(writeOverlay(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Resources) != 1 || out.Resources[0] != "Deployment/dev/web" {
+		t.Errorf("resources = %v", out.Resources)
+	}
+	for _, want := range []string{"namespace: dev", "replicas: 2", "env: dev"} {
+		if !strings.Contains(out.YAML, want) {
+			t.Errorf("built YAML lacks %q:\n%s", want, out.YAML)
+		}
+	}
+	if _, err := Build(t.TempDir()); err == nil {
+		t.Error("expected an error for a directory without a kustomization")
+	}
+	if _, err := Build(""); err == nil {
+		t.Error("expected an error without a target")
+	}
+}
+
+func newManager(runner *mockRunner) *kind.Manager {
+	return kind.NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+}
+
+func TestApply(t *testing.T) {
+	runner := &mockRunner{}
+	res, err := Apply(context.Background(), newManager(runner), "dev", Options{
+		Target: writeOverlay(t), Namespace: "apps", Prune: true, PruneSelector: "env=dev",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Applied || res.Output != "configured\n" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	last := runner.calls[len(runner.calls)-1]
+	if !strings.HasPrefix(last, "kubectl --kubeconfig") || !strings.Contains(last, " apply -f ") ||
+		!strings.HasSuffix(last, "--prune -l env=dev --namespace apps") {
+		t.Errorf("unexpected apply call: %s", last)
+	}
+
+	if _, err := Apply(context.Background(), newManager(&mockRunner{}), "dev",
+		Options{Target: writeOverlay(t), Prune: true}); err == nil {
+		t.Error("expected prune without a selector to fail")
+	}
+}
+
+func TestApply_Diff(t *testing.T) {
+	target := writeOverlay(t)
+	runner := &mockRunner{diffErr: exitError(t, 1)}
+	res, err := Apply(context.Background(), newManager(runner), "dev", Options{Target: target, Diff: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Applied || !res.Changed || res.Diff != "+  replicas: 2\n" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	for _, c := range runner.calls {
+		if strings.Contains(c, " apply ") {
+			t.Errorf("diff must not apply: %s", c)
+		}
+	}
+
+	res, err = Apply(context.Background(), newManager(&mockRunner{}), "dev", Options{Target: target, Diff: true})
+	if err != nil || res.Changed {
+		t.Errorf("expected no changes, got %+v, %v", res, err)
+	}
+
+	runner = &mockRunner{diffErr: exitError(t, 2)}
+	if _, err := Apply(context.Background(), newManager(runner), "dev", Options{Target: target, Diff: true}); err == nil ||
+		!strings.Contains(err.Error(), "diff failed") {
+		t.Errorf("expected kubectl diff failure, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+// Package limiter bounds how many heavy operations (cluster creation, image loading,
+// workload export) run at once, queueing the rest in arrival order.
+package limiter
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Limiter admits up to a fixed number of holders at a time; later callers wait in a
+// FIFO queue. It is safe for concurrent use.
+type Limiter struct {
+	mu      sync.Mutex
+	limit   int
+	running int
+	queue   []*waiter
+}
+
+type waiter struct {
+	ready chan struct{} // closed when the waiter is admitted
+	moved chan struct{} // signalled when the waiter moves up the queue
+}
+
+// New returns a Limiter admitting limit holders at once; limit < 1 means no limit.
+func New(limit int) *Limiter {
+	return &Limiter{limit: limit}
+}
+
+// Limit returns the number of holders admitted at once, or 0 for no limit.
+func (l *Limiter) Limit() int {
+	if l.limit < 1 {
+		return 0
+	}
+	return l.limit
+}
+
+// Stats returns the number of running holders and queued callers.
+func (l *Limiter) Stats() (running, queued int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running, len(l.queue)
+}
+
+// Acquire waits for a free slot and returns the func that releases it, which must be
+// called exactly once. While queued, onMove (if non-nil) is called from the calling
+// goroutine with the caller's 1-based queue position when it joins and whenever it
+// moves up. If ctx ends first, Acquire leaves the queue and returns ctx.Err().
+func (l *Limiter) Acquire(ctx context.Context, onMove func(position int)) (func(), error) {
+	if l.limit < 1 {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	if l.running < l.limit && len(l.queue) == 0 {
+		l.running++
+		l.mu.Unlock()
+		return l.releaser(), nil
+	}
+	w := &waiter{ready: make(chan struct{}), moved: make(chan struct{}, 1)}
+	l.queue = append(l.queue, w)
+	position := len(l.queue)
+	l.mu.Unlock()
+
+	reported := 0
+	for {
+		if onMove != nil && position != reported {
+			onMove(position)
+			reported = position
+		}
+		select {
+		case <-w.ready:
+			return l.releaser(), nil
+		case <-w.moved:
+			l.mu.Lock()
+			if i := slices.Index(l.queue, w); i >= 0 {
+				position = i + 1
+			}
+			l.mu.Unlock()
+		case <-ctx.Done():
+			l.mu.Lock()
+			if i := slices.Index(l.queue, w); i >= 0 {
+				l.queue = slices.Delete(l.queue, i, i+1)
+				signal(l.queue[i:])
+				l.mu.Unlock()
+				return nil, ctx.Err()
+			}
+			l.mu.Unlock()
+			// Admitted while giving up: pass the slot on.
+			l.releaser()()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// releaser returns a func that hands the caller's slot to the first queued waiter, or
+// frees it; calls after the first do nothing.
+func (l *Limiter) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if len(l.queue) == 0 {
+				l.running--
+				return
+			}
+			next := l.queue[0]
+			l.queue = l.queue[1:]
+			close(next.ready)
+			signal(l.queue)
+		})
+	}
+}
+
+// signal tells waiters they moved up the queue without blocking; a waiter that has not
+// yet handled an earlier signal reads its position once for both.
+func signal(waiters []*waiter) {
+	for _, w := range waiters {
+		select {
+		case w.moved <- struct{}{}:
+		default:
+		}
+	}
+}
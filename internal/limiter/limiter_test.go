@@ -0,0 +1,129 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// positionLog records the positions reported to one waiter.
+type positionLog struct {
+	mu        sync.Mutex
+	positions []int
+}
+
+func (p *positionLog) record(pos int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.positions = append(p.positions, pos)
+}
+
+func (p *positionLog) last() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.positions) == 0 {
+		return 0
+	}
+	return p.positions[len(p.positions)-1]
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiter_QueuesInOrder(t *testing.T) {
+	l := New(1)
+	release, err := l.Acquire(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logs := []*positionLog{{}, {}}
+	admitted := []chan func(){make(chan func(), 1), make(chan func(), 1)}
+	for i := range logs {
+		go func() {
+			rel, err := l.Acquire(context.Background(), logs[i].record)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			admitted[i] <- rel
+		}()
+		waitFor(t, func() bool { return logs[i].last() == i+1 })
+	}
+	if running, queued := l.Stats(); running != 1 || queued != 2 {
+		t.Errorf("stats = %d running, %d queued", running, queued)
+	}
+
+	release()
+	release() // a second call must not free another slot
+	releaseFirst := <-admitted[0]
+	waitFor(t, func() bool { return logs[1].last() == 1 })
+	select {
+	case <-admitted[1]:
+		t.Fatal("second waiter admitted while the first holds the slot")
+	default:
+	}
+	releaseFirst()
+	(<-admitted[1])()
+
+	if got := logs[1].positions; len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("second waiter positions = %v, want [2 1]", got)
+	}
+	if running, queued := l.Stats(); running != 0 || queued != 0 {
+		t.Errorf("stats after release = %d running, %d queued", running, queued)
+	}
+}
+
+func TestLimiter_CancelLeavesQueue(t *testing.T) {
+	l := New(1)
+	release, _ := l.Acquire(context.Background(), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first, second := &positionLog{}, &positionLog{}
+	errc := make(chan error)
+	go func() {
+		_, err := l.Acquire(ctx, first.record)
+		errc <- err
+	}()
+	waitFor(t, func() bool { return first.last() == 1 })
+	go func() {
+		rel, err := l.Acquire(context.Background(), second.record)
+		if err == nil {
+			rel()
+		}
+		errc <- err
+	}()
+	waitFor(t, func() bool { return second.last() == 2 })
+
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("cancelled Acquire err = %v", err)
+	}
+	waitFor(t, func() bool { return second.last() == 1 })
+	release()
+	if err := <-errc; err != nil {
+		t.Errorf("second Acquire err = %v", err)
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := New(0)
+	for range 5 {
+		if _, err := l.Acquire(context.Background(), func(int) { t.Error("unlimited limiter queued") }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if l.Limit() != 0 {
+		t.Errorf("Limit() = %d", l.Limit())
+	}
+}
@@ -0,0 +1,124 @@
+// Package logging builds the server's slog logger from the environment and carries
+// per-call loggers through contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Defaults for LOG_FILE rotation.
+const (
+	DefaultMaxSizeMB  = 10
+	DefaultMaxBackups = 3
+)
+
+// Config selects the level, format and destination of the server log.
+type Config struct {
+	Level slog.Level
+	// Format is "json" (default) or "text".
+	Format string
+	// File is the log file path; empty logs to stderr.
+	File string
+	// MaxSizeMB is the size at which File is rotated.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files kept (File.1 is the newest).
+	MaxBackups int
+}
+
+// ConfigFromEnv reads LOG_LEVEL, LOG_FORMAT, LOG_FILE, LOG_MAX_SIZE_MB and LOG_MAX_BACKUPS.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Level:      parseLevel(os.Getenv("LOG_LEVEL")),
+		Format:     strings.ToLower(os.Getenv("LOG_FORMAT")),
+		File:       os.Getenv("LOG_FILE"),
+		MaxSizeMB:  DefaultMaxSizeMB,
+		MaxBackups: DefaultMaxBackups,
+	}
+	switch cfg.Format {
+	case "":
+		cfg.Format = "json"
+	case "json", "text":
+	default:
+		return cfg, fmt.Errorf("LOG_FORMAT must be 'json' or 'text', got %q", cfg.Format)
+	}
+	var err error
+	if cfg.MaxSizeMB, err = envInt("LOG_MAX_SIZE_MB", DefaultMaxSizeMB, 1); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxBackups, err = envInt("LOG_MAX_BACKUPS", DefaultMaxBackups, 0); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func envInt(name string, def, min int) (int, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < min {
+		return 0, fmt.Errorf("%s must be an integer >= %d, got %q", name, min, raw)
+	}
+	return n, nil
+}
+
+func parseLevel(s string) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// New returns a logger for cfg and a closer for its output. Logging to a file rotates
+// it once it reaches cfg.MaxSizeMB.
+func New(cfg Config) (*slog.Logger, io.Closer, error) {
+	var out io.WriteCloser = nopCloser{os.Stderr}
+	if cfg.File != "" {
+		f, err := OpenRotatingFile(cfg.File, int64(cfg.MaxSizeMB)<<20, cfg.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = f
+	}
+
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+	var h slog.Handler
+	if cfg.Format == "text" {
+		h = slog.NewTextHandler(out, opts)
+	} else {
+		h = slog.NewJSONHandler(out, opts)
+	}
+	return slog.New(h), out, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+type loggerKey struct{}
+
+// NewContext returns a context carrying logger, e.g. one annotated with the tool call.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or fallback if there is none.
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return fallback
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "DEBUG")
+	t.Setenv("LOG_FORMAT", "Text")
+	t.Setenv("LOG_FILE", "/var/log/mcp-kind.log")
+	t.Setenv("LOG_MAX_SIZE_MB", "5")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Level != slog.LevelDebug || cfg.Format != "text" || cfg.File != "/var/log/mcp-kind.log" ||
+		cfg.MaxSizeMB != 5 || cfg.MaxBackups != DefaultMaxBackups {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+func TestConfigFromEnv_Invalid(t *testing.T) {
+	for name, env := range map[string][2]string{
+		"format":  {"LOG_FORMAT", "xml"},
+		"size":    {"LOG_MAX_SIZE_MB", "0"},
+		"backups": {"LOG_MAX_BACKUPS", "many"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(env[0], env[1])
+			if _, err := ConfigFromEnv(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNew_TextFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	logger, closer, err := New(Config{Format: "text", File: path, MaxSizeMB: 1, MaxBackups: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("hello", "tool", "list_clusters")
+	closer.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "msg=hello tool=list_clusters") {
+		t.Errorf("log = %q", data)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.Close()
+
+	for file, want := range map[string]string{
+		path:        "dddddddd\n",
+		path + ".1": "cccccccc\n",
+		path + ".2": "bbbbbbbb\n",
+	} {
+		data, err := os.ReadFile(file)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(file), data, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("only MaxBackups rotated files should be kept")
+	}
+}
+
+func TestContextLogger(t *testing.T) {
+	fallback := slog.Default()
+	if FromContext(context.Background(), fallback) != fallback {
+		t.Error("expected fallback without a context logger")
+	}
+	l := fallback.With("tool", "x")
+	if FromContext(NewContext(context.Background(), l), fallback) != l {
+		t.Error("expected the context logger")
+	}
+}
@@ -0,0 +1,118 @@
+// Package output keeps large tool outputs within client context limits: it shortens
+// them to a head and tail and keeps the full text retrievable as an MCP resource.
+package output
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxBytes is the default size a tool output is shortened to.
+const DefaultMaxBytes = 64 << 10
+
+// URIPrefix starts the URI of every stored output, followed by its ID.
+const URIPrefix = "kind-output://"
+
+// Limits on what a Store keeps; the oldest outputs are evicted first.
+const (
+	maxEntries    = 32
+	maxTotalBytes = 64 << 20
+)
+
+// Entry is a stored full output.
+type Entry struct {
+	URI       string
+	Name      string
+	Text      string
+	CreatedAt time.Time
+}
+
+// Store holds the full text of shortened outputs, safe for concurrent use.
+type Store struct {
+	mu      sync.Mutex
+	entries map[string]Entry
+	order   []string // URIs, oldest first
+	total   int
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{entries: make(map[string]Entry)}
+}
+
+// Limit returns text unchanged if it fits in maxBytes (DefaultMaxBytes if not
+// positive). Otherwise it stores the full text under name and returns its head and
+// tail around a marker with the number of omitted bytes and the URI of the full output.
+func (s *Store) Limit(name, text string, maxBytes int) string {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if len(text) <= maxBytes {
+		return text
+	}
+
+	uri := s.put(name, text)
+	head := cutEnd(text, maxBytes/2)
+	tail := cutStart(text, len(text)-maxBytes/2)
+	return fmt.Sprintf("%s\n\n[... %d of %d bytes omitted; the full output is in resource %s ...]\n\n%s",
+		head, len(text)-len(head)-len(tail), len(text), uri, tail)
+}
+
+// Get returns the output stored under uri.
+func (s *Store) Get(uri string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[uri]
+	return e, ok
+}
+
+func (s *Store) put(name, text string) string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	e := Entry{URI: URIPrefix + hex.EncodeToString(id), Name: name, Text: text, CreatedAt: time.Now().UTC()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[e.URI] = e
+	s.order = append(s.order, e.URI)
+	s.total += len(text)
+	// Keep at least the newest entry, even when it alone exceeds the byte limit.
+	for len(s.order) > 1 && (len(s.order) > maxEntries || s.total > maxTotalBytes) {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		s.total -= len(s.entries[oldest].Text)
+		delete(s.entries, oldest)
+	}
+	return e.URI
+}
+
+// cutEnd returns the start of s up to n bytes, ending at the last newline in the second
+// half of that range when there is one, and never inside a UTF-8 sequence.
+func cutEnd(s string, n int) string {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	head := s[:n]
+	if i := strings.LastIndexByte(head, '\n'); i >= n/2 {
+		head = head[:i+1]
+	}
+	return head
+}
+
+// cutStart returns s from byte offset i, moved forward past the next newline in the
+// first half of the remainder when there is one, and never inside a UTF-8 sequence.
+func cutStart(s string, i int) string {
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	tail := s[i:]
+	if j := strings.IndexByte(tail, '\n'); j >= 0 && j < len(tail)/2 {
+		tail = tail[j+1:]
+	}
+	return tail
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLimit_Fits(t *testing.T) {
+	s := NewStore()
+	if got := s.Limit("kubectl", "short", 100); got != "short" {
+		t.Errorf("got %q", got)
+	}
+	if len(s.order) != 0 {
+		t.Error("outputs that fit should not be stored")
+	}
+}
+
+func TestLimit_HeadTail(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&b, "line %04d\n", i)
+	}
+	text := b.String()
+
+	s := NewStore()
+	got := s.Limit("kind create", text, 200)
+	if !strings.HasPrefix(got, "line 0000\n") || !strings.HasSuffix(got, "line 0999\n") {
+		t.Errorf("expected whole first and last lines:\n%s", got)
+	}
+	if len(got) > 200+200 {
+		t.Errorf("shortened output is %d bytes", len(got))
+	}
+
+	uri := regexp.MustCompile(`kind-output://[0-9a-f]+`).FindString(got)
+	if uri == "" {
+		t.Fatalf("marker missing resource URI:\n%s", got)
+	}
+	e, ok := s.Get(uri)
+	if !ok || e.Text != text || e.Name != "kind create" {
+		t.Errorf("stored entry = %+v, %v", e.Name, ok)
+	}
+}
+
+func TestLimit_UTF8(t *testing.T) {
+	text := strings.Repeat("é", 100) // 200 bytes, no newlines
+	got := NewStore().Limit("logs", text, 51)
+	head, _, _ := strings.Cut(got, "\n\n[")
+	if !strings.HasPrefix(text, head) || len(head)%2 != 0 {
+		t.Errorf("head split a rune: %q", head)
+	}
+}
+
+func TestStore_Eviction(t *testing.T) {
+	s := NewStore()
+	var first string
+	for i := 0; i < maxEntries+5; i++ {
+		out := s.Limit("x", strings.Repeat("a", 20), 10)
+		if i == 0 {
+			first = regexp.MustCompile(`kind-output://[0-9a-f]+`).FindString(out)
+		}
+	}
+	if len(s.entries) != maxEntries {
+		t.Errorf("kept %d entries, want %d", len(s.entries), maxEntries)
+	}
+	if _, ok := s.Get(first); ok {
+		t.Error("oldest entry should have been evicted")
+	}
+}
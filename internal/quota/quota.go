@@ -0,0 +1,166 @@
+// Package quota bounds cluster creation on shared machines: how many clusters may
+// exist in total and per owner, how many nodes a cluster may have, and how many
+// clusters may be created per hour, so a runaway client loop cannot exhaust the host.
+package quota
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Names of the quotas, as reported in ExceededError.Quota.
+const (
+	MaxClusters         = "max_clusters"
+	MaxClustersPerOwner = "max_clusters_per_owner"
+	MaxNodesPerCluster  = "max_nodes_per_cluster"
+	MaxCreatesPerHour   = "max_creates_per_hour"
+)
+
+// rateWindow is the window MaxCreatesPerHour counts creations in.
+const rateWindow = time.Hour
+
+// Limits are the quotas; 0 means no limit.
+type Limits struct {
+	MaxClusters         int
+	MaxClustersPerOwner int
+	MaxNodesPerCluster  int
+	MaxCreatesPerHour   int
+}
+
+// Enabled reports whether any limit is set.
+func (l Limits) Enabled() bool {
+	return l != Limits{}
+}
+
+// Usage describes a cluster creation: the clusters that exist and the new cluster.
+type Usage struct {
+	// Clusters is the number of existing clusters.
+	Clusters int
+	// Owner is the client creating the cluster; empty for calls over stdio, which
+	// are not subject to MaxClustersPerOwner.
+	Owner string
+	// OwnerClusters is the number of existing clusters Owner owns.
+	OwnerClusters int
+	// Nodes is the node count of the new cluster.
+	Nodes int
+}
+
+// ExceededError reports the quota a creation would exceed. It encodes as JSON for
+// structured tool results.
+type ExceededError struct {
+	Quota string `json:"quota"`
+	Limit int    `json:"limit"`
+	// Current is the usage the creation would bring the quota to.
+	Current int    `json:"current"`
+	Owner   string `json:"owner,omitempty"`
+	// RetryAfterSeconds is when the next creation is admitted, for MaxCreatesPerHour.
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
+}
+
+func (e *ExceededError) Error() string {
+	switch e.Quota {
+	case MaxClusters:
+		return fmt.Sprintf("quota exceeded: at most %d clusters may exist; delete a cluster first", e.Limit)
+	case MaxClustersPerOwner:
+		return fmt.Sprintf("quota exceeded: %q may own at most %d clusters; delete one of them first", e.Owner, e.Limit)
+	case MaxNodesPerCluster:
+		return fmt.Sprintf("quota exceeded: the cluster would have %d nodes, at most %d are allowed", e.Current, e.Limit)
+	case MaxCreatesPerHour:
+		return fmt.Sprintf("quota exceeded: at most %d clusters may be created per hour; retry in %ds",
+			e.Limit, e.RetryAfterSeconds)
+	}
+	return fmt.Sprintf("quota %s exceeded: limit %d", e.Quota, e.Limit)
+}
+
+// Enforcer checks creations against Limits. Besides the existing clusters it counts
+// the creations it admitted that have not finished yet, so concurrent creations
+// cannot together exceed a limit. It is safe for concurrent use.
+type Enforcer struct {
+	limits Limits
+	now    func() time.Time
+
+	mu        sync.Mutex
+	pending   int            // admitted creations not yet done
+	byOwner   map[string]int // pending creations per owner
+	creations []time.Time    // admissions within rateWindow, oldest first
+}
+
+// New returns an Enforcer of limits.
+func New(limits Limits) *Enforcer {
+	return &Enforcer{limits: limits, now: time.Now, byOwner: make(map[string]int)}
+}
+
+// Limits returns the enforced limits.
+func (e *Enforcer) Limits() Limits {
+	return e.limits
+}
+
+// Check returns an *ExceededError if a creation described by u would exceed a limit,
+// without admitting it; use it for dry runs.
+func (e *Enforcer) Check(u Usage) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.check(u, e.now())
+}
+
+// Admit checks a creation like Check and, when it is within the limits, counts it
+// against them until the returned done func is called, which must happen once the
+// creation has finished (successfully or not). Admitted creations count against
+// MaxCreatesPerHour for an hour whether or not they succeed.
+func (e *Enforcer) Admit(u Usage) (done func(), err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	if err := e.check(u, now); err != nil {
+		return nil, err
+	}
+	if e.limits.MaxCreatesPerHour > 0 {
+		e.creations = append(e.creations, now)
+	}
+	e.pending++
+	e.byOwner[u.Owner]++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.pending--
+			if e.byOwner[u.Owner]--; e.byOwner[u.Owner] == 0 {
+				delete(e.byOwner, u.Owner)
+			}
+		})
+	}, nil
+}
+
+func (e *Enforcer) check(u Usage, now time.Time) error {
+	l := e.limits
+	if l.MaxNodesPerCluster > 0 && u.Nodes > l.MaxNodesPerCluster {
+		return &ExceededError{Quota: MaxNodesPerCluster, Limit: l.MaxNodesPerCluster, Current: u.Nodes}
+	}
+	if n := u.Clusters + e.pending + 1; l.MaxClusters > 0 && n > l.MaxClusters {
+		return &ExceededError{Quota: MaxClusters, Limit: l.MaxClusters, Current: n}
+	}
+	if n := u.OwnerClusters + e.byOwner[u.Owner] + 1; u.Owner != "" && l.MaxClustersPerOwner > 0 &&
+		n > l.MaxClustersPerOwner {
+		return &ExceededError{Quota: MaxClustersPerOwner, Limit: l.MaxClustersPerOwner, Current: n, Owner: u.Owner}
+	}
+	if l.MaxCreatesPerHour > 0 {
+		e.expire(now)
+		if len(e.creations) >= l.MaxCreatesPerHour {
+			retry := e.creations[0].Add(rateWindow).Sub(now)
+			return &ExceededError{Quota: MaxCreatesPerHour, Limit: l.MaxCreatesPerHour,
+				Current: len(e.creations) + 1, RetryAfterSeconds: int(retry.Seconds()) + 1}
+		}
+	}
+	return nil
+}
+
+// expire drops the creations older than rateWindow.
+func (e *Enforcer) expire(now time.Time) {
+	i := 0
+	for i < len(e.creations) && now.Sub(e.creations[i]) >= rateWindow {
+		i++
+	}
+	e.creations = e.creations[i:]
+}
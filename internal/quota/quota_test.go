@@ -0,0 +1,91 @@
+package quota
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func exceeded(t *testing.T, err error, quota string) *ExceededError {
+	t.Helper()
+	var qe *ExceededError
+	if !errors.As(err, &qe) || qe.Quota != quota {
+		t.Fatalf("expected %s to be exceeded, got %v", quota, err)
+	}
+	return qe
+}
+
+func TestAdmit_MaxClusters(t *testing.T) {
+	e := New(Limits{MaxClusters: 2})
+	done, err := e.Admit(Usage{Clusters: 1, Nodes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The pending creation counts as a cluster.
+	qe := exceeded(t, e.Check(Usage{Clusters: 1, Nodes: 1}), MaxClusters)
+	if qe.Limit != 2 || qe.Current != 3 {
+		t.Errorf("unexpected error %+v", qe)
+	}
+	done()
+	done()
+	if err := e.Check(Usage{Clusters: 1}); err != nil {
+		t.Errorf("expected the finished creation to be released, got %v", err)
+	}
+}
+
+func TestAdmit_PerOwner(t *testing.T) {
+	e := New(Limits{MaxClustersPerOwner: 1})
+	if _, err := e.Admit(Usage{Owner: "alice", Clusters: 5}); err != nil {
+		t.Fatal(err)
+	}
+	qe := exceeded(t, e.Check(Usage{Owner: "alice"}), MaxClustersPerOwner)
+	if qe.Owner != "alice" {
+		t.Errorf("unexpected error %+v", qe)
+	}
+	exceeded(t, e.Check(Usage{Owner: "bob", OwnerClusters: 1}), MaxClustersPerOwner)
+	if err := e.Check(Usage{Owner: "bob"}); err != nil {
+		t.Errorf("bob owns no clusters: %v", err)
+	}
+	if err := e.Check(Usage{OwnerClusters: 3}); err != nil {
+		t.Errorf("calls without an owner are not limited per owner: %v", err)
+	}
+}
+
+func TestAdmit_MaxNodes(t *testing.T) {
+	e := New(Limits{MaxNodesPerCluster: 3})
+	if err := e.Check(Usage{Nodes: 3}); err != nil {
+		t.Fatal(err)
+	}
+	qe := exceeded(t, e.Check(Usage{Nodes: 4}), MaxNodesPerCluster)
+	if qe.Current != 4 || qe.Limit != 3 {
+		t.Errorf("unexpected error %+v", qe)
+	}
+}
+
+func TestAdmit_Rate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	e := New(Limits{MaxCreatesPerHour: 2})
+	e.now = func() time.Time { return now }
+	for range 2 {
+		done, err := e.Admit(Usage{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		done()
+		now = now.Add(10 * time.Minute)
+	}
+	qe := exceeded(t, e.Check(Usage{}), MaxCreatesPerHour)
+	if qe.RetryAfterSeconds != 40*60+1 {
+		t.Errorf("unexpected retry after %ds", qe.RetryAfterSeconds)
+	}
+	now = now.Add(40 * time.Minute)
+	if _, err := e.Admit(Usage{}); err != nil {
+		t.Errorf("expected the oldest creation to have expired, got %v", err)
+	}
+}
+
+func TestLimitsEnabled(t *testing.T) {
+	if (Limits{}).Enabled() || !(Limits{MaxCreatesPerHour: 1}).Enabled() {
+		t.Error("Enabled should report whether any limit is set")
+	}
+}
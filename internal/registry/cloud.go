@@ -0,0 +1,252 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// Where refreshed cloud credentials are installed.
+const (
+	CloudTargetNodes      = "nodes"       // kubelet's config.json on every node
+	CloudTargetPullSecret = "pull_secret" // a kubernetes.io/dockerconfigjson Secret per namespace
+	CloudTargetBoth       = "both"
+
+	// DefaultPullSecretName names the Secret written for CloudTargetPullSecret.
+	DefaultPullSecretName = "cloud-registry-credentials"
+
+	kubeletConfigPath = "/var/lib/kubelet/config.json"
+)
+
+// HelperCredential is a registry credential issued by a Docker credential helper.
+type HelperCredential struct {
+	Registry string `json:"registry"`
+	Helper   string `json:"helper"`
+	Username string `json:"username"`
+	Secret   string `json:"-"`
+}
+
+// CloudRefreshOptions controls RefreshCloudCredentials.
+type CloudRefreshOptions struct {
+	// Registries maps each registry to its credential helper (the part after
+	// "docker-credential-"). An empty helper is looked up in Helpers, then inferred
+	// from the registry host.
+	Registries map[string]string
+	// Helpers are the configured helpers by registry host, as returned by CloudHelpers.
+	Helpers map[string]string
+	// Target is CloudTargetNodes (default), CloudTargetPullSecret or CloudTargetBoth.
+	Target string
+	// Namespaces receive the pull secret. Default: "default".
+	Namespaces []string
+	// SecretName names the pull secret. Default: DefaultPullSecretName.
+	SecretName string
+}
+
+// CloudRefreshResult reports what RefreshCloudCredentials refreshed and installed.
+type CloudRefreshResult struct {
+	Credentials []HelperCredential `json:"credentials"`
+	Nodes       []string           `json:"nodes,omitempty"`
+	Secrets     []string           `json:"secrets,omitempty"`
+	Errors      []string           `json:"errors,omitempty"`
+	RefreshedAt time.Time          `json:"refreshed_at"`
+}
+
+// CloudHelpers returns the credential helper configured per registry in the credHelpers
+// of every Docker and Podman credential file, keyed by registry host.
+func CloudHelpers(ri rtdetect.RuntimeInfo) map[string]string {
+	helpers := make(map[string]string)
+	for _, candidate := range mergeCandidatePaths(ri) {
+		data, err := os.ReadFile(expandPath(candidate.path))
+		if err != nil {
+			continue
+		}
+		var cfg dockerConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			continue
+		}
+		for reg, helper := range cfg.CredHelpers {
+			host := registryHost(reg)
+			if _, ok := helpers[host]; !ok {
+				helpers[host] = helper
+			}
+		}
+	}
+	return helpers
+}
+
+// InferHelper returns the usual credential helper for a cloud registry host, or "" if
+// the host is not a known ECR, GCR/Artifact Registry or ACR endpoint.
+func InferHelper(registry string) string {
+	host := registryHost(registry)
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	switch {
+	case strings.Contains(host, ".dkr.ecr.") &&
+		(strings.HasSuffix(host, ".amazonaws.com") || strings.HasSuffix(host, ".amazonaws.com.cn")):
+		return "ecr-login"
+	case host == "gcr.io", strings.HasSuffix(host, ".gcr.io"), strings.HasSuffix(host, "-docker.pkg.dev"):
+		return "gcloud"
+	case strings.HasSuffix(host, ".azurecr.io"):
+		return "acr-env"
+	}
+	return ""
+}
+
+// GetHelperCredential asks docker-credential-<helper> for a fresh credential for registry.
+func GetHelperCredential(ctx context.Context, runner rtdetect.CommandRunner, registry, helper string) (*HelperCredential, error) {
+	bin := "docker-credential-" + helper
+	if _, err := runner.LookPath(bin); err != nil {
+		return nil, fmt.Errorf("credential helper %s not found in PATH", bin)
+	}
+	stdout, stderr, err := rtdetect.RunInput(ctx, runner, []byte(registry+"\n"), bin, "get")
+	if err != nil {
+		if msg := strings.TrimSpace(string(stderr) + " " + string(stdout)); msg != "" {
+			return nil, fmt.Errorf("%s get %s: %w: %s", bin, registry, err, msg)
+		}
+		return nil, fmt.Errorf("%s get %s: %w", bin, registry, err)
+	}
+
+	var resp struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("parsing %s output: %w", bin, err)
+	}
+	if resp.Secret == "" {
+		return nil, fmt.Errorf("%s returned no secret for %s", bin, registry)
+	}
+	// "<token>" marks an identity token, which kubelet cannot exchange for pulls.
+	if resp.Username == "" || resp.Username == "<token>" {
+		return nil, fmt.Errorf("%s returned an identity token for %s; kubelet needs a username and password", bin, registry)
+	}
+	return &HelperCredential{Registry: registry, Helper: helper, Username: resp.Username, Secret: resp.Secret}, nil
+}
+
+// InlineConfig builds a Docker config.json with inline auth entries for creds.
+func InlineConfig(creds []HelperCredential) ([]byte, error) {
+	auths := make(map[string]authEntry, len(creds))
+	for _, c := range creds {
+		auths[c.Registry] = authEntry{Auth: base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Secret))}
+	}
+	return json.MarshalIndent(dockerConfig{Auths: auths}, "", "\t")
+}
+
+// RefreshCloudCredentials fetches fresh tokens from the registries' credential helpers
+// and installs them as an inline config, on the nodes for kubelet and/or as pull
+// secrets. Registries whose helper fails are reported in Errors; it fails only when
+// no credential could be fetched or installed.
+func RefreshCloudCredentials(ctx context.Context, mgr *kind.Manager, runner rtdetect.CommandRunner, clusterName string, opts CloudRefreshOptions) (*CloudRefreshResult, error) {
+	if len(opts.Registries) == 0 {
+		return nil, errors.New("no registries to refresh; configure credHelpers or pass registries")
+	}
+	target := opts.Target
+	if target == "" {
+		target = CloudTargetNodes
+	}
+	if target != CloudTargetNodes && target != CloudTargetPullSecret && target != CloudTargetBoth {
+		return nil, fmt.Errorf("invalid target %q: must be %s, %s or %s",
+			target, CloudTargetNodes, CloudTargetPullSecret, CloudTargetBoth)
+	}
+
+	result := &CloudRefreshResult{RefreshedAt: time.Now().UTC()}
+	registries := make([]string, 0, len(opts.Registries))
+	for reg := range opts.Registries {
+		registries = append(registries, reg)
+	}
+	sort.Strings(registries)
+	for _, reg := range registries {
+		helper := opts.Registries[reg]
+		if helper == "" {
+			helper = opts.Helpers[registryHost(reg)]
+		}
+		if helper == "" {
+			helper = InferHelper(reg)
+		}
+		if helper == "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: no credential helper configured or known", reg))
+			continue
+		}
+		cred, err := GetHelperCredential(ctx, runner, reg, helper)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Credentials = append(result.Credentials, *cred)
+	}
+	if len(result.Credentials) == 0 {
+		return result, fmt.Errorf("no credentials refreshed: %s", strings.Join(result.Errors, "; "))
+	}
+
+	config, err := InlineConfig(result.Credentials)
+	if err != nil {
+		return result, fmt.Errorf("encoding credentials: %w", err)
+	}
+	if target == CloudTargetNodes || target == CloudTargetBoth {
+		if err := installNodeConfig(ctx, mgr, clusterName, config, result); err != nil {
+			return result, err
+		}
+	}
+	if target == CloudTargetPullSecret || target == CloudTargetBoth {
+		applyPullSecrets(ctx, mgr, clusterName, config, opts, result)
+	}
+	if len(result.Nodes) == 0 && len(result.Secrets) == 0 {
+		return result, fmt.Errorf("refreshed credentials could not be installed: %s", strings.Join(result.Errors, "; "))
+	}
+	return result, nil
+}
+
+// installNodeConfig replaces kubelet's config.json on every node. kubelet re-reads the
+// file for later pulls, so no restart is needed.
+func installNodeConfig(ctx context.Context, mgr *kind.Manager, clusterName string, config []byte, result *CloudRefreshResult) error {
+	nodes, err := mgr.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return fmt.Errorf("getting cluster nodes: %w", err)
+	}
+	script := fmt.Sprintf("echo %s | base64 -d > %s.tmp && chmod 600 %s.tmp && mv %s.tmp %s",
+		base64.StdEncoding.EncodeToString(config), kubeletConfigPath, kubeletConfigPath, kubeletConfigPath, kubeletConfigPath)
+	for _, node := range filterNodes(nodes, "all") {
+		if _, err := mgr.ExecOnNode(ctx, node, []string{"sh", "-c", script}); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: writing %s: %v", node, kubeletConfigPath, err))
+			continue
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+	return nil
+}
+
+// applyPullSecrets writes config as a dockerconfigjson Secret in each namespace.
+func applyPullSecrets(ctx context.Context, mgr *kind.Manager, clusterName string, config []byte, opts CloudRefreshOptions, result *CloudRefreshResult) {
+	name := opts.SecretName
+	if name == "" {
+		name = DefaultPullSecretName
+	}
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{"default"}
+	}
+	for _, ns := range namespaces {
+		secret, _ := json.Marshal(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"type":       "kubernetes.io/dockerconfigjson",
+			"metadata":   map[string]any{"name": name, "namespace": ns},
+			"data":       map[string]string{".dockerconfigjson": base64.StdEncoding.EncodeToString(config)},
+		})
+		if _, err := mgr.ApplyManifest(ctx, clusterName, string(secret)); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s/%s: applying pull secret: %v", ns, name, err))
+			continue
+		}
+		result.Secrets = append(result.Secrets, ns+"/"+name)
+	}
+}
@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// helperRunner answers credential helper calls with a credential per registry.
+type helperRunner struct {
+	mockRunner
+	creds map[string]string // registry -> helper JSON output
+}
+
+func (h *helperRunner) RunInput(_ context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	out, ok := h.creds[strings.TrimSpace(string(input))]
+	if !ok {
+		return nil, []byte("credentials not found in native keychain"), fmt.Errorf("exit status 1")
+	}
+	return []byte(out), nil, nil
+}
+
+func TestInferHelper(t *testing.T) {
+	tests := map[string]string{
+		"123456789012.dkr.ecr.eu-west-1.amazonaws.com":             "ecr-login",
+		"https://123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn": "ecr-login",
+		"gcr.io":                      "gcloud",
+		"eu.gcr.io":                   "gcloud",
+		"europe-west1-docker.pkg.dev": "gcloud",
+		"myregistry.azurecr.io":       "acr-env",
+		"registry.corp:5000":          "",
+		"s3.eu-west-1.amazonaws.com":  "",
+	}
+	for reg, want := range tests {
+		if got := InferHelper(reg); got != want {
+			t.Errorf("InferHelper(%q) = %q, want %q", reg, got, want)
+		}
+	}
+}
+
+func TestGetHelperCredential_IdentityToken(t *testing.T) {
+	runner := &helperRunner{creds: map[string]string{
+		"myregistry.azurecr.io": `{"ServerURL":"myregistry.azurecr.io","Username":"<token>","Secret":"refresh"}`,
+	}}
+	_, err := GetHelperCredential(context.Background(), runner, "myregistry.azurecr.io", "acr-env")
+	if err == nil || !strings.Contains(err.Error(), "identity token") {
+		t.Errorf("expected identity token error, got %v", err)
+	}
+}
+
+func TestRefreshCloudCredentials_Nodes(t *testing.T) {
+	ecr := "123456789012.dkr.ecr.eu-west-1.amazonaws.com"
+	runner := &helperRunner{
+		mockRunner: mockRunner{nodes: "dev-control-plane\ndev-worker\n"},
+		creds:      map[string]string{ecr: `{"ServerURL":"` + ecr + `","Username":"AWS","Secret":"tok"}`},
+	}
+	opts := CloudRefreshOptions{Registries: map[string]string{ecr: "", "quay.io": ""}}
+
+	result, err := RefreshCloudCredentials(context.Background(), newMockManager(runner), runner, "dev", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Credentials) != 1 || result.Credentials[0].Helper != "ecr-login" || result.Credentials[0].Username != "AWS" {
+		t.Errorf("credentials = %+v", result.Credentials)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "quay.io") {
+		t.Errorf("errors = %v", result.Errors)
+	}
+	if strings.Join(result.Nodes, ",") != "dev-control-plane,dev-worker" {
+		t.Errorf("nodes = %v", result.Nodes)
+	}
+
+	config, _ := InlineConfig(result.Credentials)
+	encoded := base64.StdEncoding.EncodeToString(config)
+	var writes int
+	for _, args := range runner.execLog {
+		if strings.Contains(strings.Join(args, " "), encoded) {
+			writes++
+		}
+	}
+	if writes != 2 {
+		t.Errorf("config written to %d nodes, want 2: %v", writes, runner.execLog)
+	}
+}
+
+func TestRefreshCloudCredentials_NoneRefreshed(t *testing.T) {
+	runner := &helperRunner{mockRunner: mockRunner{nodes: "dev-control-plane\n"}}
+	opts := CloudRefreshOptions{Registries: map[string]string{"gcr.io": ""}}
+	if _, err := RefreshCloudCredentials(context.Background(), newMockManager(runner), runner, "dev", opts); err == nil {
+		t.Error("expected error when no helper returns credentials")
+	}
+	opts.Target = "everywhere"
+	if _, err := RefreshCloudCredentials(context.Background(), newMockManager(runner), runner, "dev", opts); err == nil {
+		t.Error("expected error for invalid target")
+	}
+}
+
+func TestCloudHelpers(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DOCKER_CONFIG", "")
+	t.Setenv("REGISTRY_AUTH_FILE", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	path := filepath.Join(home, ".docker", "config.json")
+	os.MkdirAll(filepath.Dir(path), 0o700)
+	os.WriteFile(path, []byte(`{"credHelpers":{"https://gcr.io":"gcloud","myregistry.azurecr.io":"acr"}}`), 0o600)
+
+	helpers := CloudHelpers(rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker})
+	if helpers["gcr.io"] != "gcloud" || helpers["myregistry.azurecr.io"] != "acr" {
+		t.Errorf("helpers = %v", helpers)
+	}
+}
@@ -1,8 +1,10 @@
 package registry
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	goruntime "runtime"
@@ -21,6 +23,31 @@ type CredentialInfo struct {
 	MountPath   string            `json:"mount_path"`
 	Source      string            `json:"source"`
 	Notes       string            `json:"notes,omitempty"`
+	// Sources and Provenance are set for merged credentials: the files that were read
+	// and, per registry, the file its credentials came from.
+	Sources    []string          `json:"sources,omitempty"`
+	Provenance map[string]string `json:"provenance,omitempty"`
+}
+
+// Lines renders the credential info one line per registry, after a line naming the
+// file, for the verbosity=summary mode of detect_credentials.
+func (c *CredentialInfo) Lines() []string {
+	head := fmt.Sprintf("file: %s source=%s inline_auth=%t", c.FilePath, c.Source, c.InlineAuth)
+	if c.CredStore != "" {
+		head += " cred_store=" + c.CredStore
+	}
+	lines := []string{head}
+	for _, reg := range c.Registries {
+		line := "registry " + reg
+		if helper := c.CredHelpers[reg]; helper != "" {
+			line += " helper=" + helper
+		}
+		if from := c.Provenance[reg]; from != "" {
+			line += " from=" + from
+		}
+		lines = append(lines, line)
+	}
+	return lines
 }
 
 // dockerConfig represents the structure of Docker/Podman config.json / auth.json.
@@ -152,3 +179,49 @@ func pathStrings(paths []candidatePath) []string {
 	}
 	return result
 }
+
+// authFromFile looks up inline credentials for a mirror in a Docker/Podman auth file.
+// Entries may be keyed with or without a scheme.
+func authFromFile(authFile, mirror string) (username, password string, err error) {
+	data, err := os.ReadFile(expandPath(authFile))
+	if err != nil {
+		return "", "", fmt.Errorf("reading auth_file: %w", err)
+	}
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return "", "", fmt.Errorf("parsing auth_file %s: %w", authFile, err)
+	}
+
+	host := registryHost(mirror)
+	for key, entry := range cfg.Auths {
+		if registryHost(key) != host {
+			continue
+		}
+		if entry.Auth == "" {
+			return "", "", fmt.Errorf("auth_file entry for %s has no inline auth (credential helpers are not supported)", host)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("auth_file entry for %s: %w", host, err)
+		}
+		user, pass, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return "", "", fmt.Errorf("auth_file entry for %s is not user:password", host)
+		}
+		return user, pass, nil
+	}
+	return "", "", fmt.Errorf("auth_file %s has no credentials for %s", authFile, host)
+}
+
+// registryHost reduces a registry reference ("https://mirror:5000/v2/", "mirror:5000")
+// to its host[:port].
+func registryHost(ref string) string {
+	if !strings.Contains(ref, "://") {
+		ref = "//" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Host == "" {
+		return ref
+	}
+	return u.Host
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
@@ -105,6 +106,26 @@ func TestFindCredentials_PodmanAuthFile(t *testing.T) {
 	}
 }
 
+func TestCredentialInfoLines(t *testing.T) {
+	info := &CredentialInfo{
+		FilePath:    "/home/u/.docker/config.json",
+		Source:      "docker",
+		CredStore:   "desktop",
+		Registries:  []string{"ghcr.io", "gcr.io"},
+		CredHelpers: map[string]string{"gcr.io": "gcloud"},
+		Provenance:  map[string]string{"ghcr.io": "/home/u/.docker/config.json"},
+	}
+	got := info.Lines()
+	want := []string{
+		"file: /home/u/.docker/config.json source=docker inline_auth=false cred_store=desktop",
+		"registry ghcr.io from=/home/u/.docker/config.json",
+		"registry gcr.io helper=gcloud",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q", got)
+	}
+}
+
 func TestFindCredentials_NotFound(t *testing.T) {
 	t.Setenv("DOCKER_CONFIG", "/nonexistent/path")
 	t.Setenv("REGISTRY_AUTH_FILE", "")
@@ -168,3 +189,17 @@ func TestCandidatePaths_Podman(t *testing.T) {
 		t.Error("expected Podman paths to include Docker fallback")
 	}
 }
+
+func TestRegistryHost(t *testing.T) {
+	tests := map[string]string{
+		"https://harbor.corp:8443/v2/": "harbor.corp:8443",
+		"harbor.corp:8443":             "harbor.corp:8443",
+		"http://localhost:5000":        "localhost:5000",
+		"registry.corp":                "registry.corp",
+	}
+	for in, want := range tests {
+		if got := registryHost(in); got != want {
+			t.Errorf("registryHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
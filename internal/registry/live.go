@@ -0,0 +1,235 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// LiveMirror is one mirror host from a node's hosts.toml, in the order containerd tries them.
+type LiveMirror struct {
+	URL          string   `json:"url"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	SkipVerify   bool     `json:"skip_verify,omitempty"`
+	CA           string   `json:"ca,omitempty"`
+	// Authenticated is set when the host sends an Authorization header; the
+	// credentials themselves are not reported.
+	Authenticated bool `json:"authenticated,omitempty"`
+}
+
+// NodeMirrors is the registry configuration containerd uses on one node.
+type NodeMirrors struct {
+	Node string `json:"node"`
+	// ConfigPath is containerd's registry config_path; mirrors in certs.d are only
+	// used when it is /etc/containerd/certs.d.
+	ConfigPath       string                  `json:"config_path"`
+	ConfigPathActive bool                    `json:"config_path_active"`
+	Mirrors          map[string][]LiveMirror `json:"mirrors"`
+	Problems         []string                `json:"problems,omitempty"`
+}
+
+// MirrorReport is the result of GetMirrors.
+type MirrorReport struct {
+	Cluster string        `json:"cluster"`
+	Nodes   []NodeMirrors `json:"nodes"`
+	InSync  bool          `json:"in_sync"`
+	// OutOfSync lists, per node, how its effective mirrors differ from the first
+	// control-plane node's.
+	OutOfSync map[string][]string `json:"out_of_sync,omitempty"`
+}
+
+// liveMirrorScript prints containerd's config_path and every hosts.toml under certs.d,
+// each file preceded by a "### <path>" line.
+const liveMirrorScript = `echo "config_path: $(containerd config dump 2>/dev/null | grep -E '^[[:space:]]*config_path[[:space:]]*=' | head -n 1)"
+for f in ` + certsDir + `/*/hosts.toml; do [ -f "$f" ] || continue; echo "### $f"; cat "$f"; echo; done`
+
+// GetMirrors reads the registry mirrors containerd uses on each node of a cluster:
+// the running config_path and the hosts.toml of every registry in certs.d. Nodes
+// whose effective mirrors differ from the first control-plane node are flagged.
+func GetMirrors(ctx context.Context, mgr *kind.Manager, clusterName string) (*MirrorReport, error) {
+	nodes, err := mgr.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster nodes: %w", err)
+	}
+	nodes = filterNodes(nodes, "all")
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q not found or has no nodes", clusterName)
+	}
+	// kind lists nodes in no particular order; compare against the first control plane.
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return kind.NodeRole(nodes[i]) == kind.RoleControlPlane && kind.NodeRole(nodes[j]) != kind.RoleControlPlane
+	})
+
+	report := &MirrorReport{Cluster: clusterName, InSync: true}
+	for _, node := range nodes {
+		nm := NodeMirrors{Node: node, Mirrors: map[string][]LiveMirror{}}
+		out, err := mgr.ExecOnNode(ctx, node, []string{"bash", "-c", liveMirrorScript})
+		if err != nil {
+			nm.Problems = append(nm.Problems, fmt.Sprintf("reading registry config: %v", err))
+		} else {
+			parseLiveMirrors(out, &nm)
+		}
+		report.Nodes = append(report.Nodes, nm)
+	}
+
+	ref := report.Nodes[0]
+	for _, nm := range report.Nodes {
+		var diffs []string
+		if !nm.ConfigPathActive {
+			diffs = append(diffs, fmt.Sprintf("config_path is %q, not %s: certs.d mirrors are ignored",
+				nm.ConfigPath, certsDir))
+		}
+		if nm.Node != ref.Node {
+			diffs = append(diffs, diffMirrorMaps(ref.Node, ref.Mirrors, nm.Mirrors)...)
+		}
+		diffs = append(diffs, nm.Problems...)
+		if len(diffs) > 0 {
+			if report.OutOfSync == nil {
+				report.OutOfSync = map[string][]string{}
+			}
+			report.OutOfSync[nm.Node] = diffs
+			report.InSync = false
+		}
+	}
+	return report, nil
+}
+
+// Lines renders one line per node with its mirrors, then one line per difference,
+// for the verbosity=summary mode of get_registry_mirrors.
+func (r *MirrorReport) Lines() []string {
+	var lines []string
+	for _, nm := range r.Nodes {
+		fields := []string{nm.Node}
+		if !nm.ConfigPathActive {
+			fields = append(fields, "[config_path inactive]")
+		}
+		for _, reg := range sortedMirrorKeys(nm.Mirrors) {
+			fields = append(fields, reg+"->"+strings.ReplaceAll(mirrorURLs(nm.Mirrors[reg]), ", ", ","))
+		}
+		if len(nm.Mirrors) == 0 {
+			fields = append(fields, "no mirrors")
+		}
+		lines = append(lines, strings.Join(fields, " "))
+	}
+	for _, nm := range r.Nodes {
+		for _, d := range r.OutOfSync[nm.Node] {
+			lines = append(lines, "out of sync: "+nm.Node+": "+d)
+		}
+	}
+	return lines
+}
+
+// hostsFile is the part of a containerd hosts.toml GetMirrors reports.
+type hostsFile struct {
+	Server string `toml:"server"`
+	Host   map[string]struct {
+		Capabilities []string       `toml:"capabilities"`
+		SkipVerify   bool           `toml:"skip_verify"`
+		CA           any            `toml:"ca"`
+		Header       map[string]any `toml:"header"`
+	} `toml:"host"`
+}
+
+// parseLiveMirrors fills nm from the output of liveMirrorScript.
+func parseLiveMirrors(out string, nm *NodeMirrors) {
+	var file string
+	var body strings.Builder
+	flush := func() {
+		if file == "" {
+			return
+		}
+		registry := path.Base(path.Dir(file))
+		mirrors, err := parseHostsToml(body.String())
+		if err != nil {
+			nm.Problems = append(nm.Problems, fmt.Sprintf("%s: %v", file, err))
+		}
+		nm.Mirrors[registry] = mirrors
+		body.Reset()
+	}
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "config_path: "):
+			value := strings.TrimPrefix(line, "config_path: ")
+			if _, v, ok := strings.Cut(value, "="); ok {
+				nm.ConfigPath = strings.Trim(strings.TrimSpace(v), `"'`)
+			}
+			nm.ConfigPathActive = nm.ConfigPath == certsDir
+		case strings.HasPrefix(line, "### "):
+			flush()
+			file = strings.TrimPrefix(line, "### ")
+		case file != "":
+			body.WriteString(line + "\n")
+		}
+	}
+	flush()
+}
+
+// parseHostsToml returns a hosts.toml's mirror hosts in file order.
+func parseHostsToml(data string) ([]LiveMirror, error) {
+	var hf hostsFile
+	md, err := toml.Decode(data, &hf)
+	if err != nil {
+		return nil, fmt.Errorf("parsing hosts.toml: %w", err)
+	}
+	var mirrors []LiveMirror
+	seen := map[string]bool{}
+	for _, key := range md.Keys() {
+		if len(key) != 2 || key[0] != "host" || seen[key[1]] {
+			continue
+		}
+		seen[key[1]] = true
+		h := hf.Host[key[1]]
+		lm := LiveMirror{URL: key[1], Capabilities: h.Capabilities, SkipVerify: h.SkipVerify}
+		if ca, ok := h.CA.(string); ok {
+			lm.CA = ca
+		}
+		_, lm.Authenticated = h.Header["Authorization"]
+		mirrors = append(mirrors, lm)
+	}
+	return mirrors, nil
+}
+
+// diffMirrorMaps describes how a node's mirrors differ from the reference node's.
+func diffMirrorMaps(refNode string, ref, have map[string][]LiveMirror) []string {
+	var diffs []string
+	for _, reg := range sortedMirrorKeys(ref) {
+		mirrors, ok := have[reg]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("no mirror for %s (%s has %s)", reg, refNode, mirrorURLs(ref[reg])))
+		case mirrorURLs(mirrors) != mirrorURLs(ref[reg]):
+			diffs = append(diffs, fmt.Sprintf("%s mirrors to %s, %s to %s", reg, mirrorURLs(mirrors), refNode, mirrorURLs(ref[reg])))
+		}
+	}
+	for _, reg := range sortedMirrorKeys(have) {
+		if _, ok := ref[reg]; !ok {
+			diffs = append(diffs, fmt.Sprintf("extra mirror for %s (%s), not on %s", reg, mirrorURLs(have[reg]), refNode))
+		}
+	}
+	return diffs
+}
+
+func mirrorURLs(mirrors []LiveMirror) string {
+	urls := make([]string, len(mirrors))
+	for i, m := range mirrors {
+		urls[i] = m.URL
+	}
+	if len(urls) == 0 {
+		return "no hosts"
+	}
+	return strings.Join(urls, ", ")
+}
+
+func sortedMirrorKeys(m map[string][]LiveMirror) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
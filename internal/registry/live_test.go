@@ -0,0 +1,112 @@
+package registry
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// liveRunner returns canned liveMirrorScript output per node.
+type liveRunner struct {
+	mockRunner
+	output map[string]string
+}
+
+func (l *liveRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if name == "docker" && len(args) > 1 && args[0] == "exec" {
+		return []byte(l.output[args[1]]), nil
+	}
+	return l.mockRunner.Run(ctx, name, args...)
+}
+
+const liveDockerHub = `### /etc/containerd/certs.d/docker.io/hosts.toml
+server = "https://registry-1.docker.io"
+
+[host."http://proxy:5000"]
+  capabilities = ["pull", "resolve"]
+  skip_verify = true
+
+[host."http://proxy:5000".header]
+  Authorization = ["Basic dXNlcjpwYXNz"]
+
+[host."https://backup:5443"]
+  capabilities = ["pull"]
+  ca = "/etc/containerd/certs.d/docker.io/ca.crt"
+`
+
+func TestGetMirrors(t *testing.T) {
+	active := "config_path: config_path = \"/etc/containerd/certs.d\"\n"
+	runner := &liveRunner{
+		mockRunner: mockRunner{nodes: "dev-worker\ndev-control-plane\ndev-worker2\n"},
+		output: map[string]string{
+			"dev-control-plane": active + liveDockerHub,
+			"dev-worker":        active + liveDockerHub + "### /etc/containerd/certs.d/ghcr.io/hosts.toml\n[host.\"http://proxy:5001\"]\n",
+			"dev-worker2":       "config_path: \n",
+		},
+	}
+	report, err := GetMirrors(context.Background(), newMockManager(runner), "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cp := report.Nodes[0]
+	if cp.Node != "dev-control-plane" || !cp.ConfigPathActive {
+		t.Fatalf("first node = %+v", cp)
+	}
+	hub := cp.Mirrors["docker.io"]
+	if len(hub) != 2 || hub[0].URL != "http://proxy:5000" || hub[1].URL != "https://backup:5443" {
+		t.Fatalf("docker.io mirrors = %+v", hub)
+	}
+	if !hub[0].Authenticated || !hub[0].SkipVerify || hub[1].CA == "" || hub[1].Authenticated {
+		t.Errorf("docker.io mirrors = %+v", hub)
+	}
+
+	if report.InSync {
+		t.Error("expected out of sync")
+	}
+	if _, ok := report.OutOfSync["dev-control-plane"]; ok {
+		t.Errorf("reference node flagged: %v", report.OutOfSync)
+	}
+	if diffs := strings.Join(report.OutOfSync["dev-worker"], " "); !strings.Contains(diffs, "extra mirror for ghcr.io") {
+		t.Errorf("dev-worker diffs = %q", diffs)
+	}
+	diffs := strings.Join(report.OutOfSync["dev-worker2"], " ")
+	if !strings.Contains(diffs, "certs.d mirrors are ignored") || !strings.Contains(diffs, "no mirror for docker.io") {
+		t.Errorf("dev-worker2 diffs = %q", diffs)
+	}
+}
+
+func TestGetMirrors_InSync(t *testing.T) {
+	active := "config_path: config_path = \"/etc/containerd/certs.d\"\n"
+	runner := &liveRunner{
+		mockRunner: mockRunner{nodes: "dev-control-plane\ndev-worker\n"},
+		output:     map[string]string{"dev-control-plane": active + liveDockerHub, "dev-worker": active + liveDockerHub},
+	}
+	report, err := GetMirrors(context.Background(), newMockManager(runner), "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.InSync || report.OutOfSync != nil {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestMirrorReportLines(t *testing.T) {
+	report := &MirrorReport{
+		Nodes: []NodeMirrors{
+			{Node: "dev-control-plane", ConfigPathActive: true, Mirrors: map[string][]LiveMirror{
+				"docker.io": {{URL: "http://proxy:5000"}, {URL: "https://backup:5443"}},
+			}},
+			{Node: "dev-worker", Mirrors: map[string][]LiveMirror{}},
+		},
+		OutOfSync: map[string][]string{"dev-worker": {"no mirror for docker.io"}},
+	}
+	want := []string{
+		"dev-control-plane docker.io->http://proxy:5000,https://backup:5443",
+		"dev-worker [config_path inactive] no mirrors",
+		"out of sync: dev-worker: no mirror for docker.io",
+	}
+	if got := report.Lines(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lines() = %q", got)
+	}
+}
@@ -0,0 +1,175 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"gopkg.in/yaml.v3"
+)
+
+// The ConfigMap of KEP-1755 ("Standard for communicating a local registry") through
+// which tools such as Tilt and Skaffold discover where to push images for a cluster.
+const (
+	localRegistryNamespace = "kube-public"
+	localRegistryConfigMap = "local-registry-hosting"
+	localRegistryKey       = "localRegistryHosting.v1"
+)
+
+// Sources of LocalRegistry.
+const (
+	LocalRegistrySourceConfigMap = "configmap"
+	LocalRegistrySourceMirror    = "mirror"
+)
+
+// localRegistryHosting is the KEP-1755 localRegistryHosting.v1 document.
+type localRegistryHosting struct {
+	Host                     string `yaml:"host"`
+	HostFromContainerRuntime string `yaml:"hostFromContainerRuntime,omitempty"`
+	HostFromClusterNetwork   string `yaml:"hostFromClusterNetwork,omitempty"`
+	Help                     string `yaml:"help,omitempty"`
+}
+
+// LocalRegistry is where to push images for a cluster and how to reference them.
+type LocalRegistry struct {
+	Cluster string `json:"cluster"`
+	Found   bool   `json:"found"`
+	// Source is where the registry was found: the local-registry-hosting ConfigMap or
+	// a containerd mirror for a loopback registry host.
+	Source string `json:"source,omitempty"`
+	// Host is the registry as the host sees it, e.g. "localhost:5001": tag and push
+	// to it, and reference images with it.
+	Host string `json:"host,omitempty"`
+	// HostFromContainerRuntime is where the nodes' containerd pulls Host from, e.g.
+	// "kind-registry:5000".
+	HostFromContainerRuntime string `json:"host_from_container_runtime,omitempty"`
+	HostFromClusterNetwork   string `json:"host_from_cluster_network,omitempty"`
+	KubeContext              string `json:"kube_context"`
+	// PushCommands tag and push an example image.
+	PushCommands []string `json:"push_commands,omitempty"`
+	// ImageReference explains how manifests and charts refer to pushed images.
+	ImageReference string `json:"image_reference,omitempty"`
+	// HelmValues is a values file pointing common chart image settings at Host.
+	HelmValues string   `json:"helm_values,omitempty"`
+	Notes      []string `json:"notes,omitempty"`
+}
+
+// LocalRegistryOverride returns the first override that mirrors a loopback registry
+// such as localhost:5001, the local registry pattern from the Kind documentation.
+func LocalRegistryOverride(overrides []RegistryOverride) (RegistryOverride, bool) {
+	for _, o := range overrides {
+		if isLoopbackRegistry(o.Original) {
+			return o, true
+		}
+	}
+	return RegistryOverride{}, false
+}
+
+// isLoopbackRegistry reports whether a registry host is a port on the loopback address.
+func isLoopbackRegistry(host string) bool {
+	h, port, err := net.SplitHostPort(host)
+	if err != nil || port == "" {
+		return false
+	}
+	ip := net.ParseIP(h)
+	return h == "localhost" || ip != nil && ip.IsLoopback()
+}
+
+// PublishLocalRegistry writes the local-registry-hosting ConfigMap for a local
+// registry override, so in-cluster and host tools can discover it.
+func PublishLocalRegistry(ctx context.Context, mgr *kind.Manager, clusterName string, o RegistryOverride) error {
+	doc, err := yaml.Marshal(localRegistryHosting{
+		Host:                     o.Original,
+		HostFromContainerRuntime: mirrorHost(o.Mirror),
+		HostFromClusterNetwork:   mirrorHost(o.Mirror),
+		Help:                     "https://kind.sigs.k8s.io/docs/user/local-registry/",
+	})
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", localRegistryKey, err)
+	}
+	cm := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": localRegistryConfigMap, "namespace": localRegistryNamespace},
+		"data":       map[string]string{localRegistryKey: string(doc)},
+	}
+	manifest, err := yaml.Marshal(cm)
+	if err != nil {
+		return fmt.Errorf("encoding ConfigMap: %w", err)
+	}
+	if _, err := mgr.ApplyManifest(ctx, clusterName, string(manifest)); err != nil {
+		return fmt.Errorf("publishing %s/%s: %w", localRegistryNamespace, localRegistryConfigMap, err)
+	}
+	return nil
+}
+
+// GetLocalRegistry finds a cluster's local registry: from the local-registry-hosting
+// ConfigMap if present, otherwise from a containerd mirror of a loopback registry on
+// the first control-plane node. kubeContext is the cluster's context name for the
+// Helm values (default kind-<cluster>) and runtimeBin the CLI the push commands use.
+func GetLocalRegistry(ctx context.Context, mgr *kind.Manager, clusterName, kubeContext, runtimeBin string) (*LocalRegistry, error) {
+	if kubeContext == "" {
+		kubeContext = "kind-" + clusterName
+	}
+	reg := &LocalRegistry{Cluster: clusterName, KubeContext: kubeContext}
+
+	res, err := mgr.RunKubectl(ctx, clusterName, localRegistryNamespace, []string{"get", "configmap",
+		localRegistryConfigMap, "--ignore-not-found", "-o", `jsonpath={.data.localRegistryHosting\.v1}`})
+	if err != nil {
+		return nil, err
+	}
+	if res.ExitCode != 0 {
+		reg.Notes = append(reg.Notes, fmt.Sprintf("reading the %s ConfigMap failed: %s",
+			localRegistryConfigMap, strings.TrimSpace(res.Stderr)))
+	} else if doc := strings.TrimSpace(res.Stdout); doc != "" {
+		var hosting localRegistryHosting
+		if err := yaml.Unmarshal([]byte(doc), &hosting); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", localRegistryKey, err)
+		}
+		if hosting.Host != "" {
+			reg.Found, reg.Source = true, LocalRegistrySourceConfigMap
+			reg.Host = hosting.Host
+			reg.HostFromContainerRuntime = hosting.HostFromContainerRuntime
+			reg.HostFromClusterNetwork = hosting.HostFromClusterNetwork
+		}
+	}
+
+	if !reg.Found {
+		report, err := GetMirrors(ctx, mgr, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		for host, mirrors := range report.Nodes[0].Mirrors {
+			if isLoopbackRegistry(host) && len(mirrors) > 0 && (reg.Host == "" || host < reg.Host) {
+				reg.Found, reg.Source = true, LocalRegistrySourceMirror
+				reg.Host = host
+				reg.HostFromContainerRuntime = mirrorHost(mirrors[0].URL)
+			}
+		}
+	}
+
+	if !reg.Found {
+		reg.Notes = append(reg.Notes, "No local registry is configured. Run a registry container on the kind "+
+			"network (e.g. '"+runtimeBin+" run -d -p 127.0.0.1:5001:5000 --network kind --name kind-registry "+
+			"registry:2') and call configure_registry_mirrors with "+
+			`[{"original":"localhost:5001","mirror":"http://kind-registry:5000"}]; the setup_dev_cluster prompt `+
+			"walks through it.")
+		return reg, nil
+	}
+	reg.PushCommands = []string{
+		fmt.Sprintf("%s tag my-app:dev %s/my-app:dev", runtimeBin, reg.Host),
+		fmt.Sprintf("%s push %s/my-app:dev", runtimeBin, reg.Host),
+	}
+	reg.ImageReference = fmt.Sprintf("Reference pushed images as %s/<name>:<tag> in manifests and Helm values, "+
+		"not by the in-cluster name; the nodes' containerd resolves %[1]s", reg.Host)
+	if reg.HostFromContainerRuntime != "" && reg.HostFromContainerRuntime != reg.Host {
+		reg.ImageReference += " to " + reg.HostFromContainerRuntime
+	}
+	reg.ImageReference += ". Push a new tag for each change; a re-pushed tag is only pulled again with " +
+		"imagePullPolicy: Always."
+	reg.HelmValues = fmt.Sprintf("# helm --kube-context %s ... -f <this file>\n"+
+		"global:\n  imageRegistry: %s\nimage:\n  registry: %[2]s\n", reg.KubeContext, reg.Host)
+	return reg, nil
+}
@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// localRunner answers the local-registry-hosting ConfigMap lookup with configMap and
+// the node's hosts.toml dump with hosts.
+type localRunner struct {
+	mockRunner
+	configMap string
+	hosts     string
+	applied   bool
+}
+
+func (l *localRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	if name == "kubectl" && slices.Contains(args, "configmap") {
+		return []byte(l.configMap), nil, nil
+	}
+	return l.mockRunner.RunSeparate(ctx, name, args...)
+}
+
+func (l *localRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	switch {
+	case name == "docker" && len(args) > 1 && args[0] == "exec":
+		return []byte(l.hosts), nil
+	case name == "kubectl" && slices.Contains(args, "apply"):
+		l.applied = true
+		return []byte("configmap/local-registry-hosting created\n"), nil
+	}
+	return l.mockRunner.Run(ctx, name, args...)
+}
+
+func TestGetLocalRegistry_ConfigMap(t *testing.T) {
+	runner := &localRunner{
+		mockRunner: mockRunner{nodes: "dev-control-plane\n"},
+		configMap:  "host: \"localhost:5001\"\nhostFromContainerRuntime: \"kind-registry:5000\"\nhelp: \"https://kind.sigs.k8s.io\"\n",
+	}
+	reg, err := GetLocalRegistry(context.Background(), newMockManager(runner), "dev", "", "docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reg.Found || reg.Source != LocalRegistrySourceConfigMap || reg.Host != "localhost:5001" ||
+		reg.HostFromContainerRuntime != "kind-registry:5000" || reg.KubeContext != "kind-dev" {
+		t.Fatalf("registry = %+v", reg)
+	}
+	if reg.PushCommands[1] != "docker push localhost:5001/my-app:dev" {
+		t.Errorf("push commands = %v", reg.PushCommands)
+	}
+	if !strings.Contains(reg.HelmValues, "imageRegistry: localhost:5001") ||
+		!strings.Contains(reg.HelmValues, "--kube-context kind-dev") {
+		t.Errorf("helm values:\n%s", reg.HelmValues)
+	}
+}
+
+func TestGetLocalRegistry_Mirror(t *testing.T) {
+	runner := &localRunner{
+		mockRunner: mockRunner{nodes: "dev-control-plane\n"},
+		hosts: "config_path: config_path = \"/etc/containerd/certs.d\"\n" + liveDockerHub +
+			"### /etc/containerd/certs.d/localhost:5001/hosts.toml\n[host.\"http://kind-registry:5000\"]\n",
+	}
+	reg, err := GetLocalRegistry(context.Background(), newMockManager(runner), "dev", "team-dev", "podman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reg.Found || reg.Source != LocalRegistrySourceMirror || reg.Host != "localhost:5001" ||
+		reg.HostFromContainerRuntime != "kind-registry:5000" || reg.KubeContext != "team-dev" {
+		t.Errorf("registry = %+v", reg)
+	}
+
+	runner.hosts = "config_path: \n"
+	reg, err = GetLocalRegistry(context.Background(), newMockManager(runner), "dev", "", "docker")
+	if err != nil || reg.Found || len(reg.Notes) == 0 {
+		t.Errorf("expected no registry with setup notes, got %+v, %v", reg, err)
+	}
+}
+
+func TestLocalRegistryOverride(t *testing.T) {
+	overrides := []RegistryOverride{
+		{Original: "docker.io", Mirror: "http://proxy:5000"},
+		{Original: "127.0.0.1:5001", Mirror: "http://kind-registry:5000"},
+	}
+	o, ok := LocalRegistryOverride(overrides)
+	if !ok || o.Original != "127.0.0.1:5001" {
+		t.Errorf("override = %+v, %v", o, ok)
+	}
+	if _, ok := LocalRegistryOverride(overrides[:1]); ok {
+		t.Error("docker.io is not a local registry")
+	}
+
+	runner := &localRunner{mockRunner: mockRunner{nodes: "dev-control-plane\n"}}
+	if err := PublishLocalRegistry(context.Background(), newMockManager(runner), "dev", o); err != nil || !runner.applied {
+		t.Errorf("publish: %v, applied=%v", err, runner.applied)
+	}
+}
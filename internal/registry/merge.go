@@ -0,0 +1,151 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"sort"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// MergeCredentials reads every Docker and Podman credential file on the host (Docker
+// config.json, Podman auth.json and REGISTRY_AUTH_FILE) and combines their inline auth
+// entries into one config. Files of the detected runtime take precedence when the same
+// registry appears in several. The returned info records which file each registry came
+// from; its FilePath is empty until the config is written with WriteCredentials.
+func MergeCredentials(ri rtdetect.RuntimeInfo) (*CredentialInfo, []byte, error) {
+	paths := mergeCandidatePaths(ri)
+
+	info := &CredentialInfo{
+		MountPath:  "/var/lib/kubelet/config.json",
+		Source:     "merged",
+		Provenance: make(map[string]string),
+	}
+	auths := make(map[string]json.RawMessage)
+	hosts := make(map[string]string) // registry host -> key it was merged under
+	var notes []string
+
+	for _, candidate := range paths {
+		expanded := expandPath(candidate.path)
+		data, err := os.ReadFile(expanded)
+		if err != nil {
+			continue
+		}
+		// Auth entries are kept raw so fields such as identitytoken survive the merge.
+		var cfg struct {
+			Auths       map[string]json.RawMessage `json:"auths"`
+			CredsStore  string                     `json:"credsStore"`
+			CredHelpers map[string]string          `json:"credHelpers"`
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			notes = append(notes, fmt.Sprintf("skipped %s: %v", expanded, err))
+			continue
+		}
+		info.Sources = append(info.Sources, expanded)
+		if cfg.CredsStore != "" || len(cfg.CredHelpers) > 0 {
+			notes = append(notes, fmt.Sprintf(
+				"%s uses credential helpers; only its inline auth entries are merged", expanded))
+		}
+
+		for _, key := range sortedAuthKeys(cfg.Auths) {
+			var entry authEntry
+			if err := json.Unmarshal(cfg.Auths[key], &entry); err != nil || entry.Auth == "" {
+				continue
+			}
+			host := registryHost(key)
+			if first, ok := hosts[host]; ok {
+				notes = append(notes, fmt.Sprintf("%s: using credentials from %s, ignoring %s",
+					host, info.Provenance[first], expanded))
+				continue
+			}
+			hosts[host] = key
+			auths[key] = cfg.Auths[key]
+			info.Provenance[key] = expanded
+			info.Registries = append(info.Registries, key)
+		}
+	}
+
+	if len(info.Sources) == 0 {
+		return nil, nil, fmt.Errorf("no registry credentials found; searched paths: %s",
+			strings.Join(pathStrings(paths), ", "))
+	}
+	sort.Strings(info.Registries)
+	info.InlineAuth = len(auths) > 0
+	if !info.InlineAuth {
+		notes = append(notes, "no inline auth entries found in any credential file")
+	}
+	info.Notes = strings.Join(notes, "; ")
+
+	merged, err := json.MarshalIndent(map[string]any{"auths": auths}, "", "\t")
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding merged credentials: %w", err)
+	}
+	return info, merged, nil
+}
+
+// WriteCredentials writes a merged credential config to a new 0600 temp file, records
+// its path in info and returns it. The file is left in place for node mounts.
+func WriteCredentials(info *CredentialInfo, merged []byte) (string, error) {
+	f, err := os.CreateTemp("", "kind-merged-auth-*.json")
+	if err != nil {
+		return "", fmt.Errorf("creating merged credentials file: %w", err)
+	}
+	defer f.Close()
+	if err := f.Chmod(0o600); err != nil {
+		return "", fmt.Errorf("setting merged credentials permissions: %w", err)
+	}
+	if _, err := f.Write(merged); err != nil {
+		return "", fmt.Errorf("writing merged credentials: %w", err)
+	}
+	info.FilePath = f.Name()
+	return f.Name(), nil
+}
+
+// mergeCandidatePaths lists every Docker and Podman credential file location, those of
+// the detected runtime first. Unlike candidatePaths, both runtimes are always included.
+func mergeCandidatePaths(ri rtdetect.RuntimeInfo) []candidatePath {
+	var docker, podman []candidatePath
+	if envPath := os.Getenv("DOCKER_CONFIG"); envPath != "" {
+		docker = append(docker, candidatePath{filepath.Join(envPath, "config.json"), "docker"})
+	}
+	docker = append(docker, candidatePath{"~/.docker/config.json", "docker"})
+
+	if envPath := os.Getenv("REGISTRY_AUTH_FILE"); envPath != "" {
+		podman = append(podman, candidatePath{envPath, "podman"})
+	}
+	if xdg := os.Getenv("XDG_RUNTIME_DIR"); xdg != "" && goruntime.GOOS == "linux" {
+		podman = append(podman, candidatePath{filepath.Join(xdg, "containers", "auth.json"), "podman"})
+	}
+	podman = append(podman, candidatePath{"~/.config/containers/auth.json", "podman"})
+
+	paths := append(docker, podman...)
+	if ri.Runtime == rtdetect.RuntimePodman {
+		paths = append(podman, docker...)
+	}
+
+	// The same file may be reachable through several variables.
+	seen := make(map[string]bool)
+	var unique []candidatePath
+	for _, p := range paths {
+		expanded := expandPath(p.path)
+		if seen[expanded] {
+			continue
+		}
+		seen[expanded] = true
+		unique = append(unique, p)
+	}
+	return unique
+}
+
+func sortedAuthKeys(auths map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(auths))
+	for k := range auths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+func writeAuthFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DOCKER_CONFIG", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+
+	dockerPath := filepath.Join(home, ".docker", "config.json")
+	writeAuthFile(t, dockerPath, `{
+		"auths": {
+			"https://index.docker.io/v1/": {"auth": "ZG9ja2VyOnB3"},
+			"ghcr.io": {},
+			"harbor.corp:8443": {"auth": "ZG9ja2VyOmhhcmJvcg=="}
+		},
+		"credsStore": "desktop"
+	}`)
+	podmanPath := filepath.Join(home, ".config", "containers", "auth.json")
+	writeAuthFile(t, podmanPath, `{"auths": {"quay.io": {"auth": "cG9kbWFuOnB3"}}}`)
+	envPath := filepath.Join(home, "ci-auth.json")
+	writeAuthFile(t, envPath, `{"auths": {"https://harbor.corp:8443": {"auth": "Y2k6aGFyYm9y", "identitytoken": "tok"}}}`)
+	t.Setenv("REGISTRY_AUTH_FILE", envPath)
+
+	info, merged, err := MergeCredentials(rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimePodman})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(info.Sources) != 3 || info.Sources[0] != envPath {
+		t.Errorf("Sources = %v, want REGISTRY_AUTH_FILE first", info.Sources)
+	}
+	wantProvenance := map[string]string{
+		"https://harbor.corp:8443":    envPath,
+		"quay.io":                     podmanPath,
+		"https://index.docker.io/v1/": dockerPath,
+	}
+	if len(info.Provenance) != len(wantProvenance) {
+		t.Errorf("Provenance = %v", info.Provenance)
+	}
+	for reg, want := range wantProvenance {
+		if info.Provenance[reg] != want {
+			t.Errorf("Provenance[%s] = %q, want %q", reg, info.Provenance[reg], want)
+		}
+	}
+	if !info.InlineAuth || info.FilePath != "" {
+		t.Errorf("InlineAuth = %v, FilePath = %q", info.InlineAuth, info.FilePath)
+	}
+	if !strings.Contains(info.Notes, "harbor.corp:8443: using credentials from "+envPath) ||
+		!strings.Contains(info.Notes, "credential helpers") {
+		t.Errorf("Notes = %q", info.Notes)
+	}
+
+	var cfg struct {
+		Auths map[string]map[string]string `json:"auths"`
+	}
+	if err := json.Unmarshal(merged, &cfg); err != nil {
+		t.Fatalf("merged config is not JSON: %v", err)
+	}
+	if len(cfg.Auths) != 3 || cfg.Auths["https://harbor.corp:8443"]["identitytoken"] != "tok" {
+		t.Errorf("merged auths = %v", cfg.Auths)
+	}
+	if _, ok := cfg.Auths["ghcr.io"]; ok {
+		t.Error("entries without inline auth should not be merged")
+	}
+}
+
+func TestMergeCredentials_NotFound(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCKER_CONFIG", "")
+	t.Setenv("REGISTRY_AUTH_FILE", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+
+	if _, _, err := MergeCredentials(rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}); err == nil {
+		t.Error("expected error when no credential files exist")
+	}
+}
+
+func TestWriteCredentials(t *testing.T) {
+	info := &CredentialInfo{}
+	path, err := WriteCredentials(info, []byte(`{"auths":{}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(path)
+
+	if info.FilePath != path {
+		t.Errorf("FilePath = %q, want %q", info.FilePath, path)
+	}
+	st, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %o, want 600", st.Mode().Perm())
+	}
+}
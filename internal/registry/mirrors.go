@@ -2,16 +2,106 @@ package registry
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"path"
 	"strings"
 
 	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
 )
 
+var restartContainerdCommand = []string{"systemctl", "restart", "containerd"}
+
+// Files that keep the mirror configuration active across node restarts: a script that
+// (re)enables config_path in containerd's config, run by a systemd drop-in before every
+// containerd start.
+const (
+	certsDir          = "/etc/containerd/certs.d"
+	ensureConfigPath  = "/etc/containerd/mcp-ensure-registry-config.sh"
+	containerdDropIn  = "/etc/systemd/system/containerd.service.d/50-mcp-registry-mirrors.conf"
+	ensureConfigShell = `#!/bin/sh
+# Keep containerd reading registry hosts from ` + certsDir + ` (written by mcp-kind-manager).
+f=/etc/containerd/config.toml
+grep -Eq 'config_path = ["'"'"']` + certsDir + `["'"'"']' "$f" && exit 0
+if grep -q 'config_path *=' "$f"; then
+  sed -i 's|config_path *=.*|config_path = "` + certsDir + `"|' "$f"
+else
+  printf '\n[plugins."io.containerd.grpc.v1.cri".registry]\n  config_path = "` + certsDir + `"\n' >> "$f"
+fi
+`
+	containerdDropInUnit = "[Service]\nExecStartPre=/bin/sh " + ensureConfigPath + "\n"
+)
+
 // RegistryOverride defines a mapping from an original registry to a local mirror.
 type RegistryOverride struct {
 	Original string `json:"original"`
 	Mirror   string `json:"mirror"`
+	// InsecureSkipVerify disables TLS verification for an HTTPS mirror. Plain HTTP
+	// mirrors always skip verification.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+	// CAFile is a PEM CA bundle on the host, copied to each node and trusted for the mirror.
+	CAFile string `json:"ca_file,omitempty"`
+	// Capabilities are the containerd host capabilities (pull, resolve, push).
+	// Defaults to pull and resolve.
+	Capabilities []string `json:"capabilities,omitempty"`
+	// Username and Password authenticate to the mirror with a Basic Authorization header.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// AuthFile is a Docker config.json or Podman auth.json on the host to take the
+	// mirror's credentials from instead of Username/Password.
+	AuthFile string `json:"auth_file,omitempty"`
+}
+
+var defaultCapabilities = []string{"pull", "resolve"}
+
+// validate checks the capabilities and reads the CA bundle, if any.
+func (o RegistryOverride) validate() (ca []byte, err error) {
+	if o.Original == "" || o.Mirror == "" {
+		return nil, fmt.Errorf("override needs both 'original' and 'mirror'")
+	}
+	for _, c := range o.Capabilities {
+		switch c {
+		case "pull", "resolve", "push":
+		default:
+			return nil, fmt.Errorf("override for %s: unknown capability %q (expected pull, resolve or push)", o.Original, c)
+		}
+	}
+	if o.AuthFile != "" && (o.Username != "" || o.Password != "") {
+		return nil, fmt.Errorf("override for %s: set either auth_file or username/password, not both", o.Original)
+	}
+	if (o.Username == "") != (o.Password == "") {
+		return nil, fmt.Errorf("override for %s: username and password must be set together", o.Original)
+	}
+	if o.CAFile == "" {
+		return nil, nil
+	}
+	ca, err = os.ReadFile(o.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("override for %s: reading ca_file: %w", o.Original, err)
+	}
+	if !strings.Contains(string(ca), "-----BEGIN CERTIFICATE-----") {
+		return nil, fmt.Errorf("override for %s: ca_file %s is not a PEM certificate", o.Original, o.CAFile)
+	}
+	return ca, nil
+}
+
+// resolveAuth fills Username and Password from AuthFile, if set.
+func (o *RegistryOverride) resolveAuth() error {
+	if o.AuthFile == "" {
+		return nil
+	}
+	user, pass, err := authFromFile(o.AuthFile, o.Mirror)
+	if err != nil {
+		return fmt.Errorf("override for %s: %w", o.Original, err)
+	}
+	o.Username, o.Password = user, pass
+	return nil
+}
+
+// caPath is where the override's CA bundle is written on each node.
+func (o RegistryOverride) caPath() string {
+	return path.Join(certsDir, o.Original, "ca.crt")
 }
 
 // MirrorConfig holds the generated containerd mirror configuration.
@@ -19,6 +109,20 @@ type MirrorConfig struct {
 	ContainerdPatches  []string      `json:"containerd_patches"`
 	ExtraMounts        []kind.Mount  `json:"extra_mounts,omitempty"`
 	PostCreateCommands []NodeCommand `json:"post_create_commands"`
+	Files              []NodeFile    `json:"files"`
+}
+
+// NodeFile is a file that the post-create commands write on every node.
+type NodeFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// PlannedCommand is a node command that ApplyMirrorConfig would run.
+type PlannedCommand struct {
+	Node        string `json:"node"`
+	Description string `json:"description"`
+	Command     string `json:"command"`
 }
 
 // NodeCommand represents a command to run on a Kind node after cluster creation.
@@ -26,6 +130,43 @@ type NodeCommand struct {
 	NodeSelector string   `json:"node_selector"`
 	Description  string   `json:"description"`
 	Command      []string `json:"command"`
+	// Creates is the file or directory the command creates on the node, removed on rollback.
+	Creates string `json:"creates,omitempty"`
+}
+
+// NodeVerification reports whether containerd on a node is running with the mirror
+// configuration active after the restart.
+type NodeVerification struct {
+	Node     string   `json:"node"`
+	Verified bool     `json:"verified"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// ApplyOptions controls how ApplyMirrorConfig handles failures.
+type ApplyOptions struct {
+	// RollbackOnFailure removes everything written to all nodes if any command fails.
+	RollbackOnFailure bool
+}
+
+// Final per-node states reported by ApplyMirrorConfig.
+const (
+	NodeConfigured     = "configured"
+	NodePartial        = "partial"
+	NodeRolledBack     = "rolled-back"
+	NodeRollbackFailed = "rollback-failed"
+	// NodeUnverified means every command succeeded but containerd did not come back with
+	// the mirror configuration active.
+	NodeUnverified = "unverified"
+)
+
+// ApplyResult reports the outcome of applying a mirror configuration.
+type ApplyResult struct {
+	Results    []string          `json:"results"`
+	NodeStates map[string]string `json:"node_states"`
+	Failed     bool              `json:"failed"`
+	RolledBack bool              `json:"rolled_back"`
+	// Verification is reported for every node unless the configuration was rolled back.
+	Verification []NodeVerification `json:"verification,omitempty"`
 }
 
 // GenerateMirrorConfig generates containerd mirror configuration for the given registry overrides.
@@ -44,27 +185,72 @@ func GenerateMirrorConfig(overrides []RegistryOverride, credInfo *CredentialInfo
 
 	// Generate post-create commands to write hosts.toml for each override
 	for _, override := range overrides {
+		ca, err := override.validate()
+		if err != nil {
+			return nil, err
+		}
+		if err := override.resolveAuth(); err != nil {
+			return nil, err
+		}
 		registryDir := override.Original
 		hostsToml := generateHostsToml(override)
+		config.Files = append(config.Files, NodeFile{
+			Path:    fmt.Sprintf("/etc/containerd/certs.d/%s/hosts.toml", registryDir),
+			Content: hostsToml,
+		})
 
+		dir := fmt.Sprintf("/etc/containerd/certs.d/%s", registryDir)
 		config.PostCreateCommands = append(config.PostCreateCommands, NodeCommand{
 			NodeSelector: "all",
 			Description:  fmt.Sprintf("Create registry config directory for %s", override.Original),
-			Command: []string{
-				"mkdir", "-p", fmt.Sprintf("/etc/containerd/certs.d/%s", registryDir),
-			},
+			Command:      []string{"mkdir", "-p", dir},
+			Creates:      dir,
 		})
 
+		writeHosts := fmt.Sprintf("cat > /etc/containerd/certs.d/%s/hosts.toml << 'EOF'\n%s\nEOF", registryDir, hostsToml)
+		if override.Username != "" {
+			// hosts.toml carries the mirror credentials
+			writeHosts = fmt.Sprintf("umask 077\n%s\nchmod 600 /etc/containerd/certs.d/%s/hosts.toml", writeHosts, registryDir)
+		}
 		config.PostCreateCommands = append(config.PostCreateCommands, NodeCommand{
 			NodeSelector: "all",
 			Description:  fmt.Sprintf("Configure mirror for %s -> %s", override.Original, override.Mirror),
-			Command: []string{
-				"bash", "-c",
-				fmt.Sprintf("cat > /etc/containerd/certs.d/%s/hosts.toml << 'EOF'\n%s\nEOF", registryDir, hostsToml),
-			},
+			Command:      []string{"bash", "-c", writeHosts},
 		})
+
+		if ca != nil {
+			config.Files = append(config.Files, NodeFile{Path: override.caPath(), Content: string(ca)})
+			config.PostCreateCommands = append(config.PostCreateCommands, NodeCommand{
+				NodeSelector: "all",
+				Description:  fmt.Sprintf("Install CA certificate for %s", override.Mirror),
+				Command: []string{"bash", "-c",
+					fmt.Sprintf("cat > %s << 'EOF'\n%s\nEOF", override.caPath(), strings.TrimRight(string(ca), "\n"))},
+			})
+		}
 	}
 
+	// Persist config_path across containerd and node restarts
+	config.Files = append(config.Files,
+		NodeFile{Path: ensureConfigPath, Content: ensureConfigShell},
+		NodeFile{Path: containerdDropIn, Content: containerdDropInUnit},
+	)
+	config.PostCreateCommands = append(config.PostCreateCommands,
+		NodeCommand{
+			NodeSelector: "all",
+			Description:  "Install containerd config_path guard script",
+			Command: []string{"bash", "-c", fmt.Sprintf("cat > %s << 'EOF'\n%sEOF\nchmod +x %s",
+				ensureConfigPath, ensureConfigShell, ensureConfigPath)},
+			Creates: ensureConfigPath,
+		},
+		NodeCommand{
+			NodeSelector: "all",
+			Description:  "Install containerd systemd drop-in to run the guard before every start",
+			Command: []string{"bash", "-c", fmt.Sprintf("mkdir -p %s && cat > %s << 'EOF'\n%sEOF\nsystemctl daemon-reload",
+				path.Dir(containerdDropIn), containerdDropIn, containerdDropInUnit)},
+			Creates: containerdDropIn,
+		},
+	)
+
 	// If credential info is provided and has inline auth, mount the cred file
 	if credInfo != nil && credInfo.InlineAuth {
 		config.ExtraMounts = append(config.ExtraMounts, kind.Mount{
@@ -92,75 +278,219 @@ func generateHostsToml(override RegistryOverride) string {
 		mirrorURL = "http://" + mirrorURL
 	}
 
+	capabilities := override.Capabilities
+	if len(capabilities) == 0 {
+		capabilities = defaultCapabilities
+	}
+	quoted := make([]string, len(capabilities))
+	for i, c := range capabilities {
+		quoted[i] = fmt.Sprintf("%q", c)
+	}
+
 	sb.WriteString(fmt.Sprintf("[host.\"%s\"]\n", mirrorURL))
-	sb.WriteString("  capabilities = [\"pull\", \"resolve\"]\n")
+	sb.WriteString(fmt.Sprintf("  capabilities = [%s]\n", strings.Join(quoted, ", ")))
 
-	if strings.HasPrefix(mirrorURL, "http://") {
+	if strings.HasPrefix(mirrorURL, "http://") || override.InsecureSkipVerify {
 		sb.WriteString("  skip_verify = true\n")
 	}
+	if override.CAFile != "" {
+		sb.WriteString(fmt.Sprintf("  ca = \"%s\"\n", override.caPath()))
+	}
+	if override.Username != "" {
+		token := base64.StdEncoding.EncodeToString([]byte(override.Username + ":" + override.Password))
+		sb.WriteString(fmt.Sprintf("\n[host.\"%s\".header]\n", mirrorURL))
+		sb.WriteString(fmt.Sprintf("  Authorization = [\"Basic %s\"]\n", token))
+	}
 
 	return sb.String()
 }
 
-// ApplyMirrorConfig applies mirror configuration to a running Kind cluster.
-func ApplyMirrorConfig(ctx context.Context, mgr *kind.Manager, clusterName string, mirrorCfg *MirrorConfig) ([]string, error) {
+// ApplyMirrorConfig applies mirror configuration to a running Kind cluster. Directories
+// created on each node are recorded so that, if any command fails and
+// opts.RollbackOnFailure is set, they can be removed again from every node.
+func ApplyMirrorConfig(ctx context.Context, mgr *kind.Manager, clusterName string, mirrorCfg *MirrorConfig, opts ApplyOptions) (*ApplyResult, error) {
 	nodes, err := mgr.GetClusterNodes(ctx, clusterName)
 	if err != nil {
 		return nil, fmt.Errorf("getting cluster nodes: %w", err)
 	}
+	nodes = filterNodes(nodes, "all")
 
-	var results []string
+	result := &ApplyResult{NodeStates: make(map[string]string)}
+	written := make(map[string][]string)
+	nodeFailed := make(map[string]bool)
 
 	for _, cmd := range mirrorCfg.PostCreateCommands {
-		targetNodes := filterNodes(nodes, cmd.NodeSelector)
-		for _, node := range targetNodes {
+		for _, node := range filterNodes(nodes, cmd.NodeSelector) {
 			out, err := mgr.ExecOnNode(ctx, node, cmd.Command)
 			if err != nil {
-				results = append(results, fmt.Sprintf("FAILED [%s] %s: %v", node, cmd.Description, err))
+				nodeFailed[node] = true
+				result.Results = append(result.Results, fmt.Sprintf("FAILED [%s] %s: %v", node, cmd.Description, err))
+				continue
+			}
+			if cmd.Creates != "" {
+				written[node] = append(written[node], cmd.Creates)
+			}
+			result.Results = append(result.Results, okMessage(node, cmd.Description, out))
+		}
+	}
+	result.Failed = len(nodeFailed) > 0
+
+	if result.Failed && opts.RollbackOnFailure {
+		result.RolledBack = true
+		for _, node := range nodes {
+			if err := removeDirs(ctx, mgr, node, written[node]); err != nil {
+				result.NodeStates[node] = NodeRollbackFailed
+				result.Results = append(result.Results, fmt.Sprintf("FAILED [%s] rollback: %v", node, err))
+				continue
+			}
+			result.NodeStates[node] = NodeRolledBack
+			if len(written[node]) > 0 {
+				result.Results = append(result.Results, fmt.Sprintf("OK [%s] rolled back %s",
+					node, strings.Join(written[node], ", ")))
+			}
+		}
+	} else {
+		for _, node := range nodes {
+			if nodeFailed[node] {
+				result.NodeStates[node] = NodePartial
 			} else {
-				msg := fmt.Sprintf("OK [%s] %s", node, cmd.Description)
-				if trimmed := strings.TrimSpace(out); trimmed != "" {
-					msg += ": " + trimmed
-				}
-				results = append(results, msg)
+				result.NodeStates[node] = NodeConfigured
 			}
 		}
 	}
 
-	// Restart containerd on all nodes to pick up the new config
+	// Restart containerd on each node in turn to pick up the new (or restored) config,
+	// waiting for it to serve CRI again before moving on so kubelet is never left
+	// without a runtime on several nodes at once.
+	verifyCmd := verifyCommand(mirrorCfg)
 	for _, node := range nodes {
-		out, err := mgr.ExecOnNode(ctx, node, []string{"systemctl", "restart", "containerd"})
+		out, err := mgr.ExecOnNode(ctx, node, restartContainerdCommand)
 		if err != nil {
-			results = append(results, fmt.Sprintf("FAILED [%s] restart containerd: %v", node, err))
-		} else {
-			msg := fmt.Sprintf("OK [%s] restarted containerd", node)
-			if trimmed := strings.TrimSpace(out); trimmed != "" {
-				msg += ": " + trimmed
+			result.Results = append(result.Results, fmt.Sprintf("FAILED [%s] restart containerd: %v", node, err))
+			if result.NodeStates[node] != NodeRollbackFailed {
+				result.NodeStates[node] = NodePartial
 			}
-			results = append(results, msg)
+			result.Failed = true
+			continue
+		}
+		result.Results = append(result.Results, okMessage(node, "restarted containerd", out))
+
+		if result.RolledBack {
+			continue
+		}
+		v := verifyNode(ctx, mgr, node, verifyCmd)
+		result.Verification = append(result.Verification, v)
+		if !v.Verified && result.NodeStates[node] == NodeConfigured {
+			result.NodeStates[node] = NodeUnverified
 		}
 	}
 
-	return results, nil
+	return result, nil
 }
 
-// filterNodes filters node names based on the selector.
-func filterNodes(nodes []string, selector string) []string {
-	if selector == "all" {
-		return nodes
+// verifyCommand returns a node script that waits for containerd to serve CRI, then
+// checks that config_path is active in the running config and every hosts.toml exists.
+// It prints one line per problem and exits non-zero if there are any.
+func verifyCommand(mirrorCfg *MirrorConfig) []string {
+	var files []string
+	for _, f := range mirrorCfg.Files {
+		if strings.HasPrefix(f.Path, certsDir+"/") {
+			files = append(files, f.Path)
+		}
+	}
+	script := `ready=0
+for i in $(seq 1 30); do crictl info >/dev/null 2>&1 && { ready=1; break; }; sleep 1; done
+[ "$ready" = 1 ] || { echo "containerd not serving CRI after 30s"; exit 1; }
+rc=0
+containerd config dump 2>/dev/null | grep -Eq "config_path = [\"']` + certsDir + `[\"']" || { echo "config_path not active in running containerd config"; rc=1; }
+for f in ` + strings.Join(files, " ") + `; do [ -f "$f" ] || { echo "missing $f"; rc=1; }; done
+exit $rc`
+	return []string{"bash", "-c", script}
+}
+
+// verifyNode runs the verification script on a node.
+func verifyNode(ctx context.Context, mgr *kind.Manager, node string, cmd []string) NodeVerification {
+	v := NodeVerification{Node: node, Verified: true}
+	out, err := mgr.ExecOnNode(ctx, node, cmd)
+	if err == nil {
+		return v
+	}
+	v.Verified = false
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "Output:") {
+			v.Problems = append(v.Problems, line)
+		}
+	}
+	if len(v.Problems) == 0 {
+		v.Problems = []string{err.Error()}
+	}
+	return v
+}
+
+// removeDirs deletes registry config directories from a node.
+func removeDirs(ctx context.Context, mgr *kind.Manager, node string, dirs []string) error {
+	if len(dirs) == 0 {
+		return nil
+	}
+	_, err := mgr.ExecOnNode(ctx, node, append([]string{"rm", "-rf"}, dirs...))
+	return err
+}
+
+func okMessage(node, description, out string) string {
+	msg := fmt.Sprintf("OK [%s] %s", node, description)
+	if trimmed := strings.TrimSpace(out); trimmed != "" {
+		msg += ": " + trimmed
+	}
+	return msg
+}
+
+// PlanMirrorConfig returns the exact commands ApplyMirrorConfig would run on each node
+// of a cluster, without executing any of them.
+func PlanMirrorConfig(ctx context.Context, mgr *kind.Manager, clusterName string, mirrorCfg *MirrorConfig) ([]PlannedCommand, error) {
+	nodes, err := mgr.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster nodes: %w", err)
+	}
+	if len(filterNodes(nodes, "all")) == 0 {
+		return nil, fmt.Errorf("cluster %q not found or has no nodes", clusterName)
 	}
 
+	var plan []PlannedCommand
+	for _, cmd := range mirrorCfg.PostCreateCommands {
+		for _, node := range filterNodes(nodes, cmd.NodeSelector) {
+			plan = append(plan, PlannedCommand{
+				Node:        node,
+				Description: cmd.Description,
+				Command:     mgr.ExecCommandLine(node, cmd.Command),
+			})
+		}
+	}
+	verifyCmd := verifyCommand(mirrorCfg)
+	for _, node := range filterNodes(nodes, "all") {
+		plan = append(plan, PlannedCommand{
+			Node:        node,
+			Description: "restart containerd",
+			Command:     mgr.ExecCommandLine(node, restartContainerdCommand),
+		}, PlannedCommand{
+			Node:        node,
+			Description: "verify containerd is serving with the mirror configuration",
+			Command:     mgr.ExecCommandLine(node, verifyCmd),
+		})
+	}
+	return plan, nil
+}
+
+// filterNodes filters node names based on the selector. The HA external load
+// balancer is never selected since it runs haproxy, not containerd.
+func filterNodes(nodes []string, selector string) []string {
 	var filtered []string
 	for _, n := range nodes {
-		switch selector {
-		case "control-plane":
-			if strings.Contains(n, "control-plane") {
-				filtered = append(filtered, n)
-			}
-		case "worker":
-			if !strings.Contains(n, "control-plane") {
-				filtered = append(filtered, n)
-			}
+		role := kind.NodeRole(n)
+		if role == kind.RoleExternalLoadBalancer {
+			continue
+		}
+		if selector == "all" || selector == role {
+			filtered = append(filtered, n)
 		}
 	}
 	return filtered
@@ -1,10 +1,15 @@
 package registry
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
 )
 
 func TestGenerateMirrorConfig_Basic(t *testing.T) {
@@ -24,9 +29,9 @@ func TestGenerateMirrorConfig_Basic(t *testing.T) {
 		t.Error("containerd patch should contain config_path")
 	}
 
-	// 2 commands per override: mkdir + write hosts.toml
-	if len(cfg.PostCreateCommands) != 2 {
-		t.Errorf("expected 2 post-create commands, got %d", len(cfg.PostCreateCommands))
+	// 2 commands per override (mkdir + write hosts.toml) + 2 to persist config_path
+	if len(cfg.PostCreateCommands) != 4 {
+		t.Errorf("expected 4 post-create commands, got %d", len(cfg.PostCreateCommands))
 	}
 
 	if len(cfg.ExtraMounts) != 0 {
@@ -46,9 +51,9 @@ func TestGenerateMirrorConfig_MultipleOverrides(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// 2 commands per override
-	if len(cfg.PostCreateCommands) != 6 {
-		t.Errorf("expected 6 post-create commands, got %d", len(cfg.PostCreateCommands))
+	// 2 commands per override + 2 to persist config_path
+	if len(cfg.PostCreateCommands) != 8 {
+		t.Errorf("expected 8 post-create commands, got %d", len(cfg.PostCreateCommands))
 	}
 }
 
@@ -164,6 +169,19 @@ func TestFilterNodes(t *testing.T) {
 	}
 }
 
+func TestFilterNodes_SkipsLoadBalancer(t *testing.T) {
+	nodes := []string{"ha-external-load-balancer", "ha-control-plane", "ha-control-plane2", "ha-worker"}
+
+	all := filterNodes(nodes, "all")
+	if len(all) != 3 {
+		t.Errorf("all: got %v, want 3 nodes without the load balancer", all)
+	}
+	workers := filterNodes(nodes, "worker")
+	if len(workers) != 1 || workers[0] != "ha-worker" {
+		t.Errorf("worker: got %v", workers)
+	}
+}
+
 func TestMirrorConfig_MountStruct(t *testing.T) {
 	m := kind.Mount{
 		HostPath:      "/a",
@@ -174,3 +192,315 @@ func TestMirrorConfig_MountStruct(t *testing.T) {
 		t.Errorf("mount = %+v", m)
 	}
 }
+
+func TestGenerateMirrorConfig_Files(t *testing.T) {
+	cfg, err := GenerateMirrorConfig([]RegistryOverride{
+		{Original: "ghcr.io", Mirror: "http://proxy:5001"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// hosts.toml + config_path guard script + systemd drop-in
+	if len(cfg.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(cfg.Files))
+	}
+	if cfg.Files[0].Path != "/etc/containerd/certs.d/ghcr.io/hosts.toml" {
+		t.Errorf("Path = %q", cfg.Files[0].Path)
+	}
+	if !strings.Contains(cfg.Files[0].Content, `[host."http://proxy:5001"]`) {
+		t.Errorf("Content = %q", cfg.Files[0].Content)
+	}
+}
+
+// mockRunner simulates the kind and docker CLIs for a cluster's nodes.
+type mockRunner struct {
+	nodes   string
+	failOn  string
+	execLog [][]string
+}
+
+func (m *mockRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
+	if name == "kind" {
+		return []byte(m.nodes), nil
+	}
+	m.execLog = append(m.execLog, args)
+	if m.failOn != "" && strings.Contains(strings.Join(args, " "), m.failOn) {
+		return []byte("boom"), fmt.Errorf("exit status 1")
+	}
+	return nil, nil
+}
+
+func (m *mockRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	out, err := m.Run(ctx, name, args...)
+	return out, nil, err
+}
+
+func (m *mockRunner) LookPath(name string) (string, error) {
+	return "/usr/bin/" + name, nil
+}
+
+func newMockManager(runner rtdetect.CommandRunner) *kind.Manager {
+	return kind.NewManager(runner, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+}
+
+func TestPlanMirrorConfig(t *testing.T) {
+	runner := &mockRunner{nodes: "ha-external-load-balancer\nha-control-plane\nha-worker\n"}
+	cfg, _ := GenerateMirrorConfig([]RegistryOverride{{Original: "docker.io", Mirror: "http://proxy:5000"}}, nil)
+
+	plan, err := PlanMirrorConfig(context.Background(), newMockManager(runner), "ha", cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 4 commands + restart + verify, on 2 nodes (load balancer skipped)
+	if len(plan) != 12 {
+		t.Errorf("expected 12 planned commands, got %d: %+v", len(plan), plan)
+	}
+	if len(runner.execLog) != 0 {
+		t.Errorf("plan must not execute anything, got %v", runner.execLog)
+	}
+	if !strings.HasPrefix(plan[0].Command, "docker exec ha-control-plane mkdir -p") {
+		t.Errorf("first command = %q", plan[0].Command)
+	}
+}
+
+func TestApplyMirrorConfig_Success(t *testing.T) {
+	runner := &mockRunner{nodes: "dev-control-plane\ndev-worker\n"}
+	cfg, _ := GenerateMirrorConfig([]RegistryOverride{{Original: "docker.io", Mirror: "http://proxy:5000"}}, nil)
+
+	result, err := ApplyMirrorConfig(context.Background(), newMockManager(runner), "dev", cfg, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Failed || result.RolledBack {
+		t.Errorf("unexpected failure: %+v", result)
+	}
+	for node, st := range result.NodeStates {
+		if st != NodeConfigured {
+			t.Errorf("node %s state = %q", node, st)
+		}
+	}
+}
+
+func TestApplyMirrorConfig_RollbackOnFailure(t *testing.T) {
+	// Writing hosts.toml fails everywhere, after the directories were created.
+	runner := &mockRunner{nodes: "dev-control-plane\ndev-worker\n", failOn: "hosts.toml"}
+	cfg, _ := GenerateMirrorConfig([]RegistryOverride{{Original: "docker.io", Mirror: "http://proxy:5000"}}, nil)
+
+	result, err := ApplyMirrorConfig(context.Background(), newMockManager(runner), "dev", cfg,
+		ApplyOptions{RollbackOnFailure: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Failed || !result.RolledBack {
+		t.Fatalf("expected failed + rolled back, got %+v", result)
+	}
+	if result.NodeStates["dev-worker"] != NodeRolledBack {
+		t.Errorf("worker state = %q", result.NodeStates["dev-worker"])
+	}
+
+	removed := 0
+	for _, args := range runner.execLog {
+		if strings.Contains(strings.Join(args, " "), "rm -rf /etc/containerd/certs.d/docker.io") {
+			removed++
+		}
+	}
+	if removed != 2 {
+		t.Errorf("expected rollback on 2 nodes, got %d", removed)
+	}
+}
+
+func TestApplyMirrorConfig_PartialWithoutRollback(t *testing.T) {
+	runner := &mockRunner{nodes: "dev-control-plane\n", failOn: "hosts.toml"}
+	cfg, _ := GenerateMirrorConfig([]RegistryOverride{{Original: "docker.io", Mirror: "http://proxy:5000"}}, nil)
+
+	result, _ := ApplyMirrorConfig(context.Background(), newMockManager(runner), "dev", cfg, ApplyOptions{})
+	if !result.Failed || result.RolledBack {
+		t.Errorf("expected failure without rollback, got %+v", result)
+	}
+	if result.NodeStates["dev-control-plane"] != NodePartial {
+		t.Errorf("state = %q, want partial", result.NodeStates["dev-control-plane"])
+	}
+}
+
+func TestGenerateMirrorConfig_PersistsConfigPath(t *testing.T) {
+	cfg, _ := GenerateMirrorConfig([]RegistryOverride{{Original: "docker.io", Mirror: "http://proxy:5000"}}, nil)
+
+	var dropIn *NodeFile
+	for i := range cfg.Files {
+		if cfg.Files[i].Path == containerdDropIn {
+			dropIn = &cfg.Files[i]
+		}
+	}
+	if dropIn == nil || !strings.Contains(dropIn.Content, "ExecStartPre=/bin/sh "+ensureConfigPath) {
+		t.Fatalf("expected systemd drop-in running the guard script, got %+v", cfg.Files)
+	}
+	last := cfg.PostCreateCommands[len(cfg.PostCreateCommands)-1]
+	if last.Creates != containerdDropIn || !strings.Contains(last.Command[2], "daemon-reload") {
+		t.Errorf("drop-in command = %+v", last)
+	}
+}
+
+func TestApplyMirrorConfig_Verification(t *testing.T) {
+	runner := &mockRunner{nodes: "dev-control-plane\ndev-worker\n"}
+	cfg, _ := GenerateMirrorConfig([]RegistryOverride{{Original: "docker.io", Mirror: "http://proxy:5000"}}, nil)
+
+	result, _ := ApplyMirrorConfig(context.Background(), newMockManager(runner), "dev", cfg, ApplyOptions{})
+	if len(result.Verification) != 2 || !result.Verification[0].Verified {
+		t.Fatalf("verification = %+v", result.Verification)
+	}
+
+	// The verification script reports config_path as inactive.
+	runner = &mockRunner{nodes: "dev-control-plane\n", failOn: "crictl info"}
+	result, _ = ApplyMirrorConfig(context.Background(), newMockManager(runner), "dev", cfg, ApplyOptions{})
+	if result.NodeStates["dev-control-plane"] != NodeUnverified {
+		t.Errorf("state = %q, want unverified", result.NodeStates["dev-control-plane"])
+	}
+	if v := result.Verification[0]; v.Verified || len(v.Problems) == 0 {
+		t.Errorf("verification = %+v", v)
+	}
+}
+
+func TestGenerateHostsToml_TLSOptions(t *testing.T) {
+	override := RegistryOverride{
+		Original:           "registry.corp",
+		Mirror:             "https://mirror.corp:8443",
+		InsecureSkipVerify: true,
+		CAFile:             "/tmp/ca.pem",
+		Capabilities:       []string{"pull", "resolve", "push"},
+	}
+	toml := generateHostsToml(override)
+
+	for _, want := range []string{
+		`capabilities = ["pull", "resolve", "push"]`,
+		"skip_verify = true",
+		`ca = "/etc/containerd/certs.d/registry.corp/ca.crt"`,
+	} {
+		if !strings.Contains(toml, want) {
+			t.Errorf("hosts.toml missing %q:\n%s", want, toml)
+		}
+	}
+}
+
+func TestGenerateMirrorConfig_CAFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	pem := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	if err := os.WriteFile(caFile, []byte(pem), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := GenerateMirrorConfig([]RegistryOverride{
+		{Original: "registry.corp", Mirror: "https://mirror.corp:8443", CAFile: caFile},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var found bool
+	for _, f := range cfg.Files {
+		if f.Path == "/etc/containerd/certs.d/registry.corp/ca.crt" {
+			found = true
+			if f.Content != pem {
+				t.Errorf("CA content = %q", f.Content)
+			}
+		}
+	}
+	if !found {
+		t.Error("CA bundle should be written to the registry's certs.d directory")
+	}
+
+	var installs int
+	for _, cmd := range cfg.PostCreateCommands {
+		if strings.Contains(cmd.Description, "CA certificate") {
+			installs++
+		}
+	}
+	if installs != 1 {
+		t.Errorf("expected one CA install command, got %d", installs)
+	}
+}
+
+func TestGenerateMirrorConfig_InvalidOverrides(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]RegistryOverride{
+		"unknown capability": {Original: "docker.io", Mirror: "http://proxy:5000", Capabilities: []string{"pull", "delete"}},
+		"missing ca file":    {Original: "docker.io", Mirror: "https://proxy", CAFile: "/nonexistent/ca.pem"},
+		"ca not PEM":         {Original: "docker.io", Mirror: "https://proxy", CAFile: notPEM},
+		"missing mirror":     {Original: "docker.io"},
+	}
+	for name, override := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := GenerateMirrorConfig([]RegistryOverride{override}, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestGenerateHostsToml_BasicAuth(t *testing.T) {
+	override := RegistryOverride{
+		Original: "docker.io",
+		Mirror:   "https://artifactory.corp/docker-remote",
+		Username: "svc",
+		Password: "s3cret",
+	}
+	toml := generateHostsToml(override)
+
+	want := `[host."https://artifactory.corp/docker-remote".header]
+  Authorization = ["Basic c3ZjOnMzY3JldA=="]`
+	if !strings.Contains(toml, want) {
+		t.Errorf("hosts.toml missing auth header:\n%s", toml)
+	}
+}
+
+func TestGenerateMirrorConfig_AuthFile(t *testing.T) {
+	authFile := filepath.Join(t.TempDir(), "config.json")
+	content := `{"auths":{"https://harbor.corp:8443":{"auth":"c3ZjOnMzY3JldA=="},"other.io":{"auth":"eDp5"}}}`
+	if err := os.WriteFile(authFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := GenerateMirrorConfig([]RegistryOverride{
+		{Original: "docker.io", Mirror: "harbor.corp:8443", AuthFile: authFile},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(cfg.Files[0].Content, "Basic c3ZjOnMzY3JldA==") {
+		t.Errorf("expected credentials from auth file:\n%s", cfg.Files[0].Content)
+	}
+
+	var restricted bool
+	for _, cmd := range cfg.PostCreateCommands {
+		if strings.HasPrefix(cmd.Description, "Configure mirror") && strings.Contains(cmd.Command[2], "umask 077") {
+			restricted = true
+		}
+	}
+	if !restricted {
+		t.Error("hosts.toml with credentials should be written with a restrictive umask")
+	}
+
+	_, err = GenerateMirrorConfig([]RegistryOverride{
+		{Original: "quay.io", Mirror: "http://unknown:5000", AuthFile: authFile},
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no credentials for unknown:5000") {
+		t.Errorf("expected missing-entry error, got %v", err)
+	}
+}
+
+func TestGenerateMirrorConfig_AuthValidation(t *testing.T) {
+	tests := map[string]RegistryOverride{
+		"username only":      {Original: "docker.io", Mirror: "https://m", Username: "u"},
+		"auth file and user": {Original: "docker.io", Mirror: "https://m", Username: "u", Password: "p", AuthFile: "/x"},
+	}
+	for name, override := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := GenerateMirrorConfig([]RegistryOverride{override}, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// NodeRemoved is the node state RemoveMirrorConfig reports when every registry's
+// directory was deleted and containerd came back serving CRI.
+const NodeRemoved = "removed"
+
+// mirrorDirs returns the certs.d directories of registries, rejecting names that
+// would resolve outside certs.d.
+func mirrorDirs(registries []string) ([]string, error) {
+	if len(registries) == 0 {
+		return nil, fmt.Errorf("at least one registry is required")
+	}
+	dirs := make([]string, 0, len(registries))
+	for _, reg := range registries {
+		if reg == "" || reg == "." || reg == ".." || strings.ContainsAny(reg, "/\\ ") {
+			return nil, fmt.Errorf("invalid registry %q; use a host[:port] such as docker.io or localhost:5000", reg)
+		}
+		dirs = append(dirs, path.Join(certsDir, reg))
+	}
+	return dirs, nil
+}
+
+// RemoveMirrorConfig undoes ApplyMirrorConfig for registries: it deletes their
+// hosts.toml directories (including any CA bundle) from every node, then restarts
+// containerd node by node, waiting for it to serve CRI before moving on. Pulls from
+// those registries go to the upstream registry again.
+func RemoveMirrorConfig(ctx context.Context, mgr *kind.Manager, clusterName string, registries []string) (*ApplyResult, error) {
+	dirs, err := mirrorDirs(registries)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := mgr.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster nodes: %w", err)
+	}
+	nodes = filterNodes(nodes, "all")
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q not found or has no nodes", clusterName)
+	}
+
+	result := &ApplyResult{NodeStates: make(map[string]string)}
+	verifyCmd := verifyCommand(&MirrorConfig{})
+	for _, node := range nodes {
+		if err := removeDirs(ctx, mgr, node, dirs); err != nil {
+			result.Results = append(result.Results, fmt.Sprintf("FAILED [%s] remove %s: %v", node, strings.Join(dirs, ", "), err))
+			result.NodeStates[node] = NodePartial
+			result.Failed = true
+			continue
+		}
+		result.Results = append(result.Results, fmt.Sprintf("OK [%s] removed %s", node, strings.Join(dirs, ", ")))
+
+		out, err := mgr.ExecOnNode(ctx, node, restartContainerdCommand)
+		if err != nil {
+			result.Results = append(result.Results, fmt.Sprintf("FAILED [%s] restart containerd: %v", node, err))
+			result.NodeStates[node] = NodePartial
+			result.Failed = true
+			continue
+		}
+		result.Results = append(result.Results, okMessage(node, "restarted containerd", out))
+
+		v := verifyNode(ctx, mgr, node, verifyCmd)
+		result.Verification = append(result.Verification, v)
+		result.NodeStates[node] = NodeRemoved
+		if !v.Verified {
+			result.NodeStates[node] = NodeUnverified
+		}
+	}
+	return result, nil
+}
+
+// PlanMirrorRemoval returns the commands RemoveMirrorConfig would run on each node,
+// without executing any of them.
+func PlanMirrorRemoval(ctx context.Context, mgr *kind.Manager, clusterName string, registries []string) ([]PlannedCommand, error) {
+	dirs, err := mirrorDirs(registries)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := mgr.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster nodes: %w", err)
+	}
+	nodes = filterNodes(nodes, "all")
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q not found or has no nodes", clusterName)
+	}
+
+	verifyCmd := verifyCommand(&MirrorConfig{})
+	var plan []PlannedCommand
+	for _, node := range nodes {
+		plan = append(plan,
+			PlannedCommand{
+				Node:        node,
+				Description: "remove " + strings.Join(dirs, ", "),
+				Command:     mgr.ExecCommandLine(node, append([]string{"rm", "-rf"}, dirs...)),
+			},
+			PlannedCommand{
+				Node:        node,
+				Description: "restart containerd",
+				Command:     mgr.ExecCommandLine(node, restartContainerdCommand),
+			},
+			PlannedCommand{
+				Node:        node,
+				Description: "verify containerd is serving CRI",
+				Command:     mgr.ExecCommandLine(node, verifyCmd),
+			})
+	}
+	return plan, nil
+}
@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRemoveMirrorConfig(t *testing.T) {
+	runner := &mockRunner{nodes: "dev-external-load-balancer\ndev-control-plane\ndev-worker\n"}
+	result, err := RemoveMirrorConfig(context.Background(), newMockManager(runner), "dev",
+		[]string{"docker.io", "localhost:5000"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Failed || len(result.NodeStates) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	for node, st := range result.NodeStates {
+		if st != NodeRemoved {
+			t.Errorf("node %s state = %q", node, st)
+		}
+	}
+	removed := 0
+	for _, args := range runner.execLog {
+		if strings.Contains(strings.Join(args, " "),
+			"rm -rf /etc/containerd/certs.d/docker.io /etc/containerd/certs.d/localhost:5000") {
+			removed++
+		}
+	}
+	if removed != 2 {
+		t.Errorf("expected removal on 2 nodes, got %d: %v", removed, runner.execLog)
+	}
+}
+
+func TestRemoveMirrorConfig_RestartFails(t *testing.T) {
+	runner := &mockRunner{nodes: "dev-control-plane\n", failOn: "restart containerd"}
+	result, err := RemoveMirrorConfig(context.Background(), newMockManager(runner), "dev", []string{"docker.io"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Failed || result.NodeStates["dev-control-plane"] != NodePartial {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestRemoveMirrorConfig_InvalidRegistry(t *testing.T) {
+	for _, regs := range [][]string{nil, {""}, {".."}, {"docker.io/../../etc"}} {
+		if _, err := RemoveMirrorConfig(context.Background(), newMockManager(&mockRunner{}), "dev", regs); err == nil {
+			t.Errorf("expected error for %q", regs)
+		}
+	}
+}
+
+func TestPlanMirrorRemoval(t *testing.T) {
+	runner := &mockRunner{nodes: "dev-control-plane\ndev-worker\n"}
+	plan, err := PlanMirrorRemoval(context.Background(), newMockManager(runner), "dev", []string{"docker.io"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan) != 6 || len(runner.execLog) != 0 {
+		t.Errorf("plan = %+v, executed %v", plan, runner.execLog)
+	}
+	if plan[0].Command != "docker exec dev-control-plane rm -rf /etc/containerd/certs.d/docker.io" {
+		t.Errorf("first command = %q", plan[0].Command)
+	}
+}
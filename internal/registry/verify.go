@@ -0,0 +1,125 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// verifyNamespace is the containerd namespace test pulls go to, so the images the
+// node's Kubernetes (namespace k8s.io) uses, such as its sandbox image, are untouched.
+const verifyNamespace = "mcp-kind-verify"
+
+// defaultTestImages are small, public images used to verify common registries. None is
+// a node's sandbox (pause) image.
+var defaultTestImages = map[string]string{
+	"docker.io":       "docker.io/library/busybox:latest",
+	"registry.k8s.io": "registry.k8s.io/e2e-test-images/busybox:1.36.1-1",
+	"quay.io":         "quay.io/prometheus/busybox:latest",
+	"ghcr.io":         "ghcr.io/linuxcontainers/alpine:latest",
+}
+
+// VerifyResult reports whether a test pull through a mirror succeeded.
+type VerifyResult struct {
+	Registry       string `json:"registry"`
+	Mirror         string `json:"mirror"`
+	Image          string `json:"image"`
+	Node           string `json:"node"`
+	Pulled         bool   `json:"pulled"`
+	ServedByMirror *bool  `json:"served_by_mirror"` // nil when unknown
+	Evidence       string `json:"evidence,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// VerifyMirrors pulls a test image from each overridden registry on one node of the
+// cluster with ctr in debug mode, into the throwaway verifyNamespace, and inspects the
+// resolver's debug output for requests to the mirror host. testImages maps a registry
+// to the image to pull; common registries have defaults. ServedByMirror is nil, and
+// Evidence says why, when the output shows no resolver requests.
+func VerifyMirrors(ctx context.Context, mgr *kind.Manager, clusterName string, overrides []RegistryOverride, testImages map[string]string) ([]VerifyResult, error) {
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("at least one registry override is required")
+	}
+	nodes, err := mgr.GetClusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster nodes: %w", err)
+	}
+	nodes = filterNodes(nodes, "all")
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q not found or has no nodes", clusterName)
+	}
+	node := nodes[0]
+
+	var results []VerifyResult
+	for _, o := range overrides {
+		res := VerifyResult{Registry: o.Original, Mirror: o.Mirror, Node: node}
+		res.Image = testImages[o.Original]
+		if res.Image == "" {
+			res.Image = defaultTestImages[o.Original]
+		}
+		if res.Image == "" {
+			res.Error = fmt.Sprintf("no test image known for %s; pass one in 'test_images'", o.Original)
+			results = append(results, res)
+			continue
+		}
+
+		verifyPull(ctx, mgr, &res)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// verifyPull pulls res.Image on res.Node, resolving it through the node's certs.d like
+// the CRI plugin does, and removes it again.
+func verifyPull(ctx context.Context, mgr *kind.Manager, res *VerifyResult) {
+	out, err := mgr.ExecOnNode(ctx, res.Node, []string{
+		"ctr", "--debug", "--namespace", verifyNamespace, "images", "pull", "--hosts-dir", certsDir, res.Image,
+	})
+	_, _ = mgr.ExecOnNode(ctx, res.Node, []string{"ctr", "--namespace", verifyNamespace, "images", "rm", "--sync", res.Image})
+	if err != nil {
+		res.Error = fmt.Sprintf("pull failed: %v", err)
+		return
+	}
+	res.Pulled = true
+	res.ServedByMirror, res.Evidence = mirrorEvidence(out, mirrorHost(res.Mirror))
+}
+
+// mirrorEvidence scans containerd resolver debug output for requests to the mirror
+// host. It returns nil when the output holds no resolver requests at all.
+func mirrorEvidence(logs, host string) (*bool, string) {
+	var fallback string
+	requests := false
+	for _, line := range strings.Split(logs, "\n") {
+		if strings.Contains(line, "do request") || strings.Contains(line, "resolving") {
+			requests = true
+		}
+		if !strings.Contains(line, host) {
+			continue
+		}
+		if strings.Contains(line, "trying next host") || strings.Contains(line, "failed") {
+			fallback = strings.TrimSpace(line)
+			continue
+		}
+		served := true
+		return &served, strings.TrimSpace(line)
+	}
+	served := false
+	if fallback != "" {
+		return &served, "mirror was tried but failed, pull fell back to upstream: " + fallback
+	}
+	if !requests {
+		return nil, "unknown: the pull output shows no resolver requests"
+	}
+	return &served, fmt.Sprintf("no requests to %s in the resolver output; the pull went upstream", host)
+}
+
+// mirrorHost strips the scheme and path from a mirror URL.
+func mirrorHost(mirror string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(mirror, "http://"), "https://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	return host
+}
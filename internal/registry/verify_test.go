@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// verifyRunner answers the node commands issued by VerifyMirrors.
+type verifyRunner struct {
+	mockRunner
+	pullOutput string
+}
+
+func (v *verifyRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := v.mockRunner.Run(ctx, name, args...)
+	if err == nil && slices.Contains(args, "pull") {
+		return []byte(v.pullOutput), nil
+	}
+	return out, err
+}
+
+func TestVerifyMirrors_ServedByMirror(t *testing.T) {
+	runner := &verifyRunner{
+		mockRunner: mockRunner{nodes: "dev-control-plane\n"},
+		pullOutput: `level=debug msg="do request" host="proxy:5000" url="http://proxy:5000/v2/library/busybox/manifests/latest"`,
+	}
+	overrides := []RegistryOverride{{Original: "docker.io", Mirror: "http://proxy:5000"}}
+
+	results, err := VerifyMirrors(context.Background(), newMockManager(runner), "dev", overrides, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Image != "docker.io/library/busybox:latest" {
+		t.Fatalf("results = %+v", results)
+	}
+	if !results[0].Pulled || results[0].ServedByMirror == nil || !*results[0].ServedByMirror {
+		t.Errorf("expected pull served by mirror: %+v", results[0])
+	}
+	if !strings.Contains(results[0].Evidence, "do request") {
+		t.Errorf("evidence = %q", results[0].Evidence)
+	}
+
+	// The pull goes to its own containerd namespace and is removed again, so the
+	// node's Kubernetes images are never touched.
+	var pulls, removals int
+	for _, args := range runner.execLog {
+		cmd := strings.Join(args, " ")
+		if strings.Contains(cmd, "crictl") || strings.Contains(cmd, "k8s.io") {
+			t.Errorf("command touches the node's Kubernetes images: %s", cmd)
+		}
+		if strings.Contains(cmd, "--namespace "+verifyNamespace+" images pull") {
+			pulls++
+		}
+		if strings.Contains(cmd, "--namespace "+verifyNamespace+" images rm") {
+			removals++
+		}
+	}
+	if pulls != 1 || removals != 1 {
+		t.Errorf("pulls = %d, removals = %d, commands = %v", pulls, removals, runner.execLog)
+	}
+}
+
+func TestVerifyMirrors_Unknown(t *testing.T) {
+	runner := &verifyRunner{
+		mockRunner: mockRunner{nodes: "dev-control-plane\n"},
+		pullOutput: "registry.k8s.io/e2e-test-images/busybox:1.36.1-1: resolved\n",
+	}
+	overrides := []RegistryOverride{{Original: "registry.k8s.io", Mirror: "http://proxy:5000"}}
+
+	results, err := VerifyMirrors(context.Background(), newMockManager(runner), "dev", overrides, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].Pulled || results[0].ServedByMirror != nil || !strings.HasPrefix(results[0].Evidence, "unknown") {
+		t.Errorf("expected an unknown result: %+v", results[0])
+	}
+	if strings.Contains(results[0].Image, "pause") {
+		t.Errorf("test image %q is a sandbox image", results[0].Image)
+	}
+}
+
+func TestVerifyMirrors_UnknownRegistry(t *testing.T) {
+	runner := &mockRunner{nodes: "dev-control-plane\n"}
+	overrides := []RegistryOverride{{Original: "registry.corp", Mirror: "http://proxy:5000"}}
+
+	results, err := VerifyMirrors(context.Background(), newMockManager(runner), "dev", overrides, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Error == "" {
+		t.Error("expected error for registry without a test image")
+	}
+
+	results, _ = VerifyMirrors(context.Background(), newMockManager(runner), "dev", overrides,
+		map[string]string{"registry.corp": "registry.corp/team/app:1.0"})
+	if !results[0].Pulled {
+		t.Errorf("expected pull with explicit test image: %+v", results[0])
+	}
+}
+
+func TestMirrorEvidence_Fallback(t *testing.T) {
+	logs := `msg="trying next host" error="connection refused" host="proxy:5000"`
+	served, evidence := mirrorEvidence(logs, "proxy:5000")
+	if served == nil || *served {
+		t.Error("expected fallback to be reported as not served by mirror")
+	}
+	if !strings.Contains(evidence, "fell back") {
+		t.Errorf("evidence = %q", evidence)
+	}
+}
+
+func TestMirrorHost(t *testing.T) {
+	if got := mirrorHost("https://mirror.corp:8443/v2/"); got != "mirror.corp:8443" {
+		t.Errorf("mirrorHost = %q", got)
+	}
+}
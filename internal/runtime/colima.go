@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ColimaInfo holds the VM details reported by 'colima status --json'.
+type ColimaInfo struct {
+	Profile string `json:"profile"`
+	// Runtime is the container runtime inside the VM: docker or containerd.
+	Runtime     string `json:"runtime"`
+	Arch        string `json:"arch,omitempty"`
+	VMType      string `json:"vm_type,omitempty"`
+	MountType   string `json:"mount_type,omitempty"`
+	CPUs        int    `json:"cpus"`
+	MemoryBytes int64  `json:"memory_bytes"`
+	DiskBytes   int64  `json:"disk_bytes"`
+	// Address is the VM's routable IP, set only when Colima was started with --network-address.
+	Address string `json:"address,omitempty"`
+}
+
+// NetworkAddress reports whether the Colima VM has a routable address on the host.
+func (c *ColimaInfo) NetworkAddress() bool {
+	return c != nil && c.Address != ""
+}
+
+// colimaStatus is a subset of 'colima status --json' output.
+type colimaStatus struct {
+	DisplayName string `json:"display_name"`
+	Driver      string `json:"driver"`
+	Arch        string `json:"arch"`
+	Runtime     string `json:"runtime"`
+	MountType   string `json:"mount_type"`
+	IPAddress   string `json:"ip_address"`
+	CPU         int    `json:"cpu"`
+	Memory      int64  `json:"memory"`
+	Disk        int64  `json:"disk"`
+}
+
+// probeColima queries the Colima profile that serves the given Docker socket.
+func (d *Detector) probeColima(ctx context.Context, socketPath string) (*ColimaInfo, error) {
+	if _, err := d.runner.LookPath("colima"); err != nil {
+		return nil, err
+	}
+	profile := colimaProfile(socketPath)
+	args := []string{"status", "--json"}
+	if profile != "default" {
+		args = append(args, "--profile", profile)
+	}
+	out, err := d.runner.Run(ctx, "colima", args...)
+	if err != nil {
+		return nil, fmt.Errorf("colima status failed: %w", err)
+	}
+
+	var cs colimaStatus
+	if err := json.Unmarshal(out, &cs); err != nil {
+		return nil, fmt.Errorf("parsing colima status: %w", err)
+	}
+	return &ColimaInfo{
+		Profile:     profile,
+		Runtime:     cs.Runtime,
+		Arch:        cs.Arch,
+		VMType:      cs.Driver,
+		MountType:   cs.MountType,
+		CPUs:        cs.CPU,
+		MemoryBytes: cs.Memory,
+		DiskBytes:   cs.Disk,
+		Address:     cs.IPAddress,
+	}, nil
+}
+
+// colimaProfile extracts the profile name from a socket path such as
+// ~/.colima/<profile>/docker.sock, defaulting to "default".
+func colimaProfile(socketPath string) string {
+	dir := filepath.Dir(socketPath)
+	if filepath.Base(filepath.Dir(dir)) == ".colima" {
+		if name := filepath.Base(dir); name != "" && !strings.HasPrefix(name, "_") {
+			return name
+		}
+	}
+	return "default"
+}
@@ -1,14 +1,21 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/logging"
 )
 
 // Runtime represents a container runtime type.
@@ -36,18 +43,35 @@ const (
 
 // RuntimeInfo holds information about the detected container runtime.
 type RuntimeInfo struct {
-	Runtime    Runtime `json:"runtime"`
-	Backend    Backend `json:"backend"`
-	Version    string  `json:"version"`
-	SocketPath string  `json:"socket_path,omitempty"`
-	OS         OSInfo  `json:"os"`
-	Available  bool    `json:"available"`
-	Error      string  `json:"error,omitempty"`
+	Runtime Runtime `json:"runtime"`
+	Backend Backend `json:"backend"`
+	Version string  `json:"version"`
+	// Arch is the container engine's architecture in GOARCH form (e.g. arm64). It can
+	// differ from OS.Arch when this binary runs under emulation.
+	Arch string `json:"arch,omitempty"`
+	// CPUs and MemoryBytes are what the container engine can give its containers: the
+	// VM's resources for Docker Desktop, Colima or Podman machines, else the host's.
+	CPUs        int    `json:"cpus,omitempty"`
+	MemoryBytes int64  `json:"memory_bytes,omitempty"`
+	SocketPath  string `json:"socket_path,omitempty"`
+	OS          OSInfo `json:"os"`
+	Available   bool   `json:"available"`
+	// Colima is set when the backend is Colima and 'colima status' could be queried.
+	Colima *ColimaInfo `json:"colima,omitempty"`
+	// WSL is set when the backend is WSL.
+	WSL *WSLInfo `json:"wsl,omitempty"`
+	// Podman is set when the runtime is Podman.
+	Podman *PodmanInfo `json:"podman,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	// AllRuntimes is set by DetectAll: every installed runtime, available or not, in
+	// order of preference. The enclosing RuntimeInfo is the one Detect would pick.
+	AllRuntimes []RuntimeInfo `json:"all_runtimes,omitempty"`
 }
 
 // CommandRunner abstracts command execution for testability.
 type CommandRunner interface {
 	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+	RunSeparate(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error)
 	LookPath(name string) (string, error)
 }
 
@@ -56,18 +80,51 @@ type ExecCommandRunner struct{}
 
 // Run executes a command and returns combined output.
 func (r *ExecCommandRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
+	cmd := newCommand(ctx, name, args...)
 	return cmd.CombinedOutput()
 }
 
+// RunSeparate executes a command and returns stdout and stderr separately.
+func (r *ExecCommandRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	cmd := newCommand(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
 // LookPath searches for an executable in PATH.
 func (r *ExecCommandRunner) LookPath(name string) (string, error) {
 	return exec.LookPath(name)
 }
 
+// ExitCode returns the process exit code carried by an error returned from a
+// CommandRunner: 0 for nil, the exit status for a process that ran, and -1 if the
+// process could not be started.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// defaultProbeTimeout bounds each runtime's info call during Detect. A runtime whose
+// VM is asleep can hang for much longer; it is reported as unavailable instead.
+const defaultProbeTimeout = 5 * time.Second
+
 // Detector detects container runtime information.
 type Detector struct {
-	runner CommandRunner
+	runner       CommandRunner
+	preferred    Runtime
+	probeTimeout time.Duration
+
+	mu       sync.Mutex
+	lastUsed Runtime // the runtime the previous Detect returned
 }
 
 // NewDetector creates a new Detector with the given CommandRunner.
@@ -75,7 +132,14 @@ func NewDetector(runner CommandRunner) *Detector {
 	if runner == nil {
 		runner = &ExecCommandRunner{}
 	}
-	return &Detector{runner: runner}
+	return &Detector{runner: runner, probeTimeout: defaultProbeTimeout}
+}
+
+// Prefer makes Detect choose rt when both runtimes are available; without a
+// preference the runtime returned by the previous Detect wins, then Docker.
+// Detect still falls back to the other runtime when rt is not installed or not running.
+func (d *Detector) Prefer(rt Runtime) {
+	d.preferred = rt
 }
 
 // dockerInfo is a subset of docker info JSON output.
@@ -84,19 +148,24 @@ type dockerInfo struct {
 	OperatingSystem string `json:"OperatingSystem"`
 	OSType          string `json:"OSType"`
 	Architecture    string `json:"Architecture"`
+	NCPU            int    `json:"NCPU"`
+	MemTotal        int64  `json:"MemTotal"`
 	Name            string `json:"Name"`
 }
 
 // podmanInfo is a subset of podman info JSON output.
 type podmanInfo struct {
 	Host struct {
+		podmanHostNetwork
 		RemoteSocket struct {
 			Path   string `json:"path"`
 			Exists bool   `json:"exists"`
 		} `json:"remoteSocket"`
-		OS      string `json:"os"`
-		Arch    string `json:"arch"`
-		Version struct {
+		OS       string `json:"os"`
+		Arch     string `json:"arch"`
+		CPUs     int    `json:"cpus"`
+		MemTotal int64  `json:"memTotal"`
+		Version  struct {
 			Version string `json:"Version"`
 		} `json:"version"`
 	} `json:"host"`
@@ -104,6 +173,16 @@ type podmanInfo struct {
 
 // Detect detects the container runtime and backend.
 func (d *Detector) Detect(ctx context.Context) RuntimeInfo {
+	return d.detect(ctx, false)
+}
+
+// DetectAll is Detect, but waits for every probe and lists each installed runtime in
+// AllRuntimes, so a caller can see both when Docker and Podman are available.
+func (d *Detector) DetectAll(ctx context.Context) RuntimeInfo {
+	return d.detect(ctx, true)
+}
+
+func (d *Detector) detect(ctx context.Context, all bool) RuntimeInfo {
 	osInfo := DetectOS()
 	info := RuntimeInfo{
 		Runtime:   RuntimeUnknown,
@@ -112,22 +191,94 @@ func (d *Detector) Detect(ctx context.Context) RuntimeInfo {
 		OS:        osInfo,
 	}
 
-	// Try Docker first
-	if _, err := d.runner.LookPath("docker"); err == nil {
-		if ri, err := d.detectDocker(ctx, osInfo); err == nil {
-			return ri
+	// Probe both runtimes at once so a hung one does not delay the other, then take the
+	// first available in order of preference.
+	probes := []struct {
+		rt     Runtime
+		detect func(context.Context, OSInfo) (RuntimeInfo, error)
+	}{
+		{RuntimeDocker, d.detectDocker},
+		{RuntimePodman, d.detectPodman},
+	}
+	if d.preference() == RuntimePodman {
+		probes[0], probes[1] = probes[1], probes[0]
+	}
+	// Once a probe wins, cancel the other (unless all are wanted) and wait for it to exit.
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	log := logging.FromContext(ctx, slog.New(slog.DiscardHandler))
+	type probeResult struct {
+		info      RuntimeInfo
+		err       error
+		installed bool
+	}
+	results := make([]chan probeResult, len(probes))
+	for i, p := range probes {
+		results[i] = make(chan probeResult, 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			var res probeResult
+			if _, err := d.runner.LookPath(string(p.rt)); err != nil {
+				res.err = err
+			} else {
+				res.info, res.err = p.detect(ctx, osInfo)
+				res.installed = true
+			}
+			log.Debug("runtime probe finished", "runtime", p.rt, "duration", time.Since(start), "error", res.err)
+			results[i] <- res
+		}()
+	}
+	var selected *RuntimeInfo
+	var installed []RuntimeInfo
+	for _, ch := range results {
+		res := <-ch
+		if res.installed {
+			if res.err != nil {
+				res.info.Available, res.info.Error = false, res.err.Error()
+			}
+			installed = append(installed, res.info)
+		}
+		if res.err == nil && selected == nil {
+			selected = &res.info
+			if !all {
+				break
+			}
 		}
 	}
-
-	// Try Podman
-	if _, err := d.runner.LookPath("podman"); err == nil {
-		if ri, err := d.detectPodman(ctx, osInfo); err == nil {
-			return ri
+	if selected == nil {
+		info.Error = "no container runtime detected; install Docker or Podman"
+		if all {
+			info.AllRuntimes = installed
 		}
+		return info
 	}
+	d.mu.Lock()
+	d.lastUsed = selected.Runtime
+	d.mu.Unlock()
+	if all {
+		selected.AllRuntimes = installed
+	}
+	return *selected
+}
+
+// preference returns the runtime Detect tries first: the configured one, else the
+// last detected one.
+func (d *Detector) preference() Runtime {
+	if d.preferred != "" {
+		return d.preferred
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastUsed
+}
 
-	info.Error = "no container runtime detected; install Docker or Podman"
-	return info
+// probeContext bounds a runtime's info call by the per-probe timeout.
+func (d *Detector) probeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d.probeTimeout)
 }
 
 func (d *Detector) detectDocker(ctx context.Context, osInfo OSInfo) (RuntimeInfo, error) {
@@ -137,7 +288,9 @@ func (d *Detector) detectDocker(ctx context.Context, osInfo OSInfo) (RuntimeInfo
 		OS:        osInfo,
 	}
 
-	out, err := d.runner.Run(ctx, "docker", "info", "--format", "{{json .}}")
+	probeCtx, cancel := d.probeContext(ctx)
+	out, err := d.runner.Run(probeCtx, "docker", "info", "--format", "{{json .}}")
+	cancel()
 	if err != nil {
 		return info, fmt.Errorf("docker info failed: %w", err)
 	}
@@ -148,8 +301,17 @@ func (d *Detector) detectDocker(ctx context.Context, osInfo OSInfo) (RuntimeInfo
 	}
 
 	info.Version = di.ServerVersion
+	info.Arch = NormalizeArch(di.Architecture)
+	info.CPUs, info.MemoryBytes = di.NCPU, di.MemTotal
 	info.Backend = detectDockerBackend(di, osInfo)
 	info.SocketPath = detectDockerSocket()
+	if info.Backend == BackendColima {
+		// Best effort: the runtime works without the colima CLI on PATH.
+		info.Colima, _ = d.probeColima(ctx, info.SocketPath)
+	}
+	if info.Backend == BackendWSL {
+		info.WSL = d.probeWSL(ctx)
+	}
 
 	return info, nil
 }
@@ -161,7 +323,9 @@ func (d *Detector) detectPodman(ctx context.Context, osInfo OSInfo) (RuntimeInfo
 		OS:        osInfo,
 	}
 
-	out, err := d.runner.Run(ctx, "podman", "info", "--format", "json")
+	probeCtx, cancel := d.probeContext(ctx)
+	out, err := d.runner.Run(probeCtx, "podman", "info", "--format", "json")
+	cancel()
 	if err != nil {
 		return info, fmt.Errorf("podman info failed: %w", err)
 	}
@@ -172,8 +336,14 @@ func (d *Detector) detectPodman(ctx context.Context, osInfo OSInfo) (RuntimeInfo
 	}
 
 	info.Version = pi.Host.Version.Version
+	info.Arch = NormalizeArch(pi.Host.Arch)
+	info.CPUs, info.MemoryBytes = pi.Host.CPUs, pi.Host.MemTotal
 	info.SocketPath = pi.Host.RemoteSocket.Path
 	info.Backend = d.detectPodmanBackend(ctx, osInfo)
+	info.Podman = podmanNetwork(pi.Host.podmanHostNetwork)
+	if info.Backend == BackendWSL {
+		info.WSL = d.probeWSL(ctx)
+	}
 
 	return info, nil
 }
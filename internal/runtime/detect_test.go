@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 // mockRunner implements CommandRunner for testing.
@@ -25,6 +28,11 @@ func (m *mockRunner) LookPath(name string) (string, error) {
 	return "/usr/bin/" + name, nil
 }
 
+func (m *mockRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	out, err := m.Run(ctx, name, args...)
+	return out, nil, err
+}
+
 func (m *mockRunner) Run(_ context.Context, name string, args ...string) ([]byte, error) {
 	key := name
 	if len(args) > 0 {
@@ -41,6 +49,8 @@ func TestDetect_DockerDesktop(t *testing.T) {
 		ServerVersion:   "27.0.3",
 		OperatingSystem: "Docker Desktop",
 		Name:            "docker-desktop",
+		NCPU:            8,
+		MemTotal:        8 << 30,
 	}
 	diJSON, _ := json.Marshal(di)
 
@@ -66,6 +76,9 @@ func TestDetect_DockerDesktop(t *testing.T) {
 	if ri.Backend != BackendDockerDesktop {
 		t.Errorf("Backend = %q, want %q", ri.Backend, BackendDockerDesktop)
 	}
+	if ri.CPUs != 8 || ri.MemoryBytes != 8<<30 {
+		t.Errorf("CPUs, MemoryBytes = %d, %d, want 8, %d", ri.CPUs, ri.MemoryBytes, int64(8<<30))
+	}
 }
 
 func TestDetect_Colima(t *testing.T) {
@@ -94,6 +107,44 @@ func TestDetect_Colima(t *testing.T) {
 	}
 }
 
+func TestDetect_ColimaStatus(t *testing.T) {
+	diJSON, _ := json.Marshal(dockerInfo{ServerVersion: "24.0.7", Name: "colima"})
+	runner := &mockRunner{
+		lookPathResults: map[string]error{},
+		runResults: map[string]runResult{
+			"docker info": {output: diJSON},
+			"colima status": {output: []byte(`{"display_name":"colima","driver":"QEMU","arch":"aarch64",
+				"runtime":"docker","mount_type":"sshfs","ip_address":"192.168.106.2",
+				"cpu":4,"memory":8589934592,"disk":107374182400}`)},
+		},
+	}
+
+	ri := NewDetector(runner).Detect(context.Background())
+
+	if ri.Colima == nil {
+		t.Fatal("expected Colima info")
+	}
+	if ri.Colima.CPUs != 4 || ri.Colima.MemoryBytes != 8589934592 || ri.Colima.Runtime != "docker" {
+		t.Errorf("Colima = %+v", ri.Colima)
+	}
+	if !ri.Colima.NetworkAddress() {
+		t.Error("expected network address to be detected")
+	}
+}
+
+func TestColimaProfile(t *testing.T) {
+	tests := map[string]string{
+		"/Users/me/.colima/default/docker.sock": "default",
+		"/Users/me/.colima/work/docker.sock":    "work",
+		"/var/run/docker.sock":                  "default",
+	}
+	for socket, want := range tests {
+		if got := colimaProfile(socket); got != want {
+			t.Errorf("colimaProfile(%q) = %q, want %q", socket, got, want)
+		}
+	}
+}
+
 func TestDetect_PodmanFallback(t *testing.T) {
 	pi := podmanInfo{}
 	pi.Host.Version.Version = "5.0.0"
@@ -169,3 +220,204 @@ func TestDetect_DockerFailsFallsToPodman(t *testing.T) {
 		t.Errorf("Version = %q, want %q", ri.Version, "4.9.0")
 	}
 }
+
+func TestDetect_PreferPodman(t *testing.T) {
+	pi := podmanInfo{}
+	pi.Host.Version.Version = "5.2.0"
+	piJSON, _ := json.Marshal(pi)
+
+	runner := &mockRunner{
+		lookPathResults: map[string]error{},
+		runResults: map[string]runResult{
+			"docker info": {output: []byte(`{"ServerVersion":"27.0.3"}`)},
+			"podman info": {output: piJSON},
+		},
+	}
+
+	d := NewDetector(runner)
+	d.Prefer(RuntimePodman)
+	if ri := d.Detect(context.Background()); ri.Runtime != RuntimePodman {
+		t.Errorf("Runtime = %q, want %q", ri.Runtime, RuntimePodman)
+	}
+
+	runner.lookPathResults["podman"] = fmt.Errorf("not found")
+	if ri := d.Detect(context.Background()); ri.Runtime != RuntimeDocker {
+		t.Errorf("Runtime without podman = %q, want %q", ri.Runtime, RuntimeDocker)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if got := ExitCode(nil); got != 0 {
+		t.Errorf("ExitCode(nil) = %d, want 0", got)
+	}
+	if got := ExitCode(fmt.Errorf("not started")); got != -1 {
+		t.Errorf("ExitCode(generic) = %d, want -1", got)
+	}
+
+	r := &ExecCommandRunner{}
+	_, _, err := r.RunSeparate(context.Background(), "sh", "-c", "exit 3")
+	if got := ExitCode(err); got != 3 {
+		t.Errorf("ExitCode(exit 3) = %d, want 3", got)
+	}
+}
+
+func TestProbeWSL_Mirrored(t *testing.T) {
+	home := t.TempDir()
+	conf := filepath.Join(t.TempDir(), "wsl.conf")
+	os.WriteFile(conf, []byte("[boot]\nsystemd=true\n[interop]\nenabled = true\n"), 0o644)
+	os.WriteFile(filepath.Join(home, ".wslconfig"),
+		[]byte("[wsl2]\nmemory=8GB\nnetworkingMode=Mirrored # shared with Windows\n"), 0o644)
+	defer func(old string) { wslConfPath = old }(wslConfPath)
+	wslConfPath = conf
+
+	runner := &mockRunner{runResults: map[string]runResult{
+		"cmd.exe /c": {output: []byte("C:\\Users\\me\r\n")},
+		"wslpath -u": {output: []byte(home + "\n")},
+	}}
+	info := NewDetector(runner).probeWSL(context.Background())
+
+	if !info.Mirrored() {
+		t.Errorf("NetworkingMode = %q, want mirrored", info.NetworkingMode)
+	}
+	if !info.LocalhostForwarding {
+		t.Error("expected localhostForwarding to default to true")
+	}
+	if info.WSLConfig != filepath.Join(home, ".wslconfig") {
+		t.Errorf("WSLConfig = %q", info.WSLConfig)
+	}
+}
+
+func TestProbeWSL_InteropDisabled(t *testing.T) {
+	conf := filepath.Join(t.TempDir(), "wsl.conf")
+	os.WriteFile(conf, []byte("[interop]\nenabled=false\n"), 0o644)
+	defer func(old string) { wslConfPath = old }(wslConfPath)
+	wslConfPath = conf
+
+	info := NewDetector(&mockRunner{}).probeWSL(context.Background())
+
+	if info.Interop || info.NetworkingMode != WSLNetworkingNAT || info.WSLConfig != "" {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+// hangingRunner blocks runs of one binary until their context ends, like a runtime
+// whose VM is asleep.
+type hangingRunner struct {
+	*mockRunner
+	hang string
+}
+
+func (h *hangingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if name == h.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return h.mockRunner.Run(ctx, name, args...)
+}
+
+func TestDetect_HungRuntimeTimesOut(t *testing.T) {
+	pi := podmanInfo{}
+	pi.Host.Version.Version = "5.2.0"
+	piJSON, _ := json.Marshal(pi)
+
+	runner := &hangingRunner{
+		mockRunner: &mockRunner{runResults: map[string]runResult{"podman info": {output: piJSON}}},
+		hang:       "docker",
+	}
+	d := NewDetector(runner)
+	d.probeTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	if ri := d.Detect(context.Background()); ri.Runtime != RuntimePodman {
+		t.Errorf("Runtime = %q, want %q", ri.Runtime, RuntimePodman)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Detect took %v with a hung docker", elapsed)
+	}
+}
+
+func TestDetect_PrefersLastUsed(t *testing.T) {
+	pi := podmanInfo{}
+	pi.Host.Version.Version = "5.2.0"
+	piJSON, _ := json.Marshal(pi)
+
+	runner := &mockRunner{
+		lookPathResults: map[string]error{"docker": fmt.Errorf("not found")},
+		runResults: map[string]runResult{
+			"docker info": {output: []byte(`{"ServerVersion":"27.0.3"}`)},
+			"podman info": {output: piJSON},
+		},
+	}
+	d := NewDetector(runner)
+	if ri := d.Detect(context.Background()); ri.Runtime != RuntimePodman {
+		t.Fatalf("Runtime = %q, want %q", ri.Runtime, RuntimePodman)
+	}
+
+	// Docker becoming available does not switch runtimes between calls.
+	delete(runner.lookPathResults, "docker")
+	if ri := d.Detect(context.Background()); ri.Runtime != RuntimePodman {
+		t.Errorf("Runtime after docker appeared = %q, want %q", ri.Runtime, RuntimePodman)
+	}
+}
+
+func TestDetectAll(t *testing.T) {
+	pi := podmanInfo{}
+	pi.Host.Version.Version = "5.2.0"
+	pi.Host.RemoteSocket.Path = "/run/user/1000/podman/podman.sock"
+	piJSON, _ := json.Marshal(pi)
+
+	runner := &mockRunner{
+		runResults: map[string]runResult{
+			"docker info": {output: []byte(`{"ServerVersion":"27.0.3"}`)},
+			"podman info": {output: piJSON},
+		},
+	}
+	ri := NewDetector(runner).DetectAll(context.Background())
+	if ri.Runtime != RuntimeDocker {
+		t.Errorf("Runtime = %q, want %q", ri.Runtime, RuntimeDocker)
+	}
+	if len(ri.AllRuntimes) != 2 {
+		t.Fatalf("AllRuntimes = %+v, want docker and podman", ri.AllRuntimes)
+	}
+	if pm := ri.AllRuntimes[1]; pm.Runtime != RuntimePodman || pm.Version != "5.2.0" || !pm.Available {
+		t.Errorf("podman = %+v", pm)
+	}
+
+	// An installed runtime that does not answer is listed as unavailable.
+	runner.runResults["podman info"] = runResult{err: fmt.Errorf("cannot connect")}
+	ri = NewDetector(runner).DetectAll(context.Background())
+	if len(ri.AllRuntimes) != 2 || ri.AllRuntimes[1].Available || ri.AllRuntimes[1].Error == "" {
+		t.Errorf("AllRuntimes = %+v", ri.AllRuntimes)
+	}
+	if ri := NewDetector(runner).Detect(context.Background()); ri.AllRuntimes != nil {
+		t.Errorf("Detect set AllRuntimes: %+v", ri.AllRuntimes)
+	}
+}
+
+func TestPodmanNetwork(t *testing.T) {
+	tests := []struct {
+		name string
+		info string
+		want PodmanInfo
+	}{
+		{"rootless podman 5", `{"host": {"networkBackend": "netavark", "networkBackendInfo": {"dns": {"package": "aardvark-dns-1.12.1"}},
+			"security": {"rootless": true}, "rootlessNetworkCmd": "pasta", "pasta": {"executable": "/usr/bin/pasta"}}}`,
+			PodmanInfo{NetworkBackend: "netavark", DNS: "aardvark-dns", Rootless: true, RootlessNetwork: PodmanRootlessPasta}},
+		{"rootless podman 4", `{"host": {"networkBackend": "netavark", "security": {"rootless": true},
+			"slirp4netns": {"executable": "/usr/bin/slirp4netns"}, "pasta": {"executable": "/usr/bin/pasta"}}}`,
+			PodmanInfo{NetworkBackend: "netavark", Rootless: true, RootlessNetwork: PodmanRootlessSlirp4netns}},
+		{"rootful cni", `{"host": {"networkBackend": "cni", "security": {"rootless": false}, "slirp4netns": {"executable": "/usr/bin/slirp4netns"}}}`,
+			PodmanInfo{NetworkBackend: "cni"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pi podmanInfo
+			if err := json.Unmarshal([]byte(tt.info), &pi); err != nil {
+				t.Fatal(err)
+			}
+			if got := podmanNetwork(pi.Host.podmanHostNetwork); *got != tt.want {
+				t.Errorf("podmanNetwork = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type envKey struct{}
+
+// WithEnv returns a context under which commands started by ExecCommandRunner get env,
+// a list of KEY=VALUE entries, on top of the process environment. Entries are added to
+// those already in ctx; later ones win. Use it to point a single invocation at a
+// provider or socket without changing the environment of the whole process.
+func WithEnv(ctx context.Context, env ...string) context.Context {
+	if len(env) == 0 {
+		return ctx
+	}
+	merged := append(append([]string(nil), Env(ctx)...), env...)
+	return context.WithValue(ctx, envKey{}, merged)
+}
+
+// Env returns the extra environment set on ctx with WithEnv.
+func Env(ctx context.Context) []string {
+	env, _ := ctx.Value(envKey{}).([]string)
+	return env
+}
+
+// applyEnv sets the environment of cmd from ctx, if WithEnv added any.
+func applyEnv(ctx context.Context, cmd *exec.Cmd) {
+	if env := Env(ctx); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+}
+
+// EnvRunner wraps a CommandRunner so that every command it runs gets Env (KEY=VALUE
+// entries) on top of the process environment, e.g. DOCKER_HOST for a specific engine
+// socket or HTTPS_PROXY. Environment added per command with WithEnv takes precedence.
+type EnvRunner struct {
+	Next CommandRunner
+	Env  []string
+}
+
+var (
+	_ StreamingRunner = (*EnvRunner)(nil)
+	_ InputRunner     = (*EnvRunner)(nil)
+)
+
+// Run implements CommandRunner.
+func (r *EnvRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return r.Next.Run(r.context(ctx), name, args...)
+}
+
+// RunSeparate implements CommandRunner.
+func (r *EnvRunner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	return r.Next.RunSeparate(r.context(ctx), name, args...)
+}
+
+// RunStream implements StreamingRunner, streaming through Next when it can.
+func (r *EnvRunner) RunStream(ctx context.Context, opts StreamOptions, name string, args ...string) (*StreamResult, error) {
+	return RunStream(r.context(ctx), r.Next, opts, name, args...)
+}
+
+// RunInput implements InputRunner through Next.
+func (r *EnvRunner) RunInput(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	return RunInput(r.context(ctx), r.Next, input, name, args...)
+}
+
+// LookPath implements CommandRunner.
+func (r *EnvRunner) LookPath(name string) (string, error) {
+	return r.Next.LookPath(name)
+}
+
+// context puts Env in ctx before any environment already there, so that the latter wins.
+func (r *EnvRunner) context(ctx context.Context) context.Context {
+	if len(r.Env) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, envKey{}, append(append([]string(nil), r.Env...), Env(ctx)...))
+}
+
+// EnvNames returns the variable names of KEY=VALUE entries, for logging without values,
+// which can carry credentials (proxy URLs).
+func EnvNames(env []string) []string {
+	names := make([]string, 0, len(env))
+	for _, e := range env {
+		name, _, _ := strings.Cut(e, "=")
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithEnv(t *testing.T) {
+	ctx := WithEnv(context.Background(), "MCP_KIND_TEST_A=1", "MCP_KIND_TEST_B=1")
+	ctx = WithEnv(ctx, "MCP_KIND_TEST_B=2")
+
+	out, err := (&ExecCommandRunner{}).Run(ctx, "env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env := string(out)
+	if !strings.Contains(env, "MCP_KIND_TEST_A=1\n") || !strings.Contains(env, "MCP_KIND_TEST_B=2\n") {
+		t.Errorf("environment missing added variables:\n%s", env)
+	}
+	if !strings.Contains(env, "PATH=") {
+		t.Error("process environment should be kept")
+	}
+	if got := Env(context.Background()); got != nil {
+		t.Errorf("Env of a plain context = %v", got)
+	}
+}
+
+func TestEnvRunner(t *testing.T) {
+	runner := &EnvRunner{Next: &ExecCommandRunner{}, Env: []string{"MCP_KIND_TEST_A=runner", "MCP_KIND_TEST_B=runner"}}
+	ctx := WithEnv(context.Background(), "MCP_KIND_TEST_B=call")
+
+	stdout, _, err := runner.RunSeparate(ctx, "env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env := string(stdout)
+	if !strings.Contains(env, "MCP_KIND_TEST_A=runner\n") || !strings.Contains(env, "MCP_KIND_TEST_B=call\n") {
+		t.Errorf("per-call environment should override the runner's:\n%s", env)
+	}
+
+	res, err := RunStream(context.Background(), runner, StreamOptions{}, "env")
+	if err != nil || !strings.Contains(string(res.Stdout), "MCP_KIND_TEST_B=runner\n") {
+		t.Errorf("streamed command missing runner environment: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+)
+
+// ErrInputUnsupported is returned by RunInput for runners that cannot pass standard input.
+var ErrInputUnsupported = errors.New("command runner does not support standard input")
+
+// InputRunner is a CommandRunner that can pass standard input to a command, as
+// Docker credential helpers require.
+type InputRunner interface {
+	CommandRunner
+	RunInput(ctx context.Context, input []byte, name string, args ...string) (stdout, stderr []byte, err error)
+}
+
+// RunInput runs a command with runner, writing input to its standard input. It fails
+// with ErrInputUnsupported if runner does not implement InputRunner.
+func RunInput(ctx context.Context, runner CommandRunner, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	if ir, ok := runner.(InputRunner); ok {
+		return ir.RunInput(ctx, input, name, args...)
+	}
+	return nil, nil, ErrInputUnsupported
+}
+
+// RunInput executes a command with input on its standard input and returns stdout and
+// stderr separately.
+func (r *ExecCommandRunner) RunInput(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	cmd := newCommand(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
@@ -0,0 +1,20 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestExecRunInput(t *testing.T) {
+	stdout, _, err := (&ExecCommandRunner{}).RunInput(context.Background(), []byte("registry.corp\n"), "cat")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(stdout) != "registry.corp\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+	if _, _, err := RunInput(context.Background(), &mockRunner{}, nil, "cat"); !errors.Is(err, ErrInputUnsupported) {
+		t.Errorf("expected ErrInputUnsupported, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ package runtime
 import (
 	"fmt"
 	"runtime"
+	"strings"
 )
 
 // OSInfo holds information about the host operating system.
@@ -38,3 +39,17 @@ func DetectOS() OSInfo {
 
 	return info
 }
+
+// NormalizeArch converts a kernel or engine architecture name (x86_64, aarch64, ...)
+// to its GOARCH form, as used in image platforms.
+func NormalizeArch(arch string) string {
+	switch strings.ToLower(arch) {
+	case "x86_64", "x86-64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "armv7l", "armv7", "armhf", "arm":
+		return "arm"
+	}
+	return strings.ToLower(arch)
+}
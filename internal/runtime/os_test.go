@@ -37,3 +37,12 @@ func TestDetectOS(t *testing.T) {
 		t.Error("PlatformNote should not be empty")
 	}
 }
+
+func TestNormalizeArch(t *testing.T) {
+	tests := map[string]string{"x86_64": "amd64", "aarch64": "arm64", "arm64": "arm64", "armv7l": "arm", "s390x": "s390x"}
+	for in, want := range tests {
+		if got := NormalizeArch(in); got != want {
+			t.Errorf("NormalizeArch(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
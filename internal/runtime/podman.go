@@ -0,0 +1,70 @@
+package runtime
+
+// Podman network backends reported by 'podman info'.
+const (
+	PodmanNetworkNetavark = "netavark"
+	PodmanNetworkCNI      = "cni"
+)
+
+// Rootless Podman port forwarders.
+const (
+	PodmanRootlessPasta       = "pasta"
+	PodmanRootlessSlirp4netns = "slirp4netns"
+)
+
+// PodmanInfo holds the Podman network settings that affect how Kind nodes reach each
+// other and how extraPortMappings reach the host.
+type PodmanInfo struct {
+	// NetworkBackend is "netavark" (the default since Podman 4) or "cni".
+	NetworkBackend string `json:"network_backend,omitempty"`
+	// DNS is the network DNS server, e.g. "aardvark-dns"; empty when none is installed
+	// and containers cannot resolve each other by name.
+	DNS      string `json:"dns,omitempty"`
+	Rootless bool   `json:"rootless"`
+	// RootlessNetwork is the rootless network and port forwarder: "pasta" (the default
+	// since Podman 5) or "slirp4netns". Empty for rootful Podman.
+	RootlessNetwork string `json:"rootless_network,omitempty"`
+}
+
+// podmanHostNetwork is the part of podman info's host section PodmanInfo comes from.
+type podmanHostNetwork struct {
+	NetworkBackend     string `json:"networkBackend"`
+	NetworkBackendInfo struct {
+		DNS struct {
+			Package string `json:"package"`
+			Path    string `json:"path"`
+		} `json:"dns"`
+	} `json:"networkBackendInfo"`
+	Security struct {
+		Rootless bool `json:"rootless"`
+	} `json:"security"`
+	// RootlessNetworkCmd is reported by Podman 5 and later.
+	RootlessNetworkCmd string `json:"rootlessNetworkCmd"`
+	Pasta              struct {
+		Executable string `json:"executable"`
+	} `json:"pasta"`
+	Slirp4netns struct {
+		Executable string `json:"executable"`
+	} `json:"slirp4netns"`
+}
+
+// podmanNetwork returns the network settings of a podman info host section.
+func podmanNetwork(h podmanHostNetwork) *PodmanInfo {
+	p := &PodmanInfo{NetworkBackend: h.NetworkBackend, Rootless: h.Security.Rootless}
+	if h.NetworkBackendInfo.DNS.Path != "" || h.NetworkBackendInfo.DNS.Package != "" {
+		p.DNS = "aardvark-dns"
+	}
+	if !p.Rootless {
+		return p
+	}
+	switch {
+	case h.RootlessNetworkCmd != "":
+		p.RootlessNetwork = h.RootlessNetworkCmd
+	case h.Slirp4netns.Executable != "":
+		// Before Podman 5, slirp4netns was the default when installed.
+		p.RootlessNetwork = PodmanRootlessSlirp4netns
+	case h.Pasta.Executable != "":
+		p.RootlessNetwork = PodmanRootlessPasta
+	}
+	return p
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package runtime
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+// newCommand returns a command that runs in its own process group, so that cancelling
+// ctx kills the whole group: kind and the runtime CLIs start helper processes that
+// would otherwise keep running after the command itself is killed. Environment added
+// to ctx with WithEnv is applied.
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	applyEnv(ctx, cmd)
+	return cmd
+}
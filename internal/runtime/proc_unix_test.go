@@ -0,0 +1,24 @@
+//go:build !windows
+
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExecRun_CancelKillsProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// The background sleep inherits the output pipe; Run only returns once it is gone.
+	start := time.Now()
+	_, err := (&ExecCommandRunner{}).Run(ctx, "sh", "-c", "sleep 30 & wait")
+	if err == nil {
+		t.Fatal("expected an error from the cancelled command")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run returned after %s; the child process was not killed", elapsed)
+	}
+}
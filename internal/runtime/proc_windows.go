@@ -0,0 +1,17 @@
+//go:build windows
+
+package runtime
+
+import (
+	"context"
+	"os/exec"
+)
+
+// newCommand returns a command that is killed when ctx is cancelled. Windows has no
+// process groups to kill; child processes of the command may outlive it. Environment
+// added to ctx with WithEnv is applied.
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	applyEnv(ctx, cmd)
+	return cmd
+}
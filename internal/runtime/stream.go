@@ -0,0 +1,238 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxOutputBytes is how much of each output stream RunStream keeps by default.
+const DefaultMaxOutputBytes = 1 << 20
+
+// MaxLineBytes is the longest line passed to OnLine; the rest of a longer line is
+// dropped.
+const MaxLineBytes = 64 << 10
+
+// ErrIdleTimeout is returned by RunStream when a command stops producing output for
+// longer than its idle timeout and is killed.
+var ErrIdleTimeout = errors.New("command produced no output within the idle timeout")
+
+// OutputLine is one line of a command's output, without its trailing newline.
+type OutputLine struct {
+	Text   string
+	Stderr bool
+}
+
+// StreamOptions controls RunStream.
+type StreamOptions struct {
+	// OnLine is called with each line of output as soon as it is complete. Calls are
+	// never concurrent, and the lines of each stream arrive in order.
+	OnLine func(OutputLine)
+	// IdleTimeout kills the command when it produces no output for this long. Zero
+	// disables it; the context still bounds the whole run.
+	IdleTimeout time.Duration
+	// MaxOutputBytes caps how much of each stream the result keeps; older output is
+	// dropped first. Zero means DefaultMaxOutputBytes.
+	MaxOutputBytes int
+	// Stdin, if not nil, is written to the command's standard input.
+	Stdin []byte
+}
+
+// StreamResult holds the output kept by RunStream.
+type StreamResult struct {
+	Stdout []byte
+	Stderr []byte
+	// Truncated reports that output beyond MaxOutputBytes was dropped.
+	Truncated bool
+}
+
+// StreamingRunner is a CommandRunner that can report output while a command runs.
+type StreamingRunner interface {
+	CommandRunner
+	RunStream(ctx context.Context, opts StreamOptions, name string, args ...string) (*StreamResult, error)
+}
+
+// RunStream runs a command with runner, streaming its output to opts.OnLine. Runners
+// that do not implement StreamingRunner run it with RunSeparate (or RunInput when
+// opts.Stdin is set) instead, and its lines are delivered once it exits; the idle
+// timeout does not apply to them.
+func RunStream(ctx context.Context, runner CommandRunner, opts StreamOptions, name string, args ...string) (*StreamResult, error) {
+	if sr, ok := runner.(StreamingRunner); ok {
+		return sr.RunStream(ctx, opts, name, args...)
+	}
+
+	var stdout, stderr []byte
+	var err error
+	if opts.Stdin != nil {
+		stdout, stderr, err = RunInput(ctx, runner, opts.Stdin, name, args...)
+	} else {
+		stdout, stderr, err = runner.RunSeparate(ctx, name, args...)
+	}
+	out, errOut := newTailBuffer(opts.MaxOutputBytes), newTailBuffer(opts.MaxOutputBytes)
+	var mu sync.Mutex
+	w := &lineWriter{buf: out, mu: &mu, onLine: opts.OnLine}
+	w.Write(stdout)
+	w.flush()
+	w = &lineWriter{buf: errOut, mu: &mu, onLine: opts.OnLine, stderr: true}
+	w.Write(stderr)
+	w.flush()
+	return out.result(errOut), err
+}
+
+// RunStream runs a command, passing each line of output to opts.OnLine as it is
+// produced and killing the command after opts.IdleTimeout without output.
+func (r *ExecCommandRunner) RunStream(ctx context.Context, opts StreamOptions, name string, args ...string) (*StreamResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	stdout := &lineWriter{buf: newTailBuffer(opts.MaxOutputBytes), mu: &mu, onLine: opts.OnLine}
+	stderr := &lineWriter{buf: newTailBuffer(opts.MaxOutputBytes), mu: &mu, onLine: opts.OnLine, stderr: true}
+
+	var idle bool
+	if opts.IdleTimeout > 0 {
+		timer := time.AfterFunc(opts.IdleTimeout, func() {
+			mu.Lock()
+			idle = true
+			mu.Unlock()
+			cancel()
+		})
+		defer timer.Stop()
+		activity := func() { timer.Reset(opts.IdleTimeout) }
+		stdout.activity, stderr.activity = activity, activity
+	}
+
+	cmd := newCommand(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if opts.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(opts.Stdin)
+	}
+	// Processes that left the group and hold the pipes open must not block Wait after a kill.
+	cmd.WaitDelay = 2 * time.Second
+	err := cmd.Run()
+
+	mu.Lock()
+	stdout.flushLocked()
+	stderr.flushLocked()
+	timedOut := idle
+	mu.Unlock()
+
+	result := stdout.buf.result(stderr.buf)
+	if timedOut {
+		return result, fmt.Errorf("%w (%s): %v", ErrIdleTimeout, opts.IdleTimeout, err)
+	}
+	return result, err
+}
+
+// lineWriter splits written output into lines for OnLine and keeps its tail.
+type lineWriter struct {
+	buf      *tailBuffer
+	mu       *sync.Mutex // shared by the stdout and stderr writers of one command
+	onLine   func(OutputLine)
+	stderr   bool
+	activity func()
+	partial  []byte
+	// discarding is set once the current line has been emitted truncated, until its
+	// newline.
+	discarding bool
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if w.activity != nil && len(p) > 0 {
+		w.activity()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.write(p)
+	if w.onLine == nil {
+		return len(p), nil
+	}
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		chunk := p
+		if i >= 0 {
+			chunk = p[:i]
+		}
+		if !w.discarding {
+			if room := MaxLineBytes - len(w.partial); len(chunk) > room {
+				w.emit(append(w.partial, chunk[:room]...))
+				w.partial = w.partial[:0]
+				w.discarding = true
+			} else {
+				w.partial = append(w.partial, chunk...)
+			}
+		}
+		if i < 0 {
+			break
+		}
+		if !w.discarding {
+			w.emit(w.partial)
+		}
+		w.partial = w.partial[:0]
+		w.discarding = false
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLocked()
+}
+
+// flushLocked delivers a final line without a trailing newline.
+func (w *lineWriter) flushLocked() {
+	if len(w.partial) > 0 && w.onLine != nil {
+		w.emit(w.partial)
+	}
+	w.partial = nil
+	w.discarding = false
+}
+
+func (w *lineWriter) emit(line []byte) {
+	w.onLine(OutputLine{Text: strings.TrimSuffix(string(line), "\r"), Stderr: w.stderr})
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	data      []byte
+	max       int
+	truncated bool
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	if max <= 0 {
+		max = DefaultMaxOutputBytes
+	}
+	return &tailBuffer{max: max}
+}
+
+func (b *tailBuffer) write(p []byte) {
+	b.data = append(b.data, p...)
+	// Compact only once the buffer doubles, so long outputs are not copied on every write.
+	if len(b.data) > 2*b.max {
+		b.data = append([]byte(nil), b.data[len(b.data)-b.max:]...)
+		b.truncated = true
+	}
+}
+
+func (b *tailBuffer) bytes() []byte {
+	if len(b.data) > b.max {
+		b.truncated = true
+		return b.data[len(b.data)-b.max:]
+	}
+	return b.data
+}
+
+func (b *tailBuffer) result(stderr *tailBuffer) *StreamResult {
+	res := &StreamResult{Stdout: b.bytes(), Stderr: stderr.bytes()}
+	res.Truncated = b.truncated || stderr.truncated
+	return res
+}
@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExecRunStream_Lines(t *testing.T) {
+	var lines []OutputLine
+	res, err := (&ExecCommandRunner{}).RunStream(context.Background(), StreamOptions{
+		OnLine: func(l OutputLine) { lines = append(lines, l) },
+	}, "sh", "-c", "echo one; echo two >&2; printf three")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(res.Stdout) != "one\nthree" || string(res.Stderr) != "two\n" || res.Truncated {
+		t.Errorf("result = %q / %q", res.Stdout, res.Stderr)
+	}
+
+	var stdout []string
+	for _, l := range lines {
+		if l.Stderr {
+			if l.Text != "two" {
+				t.Errorf("stderr line = %q", l.Text)
+			}
+			continue
+		}
+		stdout = append(stdout, l.Text)
+	}
+	if strings.Join(stdout, ",") != "one,three" {
+		t.Errorf("stdout lines = %v", stdout)
+	}
+}
+
+func TestExecRunStream_IdleTimeout(t *testing.T) {
+	start := time.Now()
+	res, err := (&ExecCommandRunner{}).RunStream(context.Background(), StreamOptions{
+		IdleTimeout: 200 * time.Millisecond,
+	}, "sh", "-c", "echo started; sleep 10")
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Fatalf("expected idle timeout, got %v", err)
+	}
+	if time.Since(start) > 8*time.Second {
+		t.Errorf("command was not killed promptly: %s", time.Since(start))
+	}
+	if string(res.Stdout) != "started\n" {
+		t.Errorf("stdout = %q", res.Stdout)
+	}
+}
+
+func TestExecRunStream_Cap(t *testing.T) {
+	var count int
+	res, err := (&ExecCommandRunner{}).RunStream(context.Background(), StreamOptions{
+		MaxOutputBytes: 100,
+		OnLine:         func(OutputLine) { count++ },
+	}, "sh", "-c", "i=0; while [ $i -lt 500 ]; do echo line$i; i=$((i+1)); done")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Truncated || len(res.Stdout) != 100 || !strings.HasSuffix(string(res.Stdout), "line499\n") {
+		t.Errorf("truncated = %v, stdout (%d bytes) = %q", res.Truncated, len(res.Stdout), res.Stdout)
+	}
+	if count != 500 {
+		t.Errorf("OnLine called %d times, want every line", count)
+	}
+}
+
+func TestLineWriter_LongLine(t *testing.T) {
+	var lines []string
+	w := &lineWriter{buf: newTailBuffer(0), mu: &sync.Mutex{}, onLine: func(l OutputLine) { lines = append(lines, l.Text) }}
+	long := strings.Repeat("x", MaxLineBytes)
+	for _, chunk := range []string{"short\n", long, long, "\nnext\n", long + "\n", "tail"} {
+		w.Write([]byte(chunk))
+	}
+	w.flush()
+	if len(lines) != 5 || lines[0] != "short" || lines[1] != long || lines[2] != "next" || lines[3] != long ||
+		lines[4] != "tail" {
+		t.Fatalf("got %d lines", len(lines))
+	}
+}
+
+func TestRunStream_Fallback(t *testing.T) {
+	runner := &mockRunner{runResults: map[string]runResult{
+		"kind create": {output: []byte("Creating cluster\nReady\n")},
+	}}
+	var lines []string
+	res, err := RunStream(context.Background(), runner, StreamOptions{
+		OnLine: func(l OutputLine) { lines = append(lines, l.Text) },
+	}, "kind", "create", "cluster")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(lines, "|") != "Creating cluster|Ready" || string(res.Stdout) != "Creating cluster\nReady\n" {
+		t.Errorf("lines = %v, stdout = %q", lines, res.Stdout)
+	}
+}
+
+func TestExecRunStream_Stdin(t *testing.T) {
+	res, err := (&ExecCommandRunner{}).RunStream(context.Background(), StreamOptions{
+		Stdin: []byte("kind: Cluster\n"),
+	}, "cat")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(res.Stdout) != "kind: Cluster\n" {
+		t.Errorf("stdout = %q", res.Stdout)
+	}
+}
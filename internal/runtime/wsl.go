@@ -0,0 +1,132 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// wslConfPath is the per-distribution WSL config; overridden in tests.
+var wslConfPath = "/etc/wsl.conf"
+
+// WSL2 networking modes set by networkingMode in .wslconfig.
+const (
+	WSLNetworkingNAT      = "nat"
+	WSLNetworkingMirrored = "mirrored"
+)
+
+// WSLInfo holds the WSL settings that affect how ports reach Windows and the LAN.
+type WSLInfo struct {
+	// NetworkingMode is "nat" (the default) or "mirrored".
+	NetworkingMode string `json:"networking_mode"`
+	// LocalhostForwarding reports whether ports bound in WSL are reachable on Windows
+	// localhost in NAT mode (default true).
+	LocalhostForwarding bool `json:"localhost_forwarding"`
+	// Interop reports whether Windows executables can be launched from the distribution.
+	Interop bool `json:"interop"`
+	// WSLConfig is the path of the .wslconfig that was read, if it was found.
+	WSLConfig string `json:"wslconfig,omitempty"`
+}
+
+// Mirrored reports whether WSL shares the Windows network interfaces.
+func (w *WSLInfo) Mirrored() bool {
+	return w != nil && w.NetworkingMode == WSLNetworkingMirrored
+}
+
+// probeWSL reads /etc/wsl.conf and, through interop, the Windows user's .wslconfig.
+// Missing files leave the WSL defaults in place.
+func (d *Detector) probeWSL(ctx context.Context) *WSLInfo {
+	info := &WSLInfo{
+		NetworkingMode:      WSLNetworkingNAT,
+		LocalhostForwarding: true,
+		Interop:             true,
+	}
+
+	if conf, err := readINI(wslConfPath); err == nil {
+		if v, ok := conf["interop.enabled"]; ok {
+			info.Interop = parseBool(v, true)
+		}
+	}
+	if !info.Interop {
+		return info
+	}
+
+	path := d.windowsWSLConfig(ctx)
+	if path == "" {
+		return info
+	}
+	conf, err := readINI(path)
+	if err != nil {
+		return info
+	}
+	info.WSLConfig = path
+	if v := strings.ToLower(conf["wsl2.networkingmode"]); v != "" {
+		info.NetworkingMode = v
+	}
+	if v, ok := conf["wsl2.localhostforwarding"]; ok {
+		info.LocalhostForwarding = parseBool(v, true)
+	}
+	return info
+}
+
+// windowsWSLConfig returns the Linux path of %USERPROFILE%\.wslconfig, or "" if it
+// cannot be resolved.
+func (d *Detector) windowsWSLConfig(ctx context.Context) string {
+	out, err := d.runner.Run(ctx, "cmd.exe", "/c", "echo %USERPROFILE%")
+	if err != nil {
+		return ""
+	}
+	profile := strings.TrimSpace(string(out))
+	if profile == "" || strings.Contains(profile, "%") {
+		return ""
+	}
+	out, err = d.runner.Run(ctx, "wslpath", "-u", profile)
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(strings.TrimSpace(string(out)), ".wslconfig")
+}
+
+// readINI parses a wsl.conf/.wslconfig style file into lower-cased "section.key" entries.
+func readINI(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if i := strings.IndexAny(value, "#;"); i >= 0 {
+			value = value[:i]
+		}
+		values[section+"."+strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return values, scanner.Err()
+}
+
+func parseBool(v string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "true", "1", "yes":
+		return true
+	case "false", "0", "no":
+		return false
+	}
+	return def
+}
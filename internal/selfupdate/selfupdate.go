@@ -0,0 +1,201 @@
+// Package selfupdate compares the running server with its latest GitHub release and
+// replaces a manually installed binary with the release built for this platform.
+package selfupdate
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults of Client.
+const (
+	DefaultAPIURL = "https://api.github.com"
+	DefaultRepo   = "kubevoidcraft/mcp-kind-manager"
+)
+
+// BinaryName is the name of the server binary, and the prefix of its release assets.
+const BinaryName = "mcp-kind-manager"
+
+// ChecksumsAsset is the release asset listing the SHA-256 of every other asset in
+// sha256sum format.
+const ChecksumsAsset = "checksums.txt"
+
+// ErrUpToDate is returned by Update when the running version is the latest release.
+var ErrUpToDate = errors.New("already running the latest release")
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Release is a published GitHub release.
+type Release struct {
+	Tag         string    `json:"tag_name"`
+	URL         string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Client talks to the GitHub releases API.
+type Client struct {
+	// APIURL is the GitHub API base URL. Default: DefaultAPIURL.
+	APIURL string
+	// Repo is the owner/name of the repository. Default: DefaultRepo.
+	Repo string
+	// Token authenticates API requests, raising GitHub's rate limit. Optional.
+	Token string
+	// HTTP sends the requests. Default: http.DefaultClient.
+	HTTP *http.Client
+	// GOOS and GOARCH select the release asset. Default: the running platform.
+	GOOS, GOARCH string
+}
+
+// CheckResult compares the running version with the latest release.
+type CheckResult struct {
+	Current         string    `json:"current"`
+	Latest          string    `json:"latest"`
+	UpdateAvailable bool      `json:"update_available"`
+	ReleaseURL      string    `json:"release_url"`
+	PublishedAt     time.Time `json:"published_at"`
+	// Asset is the release asset for this platform, if the release has one.
+	Asset string   `json:"asset,omitempty"`
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Latest returns the latest published release; drafts and prereleases are skipped by
+// GitHub.
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(cmp.Or(c.APIURL, DefaultAPIURL), "/"),
+		cmp.Or(c.Repo, DefaultRepo))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("parsing the latest release: %w", err)
+	}
+	if rel.Tag == "" {
+		return nil, fmt.Errorf("the latest release has no tag")
+	}
+	return &rel, nil
+}
+
+// Check compares current, the version the server was built as, with the latest
+// release. A development build ("dev", a commit hash) cannot be compared and is never
+// reported as outdated.
+func (c *Client) Check(ctx context.Context, current string) (*CheckResult, error) {
+	rel, err := c.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := &CheckResult{Current: current, Latest: rel.Tag, ReleaseURL: rel.URL, PublishedAt: rel.PublishedAt}
+	cur, ok := parseVersion(current)
+	latest, latestOK := parseVersion(rel.Tag)
+	switch {
+	case !ok:
+		r.Notes = append(r.Notes, fmt.Sprintf("%q is a development build and cannot be compared with releases", current))
+	case !latestOK:
+		r.Notes = append(r.Notes, fmt.Sprintf("unrecognized release tag %q", rel.Tag))
+	default:
+		r.UpdateAvailable = cur.less(latest)
+	}
+	if asset, ok := c.platformAsset(rel); ok {
+		r.Asset = asset.Name
+	} else {
+		r.Notes = append(r.Notes, fmt.Sprintf("the release has no binary for %s/%s; build it from source", c.goos(), c.goarch()))
+	}
+	return r, nil
+}
+
+// platformAsset returns the release's binary for the target platform: a raw binary or a
+// .tar.gz holding it, named like mcp-kind-manager_linux_amd64(.tar.gz).
+func (c *Client) platformAsset(rel *Release) (Asset, bool) {
+	for _, a := range rel.Assets {
+		name := strings.ToLower(a.Name)
+		if !strings.HasPrefix(name, BinaryName) {
+			continue
+		}
+		for _, sep := range []string{"_", "-"} {
+			base := BinaryName + sep + c.goos() + sep + c.goarch()
+			switch strings.TrimPrefix(name, base) {
+			case "", ".exe", ".tar.gz", ".tgz":
+				return a, true
+			}
+		}
+	}
+	return Asset{}, false
+}
+
+// do sends req with the client's headers and fails on a non-2xx status.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", BinaryName)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s %s", req.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (c *Client) goos() string   { return cmp.Or(c.GOOS, goruntime.GOOS) }
+func (c *Client) goarch() string { return cmp.Or(c.GOARCH, goruntime.GOARCH) }
+
+// version is a parsed vMAJOR.MINOR.PATCH version.
+type version [3]int
+
+// parseVersion parses a release tag or a "git describe" version such as
+// "v1.2.0-3-gabc1234-dirty"; the suffix is ignored.
+func parseVersion(s string) (version, bool) {
+	var v version
+	s, _, _ = strings.Cut(strings.TrimPrefix(strings.TrimSpace(s), "v"), "-")
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+func (v version) less(o version) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
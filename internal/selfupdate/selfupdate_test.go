@@ -0,0 +1,115 @@
+package selfupdate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGitHub serves a latest release with a linux/amd64 binary and its checksums.
+func fakeGitHub(t *testing.T, tag string, binary []byte, checksum string) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/repos/kubevoidcraft/mcp-kind-manager/releases/latest", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"tag_name":%q,"html_url":"https://example.test/%s","assets":[
+			{"name":"mcp-kind-manager_darwin_arm64","browser_download_url":"%[3]s/darwin"},
+			{"name":"mcp-kind-manager_linux_amd64","browser_download_url":"%[3]s/linux"},
+			{"name":"checksums.txt","browser_download_url":"%[3]s/checksums"}]}`, tag, tag, srv.URL)
+	})
+	mux.HandleFunc("/linux", func(w http.ResponseWriter, _ *http.Request) { w.Write(binary) })
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, "%s  mcp-kind-manager_linux_amd64\n%s  mcp-kind-manager_darwin_arm64\n", checksum, strings.Repeat("0", 64))
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &Client{APIURL: srv.URL, HTTP: srv.Client(), GOOS: "linux", GOARCH: "amd64"}
+}
+
+func sha(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCheck(t *testing.T) {
+	c := fakeGitHub(t, "v1.3.0", nil, "")
+	tests := []struct {
+		current string
+		want    bool
+	}{
+		{"v1.2.9", true},
+		{"v1.3.0", false},
+		{"v1.3.0-2-gabc1234-dirty", false},
+		{"v1.10.0", false},
+		{"dev", false},
+	}
+	for _, tt := range tests {
+		r, err := c.Check(context.Background(), tt.current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.UpdateAvailable != tt.want || r.Latest != "v1.3.0" || r.Asset != "mcp-kind-manager_linux_amd64" {
+			t.Errorf("Check(%s) = %+v", tt.current, r)
+		}
+	}
+
+	c.GOOS = "windows"
+	r, _ := c.Check(context.Background(), "v1.0.0")
+	if r.Asset != "" || len(r.Notes) != 1 || !strings.Contains(r.Notes[0], "windows/amd64") {
+		t.Errorf("check = %+v", r)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho new\n")
+	exe := filepath.Join(t.TempDir(), BinaryName)
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fakeGitHub(t, "v1.3.0", binary, sha(binary))
+	if _, err := c.Update(context.Background(), "v1.3.0", UpdateOptions{Executable: exe}); !errors.Is(err, ErrUpToDate) {
+		t.Errorf("err = %v, want ErrUpToDate", err)
+	}
+	if _, err := c.Update(context.Background(), "dev", UpdateOptions{Executable: exe}); err == nil {
+		t.Error("expected a development build to need force")
+	}
+
+	r, err := c.Update(context.Background(), "v1.2.0", UpdateOptions{Executable: exe})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != string(binary) {
+		t.Errorf("binary = %q", got)
+	}
+	if old, _ := os.ReadFile(r.Backup); string(old) != "old" || r.Version != "v1.3.0" || r.SHA256 != sha(binary) {
+		t.Errorf("result = %+v, backup = %q", r, old)
+	}
+}
+
+func TestUpdate_ChecksumMismatch(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), BinaryName)
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	c := fakeGitHub(t, "v1.3.0", []byte("tampered"), sha([]byte("genuine")))
+	if _, err := c.Update(context.Background(), "v1.2.0", UpdateOptions{Executable: exe}); err == nil ||
+		!strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("err = %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old" {
+		t.Errorf("binary replaced despite the mismatch: %q", got)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("leftover files: %v", entries)
+	}
+}
@@ -0,0 +1,222 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxBinarySize bounds the download and the extracted binary.
+const maxBinarySize = 256 << 20
+
+// managedPaths hold binaries installed by a package manager, which Update leaves to it.
+var managedPaths = []string{"/nix/store/", "/Cellar/", "/snap/", "/usr/bin/"}
+
+// UpdateOptions controls Update.
+type UpdateOptions struct {
+	// Executable is the binary to replace. Default: the running executable.
+	Executable string
+	// Force installs the latest release even when the running version is not older,
+	// including over development builds.
+	Force bool
+}
+
+// UpdateResult reports a replaced binary.
+type UpdateResult struct {
+	Previous string `json:"previous"`
+	Version  string `json:"version"`
+	Path     string `json:"path"`
+	// Backup is the previous binary, kept to roll back by renaming it over Path.
+	Backup string `json:"backup"`
+	Asset  string `json:"asset"`
+	SHA256 string `json:"sha256"`
+}
+
+// Update downloads the latest release's binary for this platform, verifies it against
+// the release's ChecksumsAsset and swaps it in place of the executable, keeping the
+// previous binary next to it with a ".old" suffix. The running process is not
+// replaced: the new version serves once the server is restarted. Without Force it
+// returns ErrUpToDate unless the release is newer than current.
+func (c *Client) Update(ctx context.Context, current string, opts UpdateOptions) (*UpdateResult, error) {
+	exe, err := executablePath(opts.Executable)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range managedPaths {
+		if strings.Contains(filepath.ToSlash(exe), p) {
+			return nil, fmt.Errorf("%s is managed by a package manager; update it with that instead", exe)
+		}
+	}
+
+	rel, err := c.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Force {
+		cur, ok := parseVersion(current)
+		if !ok {
+			return nil, fmt.Errorf("%q is a development build; pass force to replace it with release %s", current, rel.Tag)
+		}
+		if latest, ok := parseVersion(rel.Tag); !ok || !cur.less(latest) {
+			return nil, fmt.Errorf("%s (latest: %s): %w", current, rel.Tag, ErrUpToDate)
+		}
+	}
+	asset, ok := c.platformAsset(rel)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no binary for %s/%s", rel.Tag, c.goos(), c.goarch())
+	}
+	sums, err := c.checksums(ctx, rel)
+	if err != nil {
+		return nil, err
+	}
+	want, ok := sums[asset.Name]
+	if !ok {
+		return nil, fmt.Errorf("%s of release %s does not list %s", ChecksumsAsset, rel.Tag, asset.Name)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), "."+BinaryName+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating the new binary next to %s: %w", exe, err)
+	}
+	defer os.Remove(tmp.Name())
+	got, err := c.download(ctx, asset, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %s, %s lists %s", asset.Name, got, ChecksumsAsset, want)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return nil, err
+	}
+
+	backup := exe + ".old"
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("removing the previous backup: %w", err)
+	}
+	if err := os.Rename(exe, backup); err != nil {
+		return nil, fmt.Errorf("moving %s aside: %w", exe, err)
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		if restoreErr := os.Rename(backup, exe); restoreErr != nil {
+			return nil, fmt.Errorf("installing %s: %w (restoring the previous binary from %s failed: %v)",
+				exe, err, backup, restoreErr)
+		}
+		return nil, fmt.Errorf("installing %s: %w", exe, err)
+	}
+	return &UpdateResult{Previous: current, Version: rel.Tag, Path: exe, Backup: backup, Asset: asset.Name, SHA256: got}, nil
+}
+
+// executablePath resolves the binary to replace, following symlinks so the link keeps
+// pointing at the new binary.
+func executablePath(path string) (string, error) {
+	if path == "" {
+		var err error
+		if path, err = os.Executable(); err != nil {
+			return "", fmt.Errorf("locating the running executable: %w", err)
+		}
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", path, err)
+	}
+	return resolved, nil
+}
+
+// checksums fetches the release's ChecksumsAsset, keyed by asset name.
+func (c *Client) checksums(ctx context.Context, rel *Release) (map[string]string, error) {
+	var asset *Asset
+	for i := range rel.Assets {
+		if rel.Assets[i].Name == ChecksumsAsset {
+			asset = &rel.Assets[i]
+		}
+	}
+	if asset == nil {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", rel.Tag, ChecksumsAsset)
+	}
+	resp, err := c.get(ctx, asset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", ChecksumsAsset, err)
+	}
+	defer resp.Body.Close()
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 1<<20))
+	for scanner.Scan() {
+		// "<sha256>  <name>", or "<sha256> *<name>" in binary mode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+	return sums, scanner.Err()
+}
+
+// download writes the binary in asset to dst and returns the SHA-256 of the asset as
+// downloaded.
+func (c *Client) download(ctx context.Context, asset Asset, dst io.Writer) (string, error) {
+	resp, err := c.get(ctx, asset.URL)
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+	h := sha256.New()
+	body := io.TeeReader(io.LimitReader(resp.Body, maxBinarySize), h)
+
+	name := strings.ToLower(asset.Name)
+	if strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") {
+		err = extractBinary(body, dst)
+		// Hash what the archive reader left unread, e.g. tar padding.
+		if err == nil {
+			_, err = io.Copy(io.Discard, body)
+		}
+	} else {
+		_, err = io.Copy(dst, body)
+	}
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", asset.Name, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractBinary copies the server binary out of a .tar.gz archive.
+func extractBinary(r io.Reader, dst io.Writer) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("the archive has no %s binary", BinaryName)
+		}
+		if err != nil {
+			return err
+		}
+		base := filepath.Base(hdr.Name)
+		if hdr.Typeflag == tar.TypeReg && (base == BinaryName || base == BinaryName+".exe") {
+			_, err := io.Copy(dst, io.LimitReader(tr, maxBinarySize))
+			return err
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
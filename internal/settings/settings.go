@@ -0,0 +1,146 @@
+// Package settings loads the server's optional settings file, which sets defaults that
+// would otherwise each need an environment variable. Environment variables still take
+// precedence over the file.
+package settings
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Settings is the content of the settings file. Every field is optional.
+type Settings struct {
+	// Runtime is the container runtime tried first: "docker" or "podman".
+	Runtime string `yaml:"runtime"`
+	// Backend is "cli" or "library" (MCP_KIND_BACKEND).
+	Backend string `yaml:"backend"`
+	// NodeImageRepository replaces kindest/node (MCP_KIND_NODE_IMAGE_REPOSITORY).
+	NodeImageRepository string `yaml:"node_image_repository"`
+	// KubernetesVersion is used by generate_cluster_config when a call names none.
+	KubernetesVersion string `yaml:"kubernetes_version"`
+	// Tools limits the registered tools to these names; empty registers every tool.
+	Tools []string `yaml:"tools"`
+	// KubectlAllowedVerbs are the verbs the kubectl tool permits (KUBECTL_ALLOWED_VERBS).
+	KubectlAllowedVerbs []string `yaml:"kubectl_allowed_verbs"`
+	// StateDir holds the cluster metadata store (MCP_KIND_STATE_DIR).
+	StateDir string `yaml:"state_dir"`
+	// ConfigDir keeps each cluster's create config (MCP_KIND_CONFIG_DIR).
+	ConfigDir string `yaml:"config_dir"`
+	// StorageDir holds the host storage directory of each cluster (MCP_KIND_STORAGE_DIR).
+	StorageDir string `yaml:"storage_dir"`
+	// MaxHeavyOps bounds concurrent heavy operations (MCP_KIND_MAX_HEAVY_OPS); nil keeps
+	// the default and 0 turns the limit off.
+	MaxHeavyOps *int     `yaml:"max_heavy_ops"`
+	Timeouts    Timeouts `yaml:"timeouts"`
+	TTL         TTL      `yaml:"ttl"`
+	Quotas      Quotas   `yaml:"quotas"`
+}
+
+// Timeouts are durations written as Go duration strings, e.g. "30s" or "5m".
+type Timeouts struct {
+	// ShutdownGrace is how long cancelled calls get to clean up on SIGINT/SIGTERM
+	// (MCP_KIND_SHUTDOWN_GRACE).
+	ShutdownGrace time.Duration `yaml:"shutdown_grace"`
+	// Ready is how long start_cluster and restore_node wait for nodes to become ready
+	// when a call sets no timeout_seconds.
+	Ready time.Duration `yaml:"ready"`
+}
+
+// TTL holds default lifetimes of credentials the server issues.
+type TTL struct {
+	// ScopedToken is the token lifetime of create_scoped_kubeconfig when a call sets
+	// no duration_hours.
+	ScopedToken time.Duration `yaml:"scoped_token"`
+}
+
+// Quotas bound cluster creation; 0 means no limit. Each has an environment variable
+// overriding it.
+type Quotas struct {
+	// MaxClusters bounds the clusters that may exist (MCP_KIND_MAX_CLUSTERS).
+	MaxClusters int `yaml:"max_clusters"`
+	// MaxClustersPerOwner bounds the clusters one HTTP client may own
+	// (MCP_KIND_MAX_CLUSTERS_PER_OWNER).
+	MaxClustersPerOwner int `yaml:"max_clusters_per_owner"`
+	// MaxNodesPerCluster bounds the nodes of a new cluster (MCP_KIND_MAX_NODES_PER_CLUSTER).
+	MaxNodesPerCluster int `yaml:"max_nodes_per_cluster"`
+	// MaxCreatesPerHour bounds cluster creations per hour (MCP_KIND_MAX_CREATES_PER_HOUR).
+	MaxCreatesPerHour int `yaml:"max_creates_per_hour"`
+}
+
+// DefaultPath returns the settings file path: $MCP_KIND_SETTINGS_FILE if set, otherwise
+// <user config dir>/mcp-kind-manager/config.yaml (~/.config/... on Linux).
+func DefaultPath() string {
+	if path := os.Getenv("MCP_KIND_SETTINGS_FILE"); path != "" {
+		return path
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "mcp-kind-manager", "config.yaml")
+}
+
+// Load reads and validates the settings file at path. A missing file yields empty
+// Settings; unknown keys are errors so that typos do not go unnoticed.
+func Load(path string) (*Settings, error) {
+	s := &Settings{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading settings file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing settings file %s: %w", path, err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("settings file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *Settings) validate() error {
+	switch s.Runtime {
+	case "", "docker", "podman":
+	default:
+		return fmt.Errorf("runtime must be 'docker' or 'podman', got %q", s.Runtime)
+	}
+	switch s.Backend {
+	case "", "cli", "library":
+	default:
+		return fmt.Errorf("backend must be 'cli' or 'library', got %q", s.Backend)
+	}
+	if s.MaxHeavyOps != nil && *s.MaxHeavyOps < 0 {
+		return fmt.Errorf("max_heavy_ops must not be negative")
+	}
+	for name, n := range map[string]int{
+		"quotas.max_clusters":           s.Quotas.MaxClusters,
+		"quotas.max_clusters_per_owner": s.Quotas.MaxClustersPerOwner,
+		"quotas.max_nodes_per_cluster":  s.Quotas.MaxNodesPerCluster,
+		"quotas.max_creates_per_hour":   s.Quotas.MaxCreatesPerHour,
+	} {
+		if n < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	for name, d := range map[string]time.Duration{
+		"timeouts.shutdown_grace": s.Timeouts.ShutdownGrace,
+		"timeouts.ready":          s.Timeouts.Ready,
+		"ttl.scoped_token":        s.TTL.ScopedToken,
+	} {
+		if d < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	return nil
+}
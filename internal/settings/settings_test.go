@@ -0,0 +1,92 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeSettings(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeSettings(t, `runtime: podman
+node_image_repository: registry.corp/kind/node
+kubernetes_version: 1.31.0
+tools: [list_clusters, get_cluster_status]
+max_heavy_ops: 0
+timeouts:
+  shutdown_grace: 30s
+  ready: 5m
+ttl:
+  scoped_token: 2h
+quotas:
+  max_clusters: 5
+  max_nodes_per_cluster: 4
+`)
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Runtime != "podman" || s.NodeImageRepository != "registry.corp/kind/node" || s.KubernetesVersion != "1.31.0" {
+		t.Errorf("settings = %+v", s)
+	}
+	if !slices.Equal(s.Tools, []string{"list_clusters", "get_cluster_status"}) {
+		t.Errorf("tools = %v", s.Tools)
+	}
+	if s.MaxHeavyOps == nil || *s.MaxHeavyOps != 0 {
+		t.Errorf("max_heavy_ops = %v", s.MaxHeavyOps)
+	}
+	if s.Timeouts.ShutdownGrace != 30*time.Second || s.Timeouts.Ready != 5*time.Minute || s.TTL.ScopedToken != 2*time.Hour {
+		t.Errorf("durations = %+v %+v", s.Timeouts, s.TTL)
+	}
+	if s.Quotas != (Quotas{MaxClusters: 5, MaxNodesPerCluster: 4}) {
+		t.Errorf("quotas = %+v", s.Quotas)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "none.yaml"))
+	if err != nil || s == nil || s.Runtime != "" || s.MaxHeavyOps != nil {
+		t.Errorf("Load(missing) = %+v, %v", s, err)
+	}
+}
+
+func TestLoad_Empty(t *testing.T) {
+	if _, err := Load(writeSettings(t, "# nothing set\n")); err != nil {
+		t.Errorf("Load(empty) error = %v", err)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	for content, want := range map[string]string{
+		"runtmie: docker\n":             "field runtmie not found",
+		"runtime: containerd\n":         "runtime must be",
+		"backend: api\n":                "backend must be",
+		"max_heavy_ops: -1\n":           "max_heavy_ops",
+		"timeouts:\n  ready: soon\n":    "parsing settings file",
+		"ttl:\n  scoped_token: -1h\n":   "ttl.scoped_token",
+		"quotas:\n  max_clusters: -1\n": "quotas.max_clusters",
+	} {
+		_, err := Load(writeSettings(t, content))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%q) error = %v, want it to contain %q", content, err, want)
+		}
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("MCP_KIND_SETTINGS_FILE", "/etc/mcp-kind/config.yaml")
+	if got := DefaultPath(); got != "/etc/mcp-kind/config.yaml" {
+		t.Errorf("DefaultPath = %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+package state
+
+import (
+	"slices"
+	"sort"
+)
+
+// Reconciliation compares the records in the store with the clusters Kind reports.
+type Reconciliation struct {
+	// Managed clusters exist and have a record.
+	Managed []string `json:"managed"`
+	// Unmanaged clusters exist but have no record, e.g. because they were created with
+	// the kind CLI or by another tool.
+	Unmanaged []string `json:"unmanaged"`
+	// Stale records belong to clusters that no longer exist, e.g. because they were
+	// deleted outside this server.
+	Stale []string `json:"stale"`
+}
+
+// Reconcile sorts the existing clusters and stored records into managed, unmanaged and
+// stale names, each sorted.
+func Reconcile(records []ClusterRecord, clusters []string) Reconciliation {
+	rec := Reconciliation{Managed: []string{}, Unmanaged: []string{}, Stale: []string{}}
+	recorded := make(map[string]bool, len(records))
+	for _, r := range records {
+		recorded[r.Name] = true
+		if !slices.Contains(clusters, r.Name) {
+			rec.Stale = append(rec.Stale, r.Name)
+		}
+	}
+	for _, name := range clusters {
+		if recorded[name] {
+			rec.Managed = append(rec.Managed, name)
+		} else {
+			rec.Unmanaged = append(rec.Unmanaged, name)
+		}
+	}
+	sort.Strings(rec.Managed)
+	sort.Strings(rec.Unmanaged)
+	sort.Strings(rec.Stale)
+	return rec
+}
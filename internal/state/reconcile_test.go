@@ -0,0 +1,28 @@
+package state
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	records := []ClusterRecord{{Name: "gone"}, {Name: "dev"}}
+	rec := Reconcile(records, []string{"manual", "dev", "ci"})
+
+	if !slices.Equal(rec.Managed, []string{"dev"}) {
+		t.Errorf("managed = %v", rec.Managed)
+	}
+	if !slices.Equal(rec.Unmanaged, []string{"ci", "manual"}) {
+		t.Errorf("unmanaged = %v", rec.Unmanaged)
+	}
+	if !slices.Equal(rec.Stale, []string{"gone"}) {
+		t.Errorf("stale = %v", rec.Stale)
+	}
+}
+
+func TestReconcile_Empty(t *testing.T) {
+	rec := Reconcile(nil, nil)
+	if rec.Managed == nil || rec.Unmanaged == nil || rec.Stale == nil {
+		t.Errorf("expected empty, non-nil lists: %+v", rec)
+	}
+}
@@ -0,0 +1,243 @@
+// Package state persists metadata about Kind clusters managed by this server.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClusterRecord holds the metadata stored for a single cluster.
+type ClusterRecord struct {
+	Name      string            `json:"name"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	// Config is the Kind config YAML the cluster was created from.
+	Config string `json:"config,omitempty"`
+	// Mirrors lists the registries configured with mirrors after creation.
+	Mirrors []string `json:"mirrors,omitempty"`
+	// Namespace is the default namespace for namespaced tool calls that name none.
+	Namespace string `json:"namespace,omitempty"`
+	// Context names the kubeconfig context in kubeconfigs returned for the cluster.
+	Context string `json:"context,omitempty"`
+	// DisconnectedNodes maps nodes taken off the kind network by disconnect_node to the
+	// addresses restore_node reconnects them with.
+	DisconnectedNodes map[string][]string `json:"disconnected_nodes,omitempty"`
+	// Adopted marks a cluster created outside this server and adopted by
+	// reconcile_clusters; CreatedAt is then its oldest node's creation time.
+	Adopted bool `json:"adopted,omitempty"`
+	// Owner names the HTTP client that created or adopted the cluster; see
+	// MCP_KIND_ENFORCE_OWNERSHIP. It is empty for clusters created over stdio.
+	Owner string `json:"owner,omitempty"`
+}
+
+// Store is a JSON-file backed store of cluster records, safe for concurrent use.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// DefaultDir returns the state directory: $MCP_KIND_STATE_DIR if set, otherwise
+// <user config dir>/mcp-kind-manager.
+func DefaultDir() string {
+	if dir := os.Getenv("MCP_KIND_STATE_DIR"); dir != "" {
+		return dir
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "mcp-kind-manager")
+}
+
+// NewStore creates a Store that keeps its data in dir/clusters.json.
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &Store{path: filepath.Join(dir, "clusters.json")}
+}
+
+// Get returns the record for a cluster, or nil if none is stored.
+func (s *Store) Get(name string) (*ClusterRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	rec, ok := records[name]
+	if !ok {
+		return nil, nil
+	}
+	return &rec, nil
+}
+
+// Put creates or replaces the record for a cluster.
+func (s *Store) Put(rec ClusterRecord) error {
+	if rec.Name == "" {
+		return fmt.Errorf("cluster name is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	records[rec.Name] = rec
+	return s.save(records)
+}
+
+// Update applies fn to the record for a cluster, or to a new record with only its
+// name if none is stored, and saves the result, all under the store's lock so
+// concurrent updates are not lost. Nothing is written if fn changes nothing, so
+// updating a missing record without setting anything stores no empty record.
+func (s *Store) Update(name string, fn func(*ClusterRecord)) error {
+	if name == "" {
+		return fmt.Errorf("cluster name is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	rec, ok := records[name]
+	if !ok {
+		rec = ClusterRecord{Name: name}
+	}
+	before, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshaling record: %w", err)
+	}
+	fn(&rec)
+	rec.Name = name
+	if after, err := json.Marshal(rec); err == nil && string(after) == string(before) {
+		return nil
+	}
+	records[name] = rec
+	return s.save(records)
+}
+
+// Delete removes the record for a cluster. Deleting a missing record is not an error.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := records[name]; !ok {
+		return nil
+	}
+	delete(records, name)
+	return s.save(records)
+}
+
+// List returns all stored records sorted by name.
+func (s *Store) List() ([]ClusterRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]ClusterRecord, 0, len(records))
+	for _, rec := range records {
+		list = append(list, rec)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (s *Store) load() (map[string]ClusterRecord, error) {
+	records := make(map[string]ClusterRecord)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing state file %s: %w", s.path, err)
+	}
+	return records, nil
+}
+
+// save writes the records atomically via a temp file and rename.
+func (s *Store) save(records map[string]ClusterRecord) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "clusters-*.json")
+	if err != nil {
+		return fmt.Errorf("creating temp state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	tmp.Close()
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing state file: %w", err)
+	}
+	return nil
+}
+
+// ParseTags parses a comma-separated list of key=value pairs (e.g. "project=ml,owner=ci").
+func ParseTags(s string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q; expected key=value", pair)
+		}
+		tags[key] = strings.TrimSpace(value)
+	}
+	return tags, nil
+}
+
+// FormatTags renders tags in the form ParseTags reads, sorted by key.
+func FormatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		pairs = append(pairs, k+"="+tags[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// MatchesTags reports whether tags contains every key/value pair in filter.
+func MatchesTags(tags, filter map[string]string) bool {
+	for k, v := range filter {
+		if got, ok := tags[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_PutGetDelete(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	rec := ClusterRecord{
+		Name:      "dev",
+		Tags:      map[string]string{"project": "ml"},
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := store.Put(rec); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := store.Get("dev")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got == nil || got.Tags["project"] != "ml" || !got.CreatedAt.Equal(rec.CreatedAt) {
+		t.Errorf("Get = %+v", got)
+	}
+
+	if err := store.Delete("dev"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got, _ := store.Get("dev"); got != nil {
+		t.Errorf("expected record to be deleted, got %+v", got)
+	}
+	if err := store.Delete("missing"); err != nil {
+		t.Errorf("deleting a missing record should not fail: %v", err)
+	}
+}
+
+func TestStore_Update(t *testing.T) {
+	store := NewStore(t.TempDir())
+	store.Put(ClusterRecord{Name: "dev", Config: "kind: Cluster"})
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := store.Update("dev", func(rec *ClusterRecord) {
+				rec.Mirrors = append(rec.Mirrors, fmt.Sprintf("registry-%d", i))
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	got, _ := store.Get("dev")
+	if got == nil || len(got.Mirrors) != 20 || got.Config != "kind: Cluster" {
+		t.Errorf("Get = %+v", got)
+	}
+
+	if err := store.Update("missing", func(rec *ClusterRecord) { rec.Mirrors = nil }); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.Get("missing"); got != nil {
+		t.Errorf("an unchanged missing record was stored: %+v", got)
+	}
+	store.Update("new", func(rec *ClusterRecord) { rec.Namespace = "apps" })
+	if got, _ := store.Get("new"); got == nil || got.Name != "new" || got.Namespace != "apps" {
+		t.Errorf("Get = %+v", got)
+	}
+}
+
+func TestStore_ListSorted(t *testing.T) {
+	store := NewStore(t.TempDir())
+	store.Put(ClusterRecord{Name: "b"})
+	store.Put(ClusterRecord{Name: "a"})
+
+	list, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "a" || list[1].Name != "b" {
+		t.Errorf("List = %+v", list)
+	}
+}
+
+func TestStore_FilePermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	store := NewStore(dir)
+	store.Put(ClusterRecord{Name: "dev"})
+
+	info, err := os.Stat(filepath.Join(dir, "clusters.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("state file mode = %o, want 600", info.Mode().Perm())
+	}
+}
+
+func TestDefaultDir_Env(t *testing.T) {
+	t.Setenv("MCP_KIND_STATE_DIR", "/custom/state")
+	if got := DefaultDir(); got != "/custom/state" {
+		t.Errorf("DefaultDir = %q", got)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags("project=ml, owner=ci,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 2 || tags["project"] != "ml" || tags["owner"] != "ci" {
+		t.Errorf("tags = %v", tags)
+	}
+
+	if _, err := ParseTags("novalue"); err == nil {
+		t.Error("expected error for tag without '='")
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	tags, _ := ParseTags("project=ml,owner=ci")
+	if got := FormatTags(tags); got != "owner=ci,project=ml" {
+		t.Errorf("FormatTags = %q", got)
+	}
+}
+
+func TestMatchesTags(t *testing.T) {
+	tags := map[string]string{"project": "ml", "owner": "ci"}
+	if !MatchesTags(tags, map[string]string{"project": "ml"}) {
+		t.Error("expected match")
+	}
+	if MatchesTags(tags, map[string]string{"project": "web"}) {
+		t.Error("expected no match for different value")
+	}
+	if MatchesTags(nil, map[string]string{"owner": "ci"}) {
+		t.Error("expected no match for untagged cluster")
+	}
+	if !MatchesTags(nil, nil) {
+		t.Error("empty filter should match everything")
+	}
+}
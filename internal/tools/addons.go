@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/addons"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/bootstrap"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/state"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerAddonTools(s *server.MCPServer) {
+	certManagerTool := mcp.NewTool("install_cert_manager",
+		remoteUpdateHints,
+		mcp.WithDescription(
+			"Install cert-manager into a Kind cluster, wait for its deployments and admission webhook "+
+				"to become ready, and optionally create a self-signed ClusterIssuer."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("version",
+			mcp.Description("cert-manager release (e.g. 'v1.16.2'). Default: "+addons.DefaultCertManagerVersion+"."),
+		),
+		mcp.WithBoolean("create_self_signed_issuer",
+			mcp.Description("Create a self-signed ClusterIssuer after installation. Default: false."),
+		),
+		mcp.WithString("issuer_name",
+			mcp.Description("Name of the self-signed ClusterIssuer. Default: 'selfsigned'."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for cert-manager readiness. Default: 300."),
+		),
+	)
+	s.AddTool(certManagerTool, r.handleInstallCertManager)
+
+	gatewayTool := mcp.NewTool("install_gateway_api",
+		remoteUpdateHints,
+		mcp.WithDescription(
+			"Install the Gateway API CRDs for a release channel into a Kind cluster, optionally with an "+
+				"implementation (nginx-gateway-fabric or envoy-gateway). Returns the extraPortMappings "+
+				"needed to reach Gateways from the host."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("channel",
+			mcp.Description("Release channel: 'standard' or 'experimental'. Default: standard."),
+			mcp.Enum("standard", "experimental"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Gateway API release (e.g. 'v1.2.1'). Default: "+addons.DefaultGatewayAPIVersion+"."),
+		),
+		mcp.WithString("implementation",
+			mcp.Description("Optional Gateway implementation: 'nginx-gateway-fabric' or 'envoy-gateway'"),
+			mcp.Enum(addons.GatewayNginxFabric, addons.GatewayEnvoyGateway),
+		),
+		mcp.WithNumber("http_node_port",
+			mcp.Description("NodePort for HTTP traffic (nginx-gateway-fabric). Default: 30080."),
+		),
+		mcp.WithNumber("https_node_port",
+			mcp.Description("NodePort for HTTPS traffic (nginx-gateway-fabric). Default: 30443."),
+		),
+	)
+	s.AddTool(gatewayTool, r.handleInstallGatewayAPI)
+
+	observabilityTool := mcp.NewTool("install_observability",
+		remoteUpdateHints,
+		mcp.WithDescription(
+			"Install a lightweight observability stack into a Kind cluster with Kind-specific values: "+
+				"metrics-server plus the Kubernetes Dashboard, or kube-prometheus-stack (Prometheus and Grafana, "+
+				"needs helm). Returns how to open the UIs: a port-forward command, or with access=node-port the "+
+				"NodePort and the extraPortMappings needed to reach it from the host."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("stack",
+			mcp.Description("Stack to install. Default: dashboard."),
+			mcp.Enum(addons.ObservabilityDashboard, addons.ObservabilityPrometheus),
+		),
+		mcp.WithString("access",
+			mcp.Description("How the UI is reached from the host: 'port-forward' or 'node-port'. Default: port-forward."),
+			mcp.Enum(addons.AccessPortForward, addons.AccessNodePort),
+		),
+		mcp.WithNumber("node_port",
+			mcp.Description("NodePort of the UI with access=node-port. Default: 31443 (dashboard), 31300 (Grafana)."),
+		),
+		mcp.WithNumber("host_port",
+			mcp.Description("Host port to map to the NodePort. Default: 8443 (dashboard), 3000 (Grafana)."),
+		),
+		mcp.WithString("chart_version",
+			mcp.Description("kube-prometheus-stack chart version. Default: "+addons.DefaultKubePrometheusStackVersion+"."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for the stack to become ready. Default: 300."),
+		),
+	)
+	s.AddTool(observabilityTool, r.handleInstallObservability)
+
+	bootstrapTool := mcp.NewTool("bootstrap_environment",
+		destructiveHints,
+		mcp.WithDescription(
+			"Bring up a ready development environment in one call from a declarative spec: create a Kind "+
+				"cluster, load local images, install add-ons, Helm charts and manifests, in that order. Reports "+
+				"each step. When a step fails the rest are skipped and the environment is rolled back: a cluster "+
+				"this call created is deleted; in a reused cluster the charts and manifests it installed are removed."),
+		mcp.WithString("spec",
+			mcp.Required(),
+			mcp.Description(`JSON spec. {"cluster": {"name": "dev", "workers": 1, "kubernetes_version": "1.31.2", `+
+				`"enable_ingress_ports": true, "port_mappings": [...], "extra_mounts": [...], "overrides": "raw config YAML"} `+
+				`or {"name", "config_yaml"}, `+
+				`"reuse_existing": false, "images": ["app:dev"], `+
+				`"addons": [{"name": "cert-manager"|"gateway-api"|"observability", "version", "create_self_signed_issuer", `+
+				`"channel", "implementation", "stack", "access", "chart_version"}], `+
+				`"charts": [{"release", "chart", "repo", "version", "namespace", "values": {"key": "value"}}], `+
+				`"manifests": [{"source": "URL or path"} or {"inline": "YAML"}, optional "namespace"], `+
+				`"wait_ready": true, "timeout_seconds": 300, "keep_on_failure": false}`),
+		),
+		mcp.WithString("tags",
+			mcp.Description("Comma-separated key=value tags to record for a created cluster (e.g. 'project=ml,owner=ci')"),
+		),
+	)
+	s.AddTool(bootstrapTool, r.handleBootstrapEnvironment)
+}
+
+func (r *Registry) handleInstallCertManager(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: install_cert_manager")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	opts := addons.CertManagerOptions{
+		Version:    request.GetString("version", ""),
+		IssuerName: request.GetString("issuer_name", ""),
+	}
+	if val, ok := request.GetArguments()["create_self_signed_issuer"].(bool); ok {
+		opts.CreateSelfSignedIssuer = val
+	}
+	if timeout, err := request.RequireFloat("timeout_seconds"); err == nil && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	mgr := r.kindManager(ctx)
+	results, err := addons.InstallCertManager(ctx, mgr, clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to install cert-manager: %v\n\nCompleted steps:\n%s",
+			err, strings.Join(results, "\n"))), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("cert-manager installed in cluster %q.\n\nResults:\n%s",
+		clusterName, strings.Join(results, "\n"))), nil
+}
+
+func (r *Registry) handleInstallGatewayAPI(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: install_gateway_api")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	opts := addons.GatewayAPIOptions{
+		Version:        request.GetString("version", ""),
+		Channel:        request.GetString("channel", ""),
+		Implementation: request.GetString("implementation", ""),
+	}
+	if port, err := request.RequireFloat("http_node_port"); err == nil && int(port) > 0 {
+		opts.HTTPNodePort = int(port)
+	}
+	if port, err := request.RequireFloat("https_node_port"); err == nil && int(port) > 0 {
+		opts.HTTPSNodePort = int(port)
+	}
+
+	mgr := r.kindManager(ctx)
+	result, err := addons.InstallGatewayAPI(ctx, mgr, clusterName, opts)
+	if err != nil {
+		var steps []string
+		if result != nil {
+			steps = result.Steps
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to install Gateway API: %v\n\nCompleted steps:\n%s",
+			err, strings.Join(steps, "\n"))), nil
+	}
+
+	return jsonResult(result)
+}
+
+func (r *Registry) handleInstallObservability(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: install_observability")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	opts := addons.ObservabilityOptions{
+		Stack:        request.GetString("stack", ""),
+		Access:       request.GetString("access", ""),
+		ChartVersion: request.GetString("chart_version", ""),
+	}
+	if port, err := request.RequireFloat("node_port"); err == nil && int(port) > 0 {
+		opts.NodePort = int(port)
+	}
+	if port, err := request.RequireFloat("host_port"); err == nil && int(port) > 0 {
+		opts.HostPort = int(port)
+	}
+	if timeout, err := request.RequireFloat("timeout_seconds"); err == nil && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	mgr := r.kindManager(ctx)
+	result, err := addons.InstallObservability(ctx, mgr, clusterName, opts)
+	if err != nil {
+		var steps []string
+		if result != nil {
+			steps = result.Steps
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to install observability stack: %v\n\nCompleted steps:\n%s",
+			err, strings.Join(steps, "\n"))), nil
+	}
+
+	return jsonResult(result)
+}
+
+func (r *Registry) handleBootstrapEnvironment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: bootstrap_environment")
+	raw, err := request.RequireString("spec")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'spec' is required"), nil
+	}
+	var spec bootstrap.Spec
+	if err := json.Unmarshal([]byte(raw), &spec); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'spec' JSON: %v", err)), nil
+	}
+	tags, err := state.ParseTags(request.GetString("tags", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'tags': %v", err)), nil
+	}
+
+	c := &spec.Cluster
+	if spec.ReuseExisting {
+		if errResult := r.authorizeCluster(ctx, c.Name); errResult != nil {
+			return errResult, nil
+		}
+	}
+	c.KubernetesVersion = cmp.Or(c.KubernetesVersion, r.settings.KubernetesVersion)
+	c.NodeImageRepository = cmp.Or(c.NodeImageRepository, r.nodeImageRepo)
+	if c.EnableIngressPorts && c.IngressListenAddr == "" {
+		c.IngressListenAddr = kind.DetectNetworkConfig(r.runtimeInfo(ctx)).ListenAddress
+	}
+
+	mgr := r.kindManager(ctx)
+	if r.quotas.Limits().Enabled() {
+		clusters, err := mgr.ListClusters(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list clusters: %v", err)), nil
+		}
+		if !slices.Contains(clusters, c.Name) {
+			configYAML, err := c.KindConfig()
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to generate cluster config: %v", err)), nil
+			}
+			admitted, errResult := r.admitCluster(ctx, mgr, configYAML)
+			if errResult != nil {
+				return errResult, nil
+			}
+			defer admitted()
+		}
+	}
+
+	progress := r.progressReporter(ctx, request)
+	release, errResult := r.waitHeavy(ctx, progress)
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr.SetProgress(progress)
+	result, err := bootstrap.Run(ctx, mgr, spec)
+	if result == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to bootstrap environment: %v", err)), nil
+	}
+	if result.Created {
+		if err := r.store.Put(state.ClusterRecord{
+			Name: c.Name, Tags: tags, CreatedAt: time.Now().UTC(), Config: result.ConfigYAML,
+			Owner: callerName(ctx),
+		}); err != nil {
+			r.log(ctx).Warn("failed to record cluster state", "cluster", c.Name, "error", err)
+		}
+	}
+	if err != nil {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return mcp.NewToolResultError(fmt.Sprintf("failed to bootstrap environment: %v\n\n%s", err, data)), nil
+	}
+	return jsonResult(result)
+}
@@ -0,0 +1,31 @@
+package tools
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+// Tool annotation presets. Clients use these hints to auto-approve safe calls and to
+// require confirmation before destructive ones; every tool sets exactly one preset.
+var (
+	// readOnlyHints marks tools that only inspect the host or clusters.
+	readOnlyHints = hints(true, false, true, false)
+	// remoteReadOnlyHints is readOnlyHints for tools that query external services.
+	remoteReadOnlyHints = hints(true, false, true, true)
+	// createHints marks tools that add new resources; repeating the call is not a no-op.
+	createHints = hints(false, false, false, false)
+	// updateHints marks additive changes where repeating the call has no further effect.
+	updateHints = hints(false, false, true, false)
+	// remoteUpdateHints is updateHints for tools that also fetch from external registries or URLs.
+	remoteUpdateHints = hints(false, false, true, true)
+	// destructiveHints marks tools that may delete or overwrite existing state.
+	destructiveHints = hints(false, true, true, false)
+	// remoteDestructiveHints is destructiveHints for tools that also download from external URLs.
+	remoteDestructiveHints = hints(false, true, true, true)
+)
+
+func hints(readOnly, destructive, idempotent, openWorld bool) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		ReadOnlyHint:    mcp.ToBoolPtr(readOnly),
+		DestructiveHint: mcp.ToBoolPtr(destructive),
+		IdempotentHint:  mcp.ToBoolPtr(idempotent),
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),
+	})
+}
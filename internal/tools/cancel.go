@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodNotificationCancelled is sent by clients to cancel an in-flight request.
+const methodNotificationCancelled = "notifications/cancelled"
+
+// requestIDMetaKey carries the JSON-RPC request ID of a tool call from the
+// BeforeCallTool hook to ToolMiddleware; mcp-go does not pass it to tool handlers.
+const requestIDMetaKey = "mcp-kind-manager/request-id"
+
+// Hooks returns the server hooks the Registry needs to cancel tool calls when the
+// client sends notifications/cancelled for them, and to end a session's health
+// watches and forget its current cluster with it. Install them with server.WithHooks.
+func (r *Registry) Hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddBeforeCallTool(func(_ context.Context, id any, request *mcp.CallToolRequest) {
+		if request.Params.Meta == nil {
+			request.Params.Meta = &mcp.Meta{}
+		}
+		if request.Params.Meta.AdditionalFields == nil {
+			request.Params.Meta.AdditionalFields = make(map[string]any)
+		}
+		request.Params.Meta.AdditionalFields[requestIDMetaKey] = mcp.NewRequestId(id).String()
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		r.stopSessionHealthWatches(session.SessionID())
+		r.forgetCurrentCluster(session.SessionID())
+	})
+	return hooks
+}
+
+// registerCancellation handles notifications/cancelled by cancelling the context of
+// the tool call it names, which kills the commands the call is running.
+func (r *Registry) registerCancellation(s *server.MCPServer) {
+	s.AddNotificationHandler(methodNotificationCancelled, func(ctx context.Context, n mcp.JSONRPCNotification) {
+		id, ok := n.Params.AdditionalFields["requestId"]
+		if !ok {
+			return
+		}
+		key := callKey(ctx, mcp.NewRequestId(id).String())
+		r.callsMu.Lock()
+		cancel, ok := r.calls[key]
+		r.callsMu.Unlock()
+		if ok {
+			reason, _ := n.Params.AdditionalFields["reason"].(string)
+			r.logger.Info("tool call cancelled by client", "request", key, "reason", reason)
+			cancel()
+		}
+	})
+}
+
+// trackCall makes ctx cancellable through registerCancellation and Shutdown for the
+// duration of a tool call. The returned function must be called when the call ends.
+// It reports false, and tracks nothing, once the Registry is shutting down.
+func (r *Registry) trackCall(ctx context.Context, request mcp.CallToolRequest) (context.Context, func(), bool) {
+	r.callsMu.Lock()
+	defer r.callsMu.Unlock()
+	if r.stopCtx.Err() != nil {
+		return ctx, func() {}, false
+	}
+	r.active.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	stopCancel := context.AfterFunc(r.stopCtx, cancel)
+
+	var key string
+	if request.Params.Meta != nil {
+		if id, _ := request.Params.Meta.AdditionalFields[requestIDMetaKey].(string); id != "" {
+			key = callKey(ctx, id)
+			r.calls[key] = cancel
+		}
+	}
+	return ctx, func() {
+		if key != "" {
+			r.callsMu.Lock()
+			delete(r.calls, key)
+			r.callsMu.Unlock()
+		}
+		stopCancel()
+		cancel()
+		r.active.Done()
+	}, true
+}
+
+// callKey identifies a request within its client session.
+func callKey(ctx context.Context, requestID string) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID() + "/" + requestID
+	}
+	return requestID
+}
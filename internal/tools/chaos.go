@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/state"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerChaosTools(s *server.MCPServer) {
+	nodeParams := []mcp.ToolOption{
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("node",
+			mcp.Required(),
+			mcp.Description("Node container name (e.g. 'dev-worker2') or its suffix after the cluster name (e.g. 'worker2')"),
+		),
+	}
+
+	killTool := mcp.NewTool("kill_node",
+		append([]mcp.ToolOption{
+			destructiveHints,
+			mcp.WithDescription(
+				"Kill a node container of a Kind cluster to test how workloads handle node loss, as if the machine " +
+					"lost power. Returns the node's state and what the loss means for the cluster (API server, etcd " +
+					"quorum, pod eviction). get_cluster_status shows the node as exited; use 'restore_node' to bring it back."),
+			mcp.WithString("signal",
+				mcp.Description("Signal to send, e.g. SIGTERM for a graceful shutdown. Default: SIGKILL."),
+			),
+		}, nodeParams...)...,
+	)
+	s.AddTool(killTool, r.handleKillNode)
+
+	disconnectTool := mcp.NewTool("disconnect_node",
+		append([]mcp.ToolOption{
+			destructiveHints,
+			mcp.WithDescription(
+				"Disconnect a running node container of a Kind cluster from the kind network to simulate a network " +
+					"partition: its pods keep running but the rest of the cluster cannot reach it. The node's " +
+					"addresses are recorded so 'restore_node' reconnects it with the same ones. get_cluster_status " +
+					"reports the node as disconnected."),
+		}, nodeParams...)...,
+	)
+	s.AddTool(disconnectTool, r.handleDisconnectNode)
+
+	restoreTool := mcp.NewTool("restore_node",
+		append([]mcp.ToolOption{
+			updateHints,
+			mcp.WithDescription(
+				"Recover a node after 'kill_node', 'disconnect_node' or pausing: reconnect it to the kind network " +
+					"with its recorded addresses, start or unpause its container, wait for the API server, and " +
+					"report the node's Kubernetes Ready condition."),
+			mcp.WithNumber("timeout_seconds",
+				mcp.Description("How long to wait for the API server and the node to become ready. "+
+					"Default: the settings file's timeouts.ready, or 120."),
+			),
+		}, nodeParams...)...,
+	)
+	s.AddTool(restoreTool, r.handleRestoreNode)
+}
+
+func (r *Registry) handleKillNode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: kill_node")
+	clusterName, node, errResult := r.requireClusterNode(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	fault, err := r.kindManager(ctx).KillNode(ctx, clusterName, node, request.GetString("signal", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to kill node: %v", err)), nil
+	}
+	return jsonResult(fault)
+}
+
+func (r *Registry) handleDisconnectNode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: disconnect_node")
+	clusterName, node, errResult := r.requireClusterNode(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	fault, err := r.kindManager(ctx).DisconnectNode(ctx, clusterName, node)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to disconnect node: %v", err)), nil
+	}
+
+	err = r.store.Update(clusterName, func(rec *state.ClusterRecord) {
+		if rec.DisconnectedNodes == nil {
+			rec.DisconnectedNodes = make(map[string][]string)
+		}
+		rec.DisconnectedNodes[fault.Name] = fault.Addresses
+	})
+	if err != nil {
+		r.log(ctx).Warn("recording node addresses failed", "node", fault.Name, "error", err)
+		fault.Message += fmt.Sprintf("; recording its addresses failed (%v), so restore_node may reconnect it with new ones", err)
+	}
+	return jsonResult(fault)
+}
+
+func (r *Registry) handleRestoreNode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: restore_node")
+	clusterName, node, errResult := r.requireClusterNode(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+	timeout := r.readyTimeout(request)
+
+	rec, err := r.store.Get(clusterName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read cluster state: %v", err)), nil
+	}
+	var addresses []string
+	if rec != nil {
+		// The record is keyed by full container name; node may be a suffix.
+		addresses = rec.DisconnectedNodes[node]
+		if addresses == nil {
+			addresses = rec.DisconnectedNodes[clusterName+"-"+node]
+		}
+	}
+
+	fault, err := r.kindManager(ctx).RestoreNode(ctx, clusterName, node, addresses, timeout)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to restore node: %v", err)), nil
+	}
+	if rec != nil && rec.DisconnectedNodes[fault.Name] != nil && !fault.Disconnected {
+		err := r.store.Update(clusterName, func(rec *state.ClusterRecord) {
+			delete(rec.DisconnectedNodes, fault.Name)
+		})
+		if err != nil {
+			r.log(ctx).Warn("clearing recorded node addresses failed", "node", fault.Name, "error", err)
+		}
+	}
+	return jsonResult(fault)
+}
+
+// requireClusterNode returns the cluster_name and node parameters, or an error result
+// naming the missing one.
+func (r *Registry) requireClusterNode(ctx context.Context, request mcp.CallToolRequest) (string, string, *mcp.CallToolResult) {
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return "", "", errResult
+	}
+	node, err := request.RequireString("node")
+	if err != nil {
+		return "", "", mcp.NewToolResultError("parameter 'node' is required")
+	}
+	return clusterName, node, nil
+}
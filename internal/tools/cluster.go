@@ -1,15 +1,26 @@
 package tools
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/registry"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/state"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 func (r *Registry) registerClusterTools(s *server.MCPServer) {
 	createTool := mcp.NewTool("create_cluster",
+		createHints,
 		mcp.WithDescription(
 			"Create a Kind cluster from a configuration YAML. "+
 				"Use 'generate_cluster_config' first to generate and review the config YAML."),
@@ -19,96 +30,812 @@ func (r *Registry) registerClusterTools(s *server.MCPServer) {
 		),
 		mcp.WithString("config_yaml",
 			mcp.Required(),
-			mcp.Description("The Kind cluster configuration YAML (from generate_cluster_config)"),
+			mcp.Description("The Kind cluster configuration YAML (from generate_cluster_config); JSON is accepted too"),
 		),
+		mcp.WithBoolean("offline",
+			mcp.Description("Air-gapped mode: require every node image to be pinned and present locally, "+
+				"and fail fast with the list of missing images instead of pulling. Default: false."),
+		),
+		mcp.WithString("tags",
+			mcp.Description("Comma-separated key=value tags to record for the cluster (e.g. 'project=ml,owner=ci')"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate inputs, run preflight checks, and return the exact commands and config file "+
+				"that would be used, without creating anything. Default: false."),
+		),
+		mcp.WithBoolean("retain_on_failure",
+			mcp.Description("If creation fails, keep the node containers (kind --retain) and export their logs "+
+				"to a local directory for inspection; delete the cluster with 'delete_cluster' afterwards. Default: false."),
+		),
+		mcp.WithArray("extra_kind_args",
+			mcp.WithStringItems(),
+			mcp.Description("Extra flags passed to 'kind create cluster', e.g. ['--wait=60s', '--verbosity=3']. "+
+				"Allowed: --retain, --wait, --verbosity (-v), --quiet (-q); --name, --config and --kubeconfig "+
+				"are set by the server. Set node images in config_yaml rather than with --image. Only --retain and "+
+				"--wait are supported by the library backend."),
+		),
+		maxOutputParam(),
 	)
 	s.AddTool(createTool, r.handleCreateCluster)
 
 	deleteTool := mcp.NewTool("delete_cluster",
+		destructiveHints,
 		mcp.WithDescription("Delete a Kind cluster by name."),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("Name of the Kind cluster to delete"),
 		),
+		mcp.WithArray("extra_kind_args",
+			mcp.WithStringItems(),
+			mcp.Description("Extra flags passed to 'kind delete cluster' with the kind CLI backend. "+
+				"Allowed: --verbosity (-v), --quiet (-q)."),
+		),
+		mcp.WithBoolean("keep_host_storage",
+			mcp.Description("Keep the cluster's host storage directory (see generate_cluster_config host_storage) "+
+				"and the volume data in it. Default: false, it is removed."),
+		),
 	)
 	s.AddTool(deleteTool, r.handleDeleteCluster)
 
+	recreateTool := mcp.NewTool("recreate_cluster",
+		destructiveHints,
+		mcp.WithDescription(
+			"Delete and recreate a Kind cluster from the config it was created with. "+
+				"If no config was recorded, one is reconstructed from the running cluster's nodes and images. "+
+				"Tags are preserved."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Kind cluster to recreate"),
+		),
+		mcp.WithString("kubernetes_version",
+			mcp.Description("Optionally move every node to this Kubernetes version (e.g. '1.31.0')"),
+		),
+		maxOutputParam(),
+	)
+	s.AddTool(recreateTool, r.handleRecreateCluster)
+
+	diffTool := mcp.NewTool("diff_cluster_config",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Compare a cluster's desired Kind config (the one it was created from, or config_yaml) with its "+
+				"running node containers: node counts, pinned node images, extra mounts, port mappings and registry "+
+				"mirrors. Each difference says whether it requires recreating the cluster or can be fixed live."),
+		clusterNameParam("name", "Name of the Kind cluster"),
+		mcp.WithString("config_yaml",
+			mcp.Description("Desired Kind config to compare against. Default: the config recorded at creation."),
+		),
+	)
+	s.AddTool(diffTool, r.handleDiffClusterConfig)
+
 	listTool := mcp.NewTool("list_clusters",
-		mcp.WithDescription("List all Kind clusters currently running."),
+		readOnlyHints,
+		mcp.WithDescription(
+			"List all Kind clusters currently running, with any tags recorded at creation. Clusters created "+
+				"outside this server are reported as unmanaged, and records of clusters deleted outside it as "+
+				"stale; use 'reconcile_clusters' to adopt or prune them."),
+		mcp.WithString("filter",
+			mcp.Description("Only list clusters whose tags match all of these comma-separated key=value pairs"),
+		),
+		mcp.WithBoolean("detailed",
+			mcp.Description("Return a summary per cluster: state (running/stopped/paused/degraded), node counts, "+
+				"Kubernetes version, creation time, and tags. Default: false."),
+		),
+		verbosityParam(),
 	)
 	s.AddTool(listTool, r.handleListClusters)
 
+	reconcileTool := mcp.NewTool("reconcile_clusters",
+		updateHints,
+		mcp.WithDescription(
+			"Compare the clusters Kind reports with this server's state store. Reports managed clusters, "+
+				"unmanaged ones (created with the kind CLI or another tool, so they have no tags, recorded config "+
+				"or defaults) and stale records of clusters that no longer exist. Optionally adopts unmanaged "+
+				"clusters into the store and prunes stale records."),
+		mcp.WithArray("adopt",
+			mcp.WithStringItems(),
+			mcp.Description("Unmanaged clusters to adopt; ['*'] adopts all of them"),
+		),
+		mcp.WithString("tags",
+			mcp.Description("Comma-separated key=value tags to record for adopted clusters (e.g. 'owner=ci')"),
+		),
+		mcp.WithBoolean("prune_stale",
+			mcp.Description("Delete the records of clusters that no longer exist. Default: false."),
+		),
+	)
+	s.AddTool(reconcileTool, r.handleReconcileClusters)
+
 	statusTool := mcp.NewTool("get_cluster_status",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Get the status of a Kind cluster, including node names, roles, container states, and nodes "+
+				"disconnected from the kind network (e.g. by 'disconnect_node')."),
+		clusterNameParam("name", "Name of the Kind cluster"),
+		verbosityParam(),
+	)
+	s.AddTool(statusTool, r.handleGetClusterStatus)
+
+	defaultsTool := mcp.NewTool("set_cluster_defaults",
+		updateHints,
+		mcp.WithDescription(
+			"Set the default namespace and kubeconfig context name for a Kind cluster. The namespace is used by "+
+				"kubectl, run_pod, the rollout tools and create_scoped_kubeconfig when a call names none; the "+
+				"context name and namespace are written into kubeconfigs from get_kubeconfig. Pass an empty "+
+				"string to clear a default; omitted parameters are left unchanged. Returns the current defaults."),
+		clusterNameParam("name", "Name of the Kind cluster"),
+		mcp.WithString("namespace",
+			mcp.Description("Default namespace for namespaced tool calls"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Context name for kubeconfigs returned by get_kubeconfig (instead of kind-<name>)"),
+		),
+	)
+	s.AddTool(defaultsTool, r.handleSetClusterDefaults)
+
+	useTool := mcp.NewTool("use_cluster",
+		updateHints,
 		mcp.WithDescription(
-			"Get the status of a Kind cluster, including node names, roles, and container states."),
+			"Select the current cluster of this client session: tools that work with an existing cluster (status, "+
+				"kubectl, images, add-ons, registry mirrors, diagnostics, ...) use it when a call omits the cluster name. "+
+				"Other sessions keep their own. Creating, "+
+				"deleting, recreating, stopping and pausing a cluster still need an explicit name. The server starts "+
+				"with KIND_CLUSTER_NAME as the current cluster when set. Call without parameters to see the current "+
+				"cluster."),
 		mcp.WithString("name",
+			mcp.Description("Existing Kind cluster to make current"),
+		),
+		mcp.WithBoolean("clear",
+			mcp.Description("Clear the current cluster, so tools need an explicit name again. Default: false."),
+		),
+	)
+	s.AddTool(useTool, r.handleUseCluster)
+
+	diagnoseTool := mcp.NewTool("diagnose_networking",
+		remoteUpdateHints,
+		mcp.WithDescription(
+			"Diagnose networking in a Kind cluster. Runs short-lived debug pods in a temporary namespace to test "+
+				"cluster DNS, external DNS, pod-to-pod, pod-to-service and egress connectivity, then checks from the "+
+				"host that the node containers' published ports accept connections. Returns each check, the first "+
+				"broken layer, and likely causes for the detected runtime backend."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("image",
+			mcp.Description("Debug pod image with nslookup, wget and httpd. Default: "+kind.DefaultDiagnoseImage+"."),
+		),
+		mcp.WithString("egress_url",
+			mcp.Description("HTTP URL fetched from a pod to test egress. Default: http://example.com."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for the debug pods to start. Default: 120."),
+		),
+	)
+	s.AddTool(diagnoseTool, r.handleDiagnoseNetworking)
+
+	componentLogsTool := mcp.NewTool("get_node_component_logs",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Tail the logs of a Kubernetes component inside a Kind node container: the kubelet or containerd "+
+				"journal, or the newest container of a control-plane static pod (kube-apiserver, "+
+				"kube-controller-manager, kube-scheduler, etcd) with its state and restart count. Use it when "+
+				"system components crash-loop or a node stays NotReady."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("node",
 			mcp.Required(),
-			mcp.Description("Name of the Kind cluster"),
+			mcp.Description("Node container name (e.g. 'dev-worker2') or its suffix after the cluster name (e.g. 'control-plane')"),
+		),
+		mcp.WithString("component",
+			mcp.Required(),
+			mcp.Description("Component whose logs to read; static pods only run on control-plane nodes"),
+			mcp.Enum(kind.NodeComponents...),
+		),
+		mcp.WithNumber("lines",
+			mcp.Description(fmt.Sprintf("Number of log lines from the end. Default: %d, at most %d.",
+				kind.DefaultComponentLogLines, kind.MaxComponentLogLines)),
 		),
+		mcp.WithString("since",
+			mcp.Description("Only logs newer than this: a duration such as '15m' or an RFC 3339 time"),
+		),
+		maxOutputParam(),
 	)
-	s.AddTool(statusTool, r.handleGetClusterStatus)
+	s.AddTool(componentLogsTool, r.handleGetNodeComponentLogs)
+
+	debugNodeTool := mcp.NewTool("debug_node",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Run a bounded set of read-only diagnostics inside a Kind node container, like 'oc debug node', and "+
+				"return one report: disk and inode usage (df), memory (free), load, whether containerd and the "+
+				"kubelet are active, the CRI containers (crictl ps -a), a per-chain summary of the iptables rules, "+
+				"and recent kubelet errors. Each command is reported with its output and whether it succeeded."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("node",
+			mcp.Required(),
+			mcp.Description("Node container name (e.g. 'dev-worker2') or its suffix after the cluster name (e.g. 'control-plane')"),
+		),
+		mcp.WithArray("checks",
+			mcp.WithStringItems(mcp.Enum(kind.NodeDiagnostics...)),
+			mcp.Description("Diagnostics to run. Default: all of them."),
+		),
+		maxOutputParam(),
+	)
+	s.AddTool(debugNodeTool, r.handleDebugNode)
+
+	stopTool := mcp.NewTool("stop_cluster",
+		destructiveHints,
+		mcp.WithDescription(
+			"Stop a Kind cluster's node containers (workers, then control planes, then the HA load balancer) "+
+				"without deleting them. Use 'start_cluster' to bring it back."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Kind cluster to stop"),
+		),
+	)
+	s.AddTool(stopTool, r.handleStopCluster)
+
+	startTool := mcp.NewTool("start_cluster",
+		updateHints,
+		mcp.WithDescription(
+			"Start a stopped Kind cluster's node containers (load balancer, control planes, then workers), "+
+				"wait for the API server, and report which nodes recovered."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Kind cluster to start"),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for the API server to become ready. "+
+				"Default: the settings file's timeouts.ready, or 120."),
+		),
+	)
+	s.AddTool(startTool, r.handleStartCluster)
+
+	pauseTool := mcp.NewTool("pause_cluster",
+		updateHints,
+		mcp.WithDescription(
+			"Pause a Kind cluster's node containers to free CPU while keeping all cluster state in memory. "+
+				"Use 'unpause_cluster' to resume."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Kind cluster to pause"),
+		),
+	)
+	s.AddTool(pauseTool, r.handlePauseCluster)
+
+	unpauseTool := mcp.NewTool("unpause_cluster",
+		updateHints,
+		mcp.WithDescription("Resume a paused Kind cluster's node containers."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Kind cluster to resume"),
+		),
+	)
+	s.AddTool(unpauseTool, r.handleUnpauseCluster)
 }
 
 func (r *Registry) handleCreateCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Info("tool called: create_cluster")
+	r.log(ctx).Info("tool called: create_cluster")
 	name, err := request.RequireString("name")
 	if err != nil {
 		return mcp.NewToolResultError("parameter 'name' is required"), nil
 	}
+	if err := kind.ValidateClusterName(name); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	configYAML, err := request.RequireString("config_yaml")
 	if err != nil {
 		return mcp.NewToolResultError("parameter 'config_yaml' is required"), nil
 	}
 
+	tags, err := state.ParseTags(request.GetString("tags", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'tags': %v", err)), nil
+	}
+	extraArgs := request.GetStringSlice("extra_kind_args", nil)
+	normalizedArgs, err := kind.ValidateKindArgs(kind.KindCommandCreateCluster, extraArgs)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid extra_kind_args: %v", err)), nil
+	}
+
 	mgr := r.kindManager(ctx)
-	output, err := mgr.CreateCluster(ctx, name, configYAML)
+	offline := false
+	if val, ok := request.GetArguments()["offline"].(bool); ok {
+		offline = val
+	}
+
+	if val, ok := request.GetArguments()["dry_run"].(bool); ok && val {
+		plan, err := mgr.PlanCreateCluster(ctx, name, configYAML)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("dry run failed: %v", err)), nil
+		}
+		if len(normalizedArgs) > 0 {
+			plan.Commands[0] += " " + kind.ShellJoin(normalizedArgs)
+		}
+		if offline {
+			check := kind.PreflightCheck{Name: "offline-images", Status: kind.CheckPass,
+				Message: "all node images present locally"}
+			if err := mgr.CheckOfflineImages(ctx, configYAML); err != nil {
+				check.Status, check.Message = kind.CheckFail, err.Error()
+			}
+			plan.Preflight = append(plan.Preflight, check)
+		} else {
+			check := kind.PreflightCheck{Name: "node-images", Status: kind.CheckPass,
+				Message: "pinned node images found locally or in their registry"}
+			if err := mgr.CheckNodeImages(ctx, configYAML); err != nil {
+				check.Status, check.Message = kind.CheckFail, err.Error()
+			}
+			plan.Preflight = append(plan.Preflight, check)
+		}
+		plan.Preflight = append(plan.Preflight, mgr.CheckNodeImageArch(ctx, configYAML), mgr.CheckIPFamily(ctx, configYAML),
+			mgr.CheckKindCompatibility(ctx, configYAML), r.quotaCheck(ctx, mgr, configYAML))
+		return jsonResult(plan)
+	}
+
+	if offline {
+		if err := mgr.CheckOfflineImages(ctx, configYAML); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("offline preflight failed: %v", err)), nil
+		}
+	} else if err := mgr.CheckNodeImages(ctx, configYAML); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("node image preflight failed: %v", err)), nil
+	}
+	ipCheck := mgr.CheckIPFamily(ctx, configYAML)
+	if ipCheck.Status == kind.CheckFail {
+		return mcp.NewToolResultError(fmt.Sprintf("IP family preflight failed: %s", ipCheck.Message)), nil
+	}
+	compatCheck := mgr.CheckKindCompatibility(ctx, configYAML)
+	if compatCheck.Status == kind.CheckFail {
+		return mcp.NewToolResultError(fmt.Sprintf("kind compatibility preflight failed: %s", compatCheck.Message)), nil
+	}
+	archCheck := mgr.CheckNodeImageArch(ctx, configYAML)
+	if archCheck.Status == kind.CheckWarn {
+		r.log(ctx).Warn("node image architecture mismatch", "cluster", name, "detail", archCheck.Message)
+	}
+
+	// A managed host storage directory removed with its previous cluster is recreated,
+	// so the runtime does not create it as root. Directories elsewhere are left to the
+	// caller.
+	storageNote := ""
+	if dir := kind.HostStorageDir(configYAML); dir != "" {
+		if kind.InStorageDir(r.storageDir, dir) {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to create the host storage directory: %v", err)), nil
+			}
+		} else if _, err := os.Stat(dir); err != nil {
+			storageNote = fmt.Sprintf("\n\nNote: host storage directory %s is outside %s and was not created by this "+
+				"server; the container runtime creates a missing mount source as root.", dir, r.storageDir)
+		}
+	}
+
+	var createOpts kind.CreateOptions
+	if val, ok := request.GetArguments()["retain_on_failure"].(bool); ok {
+		createOpts.RetainOnFailure = val
+	}
+	createOpts.ExtraArgs = extraArgs
+
+	admitted, errResult := r.admitCluster(ctx, mgr, configYAML)
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer admitted()
+	progress := r.progressReporter(ctx, request)
+	release, errResult := r.waitHeavy(ctx, progress)
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr.SetProgress(progress)
+	output, err := mgr.CreateClusterWithOptions(ctx, name, configYAML, createOpts)
+	var retained *kind.RetainedCreateError
+	if errors.As(err, &retained) {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create cluster: %v\n\n"+
+			"Inspect the kept node containers with 'get_cluster_status' and the exported logs (journal, kubelet, "+
+			"containerd and pod logs per node). Delete the cluster with 'delete_cluster' when done.",
+			err)), nil
+	}
+	hostChecks := mgr.LinuxHostChecks()
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("failed to create cluster: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create cluster: %v%s", err, hostCheckHints(hostChecks))), nil
+	}
+	output += storageNote
+	for _, check := range append([]kind.PreflightCheck{ipCheck, archCheck, compatCheck}, hostChecks...) {
+		if check.Status == kind.CheckWarn {
+			output += "\n\nWarning: " + check.Message
+			for _, cmd := range check.Remediation {
+				output += "\n  " + cmd
+			}
+		}
 	}
 
+	if err := r.store.Put(state.ClusterRecord{
+		Name: name, Tags: tags, CreatedAt: time.Now().UTC(), Config: configYAML, Owner: callerName(ctx),
+	}); err != nil {
+		r.log(ctx).Warn("failed to record cluster state", "cluster", name, "error", err)
+	}
+
+	output = r.limitOutput(request, "create_cluster "+name, output)
 	return mcp.NewToolResultText(fmt.Sprintf("Cluster %q created successfully.\n\n%s", name, output)), nil
 }
 
+// hostCheckHints renders the Linux host checks that did not pass as likely causes of
+// a failed create, with their fixes.
+func hostCheckHints(checks []kind.PreflightCheck) string {
+	var b strings.Builder
+	for _, c := range checks {
+		if c.Status == kind.CheckPass {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString("\n\nHost settings that commonly break Kind:")
+		}
+		b.WriteString("\n- " + c.Message)
+		for _, cmd := range c.Remediation {
+			b.WriteString("\n    " + cmd)
+		}
+	}
+	return b.String()
+}
+
 func (r *Registry) handleDeleteCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Info("tool called: delete_cluster")
+	r.log(ctx).Info("tool called: delete_cluster")
 	name, err := request.RequireString("name")
 	if err != nil {
 		return mcp.NewToolResultError("parameter 'name' is required"), nil
 	}
 
+	extraArgs := request.GetStringSlice("extra_kind_args", nil)
+	if _, err := kind.ValidateKindArgs(kind.KindCommandDeleteCluster, extraArgs); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid extra_kind_args: %v", err)), nil
+	}
+
 	mgr := r.kindManager(ctx)
-	output, err := mgr.DeleteCluster(ctx, name)
+	storageDir, storageImage, storageNote := "", "", ""
+	if keep, _ := request.GetArguments()["keep_host_storage"].(bool); !keep {
+		storageDir = r.managedHostStorage(name)
+	}
+	if storageDir != "" {
+		// Only remove the directory the nodes really mount, never one the record
+		// merely names.
+		live, image, err := mgr.HostStorageSource(ctx, name)
+		switch {
+		case err != nil:
+			storageNote = fmt.Sprintf("\n\nKept host storage directory %s: its mount could not be verified: %v", storageDir, err)
+			storageDir = ""
+		case filepath.Clean(live) != filepath.Clean(storageDir):
+			return mcp.NewToolResultError(fmt.Sprintf("cluster %q mounts %s at %s, not its managed host storage "+
+				"directory %s; set keep_host_storage=true to delete it and keep both directories",
+				name, live, kind.HostStorageNodePath, storageDir)), nil
+		default:
+			storageImage = image
+		}
+	}
+	output, err := mgr.DeleteClusterWithArgs(ctx, name, extraArgs)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to delete cluster: %v", err)), nil
 	}
+	if err := r.store.Delete(name); err != nil {
+		r.log(ctx).Warn("failed to remove cluster state", "cluster", name, "error", err)
+	}
+	r.scheduleCloudRefresh(name, 0, registry.CloudRefreshOptions{})
+	r.clearCurrentCluster(name)
+	if storageDir != "" {
+		err := kind.RemoveHostStorageDir(r.storageDir, storageDir)
+		if err != nil && !errors.Is(err, kind.ErrOutsideStorageDir) {
+			// Volume data is often owned by root; clear it from a container and retry.
+			if clearErr := mgr.ClearHostStorage(ctx, storageDir, storageImage); clearErr != nil {
+				r.log(ctx).Warn("failed to clear host storage", "cluster", name, "error", clearErr)
+			} else {
+				err = kind.RemoveHostStorageDir(r.storageDir, storageDir)
+			}
+		}
+		if err != nil {
+			storageNote = fmt.Sprintf("\n\nWarning: failed to remove host storage directory: %v", err)
+		} else {
+			storageNote = "\n\nRemoved host storage directory " + storageDir
+		}
+	}
+	output += storageNote
 
 	return mcp.NewToolResultText(fmt.Sprintf("Cluster %q deleted successfully.\n\n%s", name, output)), nil
 }
 
-func (r *Registry) handleListClusters(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Debug("tool called: list_clusters")
+func (r *Registry) handleRecreateCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: recreate_cluster")
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'name' is required"), nil
+	}
+
+	mgr := r.kindManager(ctx)
+	rec, err := r.store.Get(name)
+	if err != nil {
+		r.log(ctx).Warn("failed to read cluster state", "cluster", name, "error", err)
+	}
+	if rec == nil {
+		rec = &state.ClusterRecord{Name: name}
+	}
+
+	configYAML, source := rec.Config, "stored"
+	if configYAML == "" {
+		if stored, err := mgr.StoredConfig(name); err == nil {
+			configYAML, source = stored, "stored"
+		}
+	}
+	if configYAML == "" {
+		configYAML, err = mgr.ReconstructConfig(ctx, name)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("no stored config and reconstruction failed: %v", err)), nil
+		}
+		source = "reconstructed"
+	}
+	if version := request.GetString("kubernetes_version", ""); version != "" {
+		configYAML, err = kind.SetNodeImageVersion(configYAML, version)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to set Kubernetes version: %v", err)), nil
+		}
+	}
+
+	progress := r.progressReporter(ctx, request)
+	release, errResult := r.waitHeavy(ctx, progress)
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr.SetProgress(progress)
+	output, err := mgr.RecreateCluster(ctx, name, configYAML)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to recreate cluster: %v", err)), nil
+	}
+
+	err = r.store.Update(name, func(rec *state.ClusterRecord) {
+		rec.Config, rec.CreatedAt = configYAML, time.Now().UTC()
+	})
+	if err != nil {
+		r.log(ctx).Warn("failed to record cluster state", "cluster", name, "error", err)
+	}
+
+	output = r.limitOutput(request, "recreate_cluster "+name, output)
+	return mcp.NewToolResultText(fmt.Sprintf("Cluster %q recreated from %s config.\n\n%s", name, source, output)), nil
+}
+
+func (r *Registry) handleDiffClusterConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: diff_cluster_config")
+	name, errResult := r.clusterParam(ctx, request, "name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	rec, err := r.store.Get(name)
+	if err != nil {
+		r.log(ctx).Warn("failed to read cluster state", "cluster", name, "error", err)
+	}
+	if rec == nil {
+		rec = &state.ClusterRecord{Name: name}
+	}
+	configYAML, source := request.GetString("config_yaml", ""), "provided"
+	if configYAML == "" {
+		configYAML, source = rec.Config, "stored"
+	}
+	if configYAML == "" {
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"no config was recorded for cluster %q; pass the desired config as 'config_yaml'", name)), nil
+	}
+
+	report, err := r.kindManager(ctx).DiffConfig(ctx, name, configYAML, rec.Mirrors)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to diff cluster config: %v", err)), nil
+	}
+	report.Source = source
+	return jsonResult(report)
+}
+
+func (r *Registry) handleListClusters(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: list_clusters")
+	filter, err := state.ParseTags(request.GetString("filter", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'filter': %v", err)), nil
+	}
+
 	mgr := r.kindManager(ctx)
 	clusters, err := mgr.ListClusters(ctx)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to list clusters: %v", err)), nil
 	}
 
-	if len(clusters) == 0 {
+	records, err := r.store.List()
+	if err != nil {
+		r.log(ctx).Warn("failed to read cluster state", "error", err)
+	}
+	// Without the records every cluster would look unmanaged.
+	var reconciled state.Reconciliation
+	if err == nil {
+		reconciled = state.Reconcile(records, clusters)
+	}
+	tagsByName := make(map[string]map[string]string)
+	owners := make(map[string]string)
+	for _, rec := range records {
+		if len(rec.Tags) > 0 {
+			tagsByName[rec.Name] = rec.Tags
+		}
+		if rec.Owner != "" {
+			owners[rec.Name] = rec.Owner
+		}
+	}
+
+	matched := []string{}
+	matchedTags := make(map[string]map[string]string)
+	for _, name := range clusters {
+		if !state.MatchesTags(tagsByName[name], filter) {
+			continue
+		}
+		matched = append(matched, name)
+		if tags, ok := tagsByName[name]; ok {
+			matchedTags[name] = tags
+		}
+	}
+
+	current, _ := r.currentCluster(ctx)
+	if len(matched) == 0 {
+		if len(filter) > 0 {
+			return mcp.NewToolResultText("No Kind clusters match the filter."), nil
+		}
 		return mcp.NewToolResultText("No Kind clusters found."), nil
 	}
 
+	if val, ok := request.GetArguments()["detailed"].(bool); ok && val {
+		summaries := make([]*kind.ClusterSummary, 0, len(matched))
+		for _, name := range matched {
+			summary, err := mgr.SummarizeCluster(ctx, name)
+			if err != nil {
+				r.log(ctx).Warn("failed to summarize cluster", "cluster", name, "error", err)
+				summary = &kind.ClusterSummary{Name: name, State: "unknown"}
+			}
+			summary.Tags = matchedTags[name]
+			summary.Owner = owners[name]
+			summary.Unmanaged = slices.Contains(reconciled.Unmanaged, name)
+			summary.Current = name == current
+			summaries = append(summaries, summary)
+		}
+		if summaryRequested(request) {
+			lines := make([]string, 0, len(summaries)+1)
+			for _, summary := range summaries {
+				lines = append(lines, summary.Line())
+			}
+			return linesResult(append(lines, reconciliationLines(reconciled)...))
+		}
+		result := map[string]any{"clusters": summaries, "count": len(summaries)}
+		addReconciliation(result, reconciled)
+		return jsonResult(result)
+	}
+	if summaryRequested(request) {
+		lines := make([]string, 0, len(matched)+1)
+		for _, name := range matched {
+			line := name
+			if tags := matchedTags[name]; len(tags) > 0 {
+				line += " " + state.FormatTags(tags)
+			}
+			if slices.Contains(reconciled.Unmanaged, name) {
+				line += " [unmanaged]"
+			}
+			if name == current {
+				line += " [current]"
+			}
+			lines = append(lines, line)
+		}
+		return linesResult(append(lines, reconciliationLines(reconciled)...))
+	}
+
 	result := map[string]any{
-		"clusters": clusters,
-		"count":    len(clusters),
+		"clusters": matched,
+		"count":    len(matched),
 	}
+	if len(matchedTags) > 0 {
+		result["tags"] = matchedTags
+	}
+	var unmanaged []string
+	for _, name := range matched {
+		if slices.Contains(reconciled.Unmanaged, name) {
+			unmanaged = append(unmanaged, name)
+		}
+	}
+	if len(unmanaged) > 0 {
+		result["unmanaged"] = unmanaged
+	}
+	if slices.Contains(matched, current) {
+		result["current"] = current
+	}
+	addReconciliation(result, reconciled)
 	return jsonResult(result)
 }
 
-func (r *Registry) handleGetClusterStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Debug("tool called: get_cluster_status")
-	name, err := request.RequireString("name")
+// addReconciliation adds stale records and a pointer to reconcile_clusters to a
+// list_clusters result when the store and Kind disagree.
+func addReconciliation(result map[string]any, reconciled state.Reconciliation) {
+	if len(reconciled.Stale) > 0 {
+		result["stale_records"] = reconciled.Stale
+	}
+	if len(reconciled.Unmanaged) > 0 || len(reconciled.Stale) > 0 {
+		result["note"] = "Some clusters were created or deleted outside this server; call reconcile_clusters " +
+			"to adopt unmanaged clusters (so tags, defaults and recreate_cluster work) or prune stale records."
+	}
+}
+
+// reconciliationLines is the summary form of addReconciliation.
+func reconciliationLines(reconciled state.Reconciliation) []string {
+	if len(reconciled.Stale) == 0 {
+		return nil
+	}
+	return []string{"stale records (call reconcile_clusters): " + strings.Join(reconciled.Stale, ", ")}
+}
+
+func (r *Registry) handleReconcileClusters(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: reconcile_clusters")
+	tags, err := state.ParseTags(request.GetString("tags", ""))
 	if err != nil {
-		return mcp.NewToolResultError("parameter 'name' is required"), nil
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'tags': %v", err)), nil
+	}
+	adopt := request.GetStringSlice("adopt", nil)
+	prune, _ := request.GetArguments()["prune_stale"].(bool)
+
+	mgr := r.kindManager(ctx)
+	clusters, err := mgr.ListClusters(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list clusters: %v", err)), nil
+	}
+	records, err := r.store.List()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read cluster state: %v", err)), nil
+	}
+	reconciled := state.Reconcile(records, clusters)
+
+	if slices.Contains(adopt, "*") {
+		adopt = reconciled.Unmanaged
+	}
+	for _, name := range adopt {
+		if !slices.Contains(reconciled.Unmanaged, name) {
+			return mcp.NewToolResultError(fmt.Sprintf("cluster %q is not an unmanaged cluster; unmanaged: %v",
+				name, reconciled.Unmanaged)), nil
+		}
+	}
+	if prune {
+		for _, name := range reconciled.Stale {
+			if errResult := r.authorizeCluster(ctx, name); errResult != nil {
+				return errResult, nil
+			}
+		}
+	}
+
+	result := map[string]any{"reconciliation": reconciled}
+	adopted := []string{}
+	for _, name := range adopt {
+		rec := state.ClusterRecord{Name: name, Tags: tags, CreatedAt: time.Now().UTC(), Adopted: true,
+			Owner: callerName(ctx)}
+		if summary, err := mgr.SummarizeCluster(ctx, name); err != nil {
+			r.log(ctx).Warn("failed to summarize adopted cluster", "cluster", name, "error", err)
+		} else if summary.CreatedAt != nil {
+			rec.CreatedAt = summary.CreatedAt.UTC()
+		}
+		if err := r.store.Put(rec); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to adopt cluster %q: %v", name, err)), nil
+		}
+		adopted = append(adopted, name)
+	}
+	if len(adopt) > 0 {
+		result["adopted"] = adopted
+	}
+
+	if prune {
+		pruned := []string{}
+		for _, name := range reconciled.Stale {
+			if err := r.store.Delete(name); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to prune record of %q: %v", name, err)), nil
+			}
+			pruned = append(pruned, name)
+		}
+		result["pruned"] = pruned
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleGetClusterStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: get_cluster_status")
+	name, errResult := r.clusterParam(ctx, request, "name")
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	mgr := r.kindManager(ctx)
@@ -117,5 +844,204 @@ func (r *Registry) handleGetClusterStatus(ctx context.Context, request mcp.CallT
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get cluster status: %v", err)), nil
 	}
 
+	if summaryRequested(request) {
+		return linesResult(status.Lines())
+	}
 	return jsonResult(status)
 }
+
+func (r *Registry) handleSetClusterDefaults(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: set_cluster_defaults")
+	name, errResult := r.clusterParam(ctx, request, "name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	clusters, err := r.kindManager(ctx).ListClusters(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list clusters: %v", err)), nil
+	}
+	if !slices.Contains(clusters, name) {
+		return mcp.NewToolResultError(fmt.Sprintf("cluster %q does not exist", name)), nil
+	}
+
+	args := request.GetArguments()
+	var defaults state.ClusterRecord
+	err = r.store.Update(name, func(rec *state.ClusterRecord) {
+		if ns, ok := args["namespace"].(string); ok {
+			rec.Namespace = ns
+		}
+		if contextName, ok := args["context"].(string); ok {
+			rec.Context = contextName
+		}
+		defaults = *rec
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to record cluster defaults: %v", err)), nil
+	}
+
+	return jsonResult(map[string]string{"cluster": name, "namespace": defaults.Namespace, "context": defaults.Context})
+}
+
+func (r *Registry) handleUseCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: use_cluster")
+	name := request.GetString("name", "")
+	clear, _ := request.GetArguments()["clear"].(bool)
+	if clear && name != "" {
+		return mcp.NewToolResultError("pass either 'name' or 'clear', not both"), nil
+	}
+
+	switch {
+	case clear:
+		r.setCurrentCluster(ctx, "", "")
+	case name != "":
+		clusters, err := r.kindManager(ctx).ListClusters(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list clusters: %v", err)), nil
+		}
+		if !slices.Contains(clusters, name) {
+			return mcp.NewToolResultError(fmt.Sprintf("cluster %q does not exist", name)), nil
+		}
+		r.setCurrentCluster(ctx, name, currentFromUse)
+	}
+
+	current, source := r.currentCluster(ctx)
+	if current == "" {
+		return jsonResult(map[string]string{"current_cluster": "",
+			"note": "No current cluster; tools need an explicit cluster name."})
+	}
+	return jsonResult(map[string]string{"current_cluster": current, "source": source})
+}
+
+func (r *Registry) handleGetNodeComponentLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: get_node_component_logs")
+	clusterName, node, errResult := r.requireClusterNode(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+	component, err := request.RequireString("component")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'component' is required"), nil
+	}
+	lines := 0
+	if v, err := request.RequireFloat("lines"); err == nil {
+		lines = int(v)
+	}
+
+	logs, err := r.kindManager(ctx).ComponentLogs(ctx, clusterName, node, component, lines, request.GetString("since", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get component logs: %v", err)), nil
+	}
+	logs.Logs = r.limitOutput(request, "get_node_component_logs "+logs.Node+" "+component, logs.Logs)
+	return jsonResult(logs)
+}
+
+func (r *Registry) handleDebugNode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: debug_node")
+	clusterName, node, errResult := r.requireClusterNode(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	report, err := r.kindManager(ctx).DebugNode(ctx, clusterName, node, request.GetStringSlice("checks", nil))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to debug node: %v", err)), nil
+	}
+	for i, d := range report.Diagnostics {
+		report.Diagnostics[i].Output = r.limitOutput(request, "debug_node "+report.Node+" "+d.Name, d.Output)
+	}
+	return jsonResult(report)
+}
+
+func (r *Registry) handleDiagnoseNetworking(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: diagnose_networking")
+	name, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	opts := kind.DiagnoseOptions{
+		Image:     request.GetString("image", ""),
+		EgressURL: request.GetString("egress_url", ""),
+	}
+	if timeout, err := request.RequireFloat("timeout_seconds"); err == nil && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	mgr := r.kindManager(ctx)
+	diag, err := mgr.DiagnoseNetworking(ctx, name, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to diagnose networking: %v", err)), nil
+	}
+
+	return jsonResult(diag)
+}
+
+// defaultReadyTimeout is how long start_cluster and restore_node wait for nodes when
+// neither the call nor the settings file sets a timeout.
+const defaultReadyTimeout = 120 * time.Second
+
+// readyTimeout returns the request's timeout_seconds, else the configured ready timeout.
+func (r *Registry) readyTimeout(request mcp.CallToolRequest) time.Duration {
+	if val, err := request.RequireFloat("timeout_seconds"); err == nil && val > 0 {
+		return time.Duration(val) * time.Second
+	}
+	return cmp.Or(r.settings.Timeouts.Ready, defaultReadyTimeout)
+}
+
+func (r *Registry) handleStopCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: stop_cluster")
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'name' is required"), nil
+	}
+
+	result, err := r.kindManager(ctx).StopCluster(ctx, name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to stop cluster: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleStartCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: start_cluster")
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'name' is required"), nil
+	}
+	timeout := r.readyTimeout(request)
+
+	result, err := r.kindManager(ctx).StartCluster(ctx, name, timeout)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to start cluster: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handlePauseCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: pause_cluster")
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'name' is required"), nil
+	}
+
+	result, err := r.kindManager(ctx).PauseCluster(ctx, name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to pause cluster: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleUnpauseCluster(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: unpause_cluster")
+	name, err := request.RequireString("name")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'name' is required"), nil
+	}
+
+	result, err := r.kindManager(ctx).UnpauseCluster(ctx, name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to unpause cluster: %v", err)), nil
+	}
+	return jsonResult(result)
+}
@@ -2,28 +2,139 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
-	"github.com/kubevoidcraft/mcp-kind-manager/internal/registry"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/settings"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 func (r *Registry) registerDetectTools(s *server.MCPServer) {
 	detectTool := mcp.NewTool("detect_environment",
+		readOnlyHints,
 		mcp.WithDescription(
 			"Detect the host operating system, container runtime (Docker/Podman), "+
 				"runtime backend (Docker Desktop, Colima, WSL, Podman Machine, native), "+
 				"and provide network configuration advice for exposing applications from Kind clusters."),
+		verbosityParam(),
 	)
 	s.AddTool(detectTool, r.handleDetectEnvironment)
+
+	osTool := mcp.NewTool("detect_os",
+		readOnlyHints,
+		mcp.WithDescription("Detect the host operating system and architecture. Does not query any container runtime."),
+		verbosityParam(),
+	)
+	s.AddTool(osTool, r.handleDetectOS)
+
+	runtimeTool := mcp.NewTool("detect_runtime",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Detect the container runtime (Docker/Podman), its version and socket, and the runtime backend, "+
+				"without computing network advice."),
+		verbosityParam(),
+	)
+	s.AddTool(runtimeTool, r.handleDetectRuntime)
+
+	allRuntimesTool := mcp.NewTool("detect_all_runtimes",
+		readOnlyHints,
+		mcp.WithDescription(
+			"List every installed container runtime (Docker and Podman) with its backend, version, socket and "+
+				"availability, and which one this server uses for clusters. Use it when both are installed to decide "+
+				"which to create clusters with; the choice is made with 'runtime' in the settings file."),
+		verbosityParam(),
+	)
+	s.AddTool(allRuntimesTool, r.handleDetectAllRuntimes)
+
+	networkTool := mcp.NewTool("get_network_advice",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Get advice for exposing applications from Kind clusters on the detected runtime backend, "+
+				"optionally targeted at an intended use."),
+		mcp.WithString("use",
+			mcp.Description("Intended use: 'ingress' (80/443 to an ingress controller), "+
+				"'api-server-lan' (reach the API server from other machines), or 'nodeport' (NodePort services)"),
+			mcp.Enum(kind.NetworkUseIngress, kind.NetworkUseAPIServerLAN, kind.NetworkUseNodePort),
+		),
+		verbosityParam(),
+	)
+	s.AddTool(networkTool, r.handleGetNetworkAdvice)
+
+	exposureTool := mcp.NewTool("plan_network_exposure",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Plan how to expose something from a Kind cluster to an audience on the detected runtime backend. "+
+				"Returns concrete port mappings and listen addresses, the generate_cluster_config arguments that "+
+				"produce them, changes inside the cluster (Service, ingress controller, kubeconfig), host-side steps "+
+				"(firewall rules, port proxies, VM settings) and the addresses clients connect to."),
+		mcp.WithString("target",
+			mcp.Required(),
+			mcp.Description("What to expose: 'http-ingress' (an ingress controller on 80/443), 'tcp-service' or "+
+				"'udp-service' (a NodePort Service), or 'api-server'"),
+			mcp.Enum(kind.ExposeHTTPIngress, kind.ExposeTCPService, kind.ExposeUDPService, kind.ExposeAPIServer),
+		),
+		mcp.WithString("audience",
+			mcp.Description("Who connects: 'localhost' (this machine), 'lan' (other machines), or 'containers' "+
+				"(other containers on this host). Default: localhost."),
+			mcp.Enum(kind.AudienceLocalhost, kind.AudienceLAN, kind.AudienceContainers),
+		),
+		mcp.WithString("cluster_name",
+			mcp.Description("Cluster name, used for node names in the plan"),
+		),
+		mcp.WithNumber("port",
+			mcp.Description("NodePort of a tcp-service or udp-service (default: 30080), or the API server host port "+
+				"(default: 6443)"),
+		),
+		mcp.WithNumber("host_port",
+			mcp.Description("Host port of a service (default: its NodePort), or the HTTP host port of an ingress "+
+				"(default: 80)"),
+		),
+		mcp.WithNumber("https_host_port",
+			mcp.Description("HTTPS host port of an ingress. Default: 443 when host_port is 80, otherwise 8443."),
+		),
+	)
+	s.AddTool(exposureTool, r.handlePlanNetworkExposure)
+
+	portsTool := mcp.NewTool("suggest_port_mappings",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Suggest extraPortMappings for the services to reach from the host, with host ports that no running "+
+				"container publishes (other Kind clusters, local registries) and nothing on this machine binds. "+
+				"Taken ports move to the next free one (http and https to 8080 and 8443), as do privileged ports "+
+				"with rootless Podman. The listen address follows the detected backend. Returns the mappings as "+
+				"the port_mappings JSON to pass to generate_cluster_config."),
+		mcp.WithArray("services",
+			mcp.Description("Services of an ingress controller to map: 'http' (container port 80), 'https' (443)"),
+			mcp.WithStringItems(mcp.Enum(kind.PortServiceHTTP, kind.PortServiceHTTPS)),
+		),
+		mcp.WithString("ports",
+			mcp.Description(`JSON array of custom ports, e.g. [{"container_port":30080},{"container_port":30053,`+
+				`"protocol":"UDP","host_port":5353}]. container_port is the port on the node (usually a NodePort); `+
+				"protocol defaults to TCP and host_port, the preferred host port, to container_port."),
+		),
+		mcp.WithString("audience",
+			mcp.Description("Who connects: 'localhost' binds 127.0.0.1, 'lan' binds 0.0.0.0. "+
+				"Default: the backend's recommended listen address."),
+			mcp.Enum(kind.AudienceLocalhost, kind.AudienceLAN),
+		),
+		verbosityParam(),
+	)
+	s.AddTool(portsTool, r.handleSuggestPortMappings)
 }
 
-func (r *Registry) handleDetectEnvironment(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Debug("tool called: detect_environment")
+func (r *Registry) handleDetectEnvironment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: detect_environment")
 	ri := r.runtimeInfo(ctx)
 	networkAdvice := kind.DetectNetworkConfig(ri)
+	if summaryRequested(request) {
+		return linesResult([]string{osLine(ri.OS), runtimeLine(ri), networkLine(networkAdvice)})
+	}
 
 	result := map[string]any{
 		"os":             ri.OS,
@@ -34,19 +145,183 @@ func (r *Registry) handleDetectEnvironment(ctx context.Context, _ mcp.CallToolRe
 		"available":      ri.Available,
 		"network_advice": networkAdvice,
 	}
+	if ri.Colima != nil {
+		result["colima"] = ri.Colima
+	}
+	if ri.WSL != nil {
+		result["wsl"] = ri.WSL
+	}
+	if ri.Podman != nil {
+		result["podman"] = ri.Podman
+	}
+	if ri.Error != "" {
+		result["error"] = ri.Error
+	}
+
+	return jsonResult(result)
+}
+
+func (r *Registry) handleDetectOS(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: detect_os")
+	info := rtdetect.DetectOS()
+	if summaryRequested(request) {
+		return linesResult([]string{osLine(info)})
+	}
+	return jsonResult(info)
+}
+
+func (r *Registry) handleDetectRuntime(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: detect_runtime")
+	ri := r.runtimeInfo(ctx)
+	if summaryRequested(request) {
+		return linesResult([]string{runtimeLine(ri)})
+	}
+	return jsonResult(ri)
+}
+
+func (r *Registry) handleDetectAllRuntimes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: detect_all_runtimes")
+	ri := r.detector.DetectAll(ctx)
+	available := 0
+	for _, rt := range ri.AllRuntimes {
+		if rt.Available {
+			available++
+		}
+	}
+	var note string
+	if available > 1 {
+		note = fmt.Sprintf("Clusters are created with %s. To use another runtime, set 'runtime' in the settings file (%s) "+
+			"and restart the server.", ri.Runtime, settings.DefaultPath())
+	}
+	if summaryRequested(request) {
+		lines := make([]string, 0, len(ri.AllRuntimes)+1)
+		for _, rt := range ri.AllRuntimes {
+			line := runtimeLine(rt)
+			if rt.Runtime == ri.Runtime && ri.Available {
+				line += " [selected]"
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			lines = append(lines, runtimeLine(ri))
+		}
+		if note != "" {
+			lines = append(lines, note)
+		}
+		return linesResult(lines)
+	}
+
+	result := map[string]any{
+		"selected": ri.Runtime,
+		"runtimes": ri.AllRuntimes,
+	}
 	if ri.Error != "" {
 		result["error"] = ri.Error
 	}
+	if note != "" {
+		result["note"] = note
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleGetNetworkAdvice(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: get_network_advice")
+	ri := r.runtimeInfo(ctx)
 
+	networkAdvice := kind.DetectNetworkConfig(ri)
+	var useAdvice *kind.UseAdvice
+	if use := request.GetString("use", ""); use != "" {
+		var err error
+		if useAdvice, err = kind.AdviseNetworkUse(ri, use); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	if summaryRequested(request) {
+		lines := []string{fmt.Sprintf("backend: %s", ri.Backend), networkLine(networkAdvice)}
+		if useAdvice != nil {
+			for _, pm := range useAdvice.PortMappings {
+				lines = append(lines, portMappingLine(pm))
+			}
+			lines = append(lines, useAdvice.Recommendations...)
+		}
+		return linesResult(lines)
+	}
+	result := map[string]any{
+		"backend":        ri.Backend,
+		"network_advice": networkAdvice,
+	}
+	if useAdvice != nil {
+		result["use_advice"] = useAdvice
+	}
 	return jsonResult(result)
 }
 
+func (r *Registry) handlePlanNetworkExposure(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: plan_network_exposure")
+	target, err := request.RequireString("target")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'target' is required"), nil
+	}
+	req := kind.ExposureRequest{
+		Target:      target,
+		Audience:    request.GetString("audience", ""),
+		ClusterName: request.GetString("cluster_name", ""),
+	}
+	if val, err := request.RequireFloat("port"); err == nil {
+		req.Port = int(val)
+	}
+	if val, err := request.RequireFloat("host_port"); err == nil {
+		req.HostPort = int(val)
+	}
+	if val, err := request.RequireFloat("https_host_port"); err == nil {
+		req.HTTPSHostPort = int(val)
+	}
+
+	plan, err := kind.PlanExposure(r.runtimeInfo(ctx), req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return jsonResult(plan)
+}
+
+func (r *Registry) handleSuggestPortMappings(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: suggest_port_mappings")
+	req := kind.PortSuggestionRequest{
+		Services: request.GetStringSlice("services", nil),
+		Audience: request.GetString("audience", ""),
+	}
+	if raw := request.GetString("ports", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Ports); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'ports' JSON: %v", err)), nil
+		}
+	}
+
+	suggestion, err := r.kindManager(ctx).SuggestPortMappings(ctx, req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if summaryRequested(request) {
+		var lines []string
+		for _, pm := range suggestion.PortMappings {
+			lines = append(lines, portMappingLine(pm))
+		}
+		lines = append(lines, suggestion.Reassigned...)
+		lines = append(lines, "port_mappings: "+suggestion.GenerateParams["port_mappings"].(string))
+		return linesResult(lines)
+	}
+	return jsonResult(suggestion)
+}
+
 func (r *Registry) registerConfigTools(s *server.MCPServer) {
 	configTool := mcp.NewTool("generate_cluster_config",
+		readOnlyHints,
 		mcp.WithDescription(
 			"Generate a Kind cluster configuration YAML. Returns the YAML for review before creation. "+
 				"Supports multi-node clusters, port mappings, credential mounting, registry mirror overrides, "+
-				"custom networking, and containerd configuration."),
+				"custom networking, and containerd configuration. The parsed config is also returned as "+
+				"structured content (Kind field names) so fields can be edited before 'create_cluster', "+
+				"which accepts it as JSON too."),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("Name of the Kind cluster"),
@@ -58,11 +333,24 @@ func (r *Registry) registerConfigTools(s *server.MCPServer) {
 			mcp.Description("Number of control plane nodes (default: 1; >1 for HA)"),
 		),
 		mcp.WithString("kubernetes_version",
-			mcp.Description("Kubernetes version for kindest/node image (e.g., '1.31.0'). Leave empty for Kind default."),
+			mcp.Description("Kubernetes version for kindest/node image (e.g., '1.31.0'). Leave empty for the "+
+				"server's configured default, or the Kind default."),
+		),
+		mcp.WithString("control_plane_image",
+			mcp.Description("Node image or bare version (e.g. '1.31.0') for control planes only, overriding "+
+				"kubernetes_version. With worker_image, tests version skew; the Kubernetes skew policy is enforced."),
+		),
+		mcp.WithString("worker_image",
+			mcp.Description("Node image or bare version (e.g. '1.30.4') for workers only, overriding "+
+				"kubernetes_version. Kubelets may trail the API server by up to three minors but never lead it."),
 		),
 		mcp.WithBoolean("mount_credentials",
 			mcp.Description("Auto-detect and mount registry credentials to cluster nodes"),
 		),
+		mcp.WithBoolean("merge_credentials",
+			mcp.Description("With mount_credentials, mount one config merged from every Docker and Podman "+
+				"credential file instead of the first one found. Default: false."),
+		),
 		mcp.WithString("pod_subnet",
 			mcp.Description("Custom pod subnet CIDR (e.g., '10.244.0.0/16')"),
 		),
@@ -81,22 +369,174 @@ func (r *Registry) registerConfigTools(s *server.MCPServer) {
 		mcp.WithNumber("api_server_port",
 			mcp.Description("Pin the API server to a specific host port (e.g., 6443). Default: random."),
 		),
+		mcp.WithString("node_port_range",
+			mcp.Description("kube-apiserver --service-node-port-range (e.g. '30000-40000'). Default: 30000-32767."),
+		),
+		mcp.WithBoolean("enable_ingress_ports",
+			mcp.Description("Map host ports 80 and 443 to the first control plane and label it ingress-ready=true "+
+				"(kubeadm patch), as the Kind ingress controller manifests expect. Default: false."),
+		),
+		mcp.WithString("ingress_listen_address",
+			mcp.Description("Host address the enable_ingress_ports mappings listen on, e.g. '0.0.0.0' for LAN access. "+
+				"Default: the backend's recommended listen address."),
+		),
+		mcp.WithArray("api_server_cert_sans",
+			mcp.WithStringItems(),
+			mcp.Description("Extra hostnames or IPs for the API server certificate, for reaching the cluster from "+
+				"other machines or through a reverse proxy (e.g. ['kind.lan', '192.168.1.20'])"),
+		),
+		mcp.WithString("node_image_repository",
+			mcp.Description("Pull node images from this repository instead of kindest/node "+
+				"(e.g. 'registry.corp/kind/node'). Defaults to the server's MCP_KIND_NODE_IMAGE_REPOSITORY setting."),
+		),
+		mcp.WithString("port_mappings",
+			mcp.Description(
+				"JSON array of port mappings. Each object has 'host_port', 'container_port', optional "+
+					"'listen_address' and 'protocol', and an optional 'target' {\"role\": \"control-plane\"|\"worker\", "+
+					"\"index\": n} (0-based within the role). Mappings without a target go on the first control plane. "+
+					"Example: [{\"host_port\":8080,\"container_port\":30080,\"target\":{\"role\":\"worker\",\"index\":0}}]. "+
+					"suggest_port_mappings returns mappings with free host ports in this form."),
+		),
+		mcp.WithString("extra_mounts",
+			mcp.Description(
+				"JSON array of host mounts, e.g. a source directory for hostPath volumes. Each object has an absolute "+
+					"'host_path' that must exist on this host, an absolute 'container_path' in the node, optional "+
+					"'read_only' and 'propagation' (None, HostToContainer or Bidirectional), and an optional 'target' "+
+					"like port_mappings. Mounts without a target go on every node. Host paths the runtime's VM does not "+
+					"share are reported as warnings. "+
+					"Example: [{\"host_path\":\"~/src/app\",\"container_path\":\"/src\",\"read_only\":true}]"),
+		),
+		mcp.WithBoolean("host_storage",
+			mcp.Description("Mount a managed host directory for the cluster's volume data into every node at "+
+				kind.HostStorageNodePath+"; 'create_cluster' creates it, then call 'provision_host_storage'. The directory "+
+				"is removed by 'delete_cluster'. Default: false."),
+		),
+		mcp.WithString("taints",
+			mcp.Description(
+				"JSON array of node taints registered at creation. Each object has 'key', optional 'value', "+
+					"'effect' (NoSchedule, PreferNoSchedule or NoExecute) and an optional 'target' like port_mappings; "+
+					"taints without a target go on every node. Tainted control planes keep kubeadm's control-plane taint. "+
+					"Example: [{\"key\":\"dedicated\",\"value\":\"gpu\",\"effect\":\"NoSchedule\",\"target\":{\"role\":\"worker\",\"index\":0}}]"),
+		),
+		mcp.WithString("tuning",
+			mcp.Description(
+				"JSON object of kubelet, API server and scheduler settings, turned into validated kubeadm patches: "+
+					"'max_pods', 'eviction_hard' (e.g. {\"memory.available\":\"200Mi\"}), 'audit_log' {'level': "+
+					"None|Metadata|Request|RequestResponse, 'max_age_days', 'max_backups', 'max_size_mb'}, "+
+					"'admission_plugins', 'disabled_admission_plugins', and 'scheduler_profiles' [{'name', "+
+					"'scoring_strategy': LeastAllocated|MostAllocated, 'disabled_plugins'}]. The audit policy and "+
+					"scheduler config are written next to the cluster's stored config and mounted into the control planes."),
+		),
+		mcp.WithString("overrides",
+			mcp.Description(
+				"Raw Kind config YAML or JSON merged onto the generated config, for fields this tool does not model "+
+					"(e.g. featureGates, runtimeConfig, networking.dnsSearch). Mappings merge key by key and null removes "+
+					"a key; nodes merge by position (extra nodes are appended); other lists and scalars replace."),
+		),
+		mcp.WithBoolean("fit_to_host",
+			mcp.Description("Cap workers at what the container engine's CPUs and memory can run, and on constrained "+
+				"engines generate a single node with kubelet resource reservations instead. Default: false (only warn)."),
+		),
+		mcp.WithBoolean("reserve_resources",
+			mcp.Description("Reserve CPU and memory for system daemons and the kubelet on every node (KubeletConfiguration "+
+				"patch). Default: on when fit_to_host shrinks a cluster for a constrained engine."),
+		),
+		mcp.WithString("output_format",
+			mcp.Description("Format of the text output: 'yaml' (default), 'json', or 'both'. "+
+				"Structured content is returned in every format."),
+			mcp.Enum("yaml", "json", "both"),
+		),
 	)
 	s.AddTool(configTool, r.handleGenerateClusterConfig)
+
+	sizeTool := mcp.NewTool("recommend_cluster_size",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Recommend how many Kind nodes the container engine can run, from the CPUs and memory it reports "+
+				"(the VM's for Docker Desktop, Colima or Podman machines) or else this host's. Warns when the "+
+				"requested workers exceed what fits and suggests a single node with kubelet resource reservations "+
+				"on constrained laptops. generate_cluster_config applies the same advice."),
+		mcp.WithNumber("workers",
+			mcp.Description("Requested worker nodes (default: 0)"),
+		),
+		mcp.WithNumber("control_planes",
+			mcp.Description("Requested control plane nodes (default: 1)"),
+		),
+	)
+	s.AddTool(sizeTool, r.handleRecommendClusterSize)
+
+	validateTool := mcp.NewTool("validate_cluster_config",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Validate a Kind cluster config (YAML or JSON) without creating anything. Returns every finding, "+
+				"errors and warnings, with the field it applies to, so hand-written configs can be checked before "+
+				"'create_cluster'. Warnings take the detected runtime backend into account."),
+		mcp.WithString("config_yaml",
+			mcp.Required(),
+			mcp.Description("The Kind cluster configuration to validate"),
+		),
+	)
+	s.AddTool(validateTool, r.handleValidateClusterConfig)
+}
+
+// tuningDir is where a cluster's tuning files are written: beside its stored config
+// when a config directory is set, else in the user cache directory.
+func (r *Registry) tuningDir(cluster string) (string, error) {
+	if r.configDir != "" {
+		return filepath.Join(kind.ExpandHome(r.configDir), cluster), nil
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "mcp-kind-manager", cluster), nil
+}
+
+// hostStorageDir is the managed host directory holding a cluster's volume data.
+func (r *Registry) hostStorageDir(cluster string) string {
+	return filepath.Join(kind.ExpandHome(r.storageDir), cluster)
+}
+
+func (r *Registry) handleRecommendClusterSize(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: recommend_cluster_size")
+	controlPlanes, workers := 1, 0
+	if v, err := request.RequireFloat("workers"); err == nil && v > 0 {
+		workers = int(v)
+	}
+	if v, err := request.RequireFloat("control_planes"); err == nil && v > 0 {
+		controlPlanes = int(v)
+	}
+	return jsonResult(kind.RecommendClusterSize(r.runtimeInfo(ctx), controlPlanes, workers))
+}
+
+func (r *Registry) handleValidateClusterConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: validate_cluster_config")
+	configYAML, err := request.RequireString("config_yaml")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'config_yaml' is required"), nil
+	}
+
+	return jsonResult(kind.CheckConfig(configYAML, r.runtimeInfo(ctx)))
 }
 
 func (r *Registry) handleGenerateClusterConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Info("tool called: generate_cluster_config")
+	r.log(ctx).Info("tool called: generate_cluster_config")
 	name, err := request.RequireString("name")
 	if err != nil {
 		return mcp.NewToolResultError("parameter 'name' is required"), nil
 	}
 
+	format := request.GetString("output_format", "yaml")
+	if format != "yaml" && format != "json" && format != "both" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid output_format %q; must be 'yaml', 'json', or 'both'", format)), nil
+	}
+
 	ri := r.runtimeInfo(ctx)
 
 	opts := kind.ConfigOptions{
-		ClusterName:      name,
-		NumControlPlanes: 1,
+		ClusterName:         name,
+		NumControlPlanes:    1,
+		NodeImageRepository: request.GetString("node_image_repository", r.nodeImageRepo),
 	}
 
 	if workers, err := request.RequireFloat("workers"); err == nil {
@@ -105,9 +545,15 @@ func (r *Registry) handleGenerateClusterConfig(ctx context.Context, request mcp.
 	if cp, err := request.RequireFloat("control_planes"); err == nil && int(cp) > 0 {
 		opts.NumControlPlanes = int(cp)
 	}
-	if version, err := request.RequireString("kubernetes_version"); err == nil {
-		opts.KubernetesVersion = version
+	sizing := kind.RecommendClusterSize(ri, opts.NumControlPlanes, opts.NumWorkers)
+	if fit, _ := request.GetArguments()["fit_to_host"].(bool); fit {
+		opts.NumControlPlanes, opts.NumWorkers = sizing.RecommendedControlPlanes, sizing.RecommendedWorkers
+		opts.ReserveKubeletResources = sizing.Constrained
+	}
+	if val, ok := request.GetArguments()["reserve_resources"].(bool); ok {
+		opts.ReserveKubeletResources = val
 	}
+	opts.KubernetesVersion = request.GetString("kubernetes_version", r.settings.KubernetesVersion)
 	if subnet, err := request.RequireString("pod_subnet"); err == nil {
 		opts.PodSubnet = subnet
 	}
@@ -123,15 +569,74 @@ func (r *Registry) handleGenerateClusterConfig(ctx context.Context, request mcp.
 	if port, err := request.RequireFloat("api_server_port"); err == nil && int(port) > 0 {
 		opts.APIServerPort = int(port)
 	}
+	opts.ControlPlaneImage = request.GetString("control_plane_image", "")
+	opts.WorkerImage = request.GetString("worker_image", "")
+	opts.NodePortRange = request.GetString("node_port_range", "")
+	opts.APIServerCertSANs = request.GetStringSlice("api_server_cert_sans", nil)
 	if val, ok := request.GetArguments()["disable_default_cni"].(bool); ok {
 		opts.DisableDefaultCNI = val
 	}
+	if val, ok := request.GetArguments()["enable_ingress_ports"].(bool); ok && val {
+		opts.EnableIngressPorts = true
+		opts.IngressListenAddress = request.GetString("ingress_listen_address", "")
+		if opts.IngressListenAddress == "" {
+			opts.IngressListenAddress = kind.DetectNetworkConfig(r.runtimeInfo(ctx)).ListenAddress
+		}
+	}
+
+	if raw := request.GetString("port_mappings", ""); raw != "" {
+		var mappings []kind.TargetedPortMapping
+		if err := json.Unmarshal([]byte(raw), &mappings); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'port_mappings' JSON: %v", err)), nil
+		}
+		for _, pm := range mappings {
+			if pm.Target == (kind.NodeTarget{}) {
+				opts.PortMappings = append(opts.PortMappings, pm.PortMapping)
+			} else {
+				opts.NodePortMappings = append(opts.NodePortMappings, pm)
+			}
+		}
+	}
+	if raw := request.GetString("extra_mounts", ""); raw != "" {
+		var mounts []kind.TargetedMount
+		if err := json.Unmarshal([]byte(raw), &mounts); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'extra_mounts' JSON: %v", err)), nil
+		}
+		for _, m := range mounts {
+			if err := kind.ValidateMounts([]kind.Mount{m.Mount}); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid 'extra_mounts': %v", err)), nil
+			}
+		}
+		opts.NodeMounts = append(opts.NodeMounts, mounts...)
+	}
+
+	if raw := request.GetString("taints", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Taints); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'taints' JSON: %v", err)), nil
+		}
+	}
+	if raw := request.GetString("tuning", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Tuning); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'tuning' JSON: %v", err)), nil
+		}
+		if opts.Tuning.NeedsFiles() {
+			dir, err := r.tuningDir(name)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to pick a directory for tuning files: %v", err)), nil
+			}
+			if err := kind.WriteTuningFiles(dir, opts.Tuning); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to write tuning files: %v", err)), nil
+			}
+			opts.TuningDir = dir
+		}
+	}
 
 	// Mount credentials if requested
 	if val, ok := request.GetArguments()["mount_credentials"].(bool); ok && val {
-		credInfo, err := registry.FindCredentials(ri)
+		merge, _ := request.GetArguments()["merge_credentials"].(bool)
+		credInfo, err := r.mountableCredentials(ctx, merge)
 		if err != nil {
-			r.logger.Warn("credential discovery failed", "error", err)
+			r.log(ctx).Warn("credential discovery failed", "error", err)
 		} else {
 			opts.ExtraMounts = append(opts.ExtraMounts, kind.Mount{
 				HostPath:      credInfo.FilePath,
@@ -141,14 +646,64 @@ func (r *Registry) handleGenerateClusterConfig(ctx context.Context, request mcp.
 		}
 	}
 
+	if val, ok := request.GetArguments()["host_storage"].(bool); ok && val {
+		opts.ExtraMounts = append(opts.ExtraMounts, kind.HostStorageMount(r.hostStorageDir(name)))
+	}
+
 	configYAML, err := kind.GenerateConfig(opts)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to generate config: %v", err)), nil
 	}
+	if overrides := request.GetString("overrides", ""); overrides != "" {
+		if configYAML, err = kind.MergeConfigOverrides(configYAML, overrides); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to apply 'overrides': %v", err)), nil
+		}
+		if err := kind.ValidateConfig(configYAML); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("config with overrides is invalid: %v", err)), nil
+		}
+	}
 
-	output := fmt.Sprintf("Generated Kind cluster config for %q:\n\n```yaml\n%s```\n\n"+
-		"Review the configuration above, then use the 'create_cluster' tool with this YAML to create the cluster.",
-		name, configYAML)
+	configObj, err := kind.ConfigObject(configYAML)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse generated config: %v", err)), nil
+	}
+	configJSON, err := json.MarshalIndent(configObj, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to encode config: %v", err)), nil
+	}
 
-	return mcp.NewToolResultText(output), nil
+	output := fmt.Sprintf("Generated Kind cluster config for %q:\n\n", name)
+	if format != "json" {
+		output += fmt.Sprintf("```yaml\n%s```\n\n", configYAML)
+	}
+	if format != "yaml" {
+		output += fmt.Sprintf("```json\n%s\n```\n\n", configJSON)
+	}
+	output += "Review the configuration above, then use the 'create_cluster' tool with this config to create the cluster."
+
+	mounts := append([]kind.Mount{}, opts.ExtraMounts...)
+	for _, m := range opts.NodeMounts {
+		mounts = append(mounts, m.Mount)
+	}
+	warnings := kind.CheckHostPaths(ri, mounts)
+	warnings = append(warnings, sizing.Warnings...)
+	if opts.KubernetesVersion != "" {
+		if check := r.kindManager(ctx).CheckKindCompatibility(ctx, configYAML); check.Status != kind.CheckPass {
+			warnings = append(warnings, check.Message)
+		}
+	}
+	if len(warnings) > 0 {
+		output += "\n\nWarnings:\n- " + strings.Join(warnings, "\n- ")
+	}
+
+	structured := map[string]any{
+		"name":        name,
+		"config":      configObj,
+		"config_yaml": configYAML,
+		"sizing":      sizing,
+	}
+	if len(warnings) > 0 {
+		structured["warnings"] = warnings
+	}
+	return mcp.NewToolResultStructured(structured, output), nil
 }
@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// healthURIPrefix is the URI scheme of cluster health resources.
+const healthURIPrefix = "kind-health://"
+
+// Bounds of the watch_cluster_health polling interval.
+const (
+	defaultHealthInterval = 30 * time.Second
+	minHealthInterval     = 5 * time.Second
+)
+
+// healthWatch is a background watch_cluster_health poller.
+type healthWatch struct {
+	cancel context.CancelFunc
+}
+
+func (r *Registry) registerHealthTools(s *server.MCPServer) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(healthURIPrefix+"{cluster}", "Cluster health",
+			mcp.WithTemplateDescription("Current health of a Kind cluster: node container states, API server "+
+				"readiness, and the problems found. 'watch_cluster_health' sends "+
+				"notifications/resources/updated for this URI when it changes."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		r.handleReadHealth,
+	)
+
+	watchTool := mcp.NewTool("watch_cluster_health",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Watch a Kind cluster's health in the background and push notifications to this client when it "+
+				"changes (e.g. nodes stopped after the host slept, API server not ready, cluster deleted): "+
+				"notifications/resources/updated for "+healthURIPrefix+"<cluster> and a notifications/message log "+
+				"entry with the new health. Returns the current health. The watch ends with the client session, "+
+				"when the cluster is deleted, or with stop=true."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to watch"),
+		mcp.WithNumber("interval_seconds",
+			mcp.Description(fmt.Sprintf("Seconds between checks (minimum %d). Default: %d.",
+				int(minHealthInterval.Seconds()), int(defaultHealthInterval.Seconds()))),
+		),
+		mcp.WithBoolean("stop",
+			mcp.Description("Stop watching the cluster instead. Default: false."),
+		),
+	)
+	s.AddTool(watchTool, r.handleWatchClusterHealth)
+}
+
+func (r *Registry) handleReadHealth(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	r.log(ctx).Debug("resource read", "uri", request.Params.URI)
+	name := strings.TrimPrefix(request.Params.URI, healthURIPrefix)
+	if name == "" || name == request.Params.URI {
+		return nil, fmt.Errorf("invalid cluster health URI %s", request.Params.URI)
+	}
+	health, err := r.kindManager(ctx).CheckHealth(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(health, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      request.Params.URI,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
+
+func (r *Registry) handleWatchClusterHealth(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: watch_cluster_health")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	session := server.ClientSessionFromContext(ctx)
+	srv := server.ServerFromContext(ctx)
+	if session == nil || srv == nil {
+		return mcp.NewToolResultError("watching requires a client session to notify"), nil
+	}
+	key := session.SessionID() + "/" + clusterName
+
+	if stop, _ := request.GetArguments()["stop"].(bool); stop {
+		if !r.stopHealthWatch(key) {
+			return mcp.NewToolResultError(fmt.Sprintf("cluster %q is not being watched", clusterName)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Stopped watching cluster %q.", clusterName)), nil
+	}
+
+	interval := defaultHealthInterval
+	if v, err := request.RequireFloat("interval_seconds"); err == nil {
+		interval = time.Duration(v * float64(time.Second))
+	}
+	if interval < minHealthInterval {
+		return mcp.NewToolResultError(fmt.Sprintf("'interval_seconds' must be at least %d", int(minHealthInterval.Seconds()))), nil
+	}
+
+	health, err := r.kindManager(ctx).CheckHealth(ctx, clusterName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to check cluster health: %v", err)), nil
+	}
+	if health.State == kind.HealthMissing {
+		return mcp.NewToolResultError(fmt.Sprintf("cluster %q not found", clusterName)), nil
+	}
+	r.startHealthWatch(srv, session.SessionID(), key, clusterName, interval, health)
+
+	return jsonResult(map[string]any{
+		"watching":         true,
+		"uri":              healthURIPrefix + clusterName,
+		"interval_seconds": interval.Seconds(),
+		"health":           health,
+	})
+}
+
+// startHealthWatch polls a cluster's health every interval and notifies the session
+// whenever it differs from the last result, replacing any watch with the same key.
+func (r *Registry) startHealthWatch(srv *server.MCPServer, sessionID, key, clusterName string, interval time.Duration, last *kind.ClusterHealth) {
+	r.stopHealthWatch(key)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &healthWatch{cancel: cancel}
+	r.watchMu.Lock()
+	r.healthWatches[key] = w
+	r.watchMu.Unlock()
+
+	logger := r.logger.With("cluster", clusterName, "session", sessionID)
+	go func() {
+		defer func() {
+			r.watchMu.Lock()
+			if r.healthWatches[key] == w {
+				delete(r.healthWatches, key)
+			}
+			r.watchMu.Unlock()
+			cancel()
+		}()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			health, err := r.kindManager(ctx).CheckHealth(ctx, clusterName)
+			if err != nil {
+				logger.Warn("cluster health check failed", "error", err)
+				continue
+			}
+			if health.SameAs(last) {
+				continue
+			}
+			logger.Info("cluster health changed", "from", last.State, "to", health.State)
+			if err := notifyHealth(srv, sessionID, health); err != nil {
+				logger.Debug("sending health notification failed, stopping watch", "error", err)
+				return
+			}
+			last = health
+			if health.State == kind.HealthMissing {
+				return
+			}
+		}
+	}()
+}
+
+// stopHealthWatch cancels the watch with key and reports whether there was one.
+func (r *Registry) stopHealthWatch(key string) bool {
+	r.watchMu.Lock()
+	defer r.watchMu.Unlock()
+	w, ok := r.healthWatches[key]
+	if ok {
+		w.cancel()
+		delete(r.healthWatches, key)
+	}
+	return ok
+}
+
+// stopSessionHealthWatches cancels every watch of a client session.
+func (r *Registry) stopSessionHealthWatches(sessionID string) {
+	r.watchMu.Lock()
+	defer r.watchMu.Unlock()
+	for key, w := range r.healthWatches {
+		if strings.HasPrefix(key, sessionID+"/") {
+			w.cancel()
+			delete(r.healthWatches, key)
+		}
+	}
+}
+
+// notifyHealth tells a session that a cluster's health resource changed, and logs the
+// new health to it for clients that show log messages rather than re-reading resources.
+func notifyHealth(srv *server.MCPServer, sessionID string, health *kind.ClusterHealth) error {
+	uri := healthURIPrefix + health.Cluster
+	if err := srv.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated,
+		map[string]any{"uri": uri}); err != nil {
+		return err
+	}
+	level := mcp.LoggingLevelWarning
+	if health.State == kind.HealthHealthy {
+		level = mcp.LoggingLevelInfo
+	}
+	return srv.SendNotificationToSpecificClient(sessionID, "notifications/message", map[string]any{
+		"level":  level,
+		"logger": "cluster-health",
+		"data":   health,
+	})
+}
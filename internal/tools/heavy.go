@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultHeavyOps is how many heavy operations (cluster creation, image saving and
+// loading, workload export and import) run at once unless MCP_KIND_MAX_HEAVY_OPS is set.
+const defaultHeavyOps = 2
+
+// heavyOpsLimit returns the heavy operation limit from MCP_KIND_MAX_HEAVY_OPS, else the
+// configured one, else defaultHeavyOps; 0 turns limiting off.
+func heavyOpsLimit(logger *slog.Logger, configured *int) int {
+	env := os.Getenv("MCP_KIND_MAX_HEAVY_OPS")
+	if env == "" {
+		if configured != nil {
+			return *configured
+		}
+		return defaultHeavyOps
+	}
+	n, err := strconv.Atoi(env)
+	if err != nil || n < 0 {
+		logger.Warn("ignoring invalid MCP_KIND_MAX_HEAVY_OPS", "value", env, "default", defaultHeavyOps)
+		return defaultHeavyOps
+	}
+	return n
+}
+
+// waitHeavy waits until the call may run a heavy operation, reporting its queue position
+// through progress (see progressReporter) while it waits. On success the caller must
+// call the returned release func when the operation ends; otherwise it gets the error
+// result to return.
+func (r *Registry) waitHeavy(ctx context.Context, progress func(string)) (func(), *mcp.CallToolResult) {
+	queued := false // onMove runs on this goroutine
+	release, err := r.heavy.Acquire(ctx, func(position int) {
+		queued = true
+		r.log(ctx).Debug("waiting for a heavy operation slot", "position", position)
+		if progress != nil {
+			progress(fmt.Sprintf("queued at position %d: %d heavy operation(s) run at once (MCP_KIND_MAX_HEAVY_OPS)",
+				position, r.heavy.Limit()))
+		}
+	})
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("cancelled while queued behind other heavy operations: %v", err))
+	}
+	if queued {
+		r.log(ctx).Info("heavy operation slot acquired after queueing")
+		if progress != nil {
+			progress("starting")
+		}
+	}
+	return release, nil
+}
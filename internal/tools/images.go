@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerImageTools(s *server.MCPServer) {
+	saveTool := mcp.NewTool("save_images",
+		updateHints,
+		mcp.WithDescription(
+			"Save container images (e.g. kindest/node and workload images) from the local runtime "+
+				"into a tarball, for moving them to an offline/air-gapped machine."),
+		mcp.WithArray("images",
+			mcp.Required(),
+			mcp.WithStringItems(),
+			mcp.Description("Image references to save (e.g. ['kindest/node:v1.31.0', 'nginx:1.27'])"),
+		),
+		mcp.WithString("output_path",
+			mcp.Required(),
+			mcp.Description("Path of the tarball to write (e.g. '~/kind-images.tar')"),
+		),
+	)
+	s.AddTool(saveTool, r.handleSaveImages)
+
+	loadTool := mcp.NewTool("load_images",
+		updateHints,
+		mcp.WithDescription(
+			"Load container images from a tarball (created by 'save_images') into the local runtime, "+
+				"and optionally onto the nodes of a Kind cluster."),
+		mcp.WithString("archive_path",
+			mcp.Required(),
+			mcp.Description("Path of the image tarball to load"),
+		),
+		mcp.WithString("cluster_name",
+			mcp.Description("Also load the images onto the nodes of this Kind cluster"),
+		),
+	)
+	s.AddTool(loadTool, r.handleLoadImages)
+
+	loadImageTool := mcp.NewTool("load_image",
+		updateHints,
+		mcp.WithDescription(
+			"Load an image onto the nodes of a Kind cluster for the nodes' platform. A multi-arch tag is "+
+				"resolved to the digest for the node architecture (or the given platform) and pulled before "+
+				"loading, instead of whatever variant the host holds; fails listing the available platforms "+
+				"when the image has none compatible."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to load the image into"),
+		mcp.WithString("image",
+			mcp.Required(),
+			mcp.Description("Image reference to load (e.g. 'nginx:1.27')"),
+		),
+		mcp.WithString("platform",
+			mcp.Description("Platform to load, as os/arch[/variant] (e.g. 'linux/arm64'). Default: the node architecture."),
+		),
+		mcp.WithBoolean("pull",
+			mcp.Description("Pull the image even if a compatible one exists locally. Default: false."),
+		),
+	)
+	s.AddTool(loadImageTool, r.handleLoadImage)
+
+	listNodeTool := mcp.NewTool("list_node_images_in_cluster",
+		readOnlyHints,
+		mcp.WithDescription(
+			"List the images cached in the containerd store of every node of a Kind cluster (crictl images), "+
+				"deduplicated across nodes and largest first, with the nodes holding each, whether any container "+
+				"uses it, and how much disk all node copies and the unused ones take."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		verbosityParam(),
+	)
+	s.AddTool(listNodeTool, r.handleListNodeImages)
+
+	pruneNodeTool := mcp.NewTool("prune_node_images",
+		destructiveHints,
+		mcp.WithDescription(
+			"Remove images from the containerd store of every node of a Kind cluster to free disk space "+
+				"without recreating it: the given images, or every image no container uses. Pinned images "+
+				"(e.g. pause) are kept. Removed images are pulled again when a pod needs them; images that were "+
+				"only loaded with kind load must be loaded again."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithArray("images",
+			mcp.WithStringItems(),
+			mcp.Description("Image references or IDs to remove. Default: all images no container uses."),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Only report what would be removed. Default: false."),
+		),
+	)
+	s.AddTool(pruneNodeTool, r.handlePruneNodeImages)
+
+	diskTool := mcp.NewTool("disk_usage",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Report the disk space Kind uses on this host: kindest/node images, the writable layer and volumes "+
+				"of every node container, and /var (containerd image store, logs) inside running nodes, with "+
+				"suggestions for reclaiming space."),
+		verbosityParam(),
+	)
+	s.AddTool(diskTool, r.handleDiskUsage)
+
+	buildTool := mcp.NewTool("build_and_load",
+		destructiveHints,
+		mcp.WithDescription(
+			"Build an image with docker/podman build, load it onto the nodes of a Kind cluster, and optionally "+
+				"restart the Deployments that use the tag — the inner development loop for local Kubernetes. "+
+				"Use a specific tag (not latest) so pods use the loaded image instead of pulling."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to load the image into"),
+		mcp.WithString("context_path",
+			mcp.Required(),
+			mcp.Description("Build context directory (e.g. '~/src/shop')"),
+		),
+		mcp.WithString("tag",
+			mcp.Required(),
+			mcp.Description("Image tag to build and load (e.g. 'shop/web:dev')"),
+		),
+		mcp.WithString("dockerfile",
+			mcp.Description("Path of the Dockerfile. Default: Dockerfile in the build context."),
+		),
+		mcp.WithString("build_args",
+			mcp.Description("JSON object of build arguments (e.g. {\"VERSION\":\"1.2.3\"})"),
+		),
+		mcp.WithBoolean("restart",
+			mcp.Description("Restart Deployments whose containers use the tag after loading. Default: false."),
+		),
+	)
+	s.AddTool(buildTool, r.handleBuildAndLoad)
+}
+
+func (r *Registry) handleSaveImages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: save_images")
+	images, err := request.RequireStringSlice("images")
+	if err != nil || len(images) == 0 {
+		return mcp.NewToolResultError("parameter 'images' is required"), nil
+	}
+	outputPath, err := request.RequireString("output_path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'output_path' is required"), nil
+	}
+
+	release, errResult := r.waitHeavy(ctx, r.progressReporter(ctx, request))
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr := r.kindManager(ctx)
+	if _, err := mgr.SaveImages(ctx, images, outputPath); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to save images: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Saved %d image(s) to %s.", len(images), outputPath)), nil
+}
+
+func (r *Registry) handleLoadImages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: load_images")
+	archivePath, err := request.RequireString("archive_path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'archive_path' is required"), nil
+	}
+	clusterName, _ := request.RequireString("cluster_name")
+
+	release, errResult := r.waitHeavy(ctx, r.progressReporter(ctx, request))
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr := r.kindManager(ctx)
+	output, err := mgr.LoadImages(ctx, archivePath, clusterName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to load images: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Images loaded from %s.\n\n%s", archivePath, output)), nil
+}
+
+func (r *Registry) handleLoadImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: load_image")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	image, err := request.RequireString("image")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'image' is required"), nil
+	}
+
+	opts := kind.LoadImageOptions{Image: image, Platform: request.GetString("platform", "")}
+	if val, ok := request.GetArguments()["pull"].(bool); ok {
+		opts.Pull = val
+	}
+
+	release, errResult := r.waitHeavy(ctx, r.progressReporter(ctx, request))
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	result, err := r.kindManager(ctx).LoadImageForPlatform(ctx, clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to load image: %v", err)), nil
+	}
+
+	return jsonResult(result)
+}
+
+func (r *Registry) handleListNodeImages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: list_node_images_in_cluster")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	report, err := r.kindManager(ctx).ListNodeImages(ctx, clusterName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list node images: %v", err)), nil
+	}
+
+	if summaryRequested(request) {
+		return linesResult(report.Lines())
+	}
+	return jsonResult(report)
+}
+
+func (r *Registry) handlePruneNodeImages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: prune_node_images")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	opts := kind.PruneNodeImagesOptions{Images: request.GetStringSlice("images", nil)}
+	if val, ok := request.GetArguments()["dry_run"].(bool); ok {
+		opts.DryRun = val
+	}
+
+	result, err := r.kindManager(ctx).PruneNodeImages(ctx, clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to prune node images: %v", err)), nil
+	}
+
+	return jsonResult(result)
+}
+
+func (r *Registry) handleDiskUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: disk_usage")
+	report, err := r.kindManager(ctx).DiskUsage(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to report disk usage: %v", err)), nil
+	}
+
+	if summaryRequested(request) {
+		return linesResult(report.Lines())
+	}
+	return jsonResult(report)
+}
+
+func (r *Registry) handleBuildAndLoad(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: build_and_load")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	contextPath, err := request.RequireString("context_path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'context_path' is required"), nil
+	}
+	tag, err := request.RequireString("tag")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'tag' is required"), nil
+	}
+
+	opts := kind.BuildOptions{
+		ContextDir: contextPath,
+		Dockerfile: request.GetString("dockerfile", ""),
+		Tag:        tag,
+	}
+	if raw := request.GetString("build_args", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.BuildArgs); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'build_args' JSON: %v", err)), nil
+		}
+	}
+	if val, ok := request.GetArguments()["restart"].(bool); ok {
+		opts.Restart = val
+	}
+
+	progress := r.progressReporter(ctx, request)
+	release, errResult := r.waitHeavy(ctx, progress)
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr := r.kindManager(ctx)
+	mgr.SetProgress(progress)
+	result, err := mgr.BuildAndLoad(ctx, clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("build and load failed: %v", err)), nil
+	}
+
+	return jsonResult(result)
+}
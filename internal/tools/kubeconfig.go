@@ -2,45 +2,312 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 func (r *Registry) registerKubeconfigTools(s *server.MCPServer) {
 	tool := mcp.NewTool("get_kubeconfig",
+		updateHints,
 		mcp.WithDescription(
 			"Get the kubeconfig for a Kind cluster. "+
-				"Returns the kubeconfig YAML that can be used with kubectl."),
-		mcp.WithString("name",
-			mcp.Required(),
-			mcp.Description("Name of the Kind cluster"),
-		),
+				"Returns the kubeconfig YAML that can be used with kubectl, or writes it to a file "+
+				"and returns only the path when 'to_file' or 'kubeconfig_path' is set. Set 'rewrite_for' when kubectl runs "+
+				"in a container or devcontainer rather than on the host: the server is rewritten for that network and the "+
+				"result reports which network this server detected it is on. The context, cluster and user entries "+
+				"can be renamed from kind-<name> (e.g. to a team naming convention) and a default namespace set."),
+		clusterNameParam("name", "Name of the Kind cluster"),
 		mcp.WithBoolean("internal",
-			mcp.Description("Get internal kubeconfig (container IPs instead of localhost). Default: false."),
+			mcp.Description("Get internal kubeconfig (container IPs instead of localhost). Same as rewrite_for=internal. Default: false."),
+		),
+		mcp.WithString("rewrite_for",
+			mcp.Description("Where the kubeconfig will be used: 'host' (127.0.0.1:<port>), 'internal' (<name>-control-plane:6443, "+
+				"for containers on the kind network), 'docker-network' (host.docker.internal:<port>, for containers on other "+
+				"networks), 'custom' (server_address), or 'auto' to choose from the detected caller network. Default: host."),
+			mcp.Enum(kind.RewriteAuto, kind.RewriteHost, kind.RewriteInternal, kind.RewriteDockerNetwork, kind.RewriteCustom),
+		),
+		mcp.WithString("server_address",
+			mcp.Description("host or host:port for rewrite_for=custom; without a port the cluster's published API server port is used."),
+		),
+		mcp.WithString("context_name",
+			mcp.Description("Name for the context instead of kind-<name>. Default: the context name from set_cluster_defaults, if any."),
+		),
+		mcp.WithString("cluster_entry_name",
+			mcp.Description("Name for the kubeconfig's cluster entry instead of kind-<name>."),
+		),
+		mcp.WithString("user_entry_name",
+			mcp.Description("Name for the kubeconfig's user entry instead of kind-<name>."),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Default namespace for the context. Default: the namespace from set_cluster_defaults, if any."),
+		),
+		mcp.WithBoolean("to_file",
+			mcp.Description("Write the kubeconfig to a temp file (0600) and return the path instead of its contents. Default: false."),
+		),
+		mcp.WithString("kubeconfig_path",
+			mcp.Description("Write the kubeconfig to this path (0600) and return the path instead of its contents."),
 		),
+		maxOutputParam(),
 	)
 	s.AddTool(tool, r.handleGetKubeconfig)
+
+	endpointTool := mcp.NewTool("get_api_endpoint",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Get where a Kind cluster's API server can be reached without its kubeconfig or credentials: the "+
+				"host-reachable server URL and port (published by the control-plane node, or by the external load "+
+				"balancer of an HA cluster), the URL containers on the kind network use, and the cluster CA "+
+				"certificate (PEM) with its subject, SHA-256 fingerprint and expiry."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+	)
+	s.AddTool(endpointTool, r.handleGetAPIEndpoint)
+
+	exposeTool := mcp.NewTool("expose_api_server",
+		updateHints,
+		mcp.WithDescription(
+			"Help teammates or VMs on the LAN reach a Kind cluster's API server. Lists host interfaces, "+
+				"selects an address, and returns the networking patch (apiServerAddress/apiServerPort) that binds "+
+				"a cluster's API server to it. For an existing cluster that already listens on that address, also "+
+				"returns a kubeconfig pointing at it (with tls-server-name set when the certificate lacks the address)."),
+		mcp.WithString("cluster_name",
+			mcp.Description("Existing Kind cluster to check and build a kubeconfig for. Omit to only get the config patch."),
+		),
+		mcp.WithString("address",
+			mcp.Description("Host interface address to expose on. Default: the first IPv4 address."),
+		),
+		mcp.WithNumber("api_server_port",
+			mcp.Description("API server host port for the config patch. Default: the cluster's current port, or 6443."),
+		),
+		mcp.WithString("kubeconfig_path",
+			mcp.Description("Write the LAN kubeconfig to this path (mode 0600) instead of returning it inline"),
+		),
+	)
+	s.AddTool(exposeTool, r.handleExposeAPIServer)
+
+	scopedTool := mcp.NewTool("create_scoped_kubeconfig",
+		updateHints,
+		mcp.WithDescription(
+			"Create a ServiceAccount bound to a ClusterRole (or to a Role built from custom rules) and return a "+
+				"kubeconfig that authenticates with a time-bound token for it, to give agents or CI steps less than "+
+				"the cluster-admin access of the default kubeconfig. Re-running updates the RBAC objects and issues "+
+				"a new token."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("service_account",
+			mcp.Required(),
+			mcp.Description("Name of the ServiceAccount to create"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for the ServiceAccount; permissions are limited to it unless cluster_wide=true. "+
+				"Default: the cluster's default namespace, or default."),
+		),
+		mcp.WithString("role",
+			mcp.Description("Existing ClusterRole to bind, e.g. 'view', 'edit' or 'admin'. Ignored when 'rules' is set. "+
+				"Default: view."),
+		),
+		mcp.WithString("rules",
+			mcp.Description("JSON array of RBAC rules for a dedicated role "+
+				"(e.g. [{\"apiGroups\":[\"\"],\"resources\":[\"pods\"],\"verbs\":[\"get\",\"list\"]}])"),
+		),
+		mcp.WithBoolean("cluster_wide",
+			mcp.Description("Grant the role in every namespace with a ClusterRoleBinding. Default: false."),
+		),
+		mcp.WithNumber("duration_hours",
+			mcp.Description("Token lifetime in hours. Default: the settings file's ttl.scoped_token, or 24."),
+		),
+		mcp.WithString("kubeconfig_path",
+			mcp.Description("Write the kubeconfig to this path (mode 0600) instead of returning it inline"),
+		),
+	)
+	s.AddTool(scopedTool, r.handleCreateScopedKubeconfig)
 }
 
 func (r *Registry) handleGetKubeconfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Debug("tool called: get_kubeconfig")
-	name, err := request.RequireString("name")
-	if err != nil {
-		return mcp.NewToolResultError("parameter 'name' is required"), nil
+	r.log(ctx).Debug("tool called: get_kubeconfig")
+	name, errResult := r.clusterParam(ctx, request, "name")
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	internal := false
 	if val, ok := request.GetArguments()["internal"].(bool); ok {
 		internal = val
 	}
+	toFile := false
+	if val, ok := request.GetArguments()["to_file"].(bool); ok {
+		toFile = val
+	}
+	path, _ := request.RequireString("kubeconfig_path")
+	rewriteFor := request.GetString("rewrite_for", "")
+	if internal {
+		if rewriteFor != "" && rewriteFor != kind.RewriteInternal {
+			return mcp.NewToolResultError(fmt.Sprintf("internal=true conflicts with rewrite_for=%q", rewriteFor)), nil
+		}
+		rewriteFor = kind.RewriteInternal
+	}
 
 	mgr := r.kindManager(ctx)
-	kubeconfig, err := mgr.GetKubeconfig(ctx, name, internal)
+	var rewrite *kind.KubeconfigRewrite
+	var kubeconfig string
+	var err error
+	if rewriteFor == "" || rewriteFor == kind.RewriteHost {
+		kubeconfig, err = mgr.GetKubeconfig(ctx, name, false)
+	} else {
+		rewrite, err = mgr.RewriteKubeconfig(ctx, name, rewriteFor, request.GetString("server_address", ""))
+		if rewrite != nil {
+			kubeconfig = rewrite.Kubeconfig
+		}
+	}
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get kubeconfig: %v", err)), nil
 	}
+	// Explicit names win over the defaults recorded with set_cluster_defaults.
+	namespace, contextName := r.clusterDefaults(ctx, name)
+	names := kind.KubeconfigNames{
+		Context:   request.GetString("context_name", contextName),
+		Cluster:   request.GetString("cluster_entry_name", ""),
+		User:      request.GetString("user_entry_name", ""),
+		Namespace: request.GetString("namespace", namespace),
+	}
+	if names != (kind.KubeconfigNames{}) {
+		kubeconfig, err = kind.RenameKubeconfig(kubeconfig, names)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to rename kubeconfig entries: %v", err)), nil
+		}
+	}
+
+	if toFile || path != "" {
+		written, err := kind.WriteKubeconfig(name, path, kubeconfig)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to write kubeconfig: %v", err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"Kubeconfig for cluster %q written to %s (mode 0600).%s\n\nUse it with: kubectl --kubeconfig %s get nodes",
+			name, written, rewriteSummary(rewrite), written)), nil
+	}
+
+	kubeconfig = r.limitOutput(request, "kubeconfig "+name, kubeconfig)
+	return mcp.NewToolResultText(fmt.Sprintf("Kubeconfig for cluster %q:%s\n\n```yaml\n%s```",
+		name, rewriteSummary(rewrite), kubeconfig)), nil
+}
+
+// rewriteSummary describes a server rewrite and the detected caller network for the
+// get_kubeconfig text result; empty when no rewrite was requested.
+func rewriteSummary(rewrite *kind.KubeconfigRewrite) string {
+	if rewrite == nil {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nServer: %s (rewrite_for=%s", rewrite.Server, rewrite.RewriteFor)
+	if rewrite.TLSServerName != "" {
+		fmt.Fprintf(&b, ", tls-server-name %s", rewrite.TLSServerName)
+	}
+	b.WriteString(")\nCaller: ")
+	switch caller := rewrite.Caller; {
+	case !caller.InContainer:
+		b.WriteString("on the host")
+	case len(caller.Networks) == 0:
+		fmt.Fprintf(&b, "in container %s (%s); its networks could not be inspected", caller.ContainerID, caller.Evidence)
+	default:
+		fmt.Fprintf(&b, "in container %s on networks %s", caller.ContainerID, strings.Join(caller.Networks, ", "))
+	}
+	for _, note := range rewrite.Notes {
+		b.WriteString("\nNote: " + note)
+	}
+	return b.String()
+}
+
+func (r *Registry) handleGetAPIEndpoint(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: get_api_endpoint")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	mgr := r.kindManager(ctx)
+	endpoint, err := mgr.APIEndpoint(ctx, clusterName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get API endpoint: %v", err)), nil
+	}
+	return jsonResult(endpoint)
+}
+
+func (r *Registry) handleExposeAPIServer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: expose_api_server")
+	clusterName := request.GetString("cluster_name", "")
+	address := request.GetString("address", "")
+	port := 0
+	if p, err := request.RequireFloat("api_server_port"); err == nil {
+		if p < 1 || p > 65535 {
+			return mcp.NewToolResultError("parameter 'api_server_port' must be between 1 and 65535"), nil
+		}
+		port = int(p)
+	}
+
+	mgr := r.kindManager(ctx)
+	result, err := mgr.ExposeAPIServer(ctx, clusterName, address, port)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to expose API server: %v", err)), nil
+	}
+
+	if path := request.GetString("kubeconfig_path", ""); path != "" && result.Kubeconfig != "" {
+		written, err := kind.WriteKubeconfig(clusterName, path, result.Kubeconfig)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to write kubeconfig: %v", err)), nil
+		}
+		result.Kubeconfig, result.KubeconfigPath = "", written
+	}
+
+	return jsonResult(result)
+}
+
+func (r *Registry) handleCreateScopedKubeconfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: create_scoped_kubeconfig")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	serviceAccount, err := request.RequireString("service_account")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'service_account' is required"), nil
+	}
+
+	opts := kind.ScopedAccessOptions{
+		ServiceAccount: serviceAccount,
+		Namespace:      r.namespaceParam(ctx, request, clusterName),
+		Role:           request.GetString("role", ""),
+	}
+	if rulesJSON := request.GetString("rules", ""); rulesJSON != "" {
+		if err := json.Unmarshal([]byte(rulesJSON), &opts.Rules); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'rules' JSON: %v", err)), nil
+		}
+	}
+	if val, ok := request.GetArguments()["cluster_wide"].(bool); ok {
+		opts.ClusterWide = val
+	}
+	opts.Duration = r.settings.TTL.ScopedToken
+	if hours, err := request.RequireFloat("duration_hours"); err == nil {
+		opts.Duration = time.Duration(hours * float64(time.Hour))
+	}
+
+	mgr := r.kindManager(ctx)
+	result, err := mgr.CreateScopedKubeconfig(ctx, clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create scoped kubeconfig: %v", err)), nil
+	}
+
+	if path := request.GetString("kubeconfig_path", ""); path != "" {
+		written, err := kind.WriteKubeconfig(clusterName, path, result.Kubeconfig)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to write kubeconfig: %v", err)), nil
+		}
+		result.Kubeconfig, result.KubeconfigPath = "", written
+	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Kubeconfig for cluster %q:\n\n```yaml\n%s```", name, kubeconfig)), nil
+	return jsonResult(result)
 }
@@ -0,0 +1,455 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kustomize"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerKubectlTools(s *server.MCPServer) {
+	tool := mcp.NewTool("kubectl",
+		updateHints,
+		mcp.WithDescription(
+			"Run a kubectl subcommand against a Kind cluster using its kubeconfig. "+
+				"Only allowlisted verbs are permitted (by default: get, describe, logs, top, explain, events, "+
+				"api-resources, api-versions, version, cluster-info, and apply with confirm=true). "+
+				"Returns structured stdout, stderr and exit code."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithArray("args",
+			mcp.Required(),
+			mcp.WithStringItems(),
+			mcp.Description("kubectl arguments starting with the verb (e.g. ['get', 'pods', '-o', 'wide'])"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to run the command in. Default: the cluster's default namespace, unless args "+
+				"select one with -n, --namespace or --all-namespaces."),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Required for verbs that modify the cluster (e.g. apply). Default: false."),
+		),
+		maxOutputParam(),
+	)
+	s.AddTool(tool, r.handleKubectl)
+
+	runPodTool := mcp.NewTool("run_pod",
+		createHints,
+		mcp.WithDescription(
+			"Run a one-off pod in a Kind cluster (restartPolicy Never), wait for it to finish or time out, "+
+				"and return its logs, phase and exit code. The pod is deleted afterwards unless keep=true. "+
+				"Useful for in-cluster checks such as DNS lookups, curl to a Service, or smoke tests."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("image",
+			mcp.Required(),
+			mcp.Description("Container image (e.g. 'busybox:1.36', 'curlimages/curl:8.10.1')"),
+		),
+		mcp.WithArray("command",
+			mcp.WithStringItems(),
+			mcp.Description("Command and arguments (e.g. ['nslookup', 'kubernetes.default']). Default: the image entrypoint."),
+		),
+		mcp.WithString("env",
+			mcp.Description("JSON object of environment variables (e.g. {\"TARGET\":\"http://web.shop:8080\"})"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to run the pod in. Default: the cluster's default namespace, or default."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for the pod to finish, including the image pull. Default: 300."),
+		),
+		mcp.WithBoolean("keep",
+			mcp.Description("Keep the pod after it finishes for further inspection. Default: false."),
+		),
+		maxOutputParam(),
+	)
+	s.AddTool(runPodTool, r.handleRunPod)
+
+	diffTool := mcp.NewTool("diff_manifest",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Show what applying a YAML manifest to a Kind cluster would change, without changing it. The manifest "+
+				"is applied with a server-side dry run (so defaulting, admission and validation errors show up) and "+
+				"each object is compared with the live one. Returns per object whether it would be created, updated "+
+				"or left unchanged, with the changed field paths and their before and after values. Present the "+
+				"changes for approval before applying with 'kubectl' (apply, confirm=true)."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("manifest",
+			mcp.Required(),
+			mcp.Description("YAML manifest, one or more documents"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for objects that name none. Default: the cluster's default namespace, or default."),
+		),
+		verbosityParam(),
+	)
+	s.AddTool(diffTool, r.handleDiffManifest)
+
+	kustomizeTool := mcp.NewTool("apply_kustomize",
+		updateHints,
+		mcp.WithDescription(
+			"Build a kustomization (a local directory or a remote URL such as "+
+				"'https://github.com/org/repo//deploy/overlays/dev?ref=main') with the kustomize library and apply "+
+				"it to a Kind cluster. Applying requires confirm=true; review the changes with diff=true first, which "+
+				"returns what would change (kubectl diff) without applying. With prune=true, deletes resources "+
+				"matching prune_selector that the kustomization no longer contains."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Kustomization directory on the server's host, or a remote kustomization URL (needs git)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for resources the kustomization leaves without one. "+
+				"Default: the cluster's default namespace, or default."),
+		),
+		mcp.WithBoolean("diff",
+			mcp.Description("Only report the differences to the cluster, without applying. Default: false."),
+		),
+		mcp.WithBoolean("prune",
+			mcp.Description("Delete resources matching prune_selector that are not in the kustomization. "+
+				"Only resources matching the selector are applied. Default: false."),
+		),
+		mcp.WithString("prune_selector",
+			mcp.Description("Label selector of the resources the kustomization manages (e.g. 'app.kubernetes.io/part-of=shop'). "+
+				"Required with prune=true."),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Required unless diff=true, since applying (and pruning) modifies the cluster. Default: false."),
+		),
+		maxOutputParam(),
+	)
+	s.AddTool(kustomizeTool, r.handleApplyKustomize)
+
+	workloadParams := []mcp.ToolOption{
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("Workload kind"),
+			mcp.Enum(kind.WorkloadDeployment, kind.WorkloadStatefulSet, kind.WorkloadDaemonSet),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the workload"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the workload. Default: the cluster's default namespace, or default."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for the rollout to finish. Default: 120."),
+		),
+	}
+
+	restartTool := mcp.NewTool("rollout_restart", append([]mcp.ToolOption{
+		updateHints,
+		mcp.WithDescription(
+			"Restart a Deployment, StatefulSet or DaemonSet with a rolling update through the Kubernetes API, as " +
+				"'kubectl rollout restart' does, e.g. after loading a new image with the same tag. With wait=true, " +
+				"waits for the rollout and returns its status."),
+		mcp.WithBoolean("wait",
+			mcp.Description("Wait for the restarted rollout to finish and return its status. Default: false."),
+		),
+	}, workloadParams...)...)
+	s.AddTool(restartTool, r.handleRolloutRestart)
+
+	statusTool := mcp.NewTool("rollout_status", append([]mcp.ToolOption{
+		readOnlyHints,
+		mcp.WithDescription(
+			"Watch a Deployment, StatefulSet or DaemonSet until its rollout finishes, judged as 'kubectl rollout " +
+				"status' does, and return whether it completed, the current revision, and desired/updated/ready/" +
+				"available replica counts."),
+	}, workloadParams...)...)
+	s.AddTool(statusTool, r.handleRolloutStatus)
+
+	waitTool := mcp.NewTool("wait_for",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Block until a resource meets a condition, watching it through the Kubernetes API instead of polling, "+
+				"with the conditions of 'kubectl wait', and report whether it was met, so a workflow can wait for a "+
+				"Deployment to be Available, a Pod Ready or a Job Complete in one call. A timeout is reported with "+
+				"timed_out=true and the resource's current conditions, not as an error."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("Resource kind, e.g. deployment, pod, job, node or crd"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the resource. Set either name or selector."),
+		),
+		mcp.WithString("selector",
+			mcp.Description("Label selector to wait on every matching resource, e.g. app=web"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the resource. Default: the cluster's default namespace, or default."),
+		),
+		mcp.WithString("condition",
+			mcp.Required(),
+			mcp.Description("Condition to wait for: a status condition type such as Available, Ready or Complete "+
+				"(optionally with a status, e.g. Ready=False), 'delete', 'create', or a JSONPath check such as "+
+				"'jsonpath={.status.phase}=Running'."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for the condition. Default: 120."),
+		),
+	)
+	s.AddTool(waitTool, r.handleWaitFor)
+
+	endpointsTool := mcp.NewTool("get_service_endpoints",
+		readOnlyHints,
+		mcp.WithDescription(
+			"List the Services of a Kind cluster and the URL to open from the host for each port: the host port "+
+				"a node's extraPortMapping publishes its NodePort on, the LoadBalancer address assigned by "+
+				"cloud-provider-kind, or otherwise a 'kubectl port-forward' command. Also lists the ingress URLs "+
+				"when nodes publish ports 80/443."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("namespace",
+			mcp.Description("Only list Services in this namespace. Default: all namespaces."),
+		),
+		verbosityParam(),
+	)
+	s.AddTool(endpointsTool, r.handleGetServiceEndpoints)
+}
+
+func (r *Registry) handleKubectl(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: kubectl")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	args, err := request.RequireStringSlice("args")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'args' is required"), nil
+	}
+	namespace := request.GetString("namespace", "")
+	if namespace == "" && !kind.HasNamespaceFlag(args) {
+		namespace, _ = r.clusterDefaults(ctx, clusterName)
+	}
+	confirmed := false
+	if val, ok := request.GetArguments()["confirm"].(bool); ok {
+		confirmed = val
+	}
+
+	if err := kind.CheckKubectlArgs(args, r.kubectlVerbs, confirmed); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	mgr := r.kindManager(ctx)
+	result, err := mgr.RunKubectl(ctx, clusterName, namespace, args)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to run kubectl: %v", err)), nil
+	}
+
+	result.Stdout = r.limitOutput(request, "kubectl stdout", result.Stdout)
+	result.Stderr = r.limitOutput(request, "kubectl stderr", result.Stderr)
+	return jsonResult(result)
+}
+
+func (r *Registry) handleRunPod(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: run_pod")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	image, err := request.RequireString("image")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'image' is required"), nil
+	}
+
+	opts := kind.RunPodOptions{
+		Image:     image,
+		Command:   request.GetStringSlice("command", nil),
+		Namespace: r.namespaceParam(ctx, request, clusterName),
+	}
+	if raw := request.GetString("env", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Env); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'env' JSON: %v", err)), nil
+		}
+	}
+	if timeout, err := request.RequireFloat("timeout_seconds"); err == nil && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+	if val, ok := request.GetArguments()["keep"].(bool); ok {
+		opts.Keep = val
+	}
+
+	mgr := r.kindManager(ctx)
+	result, err := mgr.RunPod(ctx, clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to run pod: %v", err)), nil
+	}
+
+	result.Logs = r.limitOutput(request, "run_pod logs "+result.Pod, result.Logs)
+	return jsonResult(result)
+}
+
+func (r *Registry) handleDiffManifest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: diff_manifest")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	manifest, err := request.RequireString("manifest")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'manifest' is required"), nil
+	}
+
+	mgr := r.kindManager(ctx)
+	diff, err := mgr.DiffManifest(ctx, clusterName, manifest, r.namespaceParam(ctx, request, clusterName))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to diff manifest: %v", err)), nil
+	}
+	if summaryRequested(request) {
+		return linesResult(diff.Lines())
+	}
+	return jsonResult(diff)
+}
+
+func (r *Registry) handleApplyKustomize(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: apply_kustomize")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	path, err := request.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'path' is required"), nil
+	}
+
+	opts := kustomize.Options{
+		Target:        path,
+		Namespace:     r.namespaceParam(ctx, request, clusterName),
+		PruneSelector: request.GetString("prune_selector", ""),
+	}
+	if val, ok := request.GetArguments()["diff"].(bool); ok {
+		opts.Diff = val
+	}
+	if val, ok := request.GetArguments()["prune"].(bool); ok {
+		opts.Prune = val
+	}
+	if confirmed, _ := request.GetArguments()["confirm"].(bool); !opts.Diff && !confirmed {
+		return mcp.NewToolResultError("apply_kustomize modifies the cluster; review the changes with diff=true, " +
+			"then re-run with confirm=true"), nil
+	}
+
+	result, err := kustomize.Apply(ctx, r.kindManager(ctx), clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to apply kustomization: %v", err)), nil
+	}
+	result.Diff = r.limitOutput(request, "apply_kustomize diff "+path, result.Diff)
+	result.Output = r.limitOutput(request, "apply_kustomize "+path, result.Output)
+	return jsonResult(result)
+}
+
+// rolloutTarget reads the workload parameters shared by the rollout tools.
+func (r *Registry) rolloutTarget(ctx context.Context, request mcp.CallToolRequest) (string, kind.RolloutTarget, time.Duration, *mcp.CallToolResult) {
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return "", kind.RolloutTarget{}, 0, errResult
+	}
+	workloadKind, err := request.RequireString("kind")
+	if err != nil {
+		return "", kind.RolloutTarget{}, 0, mcp.NewToolResultError("parameter 'kind' is required")
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return "", kind.RolloutTarget{}, 0, mcp.NewToolResultError("parameter 'name' is required")
+	}
+	var timeout time.Duration
+	if t, err := request.RequireFloat("timeout_seconds"); err == nil && t > 0 {
+		timeout = time.Duration(t) * time.Second
+	}
+	target := kind.RolloutTarget{Kind: workloadKind, Name: name, Namespace: r.namespaceParam(ctx, request, clusterName)}
+	return clusterName, target, timeout, nil
+}
+
+func (r *Registry) handleRolloutRestart(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: rollout_restart")
+	clusterName, target, timeout, errResult := r.rolloutTarget(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	mgr := r.kindManager(ctx)
+	if err := mgr.RolloutRestart(ctx, clusterName, target); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to restart %s/%s: %v", target.Kind, target.Name, err)), nil
+	}
+	if val, ok := request.GetArguments()["wait"].(bool); !ok || !val {
+		return mcp.NewToolResultText(fmt.Sprintf("Restarted %s/%s. Use 'rollout_status' to follow the rollout.",
+			target.Kind, target.Name)), nil
+	}
+
+	state, err := mgr.RolloutStatus(ctx, clusterName, target, timeout)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("restarted, but failed to get rollout status: %v", err)), nil
+	}
+	return jsonResult(state)
+}
+
+func (r *Registry) handleRolloutStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: rollout_status")
+	clusterName, target, timeout, errResult := r.rolloutTarget(ctx, request)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	mgr := r.kindManager(ctx)
+	state, err := mgr.RolloutStatus(ctx, clusterName, target, timeout)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get rollout status: %v", err)), nil
+	}
+	return jsonResult(state)
+}
+
+func (r *Registry) handleWaitFor(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: wait_for")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	resourceKind, err := request.RequireString("kind")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'kind' is required"), nil
+	}
+	condition, err := request.RequireString("condition")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'condition' is required"), nil
+	}
+	var timeout time.Duration
+	if t, err := request.RequireFloat("timeout_seconds"); err == nil && t > 0 {
+		timeout = time.Duration(t) * time.Second
+	}
+	target := kind.WaitTarget{
+		Kind:      resourceKind,
+		Name:      request.GetString("name", ""),
+		Selector:  request.GetString("selector", ""),
+		Namespace: r.namespaceParam(ctx, request, clusterName),
+		Condition: condition,
+	}
+
+	mgr := r.kindManager(ctx)
+	result, err := mgr.WaitFor(ctx, clusterName, target, timeout)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to wait: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleGetServiceEndpoints(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: get_service_endpoints")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	mgr := r.kindManager(ctx)
+	report, err := mgr.ServiceEndpoints(ctx, clusterName, request.GetString("namespace", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get service endpoints: %v", err)), nil
+	}
+	if summaryRequested(request) {
+		return linesResult(report.Lines())
+	}
+	return jsonResult(report)
+}
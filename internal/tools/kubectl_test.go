@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleApplyKustomize_RequiresConfirm(t *testing.T) {
+	t.Setenv("MCP_KIND_STATE_DIR", t.TempDir())
+	r := NewRegistry(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, "test")
+	defer r.Shutdown(context.Background())
+
+	for _, args := range []map[string]any{
+		{"cluster_name": "dev", "path": t.TempDir()},
+		{"cluster_name": "dev", "path": t.TempDir(), "prune": true, "prune_selector": "app=web"},
+		{"cluster_name": "dev", "path": t.TempDir(), "diff": false, "confirm": false},
+	} {
+		var request mcp.CallToolRequest
+		request.Params.Arguments = args
+		result, err := r.handleApplyKustomize(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "confirm=true") {
+			t.Errorf("%v: expected apply without confirm to be refused, got %+v", args, result)
+		}
+	}
+}
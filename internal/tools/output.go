@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/output"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerOutputResources(s *server.MCPServer) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(output.URIPrefix+"{id}", "Full tool output",
+			mcp.WithTemplateDescription("The complete text of a tool output that was shortened to fit "+
+				"max_output_bytes. Only the most recent shortened outputs are kept, in memory."),
+			mcp.WithTemplateMIMEType("text/plain"),
+		),
+		r.handleReadOutput,
+	)
+}
+
+func (r *Registry) handleReadOutput(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	r.log(ctx).Debug("resource read", "uri", request.Params.URI)
+	entry, ok := r.outputs.Get(request.Params.URI)
+	if !ok {
+		return nil, fmt.Errorf("output %s not found; it may have been evicted or the server restarted", request.Params.URI)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      entry.URI,
+		MIMEType: "text/plain",
+		Text:     entry.Text,
+	}}, nil
+}
+
+// maxOutputParam is the max_output_bytes parameter of tools whose output can be large.
+func maxOutputParam() mcp.ToolOption {
+	return mcp.WithNumber("max_output_bytes",
+		mcp.Description(fmt.Sprintf("Shorten output larger than this many bytes to its head and tail; the full "+
+			"output stays readable as a %s resource. Default: %d.", output.URIPrefix, output.DefaultMaxBytes)),
+	)
+}
+
+// limitOutput shortens text to the request's max_output_bytes, keeping the full text
+// as a resource.
+func (r *Registry) limitOutput(request mcp.CallToolRequest, name, text string) string {
+	maxBytes := 0
+	if v, err := request.RequireFloat("max_output_bytes"); err == nil {
+		maxBytes = int(v)
+	}
+	return r.outputs.Limit(name, text, maxBytes)
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sharedClusterTools are the tools any client may call on any cluster. They expose
+// only what a Kind user on the same host can see without credentials: the server's own
+// records, node container state and config (docker inspect), the API server URL and CA
+// certificate, the kube-public local registry ConfigMap, which Kubernetes makes readable
+// to unauthenticated users, and the cluster's health. Tools reading objects, node
+// contents or logs from inside a cluster are owner-only. With MCP_KIND_ENFORCE_OWNERSHIP
+// every other tool that names a cluster is limited to the cluster's owner and admins
+// over HTTP; see ownedClusterParams. bootstrap_environment checks the cluster it reuses
+// itself, and reconcile_clusters the records it prunes.
+var sharedClusterTools = []string{
+	"generate_cluster_config",
+	"get_cluster_status",
+	"diff_cluster_config",
+	"use_cluster",
+	"get_api_endpoint",
+	"get_local_registry_info",
+	"plan_network_exposure",
+	"watch_cluster_health",
+}
+
+// ownedClusterParams maps every registered tool that names a cluster, except the
+// sharedClusterTools, to the parameter naming it: "cluster_name" if it has one, else
+// "name". New tools are thus owner-only unless added to sharedClusterTools.
+func ownedClusterParams(tools map[string]*server.ServerTool) map[string]string {
+	params := make(map[string]string)
+	for name, tool := range tools {
+		if slices.Contains(sharedClusterTools, name) {
+			continue
+		}
+		for _, param := range []string{"cluster_name", "name"} {
+			if _, ok := tool.Tool.InputSchema.Properties[param]; ok {
+				params[name] = param
+				break
+			}
+		}
+	}
+	return params
+}
+
+// enforceOwnershipFromEnv reports whether MCP_KIND_ENFORCE_OWNERSHIP is set to true.
+func enforceOwnershipFromEnv(logger *slog.Logger) bool {
+	env := os.Getenv("MCP_KIND_ENFORCE_OWNERSHIP")
+	if env == "" {
+		return false
+	}
+	enforce, err := strconv.ParseBool(env)
+	if err != nil {
+		logger.Warn("ignoring invalid MCP_KIND_ENFORCE_OWNERSHIP", "value", env)
+		return false
+	}
+	return enforce
+}
+
+// callerName returns the name of the HTTP client making the call, recorded as the
+// owner of the clusters it creates; it is empty over stdio.
+func callerName(ctx context.Context) string {
+	id, _ := auth.FromContext(ctx)
+	return id.Name
+}
+
+// checkOwnership returns an error result when ownership is enforced and the HTTP
+// client making the call may not use the cluster the call names. Calls over stdio
+// are never restricted.
+func (r *Registry) checkOwnership(ctx context.Context, request mcp.CallToolRequest) *mcp.CallToolResult {
+	param, ok := r.ownedParams[request.Params.Name]
+	if !ok {
+		return nil
+	}
+	name := request.GetString(param, "")
+	if name == "" {
+		name, _ = r.currentCluster(ctx)
+	}
+	return r.authorizeCluster(ctx, name)
+}
+
+// authorizeCluster returns an error result when ownership is enforced and the HTTP
+// client making the call may not use the named cluster.
+func (r *Registry) authorizeCluster(ctx context.Context, name string) *mcp.CallToolResult {
+	id, ok := auth.FromContext(ctx)
+	if !r.enforceOwnership || !ok || name == "" {
+		return nil
+	}
+	rec, err := r.store.Get(name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read cluster state: %v", err))
+	}
+	if rec == nil || id.MayChange(rec.Owner) {
+		return nil
+	}
+	r.log(ctx).Warn("cluster access denied", "cluster", name, "owner", rec.Owner, "client", id.Name)
+	return mcp.NewToolResultError(fmt.Sprintf("cluster %q is owned by %q; only its owner or an admin may use it with this tool",
+		name, rec.Owner))
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerPrompts adds prompts that walk a client through common multi-tool workflows.
+// Each renders a single user message naming the tools to call, in order, with their
+// arguments filled in from the prompt arguments.
+func (r *Registry) registerPrompts(s *server.MCPServer) {
+	s.AddPrompt(mcp.NewPrompt("setup_dev_cluster",
+		mcp.WithPromptDescription("Create a development cluster with ports 80/443 mapped for an ingress "+
+			"controller and a local image registry reachable from the host and the nodes."),
+		mcp.WithArgument("cluster_name",
+			mcp.ArgumentDescription("Name of the cluster. Default: dev."),
+		),
+		mcp.WithArgument("workers",
+			mcp.ArgumentDescription("Number of worker nodes. Default: 1."),
+		),
+		mcp.WithArgument("kubernetes_version",
+			mcp.ArgumentDescription("Kubernetes version, e.g. 1.31.0. Default: the Kind default."),
+		),
+		mcp.WithArgument("registry_port",
+			mcp.ArgumentDescription("Host port of the local registry. Default: 5001."),
+		),
+	), r.handleSetupDevClusterPrompt)
+
+	s.AddPrompt(mcp.NewPrompt("debug_image_pull",
+		mcp.WithPromptDescription("Find out why pods in a Kind cluster cannot pull an image "+
+			"(ErrImagePull / ImagePullBackOff) and fix it."),
+		mcp.WithArgument("cluster_name",
+			mcp.ArgumentDescription("Name of the cluster"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("image",
+			mcp.ArgumentDescription("Image reference that fails to pull, e.g. ghcr.io/acme/api:1.2"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("namespace",
+			mcp.ArgumentDescription("Namespace of the failing pods. Default: the cluster's default namespace."),
+		),
+	), r.handleDebugImagePullPrompt)
+
+	s.AddPrompt(mcp.NewPrompt("upgrade_kubernetes",
+		mcp.WithPromptDescription("Move a Kind cluster to another Kubernetes version, keeping its "+
+			"workloads and volume data."),
+		mcp.WithArgument("cluster_name",
+			mcp.ArgumentDescription("Name of the cluster"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("kubernetes_version",
+			mcp.ArgumentDescription("Target Kubernetes version, e.g. 1.32.2"),
+			mcp.RequiredArgument(),
+		),
+	), r.handleUpgradeKubernetesPrompt)
+}
+
+func (r *Registry) handleSetupDevClusterPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	r.log(ctx).Info("prompt requested: setup_dev_cluster")
+	args := request.Params.Arguments
+	name := promptArg(args, "cluster_name", "dev")
+	workers, err := strconv.Atoi(promptArg(args, "workers", "1"))
+	if err != nil || workers < 0 {
+		return nil, fmt.Errorf("argument 'workers' must be a non-negative number")
+	}
+	port, err := strconv.Atoi(promptArg(args, "registry_port", "5001"))
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("argument 'registry_port' must be a port number")
+	}
+	runtime := r.runtimeInfo(ctx).Runtime
+	if runtime != rtdetect.RuntimePodman {
+		runtime = rtdetect.RuntimeDocker
+	}
+
+	version := ""
+	if v := args["kubernetes_version"]; v != "" {
+		version = fmt.Sprintf(", kubernetes_version=%q", v)
+	}
+	steps := []string{
+		"Call `detect_environment` and note the runtime, backend and any warnings about privileged ports.",
+		fmt.Sprintf("Call `generate_cluster_config` with name=%q, workers=%d, enable_ingress_ports=true%s. "+
+			"If detect_environment or the config warnings say ports 80/443 cannot be bound, use port_mappings "+
+			"for 8080/8443 instead of enable_ingress_ports.", name, workers, version),
+		"Call `create_cluster` with the generated config_yaml and dry_run=true, show me the plan and fix any " +
+			"failed preflight check; then call it again without dry_run.",
+		fmt.Sprintf("Start the registry on the host (this server has no tool for it): `%[1]s run -d --restart=always "+
+			"-p 127.0.0.1:%[2]d:5000 --network kind --name kind-registry registry:2` (skip it if a container named "+
+			"kind-registry already runs).", runtime, port),
+		fmt.Sprintf("Call `configure_registry_mirrors` with cluster_name=%q and overrides="+
+			`[{"original":"localhost:%d","mirror":"http://kind-registry:5000"}] so that nodes pull localhost:%[2]d/... `+
+			"images from the registry container; then call `verify_registry_mirrors` with the same overrides and a "+
+			"test_images entry for an image you pushed.", name, port),
+		fmt.Sprintf("Install the ingress controller with `kubectl` on cluster %q: args=[\"apply\", \"-f\", "+
+			"\"https://kind.sigs.k8s.io/examples/ingress/deploy-ingress-nginx.yaml\"], confirm=true; then `rollout_status` "+
+			"on deployment ingress-nginx-controller in namespace ingress-nginx.", name),
+		fmt.Sprintf("Call `get_kubeconfig` and `get_local_registry_info` for %q and summarize: the kubeconfig context, "+
+			"that images are pushed with `%s push localhost:%d/<image>` and referenced as localhost:%[3]d/<image>, "+
+			"and that Ingress resources are served on http://localhost/.", name, runtime, port),
+	}
+	return promptResult("Set up a Kind development cluster with ingress and a local registry", steps), nil
+}
+
+func (r *Registry) handleDebugImagePullPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	r.log(ctx).Info("prompt requested: debug_image_pull")
+	args := request.Params.Arguments
+	name, image := args["cluster_name"], args["image"]
+	if name == "" || image == "" {
+		return nil, fmt.Errorf("arguments 'cluster_name' and 'image' are required")
+	}
+	namespace := ""
+	if ns := args["namespace"]; ns != "" {
+		namespace = fmt.Sprintf(", namespace=%q", ns)
+	}
+
+	steps := []string{
+		fmt.Sprintf("Call `kubectl` on cluster %q with args=[\"get\", \"events\", \"--field-selector\", "+
+			"\"reason=Failed\"]%s and find the pull error for %s (not found, unauthorized, TLS, timeout, DNS, or "+
+			"no matching platform).", name, namespace, image),
+		fmt.Sprintf("Call `list_node_images_in_cluster` for %q. If %s is only on some nodes or was built locally "+
+			"(no registry host, or a :latest tag with imagePullPolicy Always), load it with `load_image` "+
+			"(cluster_name=%[1]q, image=%[2]q) and make sure the pod spec uses imagePullPolicy IfNotPresent.", name, image),
+		"If the error is 'no match for platform' or exec format errors follow, call `load_image` with " +
+			"the platform of the nodes, or rebuild the image for it with `build_and_load`.",
+		"If the registry answered 401/403, call `detect_credentials` (merge=true) to see which registries have " +
+			"host credentials; for cloud registries (ECR, GCR/Artifact Registry, ACR) call `refresh_cloud_credentials`, " +
+			"otherwise `configure_registry_mirrors` with include_credentials=true.",
+		fmt.Sprintf("If a mirror or proxy is involved, call `verify_registry_mirrors` for %q with the mirrors in use "+
+			"and test_images pointing at %s.", name, image),
+		fmt.Sprintf("If the error is a timeout, DNS or TLS failure, call `diagnose_networking` for %q and check the "+
+			"egress and DNS results.", name),
+		"Report the root cause, what you changed, and confirm with `kubectl` get pods that the pods are running.",
+	}
+	return promptResult(fmt.Sprintf("Debug why %s fails to pull in cluster %s", image, name), steps), nil
+}
+
+func (r *Registry) handleUpgradeKubernetesPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	r.log(ctx).Info("prompt requested: upgrade_kubernetes")
+	args := request.Params.Arguments
+	name, version := args["cluster_name"], args["kubernetes_version"]
+	if name == "" || version == "" {
+		return nil, fmt.Errorf("arguments 'cluster_name' and 'kubernetes_version' are required")
+	}
+	archive := fmt.Sprintf("%s-workloads.tar.gz", name)
+
+	steps := []string{
+		fmt.Sprintf("Call `get_cluster_status` and `diff_cluster_config` for %q. Note the registry mirrors and any "+
+			"drift that recreation would discard.", name),
+		fmt.Sprintf("Call `generate_cluster_config` with kubernetes_version=%q and check its warnings: stop and tell "+
+			"me if the installed kind does not support that version.", version),
+		fmt.Sprintf("Call `export_workloads` for %q with output_path=%q and include_volume_data=true.", name, archive),
+		fmt.Sprintf("Call `recreate_cluster` with name=%q and kubernetes_version=%q.", name, version),
+		fmt.Sprintf("Re-apply the registry mirrors noted in step 1 with `configure_registry_mirrors`, then call "+
+			"`import_workloads` for %q with archive_path=%q.", name, archive),
+		fmt.Sprintf("Call `watch_cluster_health` or `get_cluster_status` for %q and `kubectl` get nodes,pods -A; "+
+			"report the new node versions and anything that did not come back.", name),
+	}
+	return promptResult(fmt.Sprintf("Upgrade cluster %s to Kubernetes %s", name, version), steps), nil
+}
+
+// promptArg returns a prompt argument, or def when it is empty.
+func promptArg(args map[string]string, name, def string) string {
+	if v := args[name]; v != "" {
+		return v
+	}
+	return def
+}
+
+// promptResult renders numbered steps as the single user message of a prompt.
+func promptResult(description string, steps []string) *mcp.GetPromptResult {
+	var b strings.Builder
+	b.WriteString(description + " using the mcp-kind-manager tools. Work through these steps in order, " +
+		"stop and ask me when a step fails or needs a decision:\n")
+	for i, step := range steps {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, step)
+	}
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+	})
+}
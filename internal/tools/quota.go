@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/quota"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/settings"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// quotaLimits returns the cluster creation quotas: each from its environment variable
+// if set, else from the settings file.
+func quotaLimits(logger *slog.Logger, configured settings.Quotas) quota.Limits {
+	return quota.Limits{
+		MaxClusters:         quotaEnv(logger, "MCP_KIND_MAX_CLUSTERS", configured.MaxClusters),
+		MaxClustersPerOwner: quotaEnv(logger, "MCP_KIND_MAX_CLUSTERS_PER_OWNER", configured.MaxClustersPerOwner),
+		MaxNodesPerCluster:  quotaEnv(logger, "MCP_KIND_MAX_NODES_PER_CLUSTER", configured.MaxNodesPerCluster),
+		MaxCreatesPerHour:   quotaEnv(logger, "MCP_KIND_MAX_CREATES_PER_HOUR", configured.MaxCreatesPerHour),
+	}
+}
+
+func quotaEnv(logger *slog.Logger, name string, configured int) int {
+	env := os.Getenv(name)
+	if env == "" {
+		return configured
+	}
+	n, err := strconv.Atoi(env)
+	if err != nil || n < 0 {
+		logger.Warn("ignoring invalid "+name, "value", env, "default", configured)
+		return configured
+	}
+	return n
+}
+
+// clusterUsage describes the creation of a cluster from configYAML for the quotas.
+func (r *Registry) clusterUsage(ctx context.Context, mgr *kind.Manager, configYAML string) (quota.Usage, error) {
+	nodes, err := kind.ConfigNodeCount(configYAML)
+	if err != nil {
+		return quota.Usage{}, err
+	}
+	clusters, err := mgr.ListClusters(ctx)
+	if err != nil {
+		return quota.Usage{}, fmt.Errorf("listing clusters: %w", err)
+	}
+	usage := quota.Usage{Clusters: len(clusters), Owner: callerName(ctx), Nodes: nodes}
+	if usage.Owner == "" || r.quotas.Limits().MaxClustersPerOwner == 0 {
+		return usage, nil
+	}
+	records, err := r.store.List()
+	if err != nil {
+		return quota.Usage{}, fmt.Errorf("reading cluster state: %w", err)
+	}
+	for _, rec := range records {
+		if rec.Owner == usage.Owner && slices.Contains(clusters, rec.Name) {
+			usage.OwnerClusters++
+		}
+	}
+	return usage, nil
+}
+
+// admitCluster checks the creation of a cluster from configYAML against the quotas.
+// On success the caller must call the returned done func once the creation has
+// finished; otherwise it gets the error result to return, structured when a quota is
+// exceeded.
+func (r *Registry) admitCluster(ctx context.Context, mgr *kind.Manager, configYAML string) (func(), *mcp.CallToolResult) {
+	if !r.quotas.Limits().Enabled() {
+		return func() {}, nil
+	}
+	usage, err := r.clusterUsage(ctx, mgr, configYAML)
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("failed to check quotas: %v", err))
+	}
+	done, err := r.quotas.Admit(usage)
+	if err != nil {
+		r.log(ctx).Warn("cluster creation refused", "error", err)
+		return nil, quotaErrorResult(err)
+	}
+	return done, nil
+}
+
+// quotaCheck is the preflight check of the quotas for dry runs.
+func (r *Registry) quotaCheck(ctx context.Context, mgr *kind.Manager, configYAML string) kind.PreflightCheck {
+	check := kind.PreflightCheck{Name: "quota", Status: kind.CheckPass, Message: "within the cluster creation quotas"}
+	if !r.quotas.Limits().Enabled() {
+		check.Message = "no quotas configured"
+		return check
+	}
+	usage, err := r.clusterUsage(ctx, mgr, configYAML)
+	if err == nil {
+		err = r.quotas.Check(usage)
+	}
+	if err != nil {
+		check.Status, check.Message = kind.CheckFail, err.Error()
+	}
+	return check
+}
+
+// quotaErrorResult returns err as an error result, with the exceeded quota as
+// structured content when it is a *quota.ExceededError.
+func quotaErrorResult(err error) *mcp.CallToolResult {
+	var exceeded *quota.ExceededError
+	if !errors.As(err, &exceeded) {
+		return mcp.NewToolResultError(err.Error())
+	}
+	result := mcp.NewToolResultStructured(map[string]any{"error": exceeded}, exceeded.Error())
+	result.IsError = true
+	return result
+}
@@ -4,62 +4,210 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/kubevoidcraft/mcp-kind-manager/internal/registry"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/state"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// localRegistryURIPrefix is the URI scheme of local registry resources.
+const localRegistryURIPrefix = "kind-registry://"
+
 func (r *Registry) registerRegistryTools(s *server.MCPServer) {
 	credTool := mcp.NewTool("detect_credentials",
+		readOnlyHints,
 		mcp.WithDescription(
 			"Discover registry credential files on the host. "+
 				"Searches Docker and Podman credential stores based on the detected runtime and OS. "+
 				"Returns the credential file path, registries with stored credentials, "+
 				"and whether credentials are inline or managed by a credential helper."),
+		mcp.WithBoolean("merge",
+			mcp.Description("Combine the inline credentials of every Docker and Podman credential file "+
+				"(config.json, auth.json, REGISTRY_AUTH_FILE) and report which file each registry came from. "+
+				"Default: false, which stops at the first file found."),
+		),
+		verbosityParam(),
 	)
 	s.AddTool(credTool, r.handleDetectCredentials)
 
 	mirrorTool := mcp.NewTool("configure_registry_mirrors",
+		destructiveHints,
 		mcp.WithDescription(
 			"Configure containerd registry mirrors on a running Kind cluster. "+
 				"Writes hosts.toml files to each node to redirect image pulls "+
 				"from specified registries to local mirror/proxy endpoints. "+
 				"Restarts containerd on all nodes after applying configuration."),
-		mcp.WithString("cluster_name",
-			mcp.Required(),
-			mcp.Description("Name of the Kind cluster to configure"),
-		),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to configure"),
 		mcp.WithString("overrides",
 			mcp.Required(),
 			mcp.Description(
 				"JSON array of registry overrides. Each object has 'original' (source registry, e.g. 'docker.io') "+
-					"and 'mirror' (mirror URL, e.g. 'http://my-proxy:5000'). "+
+					"and 'mirror' (mirror URL, e.g. 'http://my-proxy:5000'). Optional per override: "+
+					"'insecure_skip_verify' (skip TLS verification for an HTTPS mirror), "+
+					"'ca_file' (host path to a PEM CA bundle, copied to every node and trusted for the mirror), "+
+					"'capabilities' (any of pull, resolve, push; default [\"pull\",\"resolve\"]), "+
+					"'username'/'password' or 'auth_file' (host Docker config.json or Podman auth.json holding the "+
+					"mirror's credentials) for mirrors that require authentication. "+
+					"Credentials end up in hosts.toml and in dry_run output. "+
 					"Example: [{\"original\":\"docker.io\",\"mirror\":\"http://localhost:5000\"}]"),
 		),
 		mcp.WithBoolean("include_credentials",
 			mcp.Description("Also mount discovered host credentials into the cluster nodes. Default: false."),
 		),
+		mcp.WithBoolean("merge_credentials",
+			mcp.Description("With include_credentials, mount one config merged from every Docker and Podman "+
+				"credential file instead of the first one found. Default: false."),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Return the files and exact per-node commands that would be run, without changing anything. Default: false."),
+		),
+		mcp.WithBoolean("rollback_on_failure",
+			mcp.Description("If any node command fails, remove the written registry config from all nodes "+
+				"and restart containerd, leaving the cluster as it was. Default: false."),
+		),
 	)
 	s.AddTool(mirrorTool, r.handleConfigureRegistryMirrors)
+
+	removeMirrorTool := mcp.NewTool("remove_registry_mirrors",
+		destructiveHints,
+		mcp.WithDescription(
+			"Remove registry mirrors from a running Kind cluster, e.g. to undo a mis-typed mirror endpoint set "+
+				"with configure_registry_mirrors. Deletes the registries' hosts.toml directories on each node and "+
+				"restarts containerd, so pulls go to the upstream registries again."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithArray("registries",
+			mcp.Required(),
+			mcp.WithStringItems(),
+			mcp.Description("Registries whose mirrors to remove, as given in 'original' (e.g. ['docker.io', 'localhost:5000'])"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Return the exact per-node commands that would be run, without changing anything. Default: false."),
+		),
+	)
+	s.AddTool(removeMirrorTool, r.handleRemoveRegistryMirrors)
+
+	getMirrorsTool := mcp.NewTool("get_registry_mirrors",
+		readOnlyHints,
+		mcp.WithDescription(
+			"List the registry mirrors containerd currently uses on each node of a Kind cluster: the hosts.toml "+
+				"entries under /etc/containerd/certs.d (mirror URLs in the order tried, capabilities, TLS settings, "+
+				"whether credentials are set) and whether containerd's config_path points there. Flags nodes whose "+
+				"mirrors differ from the first control-plane node."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		verbosityParam(),
+	)
+	s.AddTool(getMirrorsTool, r.handleGetRegistryMirrors)
+
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(localRegistryURIPrefix+"{cluster}", "Local registry",
+			mcp.WithTemplateDescription("Where to push images for a Kind cluster's local registry (e.g. "+
+				"localhost:5001), how nodes reach it, push commands and Helm values; the same as "+
+				"'get_local_registry_info'."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		r.handleReadLocalRegistry,
+	)
+
+	localRegistryTool := mcp.NewTool("get_local_registry_info",
+		readOnlyHints,
+		mcp.WithDescription(
+			"Get a Kind cluster's local registry: the host address to tag and push images to (e.g. "+
+				"localhost:5001), the address the nodes pull it from, example tag and push commands, how to "+
+				"reference pushed images in manifests, and a Helm values file for the cluster's kube context. "+
+				"Reads the kube-public/local-registry-hosting ConfigMap, which configure_registry_mirrors "+
+				"publishes for a localhost mirror, or the nodes' mirror config. Also available as the "+
+				localRegistryURIPrefix+"<cluster> resource."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+	)
+	s.AddTool(localRegistryTool, r.handleGetLocalRegistryInfo)
+
+	verifyTool := mcp.NewTool("verify_registry_mirrors",
+		remoteUpdateHints,
+		mcp.WithDescription(
+			"Verify configured registry mirrors by pulling a test image from each overridden registry "+
+				"on a cluster node with ctr in debug mode, and checking its resolver output for requests to the "+
+				"mirror. The pull goes to a separate containerd namespace and is removed afterwards, so the "+
+				"node's Kubernetes images are untouched. served_by_mirror is null when the output does not tell."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to verify"),
+		mcp.WithString("overrides",
+			mcp.Required(),
+			mcp.Description(
+				"JSON array of registry overrides, same format as configure_registry_mirrors. "+
+					"Example: [{\"original\":\"docker.io\",\"mirror\":\"http://localhost:5000\"}]"),
+		),
+		mcp.WithString("test_images",
+			mcp.Description(
+				"JSON object mapping a registry to the image to pull (e.g. {\"registry.corp\":\"registry.corp/team/app:1.0\"}). "+
+					"docker.io, registry.k8s.io, quay.io and ghcr.io have defaults."),
+		),
+	)
+	s.AddTool(verifyTool, r.handleVerifyRegistryMirrors)
+
+	cloudTool := mcp.NewTool("refresh_cloud_credentials",
+		updateHints,
+		mcp.WithDescription(
+			"Fetch fresh short-lived tokens for cloud registries (ECR, GCR/Artifact Registry, ACR) from their "+
+				"Docker credential helpers (docker-credential-ecr-login, -gcloud, -acr-env, ...) on the host and "+
+				"install them in a Kind cluster as an inline config: kubelet's config.json on every node, and/or a "+
+				"dockerconfigjson pull secret. Set interval_minutes to keep refreshing in the background so pulls "+
+				"do not fail after the tokens expire."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithArray("registries",
+			mcp.WithStringItems(),
+			mcp.Description("Registries to refresh (e.g. ['123456789012.dkr.ecr.eu-west-1.amazonaws.com']). Each uses "+
+				"its credHelpers entry from the host credential files, or the usual helper for its host. "+
+				"Default: every registry with a credHelpers entry."),
+		),
+		mcp.WithString("target",
+			mcp.Enum(registry.CloudTargetNodes, registry.CloudTargetPullSecret, registry.CloudTargetBoth),
+			mcp.Description("Where to install the credentials: 'nodes' (kubelet config.json, used for every pull), "+
+				"'pull_secret' (a Secret to reference in imagePullSecrets), or 'both'. Default: nodes."),
+		),
+		mcp.WithArray("namespaces",
+			mcp.WithStringItems(),
+			mcp.Description("Namespaces that get the pull secret. Default: the cluster's default namespace, or default."),
+		),
+		mcp.WithString("secret_name",
+			mcp.Description("Name of the pull secret. Default: "+registry.DefaultPullSecretName+"."),
+		),
+		mcp.WithNumber("interval_minutes",
+			mcp.Description("Refresh again every this many minutes while the server runs (at least 1), replacing "+
+				"any earlier schedule for the cluster; 0 stops the schedule. Default: refresh once."),
+		),
+	)
+	s.AddTool(cloudTool, r.handleRefreshCloudCredentials)
 }
 
-func (r *Registry) handleDetectCredentials(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Debug("tool called: detect_credentials")
+func (r *Registry) handleDetectCredentials(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: detect_credentials")
 	ri := r.runtimeInfo(ctx)
-	credInfo, err := registry.FindCredentials(ri)
+	var credInfo *registry.CredentialInfo
+	var err error
+	if val, ok := request.GetArguments()["merge"].(bool); ok && val {
+		credInfo, _, err = registry.MergeCredentials(ri)
+	} else {
+		credInfo, err = registry.FindCredentials(ri)
+	}
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("credential discovery failed: %v", err)), nil
 	}
+	if summaryRequested(request) {
+		return linesResult(credInfo.Lines())
+	}
 	return jsonResult(credInfo)
 }
 
 func (r *Registry) handleConfigureRegistryMirrors(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	r.logger.Info("tool called: configure_registry_mirrors")
-	clusterName, err := request.RequireString("cluster_name")
-	if err != nil {
-		return mcp.NewToolResultError("parameter 'cluster_name' is required"), nil
+	r.log(ctx).Info("tool called: configure_registry_mirrors")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	overridesJSON, err := request.RequireString("overrides")
@@ -79,8 +227,11 @@ func (r *Registry) handleConfigureRegistryMirrors(ctx context.Context, request m
 
 	var credInfo *registry.CredentialInfo
 	if val, ok := request.GetArguments()["include_credentials"].(bool); ok && val {
-		ri := r.runtimeInfo(ctx)
-		credInfo, _ = registry.FindCredentials(ri)
+		merge, _ := request.GetArguments()["merge_credentials"].(bool)
+		credInfo, err = r.mountableCredentials(ctx, merge)
+		if err != nil {
+			r.log(ctx).Warn("credential discovery failed", "error", err)
+		}
 	}
 
 	mirrorCfg, err := registry.GenerateMirrorConfig(overrides, credInfo)
@@ -89,13 +240,359 @@ func (r *Registry) handleConfigureRegistryMirrors(ctx context.Context, request m
 	}
 
 	mgr := r.kindManager(ctx)
-	results, err := registry.ApplyMirrorConfig(ctx, mgr, clusterName, mirrorCfg)
+	if val, ok := request.GetArguments()["dry_run"].(bool); ok && val {
+		plan, err := registry.PlanMirrorConfig(ctx, mgr, clusterName, mirrorCfg)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("dry run failed: %v", err)), nil
+		}
+		return jsonResult(map[string]any{
+			"files":    mirrorCfg.Files,
+			"commands": plan,
+		})
+	}
+
+	opts := registry.ApplyOptions{}
+	if val, ok := request.GetArguments()["rollback_on_failure"].(bool); ok {
+		opts.RollbackOnFailure = val
+	}
+
+	result, err := registry.ApplyMirrorConfig(ctx, mgr, clusterName, mirrorCfg, opts)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to apply mirror config: %v", err)), nil
 	}
 
-	output := fmt.Sprintf("Registry mirror configuration applied to cluster %q.\n\nResults:\n%s",
-		clusterName, strings.Join(results, "\n"))
+	var published string
+	if !result.RolledBack {
+		r.recordMirrors(ctx, clusterName, overrides)
+		if local, ok := registry.LocalRegistryOverride(overrides); ok {
+			if err := registry.PublishLocalRegistry(ctx, mgr, clusterName, local); err != nil {
+				r.log(ctx).Warn("failed to publish local registry", "cluster", clusterName, "error", err)
+			} else {
+				published = fmt.Sprintf("\n\nPublished %s as the cluster's local registry in kube-public/local-registry-hosting; "+
+					"call 'get_local_registry_info' for push commands and Helm values.", local.Original)
+			}
+		}
+	}
+
+	output := fmt.Sprintf("Registry mirror configuration applied to cluster %q.\n\nResults:\n%s\n\nNode states:\n%s",
+		clusterName, strings.Join(result.Results, "\n"), formatNodeStates(result.NodeStates))
+	switch {
+	case result.RolledBack:
+		output = fmt.Sprintf("Registry mirror configuration failed on cluster %q and was rolled back.\n\nResults:\n%s\n\nNode states:\n%s",
+			clusterName, strings.Join(result.Results, "\n"), formatNodeStates(result.NodeStates))
+	case result.Failed:
+		output += "\n\nSome commands failed and nodes may be inconsistent. " +
+			"Re-run with rollback_on_failure=true to undo partial changes automatically."
+	}
+	if len(result.Verification) > 0 {
+		output += "\n\nVerification:\n" + formatVerification(result.Verification)
+	}
+	output += published
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (r *Registry) handleRemoveRegistryMirrors(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: remove_registry_mirrors")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	registries := request.GetStringSlice("registries", nil)
+	if len(registries) == 0 {
+		return mcp.NewToolResultError("parameter 'registries' is required"), nil
+	}
+
+	mgr := r.kindManager(ctx)
+	if val, ok := request.GetArguments()["dry_run"].(bool); ok && val {
+		plan, err := registry.PlanMirrorRemoval(ctx, mgr, clusterName, registries)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("dry run failed: %v", err)), nil
+		}
+		return jsonResult(map[string]any{"commands": plan})
+	}
+
+	result, err := registry.RemoveMirrorConfig(ctx, mgr, clusterName, registries)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to remove mirror config: %v", err)), nil
+	}
+	r.forgetMirrors(ctx, clusterName, registries)
 
+	output := fmt.Sprintf("Registry mirrors for %s removed from cluster %q.\n\nResults:\n%s\n\nNode states:\n%s",
+		strings.Join(registries, ", "), clusterName, strings.Join(result.Results, "\n"), formatNodeStates(result.NodeStates))
+	if result.Failed {
+		output += "\n\nSome commands failed and nodes may still use the mirrors; re-run remove_registry_mirrors."
+	}
+	if len(result.Verification) > 0 {
+		output += "\n\nVerification:\n" + formatVerification(result.Verification)
+	}
 	return mcp.NewToolResultText(output), nil
 }
+
+func (r *Registry) handleGetRegistryMirrors(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: get_registry_mirrors")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	report, err := registry.GetMirrors(ctx, r.kindManager(ctx), clusterName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read registry mirrors: %v", err)), nil
+	}
+	if summaryRequested(request) {
+		return linesResult(report.Lines())
+	}
+	return jsonResult(report)
+}
+
+func (r *Registry) handleGetLocalRegistryInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Debug("tool called: get_local_registry_info")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	reg, err := r.localRegistry(ctx, clusterName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get local registry: %v", err)), nil
+	}
+	return jsonResult(reg)
+}
+
+func (r *Registry) handleReadLocalRegistry(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	r.log(ctx).Debug("resource read", "uri", request.Params.URI)
+	name := strings.TrimPrefix(request.Params.URI, localRegistryURIPrefix)
+	if name == "" || name == request.Params.URI {
+		return nil, fmt.Errorf("invalid local registry URI %s", request.Params.URI)
+	}
+	reg, err := r.localRegistry(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      request.Params.URI,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
+
+// localRegistry looks up a cluster's local registry with the context name recorded by
+// set_cluster_defaults and push commands for the detected runtime.
+func (r *Registry) localRegistry(ctx context.Context, clusterName string) (*registry.LocalRegistry, error) {
+	_, contextName := r.clusterDefaults(ctx, clusterName)
+	runtimeBin := "docker"
+	if r.runtimeInfo(ctx).Runtime == rtdetect.RuntimePodman {
+		runtimeBin = "podman"
+	}
+	return registry.GetLocalRegistry(ctx, r.kindManager(ctx), clusterName, contextName, runtimeBin)
+}
+
+// cloudRefreshResponse is the result of refresh_cloud_credentials.
+type cloudRefreshResponse struct {
+	*registry.CloudRefreshResult
+	Schedule string `json:"schedule"`
+}
+
+func (r *Registry) handleRefreshCloudCredentials(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: refresh_cloud_credentials")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	var interval time.Duration
+	intervalSet := false
+	if minutes, err := request.RequireFloat("interval_minutes"); err == nil {
+		if minutes != 0 && minutes < 1 {
+			return mcp.NewToolResultError("parameter 'interval_minutes' must be 0 or at least 1"), nil
+		}
+		interval, intervalSet = time.Duration(minutes*float64(time.Minute)), true
+	}
+
+	helpers := registry.CloudHelpers(r.runtimeInfo(ctx))
+	opts := registry.CloudRefreshOptions{
+		Registries: make(map[string]string),
+		Helpers:    helpers,
+		Target:     request.GetString("target", ""),
+		Namespaces: request.GetStringSlice("namespaces", nil),
+		SecretName: request.GetString("secret_name", ""),
+	}
+	if regs := request.GetStringSlice("registries", nil); len(regs) > 0 {
+		for _, reg := range regs {
+			opts.Registries[reg] = ""
+		}
+	} else {
+		opts.Registries = helpers
+	}
+	if len(opts.Namespaces) == 0 {
+		if ns, _ := r.clusterDefaults(ctx, clusterName); ns != "" {
+			opts.Namespaces = []string{ns}
+		}
+	}
+
+	result, err := registry.RefreshCloudCredentials(ctx, r.kindManager(ctx), r.runner, clusterName, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to refresh cloud credentials: %v", err)), nil
+	}
+
+	resp := cloudRefreshResponse{CloudRefreshResult: result, Schedule: "once"}
+	if intervalSet {
+		r.scheduleCloudRefresh(clusterName, interval, opts)
+	}
+	if current := r.cloudRefreshInterval(clusterName); current > 0 {
+		resp.Schedule = fmt.Sprintf("every %s", current)
+	}
+	return jsonResult(resp)
+}
+
+// scheduleCloudRefresh replaces the background credential refresh for a cluster with
+// one that runs every interval, or stops it if interval is zero.
+func (r *Registry) scheduleCloudRefresh(clusterName string, interval time.Duration, opts registry.CloudRefreshOptions) {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+	if prev, ok := r.cloudRefreshes[clusterName]; ok {
+		prev.cancel()
+		delete(r.cloudRefreshes, clusterName)
+	}
+	if interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cloudRefreshes[clusterName] = cloudRefresh{cancel: cancel, interval: interval}
+	logger := r.logger.With("cluster", clusterName)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			result, err := registry.RefreshCloudCredentials(ctx, r.kindManager(ctx), r.runner, clusterName, opts)
+			switch {
+			case err != nil:
+				logger.Warn("scheduled cloud credential refresh failed", "error", err)
+			case len(result.Errors) > 0:
+				logger.Warn("scheduled cloud credential refresh was partial", "errors", result.Errors)
+			default:
+				logger.Info("refreshed cloud credentials", "registries", len(result.Credentials))
+			}
+		}
+	}()
+}
+
+// cloudRefreshInterval returns the interval of a cluster's background credential
+// refresh, or zero if none is scheduled.
+func (r *Registry) cloudRefreshInterval(clusterName string) time.Duration {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+	return r.cloudRefreshes[clusterName].interval
+}
+
+// mountableCredentials discovers host registry credentials for mounting into nodes.
+// With merge, every credential file is combined into one temporary config file.
+func (r *Registry) mountableCredentials(ctx context.Context, merge bool) (*registry.CredentialInfo, error) {
+	ri := r.runtimeInfo(ctx)
+	if !merge {
+		return registry.FindCredentials(ri)
+	}
+	credInfo, merged, err := registry.MergeCredentials(ri)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := registry.WriteCredentials(credInfo, merged); err != nil {
+		return nil, err
+	}
+	return credInfo, nil
+}
+
+// recordMirrors adds the overridden registries to the cluster's state so that
+// 'diff_cluster_config' can tell recorded mirrors from drift.
+func (r *Registry) recordMirrors(ctx context.Context, clusterName string, overrides []registry.RegistryOverride) {
+	err := r.store.Update(clusterName, func(rec *state.ClusterRecord) {
+		for _, o := range overrides {
+			if !slices.Contains(rec.Mirrors, o.Original) {
+				rec.Mirrors = append(rec.Mirrors, o.Original)
+			}
+		}
+		sort.Strings(rec.Mirrors)
+	})
+	if err != nil {
+		r.log(ctx).Warn("failed to record cluster state", "cluster", clusterName, "error", err)
+	}
+}
+
+// forgetMirrors removes registries from the mirrors recorded in the cluster's state.
+func (r *Registry) forgetMirrors(ctx context.Context, clusterName string, registries []string) {
+	err := r.store.Update(clusterName, func(rec *state.ClusterRecord) {
+		rec.Mirrors = slices.DeleteFunc(rec.Mirrors, func(m string) bool { return slices.Contains(registries, m) })
+	})
+	if err != nil {
+		r.log(ctx).Warn("failed to record cluster state", "cluster", clusterName, "error", err)
+	}
+}
+
+func formatNodeStates(states map[string]string) string {
+	nodes := make([]string, 0, len(states))
+	for node := range states {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	lines := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		lines = append(lines, fmt.Sprintf("%s: %s", node, states[node]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatVerification(results []registry.NodeVerification) string {
+	lines := make([]string, 0, len(results))
+	for _, v := range results {
+		if v.Verified {
+			lines = append(lines, fmt.Sprintf("%s: containerd serving with mirror config active", v.Node))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: NOT verified: %s", v.Node, strings.Join(v.Problems, "; ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Registry) handleVerifyRegistryMirrors(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: verify_registry_mirrors")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	overridesJSON, err := request.RequireString("overrides")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'overrides' is required"), nil
+	}
+
+	var overrides []registry.RegistryOverride
+	if err := json.Unmarshal([]byte(overridesJSON), &overrides); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid 'overrides' JSON: %v", err)), nil
+	}
+
+	var testImages map[string]string
+	if raw := request.GetString("test_images", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &testImages); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid 'test_images' JSON: %v", err)), nil
+		}
+	}
+
+	mgr := r.kindManager(ctx)
+	results, err := registry.VerifyMirrors(ctx, mgr, clusterName, overrides, testImages)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to verify mirrors: %v", err)), nil
+	}
+
+	return jsonResult(map[string]any{"results": results})
+}
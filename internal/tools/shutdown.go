@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+// Shutdown stops the Registry: new tool calls are refused, the contexts of the calls
+// in flight are cancelled, which kills the commands they run, and the background jobs
+// (health watches and scheduled cloud credential refreshes) are stopped. It then waits
+// for the cancelled calls to return, e.g. after deleting a partially created cluster,
+// until ctx is done.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.callsMu.Lock()
+	r.stop()
+	r.callsMu.Unlock()
+
+	r.watchMu.Lock()
+	for key, w := range r.healthWatches {
+		w.cancel()
+		delete(r.healthWatches, key)
+	}
+	r.watchMu.Unlock()
+
+	r.refreshMu.Lock()
+	for cluster, refresh := range r.cloudRefreshes {
+		refresh.cancel()
+		delete(r.cloudRefreshes, cluster)
+	}
+	r.refreshMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tool calls still running after the grace period: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerStorageTools(s *server.MCPServer) {
+	provisionTool := mcp.NewTool("provision_host_storage",
+		updateHints,
+		mcp.WithDescription(
+			"Store a cluster's PersistentVolume data in its host storage directory, so it survives the node "+
+				"containers (e.g. across 'recreate_cluster') and can be inspected on the host. The cluster must have "+
+				"been created from a config generated with host_storage=true. Mode 'local-path' points Kind's "+
+				"local-path-provisioner (the default 'standard' StorageClass) at the directory; mode 'hostpath' installs "+
+				"a StorageClass without a provisioner and a set of static hostPath PersistentVolumes in it."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster"),
+		mcp.WithString("mode",
+			mcp.Enum(kind.HostStorageLocalPath, kind.HostStorageHostPath),
+			mcp.Description("How volumes are provisioned. Default: local-path."),
+		),
+		mcp.WithString("storage_class",
+			mcp.Description("StorageClass name for mode 'hostpath'. Default: "+kind.DefaultHostStorageClass+"."),
+		),
+		mcp.WithNumber("volumes",
+			mcp.Description(fmt.Sprintf("Number of PersistentVolumes for mode 'hostpath'. Default: %d.",
+				kind.DefaultHostStorageVolumes)),
+		),
+		mcp.WithString("volume_size",
+			mcp.Description("Capacity of each PersistentVolume for mode 'hostpath'. Default: "+kind.DefaultHostStorageSize+"."),
+		),
+	)
+	s.AddTool(provisionTool, r.handleProvisionHostStorage)
+}
+
+func (r *Registry) handleProvisionHostStorage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: provision_host_storage")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	opts := kind.HostStorageOptions{
+		Mode:         request.GetString("mode", ""),
+		StorageClass: request.GetString("storage_class", ""),
+		VolumeSize:   request.GetString("volume_size", ""),
+	}
+	if v, err := request.RequireFloat("volumes"); err == nil {
+		opts.Volumes = int(v)
+	}
+
+	result, err := r.kindManager(ctx).ProvisionHostStorage(ctx, clusterName, opts)
+	if err != nil {
+		var steps []string
+		if result != nil {
+			steps = result.Steps
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to provision host storage: %v\n\nCompleted steps:\n%s",
+			err, strings.Join(steps, "\n"))), nil
+	}
+	return jsonResult(result)
+}
+
+// managedHostStorage returns the managed host storage directory of a cluster: the one
+// its recorded config mounts, if it is inside the storage directory and exists. Other
+// clusters have none and yield "".
+func (r *Registry) managedHostStorage(cluster string) string {
+	rec, err := r.store.Get(cluster)
+	if err != nil || rec == nil || rec.Config == "" {
+		return ""
+	}
+	dir := kind.HostStorageDir(rec.Config)
+	if dir == "" {
+		return ""
+	}
+	if !kind.InStorageDir(r.storageDir, dir) {
+		return ""
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	return dir
+}
@@ -1,45 +1,267 @@
 package tools
 
 import (
+	"cmp"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/auth"
 	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/limiter"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/logging"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/output"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/quota"
 	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/settings"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/state"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/tracing"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // Registry holds shared dependencies for tool handlers.
 type Registry struct {
-	logger   *slog.Logger
-	runner   rtdetect.CommandRunner
-	detector *rtdetect.Detector
+	logger       *slog.Logger
+	runner       rtdetect.CommandRunner
+	detector     *rtdetect.Detector
+	store        *state.Store
+	outputs      *output.Store
+	kubectlVerbs []string
+	kindBackend  string
+	// nodeImageRepo is the default node image repository (MCP_KIND_NODE_IMAGE_REPOSITORY).
+	nodeImageRepo string
+	// configDir keeps each cluster's create config (MCP_KIND_CONFIG_DIR).
+	configDir string
+	// storageDir holds each cluster's host storage directory (MCP_KIND_STORAGE_DIR).
+	storageDir string
+	// heavy queues heavy operations beyond MCP_KIND_MAX_HEAVY_OPS.
+	heavy *limiter.Limiter
+	// quotas bound cluster creation; see admitCluster.
+	quotas *quota.Enforcer
+	// settings are the defaults from the settings file; never nil.
+	settings *settings.Settings
+	// enforceOwnership restricts HTTP clients to the clusters they own
+	// (MCP_KIND_ENFORCE_OWNERSHIP); see checkOwnership.
+	enforceOwnership bool
+	// ownedParams maps the tools checkOwnership guards to their cluster parameter; set
+	// by RegisterAll from ownedClusterParams.
+	ownedParams map[string]string
+	// version is the running server version, compared with releases by check_server_update.
+	version string
+	// allowSelfUpdate enables update_server (MCP_KIND_ALLOW_SELF_UPDATE).
+	allowSelfUpdate bool
+
+	refreshMu      sync.Mutex
+	cloudRefreshes map[string]cloudRefresh // by cluster name
+
+	callsMu sync.Mutex
+	calls   map[string]context.CancelFunc // in-flight tool calls; see trackCall
+	active  sync.WaitGroup                // every in-flight tool call
+	stopCtx context.Context               // cancelled by Shutdown
+	stop    context.CancelFunc
+
+	watchMu       sync.Mutex
+	healthWatches map[string]*healthWatch // by session/cluster
+
+	// current is the cluster tools use when a call names none, by session ID; see
+	// use_cluster. Sessions without an entry use defaultCurrent (KIND_CLUSTER_NAME).
+	currentMu      sync.Mutex
+	current        map[string]currentSelection
+	defaultCurrent currentSelection
 }
 
-// NewRegistry creates a new tool Registry.
-func NewRegistry(logger *slog.Logger) *Registry {
+// cloudRefresh is a background refresh_cloud_credentials schedule.
+type cloudRefresh struct {
+	cancel   context.CancelFunc
+	interval time.Duration
+}
+
+// NewRegistry creates a new tool Registry for the server build version. Defaults come
+// from the environment first, then from cfg (which may be nil).
+func NewRegistry(logger *slog.Logger, cfg *settings.Settings, version string) *Registry {
 	if logger == nil {
 		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
-	runner := &rtdetect.ExecCommandRunner{}
-	return &Registry{
-		logger:   logger,
-		runner:   runner,
-		detector: rtdetect.NewDetector(runner),
+	if cfg == nil {
+		cfg = &settings.Settings{}
+	}
+	runner := &rtdetect.EnvRunner{Next: &tracing.Runner{Next: &rtdetect.ExecCommandRunner{}}, Env: commandEnv()}
+	detector := rtdetect.NewDetector(runner)
+	detector.Prefer(rtdetect.Runtime(cfg.Runtime))
+	stateDir := state.DefaultDir()
+	if os.Getenv("MCP_KIND_STATE_DIR") == "" && cfg.StateDir != "" {
+		stateDir = cfg.StateDir
+	}
+	stopCtx, stop := context.WithCancel(context.Background())
+	r := &Registry{
+		logger:        logger,
+		runner:        runner,
+		detector:      detector,
+		store:         state.NewStore(stateDir),
+		outputs:       output.NewStore(),
+		kubectlVerbs:  kubectlVerbs(cfg.KubectlAllowedVerbs),
+		kindBackend:   cmp.Or(os.Getenv("MCP_KIND_BACKEND"), cfg.Backend),
+		nodeImageRepo: cmp.Or(os.Getenv("MCP_KIND_NODE_IMAGE_REPOSITORY"), cfg.NodeImageRepository),
+		configDir:     cmp.Or(os.Getenv("MCP_KIND_CONFIG_DIR"), cfg.ConfigDir),
+		storageDir:    cmp.Or(os.Getenv("MCP_KIND_STORAGE_DIR"), cfg.StorageDir, filepath.Join(stateDir, "storage")),
+		heavy:         limiter.New(heavyOpsLimit(logger, cfg.MaxHeavyOps)),
+		quotas:        quota.New(quotaLimits(logger, cfg.Quotas)),
+		settings:      cfg,
+
+		enforceOwnership: enforceOwnershipFromEnv(logger),
+		version:          version,
+		allowSelfUpdate:  allowSelfUpdateFromEnv(logger),
+
+		cloudRefreshes: make(map[string]cloudRefresh),
+		calls:          make(map[string]context.CancelFunc),
+		stopCtx:        stopCtx,
+		stop:           stop,
+		healthWatches:  make(map[string]*healthWatch),
+		current:        make(map[string]currentSelection),
+	}
+	if name := os.Getenv("KIND_CLUSTER_NAME"); name != "" {
+		r.defaultCurrent = currentSelection{name: name, source: currentFromEnv}
 	}
+	return r
 }
 
-// RegisterAll registers all tools on the given MCP server.
+// commandEnvPrefix marks server environment variables passed on to every command
+// without the prefix: MCP_KIND_ENV_DOCKER_HOST=... runs docker, kind, and kubectl with
+// DOCKER_HOST=... while the server's own environment stays untouched.
+const commandEnvPrefix = "MCP_KIND_ENV_"
+
+// commandEnv returns the environment configured with commandEnvPrefix, sorted by name.
+func commandEnv() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if rest, ok := strings.CutPrefix(e, commandEnvPrefix); ok && !strings.HasPrefix(rest, "=") {
+			env = append(env, rest)
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+// RegisterAll registers all tools, the resources they refer to, and the workflow prompts
+// on the given MCP server.
 func (r *Registry) RegisterAll(s *server.MCPServer) {
 	r.registerDetectTools(s)
 	r.registerConfigTools(s)
 	r.registerClusterTools(s)
 	r.registerKubeconfigTools(s)
 	r.registerRegistryTools(s)
+	r.registerImageTools(s)
+	r.registerWorkloadTools(s)
+	r.registerStorageTools(s)
+	r.registerAddonTools(s)
+	r.registerKubectlTools(s)
+	r.registerHealthTools(s)
+	r.registerChaosTools(s)
+	r.registerUpdateTools(s)
+	r.registerOutputResources(s)
+	r.registerCancellation(s)
+	r.registerPrompts(s)
+	r.applyToolAllowlist(s)
+	r.ownedParams = ownedClusterParams(s.ListTools())
+}
+
+// applyToolAllowlist removes the tools the settings file does not list, if it lists any.
+func (r *Registry) applyToolAllowlist(s *server.MCPServer) {
+	allowed := r.settings.Tools
+	if len(allowed) == 0 {
+		return
+	}
+	registered := s.ListTools()
+	for _, name := range allowed {
+		if _, ok := registered[name]; !ok {
+			r.logger.Warn("settings file allows an unknown tool", "tool", name)
+		}
+	}
+	var removed []string
+	for name := range registered {
+		if !slices.Contains(allowed, name) {
+			removed = append(removed, name)
+		}
+	}
+	s.DeleteTools(removed...)
+	r.logger.Info("tool allowlist applied", "tools", len(registered)-len(removed), "removed", len(removed))
+}
+
+// ToolMiddleware wraps every tool call in a span and gives it a logger annotated with
+// the tool name, a generated request ID, the trace ID when tracing is enabled and,
+// when there are, the client session ID and HTTP client name, so all records and
+// command spans of one call (including those from kind.Manager) can be correlated.
+// Calls on a cluster another HTTP client owns are refused (see checkOwnership). The
+// call's context is cancelled when the client cancels the request (see Hooks) or the
+// server shuts down (see Shutdown). Install it with server.WithToolHandlerMiddleware.
+func (r *Registry) ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, done, ok := r.trackCall(ctx, request)
+		defer done()
+		if !ok {
+			return mcp.NewToolResultError("the server is shutting down"), nil
+		}
+		requestID := newRequestID()
+		ctx, span := tracing.Tracer().Start(ctx, "tool "+request.Params.Name)
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("mcp.tool.name", request.Params.Name),
+			attribute.String("mcp_kind.request_id", requestID),
+		)
+
+		attrs := []any{"tool", request.Params.Name, "request_id", requestID}
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			attrs = append(attrs, "trace_id", traceID)
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+			attrs = append(attrs, "session_id", session.SessionID())
+			span.SetAttributes(attribute.String("mcp.session.id", session.SessionID()))
+		}
+		if id, ok := auth.FromContext(ctx); ok && id.Name != "" {
+			attrs = append(attrs, "client", id.Name)
+		}
+		ctx = logging.NewContext(ctx, r.logger.With(attrs...))
+
+		if errResult := r.checkOwnership(ctx, request); errResult != nil {
+			span.SetStatus(codes.Error, "cluster change denied")
+			return errResult, nil
+		}
+		result, err := next(ctx, request)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, "tool returned an error result")
+		}
+		return result, err
+	}
+}
+
+// newRequestID returns a short random ID for correlating the log records of one call.
+// mcp-go does not pass the JSON-RPC request ID to tool handlers.
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// log returns the logger for the current tool call.
+func (r *Registry) log(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, r.logger)
 }
 
 func (r *Registry) runtimeInfo(ctx context.Context) rtdetect.RuntimeInfo {
@@ -48,7 +270,167 @@ func (r *Registry) runtimeInfo(ctx context.Context) rtdetect.RuntimeInfo {
 
 func (r *Registry) kindManager(ctx context.Context) *kind.Manager {
 	ri := r.runtimeInfo(ctx)
-	return kind.NewManager(r.runner, ri, r.logger)
+	var mgr *kind.Manager
+	if r.kindBackend == "library" {
+		mgr = kind.NewLibraryManager(r.runner, ri, r.log(ctx))
+	} else {
+		mgr = kind.NewManager(r.runner, ri, r.log(ctx))
+	}
+	mgr.SetConfigDir(r.configDir)
+	return mgr
+}
+
+// clusterDefaults returns the default namespace and context recorded for a cluster
+// with 'set_cluster_defaults', or empty strings if there are none.
+func (r *Registry) clusterDefaults(ctx context.Context, clusterName string) (namespace, contextName string) {
+	rec, err := r.store.Get(clusterName)
+	if err != nil {
+		r.log(ctx).Warn("failed to read cluster state", "cluster", clusterName, "error", err)
+		return "", ""
+	}
+	if rec == nil {
+		return "", ""
+	}
+	return rec.Namespace, rec.Context
+}
+
+// Sources of the current cluster.
+const (
+	currentFromEnv = "KIND_CLUSTER_NAME"
+	currentFromUse = "use_cluster"
+)
+
+// currentSelection is a current cluster and where it was set.
+type currentSelection struct {
+	name, source string
+}
+
+// sessionKey returns the ID of the client session making a call, or "" without one.
+func sessionKey(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// currentCluster returns the current cluster of the calling session and where it was
+// set, or empty strings.
+func (r *Registry) currentCluster(ctx context.Context) (name, source string) {
+	r.currentMu.Lock()
+	defer r.currentMu.Unlock()
+	sel, ok := r.current[sessionKey(ctx)]
+	if !ok {
+		sel = r.defaultCurrent
+	}
+	return sel.name, sel.source
+}
+
+// setCurrentCluster makes name the current cluster of the calling session; an empty
+// name clears it, without falling back to KIND_CLUSTER_NAME.
+func (r *Registry) setCurrentCluster(ctx context.Context, name, source string) {
+	r.currentMu.Lock()
+	defer r.currentMu.Unlock()
+	if name == "" {
+		source = ""
+	}
+	r.current[sessionKey(ctx)] = currentSelection{name: name, source: source}
+}
+
+// clearCurrentCluster clears name wherever it is the current cluster, e.g. after
+// deleting it.
+func (r *Registry) clearCurrentCluster(name string) {
+	r.currentMu.Lock()
+	defer r.currentMu.Unlock()
+	for key, sel := range r.current {
+		if sel.name == name {
+			r.current[key] = currentSelection{}
+		}
+	}
+	if r.defaultCurrent.name == name {
+		r.defaultCurrent = currentSelection{}
+	}
+}
+
+// forgetCurrentCluster drops the current cluster of a session that ended.
+func (r *Registry) forgetCurrentCluster(sessionID string) {
+	r.currentMu.Lock()
+	defer r.currentMu.Unlock()
+	delete(r.current, sessionID)
+}
+
+// clusterNameParam declares a cluster name parameter that may be omitted when a
+// current cluster is set; handlers read it with clusterParam.
+func clusterNameParam(param, description string) mcp.ToolOption {
+	return mcp.WithString(param,
+		mcp.Description(description+". Default: the current cluster (use_cluster or KIND_CLUSTER_NAME)."),
+	)
+}
+
+// clusterParam returns the cluster named by param, falling back to the current
+// cluster, or an error result when neither is set.
+func (r *Registry) clusterParam(ctx context.Context, request mcp.CallToolRequest, param string) (string, *mcp.CallToolResult) {
+	if name := request.GetString(param, ""); name != "" {
+		return name, nil
+	}
+	if name, _ := r.currentCluster(ctx); name != "" {
+		return name, nil
+	}
+	return "", mcp.NewToolResultError(fmt.Sprintf(
+		"parameter '%s' is required when no current cluster is set; select one with 'use_cluster'", param))
+}
+
+// namespaceParam returns the 'namespace' parameter of request, falling back to the
+// cluster's default namespace.
+func (r *Registry) namespaceParam(ctx context.Context, request mcp.CallToolRequest, clusterName string) string {
+	if ns := request.GetString("namespace", ""); ns != "" {
+		return ns
+	}
+	ns, _ := r.clusterDefaults(ctx, clusterName)
+	return ns
+}
+
+// progressReporter returns a function that forwards command output lines to the client
+// as progress notifications for request, or nil if the client did not ask for progress.
+func (r *Registry) progressReporter(ctx context.Context, request mcp.CallToolRequest) func(string) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil
+	}
+	token := request.Params.Meta.ProgressToken
+	var progress float64
+	return func(line string) {
+		progress++
+		err := srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      progress,
+			"message":       line,
+		})
+		if err != nil {
+			r.log(ctx).Debug("sending progress notification failed", "error", err)
+		}
+	}
+}
+
+// kubectlVerbs returns the verb allowlist for the kubectl tool: the comma-separated
+// KUBECTL_ALLOWED_VERBS environment variable, else the configured verbs, else the defaults.
+func kubectlVerbs(configured []string) []string {
+	env := os.Getenv("KUBECTL_ALLOWED_VERBS")
+	if env == "" {
+		if len(configured) > 0 {
+			return configured
+		}
+		return kind.DefaultKubectlVerbs
+	}
+	var verbs []string
+	for _, v := range strings.Split(env, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			verbs = append(verbs, v)
+		}
+	}
+	return verbs
 }
 
 func jsonResult(v any) (*mcp.CallToolResult, error) {
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/auth"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/selfupdate"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerUpdateTools(s *server.MCPServer) {
+	checkTool := mcp.NewTool("check_server_update",
+		remoteReadOnlyHints,
+		mcp.WithDescription(
+			"Compare the running mcp-kind-manager version with its latest GitHub release, and report whether "+
+				"a newer one is available, with its release notes URL and the binary for this platform."),
+	)
+	s.AddTool(checkTool, r.handleCheckServerUpdate)
+
+	updateTool := mcp.NewTool("update_server",
+		remoteDestructiveHints,
+		mcp.WithDescription(
+			"Replace this server's binary with the latest GitHub release for this platform, verified against the "+
+				"release's checksums.txt; the previous binary is kept next to it with a '.old' suffix. The new version "+
+				"runs after the MCP client restarts the server. Only for manually installed binaries: disabled unless "+
+				"MCP_KIND_ALLOW_SELF_UPDATE=true, and limited to admin clients over HTTP. Use 'check_server_update' first."),
+		mcp.WithBoolean("force",
+			mcp.Description("Install the latest release even if the running version is not older, e.g. over a "+
+				"development build. Default: false."),
+		),
+	)
+	s.AddTool(updateTool, r.handleUpdateServer)
+}
+
+// allowSelfUpdateFromEnv reports whether MCP_KIND_ALLOW_SELF_UPDATE is set to true.
+func allowSelfUpdateFromEnv(logger *slog.Logger) bool {
+	env := os.Getenv("MCP_KIND_ALLOW_SELF_UPDATE")
+	if env == "" {
+		return false
+	}
+	allow, err := strconv.ParseBool(env)
+	if err != nil {
+		logger.Warn("ignoring invalid MCP_KIND_ALLOW_SELF_UPDATE", "value", env)
+		return false
+	}
+	return allow
+}
+
+// updateClient returns a GitHub releases client, authenticated with GITHUB_TOKEN when
+// it is set.
+func updateClient() *selfupdate.Client {
+	return &selfupdate.Client{Token: os.Getenv("GITHUB_TOKEN")}
+}
+
+func (r *Registry) handleCheckServerUpdate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: check_server_update")
+	result, err := updateClient().Check(ctx, r.version)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to check for updates: %v", err)), nil
+	}
+	if result.UpdateAvailable && result.Asset != "" {
+		if r.allowSelfUpdate {
+			result.Notes = append(result.Notes, "Install it with 'update_server'.")
+		} else {
+			result.Notes = append(result.Notes, "Install it with your package manager, 'go install', or by "+
+				"setting MCP_KIND_ALLOW_SELF_UPDATE=true and calling 'update_server'.")
+		}
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleUpdateServer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: update_server")
+	if !r.allowSelfUpdate {
+		return mcp.NewToolResultError("self-update is disabled; set MCP_KIND_ALLOW_SELF_UPDATE=true to enable it"), nil
+	}
+	if id, ok := auth.FromContext(ctx); ok && !id.Admin {
+		return mcp.NewToolResultError(fmt.Sprintf("client %q may not update the server; only admins (MCP_KIND_ADMINS) may",
+			id.Name)), nil
+	}
+	force, _ := request.GetArguments()["force"].(bool)
+
+	result, err := updateClient().Update(ctx, r.version, selfupdate.UpdateOptions{Force: force})
+	if errors.Is(err, selfupdate.ErrUpToDate) {
+		return mcp.NewToolResultText(fmt.Sprintf("No update needed: %v.", err)), nil
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to update the server: %v", err)), nil
+	}
+	r.log(ctx).Info("server binary updated", "from", result.Previous, "to", result.Version, "path", result.Path)
+	return jsonResult(result)
+}
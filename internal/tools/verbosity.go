@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Values of the verbosity parameter.
+const (
+	verbositySummary = "summary"
+	verbosityFull    = "full"
+)
+
+// verbosityParam is the verbosity parameter of read tools that can answer with one
+// line per item instead of their JSON structure.
+func verbosityParam() mcp.ToolOption {
+	return mcp.WithString("verbosity",
+		mcp.Description("'summary' returns one compact line per item to save context; 'full' returns the "+
+			"detailed JSON structure. Default: full."),
+		mcp.Enum(verbositySummary, verbosityFull),
+	)
+}
+
+// summaryRequested reports whether the request asks for verbosity=summary.
+func summaryRequested(request mcp.CallToolRequest) bool {
+	return request.GetString("verbosity", verbosityFull) == verbositySummary
+}
+
+// linesResult returns lines as a plain text result.
+func linesResult(lines []string) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(strings.Join(lines, "\n")), nil
+}
+
+// runtimeLine renders the runtime as "docker 27.1.1 docker-desktop linux/arm64 socket=...".
+func runtimeLine(ri rtdetect.RuntimeInfo) string {
+	line := "runtime: " + string(ri.Runtime)
+	if ri.Version != "" {
+		line += " " + ri.Version
+	}
+	line += " backend=" + string(ri.Backend)
+	if ri.Arch != "" {
+		line += " arch=" + ri.Arch
+	}
+	if ri.SocketPath != "" {
+		line += " socket=" + ri.SocketPath
+	}
+	if !ri.Available {
+		line += " [unavailable]"
+	}
+	if ri.Error != "" {
+		line += " error: " + ri.Error
+	}
+	return line
+}
+
+// osLine renders the host OS as "os: darwin/arm64 (macOS)".
+func osLine(info rtdetect.OSInfo) string {
+	line := fmt.Sprintf("os: %s/%s", info.OS, info.Arch)
+	if info.Platform != "" {
+		line += " (" + info.Platform + ")"
+	}
+	return line
+}
+
+// networkLine renders network advice without its notes.
+func networkLine(advice kind.NetworkAdvice) string {
+	line := fmt.Sprintf("network: listen=%s nodeports=%s", advice.ListenAddress, advice.RecommendedPortRange)
+	if advice.ReachableAddress != "" {
+		line += " reachable=" + advice.ReachableAddress
+	}
+	if advice.RequiresExtraConfig {
+		line += " [extra config required]"
+	}
+	if advice.WindowsFirewallRequired {
+		line += " [windows firewall rules for LAN]"
+	}
+	return line
+}
+
+// portMappingLine renders a port mapping as "map 127.0.0.1:80 -> 80/TCP".
+func portMappingLine(pm kind.PortMapping) string {
+	listen := pm.ListenAddress
+	if listen == "" {
+		listen = "0.0.0.0"
+	}
+	protocol := pm.Protocol
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	return fmt.Sprintf("map %s:%d -> %d/%s", listen, pm.HostPort, pm.ContainerPort, protocol)
+}
@@ -0,0 +1,283 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/bundle"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/state"
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/workloads"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (r *Registry) registerWorkloadTools(s *server.MCPServer) {
+	exportTool := mcp.NewTool("export_workloads",
+		updateHints,
+		mcp.WithDescription(
+			"Export the namespaced resources of a Kind cluster to a gzipped tarball, optionally with the data "+
+				"of its local-path PersistentVolumes copied from the nodes. Controller-owned objects (pods of "+
+				"Deployments, ReplicaSets, ...) and cluster-generated ones are skipped and server fields stripped, "+
+				"so 'import_workloads' can restore them into another or a recreated cluster."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to export from"),
+		mcp.WithString("output_path",
+			mcp.Required(),
+			mcp.Description("Path of the tarball to write (e.g. '~/dev-workloads.tar.gz')"),
+		),
+		mcp.WithArray("namespaces",
+			mcp.WithStringItems(),
+			mcp.Description("Only export these namespaces. Default: all except "+
+				strings.Join(workloads.DefaultExcludedNamespaces, ", ")+"."),
+		),
+		mcp.WithArray("exclude_namespaces",
+			mcp.WithStringItems(),
+			mcp.Description("Namespaces to skip when 'namespaces' is not set. Replaces the default exclusions."),
+		),
+		mcp.WithBoolean("include_volume_data",
+			mcp.Description("Copy the contents of bound local-path volumes into the tarball. Default: false."),
+		),
+	)
+	s.AddTool(exportTool, r.handleExportWorkloads)
+
+	importTool := mcp.NewTool("import_workloads",
+		destructiveHints,
+		mcp.WithDescription(
+			"Apply the resources from an 'export_workloads' tarball to a Kind cluster. With restore_volume_data, "+
+				"waits for each exported claim to bind, copies its data into the new volume, and restarts the pods "+
+				"mounting it. Custom resources need their CRDs installed first."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to import into"),
+		mcp.WithString("archive_path",
+			mcp.Required(),
+			mcp.Description("Path of the tarball written by export_workloads"),
+		),
+		mcp.WithBoolean("restore_volume_data",
+			mcp.Description("Restore exported volume data into the new claims. Default: true."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for restored claims to bind. Default: 120."),
+		),
+	)
+	s.AddTool(importTool, r.handleImportWorkloads)
+
+	exportBundleTool := mcp.NewTool("export_cluster_bundle",
+		updateHints,
+		mcp.WithDescription(
+			"Back up the configuration of a Kind cluster to one shareable gzipped tarball: its Kind config, "+
+				"the add-ons installed with install_cert_manager, install_gateway_api and install_observability, "+
+				"the images loaded onto its nodes, its registry mirrors, its tags and defaults, and the resources "+
+				"of the selected namespaces (as export_workloads writes them). 'import_cluster_bundle' reproduces "+
+				"the environment on another machine. Mirror credentials and CA bundles are not included."),
+		clusterNameParam("cluster_name", "Name of the Kind cluster to back up"),
+		mcp.WithString("output_path",
+			mcp.Required(),
+			mcp.Description("Path of the bundle to write (e.g. '~/dev-bundle.tar.gz')"),
+		),
+		mcp.WithArray("namespaces",
+			mcp.WithStringItems(),
+			mcp.Description("Namespaces whose resources to include. Default: none."),
+		),
+		mcp.WithBoolean("include_volume_data",
+			mcp.Description("Copy the contents of the namespaces' bound local-path volumes into the bundle. Default: false."),
+		),
+	)
+	s.AddTool(exportBundleTool, r.handleExportClusterBundle)
+
+	importBundleTool := mcp.NewTool("import_cluster_bundle",
+		createHints,
+		mcp.WithDescription(
+			"Reproduce the environment of an 'export_cluster_bundle' tarball: create a cluster from the bundled "+
+				"Kind config, load the bundled images present in the local container runtime, install the "+
+				"add-ons (deleting the new cluster if any of that fails), then configure the registry mirrors "+
+				"and apply the bundled namespaces. Images missing locally are left for the nodes to pull."),
+		mcp.WithString("bundle_path",
+			mcp.Required(),
+			mcp.Description("Path of the tarball written by export_cluster_bundle"),
+		),
+		mcp.WithString("cluster_name",
+			mcp.Description("Name of the cluster to create. Default: the exported cluster's name."),
+		),
+		mcp.WithBoolean("load_images",
+			mcp.Description("Load the bundled images found in the local container runtime. Default: true."),
+		),
+		mcp.WithBoolean("restore_volume_data",
+			mcp.Description("Restore bundled volume data into the new claims. Default: true."),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("How long to wait for each add-on and for restored claims to bind. Default: 300."),
+		),
+	)
+	s.AddTool(importBundleTool, r.handleImportClusterBundle)
+}
+
+func (r *Registry) handleExportWorkloads(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: export_workloads")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	outputPath, err := request.RequireString("output_path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'output_path' is required"), nil
+	}
+
+	opts := workloads.ExportOptions{
+		Namespaces: request.GetStringSlice("namespaces", nil),
+	}
+	if exclude, err := request.RequireStringSlice("exclude_namespaces"); err == nil {
+		opts.ExcludeNamespaces = exclude
+	}
+	if val, ok := request.GetArguments()["include_volume_data"].(bool); ok {
+		opts.IncludeVolumeData = val
+	}
+
+	release, errResult := r.waitHeavy(ctx, r.progressReporter(ctx, request))
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr := r.kindManager(ctx)
+	result, err := workloads.ExportWorkloads(ctx, mgr, clusterName, outputPath, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to export workloads: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleImportWorkloads(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: import_workloads")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	archivePath, err := request.RequireString("archive_path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'archive_path' is required"), nil
+	}
+
+	opts := workloads.ImportOptions{RestoreVolumeData: true}
+	if val, ok := request.GetArguments()["restore_volume_data"].(bool); ok {
+		opts.RestoreVolumeData = val
+	}
+	if timeout, err := request.RequireFloat("timeout_seconds"); err == nil && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	release, errResult := r.waitHeavy(ctx, r.progressReporter(ctx, request))
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr := r.kindManager(ctx)
+	result, err := workloads.ImportWorkloads(ctx, mgr, clusterName, archivePath, opts)
+	if err != nil {
+		var steps []string
+		if result != nil {
+			steps = result.Steps
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to import workloads: %v\n\nCompleted steps:\n%s",
+			err, strings.Join(steps, "\n"))), nil
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleExportClusterBundle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: export_cluster_bundle")
+	clusterName, errResult := r.clusterParam(ctx, request, "cluster_name")
+	if errResult != nil {
+		return errResult, nil
+	}
+	outputPath, err := request.RequireString("output_path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'output_path' is required"), nil
+	}
+
+	opts := bundle.ExportOptions{Namespaces: request.GetStringSlice("namespaces", nil)}
+	if val, ok := request.GetArguments()["include_volume_data"].(bool); ok {
+		opts.IncludeVolumeData = val
+	}
+	rec, err := r.store.Get(clusterName)
+	if err != nil {
+		r.log(ctx).Warn("failed to read cluster state", "cluster", clusterName, "error", err)
+	}
+	if rec != nil {
+		opts.Config, opts.Tags = rec.Config, rec.Tags
+		opts.DefaultNamespace, opts.KubeContext = rec.Namespace, rec.Context
+	}
+
+	release, errResult := r.waitHeavy(ctx, r.progressReporter(ctx, request))
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	result, err := bundle.Export(ctx, r.kindManager(ctx), clusterName, outputPath, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to export cluster bundle: %v", err)), nil
+	}
+	return jsonResult(result)
+}
+
+func (r *Registry) handleImportClusterBundle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	r.log(ctx).Info("tool called: import_cluster_bundle")
+	bundlePath, err := request.RequireString("bundle_path")
+	if err != nil {
+		return mcp.NewToolResultError("parameter 'bundle_path' is required"), nil
+	}
+	b, configYAML, err := bundle.Read(bundlePath)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read cluster bundle: %v", err)), nil
+	}
+
+	opts := bundle.ImportOptions{Name: request.GetString("cluster_name", ""), RestoreVolumeData: true}
+	if err := kind.ValidateClusterName(cmp.Or(opts.Name, b.Cluster)); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if val, ok := request.GetArguments()["load_images"].(bool); ok {
+		opts.SkipImages = !val
+	}
+	if val, ok := request.GetArguments()["restore_volume_data"].(bool); ok {
+		opts.RestoreVolumeData = val
+	}
+	if timeout, err := request.RequireFloat("timeout_seconds"); err == nil && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	mgr := r.kindManager(ctx)
+	admitted, errResult := r.admitCluster(ctx, mgr, configYAML)
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer admitted()
+	progress := r.progressReporter(ctx, request)
+	release, errResult := r.waitHeavy(ctx, progress)
+	if errResult != nil {
+		return errResult, nil
+	}
+	defer release()
+	mgr.SetProgress(progress)
+	result, err := bundle.Import(ctx, mgr, bundlePath, opts)
+	if result == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to import cluster bundle: %v", err)), nil
+	}
+	if result.Bootstrap != nil && result.Bootstrap.Created {
+		rec := state.ClusterRecord{Name: result.Cluster, Tags: b.Tags, CreatedAt: time.Now().UTC(),
+			Config: result.Bootstrap.ConfigYAML, Mirrors: result.Mirrors, Namespace: b.DefaultNamespace,
+			Owner: callerName(ctx)}
+		// A custom context name was chosen for the exported cluster; keep it only for a same-named copy.
+		if result.Cluster == b.Cluster {
+			rec.Context = b.KubeContext
+		}
+		if err := r.store.Put(rec); err != nil {
+			r.log(ctx).Warn("failed to record cluster state", "cluster", result.Cluster, "error", err)
+		}
+	}
+	if err != nil {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return mcp.NewToolResultError(fmt.Sprintf("failed to import cluster bundle: %v\n\n%s", err, data)), nil
+	}
+	return jsonResult(result)
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Runner wraps a CommandRunner with one span per command. Only the program and its
+// subcommand are recorded, never the full argument list, which can carry credentials
+// (registry mirror auth, inline manifests).
+type Runner struct {
+	Next rtdetect.CommandRunner
+}
+
+var (
+	_ rtdetect.StreamingRunner = (*Runner)(nil)
+	_ rtdetect.InputRunner     = (*Runner)(nil)
+)
+
+// Run implements rtdetect.CommandRunner.
+func (r *Runner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, span := r.start(ctx, name, args)
+	out, err := r.Next.Run(ctx, name, args...)
+	end(span, err)
+	return out, err
+}
+
+// RunSeparate implements rtdetect.CommandRunner.
+func (r *Runner) RunSeparate(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	ctx, span := r.start(ctx, name, args)
+	stdout, stderr, err := r.Next.RunSeparate(ctx, name, args...)
+	end(span, err)
+	return stdout, stderr, err
+}
+
+// RunStream implements rtdetect.StreamingRunner, streaming through Next when it can.
+func (r *Runner) RunStream(ctx context.Context, opts rtdetect.StreamOptions, name string, args ...string) (*rtdetect.StreamResult, error) {
+	ctx, span := r.start(ctx, name, args)
+	res, err := rtdetect.RunStream(ctx, r.Next, opts, name, args...)
+	end(span, err)
+	return res, err
+}
+
+// RunInput implements rtdetect.InputRunner. The input is never recorded.
+func (r *Runner) RunInput(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	ctx, span := r.start(ctx, name, args)
+	stdout, stderr, err := rtdetect.RunInput(ctx, r.Next, input, name, args...)
+	end(span, err)
+	return stdout, stderr, err
+}
+
+// LookPath implements rtdetect.CommandRunner.
+func (r *Runner) LookPath(name string) (string, error) {
+	return r.Next.LookPath(name)
+}
+
+func (r *Runner) start(ctx context.Context, name string, args []string) (context.Context, trace.Span) {
+	sub := subcommand(args)
+	spanName := "exec " + name
+	if sub != "" {
+		spanName += " " + sub
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("process.executable.name", name),
+		attribute.String("mcp_kind.subcommand", sub),
+	}
+	// Only the names of added environment variables; values can carry credentials.
+	if env := rtdetect.Env(ctx); len(env) > 0 {
+		attrs = append(attrs, attribute.StringSlice("mcp_kind.env", rtdetect.EnvNames(env)))
+	}
+	return Tracer().Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+func end(span trace.Span, err error) {
+	span.SetAttributes(attribute.Int("process.exit.code", rtdetect.ExitCode(err)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// subcommand returns the first argument that is not a flag or a flag's value, e.g.
+// "create" for "kind create cluster" and "get" for "kubectl --kubeconfig x get pods".
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") {
+			return a
+		}
+		// "--flag value" form for the flags placed before subcommands in this server
+		if !strings.Contains(a, "=") && (a == "--kubeconfig" || a == "--namespace" || a == "--context") {
+			i++
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package workloads
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarball writes a gzipped tarball holding entries to a temporary file. Regular
+// entries get their name as content.
+func tarball(t *testing.T, entries ...tar.Header) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "archive.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range entries {
+		if hdr.Typeflag == tar.TypeReg {
+			hdr.Size = int64(len(hdr.Name))
+		}
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(hdr.Name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "volumes", "shop", "data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "volumes", "shop", "data", "a.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "out.tar.gz")
+	if err := writeArchive(src, path); err != nil {
+		t.Fatalf("writeArchive: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := extractArchive(path, dst); err != nil {
+		t.Fatalf("extractArchive: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "volumes", "shop", "data", "a.txt"))
+	if err != nil || string(data) != "hello" {
+		t.Errorf("extracted file = %q, %v", data, err)
+	}
+}
+
+func TestWriteArchive_RejectsLinks(t *testing.T) {
+	src := t.TempDir()
+	if err := os.Symlink("/etc/passwd", filepath.Join(src, "passwd")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := writeArchive(src, filepath.Join(t.TempDir(), "out.tar.gz")); err == nil {
+		t.Error("expected a symlink to be rejected")
+	}
+}
+
+func TestExtractArchive_Rejects(t *testing.T) {
+	outside := t.TempDir()
+	tests := map[string][]tar.Header{
+		"parent":   {{Name: "../escape", Mode: 0o644, Typeflag: tar.TypeReg}},
+		"nested":   {{Name: "volumes/../../escape", Mode: 0o644, Typeflag: tar.TypeReg}},
+		"absolute": {{Name: filepath.ToSlash(filepath.Join(outside, "escape")), Mode: 0o644, Typeflag: tar.TypeReg}},
+		"symlink then file": {
+			{Name: "link", Linkname: outside, Mode: 0o777, Typeflag: tar.TypeSymlink},
+			{Name: "link/escape", Mode: 0o644, Typeflag: tar.TypeReg},
+		},
+		"symlink then dir": {
+			{Name: "link", Linkname: outside, Mode: 0o777, Typeflag: tar.TypeSymlink},
+			{Name: "link/escape/", Mode: 0o755, Typeflag: tar.TypeDir},
+		},
+		"hardlink": {{Name: "passwd", Linkname: "/etc/passwd", Mode: 0o644, Typeflag: tar.TypeLink}},
+	}
+	for name, entries := range tests {
+		t.Run(name, func(t *testing.T) {
+			dst := t.TempDir()
+			if err := extractArchive(tarball(t, entries...), dst); err == nil {
+				t.Error("expected the archive to be rejected")
+			}
+			if _, err := os.Lstat(filepath.Join(outside, "escape")); err == nil {
+				t.Fatal("an entry was written outside the extraction directory")
+			}
+			if _, err := os.Lstat(filepath.Join(dst, "link")); err == nil {
+				t.Error("a link was created in the extraction directory")
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+// Package workloads exports the namespaced resources of a Kind cluster, together with the
+// data of its local-path volumes, to a tarball and imports them into another cluster.
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// archiveVersion is the layout version recorded in manifest.json.
+const archiveVersion = 1
+
+// Archive entries.
+const (
+	manifestFile  = "manifest.json"
+	resourcesFile = "resources.json"
+	volumesDir    = "volumes"
+)
+
+// DefaultExcludedNamespaces are skipped unless namespaces are selected explicitly.
+var DefaultExcludedNamespaces = []string{"kube-system", "kube-public", "kube-node-lease", "local-path-storage"}
+
+// skippedResources are recreated by the cluster itself and would conflict or go stale
+// if exported.
+var skippedResources = map[string]bool{
+	"events":                              true,
+	"events.events.k8s.io":                true,
+	"endpoints":                           true,
+	"endpointslices.discovery.k8s.io":     true,
+	"controllerrevisions.apps":            true,
+	"leases.coordination.k8s.io":          true,
+	"pods.metrics.k8s.io":                 true,
+	"csistoragecapacities.storage.k8s.io": true,
+}
+
+// ExportOptions controls which resources ExportWorkloads writes.
+type ExportOptions struct {
+	// Namespaces limits the export to these namespaces. Default: every namespace
+	// except ExcludeNamespaces.
+	Namespaces []string
+	// ExcludeNamespaces is ignored when Namespaces is set. Default: DefaultExcludedNamespaces.
+	ExcludeNamespaces []string
+	// IncludeVolumeData copies the contents of bound local-path volumes from the nodes.
+	IncludeVolumeData bool
+}
+
+// VolumeRecord is the data of one PersistentVolumeClaim stored in the archive.
+type VolumeRecord struct {
+	Namespace string `json:"namespace"`
+	Claim     string `json:"claim"`
+	// Dir is the archive directory holding the volume's files.
+	Dir string `json:"dir"`
+}
+
+// Manifest describes an export archive.
+type Manifest struct {
+	Version    int            `json:"version"`
+	Cluster    string         `json:"cluster"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Namespaces []string       `json:"namespaces"`
+	Resources  int            `json:"resources"`
+	Volumes    []VolumeRecord `json:"volumes,omitempty"`
+}
+
+// ExportResult reports what ExportWorkloads wrote.
+type ExportResult struct {
+	Path     string   `json:"path"`
+	Manifest Manifest `json:"manifest"`
+	// Warnings lists resources or volumes that could not be exported as-is.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ExportWorkloads dumps the namespaced resources of a cluster (minus objects owned by a
+// controller and cluster-generated ones) and their Namespaces to a gzipped tarball at
+// path. Server-populated fields are stripped so the resources apply cleanly elsewhere.
+func ExportWorkloads(ctx context.Context, mgr *kind.Manager, clusterName, path string, opts ExportOptions) (*ExportResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("output path is required")
+	}
+	path = kind.ExpandHome(path)
+	if opts.ExcludeNamespaces == nil {
+		opts.ExcludeNamespaces = DefaultExcludedNamespaces
+	}
+
+	types, err := kubectlLines(ctx, mgr, clusterName,
+		"api-resources", "--namespaced=true", "--verbs=list,get,create", "-o", "name")
+	if err != nil {
+		return nil, fmt.Errorf("listing resource types: %w", err)
+	}
+	types = slices.DeleteFunc(types, func(t string) bool { return skippedResources[t] })
+
+	namespaces, err := kubectlList(ctx, mgr, clusterName, "get", "namespaces", "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("listing namespaces: %w", err)
+	}
+	items, err := kubectlList(ctx, mgr, clusterName, "get", strings.Join(types, ","), "--all-namespaces", "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("listing resources: %w", err)
+	}
+
+	result := &ExportResult{Path: path, Manifest: Manifest{
+		Version:    archiveVersion,
+		Cluster:    clusterName,
+		ExportedAt: time.Now().UTC(),
+	}}
+
+	selected := make(map[string]bool)
+	var objects []map[string]any
+	for _, ns := range namespaces {
+		name := metaString(ns, "name")
+		if !namespaceSelected(name, opts) {
+			continue
+		}
+		selected[name] = true
+		result.Manifest.Namespaces = append(result.Manifest.Namespaces, name)
+		objects = append(objects, ns)
+	}
+	for _, name := range opts.Namespaces {
+		if !selected[name] {
+			return nil, fmt.Errorf("namespace %q not found in cluster %q", name, clusterName)
+		}
+	}
+
+	var claims []map[string]any
+	for _, obj := range items {
+		if !selected[metaString(obj, "namespace")] || generated(obj) {
+			continue
+		}
+		if obj["kind"] == "PersistentVolumeClaim" {
+			claims = append(claims, obj)
+		}
+		objects = append(objects, obj)
+	}
+
+	staging, err := os.MkdirTemp("", "kind-workloads-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// Volume data is located through the bound PV, so copy it before cleanObject
+	// drops spec.volumeName.
+	if opts.IncludeVolumeData {
+		for _, claim := range claims {
+			rec, skipped, err := exportVolume(ctx, mgr, clusterName, claim, staging)
+			if err != nil {
+				result.Warnings = append(result.Warnings, err.Error())
+				continue
+			}
+			if len(skipped) > 0 {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("volume %s/%s: skipped links and special files %s",
+					rec.Namespace, rec.Claim, strings.Join(skipped, ", ")))
+			}
+			result.Manifest.Volumes = append(result.Manifest.Volumes, *rec)
+		}
+	}
+
+	for _, obj := range objects {
+		cleanObject(obj)
+	}
+	result.Manifest.Resources = len(objects) - len(result.Manifest.Namespaces)
+
+	if err := writeJSON(filepath.Join(staging, resourcesFile), map[string]any{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      objects,
+	}); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(filepath.Join(staging, manifestFile), result.Manifest); err != nil {
+		return nil, err
+	}
+	if err := writeArchive(staging, path); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// exportVolume copies the data of a bound claim's local volume into the staging directory.
+// Links and special files are removed from the copy, since archives only hold regular
+// files and directories; their paths are returned.
+func exportVolume(ctx context.Context, mgr *kind.Manager, clusterName string, claim map[string]any, staging string) (*VolumeRecord, []string, error) {
+	ns, name := metaString(claim, "namespace"), metaString(claim, "name")
+	spec, _ := claim["spec"].(map[string]any)
+	pvName, _ := spec["volumeName"].(string)
+	if pvName == "" {
+		return nil, nil, fmt.Errorf("volume %s/%s: claim is not bound, data not exported", ns, name)
+	}
+	vol, err := locateVolume(ctx, mgr, clusterName, pvName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("volume %s/%s: %w, data not exported", ns, name, err)
+	}
+
+	rec := &VolumeRecord{Namespace: ns, Claim: name, Dir: filepath.ToSlash(filepath.Join(volumesDir, ns, name))}
+	dst := filepath.Join(staging, filepath.FromSlash(rec.Dir))
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("volume %s/%s: %w", ns, name, err)
+	}
+	if err := mgr.CopyFromNode(ctx, vol.node, vol.path+"/.", dst); err != nil {
+		return nil, nil, fmt.Errorf("volume %s/%s: %w", ns, name, err)
+	}
+	skipped, err := removeSpecialFiles(dst)
+	if err != nil {
+		return nil, nil, fmt.Errorf("volume %s/%s: %w", ns, name, err)
+	}
+	return rec, skipped, nil
+}
+
+// removeSpecialFiles deletes everything under dir that is neither a regular file nor a
+// directory and returns the removed paths, relative to dir.
+func removeSpecialFiles(dir string) ([]string, error) {
+	var removed []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		removed = append(removed, filepath.ToSlash(rel))
+		return os.Remove(p)
+	})
+	return removed, err
+}
+
+// namespaceSelected applies the namespace filters of opts.
+func namespaceSelected(name string, opts ExportOptions) bool {
+	if len(opts.Namespaces) > 0 {
+		return slices.Contains(opts.Namespaces, name)
+	}
+	return !slices.Contains(opts.ExcludeNamespaces, name)
+}
+
+// generated reports whether an object is created and owned by the cluster or a
+// controller, so exporting it would duplicate or conflict with the recreated one.
+func generated(obj map[string]any) bool {
+	meta, _ := obj["metadata"].(map[string]any)
+	refs, _ := meta["ownerReferences"].([]any)
+	for _, ref := range refs {
+		if r, ok := ref.(map[string]any); ok && r["controller"] == true {
+			return true
+		}
+	}
+
+	name := metaString(obj, "name")
+	switch obj["kind"] {
+	case "Secret":
+		return obj["type"] == "kubernetes.io/service-account-token"
+	case "ConfigMap":
+		return name == "kube-root-ca.crt"
+	case "ServiceAccount":
+		return name == "default"
+	}
+	return false
+}
+
+// Annotations that carry server or controller state rather than user intent.
+var (
+	droppedAnnotations        = []string{"kubectl.kubernetes.io/last-applied-configuration", "deployment.kubernetes.io/revision"}
+	droppedAnnotationPrefixes = []string{"pv.kubernetes.io/", "volume.kubernetes.io/", "volume.beta.kubernetes.io/"}
+)
+
+// Job labels and selectors generated from the original Job's UID.
+var jobGeneratedLabels = []string{"controller-uid", "batch.kubernetes.io/controller-uid", "job-name", "batch.kubernetes.io/job-name"}
+
+// cleanObject strips server-populated fields so the object can be applied to another cluster.
+func cleanObject(obj map[string]any) {
+	delete(obj, "status")
+	if meta, ok := obj["metadata"].(map[string]any); ok {
+		for _, f := range []string{"uid", "resourceVersion", "creationTimestamp", "generation", "managedFields", "selfLink", "ownerReferences"} {
+			delete(meta, f)
+		}
+		if annotations, ok := meta["annotations"].(map[string]any); ok {
+			for key := range annotations {
+				if slices.Contains(droppedAnnotations, key) || hasAnyPrefix(key, droppedAnnotationPrefixes) {
+					delete(annotations, key)
+				}
+			}
+			if len(annotations) == 0 {
+				delete(meta, "annotations")
+			}
+		}
+	}
+
+	spec, _ := obj["spec"].(map[string]any)
+	if spec == nil {
+		return
+	}
+	switch obj["kind"] {
+	case "Service":
+		if spec["clusterIP"] != "None" {
+			delete(spec, "clusterIP")
+			delete(spec, "clusterIPs")
+		}
+	case "PersistentVolumeClaim":
+		delete(spec, "volumeName")
+	case "Pod":
+		delete(spec, "nodeName")
+	case "Job":
+		delete(spec, "selector")
+		if tmpl, ok := spec["template"].(map[string]any); ok {
+			if meta, ok := tmpl["metadata"].(map[string]any); ok {
+				if labels, ok := meta["labels"].(map[string]any); ok {
+					for _, l := range jobGeneratedLabels {
+						delete(labels, l)
+					}
+				}
+			}
+		}
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func metaString(obj map[string]any, field string) string {
+	meta, _ := obj["metadata"].(map[string]any)
+	s, _ := meta[field].(string)
+	return s
+}
+
+// kubectlList runs a kubectl get that prints a JSON List and returns its items.
+func kubectlList(ctx context.Context, mgr *kind.Manager, clusterName string, args ...string) ([]map[string]any, error) {
+	out, err := kubectlStdout(ctx, mgr, clusterName, args...)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return nil, fmt.Errorf("parsing kubectl output: %w", err)
+	}
+	return list.Items, nil
+}
+
+// kubectlLines runs kubectl and returns the non-empty lines of its stdout.
+func kubectlLines(ctx context.Context, mgr *kind.Manager, clusterName string, args ...string) ([]string, error) {
+	out, err := kubectlStdout(ctx, mgr, clusterName, args...)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// kubectlStdout runs kubectl and returns stdout only, so warnings on stderr can't
+// corrupt the JSON being parsed.
+func kubectlStdout(ctx context.Context, mgr *kind.Manager, clusterName string, args ...string) (string, error) {
+	res, err := mgr.RunKubectl(ctx, clusterName, "", args)
+	if err != nil {
+		return "", err
+	}
+	if res.ExitCode != 0 {
+		return "", fmt.Errorf("kubectl %s exited %d: %s", args[0], res.ExitCode, strings.TrimSpace(res.Stderr))
+	}
+	return res.Stdout, nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
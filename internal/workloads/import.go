@@ -0,0 +1,159 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// ImportOptions controls how ImportWorkloads restores an archive.
+type ImportOptions struct {
+	// RestoreVolumeData copies exported volume data into the new claims' volumes.
+	RestoreVolumeData bool
+	// Timeout bounds the wait for restored claims to bind. Default: 2 minutes.
+	Timeout time.Duration
+}
+
+// ImportResult reports what ImportWorkloads restored.
+type ImportResult struct {
+	Manifest Manifest `json:"manifest"`
+	Steps    []string `json:"steps"`
+	// Warnings lists volumes whose data could not be restored.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ImportWorkloads applies the resources of an archive written by ExportWorkloads to a
+// cluster. With RestoreVolumeData, it then waits for each exported claim to bind, copies
+// its data onto the node backing the new volume, and deletes the pods mounting the claim
+// so their controllers restart them on the restored data.
+func ImportWorkloads(ctx context.Context, mgr *kind.Manager, clusterName, path string, opts ImportOptions) (*ImportResult, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("archive path is required")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+
+	dir, err := os.MkdirTemp("", "kind-workloads-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := extractArchive(kind.ExpandHome(path), dir); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("archive has no %s: %w", manifestFile, err)
+	}
+	result := &ImportResult{}
+	if err := json.Unmarshal(data, &result.Manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", manifestFile, err)
+	}
+	if result.Manifest.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d (expected %d)", result.Manifest.Version, archiveVersion)
+	}
+
+	if _, err := mgr.Kubectl(ctx, clusterName, "apply", "-f", filepath.Join(dir, resourcesFile)); err != nil {
+		return result, fmt.Errorf("applying resources: %w", err)
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("OK applied %d resource(s) in namespaces %s",
+		result.Manifest.Resources, strings.Join(result.Manifest.Namespaces, ", ")))
+
+	if !opts.RestoreVolumeData {
+		return result, nil
+	}
+	deadline := time.Now().Add(opts.Timeout)
+	for _, rec := range result.Manifest.Volumes {
+		if err := importVolume(ctx, mgr, clusterName, dir, rec, time.Until(deadline)); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("volume %s/%s: %v", rec.Namespace, rec.Claim, err))
+			continue
+		}
+		result.Steps = append(result.Steps, fmt.Sprintf("OK restored data of %s/%s", rec.Namespace, rec.Claim))
+	}
+	return result, nil
+}
+
+// importVolume copies one exported volume into the volume now bound to its claim and
+// restarts the pods that mount it.
+func importVolume(ctx context.Context, mgr *kind.Manager, clusterName, dir string, rec VolumeRecord, timeout time.Duration) error {
+	if strings.Contains(rec.Dir, "..") {
+		return fmt.Errorf("invalid archive directory %q", rec.Dir)
+	}
+	// local-path claims bind once a pod using them is scheduled, which the
+	// applied workloads take care of.
+	if _, err := mgr.Kubectl(ctx, clusterName, "wait", "--for=jsonpath={.status.phase}=Bound",
+		"pvc/"+rec.Claim, "-n", rec.Namespace, fmt.Sprintf("--timeout=%ds", max(int(timeout.Seconds()), 1))); err != nil {
+		return fmt.Errorf("claim did not bind (is a pod using it?): %w", err)
+	}
+	pvName, err := kubectlStdout(ctx, mgr, clusterName, "get", "pvc", rec.Claim, "-n", rec.Namespace,
+		"-o", "jsonpath={.spec.volumeName}")
+	if err != nil {
+		return err
+	}
+	vol, err := locateVolume(ctx, mgr, clusterName, strings.TrimSpace(pvName))
+	if err != nil {
+		return err
+	}
+	src := filepath.Join(dir, filepath.FromSlash(rec.Dir))
+	if err := mgr.CopyToNode(ctx, vol.node, src+"/.", vol.path); err != nil {
+		return err
+	}
+
+	pods, err := podsUsingClaim(ctx, mgr, clusterName, rec.Namespace, rec.Claim)
+	if err != nil {
+		return err
+	}
+	if len(pods) > 0 {
+		args := append([]string{"delete", "pod", "-n", rec.Namespace, "--wait=false"}, pods...)
+		if _, err := mgr.Kubectl(ctx, clusterName, args...); err != nil {
+			return fmt.Errorf("data restored but restarting pods failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// podsUsingClaim returns the names of the pods in a namespace that mount a claim.
+func podsUsingClaim(ctx context.Context, mgr *kind.Manager, clusterName, namespace, claim string) ([]string, error) {
+	out, err := kubectlStdout(ctx, mgr, clusterName, "get", "pods", "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				Volumes []struct {
+					PersistentVolumeClaim *struct {
+						ClaimName string `json:"claimName"`
+					} `json:"persistentVolumeClaim"`
+				} `json:"volumes"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return nil, fmt.Errorf("parsing pods: %w", err)
+	}
+	var pods []string
+	for _, pod := range list.Items {
+		for _, v := range pod.Spec.Volumes {
+			if v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ClaimName == claim {
+				pods = append(pods, pod.Metadata.Name)
+				break
+			}
+		}
+	}
+	return pods, nil
+}
@@ -0,0 +1,71 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+)
+
+// volumeLocation is where a local PersistentVolume keeps its data.
+type volumeLocation struct {
+	node string
+	path string
+}
+
+// locateVolume finds the node and directory of a hostPath or local PersistentVolume, as
+// provisioned by Kind's default local-path storage class. Other volume types live
+// outside the nodes and are not supported.
+func locateVolume(ctx context.Context, mgr *kind.Manager, clusterName, pvName string) (*volumeLocation, error) {
+	out, err := kubectlStdout(ctx, mgr, clusterName, "get", "pv", pvName, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var pv struct {
+		Spec struct {
+			HostPath *struct {
+				Path string `json:"path"`
+			} `json:"hostPath"`
+			Local *struct {
+				Path string `json:"path"`
+			} `json:"local"`
+			NodeAffinity *struct {
+				Required struct {
+					NodeSelectorTerms []struct {
+						MatchExpressions []struct {
+							Key    string   `json:"key"`
+							Values []string `json:"values"`
+						} `json:"matchExpressions"`
+					} `json:"nodeSelectorTerms"`
+				} `json:"required"`
+			} `json:"nodeAffinity"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(out), &pv); err != nil {
+		return nil, fmt.Errorf("parsing PersistentVolume %s: %w", pvName, err)
+	}
+
+	loc := &volumeLocation{}
+	switch {
+	case pv.Spec.HostPath != nil:
+		loc.path = pv.Spec.HostPath.Path
+	case pv.Spec.Local != nil:
+		loc.path = pv.Spec.Local.Path
+	default:
+		return nil, fmt.Errorf("PersistentVolume %s is not a hostPath or local volume", pvName)
+	}
+	if pv.Spec.NodeAffinity != nil {
+		for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+			for _, expr := range term.MatchExpressions {
+				if expr.Key == "kubernetes.io/hostname" && len(expr.Values) > 0 {
+					loc.node = expr.Values[0]
+				}
+			}
+		}
+	}
+	if loc.node == "" {
+		return nil, fmt.Errorf("PersistentVolume %s has no node affinity to locate its node", pvName)
+	}
+	return loc, nil
+}
@@ -0,0 +1,200 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubevoidcraft/mcp-kind-manager/internal/kind"
+	rtdetect "github.com/kubevoidcraft/mcp-kind-manager/internal/runtime"
+)
+
+// fakeCluster answers the kubectl and runtime commands used by export and import.
+// Commands are matched by the kubectl arguments after the kubeconfig flag.
+type fakeCluster struct {
+	calls   []string
+	applied string
+	copied  map[string]string // node:path -> content of data.txt copied to it
+}
+
+func (f *fakeCluster) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, _, err := f.RunSeparate(ctx, name, args...)
+	return out, err
+}
+
+func (f *fakeCluster) RunSeparate(_ context.Context, name string, args ...string) ([]byte, []byte, error) {
+	switch name {
+	case "kind":
+		return []byte("apiVersion: v1\n"), nil, nil
+	case "docker":
+		return f.runtime(args)
+	}
+	args = args[2:] // --kubeconfig <path>
+	cmd := strings.Join(args, " ")
+	f.calls = append(f.calls, cmd)
+
+	switch {
+	case strings.HasPrefix(cmd, "api-resources"):
+		return []byte("pods\nconfigmaps\nevents\npersistentvolumeclaims\nservices\nreplicasets.apps\n"), nil, nil
+	case cmd == "get namespaces -o json":
+		return []byte(`{"items":[
+			{"kind":"Namespace","metadata":{"name":"default","uid":"1"}},
+			{"kind":"Namespace","metadata":{"name":"kube-system"}},
+			{"kind":"Namespace","metadata":{"name":"shop","resourceVersion":"7"}}]}`), nil, nil
+	case strings.HasPrefix(cmd, "get pods,configmaps,persistentvolumeclaims,services,replicasets.apps"):
+		return []byte(`{"items":[
+			{"kind":"ConfigMap","metadata":{"name":"kube-root-ca.crt","namespace":"shop"}},
+			{"kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop","uid":"a","managedFields":[]}},
+			{"kind":"ConfigMap","metadata":{"name":"coredns","namespace":"kube-system"}},
+			{"kind":"ReplicaSet","metadata":{"name":"web-1","namespace":"shop","ownerReferences":[{"kind":"Deployment","controller":true}]}},
+			{"kind":"Service","metadata":{"name":"web","namespace":"shop"},"spec":{"clusterIP":"10.96.0.12","clusterIPs":["10.96.0.12"]}},
+			{"kind":"PersistentVolumeClaim","metadata":{"name":"data","namespace":"shop",
+				"annotations":{"pv.kubernetes.io/bind-completed":"yes","team":"shop"}},
+				"spec":{"volumeName":"pvc-old"},"status":{"phase":"Bound"}}]}`), nil, nil
+	case cmd == "get pv pvc-old -o json":
+		return pvJSON("/var/local-path-provisioner/pvc-old", "src-worker"), nil, nil
+	case cmd == "get pv pvc-new -o json":
+		return pvJSON("/var/local-path-provisioner/pvc-new", "dst-worker2"), nil, nil
+	case strings.HasPrefix(cmd, "apply -f "):
+		data, err := os.ReadFile(args[2])
+		if err != nil {
+			return nil, nil, err
+		}
+		f.applied = string(data)
+		return []byte("applied\n"), nil, nil
+	case strings.HasPrefix(cmd, "wait "):
+		return []byte("condition met\n"), nil, nil
+	case strings.HasPrefix(cmd, "get pvc data -n shop -o jsonpath"):
+		return []byte("pvc-new"), nil, nil
+	case cmd == "get pods -n shop -o json":
+		return []byte(`{"items":[
+			{"metadata":{"name":"db-0"},"spec":{"volumes":[{"persistentVolumeClaim":{"claimName":"data"}}]}},
+			{"metadata":{"name":"web-abc"},"spec":{"volumes":[{"configMap":{}}]}}]}`), nil, nil
+	case strings.HasPrefix(cmd, "delete pod"):
+		return []byte("deleted\n"), nil, nil
+	}
+	return nil, nil, fmt.Errorf("unexpected kubectl %s", cmd)
+}
+
+// runtime fakes "docker cp" in both directions with a single data.txt file.
+func (f *fakeCluster) runtime(args []string) ([]byte, []byte, error) {
+	if len(args) != 3 || args[0] != "cp" {
+		return nil, nil, fmt.Errorf("unexpected docker %v", args)
+	}
+	src, dst := args[1], args[2]
+	if strings.Contains(src, ":") {
+		return nil, nil, os.WriteFile(filepath.Join(dst, "data.txt"), []byte("rows"), 0o644)
+	}
+	data, err := os.ReadFile(filepath.Join(strings.TrimSuffix(src, "/."), "data.txt"))
+	if err != nil {
+		return nil, nil, err
+	}
+	f.copied[dst] = string(data)
+	return nil, nil, nil
+}
+
+func (f *fakeCluster) LookPath(name string) (string, error) {
+	return "/usr/bin/" + name, nil
+}
+
+func pvJSON(path, node string) []byte {
+	return []byte(fmt.Sprintf(`{"spec":{"hostPath":{"path":%q},"nodeAffinity":{"required":{"nodeSelectorTerms":[
+		{"matchExpressions":[{"key":"kubernetes.io/hostname","operator":"In","values":[%q]}]}]}}}}`, path, node))
+}
+
+func newManager(f *fakeCluster) *kind.Manager {
+	return kind.NewManager(f, rtdetect.RuntimeInfo{Runtime: rtdetect.RuntimeDocker}, nil)
+}
+
+func TestExportImportWorkloads(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "shop.tar.gz")
+	src := &fakeCluster{}
+
+	exported, err := ExportWorkloads(context.Background(), newManager(src), "src", archive,
+		ExportOptions{IncludeVolumeData: true})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	m := exported.Manifest
+	if strings.Join(m.Namespaces, ",") != "default,shop" {
+		t.Errorf("namespaces = %v", m.Namespaces)
+	}
+	// settings, web, data; not kube-root-ca.crt, the owned ReplicaSet or kube-system
+	if m.Resources != 3 {
+		t.Errorf("resources = %d, want 3", m.Resources)
+	}
+	if len(m.Volumes) != 1 || m.Volumes[0].Dir != "volumes/shop/data" {
+		t.Errorf("volumes = %+v", m.Volumes)
+	}
+	if len(exported.Warnings) != 0 {
+		t.Errorf("warnings = %v", exported.Warnings)
+	}
+
+	dst := &fakeCluster{copied: map[string]string{}}
+	imported, err := ImportWorkloads(context.Background(), newManager(dst), "dst", archive,
+		ImportOptions{RestoreVolumeData: true})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(imported.Warnings) != 0 {
+		t.Errorf("warnings = %v", imported.Warnings)
+	}
+	for _, unwanted := range []string{"kube-root-ca.crt", "coredns", "web-1", "10.96.0.12", "pvc-old", "bind-completed", `"uid"`, "managedFields", "status"} {
+		if strings.Contains(dst.applied, unwanted) {
+			t.Errorf("applied resources contain %q:\n%s", unwanted, dst.applied)
+		}
+	}
+	if !strings.Contains(dst.applied, `"team": "shop"`) {
+		t.Error("user annotations should be kept")
+	}
+	if got := dst.copied["dst-worker2:/var/local-path-provisioner/pvc-new"]; got != "rows" {
+		t.Errorf("restored volume data = %q, copied = %v", got, dst.copied)
+	}
+
+	var deleted string
+	for _, c := range dst.calls {
+		if strings.HasPrefix(c, "delete pod") {
+			deleted = c
+		}
+	}
+	if !strings.HasSuffix(deleted, " db-0") {
+		t.Errorf("expected only the pod using the claim to be restarted, got %q", deleted)
+	}
+}
+
+func TestExportWorkloads_UnknownNamespace(t *testing.T) {
+	_, err := ExportWorkloads(context.Background(), newManager(&fakeCluster{}), "src",
+		filepath.Join(t.TempDir(), "out.tar.gz"), ExportOptions{Namespaces: []string{"missing"}})
+	if err == nil || !strings.Contains(err.Error(), `namespace "missing" not found`) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestCleanObject_Job(t *testing.T) {
+	job := map[string]any{
+		"kind":     "Job",
+		"metadata": map[string]any{"name": "migrate", "annotations": map[string]any{"deployment.kubernetes.io/revision": "2"}},
+		"spec": map[string]any{
+			"selector": map[string]any{"matchLabels": map[string]any{"controller-uid": "x"}},
+			"template": map[string]any{"metadata": map[string]any{"labels": map[string]any{
+				"controller-uid": "x", "job-name": "migrate", "app": "migrate",
+			}}},
+		},
+	}
+	cleanObject(job)
+
+	spec := job["spec"].(map[string]any)
+	if _, ok := spec["selector"]; ok {
+		t.Error("generated Job selector should be removed")
+	}
+	labels := spec["template"].(map[string]any)["metadata"].(map[string]any)["labels"].(map[string]any)
+	if len(labels) != 1 || labels["app"] != "migrate" {
+		t.Errorf("template labels = %v", labels)
+	}
+	if _, ok := job["metadata"].(map[string]any)["annotations"]; ok {
+		t.Error("empty annotations should be removed")
+	}
+}